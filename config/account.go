@@ -0,0 +1,27 @@
+package config
+
+// 账号标识符格式策略
+const (
+	AccountFormatUsername = "username" // 仅允许用户名：字母、数字、下划线，1-20 位 (默认)
+	AccountFormatEmail    = "email"    // 仅允许邮箱
+	AccountFormatEither   = "either"   // 用户名或邮箱均可
+)
+
+// AccountConfig 定义账号密码身份的相关配置
+type AccountConfig struct {
+	IdentifierFormat string `mapstructure:"identifier_format" json:"identifier_format" yaml:"identifier_format"` // 账号标识符格式：username / email / either，为空时默认 username
+}
+
+// IdentifierFormatOrDefault 返回应用默认值后的账号标识符格式
+func (c *AccountConfig) IdentifierFormatOrDefault() string {
+	if c.IdentifierFormat == "" {
+		return AccountFormatUsername
+	}
+	return c.IdentifierFormat
+}
+
+// AllowsEmail 判断当前策略是否允许邮箱作为账号
+func (c *AccountConfig) AllowsEmail() bool {
+	format := c.IdentifierFormatOrDefault()
+	return format == AccountFormatEmail || format == AccountFormatEither
+}
@@ -0,0 +1,96 @@
+package config
+
+import "time"
+
+// 头像处理相关的默认值
+const (
+	defaultAvatarMinDimension  = 64               // 尺寸下限 (像素)
+	defaultAvatarMaxDimension  = 4096             // 尺寸上限 (像素)
+	defaultAvatarMaxFileSize   = 5 * 1024 * 1024  // 文件大小上限 (字节)
+	defaultAvatarFetchTimeout  = 10 * time.Second // 拉取远程头像的超时时间
+	defaultGeneratedAvatarSize = 256              // 注册时生成的默认头像边长 (像素)
+	defaultAvatarUploadLockTTL = 30 * time.Second // 头像上传锁的最长持有时间
+)
+
+// 注册时默认头像的生成样式
+const (
+	DefaultAvatarNone      = ""          // 不生成，新用户头像为空
+	DefaultAvatarIdenticon = "identicon" // 由用户 ID 生成的对称几何图案
+	DefaultAvatarInitials  = "initials"  // 彩色背景 + 昵称首字母，昵称中没有可绘制的字母或数字时退化为 identicon
+)
+
+// AvatarConfig 定义头像上传处理的相关配置
+type AvatarConfig struct {
+	StripMetadata bool `mapstructure:"strip_metadata" json:"strip_metadata" yaml:"strip_metadata"` // 是否在上传前通过重新编码去除 JPEG/PNG 的 EXIF/GPS 等元数据
+	JPEGQuality   int  `mapstructure:"jpeg_quality" json:"jpeg_quality" yaml:"jpeg_quality"`       // 重新编码 JPEG 时的质量 (1-100)，<=0 时使用默认值 75
+
+	MinWidth  int `mapstructure:"min_width" json:"min_width" yaml:"min_width"`    // 最小宽度，<=0 时默认 64
+	MinHeight int `mapstructure:"min_height" json:"min_height" yaml:"min_height"` // 最小高度，<=0 时默认 64
+	MaxWidth  int `mapstructure:"max_width" json:"max_width" yaml:"max_width"`    // 最大宽度，<=0 时默认 4096
+	MaxHeight int `mapstructure:"max_height" json:"max_height" yaml:"max_height"` // 最大高度，<=0 时默认 4096
+
+	MaxFileSize  int64         `mapstructure:"max_file_size" json:"max_file_size" yaml:"max_file_size"` // 头像文件大小上限 (字节)，<=0 时默认 5MB，对所有上传方式生效
+	FetchTimeout time.Duration `mapstructure:"fetch_timeout" json:"fetch_timeout" yaml:"fetch_timeout"` // 从 URL 拉取头像的超时时间，<=0 时默认 10 秒
+
+	DefaultStyle string `mapstructure:"default_style" json:"default_style" yaml:"default_style"` // 注册时生成的默认头像样式 (identicon / initials)，为空时不生成
+	DefaultSize  int    `mapstructure:"default_size" json:"default_size" yaml:"default_size"`    // 生成的默认头像边长 (像素)，<=0 时默认 256
+
+	// UploadLockTTL 同一用户头像上传互斥锁的最长持有时间 (正常情况下上传结束即释放)；0 时默认 30 秒，负数表示不加锁
+	UploadLockTTL time.Duration `mapstructure:"upload_lock_ttl" json:"upload_lock_ttl" yaml:"upload_lock_ttl"`
+}
+
+// UploadLockEnabled 是否对同一用户的头像上传加锁
+func (c *AvatarConfig) UploadLockEnabled() bool {
+	return c.UploadLockTTL >= 0
+}
+
+// UploadLockTTLOrDefault 返回应用默认值后的头像上传锁最长持有时间
+func (c *AvatarConfig) UploadLockTTLOrDefault() time.Duration {
+	if c.UploadLockTTL <= 0 {
+		return defaultAvatarUploadLockTTL
+	}
+	return c.UploadLockTTL
+}
+
+// DefaultAvatarEnabled 是否在注册时生成默认头像
+func (c *AvatarConfig) DefaultAvatarEnabled() bool {
+	return c.DefaultStyle == DefaultAvatarIdenticon || c.DefaultStyle == DefaultAvatarInitials
+}
+
+// DefaultSizeOrDefault 返回应用默认值后的默认头像边长
+func (c *AvatarConfig) DefaultSizeOrDefault() int {
+	if c.DefaultSize <= 0 {
+		return defaultGeneratedAvatarSize
+	}
+	return c.DefaultSize
+}
+
+// MaxFileSizeBytes 返回应用默认值后的头像文件大小上限
+func (c *AvatarConfig) MaxFileSizeBytes() int64 {
+	if c.MaxFileSize <= 0 {
+		return defaultAvatarMaxFileSize
+	}
+	return c.MaxFileSize
+}
+
+// FetchTimeoutOrDefault 返回应用默认值后的远程头像拉取超时时间
+func (c *AvatarConfig) FetchTimeoutOrDefault() time.Duration {
+	if c.FetchTimeout <= 0 {
+		return defaultAvatarFetchTimeout
+	}
+	return c.FetchTimeout
+}
+
+// DimensionBounds 返回应用默认值后的尺寸上下限
+func (c *AvatarConfig) DimensionBounds() (minWidth, minHeight, maxWidth, maxHeight int) {
+	orDefault := func(v, def int) int {
+		if v <= 0 {
+			return def
+		}
+		return v
+	}
+	return orDefault(c.MinWidth, defaultAvatarMinDimension),
+		orDefault(c.MinHeight, defaultAvatarMinDimension),
+		orDefault(c.MaxWidth, defaultAvatarMaxDimension),
+		orDefault(c.MaxHeight, defaultAvatarMaxDimension)
+}
@@ -0,0 +1,34 @@
+package config
+
+// 批量接口的操作名称，作为 BatchLimitConfig.Limits 的键
+const (
+	BatchOpIntrospectTokens = "introspect_tokens" // 批量令牌检查 (POST /auth/introspect/batch)
+	BatchOpSetRole          = "set_role"          // 批量设置用户角色 (POST /users/batch-role)
+)
+
+// defaultBatchLimit 未在默认表与配置中出现的操作使用的单次上限
+const defaultBatchLimit = 100
+
+// defaultBatchLimits 各批量操作的默认单次上限
+var defaultBatchLimits = map[string]int{
+	BatchOpIntrospectTokens: 100,
+	BatchOpSetRole:          500,
+}
+
+// BatchLimitConfig 定义各批量接口单次请求可提交的最大条目数
+//   - 所有批量接口统一按操作名称查找上限，超出时返回 400 并提示允许的最大值，避免各接口各自硬编码。
+//   - 未配置或配置值 <=0 的操作使用内置默认值；新增批量接口时在上方登记操作名称与默认值即可。
+type BatchLimitConfig struct {
+	Limits map[string]int `mapstructure:"limits" json:"limits" yaml:"limits"` // 操作名称 -> 单次最大条目数
+}
+
+// MaxOrDefault 返回指定批量操作的单次最大条目数
+func (c BatchLimitConfig) MaxOrDefault(op string) int {
+	if limit, ok := c.Limits[op]; ok && limit > 0 {
+		return limit
+	}
+	if limit, ok := defaultBatchLimits[op]; ok {
+		return limit
+	}
+	return defaultBatchLimit
+}
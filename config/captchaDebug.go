@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// CaptchaDebugEnvVar 启用验证码调试固定码时必须同时设置的环境变量 (值为 "true")
+// - 只改配置文件不会生效，避免测试配置被误用到生产环境。
+const CaptchaDebugEnvVar = "USER_HUB_ALLOW_CAPTCHA_DEBUG"
+
+// captchaCodePattern 固定验证码的格式，与 utils.GenerateCaptcha 生成的验证码一致
+var captchaCodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// CaptchaDebugConfig 定义端到端测试使用的验证码固定码
+//   - 手机号以 PhonePrefix 开头时，发送验证码接口不调用短信服务，验证码固定为 Code，供 CI 通过手机号登录。
+//   - 仅在 Enabled 为 true 且进程环境变量 USER_HUB_ALLOW_CAPTCHA_DEBUG=true 时生效，生产环境不应设置该环境变量。
+//   - 发送频率限制仍然生效。
+type CaptchaDebugConfig struct {
+	Enabled     bool   `mapstructure:"enabled" json:"enabled" yaml:"enabled"`                // 是否启用
+	PhonePrefix string `mapstructure:"phone_prefix" json:"phone_prefix" yaml:"phone_prefix"` // 测试手机号前缀，如 "1990000"，为空时不生效
+	Code        string `mapstructure:"code" json:"code" yaml:"code"`                         // 固定验证码，必须为 6 位数字
+}
+
+// Active 返回固定码是否实际生效：配置启用、前缀与验证码有效，且环境变量显式允许
+func (c *CaptchaDebugConfig) Active() bool {
+	return c.Enabled &&
+		c.PhonePrefix != "" &&
+		captchaCodePattern.MatchString(c.Code) &&
+		os.Getenv(CaptchaDebugEnvVar) == "true"
+}
+
+// Matches 返回手机号是否使用固定码 (调用方应先确认 Active)
+func (c *CaptchaDebugConfig) Matches(phone string) bool {
+	return strings.HasPrefix(phone, c.PhonePrefix)
+}
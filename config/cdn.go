@@ -0,0 +1,16 @@
+package config
+
+// CDN 缓存刷新服务提供方
+const (
+	CDNProviderNone    = "none"    // 不刷新 CDN 缓存（默认）
+	CDNProviderTencent = "tencent" // 腾讯云 CDN
+)
+
+// CDNConfig 定义 CDN 缓存刷新的相关配置
+// - 头像使用固定对象键 (avatar_naming: latest) 并经由 CDN 分发时，需要在更新后刷新缓存
+type CDNConfig struct {
+	Provider  string `mapstructure:"provider" json:"provider" yaml:"provider"`       // 提供方: none (默认) 或 tencent
+	SecretID  string `mapstructure:"secret_id" json:"secret_id" yaml:"secret_id"`    // 腾讯云 API SecretId，为空时复用 COSConfig 的 SecretId
+	SecretKey string `mapstructure:"secret_key" json:"secret_key" yaml:"secret_key"` // 腾讯云 API SecretKey，为空时复用 COSConfig 的 SecretKey
+	Endpoint  string `mapstructure:"endpoint" json:"endpoint" yaml:"endpoint"`       // 可选：API 域名，默认 cdn.tencentcloudapi.com
+}
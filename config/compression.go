@@ -0,0 +1,10 @@
+package config
+
+// CompressionConfig 定义 HTTP 响应压缩 (gzip) 的相关配置
+type CompressionConfig struct {
+	Enabled       bool     `mapstructure:"enabled" json:"enabled" yaml:"enabled"`                      // 是否启用响应压缩
+	Level         int      `mapstructure:"level" json:"level" yaml:"level"`                            // gzip 压缩级别 (1-9)，0 表示使用默认级别 (无法配置为不压缩，不需要压缩时将 enabled 设为 false)
+	MinSize       int      `mapstructure:"min_size" json:"min_size" yaml:"min_size"`                   // 触发压缩的最小响应体字节数，<=0 时使用默认值 1024
+	ContentTypes  []string `mapstructure:"content_types" json:"content_types" yaml:"content_types"`    // 允许压缩的 Content-Type 列表（支持 "text/*" 形式的前缀），为空时使用默认列表
+	ExcludedPaths []string `mapstructure:"excluded_paths" json:"excluded_paths" yaml:"excluded_paths"` // 不做压缩的路径前缀（例如流式导出接口）
+}
@@ -0,0 +1,42 @@
+package config
+
+import "time"
+
+const (
+	// defaultConcurrencyRetryAfter 并发饱和时建议客户端重试等待时间的默认值
+	defaultConcurrencyRetryAfter = time.Second
+)
+
+// defaultConcurrencyExemptPaths 未配置豁免路径时默认不受并发上限约束的路径前缀 (健康检查与指标抓取)
+var defaultConcurrencyExemptPaths = []string{"/health", "/metrics"}
+
+// ConcurrencyLimitConfig 定义全局在途请求数上限 (背压) 的相关配置
+//   - 在途请求数达到 MaxInFlight 时，新请求直接返回 503 并附带 Retry-After，避免压垮数据库/Redis 连接池。
+//   - ExemptPaths 中的路径前缀不计入也不受限，保证负载高峰时健康检查仍能响应。
+type ConcurrencyLimitConfig struct {
+	Enabled     bool          `mapstructure:"enabled" json:"enabled" yaml:"enabled"`                   // 是否启用在途请求数上限
+	MaxInFlight int           `mapstructure:"max_in_flight" json:"max_in_flight" yaml:"max_in_flight"` // 最大在途请求数，<=0 时视为未启用
+	RetryAfter  time.Duration `mapstructure:"retry_after" json:"retry_after" yaml:"retry_after"`       // 饱和时通过 Retry-After 建议的等待时间，<=0 时使用默认值 1 秒
+	ExemptPaths []string      `mapstructure:"exempt_paths" json:"exempt_paths" yaml:"exempt_paths"`    // 不受限的路径前缀，为空时使用默认列表 (/health、/metrics)
+}
+
+// Active 返回是否需要注册并发上限中间件
+func (c ConcurrencyLimitConfig) Active() bool {
+	return c.Enabled && c.MaxInFlight > 0
+}
+
+// RetryAfterOrDefault 返回饱和时建议的重试等待时间
+func (c ConcurrencyLimitConfig) RetryAfterOrDefault() time.Duration {
+	if c.RetryAfter <= 0 {
+		return defaultConcurrencyRetryAfter
+	}
+	return c.RetryAfter
+}
+
+// ExemptPathsOrDefault 返回不受并发上限约束的路径前缀
+func (c ConcurrencyLimitConfig) ExemptPathsOrDefault() []string {
+	if len(c.ExemptPaths) == 0 {
+		return defaultConcurrencyExemptPaths
+	}
+	return c.ExemptPaths
+}
@@ -1,5 +1,11 @@
 package config
 
+// Web 平台响应体中意外出现刷新令牌时的处理方式
+const (
+	RefreshTokenGuardStrip = "strip" // 记录错误日志并移除刷新令牌后继续响应
+	RefreshTokenGuardFail  = "fail"  // 记录错误日志并以 500 结束请求
+)
+
 // CookieConfig 定义用于设置 HTTP Cookie 的相关参数
 type CookieConfig struct {
 	// Domain 指定 Cookie 对哪些域名有效。
@@ -30,5 +36,17 @@ type CookieConfig struct {
 	// RefreshTokenName 定义了存储刷新令牌的 Cookie 的名称。
 	RefreshTokenName string `mapstructure:"refresh_token_name" json:"refresh_token_name" yaml:"refresh_token_name"`
 
-	// 注意: 刷新令牌 Cookie 的 MaxAge (生命周期) 将从 constants.RefreshTokenTTL 获取并转换为秒。
+	// RefreshTokenGuard Web 平台的刷新令牌只能通过 Cookie 下发，响应体中意外出现时的处理方式。
+	// 可选值: "strip" (默认)、"fail"。测试环境建议设为 "fail"，让违反约束的改动无法通过测试。
+	RefreshTokenGuard string `mapstructure:"refresh_token_guard" json:"refresh_token_guard" yaml:"refresh_token_guard"`
+
+	// 注意: 刷新令牌 Cookie 的 MaxAge (生命周期) 取自 JWTConfig 中对应平台的刷新令牌有效期并转换为秒。
+}
+
+// RefreshTokenGuardOrDefault 返回应用默认值后的刷新令牌拦截方式，未知取值按 strip 处理
+func (c *CookieConfig) RefreshTokenGuardOrDefault() string {
+	if c.RefreshTokenGuard == RefreshTokenGuardFail {
+		return RefreshTokenGuardFail
+	}
+	return RefreshTokenGuardStrip
 }
@@ -1,5 +1,28 @@
 package config
 
+// 头像对象键命名模式
+const (
+	// AvatarNamingVersioned 每次上传生成新的对象键 ({时间戳}_{uuid}{扩展名})。
+	// URL 随内容变化，CDN/浏览器缓存天然失效，无需刷新缓存；代价是旧对象会残留在存储桶中。
+	AvatarNamingVersioned = "versioned"
+	// AvatarNamingLatest 每个用户使用固定的对象键，新头像直接覆盖旧对象。
+	// URL 保持稳定，便于 CDN 长期缓存且不产生孤儿对象；代价是更新后必须刷新 CDN 缓存，否则客户端仍会看到旧图。
+	AvatarNamingLatest = "latest"
+)
+
+// COS 对象访问权限 (ACL)
+const (
+	COSACLDefault    = "default"     // 不单独设置，继承存储桶的权限
+	COSACLPrivate    = "private"     // 私有读写，需通过预签名 URL 访问
+	COSACLPublicRead = "public-read" // 公有读、私有写
+)
+
+// COS 对象类型，用于选择上传时的默认 ACL
+const (
+	COSObjectTypeAvatar   = "avatar"   // 用户头像
+	COSObjectTypeDocument = "document" // 通过通用文件接口上传的文档等私有文件
+)
+
 // COSConfig 定义腾讯云对象存储 (COS) 的相关配置
 type COSConfig struct {
 	SecretID   string `mapstructure:"secret_id" yaml:"secret_id"`     // COS 的 SecretId
@@ -8,4 +31,41 @@ type COSConfig struct {
 	AppID      string `mapstructure:"app_id" yaml:"app_id"`           // 存储桶的 APPID (数字部分)
 	Region     string `mapstructure:"region" yaml:"region"`           // 存储桶所属地域 (例如 ap-guangzhou)
 	BaseURL    string `mapstructure:"base_url" yaml:"base_url"`       // 可选：存储桶的访问基础 URL (例如 https://images.example.com)
+
+	AvatarKeyPrefix  string `mapstructure:"avatar_key_prefix" yaml:"avatar_key_prefix"`     // 头像对象键前缀，为空时默认 "avatars/"
+	AvatarOmitUserID bool   `mapstructure:"avatar_omit_user_id" yaml:"avatar_omit_user_id"` // 为 true 时对象键不再包含 "{userID}/" 目录层级
+	AvatarNaming     string `mapstructure:"avatar_naming" yaml:"avatar_naming"`             // 头像命名模式: versioned (默认) 或 latest，取舍见 AvatarNamingVersioned/AvatarNamingLatest
+
+	ObjectACLs              map[string]string `mapstructure:"object_acls" yaml:"object_acls"`                               // 按对象类型 (avatar / document) 设置上传 ACL，未配置时头像为 public-read，其余为 private
+	PresignExpireSeconds    int               `mapstructure:"presign_expire_seconds" yaml:"presign_expire_seconds"`         // 私有对象预签名 GET URL 的默认有效秒数，<=0 时默认 900
+	PresignMaxExpireSeconds int               `mapstructure:"presign_max_expire_seconds" yaml:"presign_max_expire_seconds"` // 客户端可请求的预签名 URL 最长有效秒数，<=0 时默认 3600
+}
+
+// ACLFor 返回指定对象类型上传时使用的 ACL
+// - 未配置时头像默认 public-read (需公开展示)，其他类型默认 private
+func (c *COSConfig) ACLFor(objectType string) string {
+	if acl, ok := c.ObjectACLs[objectType]; ok && acl != "" {
+		return acl
+	}
+	if objectType == COSObjectTypeAvatar {
+		return COSACLPublicRead
+	}
+	return COSACLPrivate
+}
+
+// PresignExpireOrDefault 返回应用默认值后的预签名 URL 有效期 (秒)，不超过最长有效期
+func (c *COSConfig) PresignExpireOrDefault() int {
+	expire := c.PresignExpireSeconds
+	if expire <= 0 {
+		expire = 900
+	}
+	return min(expire, c.PresignMaxExpireOrDefault())
+}
+
+// PresignMaxExpireOrDefault 返回应用默认值后的预签名 URL 最长有效期 (秒)
+func (c *COSConfig) PresignMaxExpireOrDefault() int {
+	if c.PresignMaxExpireSeconds <= 0 {
+		return 3600
+	}
+	return c.PresignMaxExpireSeconds
 }
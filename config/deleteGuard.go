@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+// defaultDeleteGuardTimeout 调用删除前置检查服务的默认超时时间
+const defaultDeleteGuardTimeout = 3 * time.Second
+
+// DeleteGuardConfig 定义删除用户前的外部前置检查配置
+//   - 配置了 CheckURL 时，管理员删除用户前会调用该地址询问是否允许删除 (如用户仍有未完成的订单、帖子)，
+//     对方可以否决本次删除并给出原因；未配置时不做检查。
+type DeleteGuardConfig struct {
+	CheckURL string        `mapstructure:"check_url" json:"check_url" yaml:"check_url"` // 前置检查地址，为空时不检查
+	Timeout  time.Duration `mapstructure:"timeout" json:"timeout" yaml:"timeout"`       // 单次检查超时时间，<=0 时默认 3 秒
+	FailOpen bool          `mapstructure:"fail_open" json:"fail_open" yaml:"fail_open"` // 检查服务不可用时是否放行；默认 false，即无法确认时拒绝删除
+}
+
+// TimeoutOrDefault 返回应用默认值后的检查超时时间
+func (c *DeleteGuardConfig) TimeoutOrDefault() time.Duration {
+	if c.Timeout <= 0 {
+		return defaultDeleteGuardTimeout
+	}
+	return c.Timeout
+}
@@ -0,0 +1,22 @@
+package config
+
+// CredentialEncryptionKey 定义一把凭证加密密钥
+type CredentialEncryptionKey struct {
+	ID  string `mapstructure:"id" json:"id" yaml:"id"`    // 密钥版本标识，写入密文中用于解密时选择密钥，不可重复
+	Key string `mapstructure:"key" json:"key" yaml:"key"` // Base64 编码的 32 字节 AES-256 密钥
+}
+
+// CredentialEncryptionConfig 定义身份凭证的落库加密配置
+//   - 只用于需要取回原文的凭证 (如第三方刷新令牌)；账号密码始终哈希保存，不受此配置影响。
+//   - 密钥轮换：新增一把密钥并将 ActiveKeyID 指向它，新写入的凭证使用新密钥，
+//     旧密钥保留在 Keys 中以解密历史数据，确认历史数据均已重写后再移除。
+type CredentialEncryptionConfig struct {
+	ActiveKeyID    string                    `mapstructure:"active_key_id" json:"active_key_id" yaml:"active_key_id"`                                  // 加密新凭证使用的密钥 ID
+	Keys           []CredentialEncryptionKey `mapstructure:"keys" json:"keys" yaml:"keys"`                                                             // 全部可用于解密的密钥
+	EncryptedTypes []uint                    `mapstructure:"encrypted_identity_types" json:"encrypted_identity_types" yaml:"encrypted_identity_types"` // 需要加密保存凭证的身份类型 (账号密码类型会被忽略)
+}
+
+// Enabled 返回是否配置了加密密钥
+func (c *CredentialEncryptionConfig) Enabled() bool {
+	return len(c.Keys) > 0
+}
@@ -0,0 +1,43 @@
+package config
+
+// 失败请求体日志的默认值
+const (
+	defaultErrorBodyLogMaxBytes = 4096
+)
+
+// defaultErrorBodyRedactFields 未配置脱敏字段时默认脱敏的 JSON 字段名片段 (不区分大小写，包含即脱敏)
+var defaultErrorBodyRedactFields = []string{
+	"password",
+	"token",
+	"secret",
+	"credential",
+	"captcha",
+	"code",
+	"ticket",
+}
+
+// ErrorBodyLogConfig 定义失败请求的请求体日志配置
+// - 默认关闭；开启后仅对 Routes 中的路径、且响应为非 2xx 时记录请求体，用于排查 400/500。
+// - 请求体按 JSON 字段名脱敏，非 JSON 或超过大小上限的请求体只记录长度，不记录内容。
+type ErrorBodyLogConfig struct {
+	Enabled      bool     `mapstructure:"enabled" json:"enabled" yaml:"enabled"`                      // 是否启用
+	Routes       []string `mapstructure:"routes" json:"routes" yaml:"routes"`                         // 允许记录的路径前缀，为空时不记录任何路由
+	MaxBodyBytes int      `mapstructure:"max_body_bytes" json:"max_body_bytes" yaml:"max_body_bytes"` // 记录的请求体字节上限，<=0 时默认 4096
+	RedactFields []string `mapstructure:"redact_fields" json:"redact_fields" yaml:"redact_fields"`    // 需要脱敏的字段名片段，为空时使用默认列表
+}
+
+// MaxBodyBytesOrDefault 返回应用默认值后的请求体字节上限
+func (c *ErrorBodyLogConfig) MaxBodyBytesOrDefault() int {
+	if c.MaxBodyBytes <= 0 {
+		return defaultErrorBodyLogMaxBytes
+	}
+	return c.MaxBodyBytes
+}
+
+// RedactFieldsOrDefault 返回应用默认值后的脱敏字段名片段
+func (c *ErrorBodyLogConfig) RedactFieldsOrDefault() []string {
+	if len(c.RedactFields) == 0 {
+		return defaultErrorBodyRedactFields
+	}
+	return c.RedactFields
+}
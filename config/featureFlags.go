@@ -0,0 +1,8 @@
+package config
+
+// FeatureFlagConfig 定义下发给前端的功能开关配置，支持热重载（修改配置文件后无需重新部署）
+// 注意: 配置经由 viper 解析，开关名称会被统一转换为小写，建议使用 snake_case 命名。
+type FeatureFlagConfig struct {
+	Flags         map[string]bool            `mapstructure:"flags" json:"flags" yaml:"flags"`                            // 全局默认开关，key 为功能名称
+	RoleOverrides map[string]map[string]bool `mapstructure:"role_overrides" json:"role_overrides" yaml:"role_overrides"` // 按角色覆盖的开关，外层 key 为角色名 (admin/user/guest)
+}
@@ -0,0 +1,34 @@
+package config
+
+import "time"
+
+const (
+	// defaultHealthProbeTimeout 单项依赖探测的默认超时时间
+	defaultHealthProbeTimeout = 2 * time.Second
+	// defaultHealthOverallTimeout 一次深度健康检查的默认总时限
+	defaultHealthOverallTimeout = 5 * time.Second
+)
+
+// HealthCheckConfig 定义深度健康检查 (GET /health/deep) 的超时配置
+//   - 各依赖并发探测，单项超过 ProbeTimeout 即判定为不可用；
+//   - 整体超过 OverallTimeout 时立即返回，尚未完成的探测标记为超时，避免单个慢依赖拖住接口。
+type HealthCheckConfig struct {
+	ProbeTimeout   time.Duration `mapstructure:"probe_timeout" json:"probe_timeout" yaml:"probe_timeout"`       // 单项探测超时，<=0 时默认 2 秒
+	OverallTimeout time.Duration `mapstructure:"overall_timeout" json:"overall_timeout" yaml:"overall_timeout"` // 整体时限，<=0 时默认 5 秒
+}
+
+// ProbeTimeoutOrDefault 返回应用默认值后的单项探测超时
+func (c HealthCheckConfig) ProbeTimeoutOrDefault() time.Duration {
+	if c.ProbeTimeout <= 0 {
+		return defaultHealthProbeTimeout
+	}
+	return c.ProbeTimeout
+}
+
+// OverallTimeoutOrDefault 返回应用默认值后的整体时限
+func (c HealthCheckConfig) OverallTimeoutOrDefault() time.Duration {
+	if c.OverallTimeout <= 0 {
+		return defaultHealthOverallTimeout
+	}
+	return c.OverallTimeout
+}
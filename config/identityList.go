@@ -0,0 +1,21 @@
+package config
+
+const (
+	// defaultIdentityListPageSize 自助查询身份列表时未配置的默认每页条数
+	defaultIdentityListPageSize = 20
+)
+
+// IdentityListConfig 定义身份列表接口对非管理员调用者的软限制
+//   - 非管理员 (用户本人) 查询身份列表时按页返回，每页条数不超过 PageSize，标识符始终脱敏。
+//   - 管理员查询时返回完整、不分页的列表，仍需 full=true 才返回完整标识符。
+type IdentityListConfig struct {
+	PageSize int `mapstructure:"page_size" json:"page_size" yaml:"page_size"` // 非管理员每页最多返回的身份数，<=0 时使用默认值 20
+}
+
+// PageSizeOrDefault 返回非管理员每页最多返回的身份数
+func (c IdentityListConfig) PageSizeOrDefault() int {
+	if c.PageSize <= 0 {
+		return defaultIdentityListPageSize
+	}
+	return c.PageSize
+}
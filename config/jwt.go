@@ -1,8 +1,91 @@
 package config
 
+import (
+	"time"
+
+	"github.com/Xushengqwer/go-common/models/enums"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
 // JWTConfig 定义JWT认证功能的相关配置，包含密钥、过期时间等信息，用于生成和验证JWT。
 type JWTConfig struct {
 	SecretKey     string `mapstructure:"secret_key" yaml:"secret_key"`         // 用于签名Access Token的密钥
 	Issuer        string `mapstructure:"issuer" yaml:"issuer"`                 // JWT的签发者
 	RefreshSecret string `mapstructure:"refresh_secret" yaml:"refresh_secret"` // 用于签名Refresh Token的密钥
+
+	// Audience 写入签发令牌 aud 声明的受众，为空时不写入
+	Audience string `mapstructure:"audience" yaml:"audience"`
+	// AcceptedAudiences 解析令牌时额外接受的受众，用于多客户端部署或受众迁移期间；
+	// 与 Audience 合并后非空时，令牌的 aud 必须命中其中之一
+	AcceptedAudiences []string `mapstructure:"accepted_audiences" yaml:"accepted_audiences"`
+
+	// DefaultTTL 未按平台单独配置时使用的令牌有效期，未配置的字段回退到 constants 中的默认值
+	DefaultTTL TokenTTLConfig `mapstructure:"default_ttl" yaml:"default_ttl"`
+	// PlatformTTLs 按平台 (web / wechat / app) 覆盖令牌有效期，例如 App 端使用更长的刷新令牌
+	PlatformTTLs map[string]TokenTTLConfig `mapstructure:"platform_ttls" yaml:"platform_ttls"`
+	// RefreshWhitelist 是否启用数据库刷新令牌白名单：签发的刷新令牌 JTI 落库，刷新时必须存在且未被使用。
+	// 关闭时保持纯无状态模式，仅依赖 Redis 黑名单吊销。
+	RefreshWhitelist bool `mapstructure:"refresh_whitelist" yaml:"refresh_whitelist"`
+	// Leeway 解析访问令牌与刷新令牌时容忍的时钟偏差；0 使用 constants.TokenLeeway，负数表示不容忍
+	Leeway time.Duration `mapstructure:"leeway" yaml:"leeway"`
+}
+
+// TokenTTLConfig 定义一组访问令牌与刷新令牌的有效期，零值表示沿用上一级配置
+type TokenTTLConfig struct {
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl" yaml:"access_token_ttl"`   // 访问令牌有效期
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl" yaml:"refresh_token_ttl"` // 刷新令牌有效期
+}
+
+// AccessTokenTTLFor 返回指定平台的访问令牌有效期
+// - 优先级: 平台配置 > DefaultTTL > constants.AccessTokenTTL
+func (c *JWTConfig) AccessTokenTTLFor(platform enums.Platform) time.Duration {
+	if ttl, ok := c.PlatformTTLs[string(platform)]; ok && ttl.AccessTokenTTL > 0 {
+		return ttl.AccessTokenTTL
+	}
+	if c.DefaultTTL.AccessTokenTTL > 0 {
+		return c.DefaultTTL.AccessTokenTTL
+	}
+	return constants.AccessTokenTTL
+}
+
+// RefreshTokenTTLFor 返回指定平台的刷新令牌有效期
+// - 优先级: 平台配置 > DefaultTTL > constants.RefreshTokenTTL
+func (c *JWTConfig) RefreshTokenTTLFor(platform enums.Platform) time.Duration {
+	if ttl, ok := c.PlatformTTLs[string(platform)]; ok && ttl.RefreshTokenTTL > 0 {
+		return ttl.RefreshTokenTTL
+	}
+	if c.DefaultTTL.RefreshTokenTTL > 0 {
+		return c.DefaultTTL.RefreshTokenTTL
+	}
+	return constants.RefreshTokenTTL
+}
+
+// LeewayOrDefault 返回解析令牌时使用的时钟偏差容忍时间
+func (c *JWTConfig) LeewayOrDefault() time.Duration {
+	if c.Leeway < 0 {
+		return 0
+	}
+	if c.Leeway == 0 {
+		return constants.TokenLeeway
+	}
+	return c.Leeway
+}
+
+// ValidAudiences 返回解析令牌时接受的受众列表 (Audience 与 AcceptedAudiences 合并去重)
+// - 返回空列表表示不校验受众
+func (c *JWTConfig) ValidAudiences() []string {
+	seen := make(map[string]struct{}, len(c.AcceptedAudiences)+1)
+	var audiences []string
+	for _, aud := range append([]string{c.Audience}, c.AcceptedAudiences...) {
+		if aud == "" {
+			continue
+		}
+		if _, ok := seen[aud]; ok {
+			continue
+		}
+		seen[aud] = struct{}{}
+		audiences = append(audiences, aud)
+	}
+	return audiences
 }
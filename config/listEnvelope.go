@@ -0,0 +1,8 @@
+package config
+
+// ListEnvelopeConfig 定义列表接口分页信封的兼容配置
+// - 各列表接口统一返回 items / total / page / page_size / next_cursor。
+// - 旧版用户列表返回 users 字段，默认继续同时输出以兼容旧客户端；客户端全部迁移到 items 后可关闭。
+type ListEnvelopeConfig struct {
+	OmitLegacyFields bool `mapstructure:"omit_legacy_fields" json:"omit_legacy_fields" yaml:"omit_legacy_fields"` // 为 true 时不再输出 users 等旧版字段
+}
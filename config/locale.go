@@ -0,0 +1,32 @@
+package config
+
+import "strings"
+
+// defaultLocale 未配置支持语言时的默认语言 (当前响应文案均为简体中文)
+const defaultLocale = "zh-CN"
+
+// LocaleConfig 定义响应语言的协商配置
+// - 只接受白名单中的语言，避免返回翻译不完整的响应；Accept-Language 不匹配时回退到默认语言。
+type LocaleConfig struct {
+	Supported []string `mapstructure:"supported" json:"supported" yaml:"supported"` // 支持的语言标签 (BCP 47，如 zh-CN、en-US)，为空时只支持 zh-CN
+	Default   string   `mapstructure:"default" json:"default" yaml:"default"`       // 无法匹配时使用的语言，为空或不在支持列表中时取支持列表的第一项
+}
+
+// SupportedOrDefault 返回应用默认值后的支持语言列表
+func (c *LocaleConfig) SupportedOrDefault() []string {
+	if len(c.Supported) == 0 {
+		return []string{defaultLocale}
+	}
+	return c.Supported
+}
+
+// DefaultOrDefault 返回应用默认值后的回退语言，保证在支持列表中
+func (c *LocaleConfig) DefaultOrDefault() string {
+	supported := c.SupportedOrDefault()
+	for _, locale := range supported {
+		if strings.EqualFold(locale, c.Default) {
+			return locale
+		}
+	}
+	return supported[0]
+}
@@ -0,0 +1,7 @@
+package config
+
+// LoginPolicyConfig 定义登录策略相关的配置
+type LoginPolicyConfig struct {
+	// RequireVerifiedIdentity 开启后，登录所用的身份必须已验证 (Verified) 才能登录，默认关闭以保持原有行为
+	RequireVerifiedIdentity bool `mapstructure:"require_verified_identity" json:"require_verified_identity" yaml:"require_verified_identity"`
+}
@@ -5,4 +5,32 @@ type MySQLConfig struct {
 	DSN         string `mapstructure:"dsn" yaml:"dsn"`                     // MySQL DSN (Data Source Name)，例如 "userManage:password@tcp(host:port)/database?charset=utf8mb4&parseTime=True&loc=Local"
 	MaxOpenConn int    `mapstructure:"max_open_conn" yaml:"max_open_conn"` // 最大打开连接数
 	MaxIdleConn int    `mapstructure:"max_idle_conn" yaml:"max_idle_conn"` // 最大空闲连接数
+
+	ErrorLog   SQLErrorLogConfig   `mapstructure:"error_log" yaml:"error_log"`     // SQL 执行失败时的结构化日志配置
+	QueryCount QueryCountLogConfig `mapstructure:"query_count" yaml:"query_count"` // 单请求 SQL 次数统计 (N+1 检测) 配置
+}
+
+// defaultQueryCountWarnThreshold 单个请求执行 SQL 次数的默认告警阈值
+const defaultQueryCountWarnThreshold = 20
+
+// QueryCountLogConfig 定义单请求 SQL 执行次数统计的配置
+// - 仅用于开发与测试环境发现 N+1 查询；每条 SQL 都会经过计数回调，生产环境应保持关闭。
+type QueryCountLogConfig struct {
+	Enabled       bool `mapstructure:"enabled" yaml:"enabled"`               // 是否统计每个请求执行的 SQL 次数
+	WarnThreshold int  `mapstructure:"warn_threshold" yaml:"warn_threshold"` // 单个请求超过该次数时记录告警，<=0 时默认 20
+}
+
+// WarnThresholdOrDefault 返回应用默认值后的告警阈值
+func (c *QueryCountLogConfig) WarnThresholdOrDefault() int {
+	if c.WarnThreshold <= 0 {
+		return defaultQueryCountWarnThreshold
+	}
+	return c.WarnThreshold
+}
+
+// SQLErrorLogConfig 定义 SQL 执行失败时记录实际语句的相关配置
+// - 凭证类列 (如 credential、password_hash) 的参数无论如何配置都不会被记录
+type SQLErrorLogConfig struct {
+	Enabled         bool `mapstructure:"enabled" yaml:"enabled"`                     // 是否在仓库操作失败时记录实际执行的 SQL 与 TraceID
+	RedactAllParams bool `mapstructure:"redact_all_params" yaml:"redact_all_params"` // 是否隐藏全部绑定参数，只保留 SQL 结构 (默认仅隐藏凭证类列)
 }
@@ -0,0 +1,6 @@
+package config
+
+// ContentNegotiationConfig 定义响应格式协商的相关配置
+type ContentNegotiationConfig struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled" yaml:"enabled"` // 是否按 Accept 头协商响应格式 (JSON 为默认，可选 XML)
+}
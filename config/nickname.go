@@ -0,0 +1,70 @@
+package config
+
+// 昵称长度的默认值 (按字符数计算，一个汉字计为 1)
+const (
+	defaultNicknameMinLength = 1
+	defaultNicknameMaxLength = 20
+)
+
+// 注册时未提供昵称时的默认昵称策略
+const (
+	NicknameDefaultIdentifier = "identifier" // 使用登录标识符 (账号名、手机号)；微信 OpenID 不适合展示，按 empty 处理
+	NicknameDefaultRandom     = "random"     // 随机生成友好昵称，如 "快乐的海豚3821"
+	NicknameDefaultEmpty      = "empty"      // 留空
+)
+
+// NicknameConfig 定义昵称 (展示名) 的校验配置
+// - 昵称与登录账号解耦：允许中文等 Unicode 字符，只限制长度并拒绝控制字符。
+type NicknameConfig struct {
+	MinLength int `mapstructure:"min_length" json:"min_length" yaml:"min_length"` // 最小字符数，<=0 时默认 1
+	MaxLength int `mapstructure:"max_length" json:"max_length" yaml:"max_length"` // 最大字符数，<=0 时默认 20
+
+	// 各注册方式的默认昵称策略 (identifier / random / empty)，只在注册流程没有提供昵称时生效
+	AccountDefault string `mapstructure:"account_default" json:"account_default" yaml:"account_default"` // 账号注册，为空时默认 identifier
+	PhoneDefault   string `mapstructure:"phone_default" json:"phone_default" yaml:"phone_default"`       // 手机号自动注册，为空时默认 random (避免手机号作为公开昵称)
+	WechatDefault  string `mapstructure:"wechat_default" json:"wechat_default" yaml:"wechat_default"`    // 微信自动注册 (且微信昵称缺失或无效)，为空时默认 empty
+}
+
+// MinLengthOrDefault 返回应用默认值后的昵称最小字符数
+func (c *NicknameConfig) MinLengthOrDefault() int {
+	if c.MinLength <= 0 {
+		return defaultNicknameMinLength
+	}
+	return c.MinLength
+}
+
+// MaxLengthOrDefault 返回应用默认值后的昵称最大字符数 (不小于最小字符数)
+func (c *NicknameConfig) MaxLengthOrDefault() int {
+	maxLength := c.MaxLength
+	if maxLength <= 0 {
+		maxLength = defaultNicknameMaxLength
+	}
+	if minLength := c.MinLengthOrDefault(); maxLength < minLength {
+		return minLength
+	}
+	return maxLength
+}
+
+// AccountDefaultOrDefault 返回应用默认值后的账号注册默认昵称策略
+func (c *NicknameConfig) AccountDefaultOrDefault() string {
+	if c.AccountDefault == "" {
+		return NicknameDefaultIdentifier
+	}
+	return c.AccountDefault
+}
+
+// PhoneDefaultOrDefault 返回应用默认值后的手机号注册默认昵称策略
+func (c *NicknameConfig) PhoneDefaultOrDefault() string {
+	if c.PhoneDefault == "" {
+		return NicknameDefaultRandom
+	}
+	return c.PhoneDefault
+}
+
+// WechatDefaultOrDefault 返回应用默认值后的微信注册默认昵称策略
+func (c *NicknameConfig) WechatDefaultOrDefault() string {
+	if c.WechatDefault == "" {
+		return NicknameDefaultEmpty
+	}
+	return c.WechatDefault
+}
@@ -0,0 +1,99 @@
+package config
+
+import "time"
+
+// 密码策略相关的默认值
+const (
+	defaultPasswordHistorySize = 5 // 默认保留的历史密码数量
+
+	PasswordHashBcrypt   = "bcrypt"   // bcrypt 哈希算法
+	PasswordHashArgon2id = "argon2id" // argon2id 哈希算法
+
+	defaultPasswordHashAlgorithm = PasswordHashBcrypt
+	defaultBcryptCost            = 10        // 与 bcrypt.DefaultCost 一致
+	defaultArgon2MemoryKiB       = 64 * 1024 // 64 MiB
+	defaultArgon2Iterations      = 3
+	defaultArgon2Parallelism     = 2
+
+	defaultVerifyMaxAttempts = 5                // 校验当前密码接口每个窗口内的默认尝试次数
+	defaultVerifyWindow      = 15 * time.Minute // 校验当前密码接口的默认限流窗口
+)
+
+// PasswordPolicyConfig 定义密码策略相关配置
+// - 哈希算法与强度只影响新生成的哈希；校验时从已存储的哈希中识别算法与参数，旧哈希仍可正常校验。
+type PasswordPolicyConfig struct {
+	HistorySize int `mapstructure:"history_size" json:"history_size" yaml:"history_size"` // 修改/重置密码时禁止复用的最近密码数量 (N)，<=0 时默认 5
+
+	HashAlgorithm     string `mapstructure:"hash_algorithm" json:"hash_algorithm" yaml:"hash_algorithm"`             // 新密码使用的哈希算法: bcrypt (默认) / argon2id
+	BcryptCost        int    `mapstructure:"bcrypt_cost" json:"bcrypt_cost" yaml:"bcrypt_cost"`                      // bcrypt 强度 (4-31)，<=0 时默认 10
+	Argon2MemoryKiB   uint32 `mapstructure:"argon2_memory_kib" json:"argon2_memory_kib" yaml:"argon2_memory_kib"`    // argon2id 内存开销 (KiB)，为 0 时默认 65536
+	Argon2Iterations  uint32 `mapstructure:"argon2_iterations" json:"argon2_iterations" yaml:"argon2_iterations"`    // argon2id 迭代次数，为 0 时默认 3
+	Argon2Parallelism uint8  `mapstructure:"argon2_parallelism" json:"argon2_parallelism" yaml:"argon2_parallelism"` // argon2id 并行度，为 0 时默认 2
+
+	VerifyMaxAttempts   int `mapstructure:"verify_max_attempts" json:"verify_max_attempts" yaml:"verify_max_attempts"`       // 校验当前密码接口每个窗口内每个用户的尝试次数，<=0 时默认 5
+	VerifyWindowSeconds int `mapstructure:"verify_window_seconds" json:"verify_window_seconds" yaml:"verify_window_seconds"` // 校验当前密码接口的限流窗口 (秒)，<=0 时默认 900
+}
+
+// HistorySizeOrDefault 返回应用默认值后的历史密码保留数量
+func (c *PasswordPolicyConfig) HistorySizeOrDefault() int {
+	if c.HistorySize <= 0 {
+		return defaultPasswordHistorySize
+	}
+	return c.HistorySize
+}
+
+// HashAlgorithmOrDefault 返回应用默认值后的密码哈希算法
+func (c *PasswordPolicyConfig) HashAlgorithmOrDefault() string {
+	if c.HashAlgorithm == "" {
+		return defaultPasswordHashAlgorithm
+	}
+	return c.HashAlgorithm
+}
+
+// BcryptCostOrDefault 返回应用默认值后的 bcrypt 强度
+func (c *PasswordPolicyConfig) BcryptCostOrDefault() int {
+	if c.BcryptCost <= 0 {
+		return defaultBcryptCost
+	}
+	return c.BcryptCost
+}
+
+// Argon2MemoryKiBOrDefault 返回应用默认值后的 argon2id 内存开销 (KiB)
+func (c *PasswordPolicyConfig) Argon2MemoryKiBOrDefault() uint32 {
+	if c.Argon2MemoryKiB == 0 {
+		return defaultArgon2MemoryKiB
+	}
+	return c.Argon2MemoryKiB
+}
+
+// Argon2IterationsOrDefault 返回应用默认值后的 argon2id 迭代次数
+func (c *PasswordPolicyConfig) Argon2IterationsOrDefault() uint32 {
+	if c.Argon2Iterations == 0 {
+		return defaultArgon2Iterations
+	}
+	return c.Argon2Iterations
+}
+
+// Argon2ParallelismOrDefault 返回应用默认值后的 argon2id 并行度
+func (c *PasswordPolicyConfig) Argon2ParallelismOrDefault() uint8 {
+	if c.Argon2Parallelism == 0 {
+		return defaultArgon2Parallelism
+	}
+	return c.Argon2Parallelism
+}
+
+// VerifyMaxAttemptsOrDefault 返回应用默认值后的校验当前密码尝试次数上限
+func (c *PasswordPolicyConfig) VerifyMaxAttemptsOrDefault() int {
+	if c.VerifyMaxAttempts <= 0 {
+		return defaultVerifyMaxAttempts
+	}
+	return c.VerifyMaxAttempts
+}
+
+// VerifyWindowOrDefault 返回应用默认值后的校验当前密码限流窗口
+func (c *PasswordPolicyConfig) VerifyWindowOrDefault() time.Duration {
+	if c.VerifyWindowSeconds <= 0 {
+		return defaultVerifyWindow
+	}
+	return time.Duration(c.VerifyWindowSeconds) * time.Second
+}
@@ -0,0 +1,34 @@
+package config
+
+// X-Platform 信任模式
+const (
+	PlatformTrustAlways       = "always"        // 始终信任客户端提供的 X-Platform (兼容旧行为)
+	PlatformTrustTrustedProxy = "trusted_proxy" // 仅信任来自可信代理 (网关) 的 X-Platform
+)
+
+// defaultUntrustedPlatform 不信任请求头且无法从 User-Agent 推断时使用的平台
+// - web 平台的刷新令牌只写入 HttpOnly Cookie，不出现在响应体中，是最保守的选择。
+const defaultUntrustedPlatform = "web"
+
+// PlatformTrustConfig 定义 X-Platform 请求头的信任策略
+//   - X-Platform 决定刷新令牌放在 Cookie 还是响应体中，由客户端直接提供时可被篡改。
+//   - trusted_proxy 模式下，只有直连对端地址位于 TrustedProxies 中的请求才保留 X-Platform；
+//     其他请求的 X-Platform 会被改写为由 User-Agent 推断出的平台，推断不出时使用 DefaultPlatform。
+type PlatformTrustConfig struct {
+	Mode            string   `mapstructure:"mode" json:"mode" yaml:"mode"`                                     // 信任模式: always (默认) / trusted_proxy
+	TrustedProxies  []string `mapstructure:"trusted_proxies" json:"trusted_proxies" yaml:"trusted_proxies"`    // 可信代理的 IP 或 CIDR
+	DefaultPlatform string   `mapstructure:"default_platform" json:"default_platform" yaml:"default_platform"` // 无法推断平台时使用的平台，为空时默认 web
+}
+
+// TrustsOnlyProxies 返回是否启用了“仅信任可信代理”模式
+func (c *PlatformTrustConfig) TrustsOnlyProxies() bool {
+	return c.Mode == PlatformTrustTrustedProxy
+}
+
+// DefaultPlatformOrDefault 返回应用默认值后的兜底平台
+func (c *PlatformTrustConfig) DefaultPlatformOrDefault() string {
+	if c.DefaultPlatform == "" {
+		return defaultUntrustedPlatform
+	}
+	return c.DefaultPlatform
+}
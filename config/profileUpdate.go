@@ -0,0 +1,44 @@
+package config
+
+import (
+	"slices"
+	"time"
+)
+
+// defaultReverifyWindow 未配置时重新验证身份后的有效期
+const defaultReverifyWindow = 5 * time.Minute
+
+// ProfileUpdateConfig 定义用户修改资料的频率限制与敏感字段策略
+//   - 两类修改分别计时：资料字段 (昵称、性别、地区) 与头像，互不影响。
+//   - 冷却时间默认均为 0，即不限制；负数同样视为不限制。
+//   - SensitiveFields 中的字段 (nickname / gender / province / city) 发生变化时，要求用户在 ReverifyWindow 内重新验证过身份；
+//     默认为空，即不要求。
+type ProfileUpdateConfig struct {
+	Cooldown        time.Duration `mapstructure:"cooldown" json:"cooldown" yaml:"cooldown"`                         // 两次修改资料字段的最小间隔
+	AvatarCooldown  time.Duration `mapstructure:"avatar_cooldown" json:"avatar_cooldown" yaml:"avatar_cooldown"`    // 两次更换头像的最小间隔
+	SensitiveFields []string      `mapstructure:"sensitive_fields" json:"sensitive_fields" yaml:"sensitive_fields"` // 修改前需要重新验证身份的资料字段 (JSON 字段名)
+	ReverifyWindow  time.Duration `mapstructure:"reverify_window" json:"reverify_window" yaml:"reverify_window"`    // 重新验证身份后的有效期，<=0 时使用默认值 5 分钟
+}
+
+// CooldownOrDisabled 返回资料字段的修改间隔，<=0 时返回 0 (不限制)
+func (c *ProfileUpdateConfig) CooldownOrDisabled() time.Duration {
+	return max(c.Cooldown, 0)
+}
+
+// AvatarCooldownOrDisabled 返回头像的更换间隔，<=0 时返回 0 (不限制)
+func (c *ProfileUpdateConfig) AvatarCooldownOrDisabled() time.Duration {
+	return max(c.AvatarCooldown, 0)
+}
+
+// IsSensitiveField 返回指定资料字段 (JSON 字段名) 是否属于需要重新验证身份的敏感字段
+func (c *ProfileUpdateConfig) IsSensitiveField(field string) bool {
+	return slices.Contains(c.SensitiveFields, field)
+}
+
+// ReverifyWindowOrDefault 返回重新验证身份后的有效期
+func (c *ProfileUpdateConfig) ReverifyWindowOrDefault() time.Duration {
+	if c.ReverifyWindow <= 0 {
+		return defaultReverifyWindow
+	}
+	return c.ReverifyWindow
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// RedactedPlaceholder 替换敏感配置值的占位符
+const RedactedPlaceholder = "****"
+
+// RedactSecret 隐藏敏感字符串：非空时替换为占位符，空值保持为空，便于区分 "未配置" 与 "已配置"
+func RedactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return RedactedPlaceholder
+}
+
+// RedactDSN 隐藏 MySQL DSN 中的密码，例如 "user:pass@tcp(host:3306)/db" -> "user:****@tcp(host:3306)/db"
+// - 按最后一个 "@" 之前、第一个 ":" 之后定位密码；找不到时原样返回 (DSN 不含密码)
+func RedactDSN(dsn string) string {
+	atIndex := strings.LastIndex(dsn, "@")
+	if atIndex == -1 {
+		return dsn
+	}
+	colonIndex := strings.Index(dsn[:atIndex], ":")
+	if colonIndex == -1 {
+		return dsn
+	}
+	return dsn[:colonIndex+1] + RedactedPlaceholder + dsn[atIndex:]
+}
+
+// RedactURL 隐藏 URL 中 userinfo 部分的密码，以及常见的凭证类查询参数
+// - 无法解析时整体替换为占位符，避免原样输出可能含凭证的字符串
+func RedactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return RedactedPlaceholder
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), RedactedPlaceholder)
+	}
+	if query := u.Query(); len(query) > 0 {
+		for key := range query {
+			lower := strings.ToLower(key)
+			if strings.Contains(lower, "secret") || strings.Contains(lower, "token") || strings.Contains(lower, "key") || strings.Contains(lower, "password") {
+				query.Set(key, RedactedPlaceholder)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	// 占位符中的 "*" 在 userinfo 与查询参数中会被转义，还原为便于阅读的形式
+	return strings.ReplaceAll(u.String(), url.QueryEscape(RedactedPlaceholder), RedactedPlaceholder)
+}
+
+// Redacted 返回隐藏了全部敏感字段的配置副本，用于启动日志与管理员配置预览。
+// - 不修改原配置；各含敏感字段的子配置由其自身的 Redacted 方法处理。
+// - 新增含密钥、密码等敏感信息的配置字段时，必须同步在对应类型的 Redacted 方法中隐藏。
+func (c UserHubConfig) Redacted() UserHubConfig {
+	redacted := c
+	redacted.JWTConfig = c.JWTConfig.Redacted()
+	redacted.MySQLConfig = c.MySQLConfig.Redacted()
+	redacted.RedisConfig = c.RedisConfig.Redacted()
+	redacted.WechatConfig = c.WechatConfig.Redacted()
+	redacted.SMSConfig = c.SMSConfig.Redacted()
+	redacted.COSConfig = c.COSConfig.Redacted()
+	redacted.CDNConfig = c.CDNConfig.Redacted()
+	redacted.CredentialEncryption = c.CredentialEncryption.Redacted()
+	redacted.CaptchaDebug = c.CaptchaDebug.Redacted()
+	redacted.DeleteGuard = c.DeleteGuard.Redacted()
+	return redacted
+}
+
+// 以下含密钥的配置类型各自提供 Redacted 方法，并在 JSON 序列化时默认输出隐藏后的副本，
+// 使 json.Marshal、zap.Any 等方式输出单个子配置时也不会泄露密钥。
+// 配置加载走 mapstructure 解码，不受 MarshalJSON 影响。
+
+// Redacted 返回隐藏了签名密钥的 JWT 配置副本
+func (c JWTConfig) Redacted() JWTConfig {
+	c.SecretKey = RedactSecret(c.SecretKey)
+	c.RefreshSecret = RedactSecret(c.RefreshSecret)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏签名密钥
+func (c JWTConfig) MarshalJSON() ([]byte, error) {
+	type plain JWTConfig
+	return json.Marshal(plain(c.Redacted()))
+}
+
+// Redacted 返回隐藏了 DSN 密码的 MySQL 配置副本
+func (c MySQLConfig) Redacted() MySQLConfig {
+	c.DSN = RedactDSN(c.DSN)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏 DSN 密码
+func (c MySQLConfig) MarshalJSON() ([]byte, error) {
+	type plain MySQLConfig
+	return json.Marshal(plain(c.Redacted()))
+}
+
+// Redacted 返回隐藏了密码的 Redis 配置副本
+func (c RedisConfig) Redacted() RedisConfig {
+	c.Password = RedactSecret(c.Password)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏密码
+func (c RedisConfig) MarshalJSON() ([]byte, error) {
+	type plain RedisConfig
+	return json.Marshal(plain(c.Redacted()))
+}
+
+// Redacted 返回隐藏了 AppSecret 的微信配置副本
+func (c WechatConfig) Redacted() WechatConfig {
+	c.Secret = RedactSecret(c.Secret)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏 AppSecret
+func (c WechatConfig) MarshalJSON() ([]byte, error) {
+	type plain WechatConfig
+	return json.Marshal(plain(c.Redacted()))
+}
+
+// Redacted 返回隐藏了 Secret 的短信配置副本
+func (c SMSConfig) Redacted() SMSConfig {
+	c.Secret = RedactSecret(c.Secret)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏 Secret
+func (c SMSConfig) MarshalJSON() ([]byte, error) {
+	type plain SMSConfig
+	return json.Marshal(plain(c.Redacted()))
+}
+
+// Redacted 返回隐藏了 SecretId/SecretKey 的 COS 配置副本
+func (c COSConfig) Redacted() COSConfig {
+	c.SecretID = RedactSecret(c.SecretID)
+	c.SecretKey = RedactSecret(c.SecretKey)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏 SecretId/SecretKey
+func (c COSConfig) MarshalJSON() ([]byte, error) {
+	type plain COSConfig
+	return json.Marshal(plain(c.Redacted()))
+}
+
+// Redacted 返回隐藏了 SecretId/SecretKey 的 CDN 配置副本
+func (c CDNConfig) Redacted() CDNConfig {
+	c.SecretID = RedactSecret(c.SecretID)
+	c.SecretKey = RedactSecret(c.SecretKey)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏 SecretId/SecretKey
+func (c CDNConfig) MarshalJSON() ([]byte, error) {
+	type plain CDNConfig
+	return json.Marshal(plain(c.Redacted()))
+}
+
+// Redacted 返回隐藏了全部密钥的凭证加密配置副本；密钥列表会复制，不影响原配置
+func (c CredentialEncryptionConfig) Redacted() CredentialEncryptionConfig {
+	if len(c.Keys) > 0 {
+		keys := make([]CredentialEncryptionKey, len(c.Keys))
+		for i, key := range c.Keys {
+			keys[i] = key.Redacted()
+		}
+		c.Keys = keys
+	}
+	return c
+}
+
+// Redacted 返回隐藏了密钥内容、保留密钥 ID 的副本
+func (k CredentialEncryptionKey) Redacted() CredentialEncryptionKey {
+	k.Key = RedactSecret(k.Key)
+	return k
+}
+
+// MarshalJSON 序列化时隐藏密钥内容
+func (k CredentialEncryptionKey) MarshalJSON() ([]byte, error) {
+	type plain CredentialEncryptionKey
+	return json.Marshal(plain(k.Redacted()))
+}
+
+// Redacted 返回隐藏了固定验证码的调试配置副本
+func (c CaptchaDebugConfig) Redacted() CaptchaDebugConfig {
+	c.Code = RedactSecret(c.Code)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏固定验证码
+func (c CaptchaDebugConfig) MarshalJSON() ([]byte, error) {
+	type plain CaptchaDebugConfig
+	return json.Marshal(plain(c.Redacted()))
+}
+
+// Redacted 返回隐藏了检查地址中凭证的删除前置检查配置副本
+func (c DeleteGuardConfig) Redacted() DeleteGuardConfig {
+	c.CheckURL = RedactURL(c.CheckURL)
+	return c
+}
+
+// MarshalJSON 序列化时隐藏检查地址中的凭证
+func (c DeleteGuardConfig) MarshalJSON() ([]byte, error) {
+	type plain DeleteGuardConfig
+	return json.Marshal(plain(c.Redacted()))
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// 测试配置中使用的密钥，取值互不相同且不会出现在其他字段中，便于在序列化结果中检索
+const (
+	testJWTSecret       = "jwt-secret-value"
+	testJWTRefresh      = "jwt-refresh-value"
+	testMySQLPassword   = "mysql-password-value"
+	testRedisPassword   = "redis-password-value"
+	testWechatSecret    = "wechat-secret-value"
+	testSMSSecret       = "sms-secret-value"
+	testCOSSecretID     = "cos-secret-id-value"
+	testCOSSecretKey    = "cos-secret-key-value"
+	testCDNSecretID     = "cdn-secret-id-value"
+	testCDNSecretKey    = "cdn-secret-key-value"
+	testEncryptionKey   = "encryption-key-value"
+	testCaptchaCode     = "135790"
+	testGuardPassword   = "guard-password-value"
+	testGuardQueryToken = "guard-token-value"
+)
+
+var testSecrets = []string{
+	testJWTSecret, testJWTRefresh, testMySQLPassword, testRedisPassword, testWechatSecret, testSMSSecret,
+	testCOSSecretID, testCOSSecretKey, testCDNSecretID, testCDNSecretKey, testEncryptionKey,
+	testCaptchaCode, testGuardPassword, testGuardQueryToken,
+}
+
+// newSecretConfig 返回所有敏感字段均已填写的配置
+func newSecretConfig() UserHubConfig {
+	return UserHubConfig{
+		JWTConfig:    JWTConfig{SecretKey: testJWTSecret, RefreshSecret: testJWTRefresh, Issuer: "user_hub"},
+		MySQLConfig:  MySQLConfig{DSN: "root:" + testMySQLPassword + "@tcp(127.0.0.1:3306)/user_hub?parseTime=True"},
+		RedisConfig:  RedisConfig{Password: testRedisPassword},
+		WechatConfig: WechatConfig{Secret: testWechatSecret},
+		SMSConfig:    SMSConfig{Secret: testSMSSecret},
+		COSConfig:    COSConfig{SecretID: testCOSSecretID, SecretKey: testCOSSecretKey},
+		CDNConfig:    CDNConfig{SecretID: testCDNSecretID, SecretKey: testCDNSecretKey},
+		CredentialEncryption: CredentialEncryptionConfig{
+			ActiveKeyID: "k1",
+			Keys:        []CredentialEncryptionKey{{ID: "k1", Key: testEncryptionKey}},
+		},
+		CaptchaDebug: CaptchaDebugConfig{Enabled: true, PhonePrefix: "1990000", Code: testCaptchaCode},
+		DeleteGuard:  DeleteGuardConfig{CheckURL: "https://guard:" + testGuardPassword + "@guard.example.com/check?access_token=" + testGuardQueryToken},
+	}
+}
+
+// assertNoSecrets 断言序列化结果中不含任何测试密钥
+func assertNoSecrets(t *testing.T, name string, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("%s: 序列化失败: %v", name, err)
+	}
+	for _, secret := range testSecrets {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("%s: 序列化结果泄露了密钥 %q: %s", name, secret, data)
+		}
+	}
+}
+
+func TestMarshalJSONRedactsSecrets(t *testing.T) {
+	cfg := newSecretConfig()
+
+	assertNoSecrets(t, "UserHubConfig", cfg)
+	assertNoSecrets(t, "*UserHubConfig", &cfg)
+	assertNoSecrets(t, "UserHubConfig.Redacted", cfg.Redacted())
+
+	subConfigs := map[string]any{
+		"JWTConfig":                  cfg.JWTConfig,
+		"MySQLConfig":                cfg.MySQLConfig,
+		"RedisConfig":                cfg.RedisConfig,
+		"WechatConfig":               cfg.WechatConfig,
+		"SMSConfig":                  cfg.SMSConfig,
+		"COSConfig":                  cfg.COSConfig,
+		"CDNConfig":                  cfg.CDNConfig,
+		"CredentialEncryptionConfig": cfg.CredentialEncryption,
+		"CredentialEncryptionKey":    cfg.CredentialEncryption.Keys[0],
+		"CaptchaDebugConfig":         cfg.CaptchaDebug,
+		"DeleteGuardConfig":          cfg.DeleteGuard,
+	}
+	for name, sub := range subConfigs {
+		assertNoSecrets(t, name, sub)
+	}
+	// 通过指针序列化 (如 zap.Any(&cfg.JWTConfig)) 同样需要隐藏
+	assertNoSecrets(t, "*JWTConfig", &cfg.JWTConfig)
+	assertNoSecrets(t, "*DeleteGuardConfig", &cfg.DeleteGuard)
+}
+
+func TestRedactedKeepsNonSecretFieldsAndOriginal(t *testing.T) {
+	cfg := newSecretConfig()
+	redacted := cfg.Redacted()
+
+	if redacted.JWTConfig.Issuer != "user_hub" {
+		t.Errorf("Issuer = %q，非敏感字段不应被修改", redacted.JWTConfig.Issuer)
+	}
+	if want := "root:****@tcp(127.0.0.1:3306)/user_hub?parseTime=True"; redacted.MySQLConfig.DSN != want {
+		t.Errorf("DSN = %q，期望 %q", redacted.MySQLConfig.DSN, want)
+	}
+	if redacted.CredentialEncryption.Keys[0].ID != "k1" {
+		t.Errorf("密钥 ID 应保留，实际为 %q", redacted.CredentialEncryption.Keys[0].ID)
+	}
+	if redacted.CaptchaDebug.PhonePrefix != "1990000" {
+		t.Errorf("PhonePrefix = %q，非敏感字段不应被修改", redacted.CaptchaDebug.PhonePrefix)
+	}
+	if redacted.RedisConfig.Password != RedactedPlaceholder {
+		t.Errorf("已配置的密码应替换为占位符，实际为 %q", redacted.RedisConfig.Password)
+	}
+
+	// 原配置不受影响，尤其是共享底层数组的密钥列表
+	if cfg.JWTConfig.SecretKey != testJWTSecret || cfg.CredentialEncryption.Keys[0].Key != testEncryptionKey || cfg.CaptchaDebug.Code != testCaptchaCode {
+		t.Error("Redacted 不应修改原配置")
+	}
+
+	// 未配置的密钥保持为空，便于区分 "未配置" 与 "已配置"
+	if empty := (UserHubConfig{}).Redacted(); empty.RedisConfig.Password != "" || empty.CaptchaDebug.Code != "" || empty.DeleteGuard.CheckURL != "" {
+		t.Errorf("未配置的敏感字段应保持为空: %+v", empty)
+	}
+}
@@ -0,0 +1,11 @@
+package config
+
+// RedisDegradationConfig 定义 Redis 不可用时各功能的降级策略
+//   - 每个功能在 dependencies.RedisDegradation 中声明自己的失败模式。
+//   - 安全关键功能 (如令牌黑名单、密码校验限流) 始终失败即拒绝 (fail closed)，不受本配置影响。
+//   - FailOpen 为 true 时，验证码发送限流等非安全功能在 Redis 出错时记录日志并放行请求；
+//     为 false (默认) 时与旧行为一致，返回系统错误。
+//   - 资料修改冷却一直是记录日志后放行，无论本配置如何都保持放行。
+type RedisDegradationConfig struct {
+	FailOpen bool `mapstructure:"fail_open" json:"fail_open" yaml:"fail_open"` // 非安全功能在 Redis 出错时是否放行
+}
@@ -0,0 +1,34 @@
+package config
+
+import "fmt"
+
+// 省市一致性校验模式
+const (
+	// RegionCheckOff 不校验 (默认)
+	RegionCheckOff = "off"
+	// RegionCheckWarn 只记录警告日志，仍然保存用户提交的省市
+	RegionCheckWarn = "warn"
+	// RegionCheckReject 拒绝省市不匹配或省份未知的请求
+	RegionCheckReject = "reject"
+)
+
+// RegionConfig 定义资料中省份/城市一致性校验的相关配置
+type RegionConfig struct {
+	Mode        string `mapstructure:"mode" json:"mode" yaml:"mode"`                         // 校验模式: off | warn | reject，为空时等同于 off
+	DatasetFile string `mapstructure:"dataset_file" json:"dataset_file" yaml:"dataset_file"` // 自定义行政区划数据 JSON 文件路径，为空时使用内置的中国行政区划数据
+}
+
+// Enabled 报告是否需要执行省市校验
+func (c *RegionConfig) Enabled() bool {
+	return c.Mode == RegionCheckWarn || c.Mode == RegionCheckReject
+}
+
+// Validate 校验配置取值
+func (c *RegionConfig) Validate() error {
+	switch c.Mode {
+	case "", RegionCheckOff, RegionCheckWarn, RegionCheckReject:
+		return nil
+	default:
+		return fmt.Errorf("不支持的省市校验模式 '%s'，可选值: off, warn, reject", c.Mode)
+	}
+}
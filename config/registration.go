@@ -0,0 +1,29 @@
+package config
+
+const (
+	// RegistrationClosedModeLoginOnly 注册关闭时，手机号/微信入口仍允许已有用户登录，仅拒绝自动注册新用户 (默认)
+	RegistrationClosedModeLoginOnly = "login_only"
+	// RegistrationClosedModeReject 注册关闭时，手机号/微信的"登录或注册"入口整体拒绝，已有用户需改用账号密码登录
+	RegistrationClosedModeReject = "reject"
+)
+
+// RegistrationConfig 定义新用户注册的开关，支持热重载（修改配置文件后无需重新部署）
+//   - 关闭后账号注册、手机号自动注册、微信自动注册均返回"注册暂未开放"，已有用户的账号密码登录不受影响。
+//   - 管理员后台创建用户不受此开关限制。
+type RegistrationConfig struct {
+	Enabled    *bool  `mapstructure:"enabled" json:"enabled" yaml:"enabled"`             // 是否开放注册，未配置时视为开放
+	ClosedMode string `mapstructure:"closed_mode" json:"closed_mode" yaml:"closed_mode"` // 注册关闭时手机号/微信入口的行为: login_only (默认) 或 reject
+}
+
+// Open 返回当前是否开放注册，未配置 enabled 时视为开放
+func (c RegistrationConfig) Open() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// ClosedModeOrDefault 返回注册关闭时手机号/微信入口的行为，未配置或取值无效时使用 login_only
+func (c RegistrationConfig) ClosedModeOrDefault() string {
+	if c.ClosedMode == RegistrationClosedModeReject {
+		return RegistrationClosedModeReject
+	}
+	return RegistrationClosedModeLoginOnly
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+// RouteTimeoutOverride 定义一组路由的超时时间覆盖
+type RouteTimeoutOverride struct {
+	PathPrefix string        `mapstructure:"path_prefix" json:"path_prefix" yaml:"path_prefix"` // 路由模板前缀，如 "/api/v1/user-hub/profile/avatar"
+	Timeout    time.Duration `mapstructure:"timeout" json:"timeout" yaml:"timeout"`             // 该组路由的超时时间，<=0 的条目会被忽略
+}
+
+// RouteTimeoutConfig 定义按路由组覆盖全局请求超时的配置
+//   - 全局超时 (serverConfig.requestTimeout) 保持较短，头像上传、导入导出等耗时接口在此单独放宽。
+//   - 按注册时的路由模板 (而非实际请求路径) 做前缀匹配，多个条目匹配时取最长前缀。
+type RouteTimeoutConfig struct {
+	Overrides []RouteTimeoutOverride `mapstructure:"overrides" json:"overrides" yaml:"overrides"` // 超时覆盖列表，为空时所有路由使用全局超时
+}
@@ -0,0 +1,44 @@
+package config
+
+import "time"
+
+// 过期刷新令牌清理任务的默认值
+const (
+	defaultSessionSweepInterval   = time.Hour // 清理间隔
+	defaultSessionSweepBatchSize  = 500       // 单批删除的记录数
+	defaultSessionSweepMaxBatches = 20        // 单轮最多执行的批次数
+)
+
+// SessionSweepConfig 定义后台清理过期刷新令牌记录 (会话白名单) 的配置
+// - 令牌自然过期且未显式登出时，记录只会在该用户下次登录时顺带清理；长期不再登录的用户需要后台任务兜底。
+// - 仅在启用 JWTConfig.RefreshWhitelist 时生效。
+type SessionSweepConfig struct {
+	Enabled    bool          `mapstructure:"enabled" json:"enabled" yaml:"enabled"`             // 是否启用后台清理
+	Interval   time.Duration `mapstructure:"interval" json:"interval" yaml:"interval"`          // 清理间隔，<=0 时默认 1 小时
+	BatchSize  int           `mapstructure:"batch_size" json:"batch_size" yaml:"batch_size"`    // 单批删除的记录数，<=0 时默认 500
+	MaxBatches int           `mapstructure:"max_batches" json:"max_batches" yaml:"max_batches"` // 单轮最多执行的批次数，<=0 时默认 20，剩余记录留到下一轮
+}
+
+// IntervalOrDefault 返回应用默认值后的清理间隔
+func (c *SessionSweepConfig) IntervalOrDefault() time.Duration {
+	if c.Interval <= 0 {
+		return defaultSessionSweepInterval
+	}
+	return c.Interval
+}
+
+// BatchSizeOrDefault 返回应用默认值后的单批删除记录数
+func (c *SessionSweepConfig) BatchSizeOrDefault() int {
+	if c.BatchSize <= 0 {
+		return defaultSessionSweepBatchSize
+	}
+	return c.BatchSize
+}
+
+// MaxBatchesOrDefault 返回应用默认值后的单轮最大批次数
+func (c *SessionSweepConfig) MaxBatchesOrDefault() int {
+	if c.MaxBatches <= 0 {
+		return defaultSessionSweepMaxBatches
+	}
+	return c.MaxBatches
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+// ShutdownConfig 定义服务优雅关停的相关配置
+type ShutdownConfig struct {
+	// Timeout 等待在途请求处理完毕的最长时间，超时后强制关闭剩余连接。
+	// 未配置 (<=0) 时使用默认值 10 秒。
+	Timeout time.Duration `mapstructure:"timeout" json:"timeout" yaml:"timeout"`
+}
@@ -1,5 +1,18 @@
 package config
 
+import "time"
+
+// 验证码发送频率限制的默认值
+const (
+	defaultCaptchaCooldown   = 60 * time.Second // 同一手机号两次发送之间的默认冷却时间
+	defaultCaptchaDailyLimit = 10               // 同一手机号每天默认最多发送次数
+	defaultSendAttempts      = 3                // 单条短信默认最多尝试次数 (含首次)
+	defaultSendBackoff       = 200 * time.Millisecond
+
+	defaultGlobalSendsPerMinute = 60   // 全局 (所有手机号合计) 每分钟默认最多发送次数
+	defaultGlobalSendsPerHour   = 1000 // 全局 (所有手机号合计) 每小时默认最多发送次数
+)
+
 // SMSConfig 定义微信云托管 SMS 客户端的配置
 type SMSConfig struct {
 	// 微信云托管的 AppID
@@ -16,4 +29,81 @@ type SMSConfig struct {
 
 	// 云托管环境 ID（如 "prod-123"）
 	Env string `mapstructure:"env" json:"env" yaml:"env"`
+
+	// 同一手机号两次发送验证码之间的冷却秒数 (首次发送与重发共用)，<=0 时默认 60
+	CooldownSeconds int `mapstructure:"cooldown_seconds" json:"cooldown_seconds" yaml:"cooldown_seconds"`
+
+	// 同一手机号每天最多发送验证码的次数 (首次发送与重发共用)，<=0 时默认 10
+	DailyLimit int `mapstructure:"daily_limit" json:"daily_limit" yaml:"daily_limit"`
+
+	// 所有手机号合计每分钟最多发送验证码的次数，防止同一客户端轮换大量号码消耗短信额度；0 时默认 60，负数表示不限制
+	GlobalPerMinute int `mapstructure:"global_per_minute" json:"global_per_minute" yaml:"global_per_minute"`
+
+	// 所有手机号合计每小时最多发送验证码的次数；0 时默认 1000，负数表示不限制
+	GlobalPerHour int `mapstructure:"global_per_hour" json:"global_per_hour" yaml:"global_per_hour"`
+
+	// 单条短信最多尝试发送的次数 (含首次)，<=0 时默认 3；全部失败后写入死信表
+	SendAttempts int `mapstructure:"send_attempts" json:"send_attempts" yaml:"send_attempts"`
+
+	// 首次重试前的等待毫秒数，之后每次翻倍，<=0 时默认 200
+	RetryBackoffMs int `mapstructure:"retry_backoff_ms" json:"retry_backoff_ms" yaml:"retry_backoff_ms"`
+
+	// 是否异步发送：验证码先写入 Redis，立即响应，发送与重试在后台完成
+	AsyncSend bool `mapstructure:"async_send" json:"async_send" yaml:"async_send"`
+}
+
+// CooldownOrDefault 返回应用默认值后的发送冷却时间
+func (c *SMSConfig) CooldownOrDefault() time.Duration {
+	if c.CooldownSeconds <= 0 {
+		return defaultCaptchaCooldown
+	}
+	return time.Duration(c.CooldownSeconds) * time.Second
+}
+
+// DailyLimitOrDefault 返回应用默认值后的每日发送上限
+func (c *SMSConfig) DailyLimitOrDefault() int {
+	if c.DailyLimit <= 0 {
+		return defaultCaptchaDailyLimit
+	}
+	return c.DailyLimit
+}
+
+// GlobalPerMinuteOrDefault 返回应用默认值后的全局每分钟发送上限，0 表示不限制
+func (c *SMSConfig) GlobalPerMinuteOrDefault() int {
+	switch {
+	case c.GlobalPerMinute < 0:
+		return 0
+	case c.GlobalPerMinute == 0:
+		return defaultGlobalSendsPerMinute
+	default:
+		return c.GlobalPerMinute
+	}
+}
+
+// GlobalPerHourOrDefault 返回应用默认值后的全局每小时发送上限，0 表示不限制
+func (c *SMSConfig) GlobalPerHourOrDefault() int {
+	switch {
+	case c.GlobalPerHour < 0:
+		return 0
+	case c.GlobalPerHour == 0:
+		return defaultGlobalSendsPerHour
+	default:
+		return c.GlobalPerHour
+	}
+}
+
+// SendAttemptsOrDefault 返回应用默认值后的单条短信最多尝试次数
+func (c *SMSConfig) SendAttemptsOrDefault() int {
+	if c.SendAttempts <= 0 {
+		return defaultSendAttempts
+	}
+	return c.SendAttempts
+}
+
+// RetryBackoffOrDefault 返回应用默认值后的首次重试等待时间
+func (c *SMSConfig) RetryBackoffOrDefault() time.Duration {
+	if c.RetryBackoffMs <= 0 {
+		return defaultSendBackoff
+	}
+	return time.Duration(c.RetryBackoffMs) * time.Millisecond
 }
@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+// defaultStartupCheckTimeout 单项启动连通性检查的默认超时时间
+const defaultStartupCheckTimeout = 5 * time.Second
+
+// StartupConfig 定义服务启动阶段的相关配置
+type StartupConfig struct {
+	// StrictStartup 为 true 时，启动阶段实际探测 COS 与短信服务的凭证和连通性，
+	// 任一检查失败即终止启动；离线或本地开发环境保持 false 即可跳过。
+	StrictStartup bool `mapstructure:"strictStartup" json:"strictStartup" yaml:"strictStartup"`
+
+	// CheckTimeout 单项连通性检查的超时时间，未配置 (<=0) 时默认 5 秒。
+	CheckTimeout time.Duration `mapstructure:"check_timeout" json:"check_timeout" yaml:"check_timeout"`
+}
+
+// CheckTimeoutOrDefault 返回应用默认值后的单项检查超时时间
+func (c *StartupConfig) CheckTimeoutOrDefault() time.Duration {
+	if c.CheckTimeout <= 0 {
+		return defaultStartupCheckTimeout
+	}
+	return c.CheckTimeout
+}
@@ -0,0 +1,20 @@
+package config
+
+// 存储配额相关的默认值
+const (
+	defaultStorageQuotaMB = 100 // 每个用户默认的存储配额 (MB)
+)
+
+// StorageQuotaConfig 定义用户上传文件的存储配额配置
+type StorageQuotaConfig struct {
+	QuotaMB int `mapstructure:"quota_mb" json:"quota_mb" yaml:"quota_mb"` // 每个用户可使用的存储空间 (MB)，<=0 时默认 100
+}
+
+// QuotaBytes 返回应用默认值后的每用户存储配额 (字节)
+func (c *StorageQuotaConfig) QuotaBytes() int64 {
+	quota := c.QuotaMB
+	if quota <= 0 {
+		quota = defaultStorageQuotaMB
+	}
+	return int64(quota) << 20
+}
@@ -0,0 +1,22 @@
+package config
+
+// defaultTenantHeader 携带租户 ID 的默认请求头
+const defaultTenantHeader = "X-Tenant-ID"
+
+// TenantConfig 定义账号标识符唯一性的租户范围
+//   - 未启用时所有身份属于同一个全局租户，标识符全局唯一 (与旧行为一致)。
+//   - 启用后按请求头中的租户 ID 限定唯一性与登录查找，同一手机号/账号可在不同租户下各自注册；
+//     未携带请求头的请求归属全局租户。租户 ID 应由网关写入，不应直接信任终端用户。
+type TenantConfig struct {
+	Enabled bool     `mapstructure:"enabled" json:"enabled" yaml:"enabled"` // 是否启用租户隔离
+	Header  string   `mapstructure:"header" json:"header" yaml:"header"`    // 携带租户 ID 的请求头，为空时默认 X-Tenant-ID
+	Allowed []string `mapstructure:"allowed" json:"allowed" yaml:"allowed"` // 允许的租户 ID 列表，为空时只校验格式
+}
+
+// HeaderOrDefault 返回应用默认值后的租户请求头
+func (c *TenantConfig) HeaderOrDefault() string {
+	if c.Header == "" {
+		return defaultTenantHeader
+	}
+	return c.Header
+}
@@ -0,0 +1,27 @@
+package config
+
+// 统一登录入口支持的身份类型
+const (
+	UnifiedLoginTypeAccount = "account" // 账号 + 密码
+	UnifiedLoginTypePhone   = "phone"   // 手机号 + 验证码
+	UnifiedLoginTypeEmail   = "email"   // 邮箱 (暂未支持邮箱身份，仅用于识别并给出明确提示)
+)
+
+// UnifiedLoginConfig 定义统一登录入口 (POST /login) 的相关配置
+type UnifiedLoginConfig struct {
+	Enabled      bool     `mapstructure:"enabled" json:"enabled" yaml:"enabled"`                   // 是否注册统一登录入口，关闭时只保留各方式的专用登录接口
+	AllowedTypes []string `mapstructure:"allowed_types" json:"allowed_types" yaml:"allowed_types"` // 允许通过统一入口登录的身份类型 (account / phone)，为空时全部允许
+}
+
+// TypeAllowed 判断指定身份类型是否允许通过统一入口登录
+func (c *UnifiedLoginConfig) TypeAllowed(identityType string) bool {
+	if len(c.AllowedTypes) == 0 {
+		return true
+	}
+	for _, t := range c.AllowedTypes {
+		if t == identityType {
+			return true
+		}
+	}
+	return false
+}
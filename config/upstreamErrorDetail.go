@@ -0,0 +1,11 @@
+package config
+
+// UpstreamErrorDetailConfig 定义第三方服务 (COS/短信/微信) 调用失败时是否在响应中附带脱敏的上游错误摘要
+//   - 摘要只包含服务名、错误类别 (timeout/network/http_status/api_error 等)、上游状态码与错误码，不含原始错误文本。
+//   - 默认关闭，终端用户始终只看到通用文案；开启后仅管理员请求附带摘要，便于排障时区分 "COS 403" 与 "COS 超时"。
+type UpstreamErrorDetailConfig struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled" yaml:"enabled"` // 为 true 时管理员请求附带上游错误摘要
+
+	// AllCallers 调试开关，为 true 时对所有调用者附带摘要 (需同时开启 Enabled)，切勿在生产环境使用
+	AllCallers bool `mapstructure:"all_callers" json:"all_callers" yaml:"all_callers"`
+}
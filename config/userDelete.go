@@ -0,0 +1,21 @@
+package config
+
+// 删除用户的方式
+const (
+	UserDeleteModeSoft = "soft" // 软删除：写入 deleted_at，数据保留，管理员仍可通过 include_deleted 查看
+	UserDeleteModeHard = "hard" // 硬删除：物理删除用户、身份与资料记录，并删除存储中的头像 (满足数据最小化要求)
+)
+
+// UserDeleteConfig 定义管理员删除用户的策略
+type UserDeleteConfig struct {
+	Mode          string `mapstructure:"mode" json:"mode" yaml:"mode"`                               // 默认删除方式 (soft / hard)，为空时默认 soft
+	AllowOverride bool   `mapstructure:"allow_override" json:"allow_override" yaml:"allow_override"` // 是否允许管理员通过 mode 参数按请求指定删除方式
+}
+
+// ModeOrDefault 返回应用默认值后的删除方式
+func (c *UserDeleteConfig) ModeOrDefault() string {
+	if c.Mode == UserDeleteModeHard {
+		return UserDeleteModeHard
+	}
+	return UserDeleteModeSoft
+}
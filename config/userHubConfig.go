@@ -5,15 +5,48 @@ import (
 )
 
 type UserHubConfig struct {
-	ZapConfig     config.ZapConfig     `mapstructure:"zapConfig" json:"zapConfig" yaml:"zapConfig"`
-	GormLogConfig config.GormLogConfig `mapstructure:"gormLogConfig" json:"gormLogConfig" yaml:"gormLogConfig"`
-	ServerConfig  config.ServerConfig  `mapstructure:"serverConfig" json:"serverConfig" yaml:"serverConfig"`
-	TracerConfig  config.TracerConfig  `mapstructure:"tracerConfig" json:"tracerConfig" yaml:"tracerConfig"`
-	JWTConfig     JWTConfig            `mapstructure:"jwtConfig" json:"jwtConfig" yaml:"jwtConfig"`
-	MySQLConfig   MySQLConfig          `mapstructure:"mySQLConfig" json:"mySQLConfig" yaml:"mySQLConfig"`
-	RedisConfig   RedisConfig          `mapstructure:"redisConfig" json:"redisConfig" yaml:"redisConfig"`
-	WechatConfig  WechatConfig         `mapstructure:"wechatConfig" json:"wechatConfig" yaml:"wechatConfig"`
-	SMSConfig     SMSConfig            `mapstructure:"smsConfig" json:"smsConfig" yaml:"smsConfig"`
-	COSConfig     COSConfig            `mapstructure:"cosConfig" json:"cosConfig" yaml:"cosConfig"`
-	CookieConfig  CookieConfig         `mapstructure:"cookieConfig" json:"cookieConfig" yaml:"cookieConfig"`
+	ZapConfig            config.ZapConfig           `mapstructure:"zapConfig" json:"zapConfig" yaml:"zapConfig"`
+	GormLogConfig        config.GormLogConfig       `mapstructure:"gormLogConfig" json:"gormLogConfig" yaml:"gormLogConfig"`
+	ServerConfig         config.ServerConfig        `mapstructure:"serverConfig" json:"serverConfig" yaml:"serverConfig"`
+	TracerConfig         config.TracerConfig        `mapstructure:"tracerConfig" json:"tracerConfig" yaml:"tracerConfig"`
+	JWTConfig            JWTConfig                  `mapstructure:"jwtConfig" json:"jwtConfig" yaml:"jwtConfig"`
+	MySQLConfig          MySQLConfig                `mapstructure:"mySQLConfig" json:"mySQLConfig" yaml:"mySQLConfig"`
+	RedisConfig          RedisConfig                `mapstructure:"redisConfig" json:"redisConfig" yaml:"redisConfig"`
+	WechatConfig         WechatConfig               `mapstructure:"wechatConfig" json:"wechatConfig" yaml:"wechatConfig"`
+	SMSConfig            SMSConfig                  `mapstructure:"smsConfig" json:"smsConfig" yaml:"smsConfig"`
+	COSConfig            COSConfig                  `mapstructure:"cosConfig" json:"cosConfig" yaml:"cosConfig"`
+	CDNConfig            CDNConfig                  `mapstructure:"cdnConfig" json:"cdnConfig" yaml:"cdnConfig"`
+	AvatarConfig         AvatarConfig               `mapstructure:"avatarConfig" json:"avatarConfig" yaml:"avatarConfig"`
+	StorageConfig        StorageQuotaConfig         `mapstructure:"storageConfig" json:"storageConfig" yaml:"storageConfig"`
+	RegionConfig         RegionConfig               `mapstructure:"regionConfig" json:"regionConfig" yaml:"regionConfig"`
+	PasswordConfig       PasswordPolicyConfig       `mapstructure:"passwordConfig" json:"passwordConfig" yaml:"passwordConfig"`
+	CredentialEncryption CredentialEncryptionConfig `mapstructure:"credentialEncryptionConfig" json:"credentialEncryptionConfig" yaml:"credentialEncryptionConfig"`
+	AccountConfig        AccountConfig              `mapstructure:"accountConfig" json:"accountConfig" yaml:"accountConfig"`
+	NicknameConfig       NicknameConfig             `mapstructure:"nicknameConfig" json:"nicknameConfig" yaml:"nicknameConfig"`
+	LoginPolicyConfig    LoginPolicyConfig          `mapstructure:"loginPolicyConfig" json:"loginPolicyConfig" yaml:"loginPolicyConfig"`
+	PlatformTrust        PlatformTrustConfig        `mapstructure:"platformTrustConfig" json:"platformTrustConfig" yaml:"platformTrustConfig"`
+	CaptchaDebug         CaptchaDebugConfig         `mapstructure:"captchaDebugConfig" json:"captchaDebugConfig" yaml:"captchaDebugConfig"`
+	RedisDegradation     RedisDegradationConfig     `mapstructure:"redisDegradationConfig" json:"redisDegradationConfig" yaml:"redisDegradationConfig"`
+	Tenant               TenantConfig               `mapstructure:"tenantConfig" json:"tenantConfig" yaml:"tenantConfig"`
+	CookieConfig         CookieConfig               `mapstructure:"cookieConfig" json:"cookieConfig" yaml:"cookieConfig"`
+	UnifiedLogin         UnifiedLoginConfig         `mapstructure:"unifiedLoginConfig" json:"unifiedLoginConfig" yaml:"unifiedLoginConfig"`
+	CompressionConfig    CompressionConfig          `mapstructure:"compressionConfig" json:"compressionConfig" yaml:"compressionConfig"`
+	Negotiation          ContentNegotiationConfig   `mapstructure:"contentNegotiationConfig" json:"contentNegotiationConfig" yaml:"contentNegotiationConfig"`
+	LocaleConfig         LocaleConfig               `mapstructure:"localeConfig" json:"localeConfig" yaml:"localeConfig"`
+	ListEnvelope         ListEnvelopeConfig         `mapstructure:"listEnvelopeConfig" json:"listEnvelopeConfig" yaml:"listEnvelopeConfig"`
+	ErrorBodyLog         ErrorBodyLogConfig         `mapstructure:"errorBodyLogConfig" json:"errorBodyLogConfig" yaml:"errorBodyLogConfig"`
+	BatchLimit           BatchLimitConfig           `mapstructure:"batchLimitConfig" json:"batchLimitConfig" yaml:"batchLimitConfig"`
+	UpstreamErrorDetail  UpstreamErrorDetailConfig  `mapstructure:"upstreamErrorDetailConfig" json:"upstreamErrorDetailConfig" yaml:"upstreamErrorDetailConfig"`
+	HealthCheck          HealthCheckConfig          `mapstructure:"healthCheckConfig" json:"healthCheckConfig" yaml:"healthCheckConfig"`
+	IdentityList         IdentityListConfig         `mapstructure:"identityListConfig" json:"identityListConfig" yaml:"identityListConfig"`
+	RouteTimeout         RouteTimeoutConfig         `mapstructure:"routeTimeoutConfig" json:"routeTimeoutConfig" yaml:"routeTimeoutConfig"`
+	ConcurrencyLimit     ConcurrencyLimitConfig     `mapstructure:"concurrencyLimitConfig" json:"concurrencyLimitConfig" yaml:"concurrencyLimitConfig"`
+	ShutdownConfig       ShutdownConfig             `mapstructure:"shutdownConfig" json:"shutdownConfig" yaml:"shutdownConfig"`
+	SessionSweep         SessionSweepConfig         `mapstructure:"sessionSweepConfig" json:"sessionSweepConfig" yaml:"sessionSweepConfig"`
+	DeleteGuard          DeleteGuardConfig          `mapstructure:"deleteGuardConfig" json:"deleteGuardConfig" yaml:"deleteGuardConfig"`
+	ProfileUpdate        ProfileUpdateConfig        `mapstructure:"profileUpdateConfig" json:"profileUpdateConfig" yaml:"profileUpdateConfig"`
+	UserDelete           UserDeleteConfig           `mapstructure:"userDeleteConfig" json:"userDeleteConfig" yaml:"userDeleteConfig"`
+	StartupConfig        StartupConfig              `mapstructure:"startupConfig" json:"startupConfig" yaml:"startupConfig"`
+	FeatureFlagConfig    FeatureFlagConfig          `mapstructure:"featureFlagConfig" json:"featureFlagConfig" yaml:"featureFlagConfig"`
+	Registration         RegistrationConfig         `mapstructure:"registrationConfig" json:"registrationConfig" yaml:"registrationConfig"`
 }
@@ -1,9 +1,25 @@
 package config
 
+import "time"
+
+// defaultWechatSessionTTL 缓存的 session_key 默认保留时长；微信不公布 session_key 的有效期，客户端在其失效时应重新调用刷新接口
+const defaultWechatSessionTTL = 72 * time.Hour
+
 type WechatConfig struct {
 	// 小程序的 AppID
 	AppID string `mapstructure:"appID" json:"appID" yaml:"appID"`
 
 	// 小程序的 AppSecret
 	Secret string `mapstructure:"secret" json:"secret" yaml:"secret"`
+
+	// 登录或刷新会话后缓存 session_key 的秒数，<=0 时默认 259200 (72 小时)
+	SessionTTLSeconds int `mapstructure:"session_ttl_seconds" json:"session_ttl_seconds" yaml:"session_ttl_seconds"`
+}
+
+// SessionTTLOrDefault 返回应用默认值后的 session_key 缓存时长
+func (c *WechatConfig) SessionTTLOrDefault() time.Duration {
+	if c.SessionTTLSeconds <= 0 {
+		return defaultWechatSessionTTL
+	}
+	return time.Duration(c.SessionTTLSeconds) * time.Second
 }
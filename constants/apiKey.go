@@ -0,0 +1,12 @@
+package constants
+
+const (
+	// APIKeyHeader 携带 API 密钥的请求头
+	APIKeyHeader = "X-API-Key"
+
+	// APIKeyTokenPrefix API 密钥明文的固定前缀，便于在日志、代码仓库扫描中识别泄露的密钥
+	APIKeyTokenPrefix = "uhk_"
+
+	// APIKeyIDContextKey 通过 API 密钥认证的请求在 Gin Context 中记录密钥 ID 的键
+	APIKeyIDContextKey = "APIKeyID"
+)
@@ -0,0 +1,4 @@
+package constants
+
+// LocaleContextKey 语言协商中间件在 Gin Context 中记录所选语言的键，值为支持列表中的语言标签
+const LocaleContextKey = "Locale"
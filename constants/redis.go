@@ -3,3 +3,36 @@ package constants
 // redis 键的前缀
 
 const BlacklistKeyPrefix = "blacklist"
+
+// ReactivationKeyPrefix 账号重新激活凭证的键前缀
+const ReactivationKeyPrefix = "reactivation"
+
+// WechatAccessTokenKey 缓存微信平台 access_token 的键
+const WechatAccessTokenKey = "wechat:access_token"
+
+// WechatAccessTokenLockKey 刷新微信 access_token 时使用的分布式锁键，防止多实例同时刷新
+const WechatAccessTokenLockKey = "wechat:access_token:lock"
+
+// RecoveryKeyPrefix 账号找回凭证的键前缀
+const RecoveryKeyPrefix = "recovery"
+
+// CaptchaSendKeyPrefix 验证码发送频率限制 (冷却、每日计数) 的键前缀
+const CaptchaSendKeyPrefix = "captcha_send"
+
+// SecurityOverviewKeyPrefix 账号安全概览短期缓存的键前缀
+const SecurityOverviewKeyPrefix = "security_overview"
+
+// AvatarUploadLockKeyPrefix 头像上传按用户互斥的锁键前缀
+const AvatarUploadLockKeyPrefix = "avatar_upload:lock"
+
+// PasswordVerifyKeyPrefix 校验当前密码接口按用户限流的计数键前缀
+const PasswordVerifyKeyPrefix = "password_verify"
+
+// WechatSessionKeyPrefix 微信 session_key 按用户缓存的键前缀 (供解密微信加密数据使用)
+const WechatSessionKeyPrefix = "wechat:session"
+
+// ProfileCooldownKeyPrefix 资料/头像修改冷却时间的键前缀
+const ProfileCooldownKeyPrefix = "profile_update:cooldown"
+
+// RecentAuthKeyPrefix 用户近期重新验证身份 (如校验当前密码) 标记的键前缀
+const RecentAuthKeyPrefix = "recent_auth"
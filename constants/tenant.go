@@ -0,0 +1,4 @@
+package constants
+
+// DefaultTenantID 未启用租户隔离或请求未指定租户时使用的全局租户，已有身份记录迁移后均属于该租户
+const DefaultTenantID = "default"
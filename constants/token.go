@@ -5,9 +5,12 @@ import (
 )
 
 const (
-	// 认证令牌和刷新令牌的过期时间
+	// 认证令牌和刷新令牌的默认过期时间 (可通过 JWTConfig 的 default_ttl / platform_ttls 按平台覆盖)
 
 	AccessTokenTTL = 15 * time.Minute // 认证令牌（Access Token）的有效期
 
 	RefreshTokenTTL = 10 * 24 * time.Hour // 刷新令牌（Refresh Token）的有效期
+
+	// TokenLeeway 解析令牌时校验 exp / nbf / iat 的默认时钟偏差容忍时间 (可通过 JWTConfig 的 leeway 覆盖)
+	TokenLeeway = 10 * time.Second
 )
@@ -0,0 +1,4 @@
+package constants
+
+// UpstreamErrorDetailContextKey 第三方错误详情中间件在 Gin Context 中记录配置的键，值为 config.UpstreamErrorDetailConfig
+const UpstreamErrorDetailContextKey = "UpstreamErrorDetail"
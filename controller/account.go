@@ -2,18 +2,19 @@ package controller
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/Xushengqwer/go-common/commonerrors" // 引入公共错误包
 	"github.com/Xushengqwer/go-common/core"         // 引入日志包
-	"github.com/Xushengqwer/go-common/models/enums"
 	"github.com/Xushengqwer/go-common/response"
 	"github.com/Xushengqwer/user_hub/config"
-	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/redis"
 	"github.com/Xushengqwer/user_hub/service/login/auth"
-	"github.com/Xushengqwer/user_hub/utils"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
 )
@@ -21,9 +22,10 @@ import (
 // AccountController 处理与账号密码认证相关的 HTTP 请求。
 // 依赖于 auth.AccountService 来执行核心业务逻辑。
 type AccountController struct {
-	accountService auth.AccountService // accountService: 账号密码认证服务的实例。
-	logger         *core.ZapLogger     // logger: 日志记录器。
-	cookieConfig   config.CookieConfig // 新增：存储 Cookie 配置
+	accountService auth.AccountService            // accountService: 账号密码认证服务的实例。
+	jwtUtil        dependencies.JWTTokenInterface // jwtUtil: JWT 工具，用于获取平台对应的刷新令牌有效期。
+	logger         *core.ZapLogger                // logger: 日志记录器。
+	cookieConfig   config.CookieConfig            // 新增：存储 Cookie 配置
 }
 
 // NewAccountController 创建一个新的 AccountController 实例。
@@ -32,6 +34,7 @@ type AccountController struct {
 //
 // 参数:
 //   - accountService: 实现了 auth.AccountService 接口的服务实例。
+//   - jwtUtil: JWT工具实例。
 //   - logger: 日志记录器实例。
 //   - cookieCfg: Cookie 配置。
 //
@@ -39,11 +42,13 @@ type AccountController struct {
 //   - *AccountController: 初始化完成的控制器实例。
 func NewAccountController(
 	accountService auth.AccountService,
+	jwtUtil dependencies.JWTTokenInterface,
 	logger *core.ZapLogger, // 注入 logger
 	cookieCfg config.CookieConfig, // 新增：接收 Cookie 配置
 ) *AccountController {
 	return &AccountController{
 		accountService: accountService,
+		jwtUtil:        jwtUtil,
 		logger:         logger,    // 存储 logger
 		cookieConfig:   cookieCfg, // 存储 Cookie 配置
 	}
@@ -58,6 +63,7 @@ func NewAccountController(
 // @Param body body dto.AccountRegisterData true "注册信息 (账号、密码、确认密码)"
 // @Success 200 {object} docs.SwaggerAPIUserinfoResponse "注册成功，返回用户信息（通常只有用户ID）"
 // @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、必填项缺失) 或 业务逻辑错误 (如账号已存在、密码不一致)"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "注册暂未开放"
 // @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库操作失败、密码加密失败)"
 // @Router /api/v1/user-hub/account/register [post]
 func (ctrl *AccountController) RegisterHandler(c *gin.Context) {
@@ -86,6 +92,9 @@ func (ctrl *AccountController) RegisterHandler(c *gin.Context) {
 				zap.Error(err), // 记录服务层返回的错误（虽然是 ErrSystemError，但可能包含包装信息）
 			)
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else if errors.Is(err, loginerr.ErrRegistrationClosed) {
+			// 注册开关已关闭，服务层已记录日志。
+			response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, loginerr.ErrRegistrationClosed.Error())
 		} else {
 			// 业务逻辑错误，记录警告级别日志。
 			ctrl.logger.Warn("账号注册服务返回业务错误",
@@ -114,10 +123,11 @@ func (ctrl *AccountController) RegisterHandler(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param body body dto.AccountLoginData true "登录信息 (账号、密码)"
-// @Param X-Platform header string true "客户端平台类型" Enums(web, wechat, app) default(web)
+// @Param X-Platform header string true "客户端平台类型 (wechat 平台仅用于微信登录)" Enums(web, app) default(web)
 // @Success 200 {object} docs.SwaggerAPILoginResponse "登录成功，返回用户信息及访问和刷新令牌"
-// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、平台类型无效) 或 业务逻辑错误 (如账号不存在、密码错误、用户状态异常)"
-// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库操作失败、令牌生成失败)"
+// @Failure 400 {object} docs.SwaggerAPILoginFailureResponse "请求参数无效 (如JSON格式错误、平台类型无效) 或 业务逻辑错误；data.reason: invalid_input / invalid_credentials / account_blacklisted / account_unavailable"
+// @Failure 403 {object} docs.SwaggerAPIReactivationChallengeResponse "账号已停用，返回重新激活凭证；开启身份验证策略且账号未验证时返回 SwaggerAPIIdentityVerificationRequiredResponse"
+// @Failure 500 {object} docs.SwaggerAPILoginFailureResponse "系统内部错误 (如数据库操作失败、令牌生成失败)；data.reason: internal_error"
 // @Router /api/v1/user-hub/account/login [post] // <--- 已更新路径
 func (ctrl *AccountController) LoginHandler(c *gin.Context) {
 	const operation = "AccountController.LoginHandler"
@@ -129,26 +139,29 @@ func (ctrl *AccountController) LoginHandler(c *gin.Context) {
 			zap.String("operation", operation),
 			zap.Error(err),
 		)
-		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, vo.LoginReasonInvalidInput, "输入参数无效")
 		return
 	}
 
 	// 2. 获取并验证请求头中的 X-Platform 参数。
 	platformStr := c.GetHeader("X-Platform")
-	platform, err := enums.PlatformFromString(platformStr)
+	platform, err := resolveLoginPlatform(c, "", credentialLoginPlatforms)
 	if err != nil {
-		ctrl.logger.Warn("无效的平台类型",
+		ctrl.logger.Warn("无效或与登录方式不匹配的平台类型",
 			zap.String("operation", operation),
 			zap.String("platformHeader", platformStr),
 			zap.Error(err),
 		)
-		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "无效的平台类型")
+		respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, vo.LoginReasonInvalidInput, err.Error())
 		return
 	}
 
 	// 3. 调用服务层执行登录逻辑。
 	userInfo, tokenPair, err := ctrl.accountService.Login(c.Request.Context(), accountLoginData, platform)
 	if err != nil {
+		if respondIfAccountDeactivated(c, err) || respondIfIdentityNotVerified(c, err) {
+			return
+		}
 		// 根据服务层返回的错误类型记录日志并响应。
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			ctrl.logger.Error("账号登录服务返回系统错误",
@@ -157,7 +170,7 @@ func (ctrl *AccountController) LoginHandler(c *gin.Context) {
 				zap.Any("platform", platform),
 				zap.Error(err),
 			)
-			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+			respondLoginServiceError(c, err)
 		} else {
 			ctrl.logger.Warn("账号登录服务返回业务错误",
 				zap.String("operation", operation),
@@ -165,42 +178,136 @@ func (ctrl *AccountController) LoginHandler(c *gin.Context) {
 				zap.Any("platform", platform),
 				zap.Error(err), // 记录具体的业务错误信息
 			)
-			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+			respondLoginServiceError(c, err)
 		}
 		return
 	}
 
 	// 4. 根据平台处理令牌响应
-	if platform == enums.PlatformWeb { // 假设 enums.PlatformWeb 是你定义的 web 平台枚举值
-		// Web 平台: RT 在 HttpOnly Cookie, AT 在 JSON
-		rtMaxAge := int(constants.RefreshTokenTTL.Seconds())
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     ctrl.cookieConfig.RefreshTokenName, // 使用注入的配置
-			Value:    tokenPair.RefreshToken,
-			MaxAge:   rtMaxAge,
-			Path:     ctrl.cookieConfig.Path,
-			Domain:   ctrl.cookieConfig.Domain,
-			Secure:   ctrl.cookieConfig.Secure,
-			HttpOnly: ctrl.cookieConfig.HttpOnly,
-			SameSite: utils.ParseSameSiteString(ctrl.cookieConfig.SameSite), // 使用你的辅助函数
-		})
-
-		// 准备只包含 AccessToken 的 JSON 响应
-		responseData := vo.LoginResponse{
-			User:  userInfo,
-			Token: vo.TokenPair{AccessToken: tokenPair.AccessToken}, // RefreshToken 为空
+	// Web 平台: RT 在 HttpOnly Cookie, AT 在 JSON；其他平台: AT 和 RT 都在 JSON
+	deliverRefreshToken(c, ctrl.cookieConfig, platform, &tokenPair, ctrl.jwtUtil.RefreshTokenTTL(platform))
+	if !guardWebRefreshToken(c, ctrl.cookieConfig, platform, &tokenPair, ctrl.logger, operation) {
+		return
+	}
+	responseData := vo.LoginResponse{
+		User:  userInfo,
+		Token: tokenPair,
+	}
+	ctrl.logger.Info("账号登录成功", zap.String("operation", operation), zap.String("userID", userInfo.UserID), zap.Any("platform", platform))
+	response.RespondSuccess(c, responseData, "登录成功")
+}
+
+// VerifyPasswordHandler 校验当前登录用户的密码，通过后记录近期重新验证标记。
+// @Summary 校验当前密码
+// @Description 进入敏感设置前的二次确认：校验当前登录用户提交的密码是否正确，通过后在一段时间内允许修改敏感资料字段。用户ID取自网关透传的认证信息；每个用户在限流窗口内的尝试次数有限 (无论密码是否正确都计数)，失败时统一返回通用提示。
+// @Tags 账号密码认证
+// @Accept json
+// @Produce json
+// @Param body body dto.VerifyPasswordDTO true "当前密码"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "密码校验通过"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 或 密码校验未通过"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "校验过于频繁"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/verify-password [post]
+func (ctrl *AccountController) VerifyPasswordHandler(c *gin.Context) {
+	const operation = "AccountController.VerifyPasswordHandler"
+
+	// 1. 从上下文获取当前用户 ID
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于校验密码", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	// 2. 绑定请求体
+	var req dto.VerifyPasswordDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("校验密码请求参数绑定失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	// 3. 调用服务层校验，并输出限流响应头
+	quota, err := ctrl.accountService.VerifyPassword(c.Request.Context(), userID, req.Password)
+	if quota.Limit > 0 {
+		setRateLimitHeaders(c, quota.Limit, quota.Remaining, quota.Reset)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, redis.ErrPasswordVerifyLimit):
+			setRetryAfter(c, quota.Reset)
+			response.RespondError(c, http.StatusTooManyRequests, response.ErrCodeClientRateLimitExceeded, fmt.Sprintf("密码校验过于频繁，请 %d 秒后重试", ceilSeconds(quota.Reset)))
+		case errors.Is(err, auth.ErrPasswordVerifyFailed):
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		default:
+			ctrl.logger.Error("校验当前密码失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
 		}
-		ctrl.logger.Info("账号登录成功 (Web平台，RT已设置到Cookie)", zap.String("operation", operation), zap.String("userID", userInfo.UserID), zap.Any("platform", platform))
-		response.RespondSuccess(c, responseData, "登录成功")
-	} else {
-		// 其他平台: AT 和 RT 都在 JSON (维持原样)
-		responseData := vo.LoginResponse{
-			User:  userInfo,
-			Token: tokenPair,
+		return
+	}
+
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "密码校验通过")
+}
+
+// ChangeAccountHandler 修改当前登录用户的账号（用户名）。
+// @Summary 修改账号
+// @Description 已登录用户提交新账号和当前密码，修改自己的账号密码身份的账号，用户ID保持不变。新账号需符合账号格式且未被占用；没有账号密码身份的用户 (如仅手机号登录) 无法修改。当前密码的校验与“校验当前密码”接口共用限流额度。
+// @Tags 账号密码认证
+// @Accept json
+// @Produce json
+// @Param body body dto.ChangeAccountDTO true "新账号与当前密码"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "账号修改成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效、新账号与当前账号相同、密码校验未通过 或 未设置账号密码"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 409 {object} docs.SwaggerAPIErrorResponseString "新账号已被占用"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "密码校验过于频繁"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/username [put]
+func (ctrl *AccountController) ChangeAccountHandler(c *gin.Context) {
+	const operation = "AccountController.ChangeAccountHandler"
+
+	// 1. 从上下文获取当前用户 ID
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于修改账号", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	// 2. 绑定并校验请求体 (新账号按账号格式校验)
+	var req dto.ChangeAccountDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("修改账号请求参数绑定失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	// 3. 调用服务层修改账号，并输出密码校验的限流响应头
+	quota, err := ctrl.accountService.ChangeAccount(c.Request.Context(), userID, req)
+	if quota.Limit > 0 {
+		setRateLimitHeaders(c, quota.Limit, quota.Remaining, quota.Reset)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, redis.ErrPasswordVerifyLimit):
+			setRetryAfter(c, quota.Reset)
+			response.RespondError(c, http.StatusTooManyRequests, response.ErrCodeClientRateLimitExceeded, fmt.Sprintf("密码校验过于频繁，请 %d 秒后重试", ceilSeconds(quota.Reset)))
+		case errors.Is(err, auth.ErrPasswordVerifyFailed),
+			errors.Is(err, auth.ErrNoAccountIdentity),
+			errors.Is(err, auth.ErrAccountUnchanged):
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		case errors.Is(err, auth.ErrAccountTaken):
+			response.RespondError(c, http.StatusConflict, response.ErrCodeClientInvalidInput, err.Error())
+		default:
+			ctrl.logger.Error("修改账号失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
 		}
-		ctrl.logger.Info("账号登录成功", zap.String("operation", operation), zap.String("userID", userInfo.UserID), zap.Any("platform", platform))
-		response.RespondSuccess(c, responseData, "登录成功")
+		return
 	}
+
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "账号修改成功")
 }
 
 // RegisterRoutes 注册与账号密码认证相关的路由到指定的 Gin 路由组。
@@ -221,4 +328,32 @@ func (ctrl *AccountController) RegisterRoutes(group *gin.RouterGroup) {
 	// - 路径: /api/v1/user-hub/account/login (相对于 group 的基础路径)
 	// - 方法: POST
 	group.POST("/account/login", ctrl.LoginHandler)
+
+	// 注册校验当前密码接口
+	// 场景: 进入敏感设置前，前端要求用户再次输入密码确认身份
+	// 预期权限: 需要认证，用户只能校验自己的密码 (用户ID来自网关透传的认证信息)
+	group.POST("/account/verify-password", ctrl.VerifyPasswordHandler)
+
+	// 注册修改账号接口
+	// 场景: 用户修改自己的账号（用户名），需提交当前密码确认身份，用户ID保持不变
+	// 预期权限: 需要认证，用户只能修改自己的账号 (用户ID来自网关透传的认证信息)
+	group.PUT("/account/username", ctrl.ChangeAccountHandler)
+}
+
+// respondIfIdentityNotVerified 若错误表示登录所用身份未验证 (登录策略要求已验证)，则返回 403 及验证指引并返回 true。
+func respondIfIdentityNotVerified(c *gin.Context, err error) bool {
+	var notVerifiedErr *auth.IdentityNotVerifiedError
+	if !errors.As(err, &notVerifiedErr) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, response.APIResponse[vo.IdentityVerificationRequiredVO]{
+		Code:    response.ErrCodeClientForbidden,
+		Message: notVerifiedErr.Error(),
+		Data: vo.IdentityVerificationRequiredVO{
+			Reason:       vo.LoginReasonIdentityNotVerified,
+			IdentityType: notVerifiedErr.IdentityType,
+			ResendPath:   notVerifiedErr.ResendPath,
+		},
+	})
+	return true
 }
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/login/auth"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+var registerValidatorsOnce sync.Once
+
+// registerTestValidators 与启动流程一致地注册自定义校验器与输入规范化，全局只注册一次
+func registerTestValidators(t *testing.T) {
+	t.Helper()
+	registerValidatorsOnce.Do(func() {
+		if err := utils.RegisterCustomValidators(config.AccountFormatUsername, config.NicknameConfig{}); err != nil {
+			t.Fatalf("注册自定义校验器失败: %v", err)
+		}
+	})
+}
+
+// recordingAccountService 内嵌接口，记录登录时服务层收到的账号
+type recordingAccountService struct {
+	auth.AccountService
+	accounts []string
+}
+
+func (s *recordingAccountService) Login(_ context.Context, data dto.AccountLoginData, _ enums.Platform) (vo.Userinfo, vo.TokenPair, error) {
+	s.accounts = append(s.accounts, data.Account)
+	return vo.Userinfo{UserID: "u1"}, vo.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func TestAccountLoginNormalizesAccount(t *testing.T) {
+	registerTestValidators(t)
+
+	tests := []struct {
+		name        string
+		account     string
+		wantStatus  int
+		wantAccount string // 服务层收到的账号，为空表示请求不应到达服务层
+	}{
+		{name: "原样账号", account: "User", wantStatus: http.StatusOK, wantAccount: "User"},
+		{name: "首尾空格", account: " User ", wantStatus: http.StatusOK, wantAccount: "User"},
+		{name: "首尾制表符与换行", account: "\tUser\n", wantStatus: http.StatusOK, wantAccount: "User"},
+		{name: "包含控制字符", account: "Us\u0000er", wantStatus: http.StatusBadRequest},
+		{name: "仅空白视为缺失", account: "   ", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &recordingAccountService{}
+			ctrl := NewAccountController(svc, fakeJWT{}, newTestLogger(t), testCookieConfig())
+			r := gin.New()
+			r.POST("/account/login", ctrl.LoginHandler)
+
+			body := `{"account":` + jsonString(t, tt.account) + `,"password":"secret"}`
+			w := performRequest(r, "/account/login", string(enums.PlatformApp), body, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("状态码 = %d，期望 %d，响应体: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantAccount == "" {
+				if len(svc.accounts) != 0 {
+					t.Errorf("无效输入不应到达服务层，实际收到 %q", svc.accounts)
+				}
+				return
+			}
+			// " User " 与 "User" 必须以同一个标识符查找身份
+			if len(svc.accounts) != 1 || svc.accounts[0] != tt.wantAccount {
+				t.Errorf("服务层收到的账号 = %q，期望 %q", svc.accounts, tt.wantAccount)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/apikey"
+)
+
+// APIKeyController 处理用户 API 密钥管理相关的 HTTP 请求。
+type APIKeyController struct {
+	apiKeyService apikey.APIKeyService // apiKeyService: API 密钥服务。
+	logger        *core.ZapLogger      // logger: 日志记录器。
+}
+
+// NewAPIKeyController 创建一个新的 APIKeyController 实例。
+func NewAPIKeyController(apiKeyService apikey.APIKeyService, logger *core.ZapLogger) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+// requireInteractiveCaller 获取当前用户 ID，并拒绝通过 API 密钥认证的调用，避免泄露的密钥被用来签发或删除其他密钥。
+// 返回 false 时已写入错误响应。
+func (ctrl *APIKeyController) requireInteractiveCaller(c *gin.Context, operation string) (string, bool) {
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于管理 API 密钥", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return "", false
+	}
+	if _, viaAPIKey := c.Get(constants.APIKeyIDContextKey); viaAPIKey {
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "不能使用 API 密钥管理 API 密钥，请登录后操作")
+		return "", false
+	}
+	return userID, true
+}
+
+// CreateAPIKeyHandler 处理创建 API 密钥的请求。
+// @Summary 创建 API 密钥
+// @Description 为当前用户创建一个 API 密钥，用于程序化访问 (请求头 X-API-Key)。响应中的 key 为密钥明文，只返回这一次，服务端仅保存其哈希。权限范围省略时为只读；每个用户最多 10 个密钥。不能使用 API 密钥调用此接口。
+// @Tags 账号管理 (Account Lifecycle)
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateAPIKeyDTO true "密钥名称、权限范围与有效天数"
+// @Success 200 {object} docs.SwaggerAPIAPIKeyCreatedResponse "创建成功，返回密钥明文"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效或密钥数量已达上限"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "通过 API 密钥调用"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/api-keys [post]
+func (ctrl *APIKeyController) CreateAPIKeyHandler(c *gin.Context) {
+	const operation = "APIKeyController.CreateAPIKeyHandler"
+
+	userID, ok := ctrl.requireInteractiveCaller(c, operation)
+	if !ok {
+		return
+	}
+	var req dto.CreateAPIKeyDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("创建 API 密钥请求参数无效", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	created, err := ctrl.apiKeyService.CreateAPIKey(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, apikey.ErrTooManyKeys) {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		} else {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		}
+		return
+	}
+	response.RespondSuccess(c, *created, "API 密钥创建成功，请妥善保存，之后无法再次查看")
+}
+
+// ListAPIKeysHandler 处理列出 API 密钥的请求。
+// @Summary 列出我的 API 密钥
+// @Description 返回当前用户全部 API 密钥的元数据 (名称、前缀、权限范围、最近使用时间、过期时间)，不含密钥明文。
+// @Tags 账号管理 (Account Lifecycle)
+// @Produce json
+// @Success 200 {object} docs.SwaggerAPIAPIKeyListResponse "获取成功"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/api-keys [get]
+func (ctrl *APIKeyController) ListAPIKeysHandler(c *gin.Context) {
+	const operation = "APIKeyController.ListAPIKeysHandler"
+
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于列出 API 密钥", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	keys, err := ctrl.apiKeyService.ListAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	response.RespondSuccess(c, keys, "获取 API 密钥列表成功")
+}
+
+// DeleteAPIKeyHandler 处理删除 API 密钥的请求。
+// @Summary 删除 API 密钥
+// @Description 删除当前用户的指定 API 密钥，删除后使用该密钥的请求立即失效。不能使用 API 密钥调用此接口。
+// @Tags 账号管理 (Account Lifecycle)
+// @Produce json
+// @Param id path uint true "API 密钥 ID"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "删除成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "密钥 ID 格式无效"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "通过 API 密钥调用"
+// @Failure 404 {object} docs.SwaggerAPIErrorResponseString "密钥不存在"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/api-keys/{id} [delete]
+func (ctrl *APIKeyController) DeleteAPIKeyHandler(c *gin.Context) {
+	const operation = "APIKeyController.DeleteAPIKeyHandler"
+
+	userID, ok := ctrl.requireInteractiveCaller(c, operation)
+	if !ok {
+		return
+	}
+	keyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "密钥 ID 格式无效")
+		return
+	}
+
+	if err := ctrl.apiKeyService.DeleteAPIKey(c.Request.Context(), userID, uint(keyID)); err != nil {
+		if errors.Is(err, apikey.ErrKeyNotFound) {
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+		} else {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		}
+		return
+	}
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "API 密钥已删除")
+}
+
+// RegisterRoutes 注册 API 密钥相关的路由到指定的 Gin 路由组。
+// 参数:
+//   - group: Gin 的路由组实例。
+func (ctrl *APIKeyController) RegisterRoutes(group *gin.RouterGroup) {
+	// 创建 API 密钥
+	// - 场景: 用户为脚本或 CI 申请程序化访问凭证。
+	// - 预期权限: 需要登录认证 (不接受 API 密钥)，只能为自己创建。
+	group.POST("/account/api-keys", ctrl.CreateAPIKeyHandler)
+
+	// 列出 API 密钥
+	// - 场景: 用户查看自己的密钥及其最近使用情况。
+	// - 预期权限: 需要认证，只能查看自己的密钥。
+	group.GET("/account/api-keys", ctrl.ListAPIKeysHandler)
+
+	// 删除 API 密钥
+	// - 场景: 密钥泄露或不再使用时吊销。
+	// - 预期权限: 需要登录认证 (不接受 API 密钥)，只能删除自己的密钥。
+	group.DELETE("/account/api-keys/:id", ctrl.DeleteAPIKeyHandler)
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	service "github.com/Xushengqwer/user_hub/service/audit"
+	"github.com/Xushengqwer/user_hub/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AuditController 处理管理员操作审计日志相关的 HTTP 请求。
+type AuditController struct {
+	auditService service.AdminAuditService // auditService: 审计日志查询服务的实例。
+	logger       *core.ZapLogger           // logger: 日志记录器。
+}
+
+// NewAuditController 创建一个新的 AuditController 实例。
+// 参数:
+//   - auditService: 实现了 service.AdminAuditService 接口的服务实例。
+//   - logger: 日志记录器实例。
+//
+// 返回:
+//   - *AuditController: 初始化完成的控制器实例。
+func NewAuditController(auditService service.AdminAuditService, logger *core.ZapLogger) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// ListAuditLogsHandler 处理分页查询管理员审计日志的请求。
+// @Summary 查询审计日志 (管理员)
+// @Description 管理员按操作者、操作目标、操作类型和时间范围分页查询审计日志，结果按记录时间倒序排列。
+// @Description 时间跨度最多 90 天，未指定起始时间时默认查询结束时间之前 90 天。响应中的 next_cursor 可作为 cursor 参数继续翻页 (推荐，深分页时比 page 更高效)。
+// @Tags 审计日志 (Audit Log)
+// @Produce json
+// @Param actor_id query string false "操作者用户ID"
+// @Param target_id query string false "操作目标ID"
+// @Param action query string false "操作类型 (如 user.create, user.update, user.blacklist, user.delete)"
+// @Param start_time query string false "起始时间 (含)，RFC3339 格式"
+// @Param end_time query string false "结束时间 (不含)，RFC3339 格式"
+// @Param page query int false "页码，默认 1"
+// @Param page_size query int false "每页大小，默认 10，最大 100"
+// @Param cursor query string false "上一页响应中的 next_cursor；携带时忽略 page"
+// @Success 200 {object} docs.SwaggerAPIAuditLogListResponse "查询成功，返回审计日志列表和总记录数"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如时间格式错误、时间跨度超过 90 天、游标无效、分页参数超出范围)"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
+// @Router /api/v1/user-hub/audit [get]
+func (ctrl *AuditController) ListAuditLogsHandler(c *gin.Context) {
+	const operation = "AuditController.ListAuditLogsHandler"
+
+	// 1. 审计日志仅对管理员开放
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试查询审计日志", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可查询审计日志")
+		return
+	}
+
+	// 2. 绑定并校验查询参数
+	var queryDTO dto.AuditLogQueryDTO
+	if err := c.ShouldBindQuery(&queryDTO); err != nil {
+		ctrl.logger.Warn("查询审计日志请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+	if queryDTO.StartTime != nil && queryDTO.EndTime != nil && !queryDTO.EndTime.After(*queryDTO.StartTime) {
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "结束时间必须晚于起始时间")
+		return
+	}
+
+	// 3. 调用服务层查询
+	result, err := ctrl.auditService.ListAuditLogs(c.Request.Context(), &queryDTO)
+	if err != nil {
+		if errors.Is(err, service.ErrAuditRangeTooLarge) || errors.Is(err, utils.ErrInvalidCursor) {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		} else if errors.Is(err, commonerrors.ErrSystemError) {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else {
+			ctrl.logger.Error("查询审计日志服务返回未知错误", zap.String("operation", operation), zap.Error(err))
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, "查询审计日志时发生未知错误")
+		}
+		return
+	}
+
+	// 4. 返回成功响应
+	ctrl.logger.Info("成功查询审计日志",
+		zap.String("operation", operation),
+		zap.Int64("totalRecords", result.Total),
+		zap.Int("returnedRecords", len(result.Items)),
+	)
+	response.RespondSuccess(c, *result, "查询成功")
+}
+
+// RegisterRoutes 注册审计日志相关的路由到指定的 Gin 路由组。
+// 参数:
+//   - group: Gin 的路由组实例。
+func (ctrl *AuditController) RegisterRoutes(group *gin.RouterGroup) {
+	// 查询审计日志
+	// - 场景: 管理员后台追溯用户创建、修改、拉黑、删除等操作。
+	// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内会再次校验角色。
+	group.GET("/audit", ctrl.ListAuditLogsHandler)
+}
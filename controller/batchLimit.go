@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// checkBatchSize 按批量接口的统一配置校验本次提交的条目数。
+// 超出上限时写入 400 响应 (提示允许的最大值) 并返回 false，调用方应直接返回。
+func checkBatchSize(c *gin.Context, limits config.BatchLimitConfig, op string, size int) bool {
+	limit := limits.MaxOrDefault(op)
+	if size <= limit {
+		return true
+	}
+	response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput,
+		fmt.Sprintf("单次最多提交 %d 项，本次提交了 %d 项", limit, size))
+	return false
+}
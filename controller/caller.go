@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/Xushengqwer/go-common/constants"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/service/identity"
+)
+
+// getCallerUserID 从 Gin Context 中读取网关透传的当前用户 ID (X-User-ID)。
+// 返回的 bool 表示上下文中是否存在有效 (非空) 的用户 ID。
+func getCallerUserID(c *gin.Context) (string, bool) {
+	userIDRaw, exists := c.Get(string(constants.UserIDKey))
+	if !exists {
+		return "", false
+	}
+	userID, ok := userIDRaw.(string)
+	if !ok || userID == "" {
+		return "", false
+	}
+	return userID, true
+}
+
+// getCallerRole 从 Gin Context 中读取网关透传的调用者角色 (X-User-Role)。
+// 网关可能传入角色名称 ("admin") 或枚举数值 ("0")，两种格式都予以支持。
+// 返回的 bool 表示是否成功解析出角色。
+func getCallerRole(c *gin.Context) (enums.UserRole, bool) {
+	roleRaw, exists := c.Get(string(constants.RoleKey))
+	if !exists {
+		return enums.RoleGuest, false
+	}
+	roleStr, ok := roleRaw.(string)
+	if !ok || roleStr == "" {
+		return enums.RoleGuest, false
+	}
+	if role, err := enums.RoleFromString(roleStr); err == nil {
+		return role, true
+	}
+	if n, err := strconv.ParseUint(roleStr, 10, 32); err == nil {
+		return enums.UserRole(n), true
+	}
+	return enums.RoleGuest, false
+}
+
+// isAdminCaller 判断当前调用者是否为管理员
+func isAdminCaller(c *gin.Context) bool {
+	role, ok := getCallerRole(c)
+	return ok && role == enums.RoleAdmin
+}
+
+// identityCaller 组装身份服务归属校验所需的调用者信息。
+// 返回的 bool 为 false 表示既没有用户 ID 也不是管理员，调用方应返回 401。
+func identityCaller(c *gin.Context) (identity.Caller, bool) {
+	userID, hasUser := getCallerUserID(c)
+	isAdmin := isAdminCaller(c)
+	return identity.Caller{UserID: userID, IsAdmin: isAdmin}, hasUser || isAdmin
+}
@@ -1,15 +1,20 @@
 package controller
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/Xushengqwer/go-common/commonerrors" // 引入公共错误包
 	"github.com/Xushengqwer/go-common/core"         // 引入日志包
 	"github.com/Xushengqwer/go-common/response"
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/sms"
 	"github.com/Xushengqwer/user_hub/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
@@ -18,36 +23,60 @@ import (
 // AuthController 处理与认证辅助功能相关的 HTTP 请求，例如发送验证码。
 // 注意：登录、注册、登出、刷新令牌等核心认证流程由其他控制器（如 AccountController, TokenController）处理。
 type AuthController struct {
-	smsClient dependencies.SMSClient // smsClient: 短信服务客户端，用于实际发送短信。
-	codeRepo  redis.CodeRepo         // codeRepo: Redis 验证码仓库，用于存储和验证验证码。
-	logger    *core.ZapLogger        // logger: 日志记录器。
+	smsSender sms.CaptchaSender              // smsSender: 验证码短信发送器，失败时自动重试并记录死信。
+	codeRepo  redis.CodeRepo                 // codeRepo: Redis 验证码仓库，用于存储和验证验证码。
+	smsConfig config.SMSConfig               // smsConfig: 短信配置，提供发送冷却时间与每日上限。
+	degrade   *dependencies.RedisDegradation // degrade: Redis 出错时发送额度检查是否放行。
+	debugCode config.CaptchaDebugConfig      // debugCode: 测试手机号的固定验证码，仅在 Active 时使用。
+	logger    *core.ZapLogger                // logger: 日志记录器。
 }
 
+// captchaExpire 验证码在 Redis 中的有效期。
+const captchaExpire = 5 * time.Minute
+
+// captchaResendMessage 重发接口的统一响应文案，无论手机号是否存在待验证的验证码都返回相同内容，避免被用于探测。
+const captchaResendMessage = "如果该手机号存在待完成的验证，新的验证码已发送，请注意查收"
+
 // NewAuthController 创建一个新的 AuthController 实例。
 // 设计目的:
 //   - 通过依赖注入传入所需的服务和仓库实例，以及日志记录器。
 //
 // 参数:
-//   - smsClient: 实现了 dependencies.SMSClient 接口的短信服务实例。
+//   - smsSender: 实现了 sms.CaptchaSender 接口的验证码短信发送器。
 //   - codeRepo: 实现了 redis.CodeRepo 接口的验证码仓库实例。
+//   - smsCfg: 短信配置 (发送冷却时间、每日上限)。
 //   - logger: 日志记录器实例。
 //
 // 返回:
 //   - *AuthController: 初始化完成的控制器实例。
 func NewAuthController(
-	smsClient dependencies.SMSClient,
+	smsSender sms.CaptchaSender,
 	codeRepo redis.CodeRepo,
+	smsCfg config.SMSConfig,
+	degrade *dependencies.RedisDegradation,
+	debugCode config.CaptchaDebugConfig,
 	logger *core.ZapLogger, // 注入 logger
 ) *AuthController {
+	if debugCode.Active() {
+		logger.Error("!!! 验证码调试固定码已启用：测试手机号不发送短信且验证码固定，切勿在生产环境使用 !!!",
+			zap.String("phonePrefix", debugCode.PhonePrefix),
+			zap.String("envVar", config.CaptchaDebugEnvVar),
+		)
+	} else if debugCode.Enabled {
+		logger.Warn("验证码调试固定码已配置但未生效 (需要有效的前缀、6 位数字验证码以及环境变量)", zap.String("envVar", config.CaptchaDebugEnvVar))
+	}
 	return &AuthController{
-		smsClient: smsClient,
+		smsSender: smsSender,
 		codeRepo:  codeRepo,
+		smsConfig: smsCfg,
+		degrade:   degrade,
+		debugCode: debugCode,
 		logger:    logger, // 存储 logger
 	}
 }
 
 // SendCaptcha 处理发送短信验证码的请求。
-// 流程: 校验手机号 -> 生成验证码 -> 调用短信服务发送 -> 将验证码存入 Redis (设置过期时间)。
+// 流程: 校验手机号 -> 生成验证码 -> 将验证码存入 Redis (设置过期时间) -> 调用短信服务发送 (失败自动重试)。
 // @Summary 发送短信验证码
 // @Description 向用户指定的手机号发送一个6位随机数字验证码，该验证码在5分钟内有效。
 // @Tags 认证辅助 (Auth Helper)
@@ -56,6 +85,7 @@ func NewAuthController(
 // @Param request body dto.SendCaptchaRequest true "请求体，包含目标手机号"
 // @Success 200 {object} docs.SwaggerAPIEmptyResponse "验证码发送成功（响应体中不包含验证码）"
 // @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、手机号格式不正确)"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "发送过于频繁、已达每日发送上限或全局发送总量已达上限"
 // @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如短信服务发送失败、Redis存储失败)"
 // @Router /api/v1/user-hub/auth/send-captcha [post] // <--- 已更新路径
 func (ctrl *AuthController) SendCaptcha(c *gin.Context) {
@@ -74,31 +104,23 @@ func (ctrl *AuthController) SendCaptcha(c *gin.Context) {
 	// TODO: 可以在DTO的binding标签或此处添加更严格的手机号格式校验逻辑，
 	//       例如使用自定义validator。当前假设dto.SendCaptchaRequest已有基础校验。
 
-	// 2. 生成6位随机验证码。
-	captcha := utils.GenerateCaptcha()
+	// 1.1 检查并预占发送额度 (冷却期与每日上限，与重发接口共用)。
+	if !ctrl.reserveSend(c, operation, req.Phone) {
+		return
+	}
+
+	// 2. 生成6位随机验证码 (测试手机号使用调试固定码)。
+	captcha := ctrl.generateCaptcha(operation, req.Phone)
 	ctrl.logger.Info("已生成验证码",
 		zap.String("operation", operation),
 		zap.String("phone", req.Phone), // 注意：生产环境日志中手机号可能需要脱敏
 		// 不记录验证码本身到常规日志，除非是调试模式下的特定日志级别
 	)
 
-	// 3. 调用短信服务发送验证码。
-	//    此处假设短信服务本身会处理发送频率限制等问题。
-	if err := ctrl.smsClient.SendCode(c.Request.Context(), req.Phone, captcha); err != nil {
-		ctrl.logger.Error("调用短信服务发送验证码失败",
-			zap.String("operation", operation),
-			zap.String("phone", req.Phone),
-			zap.Error(err), // 记录短信服务返回的原始错误
-		)
-		// 短信发送失败是系统层面问题，返回通用系统错误。
-		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
-		return
-	}
-	ctrl.logger.Info("短信服务发送验证码成功", zap.String("operation", operation), zap.String("phone", req.Phone))
-
-	// 4. 在 Redis 中存储验证码，并设置5分钟过期时间。
-	//    这是为了后续用户使用验证码登录/注册时进行校验。
-	expire := 5 * time.Minute
+	// 3. 先在 Redis 中存储验证码，并设置5分钟过期时间。
+	//    先存后发：即使短信最终发送失败，用户也可以通过重发接口重新获取，而不必重新发起流程；
+	//    异步发送模式也依赖验证码在发送前已保存。
+	expire := captchaExpire
 	if err := ctrl.codeRepo.SetCaptcha(c.Request.Context(), req.Phone, captcha, expire); err != nil {
 		ctrl.logger.Error("将验证码存入 Redis 失败",
 			zap.String("operation", operation),
@@ -115,11 +137,161 @@ func (ctrl *AuthController) SendCaptcha(c *gin.Context) {
 		zap.Duration("expire", expire),
 	)
 
+	// 4. 调用短信发送器发送验证码 (失败时按配置重试，重试耗尽后写入死信表)。
+	//    发送频率已在步骤 1.1 中按手机号限制。
+	if err := ctrl.sendCaptcha(c.Request.Context(), req.Phone, captcha); err != nil {
+		ctrl.logger.Error("调用短信服务发送验证码失败",
+			zap.String("operation", operation),
+			zap.String("phone", req.Phone),
+			zap.Error(err),
+		)
+		// 短信发送失败是系统层面问题，返回通用系统错误 (有权查看时附带上游错误摘要)。
+		respondUpstreamError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error(), err)
+		return
+	}
+	ctrl.logger.Info("短信验证码已发送或已提交后台发送", zap.String("operation", operation), zap.String("phone", req.Phone))
+
 	// 5. 返回成功响应。
 	//    响应体中不应包含验证码本身，以确保安全。
 	response.RespondSuccess[interface{}](c, nil, "验证码发送成功，请注意查收")
 }
 
+// ResendCode 处理重新发送短信验证码的请求。
+// 流程: 校验手机号 -> 检查发送额度 -> 若该手机号存在未使用的验证码则重新生成并发送，否则不发送。
+// @Summary 重新发送短信验证码
+// @Description 验证码丢失时重新发送。仅当该手机号存在待完成的验证 (已发送且未使用、未过期的验证码) 时才会重新发送，与首次发送共用冷却时间和每日上限。无论是否实际发送，成功响应的文案都相同，避免被用于探测手机号。
+// @Tags 认证辅助 (Auth Helper)
+// @Accept json
+// @Produce json
+// @Param request body dto.ResendCodeRequest true "请求体，包含目标手机号"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "请求已受理（响应体中不包含验证码）"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、手机号格式不正确)"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "发送过于频繁、已达每日发送上限或全局发送总量已达上限"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如短信服务发送失败、Redis存储失败)"
+// @Router /api/v1/user-hub/auth/resend-code [post]
+func (ctrl *AuthController) ResendCode(c *gin.Context) {
+	const operation = "AuthController.ResendCode"
+
+	// 1. 绑定并校验请求体数据。
+	var req dto.ResendCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("重发验证码请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "无效的输入参数")
+		return
+	}
+
+	// 2. 先检查发送额度：无论是否存在待验证的验证码都同样计入，使限流响应不泄露手机号状态。
+	if !ctrl.reserveSend(c, operation, req.Phone) {
+		return
+	}
+
+	// 3. 仅当存在未使用的验证码时才重新发送。
+	if _, err := ctrl.codeRepo.GetCaptcha(c.Request.Context(), req.Phone); err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			ctrl.logger.Info("手机号没有待完成的验证，忽略重发请求", zap.String("operation", operation), zap.String("phone", req.Phone))
+			response.RespondSuccess[interface{}](c, nil, captchaResendMessage)
+			return
+		}
+		ctrl.logger.Error("查询待验证的验证码失败", zap.String("operation", operation), zap.String("phone", req.Phone), zap.Error(err))
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+
+	// 4. 生成新验证码，先覆盖旧验证码 (旧验证码随之失效) 再发送。
+	captcha := ctrl.generateCaptcha(operation, req.Phone)
+	if err := ctrl.codeRepo.SetCaptcha(c.Request.Context(), req.Phone, captcha, captchaExpire); err != nil {
+		ctrl.logger.Error("将重发的验证码存入 Redis 失败", zap.String("operation", operation), zap.String("phone", req.Phone), zap.Error(err))
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	if err := ctrl.sendCaptcha(c.Request.Context(), req.Phone, captcha); err != nil {
+		ctrl.logger.Error("调用短信服务重发验证码失败", zap.String("operation", operation), zap.String("phone", req.Phone), zap.Error(err))
+		respondUpstreamError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error(), err)
+		return
+	}
+
+	ctrl.logger.Info("验证码重发成功", zap.String("operation", operation), zap.String("phone", req.Phone))
+	response.RespondSuccess[interface{}](c, nil, captchaResendMessage)
+}
+
+// generateCaptcha 生成验证码；调试固定码生效且手机号匹配测试前缀时返回固定码并记录告警日志
+func (ctrl *AuthController) generateCaptcha(operation, phone string) string {
+	if ctrl.debugCode.Active() && ctrl.debugCode.Matches(phone) {
+		ctrl.logger.Warn("测试手机号使用验证码调试固定码", zap.String("operation", operation), zap.String("phone", phone))
+		return ctrl.debugCode.Code
+	}
+	return utils.GenerateCaptcha()
+}
+
+// sendCaptcha 发送验证码短信；使用调试固定码的测试手机号不调用短信服务
+func (ctrl *AuthController) sendCaptcha(ctx context.Context, phone, captcha string) error {
+	if ctrl.debugCode.Active() && ctrl.debugCode.Matches(phone) {
+		return nil
+	}
+	return ctrl.smsSender.Send(ctx, phone, captcha)
+}
+
+// reserveSend 按手机号预占一次验证码发送额度，首次发送与重发共用同一套冷却期和每日上限；按手机号通过后再检查全局发送总量。
+// 额度检查成功时 (无论是否通过) 都输出 X-RateLimit-* 响应头，额度不足或 Redis 出错时直接写入错误响应并返回 false。
+func (ctrl *AuthController) reserveSend(c *gin.Context, operation, phone string) bool {
+	quota, err := ctrl.codeRepo.ReserveSend(c.Request.Context(), phone, ctrl.smsConfig.CooldownOrDefault(), ctrl.smsConfig.DailyLimitOrDefault())
+	if err == nil || errors.Is(err, redis.ErrCaptchaCooldown) || errors.Is(err, redis.ErrCaptchaDailyLimit) {
+		setRateLimitHeaders(c, quota.Limit, quota.Remaining, quota.Reset)
+	}
+	if err == nil {
+		return ctrl.reserveGlobalSend(c, operation, phone)
+	}
+
+	seconds := ceilSeconds(quota.Wait)
+	switch {
+	case errors.Is(err, redis.ErrCaptchaCooldown):
+		ctrl.logger.Warn("验证码发送处于冷却期", zap.String("operation", operation), zap.String("phone", phone), zap.Duration("wait", quota.Wait))
+		setRetryAfter(c, quota.Wait)
+		response.RespondError(c, http.StatusTooManyRequests, response.ErrCodeClientRateLimitExceeded, fmt.Sprintf("验证码发送过于频繁，请 %d 秒后重试", seconds))
+	case errors.Is(err, redis.ErrCaptchaDailyLimit):
+		ctrl.logger.Warn("验证码发送已达每日上限", zap.String("operation", operation), zap.String("phone", phone))
+		setRetryAfter(c, quota.Wait)
+		response.RespondError(c, http.StatusTooManyRequests, response.ErrCodeClientRateLimitExceeded, "今日验证码发送次数已达上限，请明天再试")
+	case ctrl.degrade.AllowOnFailure(dependencies.RedisFeatureCaptchaSendQuota, err):
+		// Redis 不可用且允许降级：跳过按手机号的额度检查，仍检查全局额度
+		return ctrl.reserveGlobalSend(c, operation, phone)
+	default:
+		ctrl.logger.Error("检查验证码发送额度失败", zap.String("operation", operation), zap.String("phone", phone), zap.Error(err))
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+	}
+	return false
+}
+
+// reserveGlobalSend 预占一次全局 (所有手机号合计) 验证码发送额度，防止轮换大量手机号消耗短信额度。
+// - 触发全局上限通常意味着滥用，按 Error 级别记录以便告警；此时该手机号已开始的冷却期不退还。
+// - 额度不足或 Redis 出错时直接写入错误响应并返回 false。
+func (ctrl *AuthController) reserveGlobalSend(c *gin.Context, operation, phone string) bool {
+	perMinute, perHour := ctrl.smsConfig.GlobalPerMinuteOrDefault(), ctrl.smsConfig.GlobalPerHourOrDefault()
+	wait, err := ctrl.codeRepo.ReserveGlobalSend(c.Request.Context(), perMinute, perHour)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, redis.ErrCaptchaGlobalLimit) {
+		ctrl.logger.Error("验证码全局发送总量已达上限，可能存在批量刷短信行为",
+			zap.String("operation", operation),
+			zap.String("phone", phone),
+			zap.Int("globalPerMinute", perMinute),
+			zap.Int("globalPerHour", perHour),
+			zap.Duration("wait", wait),
+		)
+		setRetryAfter(c, wait)
+		response.RespondError(c, http.StatusTooManyRequests, response.ErrCodeClientRateLimitExceeded, "验证码发送繁忙，请稍后重试")
+		return false
+	}
+	if ctrl.degrade.AllowOnFailure(dependencies.RedisFeatureCaptchaGlobalQuota, err) {
+		return true
+	}
+	ctrl.logger.Error("检查全局验证码发送额度失败", zap.String("operation", operation), zap.Error(err))
+	response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+	return false
+}
+
 // RegisterRoutes 注册与认证辅助功能相关的路由到指定的 Gin 路由组。
 // 设计目的:
 //   - 集中管理此控制器的路由。
@@ -138,6 +310,10 @@ func (ctrl *AuthController) RegisterRoutes(group *gin.RouterGroup) {
 		// - 方法: POST
 		// - 此接口通常不需要用户认证即可访问。
 		authRoutes.POST("/send-captcha", ctrl.SendCaptcha)
+
+		// 重新发送验证码 (与发送共用冷却期和每日上限)
+		// - 场景: 用户未收到或丢失验证码。
+		authRoutes.POST("/resend-code", ctrl.ResendCode)
 	}
 	// 注意：核心的登录、注册、登出、刷新令牌等接口通常在其他专门的控制器中定义和注册。
 }
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// ConfigController 处理运行时配置预览相关的 HTTP 请求。
+type ConfigController struct {
+	redacted config.UserHubConfig // redacted: 启动时加载并已隐藏敏感字段的配置副本。
+	logger   *core.ZapLogger      // logger: 日志记录器。
+}
+
+// NewConfigController 创建一个新的 ConfigController 实例。
+// 参数:
+//   - cfg: 应用的全局配置，构造时即生成隐藏敏感字段的副本，控制器不持有原始密钥。
+//   - logger: 日志记录器实例。
+//
+// 返回:
+//   - *ConfigController: 初始化完成的控制器实例。
+func NewConfigController(cfg *config.UserHubConfig, logger *core.ZapLogger) *ConfigController {
+	return &ConfigController{
+		redacted: cfg.Redacted(),
+		logger:   logger,
+	}
+}
+
+// GetEffectiveConfigHandler 处理管理员预览生效配置的请求。
+// @Summary 预览生效配置 (管理员)
+// @Description 返回服务启动时加载的完整配置，数据库 DSN 密码、JWT 密钥、Redis 密码、COS/CDN/短信/微信密钥、凭证加密密钥等敏感字段以 **** 替代 (未配置的保持为空)，便于运维在无 Shell 权限时核对配置。功能开关支持热重载，其当前值以功能开关接口为准。
+// @Tags 运维 (Operations)
+// @Produce json
+// @Success 200 {object} docs.SwaggerAPIConfigResponse "获取成功，返回隐藏敏感字段后的配置"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Router /api/v1/user-hub/admin/config [get]
+func (ctrl *ConfigController) GetEffectiveConfigHandler(c *gin.Context) {
+	const operation = "ConfigController.GetEffectiveConfigHandler"
+
+	// 1. 配置预览仅对管理员开放
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试预览生效配置", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可查看服务配置")
+		return
+	}
+
+	// 2. 返回隐藏敏感字段后的配置副本
+	actorID, _ := getCallerUserID(c)
+	ctrl.logger.Info("管理员预览生效配置", zap.String("operation", operation), zap.String("actorID", actorID))
+	response.RespondSuccess(c, ctrl.redacted, "获取配置成功")
+}
+
+// RegisterRoutes 注册配置预览相关的路由到指定的 Gin 路由组。
+// 参数:
+//   - group: Gin 的路由组实例。
+func (ctrl *ConfigController) RegisterRoutes(group *gin.RouterGroup) {
+	// 生效配置预览
+	// - 场景: 运维核对部署后的配置是否符合预期，无需登录服务器查看配置文件。
+	// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内会校验角色。
+	group.GET("/admin/config", ctrl.GetEffectiveConfigHandler)
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/service/consistency"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ConsistencyController 处理数据一致性巡检相关的 HTTP 请求。
+type ConsistencyController struct {
+	consistencyService consistency.DataConsistencyService // consistencyService: 数据一致性巡检服务的实例。
+	logger             *core.ZapLogger                    // logger: 日志记录器。
+}
+
+// NewConsistencyController 创建一个新的 ConsistencyController 实例。
+// 参数:
+//   - consistencyService: 实现了 consistency.DataConsistencyService 接口的服务实例。
+//   - logger: 日志记录器实例。
+//
+// 返回:
+//   - *ConsistencyController: 初始化完成的控制器实例。
+func NewConsistencyController(consistencyService consistency.DataConsistencyService, logger *core.ZapLogger) *ConsistencyController {
+	return &ConsistencyController{
+		consistencyService: consistencyService,
+		logger:             logger,
+	}
+}
+
+// ScanHandler 处理管理员发起的数据一致性巡检请求。
+// @Summary 数据一致性巡检 (管理员)
+// @Description 分批扫描缺少资料的用户、关联用户已不存在的孤儿资料与孤儿身份，返回巡检报告。repair 为 true 时补建缺失资料并删除孤儿记录，修复操作写入审计日志。
+// @Tags 数据一致性
+// @Accept json
+// @Produce json
+// @Param body body dto.ConsistencyScanDTO false "巡检参数 (是否修复、批次大小、每类上限)"
+// @Success 200 {object} docs.SwaggerAPIConsistencyReportResponse "巡检完成，返回报告"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询或修复失败)"
+// @Router /api/v1/user-hub/admin/consistency/scan [post]
+func (ctrl *ConsistencyController) ScanHandler(c *gin.Context) {
+	const operation = "ConsistencyController.ScanHandler"
+
+	// 1. 巡检与修复仅对管理员开放
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试执行数据一致性巡检", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可执行数据一致性巡检")
+		return
+	}
+	actorID, _ := getCallerUserID(c)
+
+	// 2. 绑定请求体，允许空请求体 (使用默认参数、仅扫描不修复)
+	var scanData dto.ConsistencyScanDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&scanData); err != nil {
+			ctrl.logger.Warn("数据一致性巡检请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+			return
+		}
+	}
+
+	// 3. 调用服务层执行巡检
+	report, err := ctrl.consistencyService.Scan(c.Request.Context(), actorID, scanData)
+	if err != nil {
+		ctrl.logger.Error("数据一致性巡检服务返回错误",
+			zap.String("operation", operation),
+			zap.String("actorID", actorID),
+			zap.Bool("repair", scanData.Repair),
+			zap.Error(err),
+		)
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+
+	// 4. 返回巡检报告
+	response.RespondSuccess(c, *report, "巡检完成")
+}
+
+// RegisterRoutes 注册数据一致性巡检相关的路由到指定的 Gin 路由组。
+// 参数:
+//   - group: Gin 的路由组实例。
+func (ctrl *ConsistencyController) RegisterRoutes(group *gin.RouterGroup) {
+	// 数据一致性巡检
+	// - 场景: 运维排查并修复用户、资料、身份三张表之间的数据漂移。
+	// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内会再次校验角色。
+	group.POST("/admin/consistency/scan", ctrl.ScanHandler)
+}
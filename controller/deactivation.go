@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/deactivation"
+)
+
+// AccountDeactivationController 处理用户自助停用/重新激活账号的 HTTP 请求。
+type AccountDeactivationController struct {
+	deactivationService deactivation.AccountDeactivationService // deactivationService: 账号停用服务。
+	jwtUtil             dependencies.JWTTokenInterface          // jwtUtil: 用于获取平台对应的刷新令牌有效期。
+	logger              *core.ZapLogger                         // logger: 日志记录器。
+	cookieConfig        config.CookieConfig                     // cookieConfig: Web 平台刷新令牌 Cookie 配置。
+}
+
+// NewAccountDeactivationController 创建一个新的 AccountDeactivationController 实例。
+func NewAccountDeactivationController(
+	deactivationService deactivation.AccountDeactivationService,
+	jwtUtil dependencies.JWTTokenInterface,
+	logger *core.ZapLogger,
+	cookieCfg config.CookieConfig,
+) *AccountDeactivationController {
+	return &AccountDeactivationController{
+		deactivationService: deactivationService,
+		jwtUtil:             jwtUtil,
+		logger:              logger,
+		cookieConfig:        cookieCfg,
+	}
+}
+
+// DeactivateHandler 处理当前用户停用自己账号的请求。
+// @Summary 停用我的账号
+// @Description 当前认证用户临时停用自己的账号（可逆，不删除任何数据）。停用后所有登录方式都会返回"账号已停用"及重新激活凭证，已签发的刷新令牌无法再续期；本次请求携带的令牌会被立即吊销。
+// @Tags 账号管理 (Account Lifecycle)
+// @Accept json
+// @Produce json
+// @Param Authorization header string false "Bearer <当前 Access Token>"
+// @Param request body dto.RefreshTokenRequest false "非 Web 平台可在请求体中提交当前的 refresh_token 以便立即吊销"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "账号已停用"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "账号已处于停用状态或当前状态不允许停用"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 404 {object} docs.SwaggerAPIErrorResponseString "用户不存在"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/deactivate [post]
+func (ctrl *AccountDeactivationController) DeactivateHandler(c *gin.Context) {
+	const operation = "AccountDeactivationController.DeactivateHandler"
+
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于停用账号", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	// 1. 收集本次请求携带的令牌 (Authorization 头、RT Cookie、请求体中的 RT)，停用后立即吊销
+	var tokensToRevoke []string
+	if bearer, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); found && bearer != "" {
+		tokensToRevoke = append(tokensToRevoke, bearer)
+	}
+	platform, _ := enums.PlatformFromString(c.GetHeader("X-Platform"))
+	if platform == enums.PlatformWeb {
+		if cookieRT, err := c.Cookie(ctrl.cookieConfig.RefreshTokenName); err == nil && cookieRT != "" {
+			tokensToRevoke = append(tokensToRevoke, cookieRT)
+		}
+	} else {
+		var req dto.RefreshTokenRequest
+		if err := c.ShouldBindJSON(&req); err == nil && req.RefreshToken != "" {
+			tokensToRevoke = append(tokensToRevoke, req.RefreshToken)
+		}
+	}
+
+	// 2. 调用服务层停用账号
+	if err := ctrl.deactivationService.Deactivate(c.Request.Context(), userID, tokensToRevoke); err != nil {
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else if strings.Contains(err.Error(), "不存在") {
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+		} else {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		}
+		return
+	}
+
+	// 3. Web 平台同时清除 RT Cookie
+	if platform == enums.PlatformWeb {
+		clearRefreshTokenCookie(c, ctrl.cookieConfig)
+	}
+
+	ctrl.logger.Info("用户已停用账号", zap.String("operation", operation), zap.String("userID", userID))
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "账号已停用")
+}
+
+// ReactivateHandler 处理使用重新激活凭证恢复已停用账号的请求。
+// @Summary 重新激活我的账号
+// @Description 使用登录已停用账号时返回的一次性凭证恢复账号，成功后直接返回与登录接口一致的令牌（Web 平台 RT 写入 Cookie）。
+// @Tags 账号管理 (Account Lifecycle)
+// @Accept json
+// @Produce json
+// @Param body body dto.ReactivateAccountDTO true "重新激活凭证"
+// @Param X-Platform header string true "客户端平台类型" Enums(web, wechat, app) default(web)
+// @Success 200 {object} docs.SwaggerAPILoginResponse "重新激活成功，返回用户信息及令牌"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "凭证无效或已过期、账号未处于停用状态、平台类型无效"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/reactivate [post]
+func (ctrl *AccountDeactivationController) ReactivateHandler(c *gin.Context) {
+	const operation = "AccountDeactivationController.ReactivateHandler"
+
+	var req dto.ReactivateAccountDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("重新激活请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请提供重新激活凭证")
+		return
+	}
+	platformStr := c.GetHeader("X-Platform")
+	platform, err := enums.PlatformFromString(platformStr)
+	if err != nil {
+		ctrl.logger.Warn("无效的平台类型", zap.String("operation", operation), zap.String("platformHeader", platformStr), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "无效的平台类型")
+		return
+	}
+
+	userInfo, tokenPair, err := ctrl.deactivationService.Reactivate(c.Request.Context(), req.ReactivationTicket, platform)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		}
+		return
+	}
+
+	// 与登录接口一致：Web 平台 RT 写入 HttpOnly Cookie，其他平台 AT/RT 都在 JSON 中
+	deliverRefreshToken(c, ctrl.cookieConfig, platform, &tokenPair, ctrl.jwtUtil.RefreshTokenTTL(platform))
+	if !guardWebRefreshToken(c, ctrl.cookieConfig, platform, &tokenPair, ctrl.logger, operation) {
+		return
+	}
+
+	ctrl.logger.Info("用户已重新激活账号", zap.String("operation", operation), zap.String("userID", userInfo.UserID), zap.Any("platform", platform))
+	response.RespondSuccess(c, vo.LoginResponse{User: userInfo, Token: tokenPair}, "账号已重新激活")
+}
+
+// respondIfAccountDeactivated 供各登录接口使用：若服务层返回账号已停用错误，
+// 以 403 响应"账号已停用"并附带重新激活凭证与接口路径，返回 true 表示已写入响应。
+func respondIfAccountDeactivated(c *gin.Context, err error) bool {
+	var deactivatedErr *deactivation.AccountDeactivatedError
+	if !errors.As(err, &deactivatedErr) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, response.APIResponse[vo.ReactivationChallengeVO]{
+		Code:    response.ErrCodeClientForbidden,
+		Message: deactivatedErr.Error(),
+		Data: vo.ReactivationChallengeVO{
+			Reason:             vo.LoginReasonAccountDeactivated,
+			ReactivationTicket: deactivatedErr.ReactivationTicket,
+			ExpiresIn:          int64(deactivatedErr.ExpiresIn.Seconds()),
+			ReactivatePath:     deactivation.ReactivatePath,
+		},
+	})
+	return true
+}
+
+// RegisterRoutes 注册账号停用/重新激活相关的路由。
+func (ctrl *AccountDeactivationController) RegisterRoutes(group *gin.RouterGroup) {
+	// 停用我的账号
+	// 场景：用户希望暂时离开，但保留全部数据
+	group.POST("/account/deactivate", ctrl.DeactivateHandler)
+
+	// 重新激活我的账号
+	// 场景：登录已停用账号时拿到重新激活凭证，凭此恢复账号（无需认证，凭证本身即身份证明）
+	group.POST("/account/reactivate", ctrl.ReactivateHandler)
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/service/file"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FileController 处理用户私有文件相关的 HTTP 请求。
+type FileController struct {
+	fileService file.FileService // fileService: 私有文件服务的实例。
+	logger      *core.ZapLogger  // logger: 日志记录器。
+}
+
+// NewFileController 创建一个新的 FileController 实例。
+// 参数:
+//   - fileService: 实现了 file.FileService 接口的服务实例。
+//   - logger: 日志记录器实例。
+//
+// 返回:
+//   - *FileController: 初始化完成的控制器实例。
+func NewFileController(fileService file.FileService, logger *core.ZapLogger) *FileController {
+	return &FileController{
+		fileService: fileService,
+		logger:      logger,
+	}
+}
+
+// GetDownloadURLHandler 为当前用户的私有文件生成临时下载链接。
+// @Summary 获取私有文件临时下载链接
+// @Description 为当前登录用户拥有的私有对象生成带签名的临时 GET 链接。对象键需整体 URL 编码 (如 documents%2F{userID}%2Fa.pdf)，且目录部分必须包含当前用户 ID。
+// @Tags 文件
+// @Produce json
+// @Param key path string true "URL 编码后的对象键"
+// @Param ttl query int false "链接有效秒数，省略时使用默认有效期，不得超过配置的最长有效期"
+// @Success 200 {object} docs.SwaggerAPIPresignedURLResponse "生成成功，返回临时下载链接及过期时间"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如有效期超出上限)"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "用户未认证"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "文件不属于当前用户"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如签名失败)"
+// @Router /api/v1/user-hub/files/{key}/url [get]
+func (ctrl *FileController) GetDownloadURLHandler(c *gin.Context) {
+	const operation = "FileController.GetDownloadURLHandler"
+
+	// 1. 获取当前用户
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Warn("无法从上下文中获取有效的UserID用于获取文件链接", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	// 2. 解析对象键与有效期
+	objectKey := c.Param("key")
+	var query dto.PresignFileURLDTO
+	if err := c.ShouldBindQuery(&query); err != nil || objectKey == "" {
+		ctrl.logger.Warn("获取文件链接请求参数无效", zap.String("operation", operation), zap.String("objectKey", objectKey), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	// 3. 调用服务层生成链接
+	result, err := ctrl.fileService.PresignDownloadURL(c.Request.Context(), userID, objectKey, query.TTL)
+	if err != nil {
+		switch {
+		case errors.Is(err, commonerrors.ErrSystemError):
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		case errors.Is(err, file.ErrFileAccessDenied):
+			response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, err.Error())
+		default:
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		}
+		return
+	}
+
+	response.RespondSuccess(c, *result, "获取下载链接成功")
+}
+
+// GetUsageHandler 返回当前用户的存储用量与配额。
+// @Summary 获取我的存储用量
+// @Description 返回当前登录用户已上传对象的累计大小、存储配额与剩余可用空间。
+// @Tags 文件
+// @Produce json
+// @Success 200 {object} docs.SwaggerAPIStorageUsageResponse "获取成功，返回存储用量"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "用户未认证"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
+// @Router /api/v1/user-hub/files/usage [get]
+func (ctrl *FileController) GetUsageHandler(c *gin.Context) {
+	const operation = "FileController.GetUsageHandler"
+
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Warn("无法从上下文中获取有效的UserID用于查询存储用量", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	usage, err := ctrl.fileService.GetUsage(c.Request.Context(), userID)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+
+	response.RespondSuccess(c, *usage, "获取存储用量成功")
+}
+
+// RegisterRoutes 注册文件相关的路由到指定的 Gin 路由组。
+// 参数:
+//   - group: Gin 的路由组实例。
+func (ctrl *FileController) RegisterRoutes(group *gin.RouterGroup) {
+	filesRoutes := group.Group("/files")
+	{
+		// 获取私有文件临时下载链接
+		// - 场景: 前端展示或下载用户上传的私有文档。
+		// - 预期权限: 需要认证，仅能获取自己目录下的对象。
+		// - 对象键作为单个路径段传入 (斜杠编码为 %2F)，依赖引擎开启 UseRawPath。
+		filesRoutes.GET("/:key/url", ctrl.GetDownloadURLHandler)
+
+		// 获取我的存储用量
+		// - 场景: 前端在上传前展示剩余空间。
+		// - 预期权限: 需要认证。
+		filesRoutes.GET("/usage", ctrl.GetUsageHandler)
+	}
+}
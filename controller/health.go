@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/health"
+)
+
+// HealthController 处理外部依赖健康检查相关的 HTTP 请求。
+type HealthController struct {
+	healthService health.DependencyHealthService // healthService: 依赖健康检查服务的实例。
+	logger        *core.ZapLogger                // logger: 日志记录器。
+}
+
+// NewHealthController 创建一个新的 HealthController 实例。
+// 参数:
+//   - healthService: 实现了 health.DependencyHealthService 接口的服务实例。
+//   - logger: 日志记录器实例。
+//
+// 返回:
+//   - *HealthController: 初始化完成的控制器实例。
+func NewHealthController(healthService health.DependencyHealthService, logger *core.ZapLogger) *HealthController {
+	return &HealthController{
+		healthService: healthService,
+		logger:        logger,
+	}
+}
+
+// DeepHealthHandler 处理管理员发起的深度健康检查请求。
+// @Summary 深度健康检查 (管理员)
+// @Description 并发探测 MySQL、Redis、COS (HEAD 存储桶)、短信服务 (换取 access_token) 与微信 API (可达性)，返回每项依赖的状态、耗时与失败原因。各项探测有独立超时，整体超过时限时立即返回，未完成的探测标记为 timeout。依赖不可用时仍返回 200，整体状态为 degraded。
+// @Tags 运维 (Operations)
+// @Produce json
+// @Success 200 {object} docs.SwaggerAPIDeepHealthResponse "检查完成，返回各依赖状态"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Router /api/v1/user-hub/health/deep [get]
+func (ctrl *HealthController) DeepHealthHandler(c *gin.Context) {
+	const operation = "HealthController.DeepHealthHandler"
+
+	// 1. 深度检查会访问所有外部依赖，仅对管理员开放
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试执行深度健康检查", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可执行深度健康检查")
+		return
+	}
+
+	// 2. 调用服务层并发探测各依赖
+	report := ctrl.healthService.Check(c.Request.Context())
+	if report.Status != vo.DependencyStatusUp {
+		ctrl.logger.Warn("深度健康检查发现不可用的依赖", zap.String("operation", operation), zap.String("status", report.Status))
+	}
+
+	// 3. 返回检查结果
+	response.RespondSuccess(c, *report, "健康检查完成")
+}
+
+// RegisterRoutes 注册健康检查相关的路由到指定的 Gin 路由组。
+// 参数:
+//   - group: Gin 的路由组实例。
+func (ctrl *HealthController) RegisterRoutes(group *gin.RouterGroup) {
+	// 深度健康检查
+	// - 场景: 运维面板与故障排查，查看各外部依赖的连通性。
+	// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内会校验角色。
+	group.GET("/health/deep", ctrl.DeepHealthHandler)
+}
@@ -101,7 +101,9 @@ func (ctrl *IdentityController) CreateIdentityHandler(c *gin.Context) {
 // @Param identityID path uint true "要更新的身份记录的唯一ID" Format(uint)
 // @Param body body dto.UpdateIdentityDTO true "更新身份请求的详细信息，主要包含新的凭证"
 // @Success 200 {object} response.APIResponse[vo.IdentityVO] "身份信息更新成功，返回更新后的身份信息"
-// @Failure 400 {object} response.APIResponse[string] "请求参数无效 (如JSON格式错误、身份ID格式无效、新凭证无效)"
+// @Failure 400 {object} response.APIResponse[string] "请求参数无效 (如JSON格式错误、身份ID格式无效、新凭证无效) 或 新密码与最近使用过的密码相同"
+// @Failure 401 {object} response.APIResponse[string] "未授权或认证失败"
+// @Failure 403 {object} response.APIResponse[string] "身份不属于当前用户 (非管理员)"
 // @Failure 404 {object} response.APIResponse[string] "指定的身份记录不存在"
 // @Failure 500 {object} response.APIResponse[string] "系统内部错误 (如数据库操作失败、密码加密失败)"
 // @Router /api/v1/user-hub/identities/{identityID} [put] // <--- 已更新路径
@@ -134,10 +136,17 @@ func (ctrl *IdentityController) UpdateIdentityHandler(c *gin.Context) {
 	}
 
 	// 3. 调用服务层执行更新身份的逻辑。
-	identityVO, err := ctrl.identityService.UpdateIdentity(c.Request.Context(), uint(identityID), &updateIdentityDTO)
+	caller, ok := identityCaller(c)
+	if !ok {
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+	identityVO, err := ctrl.identityService.UpdateIdentity(c.Request.Context(), caller, uint(identityID), &updateIdentityDTO)
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else if errors.Is(err, service.ErrIdentityForbidden) {
+			response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, err.Error())
 		} else if err.Error() == "要更新的身份记录不存在" { // 假设服务层对未找到情况返回此特定业务错误
 			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
 		} else {
@@ -157,14 +166,17 @@ func (ctrl *IdentityController) UpdateIdentityHandler(c *gin.Context) {
 
 // DeleteIdentityHandler 处理删除用户某个特定身份的请求。
 // @Summary 删除身份
-// @Description 用户或管理员注销或移除某个特定的登录方式（身份记录）。
+// @Description 用户或管理员注销或移除某个特定的登录方式（身份记录）。非管理员不能删除自己唯一的登录方式。
 // @Tags 身份管理 (Identity Management)
 // @Accept json
 // @Produce json
 // @Param identityID path uint true "要删除的身份记录的唯一ID" Format(uint)
 // @Success 200 {object} response.APIResponse[vo.Empty] "身份删除成功"
 // @Failure 400 {object} response.APIResponse[string] "请求参数无效 (如身份ID格式无效)"
+// @Failure 401 {object} response.APIResponse[string] "未授权或认证失败"
+// @Failure 403 {object} response.APIResponse[string] "身份不属于当前用户 (非管理员)"
 // @Failure 404 {object} response.APIResponse[string] "指定的身份记录不存在 (如果服务层认为删除不存在的记录是错误)"
+// @Failure 409 {object} response.APIResponse[string] "不能删除唯一的登录方式 (非管理员)"
 // @Failure 500 {object} response.APIResponse[string] "系统内部错误 (如数据库操作失败)"
 // @Router /api/v1/user-hub/identities/{identityID} [delete] // <--- 已更新路径
 func (ctrl *IdentityController) DeleteIdentityHandler(c *gin.Context) {
@@ -184,10 +196,19 @@ func (ctrl *IdentityController) DeleteIdentityHandler(c *gin.Context) {
 	}
 
 	// 2. 调用服务层执行删除身份的逻辑。
-	err = ctrl.identityService.DeleteIdentity(c.Request.Context(), uint(identityID))
+	caller, ok := identityCaller(c)
+	if !ok {
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+	err = ctrl.identityService.DeleteIdentity(c.Request.Context(), caller, uint(identityID))
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else if errors.Is(err, service.ErrIdentityForbidden) {
+			response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, err.Error())
+		} else if errors.Is(err, service.ErrLastIdentity) {
+			response.RespondError(c, http.StatusConflict, response.ErrCodeClientInvalidInput, err.Error())
 		} else if err.Error() == "要删除的身份记录不存在" { // 假设服务层对删除不存在记录返回此业务错误
 			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
 		} else {
@@ -205,16 +226,66 @@ func (ctrl *IdentityController) DeleteIdentityHandler(c *gin.Context) {
 	response.RespondSuccess[interface{}](c, nil, "身份删除成功")
 }
 
+// UnbindMyIdentityHandler 处理当前登录用户解绑自己某个登录方式的请求。
+// @Summary 解绑我的登录方式
+// @Description 用户移除自己不再使用的登录方式。用户ID取自网关透传的认证信息，只能解绑属于自己的身份，且不能解绑唯一的登录方式。
+// @Tags 身份管理 (Identity Management)
+// @Accept json
+// @Produce json
+// @Param identityID path uint true "要解绑的身份记录ID" Format(uint)
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "解绑成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如身份ID格式无效)"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 404 {object} docs.SwaggerAPIErrorResponseString "身份记录不存在或不属于当前用户"
+// @Failure 409 {object} docs.SwaggerAPIErrorResponseString "不能解绑唯一的登录方式"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库操作失败)"
+// @Router /api/v1/user-hub/identities/mine/{identityID} [delete]
+func (ctrl *IdentityController) UnbindMyIdentityHandler(c *gin.Context) {
+	const operation = "IdentityController.UnbindMyIdentityHandler"
+
+	// 1. 从上下文获取当前用户ID，并校验路径参数。
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Warn("无法从上下文中获取有效的UserID用于解绑登录方式", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+	identityID, err := strconv.ParseUint(c.Param("identityID"), 10, 64)
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "身份 ID 格式无效")
+		return
+	}
+
+	// 2. 调用服务层解绑 (服务层校验归属与唯一登录方式规则)。
+	if err := ctrl.identityService.UnbindMyIdentity(c.Request.Context(), userID, uint(identityID)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrIdentityNotFound):
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+		case errors.Is(err, service.ErrLastIdentity):
+			response.RespondError(c, http.StatusConflict, response.ErrCodeClientInvalidInput, err.Error())
+		default:
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		}
+		return
+	}
+
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "解绑成功")
+}
+
 // GetIdentitiesByUserIDHandler 处理根据用户ID获取其所有身份信息的请求。
 // @Summary 获取用户的所有身份信息
-// @Description 管理员或用户本人查看指定用户ID关联的所有登录方式/身份凭证信息（不含敏感凭证内容）。
+// @Description 管理员或用户本人查看指定用户ID关联的登录方式/身份凭证信息（不含敏感凭证内容）。管理员获得完整、不分页的列表；非管理员按页返回 (每页上限由 identityListConfig.page_size 配置)，标识符始终脱敏。
 // @Tags 身份管理 (Identity Management)
 // @Accept json
 // @Produce json
 // @Param userID path string true "要查询的用户ID"
+// @Param full query bool false "是否返回完整标识符（仅管理员可用，默认返回脱敏后的标识符）"
+// @Param page query int false "页码，从 1 开始（仅非管理员生效）"
+// @Param page_size query int false "每页条数，超过配置上限时按上限返回（仅非管理员生效）"
 // @Success 200 {object} response.APIResponse[vo.IdentityList] "获取用户身份列表成功"
-// @Failure 400 {object} response.APIResponse[string] "请求参数无效 (如用户ID为空)"
-// @Failure 404 {object} response.APIResponse[string] "指定的用户不存在 (如果服务层检查用户存在性)"
+// @Failure 400 {object} response.APIResponse[string] "请求参数无效 (如用户ID为空或分页参数非法)"
+// @Failure 403 {object} response.APIResponse[string] "非管理员请求完整标识符"
+// @Failure 404 {object} response.APIResponse[string] "指定的用户不存在 (用户存在但没有任何身份时返回 200 和空列表)"
 // @Failure 500 {object} response.APIResponse[string] "系统内部错误 (如数据库查询失败)"
 // @Router /api/v1/user-hub/users/{userID}/identities [get] // <--- 已更新路径
 func (ctrl *IdentityController) GetIdentitiesByUserIDHandler(c *gin.Context) {
@@ -228,12 +299,36 @@ func (ctrl *IdentityController) GetIdentitiesByUserIDHandler(c *gin.Context) {
 		return
 	}
 
-	// 2. 调用服务层获取身份列表。
-	identitiesVO, err := ctrl.identityService.GetIdentitiesByUserID(c.Request.Context(), userID)
+	// 2. 解析是否需要完整标识符：默认脱敏，仅管理员可通过 ?full=true 显式查看完整值。
+	revealFull := c.Query("full") == "true"
+	if revealFull && !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试获取完整身份标识符",
+			zap.String("operation", operation),
+			zap.String("userID", userID),
+		)
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可以查看完整的身份标识符")
+		return
+	}
+
+	// 3. 绑定分页参数：仅对非管理员生效，管理员始终获得完整列表。
+	var query dto.IdentityListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ctrl.logger.Warn("获取用户身份列表请求分页参数无效", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	// 4. 调用服务层获取身份列表。
+	identityList, err := ctrl.identityService.GetIdentitiesByUserID(c.Request.Context(), userID, service.IdentityListOptions{
+		Privileged: isAdminCaller(c),
+		RevealFull: revealFull,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+	})
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
-		} else if err.Error() == "用户不存在" { // 假设服务层可能返回此业务错误
+		} else if errors.Is(err, service.ErrUserNotFound) {
 			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
 		} else {
 			// 其他错误视为业务逻辑错误或未预期的服务层错误
@@ -242,14 +337,15 @@ func (ctrl *IdentityController) GetIdentitiesByUserIDHandler(c *gin.Context) {
 		return
 	}
 
-	// 3. 构造响应数据并返回。
-	//    即使列表为空 (identitiesVO 切片长度为0)，也应返回成功和空列表，而不是错误。
+	// 5. 构造响应数据并返回。
+	//    即使列表为空 (Items 长度为0)，也应返回成功和空列表，而不是错误。
 	ctrl.logger.Info("成功获取用户身份列表",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
-		zap.Int("count", len(identitiesVO)),
+		zap.Int("count", len(identityList.Items)),
+		zap.Int64("total", identityList.Total),
 	)
-	response.RespondSuccess(c, vo.IdentityList{Items: identitiesVO}, "获取用户身份列表成功")
+	response.RespondSuccess(c, identityList, "获取用户身份列表成功")
 }
 
 // GetIdentityTypesByUserIDHandler 处理根据用户ID获取其所有身份类型的请求。
@@ -260,8 +356,8 @@ func (ctrl *IdentityController) GetIdentitiesByUserIDHandler(c *gin.Context) {
 // @Produce json
 // @Param userID path string true "要查询的用户ID"
 // @Success 200 {object} response.APIResponse[vo.IdentityTypeList] "获取用户身份类型列表成功"
-// @Failure 400 {object} response.APIResponse[string] "请求参数无效 (如用户ID为空)"
-// @Failure 404 {object} response.APIResponse[string] "指定的用户不存在 (如果服务层检查用户存在性)"
+// @Failure 400 {object} response.APIResponse[string] "请求参数无效 (如用户ID为空或分页参数非法)"
+// @Failure 404 {object} response.APIResponse[string] "指定的用户不存在 (用户存在但没有任何身份时返回 200 和空列表)"
 // @Failure 500 {object} response.APIResponse[string] "系统内部错误 (如数据库查询失败)"
 // @Router /api/v1/user-hub/users/{userID}/identity-types [get] // <--- 已更新路径
 func (ctrl *IdentityController) GetIdentityTypesByUserIDHandler(c *gin.Context) {
@@ -280,7 +376,7 @@ func (ctrl *IdentityController) GetIdentityTypesByUserIDHandler(c *gin.Context)
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
-		} else if err.Error() == "用户不存在" { // 假设服务层可能返回此业务错误
+		} else if errors.Is(err, service.ErrUserNotFound) {
 			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
 		} else {
 			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
@@ -297,6 +393,161 @@ func (ctrl *IdentityController) GetIdentityTypesByUserIDHandler(c *gin.Context)
 	response.RespondSuccess(c, vo.IdentityTypeList{Items: identityTypes}, "获取用户身份类型列表成功")
 }
 
+// GetLoginMethodsByUserIDHandler 处理获取用户已绑定登录方式（含元数据）的请求。
+// @Summary 获取用户的登录方式
+// @Description 账号安全页使用：返回用户已绑定的每种登录方式的类型、脱敏标识符、是否已验证、是否主登录方式以及最近使用时间。
+// @Tags 身份管理 (Identity Management)
+// @Accept json
+// @Produce json
+// @Param userID path string true "要查询的用户ID"
+// @Success 200 {object} docs.SwaggerAPILoginMethodListResponse "获取用户登录方式成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
+// @Router /api/v1/user-hub/users/{userID}/login-methods [get]
+func (ctrl *IdentityController) GetLoginMethodsByUserIDHandler(c *gin.Context) {
+	const operation = "IdentityController.GetLoginMethodsByUserIDHandler"
+
+	// 1. 获取路径参数 userID。
+	userID := c.Param("userID")
+	if userID == "" {
+		ctrl.logger.Warn("获取用户登录方式请求的用户ID为空", zap.String("operation", operation))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "用户 ID 不能为空")
+		return
+	}
+
+	// 2. 调用服务层获取登录方式列表。
+	loginMethods, err := ctrl.identityService.GetLoginMethodsByUserID(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		}
+		return
+	}
+
+	// 3. 返回成功响应。
+	ctrl.logger.Info("成功获取用户登录方式",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.Int("count", len(loginMethods)),
+	)
+	response.RespondSuccess(c, vo.LoginMethodList{Items: loginMethods}, "获取用户登录方式成功")
+}
+
+// GetMyIdentitiesHandler 处理当前登录用户查看自己已绑定身份的请求。
+// @Summary 获取我的身份列表
+// @Description 用户查看自己绑定的登录方式，用户ID取自网关透传的认证信息，标识符始终脱敏返回；按页返回，每页上限由 identityListConfig.page_size 配置。
+// @Tags 身份管理 (Identity Management)
+// @Accept json
+// @Produce json
+// @Param page query int false "页码，从 1 开始"
+// @Param page_size query int false "每页条数，超过配置上限时按上限返回"
+// @Success 200 {object} docs.SwaggerAPIIdentityListResponse "获取我的身份列表成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "分页参数无效"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
+// @Router /api/v1/user-hub/identities/mine [get]
+func (ctrl *IdentityController) GetMyIdentitiesHandler(c *gin.Context) {
+	const operation = "IdentityController.GetMyIdentitiesHandler"
+
+	// 1. 从上下文获取当前用户ID。
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Warn("无法从上下文中获取有效的UserID用于查询我的身份", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	// 2. 绑定分页参数。
+	var query dto.IdentityListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ctrl.logger.Warn("获取我的身份列表请求分页参数无效", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	// 3. 调用服务层获取身份列表，自助查询始终按页返回脱敏后的标识符。
+	identityList, err := ctrl.identityService.GetIdentitiesByUserID(c.Request.Context(), userID, service.IdentityListOptions{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else if errors.Is(err, service.ErrUserNotFound) {
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+		} else {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		}
+		return
+	}
+
+	// 4. 返回成功响应。
+	ctrl.logger.Info("成功获取我的身份列表",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.Int("count", len(identityList.Items)),
+		zap.Int64("total", identityList.Total),
+	)
+	response.RespondSuccess(c, identityList, "获取我的身份列表成功")
+}
+
+// VerifyPhoneHandler 处理当前登录用户验证并绑定手机号的请求。
+// @Summary 验证并绑定手机号
+// @Description 已登录用户提交手机号和短信验证码，校验通过后为当前用户绑定一个已验证的手机号身份（不创建登录会话、不签发令牌）。若该手机号已被其他账号绑定则拒绝；若当前用户已绑定同一手机号，则将其标记为已验证。
+// @Tags 身份管理 (Identity Management)
+// @Accept json
+// @Produce json
+// @Param body body dto.VerifyPhoneDTO true "手机号及短信验证码"
+// @Success 200 {object} docs.SwaggerAPIIdentityVOResponse "手机号验证并绑定成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 或 业务逻辑错误 (如验证码错误或过期、手机号已被其他账号绑定、当前账号已绑定其他手机号)"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/identities/phone/verify [post]
+func (ctrl *IdentityController) VerifyPhoneHandler(c *gin.Context) {
+	const operation = "IdentityController.VerifyPhoneHandler"
+
+	// 1. 从上下文获取当前用户ID。
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Warn("无法从上下文中获取有效的UserID用于验证手机号", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	// 2. 绑定并校验请求体数据。
+	var verifyPhoneDTO dto.VerifyPhoneDTO
+	if err := c.ShouldBindJSON(&verifyPhoneDTO); err != nil {
+		ctrl.logger.Warn("验证手机号请求参数绑定失败",
+			zap.String("operation", operation),
+			zap.String("userID", userID),
+			zap.Error(err),
+		)
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请求数据无效")
+		return
+	}
+
+	// 3. 调用服务层验证并绑定手机号。
+	identityVO, err := ctrl.identityService.VerifyAndBindPhone(c.Request.Context(), userID, verifyPhoneDTO.Phone, verifyPhoneDTO.Code)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		}
+		return
+	}
+
+	// 4. 返回成功响应。
+	ctrl.logger.Info("成功验证并绑定手机号",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.Uint("identityID", identityVO.IdentityID),
+	)
+	response.RespondSuccess(c, identityVO, "手机号验证成功")
+}
+
 // RegisterRoutes 注册与用户身份管理相关的路由到指定的 Gin 路由组。
 // 设计目的:
 //   - 将此控制器的所有API端点集中定义和注册。
@@ -315,12 +566,24 @@ func (ctrl *IdentityController) RegisterRoutes(group *gin.RouterGroup) {
 		// 预期需要认证, 允许用户和管理员操作 (由网关处理认证和基础角色判断)
 		identitiesRoutes.POST("", ctrl.CreateIdentityHandler) // 完整路径: /user-hub/api/v1/identities
 
+		// 查看当前登录用户自己的身份列表 (标识符脱敏)
+		// 预期需要认证，用户ID取自网关透传的上下文，无需暴露管理员路径
+		identitiesRoutes.GET("/mine", ctrl.GetMyIdentitiesHandler) // 完整路径: /user-hub/api/v1/identities/mine
+
+		// 解绑自己的登录方式 (只能解绑属于自己的身份，且不能解绑唯一的登录方式)
+		// 预期需要认证，用户ID取自网关透传的上下文；管理员移除任意身份请使用 DELETE /identities/:identityID
+		identitiesRoutes.DELETE("/mine/:identityID", ctrl.UnbindMyIdentityHandler) // 完整路径: /user-hub/api/v1/identities/mine/:identityID
+
+		// 验证并绑定手机号 (不登录，只创建已验证的手机号身份)
+		// 场景：账号密码/微信注册的用户补充绑定手机号；用户ID取自网关透传的上下文
+		identitiesRoutes.POST("/phone/verify", ctrl.VerifyPhoneHandler) // 完整路径: /user-hub/api/v1/identities/phone/verify
+
 		// 更新身份信息 (例如，修改密码)
-		// 预期需要认证，允许管理员或用户本人操作 (网关处理认证，服务层或后续逻辑需处理本人或管理员判断)
+		// 预期需要认证，允许管理员或用户本人操作 (网关处理认证，服务层再次校验身份归属)
 		identitiesRoutes.PUT("/:identityID", ctrl.UpdateIdentityHandler) // 完整路径: /user-hub/api/v1/identities/:identityID
 
 		// 删除身份 (例如，用户解绑登录方式)
-		// 预期需要认证，允许管理员或用户本人操作 (同上)
+		// 预期需要认证，允许管理员或用户本人操作 (同上，服务层再次校验身份归属)
 		identitiesRoutes.DELETE("/:identityID", ctrl.DeleteIdentityHandler) // 完整路径: /user-hub/api/v1/identities/:identityID
 	}
 
@@ -337,5 +600,10 @@ func (ctrl *IdentityController) RegisterRoutes(group *gin.RouterGroup) {
 		// 预期需要认证，仅允许管理员操作 (网关处理认证，服务层或后续逻辑需处理本人或管理员判断)
 		// 完整路径: /user-hub/api/v1/users/:userID/identities
 		userSpecificIdentityRoutes.GET("/:userID/identities", ctrl.GetIdentitiesByUserIDHandler)
+
+		// 获取指定用户的登录方式（含脱敏标识符、验证状态、最近使用时间），供账号安全页使用
+		// 预期需要认证，允许管理员或用户本人操作
+		// 完整路径: /user-hub/api/v1/users/:userID/login-methods
+		userSpecificIdentityRoutes.GET("/:userID/login-methods", ctrl.GetLoginMethodsByUserIDHandler)
 	}
 }
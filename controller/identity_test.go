@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/constants"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	repo "github.com/Xushengqwer/user_hub/repository/mysql"
+	service "github.com/Xushengqwer/user_hub/service/identity"
+)
+
+// newMockGormDB 基于 sqlmock 创建 GORM 连接，仅用于驱动服务层的事务开启/提交/回滚；
+// 数据读写由内存假仓库完成。
+func newMockGormDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建 sqlmock 失败: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建 GORM 连接失败: %v", err)
+	}
+	return db, mock
+}
+
+// memoryIdentityRepo 内嵌接口，以内存 map 实现解绑流程用到的加锁查询与删除
+type memoryIdentityRepo struct {
+	repo.IdentityRepository
+	identities map[uint]*entities.UserIdentity
+}
+
+func (r *memoryIdentityRepo) LockIdentitiesByUserID(_ context.Context, _ *gorm.DB, userID string) ([]*entities.UserIdentity, error) {
+	var result []*entities.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			result = append(result, identity)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryIdentityRepo) GetIdentityByID(_ context.Context, identityID uint) (*entities.UserIdentity, error) {
+	identity, ok := r.identities[identityID]
+	if !ok {
+		return nil, commonerrors.ErrRepoNotFound
+	}
+	copied := *identity
+	return &copied, nil
+}
+
+func (r *memoryIdentityRepo) DeleteIdentity(_ context.Context, _ *gorm.DB, identityID uint) error {
+	delete(r.identities, identityID)
+	return nil
+}
+
+func TestUnbindMyIdentityHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		callerID    string
+		identityID  string
+		wantStatus  int
+		wantDeleted bool
+		wantTx      bool // 是否进入服务层事务
+		wantCommit  bool
+	}{
+		{name: "解绑自己的非唯一登录方式", callerID: "u1", identityID: "1", wantStatus: http.StatusOK, wantDeleted: true, wantTx: true, wantCommit: true},
+		{name: "不能解绑他人的登录方式", callerID: "u1", identityID: "3", wantStatus: http.StatusNotFound, wantTx: true},
+		{name: "不能解绑唯一的登录方式", callerID: "u2", identityID: "3", wantStatus: http.StatusConflict, wantTx: true},
+		{name: "身份ID格式无效", callerID: "u1", identityID: "abc", wantStatus: http.StatusBadRequest},
+		{name: "未认证", callerID: "", identityID: "1", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// u1 拥有两个登录方式，u2 仅有一个
+			identityRepo := &memoryIdentityRepo{identities: map[uint]*entities.UserIdentity{
+				1: {IdentityID: 1, UserID: "u1", IdentityType: enums.AccountPassword},
+				2: {IdentityID: 2, UserID: "u1", IdentityType: enums.Phone},
+				3: {IdentityID: 3, UserID: "u2", IdentityType: enums.AccountPassword},
+			}}
+			db, mock := newMockGormDB(t)
+			if tt.wantTx {
+				mock.ExpectBegin()
+				if tt.wantCommit {
+					mock.ExpectCommit()
+				} else {
+					mock.ExpectRollback()
+				}
+			}
+			logger := newTestLogger(t)
+			identityService := service.NewUserIdentityService(identityRepo, nil, db, logger, nil, nil, service.CredentialStrategies{}, config.IdentityListConfig{})
+			ctrl := NewIdentityController(identityService, fakeJWT{}, logger)
+
+			r := gin.New()
+			r.DELETE("/identities/mine/:identityID", func(c *gin.Context) {
+				if tt.callerID != "" {
+					c.Set(string(constants.UserIDKey), tt.callerID)
+				}
+				ctrl.UnbindMyIdentityHandler(c)
+			})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/identities/mine/"+tt.identityID, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("状态码 = %d，期望 %d，响应体: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if id, ok := map[string]uint{"1": 1, "3": 3}[tt.identityID]; ok {
+				_, exists := identityRepo.identities[id]
+				if exists == tt.wantDeleted {
+					t.Errorf("身份 %d 是否仍存在 = %v，期望被删除 = %v", id, exists, tt.wantDeleted)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("事务行为不符合预期: %v", err)
+			}
+		})
+	}
+}
+
+func TestIdentityHandlersOwnership(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		callerID   string
+		role       string
+		body       string
+		wantStatus int
+		wantTx     bool // 是否进入服务层事务 (仅非管理员删除自己的身份)
+	}{
+		{name: "本人删除自己的身份", method: http.MethodDelete, callerID: "u1", wantStatus: http.StatusOK, wantTx: true},
+		{name: "非本人删除他人身份", method: http.MethodDelete, callerID: "u2", wantStatus: http.StatusForbidden},
+		{name: "管理员删除他人身份", method: http.MethodDelete, callerID: "admin", role: "admin", wantStatus: http.StatusOK},
+		{name: "非本人更新他人身份", method: http.MethodPut, callerID: "u2", body: `{"credential":"new"}`, wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identityRepo := &memoryIdentityRepo{identities: map[uint]*entities.UserIdentity{
+				1: {IdentityID: 1, UserID: "u1", IdentityType: enums.Phone},
+				2: {IdentityID: 2, UserID: "u1", IdentityType: enums.Phone},
+			}}
+			db, mock := newMockGormDB(t)
+			if tt.wantTx {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			}
+			logger := newTestLogger(t)
+			identityService := service.NewUserIdentityService(identityRepo, nil, db, logger, nil, nil, service.CredentialStrategies{}, config.IdentityListConfig{})
+			ctrl := NewIdentityController(identityService, fakeJWT{}, logger)
+
+			r := gin.New()
+			setCaller := func(c *gin.Context) {
+				c.Set(string(constants.UserIDKey), tt.callerID)
+				if tt.role != "" {
+					c.Set(string(constants.RoleKey), tt.role)
+				}
+			}
+			r.PUT("/identities/:identityID", setCaller, ctrl.UpdateIdentityHandler)
+			r.DELETE("/identities/:identityID", setCaller, ctrl.DeleteIdentityHandler)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, "/identities/1", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("状态码 = %d，期望 %d，响应体: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if _, exists := identityRepo.identities[1]; exists == (tt.method == http.MethodDelete && tt.wantStatus == http.StatusOK) {
+				t.Errorf("身份 1 是否仍存在 = %v，与期望不符", exists)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("事务行为不符合预期: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/login/unified"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// UnifiedLoginController 处理"任意标识符登录"统一入口的 HTTP 请求。
+// 依赖于 unified.UnifiedLoginService 将请求分发到对应的专用登录服务。
+type UnifiedLoginController struct {
+	loginService unified.UnifiedLoginService    // loginService: 统一登录服务。
+	jwtUtil      dependencies.JWTTokenInterface // jwtUtil: JWT 工具，用于获取平台对应的刷新令牌有效期。
+	logger       *core.ZapLogger                // logger: 日志记录器。
+	cookieConfig config.CookieConfig            // cookieConfig: Web 平台刷新令牌 Cookie 配置。
+	loginConfig  config.UnifiedLoginConfig      // loginConfig: 统一登录入口配置，决定是否注册路由。
+}
+
+// NewUnifiedLoginController 创建一个新的 UnifiedLoginController 实例。
+func NewUnifiedLoginController(
+	loginService unified.UnifiedLoginService,
+	jwtUtil dependencies.JWTTokenInterface,
+	logger *core.ZapLogger,
+	cookieCfg config.CookieConfig,
+	loginCfg config.UnifiedLoginConfig,
+) *UnifiedLoginController {
+	return &UnifiedLoginController{
+		loginService: loginService,
+		jwtUtil:      jwtUtil,
+		logger:       logger,
+		cookieConfig: cookieCfg,
+		loginConfig:  loginCfg,
+	}
+}
+
+// LoginHandler 处理统一登录入口的请求。
+// @Summary 统一登录 (任意标识符)
+// @Description 使用账号或手机号登录。未指定 type 时按标识符格式自动识别 (邮箱 / 手机号 / 账号)；提供 password 时按密码登录，否则按验证码登录。各方式的专用登录接口依然可用。
+// @Tags 统一登录
+// @Accept json
+// @Produce json
+// @Param body body dto.UnifiedLoginData true "登录信息 (标识符、密码或验证码、可选类型)"
+// @Param X-Platform header string true "客户端平台类型 (wechat 平台仅用于微信登录)" Enums(web, app) default(web)
+// @Success 200 {object} docs.SwaggerAPILoginResponse "登录成功，返回用户信息及访问和刷新令牌"
+// @Failure 400 {object} docs.SwaggerAPILoginFailureResponse "请求参数无效、登录类型不受支持或凭证错误；data.reason: invalid_input / invalid_credentials / invalid_captcha / account_blacklisted / account_unavailable"
+// @Failure 403 {object} docs.SwaggerAPIReactivationChallengeResponse "账号已停用，返回重新激活凭证；开启身份验证策略且身份未验证时返回 SwaggerAPIIdentityVerificationRequiredResponse；注册暂未开放时返回 SwaggerAPILoginFailureResponse，data.reason: registration_closed"
+// @Failure 500 {object} docs.SwaggerAPILoginFailureResponse "系统内部错误；data.reason: internal_error"
+// @Router /api/v1/user-hub/login [post]
+func (ctrl *UnifiedLoginController) LoginHandler(c *gin.Context) {
+	const operation = "UnifiedLoginController.LoginHandler"
+
+	// 1. 绑定并校验请求体
+	var loginData dto.UnifiedLoginData
+	if err := c.ShouldBindJSON(&loginData); err != nil {
+		ctrl.logger.Warn("统一登录请求参数绑定失败",
+			zap.String("operation", operation),
+			zap.Error(err),
+		)
+		respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, vo.LoginReasonInvalidInput, "输入参数无效")
+		return
+	}
+
+	// 2. 获取并验证请求头中的 X-Platform 参数
+	platformStr := c.GetHeader("X-Platform")
+	platform, err := resolveLoginPlatform(c, "", credentialLoginPlatforms)
+	if err != nil {
+		ctrl.logger.Warn("无效或与登录方式不匹配的平台类型",
+			zap.String("operation", operation),
+			zap.String("platformHeader", platformStr),
+			zap.Error(err),
+		)
+		respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, vo.LoginReasonInvalidInput, err.Error())
+		return
+	}
+
+	// 3. 调用服务层分发登录
+	userInfo, tokenPair, err := ctrl.loginService.Login(c.Request.Context(), loginData, platform)
+	if err != nil {
+		if respondIfAccountDeactivated(c, err) || respondIfIdentityNotVerified(c, err) {
+			return
+		}
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			ctrl.logger.Error("统一登录服务返回系统错误",
+				zap.String("operation", operation),
+				zap.String("identifier", loginData.Identifier), // 注意脱敏
+				zap.Any("platform", platform),
+				zap.Error(err),
+			)
+			respondLoginServiceError(c, err)
+		} else {
+			ctrl.logger.Warn("统一登录服务返回业务错误",
+				zap.String("operation", operation),
+				zap.String("identifier", loginData.Identifier), // 注意脱敏
+				zap.Any("platform", platform),
+				zap.Error(err),
+			)
+			respondLoginServiceError(c, err)
+		}
+		return
+	}
+
+	// 4. 根据平台处理令牌响应：Web 平台 RT 写入 HttpOnly Cookie，其余平台随 JSON 返回
+	deliverRefreshToken(c, ctrl.cookieConfig, platform, &tokenPair, ctrl.jwtUtil.RefreshTokenTTL(platform))
+	if !guardWebRefreshToken(c, ctrl.cookieConfig, platform, &tokenPair, ctrl.logger, operation) {
+		return
+	}
+	responseData := vo.LoginResponse{User: userInfo, Token: tokenPair}
+
+	ctrl.logger.Info("统一登录成功",
+		zap.String("operation", operation),
+		zap.String("userID", userInfo.UserID),
+		zap.Any("platform", platform),
+	)
+	response.RespondSuccess(c, responseData, "登录成功")
+}
+
+// RegisterRoutes 注册统一登录入口路由。
+// - 场景：客户端只提供一个输入框，由服务端识别标识符类型；配置关闭时不注册，只保留各专用登录接口。
+func (ctrl *UnifiedLoginController) RegisterRoutes(group *gin.RouterGroup) {
+	if !ctrl.loginConfig.Enabled {
+		ctrl.logger.Info("统一登录入口未启用，跳过路由注册")
+		return
+	}
+	group.POST("/login", ctrl.LoginHandler)
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
+)
+
+// respondLoginFailure 输出登录失败响应，data.reason 中附带稳定的原因码，message 仍为面向用户的通用文案。
+func respondLoginFailure(c *gin.Context, statusCode int, code int, reason string, message string) {
+	c.JSON(statusCode, response.APIResponse[vo.LoginFailureVO]{
+		Code:    code,
+		Message: message,
+		Data:    vo.LoginFailureVO{Reason: reason},
+	})
+}
+
+// respondLoginServiceError 将登录服务返回的错误映射为 HTTP 状态码与原因码并输出响应。
+// - 停用、未验证等携带额外指引的错误由 respondIfAccountDeactivated / respondIfIdentityNotVerified 先行处理。
+// - 系统错误返回 500，注册暂未开放返回 403，其余业务错误维持 400，文案沿用服务层的用户友好提示。
+func respondLoginServiceError(c *gin.Context, err error) {
+	if errors.Is(err, commonerrors.ErrSystemError) {
+		respondLoginFailure(c, http.StatusInternalServerError, response.ErrCodeServerInternal, vo.LoginReasonInternalError, commonerrors.ErrSystemError.Error())
+		return
+	}
+	if errors.Is(err, loginerr.ErrWechatUnavailable) {
+		// 服务层附带了上游错误，文案只使用通用提示；有权查看时在 data.upstream 中附带脱敏摘要
+		c.JSON(http.StatusBadRequest, response.APIResponse[vo.LoginFailureVO]{
+			Code:    response.ErrCodeClientInvalidInput,
+			Message: loginerr.ErrWechatUnavailable.Error(),
+			Data:    vo.LoginFailureVO{Reason: vo.LoginReasonServiceUnavailable, Upstream: upstreamErrorDetail(c, err)},
+		})
+		return
+	}
+	if errors.Is(err, loginerr.ErrRegistrationClosed) {
+		respondLoginFailure(c, http.StatusForbidden, response.ErrCodeClientForbidden, vo.LoginReasonRegistrationClosed, loginerr.ErrRegistrationClosed.Error())
+		return
+	}
+	respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, loginFailureReason(err), err.Error())
+}
+
+// loginFailureReason 根据登录服务返回的业务错误确定原因码，无法归类的错误视为输入无效。
+func loginFailureReason(err error) string {
+	switch {
+	case errors.Is(err, loginerr.ErrInvalidCredentials):
+		return vo.LoginReasonInvalidCredentials
+	case errors.Is(err, loginerr.ErrInvalidCaptcha):
+		return vo.LoginReasonInvalidCaptcha
+	case errors.Is(err, loginerr.ErrInvalidWechatCode):
+		return vo.LoginReasonInvalidWechatCode
+	case errors.Is(err, loginerr.ErrAccountBlacklisted):
+		return vo.LoginReasonAccountBlacklisted
+	case errors.Is(err, loginerr.ErrAccountUnavailable):
+		return vo.LoginReasonAccountUnavailable
+	case errors.Is(err, loginerr.ErrWechatUnavailable), errors.Is(err, commonerrors.ErrServiceBusy):
+		return vo.LoginReasonServiceUnavailable
+	default:
+		return vo.LoginReasonInvalidInput
+	}
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	service "github.com/Xushengqwer/user_hub/service/feature"
+	"github.com/gin-gonic/gin"
+)
+
+// MetaController 处理面向前端的元信息请求，例如功能开关、支持的语言。
+type MetaController struct {
+	featureService service.FeatureFlagService // featureService: 功能开关服务的实例。
+	localeCfg      config.LocaleConfig        // localeCfg: 支持的语言与默认语言。
+	logger         *core.ZapLogger            // logger: 日志记录器。
+}
+
+// NewMetaController 创建一个新的 MetaController 实例。
+// 参数:
+//   - featureService: 实现了 service.FeatureFlagService 接口的服务实例。
+//   - localeCfg: 语言协商配置。
+//   - logger: 日志记录器实例。
+//
+// 返回:
+//   - *MetaController: 初始化完成的控制器实例。
+func NewMetaController(featureService service.FeatureFlagService, localeCfg config.LocaleConfig, logger *core.ZapLogger) *MetaController {
+	return &MetaController{
+		featureService: featureService,
+		localeCfg:      localeCfg,
+		logger:         logger,
+	}
+}
+
+// GetFeaturesHandler 处理获取功能开关的请求。
+// @Summary 获取功能开关
+// @Description 返回服务端控制的功能开关集合。已认证用户会额外合并其角色的覆盖配置。响应带有 ETag，客户端可通过 If-None-Match 进行条件请求，内容未变化时返回 304。
+// @Tags 元信息 (Meta)
+// @Produce json
+// @Param If-None-Match header string false "上次响应返回的 ETag"
+// @Success 200 {object} docs.SwaggerAPIFeatureFlagsResponse "获取成功，返回功能开关集合"
+// @Success 304 "功能开关未变化"
+// @Router /api/v1/user-hub/meta/features [get]
+func (ctrl *MetaController) GetFeaturesHandler(c *gin.Context) {
+	var rolePtr *enums.UserRole
+	if role, ok := getCallerRole(c); ok {
+		rolePtr = &role
+	}
+	features, etag := ctrl.featureService.GetFeatures(rolePtr)
+
+	// 结果随调用者角色变化，缓存需按角色区分，且每次使用前需向服务端校验
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("Vary", "X-User-Role")
+
+	if match := c.GetHeader("If-None-Match"); match != "" && (match == etag || match == "*") {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	response.RespondSuccess(c, *features, "获取功能开关成功")
+}
+
+// GetLocalesHandler 处理获取支持语言的请求。
+// @Summary 获取支持的语言
+// @Description 返回服务端支持的语言列表与默认语言。请求的 Accept-Language 只会在该列表中匹配，无法匹配时使用默认语言；实际使用的语言见响应头 Content-Language。
+// @Tags 元信息 (Meta)
+// @Produce json
+// @Success 200 {object} docs.SwaggerAPILocalesResponse "获取成功，返回支持的语言"
+// @Router /api/v1/user-hub/meta/locales [get]
+func (ctrl *MetaController) GetLocalesHandler(c *gin.Context) {
+	response.RespondSuccess(c, vo.LocalesVO{
+		Supported: ctrl.localeCfg.SupportedOrDefault(),
+		Default:   ctrl.localeCfg.DefaultOrDefault(),
+	}, "获取支持语言成功")
+}
+
+// RegisterRoutes 注册元信息相关的路由到指定的 Gin 路由组。
+// 参数:
+//   - group: Gin 的路由组实例。
+func (ctrl *MetaController) RegisterRoutes(group *gin.RouterGroup) {
+	metaRoutes := group.Group("/meta")
+	{
+		// 获取功能开关
+		// - 场景: 前端启动或定期刷新时拉取服务端控制的功能开关。
+		// - 预期权限: 公开接口，已认证用户会获得按角色覆盖后的结果。
+		metaRoutes.GET("/features", ctrl.GetFeaturesHandler)
+
+		// 获取支持的语言
+		// - 场景: 前端据此展示语言切换选项，并了解不支持的语言会回退到哪种语言。
+		// - 预期权限: 公开接口。
+		metaRoutes.GET("/locales", ctrl.GetLocalesHandler)
+	}
+}
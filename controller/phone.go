@@ -6,14 +6,12 @@ import (
 
 	"github.com/Xushengqwer/go-common/commonerrors" // 引入公共错误包
 	"github.com/Xushengqwer/go-common/core"         // 引入日志包
-	"github.com/Xushengqwer/go-common/models/enums"
 	"github.com/Xushengqwer/go-common/response"
 	"github.com/Xushengqwer/user_hub/config"
-	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/service/login/auth"
-	"github.com/Xushengqwer/user_hub/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
 )
@@ -21,9 +19,10 @@ import (
 // PhoneAuthController 处理与手机号+验证码认证相关的 HTTP 请求。
 // 依赖于 auth.PhoneAuthService 来执行核心业务逻辑。
 type PhoneAuthController struct {
-	phoneService auth.PhoneAuthService // phoneService: 手机号认证服务的实例。
-	logger       *core.ZapLogger       // logger: 日志记录器。
-	cookieConfig config.CookieConfig   // 新增：存储 Cookie 配置
+	phoneService auth.PhoneAuthService          // phoneService: 手机号认证服务的实例。
+	jwtUtil      dependencies.JWTTokenInterface // jwtUtil: JWT 工具，用于获取平台对应的刷新令牌有效期。
+	logger       *core.ZapLogger                // logger: 日志记录器。
+	cookieConfig config.CookieConfig            // 新增：存储 Cookie 配置
 }
 
 // NewPhoneAuthController 创建一个新的 PhoneAuthController 实例。
@@ -32,6 +31,7 @@ type PhoneAuthController struct {
 //
 // 参数:
 //   - phoneService: 实现了 auth.PhoneAuthService 接口的服务实例。
+//   - jwtUtil: JWT工具实例。
 //   - logger: 日志记录器实例。
 //   - cookieCfg: Cookie 配置。
 //
@@ -39,11 +39,13 @@ type PhoneAuthController struct {
 //   - *PhoneAuthController: 初始化完成的控制器实例。
 func NewPhoneAuthController(
 	phoneService auth.PhoneAuthService,
+	jwtUtil dependencies.JWTTokenInterface,
 	logger *core.ZapLogger, // 注入 logger
 	cookieCfg config.CookieConfig, // 新增：接收 Cookie 配置
 ) *PhoneAuthController {
 	return &PhoneAuthController{
 		phoneService: phoneService,
+		jwtUtil:      jwtUtil,
 		logger:       logger,    // 存储 logger
 		cookieConfig: cookieCfg, // 存储 Cookie 配置
 	}
@@ -56,10 +58,11 @@ func NewPhoneAuthController(
 // @Accept json
 // @Produce json
 // @Param body body dto.PhoneLoginOrRegisterData true "登录/注册信息 (手机号、验证码)"
-// @Param X-Platform header string true "客户端平台类型" Enums(web, wechat, app) default(web)
+// @Param X-Platform header string true "客户端平台类型 (wechat 平台仅用于微信登录)" Enums(web, app) default(web)
 // @Success 200 {object} docs.SwaggerAPILoginResponse "登录或注册成功，返回用户信息及访问和刷新令牌"
-// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、平台类型无效) 或 业务逻辑错误 (如验证码错误或过期、用户状态异常)"
-// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库操作失败、令牌生成失败、Redis操作失败)"
+// @Failure 400 {object} docs.SwaggerAPILoginFailureResponse "请求参数无效 (如JSON格式错误、平台类型无效) 或 业务逻辑错误；data.reason: invalid_input / invalid_captcha / account_blacklisted / account_unavailable"
+// @Failure 403 {object} docs.SwaggerAPIReactivationChallengeResponse "账号已停用，返回重新激活凭证；注册暂未开放时返回 SwaggerAPILoginFailureResponse，data.reason: registration_closed"
+// @Failure 500 {object} docs.SwaggerAPILoginFailureResponse "系统内部错误 (如数据库操作失败、令牌生成失败、Redis操作失败)；data.reason: internal_error"
 // @Router /api/v1/user-hub/phone/login [post] // <--- 已更新路径
 func (ctrl *PhoneAuthController) LoginOrRegisterHandler(c *gin.Context) {
 	const operation = "PhoneAuthController.LoginOrRegisterHandler"
@@ -71,21 +74,21 @@ func (ctrl *PhoneAuthController) LoginOrRegisterHandler(c *gin.Context) {
 			zap.String("operation", operation),
 			zap.Error(err),
 		)
-		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, vo.LoginReasonInvalidInput, "输入参数无效")
 		return
 	}
 
 	// 2. 获取并验证请求头中的 X-Platform 参数。
 	platformStr := c.GetHeader("X-Platform")
-	platform, err := enums.PlatformFromString(platformStr)
+	platform, err := resolveLoginPlatform(c, "", credentialLoginPlatforms)
 	if err != nil {
-		ctrl.logger.Warn("无效的平台类型",
+		ctrl.logger.Warn("无效或与登录方式不匹配的平台类型",
 			zap.String("operation", operation),
 			zap.String("platformHeader", platformStr),
 			zap.String("phone", phoneLoginOrRegisterData.Phone), // 记录关联手机号
 			zap.Error(err),
 		)
-		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "无效的平台类型")
+		respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, vo.LoginReasonInvalidInput, err.Error())
 		return
 	}
 
@@ -93,6 +96,9 @@ func (ctrl *PhoneAuthController) LoginOrRegisterHandler(c *gin.Context) {
 	//    服务层会处理验证码校验、用户查找/创建、状态检查和令牌生成。
 	userInfo, tokenPair, err := ctrl.phoneService.LoginOrRegister(c.Request.Context(), phoneLoginOrRegisterData, platform)
 	if err != nil {
+		if respondIfAccountDeactivated(c, err) {
+			return
+		}
 		// 根据服务层返回的错误类型记录日志并响应。
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			ctrl.logger.Error("手机号登录/注册服务返回系统错误",
@@ -101,7 +107,7 @@ func (ctrl *PhoneAuthController) LoginOrRegisterHandler(c *gin.Context) {
 				zap.Any("platform", platform),
 				zap.Error(err),
 			)
-			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+			respondLoginServiceError(c, err)
 		} else {
 			// 业务逻辑错误（例如，验证码错误、用户状态异常）。
 			ctrl.logger.Warn("手机号登录/注册服务返回业务错误",
@@ -111,38 +117,22 @@ func (ctrl *PhoneAuthController) LoginOrRegisterHandler(c *gin.Context) {
 				zap.Error(err), // 记录具体的业务错误信息
 			)
 			// 将服务层返回的、对用户友好的错误信息直接展示给用户。
-			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+			respondLoginServiceError(c, err)
 		}
 		return
 	}
 
-	// 4. 根据平台处理令牌响应
-	if platform == enums.PlatformWeb {
-		rtMaxAge := int(constants.RefreshTokenTTL.Seconds())
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     ctrl.cookieConfig.RefreshTokenName,
-			Value:    tokenPair.RefreshToken,
-			MaxAge:   rtMaxAge,
-			Path:     ctrl.cookieConfig.Path,
-			Domain:   ctrl.cookieConfig.Domain,
-			Secure:   ctrl.cookieConfig.Secure,
-			HttpOnly: ctrl.cookieConfig.HttpOnly,
-			SameSite: utils.ParseSameSiteString(ctrl.cookieConfig.SameSite),
-		})
-		responseData := vo.LoginResponse{
-			User:  userInfo,
-			Token: vo.TokenPair{AccessToken: tokenPair.AccessToken},
-		}
-		ctrl.logger.Info("手机号登录/注册成功 (Web平台，RT已设置到Cookie)", zap.String("operation", operation), zap.String("userID", userInfo.UserID), zap.String("phone", phoneLoginOrRegisterData.Phone), zap.Any("platform", platform))
-		response.RespondSuccess(c, responseData, "登录/注册成功")
-	} else {
-		responseData := vo.LoginResponse{
-			User:  userInfo,
-			Token: tokenPair,
-		}
-		ctrl.logger.Info("手机号登录/注册成功", zap.String("operation", operation), zap.String("userID", userInfo.UserID), zap.String("phone", phoneLoginOrRegisterData.Phone), zap.Any("platform", platform))
-		response.RespondSuccess(c, responseData, "登录/注册成功")
+	// 4. 根据平台处理令牌响应：Web 平台 RT 写入 HttpOnly Cookie，其余平台随 JSON 返回
+	deliverRefreshToken(c, ctrl.cookieConfig, platform, &tokenPair, ctrl.jwtUtil.RefreshTokenTTL(platform))
+	if !guardWebRefreshToken(c, ctrl.cookieConfig, platform, &tokenPair, ctrl.logger, operation) {
+		return
+	}
+	responseData := vo.LoginResponse{
+		User:  userInfo,
+		Token: tokenPair,
 	}
+	ctrl.logger.Info("手机号登录/注册成功", zap.String("operation", operation), zap.String("userID", userInfo.UserID), zap.String("phone", phoneLoginOrRegisterData.Phone), zap.Any("platform", platform))
+	response.RespondSuccess(c, responseData, "登录/注册成功")
 }
 
 // RegisterRoutes 注册与手机号认证相关的路由到指定的 Gin 路由组。
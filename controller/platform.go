@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/gin-gonic/gin"
+)
+
+// 各登录渠道允许签发令牌的平台
+// - 微信小程序登录只能签发 wechat 平台令牌，否则 web 平台会把刷新令牌写入小程序无法使用的 Cookie。
+// - wechat 平台保留给微信渠道，账号/手机号等登录只允许 web 与 app。
+var (
+	wechatLoginPlatforms     = []enums.Platform{enums.PlatformWechat}
+	credentialLoginPlatforms = []enums.Platform{enums.PlatformWeb, enums.PlatformApp}
+)
+
+// resolveLoginPlatform 读取并校验请求头中的 X-Platform，确保其与登录渠道相符。
+// - 请求头缺失且 fallback 非空时使用 fallback；否则要求请求头存在且有效。
+// - 平台有效但不在 allowed 中时返回说明原因的错误，调用方应以 400 响应。
+func resolveLoginPlatform(c *gin.Context, fallback enums.Platform, allowed []enums.Platform) (enums.Platform, error) {
+	platformStr := c.GetHeader("X-Platform")
+	if platformStr == "" && fallback != "" {
+		return fallback, nil
+	}
+	platform, err := enums.PlatformFromString(platformStr)
+	if err != nil {
+		return "", errors.New("无效的平台类型")
+	}
+	names := make([]string, 0, len(allowed))
+	for _, p := range allowed {
+		if p == platform {
+			return platform, nil
+		}
+		names = append(names, string(p))
+	}
+	return "", fmt.Errorf("该登录方式不支持平台类型 %s，请使用 %s", platform, strings.Join(names, " 或 "))
+}
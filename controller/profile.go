@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"io"
 	"net/http"
 
 	"github.com/Xushengqwer/go-common/commonerrors" // 引入公共错误包
@@ -12,6 +13,8 @@ import (
 	"github.com/Xushengqwer/go-common/response"
 	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/file"
 	service "github.com/Xushengqwer/user_hub/service/profile"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
@@ -59,8 +62,11 @@ func NewUserProfileController(
 // @Produce json
 // @Param body body dto.UpdateProfileDTO true "包含待更新字段的资料信息（不含头像URL）"
 // @Success 200 {object} docs.SwaggerAPIProfileVOResponse "资料更新成功，返回更新后的资料信息"
+// @Header 200 {string} X-Resource-Changed "本次请求是否实际修改了数据 (true/false)；为 false 时消息为“无变更”"
 // @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误)"
 // @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "修改的字段属于敏感字段，需先校验当前密码重新验证身份"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "修改过于频繁，处于冷却时间内 (响应头 Retry-After 给出剩余秒数)"
 // @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库操作失败或用户资料不存在)"
 // @Router /api/v1/user-hub/profile [put]
 func (ctrl *UserProfileController) UpdateProfileHandler(c *gin.Context) {
@@ -90,8 +96,15 @@ func (ctrl *UserProfileController) UpdateProfileHandler(c *gin.Context) {
 		return
 	}
 
-	profileVO, err := ctrl.profileService.UpdateProfile(c.Request.Context(), userID, &updateProfileDTO)
+	profileVO, changed, err := ctrl.profileService.UpdateProfile(c.Request.Context(), userID, &updateProfileDTO)
 	if err != nil {
+		if ctrl.respondCooldownError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrReverificationRequired) {
+			response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, err.Error())
+			return
+		}
 		// 根据您的要求，如果服务层返回 "要更新的用户资料不存在"，则视为服务器内部错误
 		if err.Error() == "要更新的用户资料不存在" || err.Error() == "无效的性别值" { // 也处理服务层可能返回的性别校验错误
 			ctrl.logger.Error("更新用户资料时发生内部错误或数据校验问题",
@@ -111,8 +124,9 @@ func (ctrl *UserProfileController) UpdateProfileHandler(c *gin.Context) {
 	ctrl.logger.Info("成功更新用户资料",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
+		zap.Bool("changed", changed),
 	)
-	response.RespondSuccess(c, profileVO, "资料更新成功")
+	respondUpdateResult(c, profileVO, changed, "资料更新成功")
 }
 
 // UploadAvatarHandler 处理用户头像上传的请求。
@@ -123,8 +137,10 @@ func (ctrl *UserProfileController) UpdateProfileHandler(c *gin.Context) {
 // @Produce json
 // @Param avatar formData file true "头像文件 (multipart/form-data key: 'avatar')"
 // @Success 200 {object} response.APIResponse[map[string]string] "头像上传成功，返回包含新头像URL的map"
-// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求无效 (如文件过大、类型不支持、未提供文件)"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求无效 (如文件过大、类型不支持、图片尺寸超出范围、未提供文件)"
 // @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 409 {object} docs.SwaggerAPIErrorResponseString "该用户已有头像上传正在处理"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "修改过于频繁，处于冷却时间内 (响应头 Retry-After 给出剩余秒数)"
 // @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如文件上传到COS失败、数据库更新失败)"
 // @Router /api/v1/user-hub/profile/avatar [post]
 func (ctrl *UserProfileController) UploadAvatarHandler(c *gin.Context) {
@@ -160,15 +176,7 @@ func (ctrl *UserProfileController) UploadAvatarHandler(c *gin.Context) {
 
 	newAvatarURL, err := ctrl.profileService.UploadAndSetAvatar(c.Request.Context(), userID, header.Filename, file, header.Size)
 	if err != nil {
-		// 根据服务层返回的错误类型进行处理
-		// 假设 ErrCodeThirdPartyServiceError = 50004
-		if errors.Is(err, commonerrors.ErrThirdPartyServiceError) { // 检查是否为第三方服务错误
-			ctrl.logger.Error("服务层报告腾讯云COS服务失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
-			response.RespondError(c, http.StatusBadGateway, response.ErrCodeThirdPartyServiceError, "头像上传服务暂时不可用，请稍后重试") // 使用 502 Bad Gateway 可能更合适
-		} else if errors.Is(err, commonerrors.ErrSystemError) { // 其他系统内部错误（例如服务层返回 "用户不存在或用户资料未初始化"，但我们已将其归为内部错误）
-			ctrl.logger.Error("服务层报告系统内部错误", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
-			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, "上传头像失败，请稍后重试")
-		}
+		ctrl.respondAvatarError(c, operation, userID, err)
 		return
 	}
 
@@ -180,6 +188,209 @@ func (ctrl *UserProfileController) UploadAvatarHandler(c *gin.Context) {
 	response.RespondSuccess(c, map[string]string{"avatar_url": newAvatarURL}, "头像上传成功")
 }
 
+// UploadAvatarFromURLHandler 处理从远程 URL 设置头像的请求。
+// @Summary 通过图片链接设置我的头像
+// @Description 服务端拉取指定的图片链接作为当前用户的头像，适用于只持有图片 URL 的集成方。拉取带有超时、大小与类型限制，并拒绝指向内网/保留地址的链接。校验规则与文件上传一致。
+// @Tags 资料管理 (Profile Management)
+// @Accept json
+// @Produce json
+// @Param body body dto.AvatarFromURLDTO true "远程图片地址"
+// @Success 200 {object} response.APIResponse[map[string]string] "头像设置成功，返回包含新头像URL的map"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求无效 (如链接无效、指向内网地址、内容不是图片、文件过大、尺寸超出范围)"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 409 {object} docs.SwaggerAPIErrorResponseString "该用户已有头像上传正在处理"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "修改过于频繁，处于冷却时间内 (响应头 Retry-After 给出剩余秒数)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如文件上传到COS失败、数据库更新失败)"
+// @Router /api/v1/user-hub/profile/avatar/from-url [post]
+func (ctrl *UserProfileController) UploadAvatarFromURLHandler(c *gin.Context) {
+	const operation = "UserProfileController.UploadAvatarFromURLHandler"
+
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于头像设置", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	var req dto.AvatarFromURLDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("通过链接设置头像请求参数绑定失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请提供有效的图片链接")
+		return
+	}
+
+	newAvatarURL, err := ctrl.profileService.UploadAvatarFromURL(c.Request.Context(), userID, req.URL)
+	if err != nil {
+		ctrl.respondAvatarError(c, operation, userID, err)
+		return
+	}
+
+	ctrl.logger.Info("通过链接设置头像成功",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.String("newAvatarURL", newAvatarURL),
+	)
+	response.RespondSuccess(c, map[string]string{"avatar_url": newAvatarURL}, "头像上传成功")
+}
+
+// UploadAvatarBase64Handler 处理以 base64 data URI 上传头像的请求。
+// @Summary 以 base64 上传我的头像
+// @Description 当前认证用户以 data URI (如 canvas 导出的 "data:image/png;base64,...") 上传头像，适用于移动端等无法直接提交文件的场景。大小、类型与尺寸限制与文件上传一致。
+// @Tags 资料管理 (Profile Management)
+// @Accept json
+// @Produce json
+// @Param body body dto.AvatarBase64DTO true "图片的 data URI"
+// @Success 200 {object} response.APIResponse[map[string]string] "头像上传成功，返回包含新头像URL的map"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求无效 (如 data URI 格式错误、类型不支持、文件过大、尺寸超出范围)"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 409 {object} docs.SwaggerAPIErrorResponseString "该用户已有头像上传正在处理"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "修改过于频繁，处于冷却时间内 (响应头 Retry-After 给出剩余秒数)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如文件上传到COS失败、数据库更新失败)"
+// @Router /api/v1/user-hub/profile/avatar/base64 [post]
+func (ctrl *UserProfileController) UploadAvatarBase64Handler(c *gin.Context) {
+	const operation = "UserProfileController.UploadAvatarBase64Handler"
+
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于头像上传", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	var req dto.AvatarBase64DTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("base64 头像上传请求参数绑定失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请提供头像的 data URI")
+		return
+	}
+
+	newAvatarURL, err := ctrl.profileService.UploadAvatarFromBase64(c.Request.Context(), userID, req.Data)
+	if err != nil {
+		ctrl.respondAvatarError(c, operation, userID, err)
+		return
+	}
+
+	ctrl.logger.Info("base64 头像上传并设置成功",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.String("newAvatarURL", newAvatarURL),
+	)
+	response.RespondSuccess(c, map[string]string{"avatar_url": newAvatarURL}, "头像上传成功")
+}
+
+// UpdateProfileWithAvatarHandler 处理以 multipart/form-data 同时更新资料字段与头像的请求。
+// @Summary 更新我的资料和头像 (表单)
+// @Description 当前认证用户在一次请求中提交资料字段和可选的头像文件，资料与头像作为整体生效，避免分两次调用时出现部分成功。原有的 PUT /profile 与 POST /profile/avatar 接口保持可用。
+// @Tags 资料管理 (Profile Management)
+// @Accept multipart/form-data
+// @Produce json
+// @Param nickname formData string false "昵称"
+// @Param gender formData int false "性别（0=未知, 1=男, 2=女）"
+// @Param province formData string false "省份"
+// @Param city formData string false "城市"
+// @Param avatar formData file false "头像文件 (可选)"
+// @Success 200 {object} docs.SwaggerAPIProfileVOResponse "更新成功，返回更新后的资料信息"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求无效 (如字段值无效、文件过大、类型不支持、图片尺寸超出范围)"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "修改的字段属于敏感字段，需先校验当前密码重新验证身份"
+// @Failure 404 {object} docs.SwaggerAPIErrorResponseString "用户资料不存在"
+// @Failure 409 {object} docs.SwaggerAPIErrorResponseString "该用户已有头像上传正在处理"
+// @Failure 429 {object} docs.SwaggerAPIErrorResponseString "修改过于频繁，处于冷却时间内 (响应头 Retry-After 给出剩余秒数)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库更新失败)"
+// @Failure 502 {object} docs.SwaggerAPIErrorResponseString "头像上传到COS失败"
+// @Router /api/v1/user-hub/profile/with-avatar [put]
+func (ctrl *UserProfileController) UpdateProfileWithAvatarHandler(c *gin.Context) {
+	const operation = "UserProfileController.UpdateProfileWithAvatarHandler"
+
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于更新资料", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	// 1. 绑定表单中的资料字段
+	var updateProfileDTO dto.UpdateProfileDTO
+	if err := c.ShouldBind(&updateProfileDTO); err != nil {
+		ctrl.logger.Warn("更新资料表单参数绑定失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效: "+err.Error())
+		return
+	}
+
+	// 2. 头像文件可选，未提供时只更新资料字段
+	var (
+		fileName   string
+		fileSize   int64
+		fileReader io.Reader
+	)
+	file, header, err := c.Request.FormFile("avatar")
+	switch {
+	case err == nil:
+		defer file.Close()
+		fileName, fileSize, fileReader = header.Filename, header.Size, file
+	case errors.Is(err, http.ErrMissingFile):
+	default:
+		ctrl.logger.Warn("获取上传文件失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "无法读取上传的文件: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层一次性完成头像上传与资料更新
+	profileVO, err := ctrl.profileService.UpdateProfileWithAvatar(c.Request.Context(), userID, &updateProfileDTO, fileName, fileReader, fileSize)
+	if err != nil {
+		if err.Error() == "要更新的用户资料不存在" {
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+			return
+		}
+		ctrl.respondAvatarError(c, operation, userID, err)
+		return
+	}
+
+	ctrl.logger.Info("成功更新用户资料及头像",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.Bool("avatarUpdated", fileReader != nil),
+	)
+	response.RespondSuccess(c, profileVO, "资料更新成功")
+}
+
+// respondAvatarError 将头像上传相关的服务层错误映射为 HTTP 响应，供各头像上传入口共用
+func (ctrl *UserProfileController) respondAvatarError(c *gin.Context, operation string, userID string, err error) {
+	if ctrl.respondCooldownError(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrReverificationRequired) {
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, commonerrors.ErrThirdPartyServiceError) {
+		ctrl.logger.Error("服务层报告腾讯云COS服务失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		respondUpstreamError(c, http.StatusBadGateway, response.ErrCodeThirdPartyServiceError, "头像上传服务暂时不可用，请稍后重试", err)
+	} else if errors.Is(err, commonerrors.ErrSystemError) {
+		ctrl.logger.Error("服务层报告系统内部错误", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, "上传头像失败，请稍后重试")
+	} else if errors.Is(err, service.ErrAvatarUploadInProgress) {
+		response.RespondError(c, http.StatusConflict, response.ErrCodeClientInvalidInput, err.Error())
+	} else if errors.Is(err, file.ErrStorageQuotaExceeded) {
+		ctrl.logger.Warn("头像上传超出存储配额", zap.String("operation", operation), zap.String("userID", userID))
+		response.RespondError(c, http.StatusRequestEntityTooLarge, response.ErrCodeClientInvalidInput, err.Error())
+	} else { // 其他视为业务错误，例如图片无法解码、尺寸超出范围
+		ctrl.logger.Warn("头像上传业务校验失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+	}
+}
+
+// respondCooldownError 资料或头像处于修改冷却中时返回 429 并设置 Retry-After，已处理时返回 true
+func (ctrl *UserProfileController) respondCooldownError(c *gin.Context, err error) bool {
+	var cooldownErr *service.ProfileCooldownError
+	if !errors.As(err, &cooldownErr) {
+		return false
+	}
+	setRetryAfter(c, cooldownErr.RetryAfter)
+	response.RespondError(c, http.StatusTooManyRequests, response.ErrCodeClientRateLimitExceeded, cooldownErr.Error())
+	return true
+}
+
 // GetMyProfileHandler 处理当前认证用户获取自己账户聚合信息的请求。
 // @Summary 获取我的账户详情 (核心信息 + 资料)
 // @Description 获取当前认证用户的核心账户信息（如角色、状态）和详细个人资料（如昵称、头像）。
@@ -230,6 +441,56 @@ func (ctrl *UserProfileController) GetMyProfileHandler(c *gin.Context) {
 	response.RespondSuccess(c, accountDetailVO, "获取账户详情成功")
 }
 
+// DeleteProfileHandler 处理管理员删除指定用户资料的请求。
+// @Summary 删除用户资料 (管理员)
+// @Description 管理员删除指定用户的资料记录（昵称、头像、性别、地区等），核心用户和身份记录不受影响。
+// @Tags 资料管理 (Profile Management)
+// @Produce json
+// @Param userID path string true "要删除资料的用户ID"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "用户资料删除成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空)"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Failure 404 {object} docs.SwaggerAPIErrorResponseString "指定用户的资料不存在"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库操作失败)"
+// @Router /api/v1/user-hub/users/{userID}/profile [delete]
+func (ctrl *UserProfileController) DeleteProfileHandler(c *gin.Context) {
+	const operation = "UserProfileController.DeleteProfileHandler"
+
+	// 1. 删除他人资料仅对管理员开放
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试删除用户资料", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可删除用户资料")
+		return
+	}
+
+	// 2. 获取并校验路径参数 userID
+	userID := c.Param("userID")
+	if userID == "" {
+		ctrl.logger.Warn("删除用户资料请求的用户ID为空", zap.String("operation", operation))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "用户 ID 不能为空")
+		return
+	}
+
+	// 3. 调用服务层删除资料
+	if err := ctrl.profileService.DeleteProfile(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		} else if err.Error() == "要删除的用户资料不存在" {
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+		} else {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		}
+		return
+	}
+
+	// 4. 返回成功响应
+	ctrl.logger.Info("成功删除用户资料",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+	)
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "用户资料删除成功")
+}
+
 // RegisterRoutes 注册与用户资料管理相关的路由到指定的 Gin 路由组。
 // 设计目的:
 //   - 将此控制器的所有API端点集中定义和注册。
@@ -247,12 +508,29 @@ func (ctrl *UserProfileController) RegisterRoutes(group *gin.RouterGroup) {
 		// 场景：用户（包括普通用户和管理员）修改自己的资料
 		profileRoutes.PUT("", ctrl.UpdateProfileHandler)
 
+		// 以表单同时更新资料字段和头像
+		// 场景：资料编辑页一次提交，避免分两次调用时的部分失败
+		profileRoutes.PUT("/with-avatar", ctrl.UpdateProfileWithAvatarHandler)
+
 		// 用户上传自己的头像
 		// 场景：包含用户和管理员都可以
 		profileRoutes.POST("/avatar", ctrl.UploadAvatarHandler) // 上传我的头像
 
+		// 通过图片链接设置我的头像
+		// 场景：集成方只持有图片 URL，由服务端拉取后走与文件上传相同的校验流程
+		profileRoutes.POST("/avatar/from-url", ctrl.UploadAvatarFromURLHandler)
+
+		// 以 base64 data URI 上传我的头像
+		// 场景：移动端/前端 canvas 只持有 base64 图片数据
+		profileRoutes.POST("/avatar/base64", ctrl.UploadAvatarBase64Handler)
+
 		// 处理当前认证用户获取自己账户聚合信息的请求
 		// 场景： 前端需要使用这个加载用户头像，个人信息
 		profileRoutes.GET("", ctrl.GetMyProfileHandler) // 修改为调用 GetMyProfileHandler
 	}
+
+	// 管理员删除指定用户的资料
+	// 场景：清理违规资料，核心用户与身份保持不变
+	// 完整路径: /user-hub/api/v1/users/:userID/profile
+	group.DELETE("/users/:userID/profile", ctrl.DeleteProfileHandler)
 }
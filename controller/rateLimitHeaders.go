@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 限流相关的响应头，所有限流接口统一使用，便于客户端在触发 429 之前自行退避
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"     // 当前窗口内允许的请求数
+	headerRateLimitRemaining = "X-RateLimit-Remaining" // 当前窗口内剩余的请求数
+	headerRateLimitReset     = "X-RateLimit-Reset"     // 距窗口重置的秒数
+	headerRetryAfter         = "Retry-After"           // 被限流时建议的重试等待秒数
+)
+
+// setRateLimitHeaders 输出 X-RateLimit-Limit / Remaining / Reset 响应头
+// - Reset 为距窗口重置的秒数 (向上取整)，与 Retry-After 的单位保持一致。
+func setRateLimitHeaders(c *gin.Context, limit, remaining int, reset time.Duration) {
+	c.Header(headerRateLimitLimit, strconv.Itoa(limit))
+	c.Header(headerRateLimitRemaining, strconv.Itoa(max(remaining, 0)))
+	c.Header(headerRateLimitReset, strconv.Itoa(ceilSeconds(reset)))
+}
+
+// setRetryAfter 在返回 429 时输出 Retry-After 响应头 (秒，向上取整)
+func setRetryAfter(c *gin.Context, wait time.Duration) {
+	c.Header(headerRetryAfter, strconv.Itoa(ceilSeconds(wait)))
+}
+
+// ceilSeconds 将时长向上取整为秒，负数按 0 处理
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
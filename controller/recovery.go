@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/recovery"
+)
+
+// AccountRecoveryController 处理通过次要已验证身份找回账号的 HTTP 请求。
+// - 这些接口无需登录：用户正是因为无法登录才需要找回，身份由验证码或找回凭证证明。
+type AccountRecoveryController struct {
+	recoveryService recovery.AccountRecoveryService // recoveryService: 账号找回服务。
+	logger          *core.ZapLogger                 // logger: 日志记录器。
+}
+
+// NewAccountRecoveryController 创建一个新的 AccountRecoveryController 实例。
+func NewAccountRecoveryController(
+	recoveryService recovery.AccountRecoveryService,
+	logger *core.ZapLogger,
+) *AccountRecoveryController {
+	return &AccountRecoveryController{
+		recoveryService: recoveryService,
+		logger:          logger,
+	}
+}
+
+// StartRecoveryHandler 处理发起账号找回的请求。
+// @Summary 发起账号找回
+// @Description 使用一个已验证的次要身份（手机号 + 短信验证码，或微信登录 code）证明账号归属，成功后返回短期一次性找回凭证。找回凭证不是访问令牌，只能用于重置密码或添加新的账号密码登录方式。每次尝试都会记录审计日志。
+// @Tags 账号管理 (Account Lifecycle)
+// @Accept json
+// @Produce json
+// @Param body body dto.StartRecoveryDTO true "次要身份及其验证码"
+// @Success 200 {object} docs.SwaggerAPIRecoveryTokenResponse "验证通过，返回找回凭证"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 或 身份验证失败"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/recovery/start [post]
+func (ctrl *AccountRecoveryController) StartRecoveryHandler(c *gin.Context) {
+	const operation = "AccountRecoveryController.StartRecoveryHandler"
+
+	var req dto.StartRecoveryDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("发起账号找回请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请求数据无效")
+		return
+	}
+
+	tokenVO, err := ctrl.recoveryService.StartRecovery(c.Request.Context(), req)
+	if err != nil {
+		ctrl.respondRecoveryError(c, err)
+		return
+	}
+	response.RespondSuccess(c, tokenVO, "身份验证成功，请在有效期内完成找回")
+}
+
+// ResetPasswordHandler 处理使用找回凭证重置密码的请求。
+// @Summary 找回账号：重置密码
+// @Description 使用找回凭证重置账号密码登录方式的密码。新密码不能与最近使用过的密码相同；成功后凭证失效。
+// @Tags 账号管理 (Account Lifecycle)
+// @Accept json
+// @Produce json
+// @Param body body dto.RecoveryResetPasswordDTO true "找回凭证及新密码"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "密码重置成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 或 凭证无效/已过期、未设置账号密码登录方式、新密码与最近使用过的密码相同"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/recovery/reset-password [post]
+func (ctrl *AccountRecoveryController) ResetPasswordHandler(c *gin.Context) {
+	const operation = "AccountRecoveryController.ResetPasswordHandler"
+
+	var req dto.RecoveryResetPasswordDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("找回重置密码请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请求数据无效")
+		return
+	}
+
+	if err := ctrl.recoveryService.ResetPassword(c.Request.Context(), req); err != nil {
+		ctrl.respondRecoveryError(c, err)
+		return
+	}
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "密码重置成功，请使用新密码登录")
+}
+
+// AddIdentityHandler 处理使用找回凭证添加账号密码登录方式的请求。
+// @Summary 找回账号：添加账号密码登录方式
+// @Description 对尚未设置账号密码登录方式的用户，使用找回凭证添加一个新的账号密码登录方式；成功后凭证失效。
+// @Tags 账号管理 (Account Lifecycle)
+// @Accept json
+// @Produce json
+// @Param body body dto.RecoveryAddIdentityDTO true "找回凭证及新的账号密码"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "登录方式添加成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 或 凭证无效/已过期、已设置账号密码登录方式、账号已存在"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/recovery/add-identity [post]
+func (ctrl *AccountRecoveryController) AddIdentityHandler(c *gin.Context) {
+	const operation = "AccountRecoveryController.AddIdentityHandler"
+
+	var req dto.RecoveryAddIdentityDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("找回添加登录方式请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请求数据无效")
+		return
+	}
+
+	if err := ctrl.recoveryService.AddPasswordIdentity(c.Request.Context(), req); err != nil {
+		ctrl.respondRecoveryError(c, err)
+		return
+	}
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "登录方式添加成功，请使用新账号登录")
+}
+
+// respondRecoveryError 将找回服务返回的错误映射为 HTTP 响应：系统错误 500，其余视为业务错误 400。
+func (ctrl *AccountRecoveryController) respondRecoveryError(c *gin.Context, err error) {
+	if errors.Is(err, commonerrors.ErrSystemError) {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+}
+
+// RegisterRoutes 注册账号找回相关的路由。
+func (ctrl *AccountRecoveryController) RegisterRoutes(group *gin.RouterGroup) {
+	recoveryRoutes := group.Group("/account/recovery")
+	{
+		// 发起找回
+		// 场景：用户无法使用主登录方式，凭已验证的手机号或微信证明账号归属
+		recoveryRoutes.POST("/start", ctrl.StartRecoveryHandler)
+
+		// 使用找回凭证重置密码
+		// 场景：忘记密码
+		recoveryRoutes.POST("/reset-password", ctrl.ResetPasswordHandler)
+
+		// 使用找回凭证添加账号密码登录方式
+		// 场景：原主登录方式（如某个微信号）不可用，且账号尚未设置账号密码
+		recoveryRoutes.POST("/add-identity", ctrl.AddIdentityHandler)
+	}
+}
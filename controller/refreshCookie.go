@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// Web 平台的刷新令牌只存在于 HttpOnly Cookie 中，任何 JSON 响应体都不得携带。
+// 所有签发令牌的接口都通过 deliverRefreshToken 交付刷新令牌，并在写出响应前调用 guardWebRefreshToken 做最后检查。
+
+// setRefreshTokenCookie 将刷新令牌写入 HttpOnly Cookie，maxAge 取自对应平台的刷新令牌有效期
+func setRefreshTokenCookie(c *gin.Context, cfg config.CookieConfig, refreshToken string, maxAge time.Duration) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cfg.RefreshTokenName,
+		Value:    refreshToken,
+		MaxAge:   int(maxAge.Seconds()),
+		Path:     cfg.Path,
+		Domain:   cfg.Domain,
+		Secure:   cfg.Secure,
+		HttpOnly: cfg.HttpOnly,
+		SameSite: utils.ParseSameSiteString(cfg.SameSite),
+	})
+}
+
+// clearRefreshTokenCookie 让浏览器立即删除刷新令牌 Cookie
+func clearRefreshTokenCookie(c *gin.Context, cfg config.CookieConfig) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cfg.RefreshTokenName,
+		Value:    "",
+		MaxAge:   -1, // Expire immediately
+		Path:     cfg.Path,
+		Domain:   cfg.Domain,
+		Secure:   cfg.Secure,
+		HttpOnly: cfg.HttpOnly,
+		SameSite: utils.ParseSameSiteString(cfg.SameSite),
+	})
+}
+
+// deliverRefreshToken 按平台交付刷新令牌：Web 平台写入 Cookie 并从 pair 中移除，其他平台保留在 JSON 中
+func deliverRefreshToken(c *gin.Context, cfg config.CookieConfig, platform enums.Platform, pair *vo.TokenPair, maxAge time.Duration) {
+	if platform != enums.PlatformWeb {
+		return
+	}
+	setRefreshTokenCookie(c, cfg, pair.RefreshToken, maxAge)
+	pair.RefreshToken = ""
+}
+
+// guardWebRefreshToken 写出响应前的最后检查：Web 平台的响应体中出现刷新令牌说明交付流程有缺陷。
+// - strip (默认): 记录错误日志，移除刷新令牌后继续响应。
+// - fail: 记录错误日志并以 500 结束请求，便于在测试环境中尽早暴露问题。
+// 返回 false 表示已写入错误响应，调用方应直接返回。
+func guardWebRefreshToken(c *gin.Context, cfg config.CookieConfig, platform enums.Platform, pair *vo.TokenPair, logger *core.ZapLogger, operation string) bool {
+	if platform != enums.PlatformWeb || pair.RefreshToken == "" {
+		return true
+	}
+	logger.Error("Web 平台响应体中出现刷新令牌，已拦截", zap.String("operation", operation), zap.String("guardMode", cfg.RefreshTokenGuardOrDefault()))
+	if cfg.RefreshTokenGuardOrDefault() == config.RefreshTokenGuardFail {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, "系统内部错误")
+		return false
+	}
+	pair.RefreshToken = ""
+	return true
+}
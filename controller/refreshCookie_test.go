@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/login/auth"
+	"github.com/Xushengqwer/user_hub/service/token"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// fakeAccountService 内嵌接口，仅实现登录，始终返回同时包含访问令牌与刷新令牌的令牌对
+type fakeAccountService struct {
+	auth.AccountService
+}
+
+func (fakeAccountService) Login(_ context.Context, _ dto.AccountLoginData, _ enums.Platform) (vo.Userinfo, vo.TokenPair, error) {
+	return vo.Userinfo{UserID: "u1"}, vo.TokenPair{AccessToken: "new-access", RefreshToken: "new-refresh"}, nil
+}
+
+// fakeTokenService 内嵌接口，仅实现刷新令牌，记录收到的刷新令牌
+type fakeTokenService struct {
+	token.AuthTokenService
+	received string
+}
+
+func (s *fakeTokenService) RefreshToken(_ context.Context, refreshToken string) (vo.TokenPair, error) {
+	s.received = refreshToken
+	return vo.TokenPair{AccessToken: "new-access", RefreshToken: "new-refresh"}, nil
+}
+
+// fakeJWT 内嵌接口，仅实现刷新令牌有效期
+type fakeJWT struct {
+	dependencies.JWTTokenInterface
+}
+
+func (fakeJWT) RefreshTokenTTL(_ enums.Platform) time.Duration {
+	return time.Hour
+}
+
+// testCookieConfig 返回拦截模式为 fail 的 Cookie 配置：Web 响应体中一旦出现刷新令牌即返回 500
+func testCookieConfig() config.CookieConfig {
+	return config.CookieConfig{
+		RefreshTokenName:  "refresh_token",
+		Path:              "/",
+		HttpOnly:          true,
+		RefreshTokenGuard: config.RefreshTokenGuardFail,
+	}
+}
+
+// performRequest 以指定平台发送请求，cookie 不为空时附带刷新令牌 Cookie
+func performRequest(r http.Handler, path string, platform string, body string, cookie string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if platform != "" {
+		req.Header.Set("X-Platform", platform)
+	}
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: "refresh_token", Value: cookie})
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decodeTokenPair 从统一响应中取出令牌对 (登录接口位于 data.token，刷新接口位于 data)
+func decodeTokenPair(t *testing.T, body []byte, nested bool) map[string]any {
+	t.Helper()
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("解析响应失败: %v，响应体: %s", err, body)
+	}
+	if !nested {
+		return resp.Data
+	}
+	pair, ok := resp.Data["token"].(map[string]any)
+	if !ok {
+		t.Fatalf("响应中缺少 data.token，响应体: %s", body)
+	}
+	return pair
+}
+
+// jsonString 将字符串编码为 JSON 字面量，便于拼接包含控制字符的请求体
+func jsonString(t *testing.T, s string) string {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("编码 JSON 字符串失败: %v", err)
+	}
+	return string(b)
+}
+
+// refreshCookieValue 返回响应中写入的刷新令牌 Cookie 值
+func refreshCookieValue(w *httptest.ResponseRecorder) string {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "refresh_token" {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+func newRefreshCookieTestRouter(t *testing.T) (*gin.Engine, *fakeTokenService) {
+	t.Helper()
+	logger := newTestLogger(t)
+	tokenService := &fakeTokenService{}
+	accountCtrl := NewAccountController(fakeAccountService{}, fakeJWT{}, logger, testCookieConfig())
+	tokenCtrl := NewAuthTokenController(tokenService, fakeJWT{}, logger, testCookieConfig(), nil, nil, config.BatchLimitConfig{})
+
+	r := gin.New()
+	r.POST("/account/login", accountCtrl.LoginHandler)
+	r.POST("/auth/refresh-token", tokenCtrl.RefreshToken)
+	return r, tokenService
+}
+
+func TestWebResponsesNeverContainRefreshToken(t *testing.T) {
+	r, tokenService := newRefreshCookieTestRouter(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		cookie string
+		nested bool
+	}{
+		{name: "账号登录", path: "/account/login", body: `{"account":"alice","password":"secret"}`, nested: true},
+		{name: "刷新令牌", path: "/auth/refresh-token", cookie: "old-refresh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(r, tt.path, string(enums.PlatformWeb), tt.body, tt.cookie)
+			if w.Code != http.StatusOK {
+				t.Fatalf("状态码 = %d，期望 200 (guard=fail 时响应体带刷新令牌会返回 500)，响应体: %s", w.Code, w.Body.String())
+			}
+			pair := decodeTokenPair(t, w.Body.Bytes(), tt.nested)
+			if _, ok := pair["refresh_token"]; ok {
+				t.Errorf("Web 响应体中不应出现 refresh_token: %s", w.Body.String())
+			}
+			if strings.Contains(w.Body.String(), "new-refresh") {
+				t.Errorf("Web 响应体中不应出现刷新令牌的值: %s", w.Body.String())
+			}
+			if pair["access_token"] != "new-access" {
+				t.Errorf("access_token = %v，期望 new-access", pair["access_token"])
+			}
+			if got := refreshCookieValue(w); got != "new-refresh" {
+				t.Errorf("刷新令牌 Cookie = %q，期望 new-refresh", got)
+			}
+		})
+	}
+	if tokenService.received != "old-refresh" {
+		t.Errorf("刷新接口应从 Cookie 读取刷新令牌，实际收到 %q", tokenService.received)
+	}
+}
+
+func TestNonWebResponsesKeepRefreshTokenInBody(t *testing.T) {
+	r, _ := newRefreshCookieTestRouter(t)
+
+	w := performRequest(r, "/auth/refresh-token", string(enums.PlatformApp), `{"refresh_token":"old-refresh"}`, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("状态码 = %d，期望 200，响应体: %s", w.Code, w.Body.String())
+	}
+	if pair := decodeTokenPair(t, w.Body.Bytes(), false); pair["refresh_token"] != "new-refresh" {
+		t.Errorf("非 Web 平台的刷新令牌应随 JSON 返回，响应体: %s", w.Body.String())
+	}
+	if got := refreshCookieValue(w); got != "" {
+		t.Errorf("非 Web 平台不应写入刷新令牌 Cookie，实际为 %q", got)
+	}
+}
+
+func TestGuardWebRefreshToken(t *testing.T) {
+	tests := []struct {
+		name         string
+		guard        string
+		platform     enums.Platform
+		wantContinue bool
+		wantStatus   int
+		wantToken    string
+	}{
+		{name: "fail 模式拦截 Web 响应", guard: config.RefreshTokenGuardFail, platform: enums.PlatformWeb, wantContinue: false, wantStatus: http.StatusInternalServerError, wantToken: "leaked"},
+		{name: "strip 模式移除后继续", guard: config.RefreshTokenGuardStrip, platform: enums.PlatformWeb, wantContinue: true, wantToken: ""},
+		{name: "非 Web 平台不拦截", guard: config.RefreshTokenGuardFail, platform: enums.PlatformApp, wantContinue: true, wantToken: "leaked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			cfg := testCookieConfig()
+			cfg.RefreshTokenGuard = tt.guard
+			pair := vo.TokenPair{AccessToken: "access", RefreshToken: "leaked"}
+
+			if got := guardWebRefreshToken(c, cfg, tt.platform, &pair, newTestLogger(t), "test"); got != tt.wantContinue {
+				t.Fatalf("guardWebRefreshToken 返回 %v，期望 %v", got, tt.wantContinue)
+			}
+			if pair.RefreshToken != tt.wantToken {
+				t.Errorf("RefreshToken = %q，期望 %q", pair.RefreshToken, tt.wantToken)
+			}
+			if tt.wantStatus != 0 && w.Code != tt.wantStatus {
+				t.Errorf("状态码 = %d，期望 %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
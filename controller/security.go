@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/service/security"
+)
+
+// AccountSecurityController 处理账号安全概览相关的 HTTP 请求。
+type AccountSecurityController struct {
+	securityService security.AccountSecurityService // securityService: 账号安全概览服务。
+	logger          *core.ZapLogger                 // logger: 日志记录器。
+}
+
+// NewAccountSecurityController 创建一个新的 AccountSecurityController 实例。
+func NewAccountSecurityController(securityService security.AccountSecurityService, logger *core.ZapLogger) *AccountSecurityController {
+	return &AccountSecurityController{
+		securityService: securityService,
+		logger:          logger,
+	}
+}
+
+// GetSecurityOverviewHandler 处理当前用户获取账号安全概览的请求。
+// @Summary 获取我的账号安全概览
+// @Description 账号安全页使用：一次返回已绑定的登录方式 (脱敏)、当前有效会话数、最近登录时间与方式以及近期安全事件。用户ID取自网关透传的认证信息；结果在服务端短暂缓存 (约 30 秒)。
+// @Tags 账号管理 (Account Lifecycle)
+// @Produce json
+// @Success 200 {object} docs.SwaggerAPISecurityOverviewResponse "获取成功"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 404 {object} docs.SwaggerAPIErrorResponseString "用户不存在"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/security [get]
+func (ctrl *AccountSecurityController) GetSecurityOverviewHandler(c *gin.Context) {
+	const operation = "AccountSecurityController.GetSecurityOverviewHandler"
+
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于查询安全概览", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	overview, err := ctrl.securityService.GetSecurityOverview(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, security.ErrUserNotFound) {
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+		} else {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		}
+		return
+	}
+	response.RespondSuccess(c, *overview, "获取账号安全概览成功")
+}
+
+// GetActivePlatformsHandler 处理当前用户查询已登录平台的请求。
+// @Summary 获取我当前已登录的平台
+// @Description 返回当前用户至少有一个有效会话的平台 (web、app、wechat) 及各平台的会话数，用于“你已在网页端和 App 上登录”一类的提示。数据实时读取，不经过缓存；未启用刷新令牌白名单时 platforms 与 total_sessions 为 null。
+// @Tags 账号管理 (Account Lifecycle)
+// @Produce json
+// @Success 200 {object} docs.SwaggerAPIActivePlatformsResponse "获取成功"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Router /api/v1/user-hub/account/active-platforms [get]
+func (ctrl *AccountSecurityController) GetActivePlatformsHandler(c *gin.Context) {
+	const operation = "AccountSecurityController.GetActivePlatformsHandler"
+
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于查询已登录平台", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	platforms, err := ctrl.securityService.GetActivePlatforms(c.Request.Context(), userID)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	response.RespondSuccess(c, *platforms, "获取已登录平台成功")
+}
+
+// RegisterRoutes 注册账号安全相关的路由到指定的 Gin 路由组。
+// 参数:
+//   - group: Gin 的路由组实例。
+func (ctrl *AccountSecurityController) RegisterRoutes(group *gin.RouterGroup) {
+	// 获取账号安全概览
+	// - 场景: 用户打开账号安全页。
+	// - 预期权限: 需要认证，只能查看自己的概览。
+	group.GET("/account/security", ctrl.GetSecurityOverviewHandler)
+
+	// 获取当前已登录的平台
+	// - 场景: 前端展示“你已在网页端和 App 上登录”。
+	// - 预期权限: 需要认证，只能查看自己的会话。
+	group.GET("/account/active-platforms", ctrl.GetActivePlatformsHandler)
+}
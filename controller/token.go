@@ -2,7 +2,10 @@ package controller
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/Xushengqwer/go-common/commonerrors" // 引入公共错误包
@@ -12,11 +15,11 @@ import (
 	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/constants"
 	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/middleware"
 	// "user_hub/docs" // 如果您的 linter/IDE 需要，可以导入 docs 包，swag 通常会自动处理
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/service/token" // 假设 service/token 包下有 AuthTokenService
-	"github.com/Xushengqwer/user_hub/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
 )
@@ -28,6 +31,9 @@ type AuthTokenController struct {
 	jwtUtil      dependencies.JWTTokenInterface // jwtUtil: JWT 工具，用于认证中间件。
 	logger       *core.ZapLogger                // logger: 日志记录器。
 	cookieConfig config.CookieConfig            // 新增：存储 Cookie 配置
+	degrade      *dependencies.RedisDegradation // degrade: Redis 降级统计，随 /metrics 导出。
+	inFlight     *middleware.InFlightLimiter    // inFlight: 在途请求数限制器，随 /metrics 导出；未启用时为 nil。
+	batchLimits  config.BatchLimitConfig        // batchLimits: 批量接口单次条目数上限。
 }
 
 // NewAuthTokenController 创建一个新的 AuthTokenController 实例。
@@ -39,6 +45,9 @@ type AuthTokenController struct {
 //   - jwtUtil: JWT工具实例。
 //   - logger: 日志记录器实例。
 //   - cookieCfg: Cookie 配置。
+//   - degrade: Redis 降级统计。
+//   - inFlight: 在途请求数限制器，未启用并发上限时传 nil。
+//   - batchLimits: 批量接口单次条目数上限配置。
 //
 // 返回:
 //   - *AuthTokenController: 初始化完成的控制器实例。
@@ -47,12 +56,18 @@ func NewAuthTokenController(
 	jwtUtil dependencies.JWTTokenInterface,
 	logger *core.ZapLogger, // 注入 logger
 	cookieCfg config.CookieConfig, // 新增：接收 Cookie 配置
+	degrade *dependencies.RedisDegradation,
+	inFlight *middleware.InFlightLimiter,
+	batchLimits config.BatchLimitConfig,
 ) *AuthTokenController {
 	return &AuthTokenController{
 		tokenService: tokenService,
 		jwtUtil:      jwtUtil,
 		logger:       logger,    // 存储 logger
 		cookieConfig: cookieCfg, // 存储 Cookie 配置
+		degrade:      degrade,
+		inFlight:     inFlight,
+		batchLimits:  batchLimits,
 	}
 }
 
@@ -98,16 +113,7 @@ func (ctrl *AuthTokenController) Logout(c *gin.Context) {
 
 	if platform == enums.PlatformWeb {
 		ctrl.logger.Info("Web平台退出登录，尝试清除RT Cookie", zap.String("operation", operation))
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     ctrl.cookieConfig.RefreshTokenName,
-			Value:    "",
-			MaxAge:   -1, // Expire immediately
-			Path:     ctrl.cookieConfig.Path,
-			Domain:   ctrl.cookieConfig.Domain,
-			Secure:   ctrl.cookieConfig.Secure,
-			HttpOnly: ctrl.cookieConfig.HttpOnly,
-			SameSite: utils.ParseSameSiteString(ctrl.cookieConfig.SameSite),
-		})
+		clearRefreshTokenCookie(c, ctrl.cookieConfig)
 	}
 	// 对于非 Web 平台，客户端应自行删除本地存储的 RT。
 	// 如果你的 tokenService.Logout 也依赖于从请求中获取RT来吊销（除了AT），
@@ -120,13 +126,14 @@ func (ctrl *AuthTokenController) Logout(c *gin.Context) {
 
 // RefreshToken 处理使用 Refresh Token 刷新认证令牌的请求。
 // @Summary 刷新令牌
-// @Description 使用有效的 Refresh Token 获取一对新的 Access Token 和 Refresh Token。支持从请求体或 Cookie 中获取 Refresh Token。
+// @Description 使用有效的 Refresh Token 获取一对新的 Access Token 和 Refresh Token。必须提供 X-Platform 请求头：Web 平台从 Cookie 中读取 Refresh Token，其余平台从请求体读取。
 // @Tags 认证管理 (Auth Management)
 // @Accept json
 // @Produce json
-// @Param request body dto.RefreshTokenRequest false "请求体 (可选)，包含 refresh_token 字段"
+// @Param X-Platform header string true "客户端平台类型" Enums(web, app, wechat)
+// @Param request body dto.RefreshTokenRequest false "请求体 (非 Web 平台必填)，包含 refresh_token 字段"
 // @Success 200 {object} docs.SwaggerAPITokenPairResponse "刷新成功，返回新的令牌对"
-// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数错误 (如未提供有效的 Refresh Token)"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数错误 (缺少或无法识别 X-Platform、Web 平台 Cookie 中缺少 Refresh Token、非 Web 平台请求体无效或缺少 refresh_token)"
 // @Failure 401 {object} docs.SwaggerAPIErrorResponseString "认证失败 (Refresh Token 无效、已过期、已被吊销或用户状态异常)"
 // @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库操作失败、令牌生成失败、Redis 操作失败)"
 // @Router /api/v1/user-hub/auth/refresh-token [post] // <--- 已更新路径
@@ -134,37 +141,45 @@ func (ctrl *AuthTokenController) RefreshToken(c *gin.Context) {
 	const operation = "AuthTokenController.RefreshToken"
 	var refreshTokenString string // 改为 refreshTokenString 以明确是字符串
 
-	// 1. 获取平台信息
+	// 1. 获取并校验平台信息：平台决定 RT 的来源，缺失或无法识别时直接拒绝，不再按默认平台猜测
 	platformStr := c.GetHeader("X-Platform")
+	if platformStr == "" {
+		ctrl.logger.Warn("刷新令牌请求缺少平台类型", zap.String("operation", operation))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "缺少 X-Platform 请求头")
+		return
+	}
 	platform, err := enums.PlatformFromString(platformStr) // 使用 go-common 的 enums
 	if err != nil {
-		// 如果平台头缺失或无效，可以根据你的策略决定是拒绝还是默认为某个平台（例如非 Web）
-		// 这里假设如果解析失败，则按非 Web 平台处理，尝试从 Body 读取
-		ctrl.logger.Warn("刷新令牌请求平台类型无效或缺失，尝试从Body读取RT", zap.String("operation", operation), zap.String("platformHeader", platformStr), zap.Error(err))
-		platform = enums.PlatformApp // 或其他非 Web 的默认值，或者直接报错
+		ctrl.logger.Warn("刷新令牌请求平台类型无效", zap.String("operation", operation), zap.String("platformHeader", platformStr), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, fmt.Sprintf("不支持的平台类型: %s", platformStr))
+		return
 	}
 
-	// 2. 根据平台获取 Refresh Token
+	// 2. 按平台从对应来源获取 Refresh Token：Web 平台只认 Cookie，其余平台只认请求体
 	if platform == enums.PlatformWeb {
 		cookieRT, err := c.Cookie(ctrl.cookieConfig.RefreshTokenName)
-		if err != nil {
+		if err != nil || cookieRT == "" {
 			ctrl.logger.Warn("Web平台刷新令牌请求：Cookie中未找到RT", zap.String("operation", operation), zap.Error(err))
-			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "未提供有效的刷新令牌")
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "Web 平台需通过 Cookie 提供刷新令牌")
 			return
 		}
 		refreshTokenString = cookieRT
 		ctrl.logger.Debug("从Cookie获取到Refresh Token (Web平台)", zap.String("operation", operation))
 	} else {
-		// 非 Web 平台 (App, WeChat, etc.)，尝试从请求体获取
+		// 非 Web 平台 (App, WeChat, etc.)，从请求体获取
 		var req dto.RefreshTokenRequest
-		if err := c.ShouldBindJSON(&req); err == nil && req.RefreshToken != "" {
-			refreshTokenString = req.RefreshToken
-			ctrl.logger.Debug("从请求体获取到Refresh Token (非Web平台)", zap.String("operation", operation))
-		} else {
-			ctrl.logger.Warn("非Web平台刷新令牌请求：请求体中未提供有效RT", zap.String("operation", operation), zap.Error(err))
-			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "未提供有效的刷新令牌")
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			ctrl.logger.Warn("非Web平台刷新令牌请求：请求体格式无效", zap.String("operation", operation), zap.Any("platform", platform), zap.Error(err))
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请求体格式无效，应为包含 refresh_token 字段的 JSON")
 			return
 		}
+		if req.RefreshToken == "" {
+			ctrl.logger.Warn("非Web平台刷新令牌请求：请求体中缺少RT", zap.String("operation", operation), zap.Any("platform", platform))
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请求体中缺少 refresh_token")
+			return
+		}
+		refreshTokenString = req.RefreshToken
+		ctrl.logger.Debug("从请求体获取到Refresh Token (非Web平台)", zap.String("operation", operation))
 	}
 
 	// 3. 调用服务层执行令牌刷新逻辑。
@@ -181,25 +196,259 @@ func (ctrl *AuthTokenController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// 4. 根据平台处理新令牌的响应
-	if platform == enums.PlatformWeb {
-		rtMaxAge := int(constants.RefreshTokenTTL.Seconds())
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     ctrl.cookieConfig.RefreshTokenName,
-			Value:    newTokenPair.RefreshToken,
-			MaxAge:   rtMaxAge,
-			Path:     ctrl.cookieConfig.Path,
-			Domain:   ctrl.cookieConfig.Domain,
-			Secure:   ctrl.cookieConfig.Secure,
-			HttpOnly: ctrl.cookieConfig.HttpOnly,
-			SameSite: utils.ParseSameSiteString(ctrl.cookieConfig.SameSite),
-		})
-		ctrl.logger.Info("成功刷新令牌 (Web平台，新RT已设置到Cookie)", zap.String("operation", operation))
-		response.RespondSuccess(c, vo.TokenPair{AccessToken: newTokenPair.AccessToken}, "刷新成功")
-	} else {
-		ctrl.logger.Info("成功刷新令牌 (非Web平台)", zap.String("operation", operation))
-		response.RespondSuccess(c, newTokenPair, "刷新成功")
+	// 4. 根据平台处理新令牌的响应：Web 平台新 RT 写入 Cookie，其余平台随 JSON 返回
+	deliverRefreshToken(c, ctrl.cookieConfig, platform, &newTokenPair, ctrl.jwtUtil.RefreshTokenTTL(platform))
+	if !guardWebRefreshToken(c, ctrl.cookieConfig, platform, &newTokenPair, ctrl.logger, operation) {
+		return
+	}
+	ctrl.logger.Info("成功刷新令牌", zap.String("operation", operation), zap.Any("platform", platform))
+	response.RespondSuccess(c, newTokenPair, "刷新成功")
+}
+
+// IntrospectHandler 处理令牌检查请求。
+// @Summary 检查令牌是否可用
+// @Description 供网关等内部调用方检查令牌当前是否可用。不可用时返回粗粒度原因：expired (可尝试刷新)、invalid 或 revoked (需要重新登录)；具体的校验失败原因只记录在服务端日志中。
+// @Tags 令牌管理 (Token Management)
+// @Accept json
+// @Produce json
+// @Param request body dto.IntrospectTokenRequest true "待检查的令牌及类型提示"
+// @Success 200 {object} docs.SwaggerAPITokenIntrospectionResponse "检查完成 (令牌是否可用见 active 字段)"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如 Redis 查询失败)"
+// @Router /api/v1/user-hub/auth/introspect [post]
+func (ctrl *AuthTokenController) IntrospectHandler(c *gin.Context) {
+	const operation = "AuthTokenController.IntrospectHandler"
+
+	var req dto.IntrospectTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("令牌检查请求参数无效", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	result, err := ctrl.tokenService.IntrospectToken(c.Request.Context(), req.Token, req.TokenTypeHint == "refresh_token")
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
 	}
+	response.RespondSuccess(c, *result, "令牌检查完成")
+}
+
+// BatchIntrospectHandler 处理批量令牌检查请求。
+// @Summary 批量检查令牌是否可用
+// @Description 供网关在一次请求中检查多个令牌，结果按请求顺序返回，每项含义与单个检查接口相同。单次上限由 batchLimitConfig 配置 (默认 100 个)；令牌并行解析，黑名单通过一次批量查询完成。
+// @Tags 令牌管理 (Token Management)
+// @Accept json
+// @Produce json
+// @Param request body dto.BatchIntrospectTokenRequest true "待检查的令牌列表"
+// @Success 200 {object} docs.SwaggerAPITokenIntrospectionBatchResponse "检查完成 (各令牌是否可用见 items[].active)"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如令牌列表为空或超过配置的上限)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如 Redis 查询失败)"
+// @Router /api/v1/user-hub/auth/introspect/batch [post]
+func (ctrl *AuthTokenController) BatchIntrospectHandler(c *gin.Context) {
+	const operation = "AuthTokenController.BatchIntrospectHandler"
+
+	var req dto.BatchIntrospectTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("批量令牌检查请求参数无效", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+	if !checkBatchSize(c, ctrl.batchLimits, config.BatchOpIntrospectTokens, len(req.Tokens)) {
+		ctrl.logger.Warn("批量令牌检查超出单次上限", zap.String("operation", operation), zap.Int("count", len(req.Tokens)))
+		return
+	}
+
+	results, err := ctrl.tokenService.IntrospectTokens(c.Request.Context(), req.Tokens)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	response.RespondSuccess(c, vo.TokenIntrospectionBatchVO{Items: results}, "令牌检查完成")
+}
+
+// WhoAmIHandler 返回当前 Access Token 中携带的声明。
+// @Summary 查看当前令牌信息 (who am I)
+// @Description 解析 Authorization 头中的 Access Token，校验签名、有效期并检查黑名单后返回用户ID、角色、状态、平台与签发/过期时间。完全由令牌得出，不查询数据库，角色与状态为签发时的快照；需要最新资料请使用 /profile。
+// @Tags 令牌管理 (Token Management)
+// @Produce json
+// @Param Authorization header string true "Bearer <Access Token>"
+// @Success 200 {object} docs.SwaggerAPITokenClaimsResponse "获取成功"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未提供令牌、令牌无效、已过期或已吊销"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如 Redis 查询失败)"
+// @Router /api/v1/user-hub/auth/me [get]
+func (ctrl *AuthTokenController) WhoAmIHandler(c *gin.Context) {
+	const operation = "AuthTokenController.WhoAmIHandler"
+
+	accessToken, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || accessToken == "" {
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "未提供有效的访问令牌")
+		return
+	}
+
+	claims, err := ctrl.tokenService.DescribeAccessToken(c.Request.Context(), accessToken)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+			return
+		}
+		ctrl.logger.Info("查询令牌声明被拒绝", zap.String("operation", operation), zap.String("reason", string(token.RejectionReasonOf(err))))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, err.Error())
+		return
+	}
+	response.RespondSuccess(c, *claims, "获取令牌信息成功")
+}
+
+// GetBlacklistStatsHandler 处理查询令牌黑名单统计信息的请求。
+// @Summary 令牌黑名单统计 (管理员)
+// @Description 返回当前实例的黑名单写入/命中/未命中计数，以及通过有界 SCAN 估算的黑名单大小。扫描量有上限，不会阻塞 Redis；超出预算时按比例外推，size_exact 为 false。
+// @Tags 令牌管理 (Token Management)
+// @Produce json
+// @Success 200 {object} docs.SwaggerAPIBlacklistStatsResponse "获取成功"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如 Redis 扫描失败)"
+// @Router /api/v1/user-hub/auth/blacklist/stats [get]
+func (ctrl *AuthTokenController) GetBlacklistStatsHandler(c *gin.Context) {
+	const operation = "AuthTokenController.GetBlacklistStatsHandler"
+
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试查询令牌黑名单统计", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可查询令牌黑名单统计")
+		return
+	}
+
+	stats, err := ctrl.tokenService.GetBlacklistStats(c.Request.Context())
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	response.RespondSuccess(c, *stats, "获取令牌黑名单统计成功")
+}
+
+// maxJtiLength JTI 路径参数的最大长度 (签发的 JTI 为 UUID)
+const maxJtiLength = 64
+
+// requireBlacklistAdmin 校验黑名单管理接口的调用者：必须是以登录身份调用的管理员，不接受 API 密钥。
+// 通过时返回管理员 ID 与路径中的 JTI；返回 false 时已写入错误响应。
+func (ctrl *AuthTokenController) requireBlacklistAdmin(c *gin.Context, operation string) (string, string, bool) {
+	actorID, ok := getCallerUserID(c)
+	if !ok {
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return "", "", false
+	}
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试访问令牌黑名单管理接口", zap.String("operation", operation), zap.String("userID", actorID))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可管理令牌黑名单")
+		return "", "", false
+	}
+	if _, viaAPIKey := c.Get(constants.APIKeyIDContextKey); viaAPIKey {
+		ctrl.logger.Warn("尝试通过 API 密钥访问令牌黑名单管理接口", zap.String("operation", operation), zap.String("userID", actorID))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "不能使用 API 密钥管理令牌黑名单，请登录后操作")
+		return "", "", false
+	}
+	jti := strings.TrimSpace(c.Param("jti"))
+	if jti == "" || len(jti) > maxJtiLength {
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "JTI 无效")
+		return "", "", false
+	}
+	return actorID, jti, true
+}
+
+// InspectBlacklistedJtiHandler 处理查询单个 JTI 黑名单状态的请求。
+// @Summary 查询 JTI 黑名单状态 (管理员)
+// @Description 返回指定 JTI 是否在令牌黑名单中及其剩余存活秒数，用于排查“退出登录后无法重新登录”等问题。仅限以登录身份调用的管理员，不接受 API 密钥。
+// @Tags 令牌管理 (Token Management)
+// @Produce json
+// @Param jti path string true "JWT ID"
+// @Success 200 {object} docs.SwaggerAPIBlacklistEntryResponse "查询成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "JTI 无效"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作或通过 API 密钥调用)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如 Redis 查询失败)"
+// @Router /api/v1/user-hub/auth/blacklist/{jti} [get]
+func (ctrl *AuthTokenController) InspectBlacklistedJtiHandler(c *gin.Context) {
+	const operation = "AuthTokenController.InspectBlacklistedJtiHandler"
+
+	_, jti, ok := ctrl.requireBlacklistAdmin(c, operation)
+	if !ok {
+		return
+	}
+	entry, err := ctrl.tokenService.InspectBlacklistedJti(c.Request.Context(), jti)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	response.RespondSuccess(c, *entry, "查询 JTI 黑名单状态成功")
+}
+
+// ClearBlacklistedJtiHandler 处理将单个 JTI 移出黑名单的请求。
+// @Summary 将 JTI 移出黑名单 (管理员)
+// @Description 将指定 JTI 从令牌黑名单中移除，对应令牌在过期前会重新可用，请谨慎操作。必须填写原因；操作先写入审计日志再执行移除，审计写入失败时不会移除。仅限以登录身份调用的管理员，不接受 API 密钥。JTI 本就不在黑名单中时返回 removed=false，不记录审计日志。
+// @Tags 令牌管理 (Token Management)
+// @Accept json
+// @Produce json
+// @Param jti path string true "JWT ID"
+// @Param body body dto.ClearBlacklistedJtiRequest true "移出原因"
+// @Success 200 {object} docs.SwaggerAPIBlacklistClearResponse "操作完成"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "JTI 无效或未填写原因"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作或通过 API 密钥调用)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如 Redis 操作或审计日志写入失败)"
+// @Router /api/v1/user-hub/auth/blacklist/{jti} [delete]
+func (ctrl *AuthTokenController) ClearBlacklistedJtiHandler(c *gin.Context) {
+	const operation = "AuthTokenController.ClearBlacklistedJtiHandler"
+
+	actorID, jti, ok := ctrl.requireBlacklistAdmin(c, operation)
+	if !ok {
+		return
+	}
+	var req dto.ClearBlacklistedJtiRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "请填写移出黑名单的原因")
+		return
+	}
+	result, err := ctrl.tokenService.ClearBlacklistedJti(c.Request.Context(), actorID, jti, req.Reason)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	response.RespondSuccess(c, *result, "JTI 黑名单移除操作完成")
+}
+
+// BlacklistMetricsHandler 以 Prometheus 文本格式导出令牌黑名单指标、各功能的 Redis 失败/降级计数以及在途请求数。
+// 该接口不访问 Redis，大小指标取自最近一次 GetBlacklistStatsHandler 的估算结果，尚未估算时不输出。
+func (ctrl *AuthTokenController) BlacklistMetricsHandler(c *gin.Context) {
+	stats := ctrl.tokenService.GetBlacklistMetrics()
+
+	var b strings.Builder
+	writeMetric := func(name, help, metricType string, value string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, help, name, metricType, name, value)
+	}
+	writeMetric("user_hub_token_blacklist_added_total", "Number of JTIs added to the token blacklist.", "counter", strconv.FormatInt(stats.Added, 10))
+	writeMetric("user_hub_token_blacklist_hits_total", "Number of blacklist checks that found the JTI.", "counter", strconv.FormatInt(stats.Hits, 10))
+	writeMetric("user_hub_token_blacklist_misses_total", "Number of blacklist checks that did not find the JTI.", "counter", strconv.FormatInt(stats.Misses, 10))
+	if stats.EstimatedAt != nil {
+		writeMetric("user_hub_token_blacklist_size_estimate", "Approximate number of JTIs in the token blacklist from the last bounded scan.", "gauge", strconv.FormatInt(stats.SizeEstimate, 10))
+		writeMetric("user_hub_token_blacklist_size_estimate_timestamp_seconds", "Unix time of the last blacklist size estimate.", "gauge", strconv.FormatInt(stats.EstimatedAt.Unix(), 10))
+	}
+
+	degradeStats := ctrl.degrade.Stats()
+	b.WriteString("# HELP user_hub_redis_failures_total Number of failed Redis operations per feature.\n# TYPE user_hub_redis_failures_total counter\n")
+	for _, stat := range degradeStats {
+		fmt.Fprintf(&b, "user_hub_redis_failures_total{feature=%q,critical=\"%t\"} %d\n", stat.Feature, stat.Critical, stat.Failures)
+	}
+	b.WriteString("# HELP user_hub_redis_fail_open_total Number of requests allowed through without Redis under the degradation policy.\n# TYPE user_hub_redis_fail_open_total counter\n")
+	for _, stat := range degradeStats {
+		fmt.Fprintf(&b, "user_hub_redis_fail_open_total{feature=%q} %d\n", stat.Feature, stat.FailOpen)
+	}
+
+	if ctrl.inFlight != nil {
+		writeMetric("user_hub_http_in_flight_requests", "Number of requests currently being handled under the in-flight limit.", "gauge", strconv.FormatInt(ctrl.inFlight.InFlight(), 10))
+		writeMetric("user_hub_http_in_flight_limit", "Maximum number of in-flight requests.", "gauge", strconv.Itoa(ctrl.inFlight.Max()))
+		writeMetric("user_hub_http_in_flight_rejected_total", "Number of requests rejected with 503 because the in-flight limit was reached.", "counter", strconv.FormatInt(ctrl.inFlight.Rejected(), 10))
+	}
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
 }
 
 // RegisterRoutes 注册与令牌管理相关的路由到指定的 Gin 路由组。
@@ -224,5 +473,31 @@ func (ctrl *AuthTokenController) RegisterRoutes(group *gin.RouterGroup) {
 		// - 场景: Access Token 过期后，客户端使用 Refresh Token 获取新的令牌对。
 		// - 预期权限: 无需认证（因为 Refresh Token 本身就是一种认证凭证），服务层会校验其有效性。
 		authRoutes.POST("/refresh-token", ctrl.RefreshToken)
+
+		// 注册令牌检查路由
+		// - 场景: 网关在转发前确认令牌状态，并根据原因决定引导客户端刷新令牌还是重新登录。
+		// - 预期权限: 仅供内部服务调用，应在网关层禁止外部直接访问。
+		authRoutes.POST("/introspect", ctrl.IntrospectHandler)
+
+		// 注册批量令牌检查路由
+		// - 场景: 网关需要在一次调用中校验多个令牌 (如聚合请求)，避免逐个调用的往返开销。
+		// - 预期权限: 仅供内部服务调用，应在网关层禁止外部直接访问。
+		authRoutes.POST("/introspect/batch", ctrl.BatchIntrospectHandler)
+
+		// 注册查看当前令牌信息路由
+		// - 场景: 客户端需要知道自己令牌中的身份与过期时间，又不想自行解码 JWT。
+		// - 预期权限: 需要携带 Access Token，处理函数内自行校验。
+		authRoutes.GET("/me", ctrl.WhoAmIHandler)
+
+		// 注册令牌黑名单统计路由
+		// - 场景: 管理员评估黑名单容量与命中率（例如规划独立 Redis 实例）。
+		// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内会再次校验角色。
+		authRoutes.GET("/blacklist/stats", ctrl.GetBlacklistStatsHandler)
+
+		// 注册单个 JTI 黑名单查询与移除路由
+		// - 场景: 排查“退出登录后无法重新登录”等问题时，查看某个 JTI 是否在黑名单中，必要时将其移出。
+		// - 预期权限: 仅管理员 (Admin)，且只能以登录身份调用 (不接受 API 密钥)；移除操作必须填写原因并写入审计日志。
+		authRoutes.GET("/blacklist/:jti", ctrl.InspectBlacklistedJtiHandler)
+		authRoutes.DELETE("/blacklist/:jti", ctrl.ClearBlacklistedJtiHandler)
 	}
 }
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Xushengqwer/go-common/models/enums"
+)
+
+func TestRefreshTokenBadRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		platform    string
+		body        string
+		cookie      string
+		wantMessage string
+	}{
+		{name: "缺少平台请求头", body: `{"refresh_token":"old-refresh"}`, wantMessage: "缺少 X-Platform 请求头"},
+		{name: "平台请求头无效", platform: "desktop", body: `{"refresh_token":"old-refresh"}`, wantMessage: "不支持的平台类型: desktop"},
+		{name: "Web 平台缺少 Cookie", platform: string(enums.PlatformWeb), body: `{"refresh_token":"old-refresh"}`, wantMessage: "Web 平台需通过 Cookie 提供刷新令牌"},
+		{name: "非 Web 平台请求体为空", platform: string(enums.PlatformApp), wantMessage: "请求体中缺少 refresh_token"},
+		{name: "非 Web 平台不从 Cookie 读取", platform: string(enums.PlatformApp), body: `{}`, cookie: "old-refresh", wantMessage: "请求体中缺少 refresh_token"},
+		{name: "非 Web 平台请求体格式无效", platform: string(enums.PlatformApp), body: `{"refresh_token":`, wantMessage: "请求体格式无效，应为包含 refresh_token 字段的 JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, tokenService := newRefreshCookieTestRouter(t)
+
+			w := performRequest(r, "/auth/refresh-token", tt.platform, tt.body, tt.cookie)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("状态码 = %d，期望 400，响应体: %s", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v，响应体: %s", err, w.Body.String())
+			}
+			if resp.Message != tt.wantMessage {
+				t.Errorf("message = %q，期望 %q", resp.Message, tt.wantMessage)
+			}
+			if tokenService.received != "" {
+				t.Errorf("参数无效时不应调用刷新服务，实际收到 %q", tokenService.received)
+			}
+		})
+	}
+}
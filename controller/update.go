@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ResourceChangedHeader 更新类接口的响应头，取值 "true" / "false"，表示本次请求是否实际修改了资源
+// - 值与现有数据相同的更新不会写库，客户端与缓存可据此区分真实变更与无变更的请求。
+const ResourceChangedHeader = "X-Resource-Changed"
+
+// unchangedMessage 未发生实际变更时的响应消息
+const unchangedMessage = "无变更"
+
+// respondUpdateResult 输出更新类接口的成功响应：设置 X-Resource-Changed 头，无变更时使用统一的“无变更”消息
+func respondUpdateResult[T any](c *gin.Context, data T, changed bool, changedMessage string) {
+	c.Header(ResourceChangedHeader, strconv.FormatBool(changed))
+	if !changed {
+		response.RespondSuccess(c, data, unchangedMessage)
+		return
+	}
+	response.RespondSuccess(c, data, changedMessage)
+}
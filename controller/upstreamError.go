@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/vo"
+)
+
+// upstreamErrorDetail 在开启第三方错误详情且调用者有权查看时，从 err 链中取出脱敏的上游错误摘要。
+// - 未启用、调用者不是管理员 (且未开启对所有调用者生效的调试开关) 或 err 链中没有 UpstreamError 时返回 nil。
+func upstreamErrorDetail(c *gin.Context, err error) *vo.UpstreamErrorVO {
+	raw, exists := c.Get(constants.UpstreamErrorDetailContextKey)
+	if !exists {
+		return nil
+	}
+	cfg, ok := raw.(config.UpstreamErrorDetailConfig)
+	if !ok || !cfg.Enabled || (!cfg.AllCallers && !isAdminCaller(c)) {
+		return nil
+	}
+	var upstreamErr *dependencies.UpstreamError
+	if !errors.As(err, &upstreamErr) {
+		return nil
+	}
+	return &vo.UpstreamErrorVO{
+		Service:    upstreamErr.Service,
+		Category:   upstreamErr.Category,
+		StatusCode: upstreamErr.StatusCode,
+		Code:       upstreamErr.Code,
+		RequestID:  upstreamErr.RequestID,
+	}
+}
+
+// respondUpstreamError 输出第三方服务失败的错误响应，message 为面向用户的通用文案。
+// 调用者有权查看时在 data.upstream 中附带脱敏的上游错误摘要，否则与 response.RespondError 的输出一致。
+func respondUpstreamError(c *gin.Context, statusCode int, code int, message string, err error) {
+	detail := upstreamErrorDetail(c, err)
+	if detail == nil {
+		response.RespondError(c, statusCode, code, message)
+		return
+	}
+	c.JSON(statusCode, response.APIResponse[vo.UpstreamFailureVO]{
+		Code:    code,
+		Message: message,
+		Data:    vo.UpstreamFailureVO{Upstream: detail},
+	})
+}
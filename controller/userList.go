@@ -7,6 +7,7 @@ import (
 	"github.com/Xushengqwer/go-common/commonerrors" // 引入公共错误包
 	"github.com/Xushengqwer/go-common/core"         // 引入日志包
 	"github.com/Xushengqwer/go-common/response"
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/dependencies"
 	// "user_hub/docs" // 如果您的 linter/IDE 需要，可以导入 docs 包，swag 通常会自动处理
 	"github.com/Xushengqwer/user_hub/models/dto"
@@ -22,6 +23,7 @@ type UserListQueryController struct {
 	queryService service.UserListQueryService   // queryService: 用户列表查询服务的实例。
 	jwtUtil      dependencies.JWTTokenInterface // jwtUtil: JWT 工具，用于认证中间件。
 	logger       *core.ZapLogger                // logger: 日志记录器。
+	listEnvelope config.ListEnvelopeConfig      // listEnvelope: 列表信封兼容配置。
 }
 
 // NewUserListQueryController 创建一个新的 UserListQueryController 实例。
@@ -32,6 +34,7 @@ type UserListQueryController struct {
 //   - queryService: 实现了 service.UserListQueryService 接口的服务实例。
 //   - jwtUtil: JWT工具实例。
 //   - logger: 日志记录器实例。
+//   - listEnvelope: 列表信封兼容配置 (是否输出旧版 users 字段)。
 //
 // 返回:
 //   - *UserListQueryController: 初始化完成的控制器实例。
@@ -39,23 +42,25 @@ func NewUserListQueryController(
 	queryService service.UserListQueryService,
 	jwtUtil dependencies.JWTTokenInterface,
 	logger *core.ZapLogger, // 注入 logger
+	listEnvelope config.ListEnvelopeConfig,
 ) *UserListQueryController {
 	return &UserListQueryController{
 		queryService: queryService,
 		jwtUtil:      jwtUtil,
 		logger:       logger, // 存储 logger
+		listEnvelope: listEnvelope,
 	}
 }
 
 // ListUsersWithProfileHandler 处理分页查询用户及其关联 Profile 信息的请求。
 // @Summary 分页查询用户及其资料 (管理员)
-// @Description 管理员根据指定的过滤、排序和分页条件，查询用户列表及其关联的 Profile 信息。
+// @Description 管理员根据指定的过滤、排序和分页条件，查询用户列表及其关联的 Profile 信息。请求体中 fields 可限定只查询并返回部分字段 (user_id 总会返回)，此时列表项只包含所选字段。
 // @Tags 用户查询 (User Query)
 // @Accept json
 // @Produce json
 // @Param body body dto.UserQueryDTO true "查询条件 (过滤、排序、分页)"
 // @Success 200 {object} docs.SwaggerAPIUserListResponse "查询成功，返回用户列表和总记录数"
-// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、分页参数超出范围)"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、分页参数超出范围、fields 包含不支持的字段)"
 // @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
 // @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
 // @Router /api/v1/user-hub/users/query [post] // <--- 已更新路径
@@ -78,6 +83,10 @@ func (ctrl *UserListQueryController) ListUsersWithProfileHandler(c *gin.Context)
 	if err != nil {
 		// 根据服务层返回的错误类型记录日志并响应。
 		// UserListQueryService 通常只在数据库层面失败，返回 ErrSystemError。
+		if errors.Is(err, service.ErrInvalidProjectionField) {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+			return
+		}
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			ctrl.logger.Error("查询用户列表服务返回系统错误",
 				zap.String("operation", operation),
@@ -97,22 +106,88 @@ func (ctrl *UserListQueryController) ListUsersWithProfileHandler(c *gin.Context)
 		return
 	}
 
-	// 3. 构造响应数据。
-	//    服务层直接返回了 vo.UserWithProfileVO 列表，无需控制器再次转换。
-	responseData := vo.UserListResponse{
-		Users: users,
-		Total: total,
-	}
-
-	// 4. 记录日志并返回成功响应。
+	// 3. 记录日志。
 	ctrl.logger.Info("成功查询用户列表及其Profile信息",
 		zap.String("operation", operation),
 		zap.Int64("totalRecords", total),
 		zap.Int("returnedRecords", len(users)),
 		zap.Int("page", queryDTO.Page),
 		zap.Int("pageSize", queryDTO.PageSize),
+		zap.Strings("fields", queryDTO.Fields),
 	)
-	response.RespondSuccess(c, responseData, "查询成功")
+
+	// 4. 返回成功响应：指定了 fields 时列表项只包含所选字段 (不再输出旧版 users 字段)
+	if len(queryDTO.Fields) > 0 {
+		projected := make([]map[string]any, 0, len(users))
+		for _, user := range users {
+			projected = append(projected, user.Project(queryDTO.Fields))
+		}
+		response.RespondSuccess(c, vo.NewPageVO(projected, total, queryDTO.Page, queryDTO.PageSize), "查询成功")
+		return
+	}
+	response.RespondSuccess(c, ctrl.userListResponse(users, total, queryDTO.Page, queryDTO.PageSize), "查询成功")
+}
+
+// SearchUsersHandler 处理管理员组合搜索用户的请求。
+// @Summary 组合搜索用户 (管理员)
+// @Description 管理员使用单一关键字搜索用户：精确匹配用户ID、手机号、账号/邮箱，模糊匹配昵称。结果去重，精确命中的用户排在前面，最多返回 200 条可翻页结果。
+// @Tags 用户查询 (User Query)
+// @Produce json
+// @Param q query string true "搜索关键字，至少 2 个字符"
+// @Param page query int false "页码，默认 1"
+// @Param page_size query int false "每页大小，默认 10，最大 100"
+// @Success 200 {object} docs.SwaggerAPIUserListResponse "搜索成功，返回用户列表和命中总数 (不超过结果上限)"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如关键字过短、分页参数超出范围)"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
+// @Router /api/v1/user-hub/users/search [get]
+func (ctrl *UserListQueryController) SearchUsersHandler(c *gin.Context) {
+	const operation = "UserListQueryController.SearchUsersHandler"
+
+	// 1. 搜索可按手机号等定位用户，仅对管理员开放
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试搜索用户", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可搜索用户")
+		return
+	}
+
+	// 2. 绑定并校验查询参数
+	var searchDTO dto.UserSearchDTO
+	if err := c.ShouldBindQuery(&searchDTO); err != nil {
+		ctrl.logger.Warn("搜索用户请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	// 3. 调用服务层搜索
+	users, total, err := ctrl.queryService.SearchUsers(c.Request.Context(), &searchDTO)
+	if err != nil {
+		if errors.Is(err, service.ErrSearchQueryTooShort) {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+			return
+		}
+		ctrl.logger.Error("搜索用户服务返回错误", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+
+	page, pageSize := searchDTO.Page, searchDTO.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	response.RespondSuccess(c, ctrl.userListResponse(users, total, page, pageSize), "搜索成功")
+}
+
+// userListResponse 构造用户列表的分页信封，按配置决定是否同时输出旧版 users 字段
+func (ctrl *UserListQueryController) userListResponse(users []*vo.UserWithProfileVO, total int64, page, pageSize int) vo.UserListResponse {
+	resp := vo.UserListResponse{PageVO: vo.NewPageVO(users, total, page, pageSize)}
+	if !ctrl.listEnvelope.OmitLegacyFields {
+		resp.Users = resp.Items
+	}
+	return resp
 }
 
 // RegisterRoutes 注册与用户列表查询相关的路由到指定的 Gin 路由组。
@@ -132,5 +207,10 @@ func (ctrl *UserListQueryController) RegisterRoutes(group *gin.RouterGroup) {
 		// - 场景: 管理员后台分页查看用户列表，支持筛选和排序。
 		// - 预期权限: 需要认证，且角色为管理员 (Admin)，由网关处理。
 		usersRoutes.POST("/query", ctrl.ListUsersWithProfileHandler)
+
+		// 注册组合搜索用户的路由
+		// - 场景: 管理员后台通过单一搜索框按用户ID、手机号、邮箱或昵称查找用户。
+		// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内校验。
+		usersRoutes.GET("/search", ctrl.SearchUsersHandler)
 	}
 }
@@ -3,14 +3,15 @@ package controller
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/Xushengqwer/go-common/commonerrors" // 引入公共错误包
 	"github.com/Xushengqwer/go-common/core"         // 引入日志包
 	"github.com/Xushengqwer/go-common/response"
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/dependencies"
 	// "user_hub/docs" // 如果您的 linter/IDE 需要，可以导入 docs 包，swag 通常会自动处理
 	"github.com/Xushengqwer/user_hub/models/dto"
-	"github.com/Xushengqwer/user_hub/models/vo"
 	service "github.com/Xushengqwer/user_hub/service/userManage"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -20,8 +21,10 @@ import (
 // 例如：管理员创建用户、获取/更新/删除用户信息、拉黑用户等。
 type UserManageController struct {
 	userService service.UserManageService      // userService: 用户管理服务的实例。
+	userDetail  service.AdminUserDetailService // userDetail: 管理员用户详情聚合服务。
 	jwtToken    dependencies.JWTTokenInterface // jwtToken: JWT 工具，用于认证中间件。
 	logger      *core.ZapLogger                // logger: 日志记录器。
+	batchLimits config.BatchLimitConfig        // batchLimits: 批量接口单次条目数上限。
 }
 
 // NewUserController 创建一个新的 UserManageController 实例。
@@ -30,20 +33,26 @@ type UserManageController struct {
 //
 // 参数:
 //   - userService: 实现了 service.UserManageService 接口的服务实例。
+//   - userDetail: 管理员查看用户聚合详情的服务实例。
 //   - jwtUtil: JWT工具实例。
 //   - logger: 日志记录器实例。
+//   - batchLimits: 批量接口单次条目数上限配置。
 //
 // 返回:
 //   - *UserManageController: 初始化完成的控制器实例。
 func NewUserController( // 函数名保持与您提供的一致
 	userService service.UserManageService,
+	userDetail service.AdminUserDetailService,
 	jwtUtil dependencies.JWTTokenInterface,
 	logger *core.ZapLogger, // 注入 logger
+	batchLimits config.BatchLimitConfig,
 ) *UserManageController {
 	return &UserManageController{
 		userService: userService,
+		userDetail:  userDetail,
 		jwtToken:    jwtUtil,
 		logger:      logger, // 存储 logger
+		batchLimits: batchLimits,
 	}
 }
 
@@ -70,8 +79,11 @@ func (ctrl *UserManageController) CreateUserHandler(c *gin.Context) {
 		return
 	}
 
+	// 操作者 ID 由网关注入，用于写入审计日志
+	actorID, _ := getCallerUserID(c)
+
 	// 2. 调用服务层执行创建用户的逻辑。
-	userVO, err := ctrl.userService.CreateUser(c.Request.Context(), &createUserDTO)
+	userVO, err := ctrl.userService.CreateUser(c.Request.Context(), actorID, &createUserDTO)
 	if err != nil {
 		// CreateUser 服务通常只在数据库层面失败，返回 ErrSystemError
 		if errors.Is(err, commonerrors.ErrSystemError) {
@@ -101,9 +113,10 @@ func (ctrl *UserManageController) CreateUserHandler(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param userID path string true "要查询的用户ID"
+// @Param include_deleted query bool false "是否包含已软删除的用户 (仅管理员)，包含时响应中返回 deleted_at"
 // @Success 200 {object} docs.SwaggerAPIUserVOResponse "获取用户信息成功"
-// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空)"
-// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员或用户本人)"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空、include_deleted 不是布尔值)"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员或用户本人；非管理员使用 include_deleted)"
 // @Failure 404 {object} docs.SwaggerAPIErrorResponseString "指定的用户不存在"
 // @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
 // @Router /api/v1/user-hub/users/{userID} [get] // <--- 已更新路径
@@ -119,8 +132,24 @@ func (ctrl *UserManageController) GetUserByIDHandler(c *gin.Context) {
 	}
 	// 权限校验：是否是用户本人或管理员？依赖中间件。
 
+	// 1.1 解析 include_deleted：查看已删除账号仅限管理员。
+	includeDeleted := false
+	if raw := c.Query("include_deleted"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "include_deleted 参数无效")
+			return
+		}
+		includeDeleted = parsed
+	}
+	if includeDeleted && !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试查询已删除用户", zap.String("operation", operation), zap.String("userID", userID))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可查看已删除的用户")
+		return
+	}
+
 	// 2. 调用服务层获取用户信息。
-	userVO, err := ctrl.userService.GetUserByID(c.Request.Context(), userID)
+	userVO, err := ctrl.userService.GetUserByID(c.Request.Context(), userID, includeDeleted)
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
@@ -182,6 +211,45 @@ func (ctrl *UserManageController) GetUserProfileByAdminHandler(c *gin.Context) {
 	response.RespondSuccess(c, profileVO, "获取用户资料成功")
 }
 
+// GetUserDetailHandler 处理管理员获取指定用户聚合详情的请求。
+// @Summary 获取指定用户的完整详情 (管理员)
+// @Description (管理员权限) 一次返回用户的核心信息、资料、脱敏后的登录方式、有效会话数与最近登录，替代分别调用用户、资料、身份接口。
+// @Tags 用户管理 (User Management)
+// @Accept json
+// @Produce json
+// @Param userID path string true "要查询的用户ID"
+// @Success 200 {object} docs.SwaggerAPIAdminUserDetailResponse "获取用户详情成功"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空)"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Failure 404 {object} docs.SwaggerAPIErrorResponseString "指定的用户不存在"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
+// @Router /api/v1/user-hub/users/{userID}/detail [get]
+func (ctrl *UserManageController) GetUserDetailHandler(c *gin.Context) {
+	const operation = "UserManageController.GetUserDetailHandler"
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试查看用户详情", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可查看用户详情")
+		return
+	}
+	targetUserID := c.Param("userID")
+	if targetUserID == "" {
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "目标用户 ID 不能为空")
+		return
+	}
+
+	detail, err := ctrl.userDetail.GetUserDetail(c.Request.Context(), targetUserID)
+	if err != nil {
+		if errors.Is(err, service.ErrDetailUserNotFound) {
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+			return
+		}
+		ctrl.logger.Error("管理员获取用户详情失败", zap.String("operation", operation), zap.String("targetUserID", targetUserID), zap.Error(err))
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	response.RespondSuccess(c, detail, "获取用户详情成功")
+}
+
 // UpdateUserHandler 处理更新用户核心信息（角色、状态）的请求。
 // @Summary 更新用户信息 (管理员)
 // @Description 管理员更新指定用户的角色和状态。
@@ -191,6 +259,7 @@ func (ctrl *UserManageController) GetUserProfileByAdminHandler(c *gin.Context) {
 // @Param userID path string true "要更新的用户ID"
 // @Param body body dto.UpdateUserDTO true "包含待更新角色和/或状态的请求体"
 // @Success 200 {object} docs.SwaggerAPIUserVOResponse "用户信息更新成功，返回更新后的用户信息"
+// @Header 200 {string} X-Resource-Changed "本次请求是否实际修改了数据 (true/false)；为 false 时消息为“无变更”"
 // @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、用户ID为空、角色或状态值无效)"
 // @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
 // @Failure 404 {object} docs.SwaggerAPIErrorResponseString "指定的用户不存在"
@@ -220,8 +289,11 @@ func (ctrl *UserManageController) UpdateUserHandler(c *gin.Context) {
 	}
 	// 可以在此添加对 DTO 中 Role 和 Status 枚举值的进一步校验（如果 binding 标签不够）
 
+	// 操作者 ID 由网关注入，用于写入审计日志
+	actorID, _ := getCallerUserID(c)
+
 	// 3. 调用服务层执行更新逻辑。
-	userVO, err := ctrl.userService.UpdateUser(c.Request.Context(), userID, &updateUserDTO)
+	userVO, changed, err := ctrl.userService.UpdateUser(c.Request.Context(), actorID, userID, &updateUserDTO)
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
@@ -238,21 +310,26 @@ func (ctrl *UserManageController) UpdateUserHandler(c *gin.Context) {
 	ctrl.logger.Info("成功更新用户信息",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
+		zap.Bool("changed", changed),
 	)
-	response.RespondSuccess(c, userVO, "用户信息更新成功")
+	respondUpdateResult(c, userVO, changed, "用户信息更新成功")
 }
 
-// DeleteUserHandler 处理（软）删除用户的请求。
+// DeleteUserHandler 处理删除用户的请求。
 // @Summary 删除用户 (管理员)
-// @Description 管理员（软）删除指定的用户账户及其所有关联数据（如身份、资料）。
+// @Description 管理员删除指定的用户账户及其所有关联数据（如身份、资料）。默认按配置软删除；硬删除会物理删除上述记录并删除存储中的头像。配置允许时可通过 mode 参数按请求指定删除方式。携带 dry_run=true 时只返回将会发生的结果 (updated / not_found)，不做任何修改。
 // @Tags 用户管理 (User Management)
 // @Accept json
 // @Produce json
 // @Param userID path string true "要删除的用户ID"
-// @Success 200 {object} docs.SwaggerAPIEmptyResponse "用户删除成功"
-// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空)"
+// @Param dry_run query bool false "为 true 时只校验并返回将会发生的结果，不提交任何修改"
+// @Param mode query string false "删除方式 (soft / hard)，为空时使用配置的默认方式；需配置 allow_override 才能指定与默认不同的方式" Enums(soft, hard)
+// @Success 200 {object} docs.SwaggerAPIUserActionResultResponse "用户删除成功 (或预演结果)"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空、删除方式无效或不允许按请求指定)"
 // @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
 // @Failure 404 {object} docs.SwaggerAPIErrorResponseString "指定的用户不存在 (如果服务层认为删除不存在的用户是错误)"
+// @Failure 409 {object} docs.SwaggerAPIErrorResponseString "删除被前置检查否决 (如用户仍有关联内容)，消息中包含原因"
+// @Failure 502 {object} docs.SwaggerAPIErrorResponseString "前置检查服务不可用，删除未执行"
 // @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库事务失败)"
 // @Router /api/v1/user-hub/users/{userID} [delete] // <--- 已更新路径
 func (ctrl *UserManageController) DeleteUserHandler(c *gin.Context) {
@@ -266,10 +343,27 @@ func (ctrl *UserManageController) DeleteUserHandler(c *gin.Context) {
 		return
 	}
 
+	dryRun, ok := parseDryRun(c)
+	if !ok {
+		ctrl.logger.Warn("删除用户请求的 dry_run 参数无效", zap.String("operation", operation), zap.String("dry_run", c.Query("dry_run")))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "dry_run 参数无效")
+		return
+	}
+
+	// 操作者 ID 由网关注入，用于写入审计日志
+	actorID, _ := getCallerUserID(c)
+
 	// 2. 调用服务层执行删除用户的逻辑（包含事务性删除关联数据）。
-	err := ctrl.userService.DeleteUser(c.Request.Context(), userID)
+	result, err := ctrl.userService.DeleteUser(c.Request.Context(), actorID, userID, dryRun, c.Query("mode"))
 	if err != nil {
-		if errors.Is(err, commonerrors.ErrSystemError) {
+		var veto *service.DeleteVetoError
+		if errors.Is(err, service.ErrDeleteModeInvalid) || errors.Is(err, service.ErrDeleteModeOverrideNotAllowed) {
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		} else if errors.As(err, &veto) {
+			response.RespondError(c, http.StatusConflict, response.ErrCodeClientForbidden, veto.Error())
+		} else if errors.Is(err, commonerrors.ErrThirdPartyServiceError) {
+			response.RespondError(c, http.StatusBadGateway, response.ErrCodeThirdPartyServiceError, "无法确认用户是否允许删除，请稍后重试")
+		} else if errors.Is(err, commonerrors.ErrSystemError) {
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
 		} else if err.Error() == "要删除的用户不存在" { // 假设服务层对删除不存在用户返回此业务错误
 			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
@@ -281,21 +375,74 @@ func (ctrl *UserManageController) DeleteUserHandler(c *gin.Context) {
 	}
 
 	// 3. 返回成功响应。
+	if dryRun {
+		response.RespondSuccess(c, *result, "预演完成，未做任何修改")
+		return
+	}
 	ctrl.logger.Info("成功删除用户及其关联数据",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
+		zap.String("mode", result.DeleteMode),
 	)
-	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "用户删除成功")
+	response.RespondSuccess(c, *result, "用户删除成功")
+}
+
+// BatchSetRoleHandler 处理批量设置用户角色的请求。
+// @Summary 批量设置用户角色 (管理员)
+// @Description 管理员将一批用户 (单次上限由 batchLimitConfig 配置，默认 500 个) 的角色设置为同一个值。按批在独立事务中更新，并为每个实际变更的用户写入审计日志；响应中给出每个用户的处理结果 (updated / unchanged / not_found / failed)。请求体中 dry_run 为 true 时只校验并返回将会发生的结果，不提交任何修改。
+// @Tags 用户管理 (User Management)
+// @Accept json
+// @Produce json
+// @Param request body dto.BatchRoleDTO true "目标用户 ID 列表与角色"
+// @Success 200 {object} docs.SwaggerAPIBatchRoleResultResponse "处理完成，返回逐个用户的结果"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如列表为空或超过上限、角色无效)"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库查询失败)"
+// @Router /api/v1/user-hub/users/batch-role [post]
+func (ctrl *UserManageController) BatchSetRoleHandler(c *gin.Context) {
+	const operation = "UserManageController.BatchSetRoleHandler"
+
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试批量设置用户角色", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可批量设置用户角色")
+		return
+	}
+
+	var req dto.BatchRoleDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("批量设置角色请求参数无效", zap.String("operation", operation), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+	if !checkBatchSize(c, ctrl.batchLimits, config.BatchOpSetRole, len(req.UserIDs)) {
+		ctrl.logger.Warn("批量设置角色超出单次上限", zap.String("operation", operation), zap.Int("count", len(req.UserIDs)))
+		return
+	}
+
+	// 操作者 ID 由网关注入，用于写入审计日志
+	actorID, _ := getCallerUserID(c)
+
+	report, err := ctrl.userService.BatchSetRole(c.Request.Context(), actorID, &req)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+	message := "批量设置角色完成"
+	if report.DryRun {
+		message = "预演完成，未做任何修改"
+	}
+	response.RespondSuccess(c, *report, message)
 }
 
 // BlackUserHandler 处理将用户加入黑名单的请求。
 // @Summary 拉黑用户 (管理员)
-// @Description 管理员将指定的用户账户状态设置为“拉黑”，阻止其登录或访问受限资源。
+// @Description 管理员将指定的用户账户状态设置为“拉黑”，阻止其登录或访问受限资源。携带 dry_run=true 时只返回将会发生的结果 (updated / unchanged)，不做任何修改。
 // @Tags 用户管理 (User Management)
 // @Accept json
 // @Produce json
 // @Param userID path string true "要拉黑的用户ID"
-// @Success 200 {object} docs.SwaggerAPIEmptyResponse "用户已成功拉黑"
+// @Param dry_run query bool false "为 true 时只校验并返回将会发生的结果，不提交任何修改"
+// @Success 200 {object} docs.SwaggerAPIUserActionResultResponse "用户已成功拉黑 (或预演结果)"
 // @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空)"
 // @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
 // @Failure 404 {object} docs.SwaggerAPIErrorResponseString "指定的用户不存在"
@@ -312,8 +459,18 @@ func (ctrl *UserManageController) BlackUserHandler(c *gin.Context) {
 		return
 	}
 
+	dryRun, ok := parseDryRun(c)
+	if !ok {
+		ctrl.logger.Warn("拉黑用户请求的 dry_run 参数无效", zap.String("operation", operation), zap.String("dry_run", c.Query("dry_run")))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "dry_run 参数无效")
+		return
+	}
+
+	// 操作者 ID 由网关注入，用于写入审计日志
+	actorID, _ := getCallerUserID(c)
+
 	// 2. 调用服务层执行拉黑用户的逻辑。
-	err := ctrl.userService.BlackUser(c.Request.Context(), userID)
+	result, err := ctrl.userService.BlackUser(c.Request.Context(), actorID, userID, dryRun)
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
@@ -327,11 +484,87 @@ func (ctrl *UserManageController) BlackUserHandler(c *gin.Context) {
 	}
 
 	// 3. 返回成功响应。
+	if dryRun {
+		response.RespondSuccess(c, *result, "预演完成，未做任何修改")
+		return
+	}
 	ctrl.logger.Info("成功拉黑用户",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
 	)
-	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "用户已拉黑")
+	response.RespondSuccess(c, *result, "用户已拉黑")
+}
+
+// ReactivateUserHandler 处理管理员恢复用户的请求。
+// @Summary 恢复用户 (管理员)
+// @Description 管理员将已拉黑或已停用 (自行停用) 的用户恢复为活跃状态，并在审计日志中记录操作者与原因。只修改状态，不会使用户现有的登录会话失效。用户本就处于活跃状态时不做修改，响应头 X-Resource-Changed 为 false。
+// @Tags 用户管理 (User Management)
+// @Accept json
+// @Produce json
+// @Param userID path string true "要恢复的用户ID"
+// @Param request body dto.ReactivateUserDTO false "恢复原因 (可选)"
+// @Success 200 {object} docs.SwaggerAPIUserVOResponse "用户已恢复，返回最新的用户信息"
+// @Header 200 {string} X-Resource-Changed "本次请求是否实际修改了数据 (true/false)；为 false 时消息为“无变更”"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如用户ID为空、原因过长)"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "权限不足 (非管理员操作)"
+// @Failure 404 {object} docs.SwaggerAPIErrorResponseString "指定的用户不存在"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如数据库操作失败)"
+// @Router /api/v1/user-hub/users/{userID}/reactivate [post]
+func (ctrl *UserManageController) ReactivateUserHandler(c *gin.Context) {
+	const operation = "UserManageController.ReactivateUserHandler"
+
+	if !isAdminCaller(c) {
+		ctrl.logger.Warn("非管理员尝试恢复用户", zap.String("operation", operation))
+		response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "仅管理员可恢复用户")
+		return
+	}
+
+	userID := c.Param("userID")
+	if userID == "" {
+		ctrl.logger.Warn("恢复用户请求的用户ID为空", zap.String("operation", operation))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "用户 ID 不能为空")
+		return
+	}
+
+	// 请求体可省略 (不填写原因)
+	var req dto.ReactivateUserDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ctrl.logger.Warn("恢复用户请求参数绑定失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+			return
+		}
+	}
+
+	// 操作者 ID 由网关注入，用于写入审计日志
+	actorID, _ := getCallerUserID(c)
+
+	userVO, changed, err := ctrl.userService.ReactivateUser(c.Request.Context(), actorID, userID, req.Reason)
+	if err != nil {
+		if errors.Is(err, service.ErrReactivateUserNotFound) {
+			response.RespondError(c, http.StatusNotFound, response.ErrCodeClientResourceNotFound, err.Error())
+			return
+		}
+		response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		return
+	}
+
+	ctrl.logger.Info("管理员恢复用户处理完成",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.Bool("changed", changed),
+	)
+	respondUpdateResult(c, userVO, changed, "用户已恢复")
+}
+
+// parseDryRun 解析可选的 dry_run 查询参数；未携带时为 false，取值无法解析为布尔值时 ok 为 false
+func parseDryRun(c *gin.Context) (dryRun bool, ok bool) {
+	raw := c.Query("dry_run")
+	if raw == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(raw)
+	return dryRun, err == nil
 }
 
 // RegisterRoutes 注册与核心用户管理相关的路由到指定的 Gin 路由组。
@@ -371,7 +604,22 @@ func (ctrl *UserManageController) RegisterRoutes(group *gin.RouterGroup) {
 		// - 预期权限: 需要认证，且角色为管理员 (Admin)。
 		usersRoutes.PUT("/:userID/blacklist", ctrl.BlackUserHandler)
 
+		// 恢复用户 (解除拉黑或停用)
+		// - 场景: 管理员核实后恢复被拉黑或自行停用的用户，并记录原因。
+		// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内会再次校验角色。
+		usersRoutes.POST("/:userID/reactivate", ctrl.ReactivateUserHandler)
+
 		// 新增：管理员获取指定用户详细资料的路由
 		usersRoutes.GET("/:userID/profile", ctrl.GetUserProfileByAdminHandler)
+
+		// 获取用户聚合详情 (核心信息 + 资料 + 脱敏登录方式 + 会话数 + 最近登录)
+		// - 场景: 管理后台用户详情页，一次请求取代分别调用用户、资料、身份接口。
+		// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内会再次校验角色。
+		usersRoutes.GET("/:userID/detail", ctrl.GetUserDetailHandler)
+
+		// 批量设置用户角色
+		// - 场景: 管理员为一批新用户统一设置角色。
+		// - 预期权限: 需要认证，且角色为管理员 (Admin)，处理函数内会再次校验角色。
+		usersRoutes.POST("/batch-role", ctrl.BatchSetRoleHandler)
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/Xushengqwer/go-common/response"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
 	"github.com/Xushengqwer/user_hub/service/login/oAuth" // Corrected import path
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
@@ -49,10 +50,11 @@ func NewWechatAuthController(
 // @Accept json
 // @Produce json
 // @Param body body dto.WechatMiniProgramLoginData true "包含微信小程序 code 的请求体"
-// @Param X-Platform header string true "客户端平台类型" Enums(web, wechat, app) default(wechat)
+// @Param X-Platform header string false "客户端平台类型，仅支持 wechat，缺省时按 wechat 处理" Enums(wechat) default(wechat)
 // @Success 200 {object} docs.SwaggerAPILoginResponse "登录或注册成功，返回用户信息及访问和刷新令牌"
-// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 (如JSON格式错误、code为空、平台类型无效) 或 业务逻辑错误 (如微信 code 无效或已过期、用户状态异常)"
-// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误 (如调用微信API失败、数据库操作失败、令牌生成失败)"
+// @Failure 400 {object} docs.SwaggerAPILoginFailureResponse "请求参数无效 (如JSON格式错误、code为空、平台类型无效) 或 业务逻辑错误；data.reason: invalid_input / invalid_wechat_code / account_blacklisted / account_unavailable / service_unavailable"
+// @Failure 403 {object} docs.SwaggerAPIReactivationChallengeResponse "账号已停用，返回重新激活凭证；注册暂未开放时返回 SwaggerAPILoginFailureResponse，data.reason: registration_closed"
+// @Failure 500 {object} docs.SwaggerAPILoginFailureResponse "系统内部错误 (如微信 AppID/Secret 配置错误、数据库操作失败、令牌生成失败)；data.reason: internal_error"
 // @Router /api/v1/user-hub/wechat/login [post] // <--- 已更新路径
 func (ctrl *WechatAuthController) LoginOrRegisterHandler(c *gin.Context) {
 	const operation = "WechatAuthController.LoginOrRegisterHandler"
@@ -61,22 +63,21 @@ func (ctrl *WechatAuthController) LoginOrRegisterHandler(c *gin.Context) {
 	var wechatLoginData dto.WechatMiniProgramLoginData
 	if err := c.ShouldBindJSON(&wechatLoginData); err != nil {
 		ctrl.logger.Warn("微信登录/注册请求参数绑定失败", zap.String("operation", operation), zap.Error(err))
-		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, vo.LoginReasonInvalidInput, "输入参数无效")
 		return
 	}
 	// code 的有效性由服务层调用微信 API 时校验。
 
-	// 2. 获取并验证请求头中的 X-Platform 参数。
-	platformStr := c.GetHeader("X-Platform")
-	platform, err := enums.PlatformFromString(platformStr)
+	// 2. 获取并验证请求头中的 X-Platform 参数：微信登录只签发 wechat 平台令牌，缺省时按 wechat 处理。
+	platform, err := resolveLoginPlatform(c, enums.PlatformWechat, wechatLoginPlatforms)
 	if err != nil {
-		ctrl.logger.Warn("无效的平台类型",
+		ctrl.logger.Warn("微信登录的平台类型不匹配",
 			zap.String("operation", operation),
-			zap.String("platformHeader", platformStr),
+			zap.String("platformHeader", c.GetHeader("X-Platform")),
 			zap.String("code", wechatLoginData.Code), // 记录关联的 code
 			zap.Error(err),
 		)
-		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "无效的平台类型")
+		respondLoginFailure(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, vo.LoginReasonInvalidInput, err.Error())
 		return
 	}
 
@@ -84,6 +85,9 @@ func (ctrl *WechatAuthController) LoginOrRegisterHandler(c *gin.Context) {
 	//    服务层会处理 code 换取 openid、用户查找/创建、状态检查和令牌生成。
 	userInfo, tokenPair, err := ctrl.wechatService.LoginOrRegister(c.Request.Context(), wechatLoginData, platform)
 	if err != nil {
+		if respondIfAccountDeactivated(c, err) {
+			return
+		}
 		// 根据服务层返回的错误类型记录日志并响应。
 		if errors.Is(err, commonerrors.ErrSystemError) {
 			ctrl.logger.Error("微信登录/注册服务返回系统错误",
@@ -92,7 +96,7 @@ func (ctrl *WechatAuthController) LoginOrRegisterHandler(c *gin.Context) {
 				zap.Any("platform", platform),
 				zap.Error(err),
 			)
-			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+			respondLoginServiceError(c, err)
 		} else {
 			// 业务逻辑错误（例如，微信 code 无效、用户状态异常）。
 			ctrl.logger.Warn("微信登录/注册服务返回业务错误",
@@ -102,7 +106,7 @@ func (ctrl *WechatAuthController) LoginOrRegisterHandler(c *gin.Context) {
 				zap.Error(err), // 记录具体的业务错误信息
 			)
 			// 将服务层返回的、对用户友好的错误信息直接展示给用户。
-			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+			respondLoginServiceError(c, err)
 		}
 		return
 	}
@@ -122,6 +126,58 @@ func (ctrl *WechatAuthController) LoginOrRegisterHandler(c *gin.Context) {
 	response.RespondSuccess(c, responseData, "登录/注册成功")
 }
 
+// RefreshSessionHandler 为已登录的微信用户刷新缓存的 session_key。
+// @Summary 刷新微信会话
+// @Description 已登录用户的 session_key 过期后 (如解密微信加密数据失败)，用 wx.login() 重新获取的 code 刷新服务端缓存的会话。只更新当前用户已绑定的微信身份，绝不创建新账号或新身份。用户ID取自网关透传的认证信息。
+// @Tags 微信小程序认证
+// @Accept json
+// @Produce json
+// @Param body body dto.WechatRefreshSessionData true "重新获取的 code"
+// @Success 200 {object} docs.SwaggerAPIEmptyResponse "会话已刷新"
+// @Failure 400 {object} docs.SwaggerAPIErrorResponseString "请求参数无效 或 微信 code 无效或已过期"
+// @Failure 401 {object} docs.SwaggerAPIErrorResponseString "未授权或认证失败"
+// @Failure 403 {object} docs.SwaggerAPIErrorResponseString "code 对应的微信账号未绑定到当前用户"
+// @Failure 500 {object} docs.SwaggerAPIErrorResponseString "系统内部错误"
+// @Failure 502 {object} docs.SwaggerAPIErrorResponseString "微信接口暂时不可用"
+// @Router /api/v1/user-hub/wechat/refresh-session [post]
+func (ctrl *WechatAuthController) RefreshSessionHandler(c *gin.Context) {
+	const operation = "WechatAuthController.RefreshSessionHandler"
+
+	// 1. 从上下文获取当前用户 ID
+	userID, ok := getCallerUserID(c)
+	if !ok {
+		ctrl.logger.Error("无法从上下文中获取有效的UserID用于刷新微信会话", zap.String("operation", operation))
+		response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, "用户未认证")
+		return
+	}
+
+	// 2. 绑定请求体
+	var req dto.WechatRefreshSessionData
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.logger.Warn("刷新微信会话请求参数绑定失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "输入参数无效")
+		return
+	}
+
+	// 3. 调用服务层刷新会话
+	if err := ctrl.wechatService.RefreshSession(c.Request.Context(), userID, req.Code); err != nil {
+		switch {
+		case errors.Is(err, oAuth.ErrWechatSessionMismatch):
+			response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, err.Error())
+		case errors.Is(err, loginerr.ErrInvalidWechatCode):
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, err.Error())
+		case errors.Is(err, loginerr.ErrWechatUnavailable):
+			respondUpstreamError(c, http.StatusBadGateway, response.ErrCodeThirdPartyServiceError, loginerr.ErrWechatUnavailable.Error(), err)
+		default:
+			ctrl.logger.Error("刷新微信会话失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+		}
+		return
+	}
+
+	response.RespondSuccess[vo.Empty](c, vo.Empty{}, "微信会话已刷新")
+}
+
 // RegisterRoutes 注册与微信小程序认证相关的路由到指定的 Gin 路由组。
 // 设计目的:
 //   - 集中管理此控制器的 API 端点。
@@ -136,4 +192,9 @@ func (ctrl *WechatAuthController) RegisterRoutes(group *gin.RouterGroup) {
 	// - 方法: POST
 	// - 此接口通常不需要用户认证即可访问。
 	group.POST("/wechat/login", ctrl.LoginOrRegisterHandler)
+
+	// 刷新微信会话 (session_key)
+	// 场景: 已登录用户解密微信加密数据前发现 session_key 过期，用新的 code 刷新
+	// 预期权限: 需要认证，只能刷新当前用户已绑定的微信身份 (用户ID来自网关透传的认证信息)
+	group.POST("/wechat/refresh-session", ctrl.RefreshSessionHandler)
 }
@@ -0,0 +1,172 @@
+package dependencies
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/user_hub/config"
+	"go.uber.org/zap"
+)
+
+const (
+	tencentCDNDefaultEndpoint = "cdn.tencentcloudapi.com"
+	tencentCDNService         = "cdn"
+	tencentCDNAPIVersion      = "2018-06-06"
+)
+
+// CDNClient 定义 CDN 缓存刷新客户端接口
+type CDNClient interface {
+	// PurgeURLs 刷新指定 URL 的 CDN 缓存，使后续请求回源获取最新内容
+	// - 刷新是异步生效的，返回 nil 仅表示刷新任务已被 CDN 接受
+	PurgeURLs(ctx context.Context, urls []string) error
+}
+
+// NewCDNClient 根据配置创建 CDN 客户端
+// - provider 为空或 none 时返回不执行任何操作的客户端
+// - 腾讯云凭证未单独配置时复用 COS 的 SecretId/SecretKey
+func NewCDNClient(cfg *config.CDNConfig, cosCfg *config.COSConfig, logger *core.ZapLogger) (CDNClient, error) {
+	switch cfg.Provider {
+	case "", config.CDNProviderNone:
+		logger.Info("未启用 CDN 缓存刷新")
+		return noopCDNClient{}, nil
+	case config.CDNProviderTencent:
+		secretID, secretKey := cfg.SecretID, cfg.SecretKey
+		if secretID == "" && secretKey == "" {
+			secretID, secretKey = cosCfg.SecretID, cosCfg.SecretKey
+		}
+		if secretID == "" || secretKey == "" {
+			return nil, fmt.Errorf("腾讯云 CDN 配置不完整，缺少 SecretID 或 SecretKey")
+		}
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = tencentCDNDefaultEndpoint
+		}
+		logger.Info("已启用腾讯云 CDN 缓存刷新", zap.String("endpoint", endpoint))
+		return &tencentCDNClient{
+			secretID:   secretID,
+			secretKey:  secretKey,
+			endpoint:   endpoint,
+			httpClient: &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的 CDN 提供方 '%s'，可选值: %s, %s", cfg.Provider, config.CDNProviderNone, config.CDNProviderTencent)
+	}
+}
+
+// noopCDNClient 不执行任何操作的 CDN 客户端，用于未接入 CDN 的部署
+type noopCDNClient struct{}
+
+// PurgeURLs 直接返回 nil
+func (noopCDNClient) PurgeURLs(context.Context, []string) error { return nil }
+
+// tencentCDNClient 基于腾讯云 API 3.0 (TC3-HMAC-SHA256 签名) 的 CDN 客户端
+type tencentCDNClient struct {
+	secretID   string
+	secretKey  string
+	endpoint   string
+	httpClient *http.Client
+}
+
+// tencentCDNPurgeResponse 腾讯云 PurgeUrlsCache 接口的响应结构
+type tencentCDNPurgeResponse struct {
+	Response struct {
+		TaskID    string `json:"TaskId"`
+		RequestID string `json:"RequestId"`
+		Error     *struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Error"`
+	} `json:"Response"`
+}
+
+// PurgeURLs 调用腾讯云 PurgeUrlsCache 接口刷新 URL 缓存
+func (c *tencentCDNClient) PurgeURLs(ctx context.Context, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(map[string][]string{"Urls": urls})
+	if err != nil {
+		return fmt.Errorf("构造 CDN 刷新请求参数失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("创建 CDN 刷新请求失败: %w", err)
+	}
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Host", c.endpoint)
+	req.Header.Set("X-TC-Action", "PurgeUrlsCache")
+	req.Header.Set("X-TC-Version", tencentCDNAPIVersion)
+	req.Header.Set("X-TC-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set("Authorization", c.sign(payload, now))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("调用腾讯云 CDN 刷新接口失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取腾讯云 CDN 刷新响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("腾讯云 CDN 刷新接口返回非200状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+	var result tencentCDNPurgeResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("解析腾讯云 CDN 刷新响应失败: %w", err)
+	}
+	if result.Response.Error != nil {
+		return fmt.Errorf("腾讯云 CDN 刷新失败 (RequestId: %s): %s - %s",
+			result.Response.RequestID, result.Response.Error.Code, result.Response.Error.Message)
+	}
+	return nil
+}
+
+// sign 按 TC3-HMAC-SHA256 规则生成 Authorization 请求头
+func (c *tencentCDNClient) sign(payload []byte, now time.Time) string {
+	const algorithm = "TC3-HMAC-SHA256"
+	date := now.UTC().Format("2006-01-02")
+
+	// 1. 规范请求串
+	canonicalRequest := "POST\n/\n\n" +
+		"content-type:application/json; charset=utf-8\nhost:" + c.endpoint + "\n\n" +
+		"content-type;host\n" +
+		sha256Hex(payload)
+
+	// 2. 待签名字符串
+	credentialScope := date + "/" + tencentCDNService + "/tc3_request"
+	stringToSign := algorithm + "\n" + strconv.FormatInt(now.Unix(), 10) + "\n" + credentialScope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	// 3. 派生签名密钥并计算签名
+	secretDate := hmacSHA256([]byte("TC3"+c.secretKey), date)
+	secretService := hmacSHA256(secretDate, tencentCDNService)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=content-type;host, Signature=%s",
+		algorithm, c.secretID, credentialScope, signature)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(msg))
+	return mac.Sum(nil)
+}
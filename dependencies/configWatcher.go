@@ -0,0 +1,51 @@
+package dependencies
+
+import (
+	"fmt"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// ConfigSubscriber 配置文件热重载的订阅者，如功能开关、注册开关
+type ConfigSubscriber interface {
+	// ReloadConfig 配置文件变化后被调用，从 v 中重新解析自己关心的部分。
+	// - v 已读取最新的文件内容，只读使用，不要在其上注册回调或再次监听。
+	// - 解析失败时应记录日志并保留旧配置，不影响其他订阅者。
+	ReloadConfig(v *viper.Viper, file string)
+}
+
+// WatchConfigFile 使用单个 viper 实例监听配置文件，文件变化时依次通知所有订阅者。
+// - 同一文件只注册一个 fsnotify 监听，避免多个 viper 实例各自监听、各自解析。
+// - path 为空时不启用监听，订阅者保持启动时的配置。
+// - 首次读取配置文件失败时返回错误。
+func WatchConfigFile(path string, logger *core.ZapLogger, subscribers ...ConfigSubscriber) error {
+	const operation = "WatchConfigFile"
+	if path == "" {
+		logger.Info("未提供配置文件路径，配置热重载未启用", zap.String("operation", operation))
+		return nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("读取热重载配置文件 '%s' 失败: %w", path, err)
+	}
+
+	v.OnConfigChange(func(e fsnotify.Event) {
+		for _, subscriber := range subscribers {
+			subscriber.ReloadConfig(v, e.Name)
+		}
+	})
+	v.WatchConfig()
+
+	logger.Info("已启用配置热重载",
+		zap.String("operation", operation),
+		zap.String("file", path),
+		zap.Int("subscribers", len(subscribers)),
+	)
+	return nil
+}
@@ -0,0 +1,83 @@
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/spf13/viper"
+)
+
+// recordingSubscriber 记录每次热重载时读到的指定键的值
+type recordingSubscriber struct {
+	key    string
+	mu     sync.Mutex
+	values []string
+}
+
+func (s *recordingSubscriber) ReloadConfig(v *viper.Viper, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = append(s.values, v.GetString(s.key))
+}
+
+func (s *recordingSubscriber) last() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.values) == 0 {
+		return "", false
+	}
+	return s.values[len(s.values)-1], true
+}
+
+func TestWatchConfigFileNotifiesAllSubscribers(t *testing.T) {
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("a:\n  v: old\nb:\n  v: old\n"), 0o644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	first := &recordingSubscriber{key: "a.v"}
+	second := &recordingSubscriber{key: "b.v"}
+	if err := WatchConfigFile(path, logger, first, second); err != nil {
+		t.Fatalf("启动监听失败: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("a:\n  v: new-a\nb:\n  v: new-b\n"), 0o644); err != nil {
+		t.Fatalf("更新配置文件失败: %v", err)
+	}
+
+	// 同一次文件变化应通知到所有订阅者，且各自读到最新内容
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		a, _ := first.last()
+		b, _ := second.last()
+		if a == "new-a" && b == "new-b" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("等待热重载超时: first=%q second=%q", a, b)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWatchConfigFileMissingFileAndEmptyPath(t *testing.T) {
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	if err := WatchConfigFile("", logger, &recordingSubscriber{}); err != nil {
+		t.Errorf("路径为空时应跳过监听，实际返回 %v", err)
+	}
+	if err := WatchConfigFile(filepath.Join(t.TempDir(), "missing.yaml"), logger, &recordingSubscriber{}); err == nil {
+		t.Error("配置文件不存在时期望返回错误")
+	}
+}
@@ -20,12 +20,21 @@ import (
 // COSClientInterface 定义了COS客户端需要实现的方法
 type COSClientInterface interface {
 	GetClient() *cos.Client // 获取原始的 COS 客户端
-	// UploadFile 从 io.Reader 上传文件，并返回其公开可访问的 URL
-	UploadFile(ctx context.Context, objectKey string, reader io.Reader, size int64, contentType string) (string, error)
+	// UploadFile 从 io.Reader 上传文件，并返回对象 URL
+	// acl 为对象访问权限 (config.COSACLPrivate / COSACLPublicRead / COSACLDefault)，为空时继承存储桶权限；
+	// 私有对象返回的 URL 不能直接访问，需要通过 PresignGetURL 获取临时地址
+	UploadFile(ctx context.Context, objectKey string, reader io.Reader, size int64, contentType string, acl string) (string, error)
 	// UploadUserAvatar 专门用于上传用户头像，返回头像的公开可访问 URL
+	// 对象键由 COSConfig 中的前缀与命名模式 (versioned/latest) 决定
 	UploadUserAvatar(ctx context.Context, userID string, fileName string, reader io.Reader, size int64) (string, error)
 	// DeleteObject 从COS删除一个对象
 	DeleteObject(ctx context.Context, objectKey string) error
+	// ObjectKeyFromURL 从本客户端生成的公开访问 URL 反解出对象键，URL 不属于当前存储桶时返回 false
+	ObjectKeyFromURL(publicURL string) (string, bool)
+	// PresignGetURL 为私有对象生成带签名的临时 GET URL，expire <= 0 时使用配置的默认有效期
+	PresignGetURL(ctx context.Context, objectKey string, expire time.Duration) (string, error)
+	// Ping 对存储桶发起 HEAD 请求，验证凭证有效且存储桶可访问，用于启动检查
+	Ping(ctx context.Context) error
 }
 
 type cosClient struct {
@@ -46,6 +55,20 @@ func InitCOS(cfg *config.COSConfig, logger *core.ZapLogger) (COSClientInterface,
 		logger.Error("COS 配置不完整", zap.Any("配置详情", cfg))
 		return nil, fmt.Errorf("COS 配置不完整，缺少关键字段 (SecretID, SecretKey, BucketName, AppID, Region)")
 	}
+	switch cfg.AvatarNaming {
+	case "", config.AvatarNamingVersioned, config.AvatarNamingLatest:
+	default:
+		logger.Error("COS 头像命名模式无效", zap.String("avatarNaming", cfg.AvatarNaming))
+		return nil, fmt.Errorf("COS 头像命名模式 '%s' 无效，可选值: %s, %s", cfg.AvatarNaming, config.AvatarNamingVersioned, config.AvatarNamingLatest)
+	}
+	for objectType, acl := range cfg.ObjectACLs {
+		switch acl {
+		case "", config.COSACLDefault, config.COSACLPrivate, config.COSACLPublicRead:
+		default:
+			logger.Error("COS 对象 ACL 无效", zap.String("objectType", objectType), zap.String("acl", acl))
+			return nil, fmt.Errorf("COS 对象类型 '%s' 的 ACL '%s' 无效，可选值: %s, %s, %s", objectType, acl, config.COSACLDefault, config.COSACLPrivate, config.COSACLPublicRead)
+		}
+	}
 
 	sdkBucketURLStr := fmt.Sprintf("https://%s-%s.cos.%s.myqcloud.com", cfg.BucketName, cfg.AppID, cfg.Region)
 	sdkURL, err := url.Parse(sdkBucketURLStr)
@@ -114,16 +137,35 @@ func (c *cosClient) buildPublicObjectURL(objectKey string) string {
 	return finalURL.String()
 }
 
-// UploadFile 从 io.Reader 上传文件，并返回其公开可访问的 URL
-func (c *cosClient) UploadFile(ctx context.Context, objectKey string, reader io.Reader, size int64, contentType string) (string, error) {
-	c.logger.Info("开始上传文件到 COS", zap.String("对象键", objectKey), zap.Int64("文件大小", size), zap.String("内容类型", contentType))
+// ObjectKeyFromURL 是 buildPublicObjectURL 的逆操作，仅接受与公开访问基础 URL 同源且同路径前缀的地址
+func (c *cosClient) ObjectKeyFromURL(publicURL string) (string, bool) {
+	u, err := url.Parse(publicURL)
+	if err != nil || u.Scheme != c.publicAccessURLBase.Scheme || u.Host != c.publicAccessURLBase.Host {
+		return "", false
+	}
+	basePath := c.publicAccessURLBase.Path
+	if !strings.HasSuffix(basePath, "/") {
+		basePath += "/"
+	}
+	objectKey, found := strings.CutPrefix(u.Path, basePath)
+	if !found || objectKey == "" {
+		return "", false
+	}
+	return objectKey, true
+}
+
+// UploadFile 从 io.Reader 上传文件，并按 acl 设置对象访问权限
+func (c *cosClient) UploadFile(ctx context.Context, objectKey string, reader io.Reader, size int64, contentType string, acl string) (string, error) {
+	c.logger.Info("开始上传文件到 COS", zap.String("对象键", objectKey), zap.Int64("文件大小", size), zap.String("内容类型", contentType), zap.String("ACL", acl))
 	opts := &cos.ObjectPutOptions{
 		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{
 			ContentType:   contentType,
 			ContentLength: size,
 		},
-		// 对于公有读桶，通常不需要在这里特别设置ACL为public-read，对象会继承桶的权限。
-		// 如果桶是私有的，而你又想让这个特定对象公有读，可以设置 ACL: "public-read"
+	}
+	// 显式设置对象 ACL，不再依赖存储桶级别的公有读；default 或空值时继承存储桶权限
+	if acl != "" && acl != config.COSACLDefault {
+		opts.ACLHeaderOptions = &cos.ACLHeaderOptions{XCosACL: acl}
 	}
 
 	resp, err := c.client.Object.Put(ctx, objectKey, reader, opts)
@@ -150,14 +192,40 @@ func (c *cosClient) UploadFile(ctx context.Context, objectKey string, reader io.
 	return publicURL, nil
 }
 
+// buildAvatarObjectKey 按配置的前缀与命名模式生成头像对象键
+// - versioned: {prefix}{userID}/{时间戳}_{uuid}{ext}，或省略用户目录时 {prefix}{时间戳}_{uuid}{ext}
+// - latest:    {prefix}{userID}/avatar，或省略用户目录时 {prefix}{userID}；不带扩展名，保证更换图片格式时仍覆盖同一对象
+func (c *cosClient) buildAvatarObjectKey(userID string, ext string) string {
+	prefix := c.cfg.AvatarKeyPrefix
+	if prefix == "" {
+		prefix = "avatars/"
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	prefix = strings.TrimPrefix(prefix, "/")
+
+	if c.cfg.AvatarNaming == config.AvatarNamingLatest {
+		if c.cfg.AvatarOmitUserID {
+			return prefix + userID
+		}
+		return prefix + userID + "/avatar"
+	}
+
+	uniqueFileName := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), uuid.New().String(), ext)
+	if c.cfg.AvatarOmitUserID {
+		return prefix + uniqueFileName
+	}
+	return prefix + userID + "/" + uniqueFileName
+}
+
 // UploadUserAvatar 专门用于上传用户头像, 返回头像的公开可访问URL
 func (c *cosClient) UploadUserAvatar(ctx context.Context, userID string, fileName string, reader io.Reader, size int64) (string, error) {
 	ext := filepath.Ext(fileName)
 	if ext == "" {
 		c.logger.Warn("无法从文件名推断头像扩展名", zap.String("原始文件名", fileName), zap.String("用户ID", userID))
 	}
-	uniqueFileName := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), uuid.New().String(), ext)
-	objectKey := fmt.Sprintf("avatars/%s/%s", userID, uniqueFileName)
+	objectKey := c.buildAvatarObjectKey(userID, ext)
 
 	var contentType string
 	lowerExt := strings.ToLower(ext)
@@ -181,7 +249,20 @@ func (c *cosClient) UploadUserAvatar(ctx context.Context, userID string, fileNam
 		zap.String("COS对象键", objectKey),
 		zap.String("内容类型", contentType),
 	)
-	return c.UploadFile(ctx, objectKey, reader, size, contentType)
+	return c.UploadFile(ctx, objectKey, reader, size, contentType, c.cfg.ACLFor(config.COSObjectTypeAvatar))
+}
+
+// PresignGetURL 为对象生成带签名的临时 GET URL，用于读取私有对象
+func (c *cosClient) PresignGetURL(ctx context.Context, objectKey string, expire time.Duration) (string, error) {
+	if expire <= 0 {
+		expire = time.Duration(c.cfg.PresignExpireOrDefault()) * time.Second
+	}
+	presigned, err := c.client.Object.GetPresignedURL(ctx, http.MethodGet, objectKey, c.cfg.SecretID, c.cfg.SecretKey, expire, nil)
+	if err != nil {
+		c.logger.Error("生成 COS 预签名 GET URL 失败", zap.String("对象键", objectKey), zap.Error(err))
+		return "", fmt.Errorf("为对象 '%s' 生成预签名 URL 失败: %w", objectKey, err)
+	}
+	return presigned.String(), nil
 }
 
 // DeleteObject 从COS删除一个对象
@@ -202,3 +283,13 @@ func (c *cosClient) DeleteObject(ctx context.Context, objectKey string) error {
 	c.logger.Info("COS 对象删除成功", zap.String("对象键", objectKey))
 	return nil
 }
+
+// Ping 对存储桶发起 HEAD 请求，验证凭证有效且存储桶可访问
+func (c *cosClient) Ping(ctx context.Context) error {
+	resp, err := c.client.Bucket.Head(ctx)
+	if err != nil {
+		return fmt.Errorf("访问 COS 存储桶 '%s' 失败: %w", c.cfg.BucketName, err)
+	}
+	defer resp.Body.Close()
+	return nil
+}
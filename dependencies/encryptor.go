@@ -0,0 +1,125 @@
+package dependencies
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// encryptedPrefix 密文前缀，完整格式为 "enc:v1:<密钥ID>:<Base64(nonce||密文)>"
+const encryptedPrefix = "enc:v1:"
+
+// ErrEncryptionNotConfigured 未配置加密密钥时加解密返回的错误
+var ErrEncryptionNotConfigured = errors.New("未配置凭证加密密钥")
+
+// Encryptor 定义凭证落库加密的接口
+// - 密文中带有密钥 ID，解密时按 ID 选择密钥，因此轮换密钥后历史密文仍可解密。
+type Encryptor interface {
+	// Encrypt 使用当前生效的密钥加密明文。
+	Encrypt(plaintext string) (string, error)
+
+	// Decrypt 解密由 Encrypt 生成的密文；密钥 ID 未配置或密文被篡改时返回错误。
+	Decrypt(ciphertext string) (string, error)
+
+	// IsEncrypted 判断给定值是否为本加密器生成的密文格式，用于兼容启用加密前写入的明文数据。
+	IsEncrypted(value string) bool
+}
+
+// aesGCMEncryptor 是基于 AES-256-GCM 的 Encryptor 实现
+type aesGCMEncryptor struct {
+	activeKeyID string
+	aeads       map[string]cipher.AEAD
+}
+
+// NewEncryptor 根据配置创建 AES-GCM 加密器
+// - 未配置任何密钥时返回的加密器在加解密时返回 ErrEncryptionNotConfigured，不影响未启用加密的部署。
+// - 密钥长度不是 32 字节、ID 重复或 ActiveKeyID 不存在时返回错误。
+func NewEncryptor(cfg *config.CredentialEncryptionConfig) (Encryptor, error) {
+	e := &aesGCMEncryptor{activeKeyID: cfg.ActiveKeyID, aeads: make(map[string]cipher.AEAD, len(cfg.Keys))}
+	if !cfg.Enabled() {
+		return e, nil
+	}
+
+	for _, k := range cfg.Keys {
+		if k.ID == "" || strings.Contains(k.ID, ":") {
+			return nil, fmt.Errorf("凭证加密密钥 ID 无效: %q", k.ID)
+		}
+		if _, exists := e.aeads[k.ID]; exists {
+			return nil, fmt.Errorf("凭证加密密钥 ID 重复: %s", k.ID)
+		}
+		raw, err := base64.StdEncoding.DecodeString(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("凭证加密密钥 %s 不是有效的 Base64: %w", k.ID, err)
+		}
+		if len(raw) != 32 {
+			return nil, fmt.Errorf("凭证加密密钥 %s 长度应为 32 字节，实际为 %d", k.ID, len(raw))
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, fmt.Errorf("创建 AES 密钥 %s 失败: %w", k.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("创建 GCM 实例 %s 失败: %w", k.ID, err)
+		}
+		e.aeads[k.ID] = aead
+	}
+	if _, ok := e.aeads[cfg.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("凭证加密的 active_key_id %q 不在已配置的密钥中", cfg.ActiveKeyID)
+	}
+	return e, nil
+}
+
+// Encrypt 实现接口方法。
+// - 密钥 ID 作为附加认证数据，防止密文被改写为指向其他密钥。
+func (e *aesGCMEncryptor) Encrypt(plaintext string) (string, error) {
+	aead, ok := e.aeads[e.activeKeyID]
+	if !ok {
+		return "", ErrEncryptionNotConfigured
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(e.activeKeyID))
+	return encryptedPrefix + e.activeKeyID + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 实现接口方法。
+func (e *aesGCMEncryptor) Decrypt(ciphertext string) (string, error) {
+	if len(e.aeads) == 0 {
+		return "", ErrEncryptionNotConfigured
+	}
+	rest, ok := strings.CutPrefix(ciphertext, encryptedPrefix)
+	if !ok {
+		return "", errors.New("密文格式无效")
+	}
+	keyID, payload, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", errors.New("密文格式无效")
+	}
+	aead, ok := e.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("密文使用的密钥 %s 未配置", keyID)
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("密文格式无效")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("解密失败 (密钥 %s): %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted 实现接口方法。
+func (e *aesGCMEncryptor) IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
@@ -0,0 +1,184 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Xushengqwer/go-common/core"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// redactedParam 替换被隐藏参数的占位文本
+const redactedParam = "***"
+
+// sensitiveColumns 参数绝不能出现在日志中的列 (凭证、密码哈希、刷新令牌 JTI)
+var sensitiveColumns = map[string]struct{}{
+	"credential":    {},
+	"password_hash": {},
+	"jti":           {},
+}
+
+// sqlKeywords 推断占位符所属列时需要跳过的 SQL 关键字
+var sqlKeywords = map[string]struct{}{
+	"AND": {}, "OR": {}, "NOT": {}, "IN": {}, "IS": {}, "LIKE": {}, "BETWEEN": {},
+	"SET": {}, "WHERE": {}, "VALUES": {}, "LIMIT": {}, "OFFSET": {}, "NULL": {},
+}
+
+// redactingGormLogger 包装 GORM 日志记录器，实现 gorm.ParamsFilter，
+// 使所有 GORM 日志 (SQL 执行、慢查询、错误) 在展开参数前隐藏凭证类列的值。
+type redactingGormLogger struct {
+	logger.Interface
+	redactAll bool // 是否隐藏全部参数
+}
+
+// newRedactingGormLogger 创建包装后的 GORM 日志记录器。
+func newRedactingGormLogger(inner logger.Interface, redactAll bool) logger.Interface {
+	return &redactingGormLogger{Interface: inner, redactAll: redactAll}
+}
+
+// LogMode 保持包装层，避免 GORM 切换日志级别后丢失参数过滤。
+func (l *redactingGormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &redactingGormLogger{Interface: l.Interface.LogMode(level), redactAll: l.redactAll}
+}
+
+// ParamsFilter 实现 gorm.ParamsFilter，在 GORM 记录 SQL 前隐藏敏感参数。
+func (l *redactingGormLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, redactSQLParams(sql, params, l.redactAll)
+}
+
+// RegisterSQLErrorLogging 为 GORM 的各类操作注册错误回调：
+// 操作失败 (不含记录不存在) 时记录实际执行的 SQL (参数已按策略隐藏)、影响行数与 TraceID。
+func RegisterSQLErrorLogging(db *gorm.DB, zapLogger *core.ZapLogger, cfg config.SQLErrorLogConfig) error {
+	callback := func(tx *gorm.DB) {
+		if tx.Error == nil || errors.Is(tx.Error, gorm.ErrRecordNotFound) || tx.Statement.SQL.Len() == 0 {
+			return
+		}
+		sql := tx.Statement.SQL.String()
+		vars := redactSQLParams(sql, tx.Statement.Vars, cfg.RedactAllParams)
+
+		fields := []zap.Field{
+			zap.String("table", tx.Statement.Table),
+			zap.String("sql", tx.Dialector.Explain(sql, vars...)),
+			zap.Int64("rowsAffected", tx.RowsAffected),
+			zap.Error(tx.Error),
+		}
+		if spanCtx := trace.SpanContextFromContext(tx.Statement.Context); spanCtx.IsValid() {
+			fields = append(fields,
+				zap.String("trace_id", spanCtx.TraceID().String()),
+				zap.String("span_id", spanCtx.SpanID().String()),
+			)
+		}
+		zapLogger.Error("仓库操作执行 SQL 失败", fields...)
+	}
+
+	const name = "user_hub:log_sql_error"
+	registrations := []error{
+		db.Callback().Create().After("gorm:create").Register(name, callback),
+		db.Callback().Query().After("gorm:query").Register(name, callback),
+		db.Callback().Update().After("gorm:update").Register(name, callback),
+		db.Callback().Delete().After("gorm:delete").Register(name, callback),
+		db.Callback().Row().After("gorm:row").Register(name, callback),
+		db.Callback().Raw().After("gorm:raw").Register(name, callback),
+	}
+	return errors.Join(registrations...)
+}
+
+// redactSQLParams 返回隐藏敏感参数后的参数副本。
+// - redactAll 为 true 时隐藏全部参数。
+// - 否则按占位符推断其所属列，凭证类列的参数替换为 "***"。
+func redactSQLParams(sql string, params []interface{}, redactAll bool) []interface{} {
+	redacted := make([]interface{}, len(params))
+	copy(redacted, params)
+	if redactAll {
+		for i := range redacted {
+			redacted[i] = redactedParam
+		}
+		return redacted
+	}
+
+	columns := placeholderColumns(sql)
+	for i := range redacted {
+		if i < len(columns) {
+			if _, ok := sensitiveColumns[columns[i]]; ok {
+				redacted[i] = redactedParam
+			}
+		}
+	}
+	return redacted
+}
+
+// placeholderColumns 按顺序推断 SQL 中每个 "?" 占位符对应的列名 (小写，不含表前缀)。
+// - INSERT 语句按列清单的位置对应 (支持多行 VALUES)。
+// - 其他语句取占位符之前最近的标识符，例如 "`user_identities`.`credential` = ?" 对应 credential。
+// - 无法推断时对应空字符串。
+func placeholderColumns(sql string) []string {
+	var insertColumns []string
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sql)), "INSERT") {
+		if start := strings.Index(sql, "("); start >= 0 {
+			if end := strings.Index(sql[start:], ")"); end > 0 {
+				for _, col := range strings.Split(sql[start+1:start+end], ",") {
+					insertColumns = append(insertColumns, normalizeColumn(col))
+				}
+			}
+		}
+	}
+
+	var (
+		columns   []string
+		lastIdent string
+		token     strings.Builder
+		inQuote   byte
+	)
+	flush := func() {
+		if token.Len() == 0 {
+			return
+		}
+		word := token.String()
+		token.Reset()
+		if _, isKeyword := sqlKeywords[strings.ToUpper(word)]; !isKeyword {
+			lastIdent = normalizeColumn(word)
+		}
+	}
+
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		if inQuote != 0 {
+			if ch == inQuote {
+				inQuote = 0
+			}
+			continue
+		}
+		switch {
+		case ch == '\'' || ch == '"':
+			flush()
+			inQuote = ch
+		case ch == '`' || ch == '_' || ch == '.' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9':
+			token.WriteByte(ch)
+		case ch == '?':
+			flush()
+			if insertColumns != nil {
+				columns = append(columns, insertColumns[len(columns)%len(insertColumns)])
+			} else {
+				columns = append(columns, lastIdent)
+			}
+		default:
+			flush()
+		}
+	}
+	return columns
+}
+
+// normalizeColumn 去除反引号、空白与表前缀，并转为小写。
+func normalizeColumn(ident string) string {
+	ident = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(ident), "`", ""))
+	if dot := strings.LastIndex(ident, "."); dot >= 0 {
+		ident = ident[dot+1:]
+	}
+	return ident
+}
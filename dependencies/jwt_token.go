@@ -1,11 +1,12 @@
 package dependencies
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"github.com/Xushengqwer/go-common/models/enums"
 	"github.com/Xushengqwer/user_hub/config"
-	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/utils"
 	"github.com/google/uuid"
 	"time"
 
@@ -16,6 +17,19 @@ import (
 //	改进建议：使用 UUID 或其他唯一 ID 生成器（如 import "github.com/google/uuid"）
 //	jti := uuid.New().String()
 
+// 令牌解析失败的分类错误
+// - parseToken 会将 jwt 库返回的错误包装为以下错误之一 (原始错误仍保留在错误链中)，调用方可用 errors.Is 分支处理
+// - 错误信息包含具体原因，仅用于日志与内部判断，不应直接返回给终端用户
+var (
+	ErrTokenExpired          = errors.New("令牌已过期")
+	ErrTokenNotYetValid      = errors.New("令牌尚未生效")
+	ErrTokenMalformed        = errors.New("令牌格式错误")
+	ErrTokenSignatureInvalid = errors.New("令牌签名无效")
+	ErrTokenIssuerMismatch   = errors.New("令牌发行者不匹配")
+	ErrTokenAudienceMismatch = errors.New("令牌受众不匹配")
+	ErrTokenInvalid          = errors.New("令牌无效") // 无法归入以上类别的其他校验失败
+)
+
 // JWTTokenInterface 定义 JWT 工具的接口
 // - 用于生成和解析 JWT 令牌，提供访问令牌和刷新令牌的相关功能
 type JWTTokenInterface interface {
@@ -31,13 +45,21 @@ type JWTTokenInterface interface {
 
 	// ParseAccessToken 解析并验证访问令牌
 	// - 输入: tokenString 待解析的令牌字符串
-	// - 输出: 解析后的 CustomClaims 和可能的错误
+	// - 输出: 解析后的 CustomClaims 和可能的错误，失败时错误链中包含 ErrTokenExpired 等分类错误
 	ParseAccessToken(tokenString string) (*CustomClaims, error)
 
 	// ParseRefreshToken 解析并验证刷新令牌
 	// - 输入: tokenString 待解析的令牌字符串
-	// - 输出: 解析后的 CustomClaims 和可能的错误
+	// - 输出: 解析后的 CustomClaims 和可能的错误，失败时错误链中包含 ErrTokenExpired 等分类错误
 	ParseRefreshToken(tokenString string) (*CustomClaims, error)
+
+	// RefreshTokenTTL 返回指定平台的刷新令牌有效期
+	// - 用于控制器设置 Web 端 Refresh Token Cookie 的 MaxAge，保证与令牌本身的过期时间一致
+	RefreshTokenTTL(platform enums.Platform) time.Duration
+
+	// Leeway 返回解析令牌时容忍的时钟偏差
+	// - 令牌过期后的这段时间内仍会被接受，吊销 (加入黑名单) 时需要把它计入黑名单有效期
+	Leeway() time.Duration
 }
 
 // CustomClaims 定义 JWT 的声明结构体，包含标准字段和自定义字段
@@ -51,21 +73,25 @@ type CustomClaims struct {
 
 // JWTUtility 实现 JWTTokenInterface 接口的结构体
 type JWTUtility struct {
-	cfg *config.JWTConfig // JWT 配置，包含密钥、发行者等信息
+	cfg   *config.JWTConfig // JWT 配置，包含密钥、发行者等信息
+	clock utils.Clock       // 时间来源，用于签发时间、过期时间与解析时的有效期校验
 }
 
 // NewJWTUtility 创建 JWTUtility 实例，通过依赖注入初始化
-// - 输入: cfg JWT 配置实例
+// - 输入: cfg JWT 配置实例, clock 时间来源 (为 nil 时使用系统时间)
 // - 输出: JWTTokenInterface 接口实例
-func NewJWTUtility(cfg *config.JWTConfig) JWTTokenInterface {
-	return &JWTUtility{cfg: cfg}
+func NewJWTUtility(cfg *config.JWTConfig, clock utils.Clock) JWTTokenInterface {
+	if clock == nil {
+		clock = utils.SystemClock
+	}
+	return &JWTUtility{cfg: cfg, clock: clock}
 }
 
 // GenerateAccessToken 生成访问令牌
 // - 输入: userID 用户ID, role 用户角色, status 用户状态, platform 客户端平台
 // - 输出: 访问令牌字符串和可能的错误
 func (ju *JWTUtility) GenerateAccessToken(userID string, role enums.UserRole, status enums.UserStatus, platform enums.Platform) (string, error) {
-	now := time.Now()
+	now := ju.clock.Now()
 
 	// 创建自定义声明
 	claims := &CustomClaims{
@@ -74,13 +100,17 @@ func (ju *JWTUtility) GenerateAccessToken(userID string, role enums.UserRole, st
 		Status:   status,
 		Platform: platform,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    ju.cfg.Issuer,                                         // 令牌发行者，从配置中获取
-			IssuedAt:  jwt.NewNumericDate(now),                               // 签发时间
-			ExpiresAt: jwt.NewNumericDate(now.Add(constants.AccessTokenTTL)), // 过期时间，使用常量定义的 TTL
-			ID:        uuid.New().String(),                                   // 默认生成唯一 JTI
+			Issuer:    ju.cfg.Issuer,                                                   // 令牌发行者，从配置中获取
+			IssuedAt:  jwt.NewNumericDate(now),                                         // 签发时间
+			ExpiresAt: jwt.NewNumericDate(now.Add(ju.cfg.AccessTokenTTLFor(platform))), // 过期时间，按平台选择 TTL
+			ID:        uuid.New().String(),                                             // 默认生成唯一 JTI
 		},
 	}
 
+	if ju.cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{ju.cfg.Audience} // 令牌受众，从配置中获取
+	}
+
 	// 创建令牌，使用 HS256 签名算法
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -97,20 +127,24 @@ func (ju *JWTUtility) GenerateAccessToken(userID string, role enums.UserRole, st
 // - 输入: userID 用户ID, platform 客户端平台
 // - 输出: 刷新令牌字符串和可能的错误
 func (ju *JWTUtility) GenerateRefreshToken(userID string, platform enums.Platform) (string, error) {
-	now := time.Now()
+	now := ju.clock.Now()
 
 	// 创建自定义声明
 	claims := &CustomClaims{
 		UserID:   userID,
 		Platform: platform,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    ju.cfg.Issuer,                                          // 令牌发行者，从配置中获取
-			IssuedAt:  jwt.NewNumericDate(now),                                // 签发时间
-			ExpiresAt: jwt.NewNumericDate(now.Add(constants.RefreshTokenTTL)), // 过期时间，使用常量定义的 TTL
-			ID:        uuid.New().String(),                                    // 默认生成唯一 JTI
+			Issuer:    ju.cfg.Issuer,                                             // 令牌发行者，从配置中获取
+			IssuedAt:  jwt.NewNumericDate(now),                                   // 签发时间
+			ExpiresAt: jwt.NewNumericDate(now.Add(ju.RefreshTokenTTL(platform))), // 过期时间，按平台选择 TTL
+			ID:        uuid.New().String(),                                       // 默认生成唯一 JTI
 		},
 	}
 
+	if ju.cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{ju.cfg.Audience} // 令牌受众，从配置中获取
+	}
+
 	// 创建令牌，使用 HS256 签名算法
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -123,6 +157,18 @@ func (ju *JWTUtility) GenerateRefreshToken(userID string, platform enums.Platfor
 	return signedToken, nil
 }
 
+// RefreshTokenTTL 返回指定平台的刷新令牌有效期
+// - 输入: platform 客户端平台
+// - 输出: 平台配置的有效期，未配置时回退到默认值
+func (ju *JWTUtility) RefreshTokenTTL(platform enums.Platform) time.Duration {
+	return ju.cfg.RefreshTokenTTLFor(platform)
+}
+
+// Leeway 返回解析令牌时容忍的时钟偏差
+func (ju *JWTUtility) Leeway() time.Duration {
+	return ju.cfg.LeewayOrDefault()
+}
+
 // ParseAccessToken 解析并验证访问令牌
 // - 输入: tokenString 待解析的令牌字符串
 // - 输出: 解析后的 CustomClaims 和可能的错误
@@ -130,10 +176,7 @@ func (ju *JWTUtility) ParseAccessToken(tokenString string) (*CustomClaims, error
 	secret := []byte(ju.cfg.SecretKey)
 
 	// 创建解析器，启用 v5 的严格验证选项
-	parser := jwt.NewParser(
-		jwt.WithExpirationRequired(),  // 强制要求令牌包含过期时间
-		jwt.WithIssuer(ju.cfg.Issuer), // 验证发行者是否匹配配置中的值
-	)
+	parser := jwt.NewParser(ju.parserOptions()...)
 
 	// 解析令牌
 	return ju.parseToken(tokenString, secret, parser)
@@ -146,15 +189,25 @@ func (ju *JWTUtility) ParseRefreshToken(tokenString string) (*CustomClaims, erro
 	secret := []byte(ju.cfg.RefreshSecret)
 
 	// 创建解析器，启用 v5 的严格验证选项
-	parser := jwt.NewParser(
-		jwt.WithExpirationRequired(),  // 强制要求令牌包含过期时间
-		jwt.WithIssuer(ju.cfg.Issuer), // 验证发行者是否匹配配置中的值
-	)
+	parser := jwt.NewParser(ju.parserOptions()...)
 
 	// 解析令牌
 	return ju.parseToken(tokenString, secret, parser)
 }
 
+// parserOptions 返回访问令牌与刷新令牌共用的解析选项
+// - 强制要求令牌包含过期时间，并验证发行者是否匹配配置中的值
+// - 校验 exp / nbf / iat 时容忍配置的时钟偏差，避免与签发方时钟略有漂移的节点误拒令牌
+// - 受众由 parseToken 在解析后统一校验，单个与多个受众的行为一致 (缺少 aud 同样视为受众不匹配)
+func (ju *JWTUtility) parserOptions() []jwt.ParserOption {
+	return []jwt.ParserOption{
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(ju.cfg.Issuer),
+		jwt.WithLeeway(ju.cfg.LeewayOrDefault()),
+		jwt.WithTimeFunc(ju.clock.Now),
+	}
+}
+
 // parseToken 辅助函数，用于解析和验证 JWT 令牌
 // - 输入: tokenString 待解析的令牌字符串, secret 签名密钥, parser v5 的解析器实例
 // - 输出: 解析后的 CustomClaims 和可能的错误
@@ -168,16 +221,56 @@ func (ju *JWTUtility) parseToken(tokenString string, secret []byte, parser *jwt.
 		return secret, nil
 	})
 
-	// 如果解析失败，返回错误
+	// 如果解析失败，返回分类后的错误
 	if err != nil {
-		return nil, err
+		return nil, classifyTokenError(err)
 	}
 
 	// 类型断言并验证令牌有效性
 	claims, ok := token.Claims.(*CustomClaims)
 	if !ok || !token.Valid {
-		return nil, errors.New("无效的JWT声明")
+		return nil, ErrTokenInvalid
+	}
+
+	// 配置了受众时 aud 必须命中其中之一；jwt v5 的 WithAudience 只接受单个受众且缺少 aud 时报告为缺少声明，因此不使用
+	if audiences := ju.cfg.ValidAudiences(); len(audiences) > 0 && !audienceMatches(claims.Audience, audiences) {
+		return nil, fmt.Errorf("%w: %w", ErrTokenAudienceMismatch, jwt.ErrTokenInvalidAudience)
 	}
 
 	return claims, nil
 }
+
+// classifyTokenError 将 jwt v5 的错误归类为本包定义的分类错误，原始错误保留在错误链中
+func classifyTokenError(err error) error {
+	var category error
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		category = ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		category = ErrTokenNotYetValid
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		category = ErrTokenMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		// 签名算法不匹配由 keyfunc 返回，jwt 库将其归为 ErrTokenUnverifiable
+		category = ErrTokenSignatureInvalid
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		category = ErrTokenIssuerMismatch
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		category = ErrTokenAudienceMismatch
+	default:
+		category = ErrTokenInvalid
+	}
+	return fmt.Errorf("%w: %w", category, err)
+}
+
+// audienceMatches 判断令牌的 aud 声明是否命中任一允许的受众
+func audienceMatches(tokenAud jwt.ClaimStrings, allowed []string) bool {
+	for _, aud := range tokenAud {
+		for _, a := range allowed {
+			if subtle.ConstantTimeCompare([]byte(aud), []byte(a)) == 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package dependencies
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Xushengqwer/go-common/models/enums"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// newTestJWTConfig 返回测试用的 JWT 配置，访问令牌与刷新令牌有效期均为 10 分钟
+func newTestJWTConfig() *config.JWTConfig {
+	return &config.JWTConfig{
+		SecretKey:     "access-secret",
+		RefreshSecret: "refresh-secret",
+		Issuer:        "user_hub_test",
+		DefaultTTL:    config.TokenTTLConfig{AccessTokenTTL: 10 * time.Minute, RefreshTokenTTL: 10 * time.Minute},
+	}
+}
+
+func TestParseTokenLeeway(t *testing.T) {
+	const ttl = 10 * time.Minute
+
+	tests := []struct {
+		name        string
+		leeway      time.Duration
+		elapsed     time.Duration // 签发后经过的时间
+		wantExpired bool
+	}{
+		{name: "未过期", leeway: 30 * time.Second, elapsed: ttl - time.Second},
+		{name: "过期时间少于容忍时间", leeway: 30 * time.Second, elapsed: ttl + 29*time.Second},
+		{name: "过期时间超过容忍时间", leeway: 30 * time.Second, elapsed: ttl + 31*time.Second, wantExpired: true},
+		{name: "负数容忍时间不容忍偏差", leeway: -1, elapsed: ttl + time.Second, wantExpired: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestJWTConfig()
+			cfg.Leeway = tt.leeway
+			clock := utils.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+			ju := NewJWTUtility(cfg, clock)
+
+			accessToken, err := ju.GenerateAccessToken("u1", enums.RoleUser, enums.StatusActive, enums.PlatformWeb)
+			if err != nil {
+				t.Fatalf("签发访问令牌失败: %v", err)
+			}
+			refreshToken, err := ju.GenerateRefreshToken("u1", enums.PlatformWeb)
+			if err != nil {
+				t.Fatalf("签发刷新令牌失败: %v", err)
+			}
+			clock.Advance(tt.elapsed)
+
+			for kind, parse := range map[string]func() (*CustomClaims, error){
+				"access":  func() (*CustomClaims, error) { return ju.ParseAccessToken(accessToken) },
+				"refresh": func() (*CustomClaims, error) { return ju.ParseRefreshToken(refreshToken) },
+			} {
+				claims, err := parse()
+				if tt.wantExpired {
+					if !errors.Is(err, ErrTokenExpired) {
+						t.Errorf("%s: 期望 ErrTokenExpired，实际为 %v", kind, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Errorf("%s: 期望解析成功，实际错误: %v", kind, err)
+					continue
+				}
+				if claims.UserID != "u1" {
+					t.Errorf("%s: UserID = %q，期望 u1", kind, claims.UserID)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTokenAudience(t *testing.T) {
+	tests := []struct {
+		name         string
+		issuedAud    string   // 签发方写入的受众，为空时令牌不含 aud
+		audience     string   // 解析方配置的 Audience
+		accepted     []string // 解析方配置的 AcceptedAudiences
+		wantMismatch bool
+	}{
+		// 未配置受众：不校验 aud
+		{name: "未配置受众_令牌无受众", issuedAud: ""},
+		{name: "未配置受众_令牌带任意受众", issuedAud: "other"},
+
+		// 单受众
+		{name: "单受众_匹配", issuedAud: "web", audience: "web"},
+		{name: "单受众_不匹配", issuedAud: "app", audience: "web", wantMismatch: true},
+		{name: "单受众_令牌无受众", issuedAud: "", audience: "web", wantMismatch: true},
+		{name: "单受众_仅配置接受列表", issuedAud: "web", accepted: []string{"web"}},
+		{name: "单受众_重复配置去重后仍为单受众", issuedAud: "app", audience: "web", accepted: []string{"web"}, wantMismatch: true},
+
+		// 多受众
+		{name: "多受众_命中主受众", issuedAud: "web", audience: "web", accepted: []string{"app"}},
+		{name: "多受众_命中接受列表", issuedAud: "app", audience: "web", accepted: []string{"app"}},
+		{name: "多受众_均不匹配", issuedAud: "admin", audience: "web", accepted: []string{"app"}, wantMismatch: true},
+		{name: "多受众_令牌无受众", issuedAud: "", audience: "web", accepted: []string{"app"}, wantMismatch: true},
+		{name: "多受众_大小写不同视为不匹配", issuedAud: "WEB", audience: "web", accepted: []string{"app"}, wantMismatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := utils.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+			issuerCfg := newTestJWTConfig()
+			issuerCfg.Audience = tt.issuedAud
+			issuer := NewJWTUtility(issuerCfg, clock)
+
+			parserCfg := newTestJWTConfig()
+			parserCfg.Audience = tt.audience
+			parserCfg.AcceptedAudiences = tt.accepted
+			parser := NewJWTUtility(parserCfg, clock)
+
+			accessToken, err := issuer.GenerateAccessToken("u1", enums.RoleUser, enums.StatusActive, enums.PlatformWeb)
+			if err != nil {
+				t.Fatalf("签发访问令牌失败: %v", err)
+			}
+			refreshToken, err := issuer.GenerateRefreshToken("u1", enums.PlatformWeb)
+			if err != nil {
+				t.Fatalf("签发刷新令牌失败: %v", err)
+			}
+
+			for kind, parse := range map[string]func() (*CustomClaims, error){
+				"access":  func() (*CustomClaims, error) { return parser.ParseAccessToken(accessToken) },
+				"refresh": func() (*CustomClaims, error) { return parser.ParseRefreshToken(refreshToken) },
+			} {
+				_, err := parse()
+				if tt.wantMismatch {
+					if !errors.Is(err, ErrTokenAudienceMismatch) {
+						t.Errorf("%s: 期望 ErrTokenAudienceMismatch，实际为 %v", kind, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Errorf("%s: 期望解析成功，实际错误: %v", kind, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAudienceMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokenAud []string
+		allowed  []string
+		want     bool
+	}{
+		{name: "命中其一", tokenAud: []string{"web"}, allowed: []string{"app", "web"}, want: true},
+		{name: "令牌多受众命中其一", tokenAud: []string{"admin", "app"}, allowed: []string{"app", "web"}, want: true},
+		{name: "均不命中", tokenAud: []string{"admin"}, allowed: []string{"app", "web"}, want: false},
+		{name: "令牌无受众", tokenAud: nil, allowed: []string{"app", "web"}, want: false},
+		{name: "前缀不算命中", tokenAud: []string{"we"}, allowed: []string{"app", "web"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := audienceMatches(tt.tokenAud, tt.allowed); got != tt.want {
+				t.Errorf("audienceMatches(%v, %v) = %v，期望 %v", tt.tokenAud, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
 )
 
 // InitMySQL 初始化 MySQL 连接并返回 *gorm.DB
@@ -24,7 +25,8 @@ func InitMySQL(cfg *config.UserHubConfig, logger *core.ZapLogger) (*gorm.DB, err
 	gormLogger := core.NewGormLogger(logger, cfg.GormLogConfig) // 假设 cfg.GormLogConfig 仍然存在且适用
 
 	gormConfig := &gorm.Config{
-		Logger: gormLogger, // 使用 GormLogger 作为 GORM 的日志接口
+		// 使用 GormLogger 作为 GORM 的日志接口，并在记录 SQL 前隐藏凭证类参数
+		Logger: newRedactingGormLogger(gormLogger, cfg.MySQLConfig.ErrorLog.RedactAllParams),
 	}
 
 	// 连接数据库
@@ -34,7 +36,7 @@ func InitMySQL(cfg *config.UserHubConfig, logger *core.ZapLogger) (*gorm.DB, err
 	maxRetries := 5
 	retryInterval := 2 * time.Second
 
-	logger.Info("Attempting to connect to MySQL using DSN", zap.String("dsn_preview", previewDSN(cfg.MySQLConfig.DSN))) // 记录DSN预览，注意隐藏密码
+	logger.Info("Attempting to connect to MySQL using DSN", zap.String("dsn_preview", config.RedactDSN(cfg.MySQLConfig.DSN))) // 记录DSN预览，注意隐藏密码
 
 	for i := 0; i < maxRetries; i++ {
 		db, err = gorm.Open(mysql.Open(cfg.MySQLConfig.DSN), gormConfig)
@@ -53,7 +55,7 @@ func InitMySQL(cfg *config.UserHubConfig, logger *core.ZapLogger) (*gorm.DB, err
 			zap.Int("retry", i+1),
 			zap.Int("maxRetries", maxRetries),
 			zap.Error(err),
-			zap.String("dsn_preview", previewDSN(cfg.MySQLConfig.DSN)),
+			zap.String("dsn_preview", config.RedactDSN(cfg.MySQLConfig.DSN)),
 		)
 		if i < maxRetries-1 { // 最后一次失败时不再等待
 			time.Sleep(retryInterval)
@@ -63,9 +65,9 @@ func InitMySQL(cfg *config.UserHubConfig, logger *core.ZapLogger) (*gorm.DB, err
 	if err != nil {
 		logger.Error("无法连接到数据库 (DSN)",
 			zap.Error(err),
-			zap.String("dsn_preview", previewDSN(cfg.MySQLConfig.DSN)),
+			zap.String("dsn_preview", config.RedactDSN(cfg.MySQLConfig.DSN)),
 		)
-		return nil, fmt.Errorf("无法连接到数据库 (DSN: %s): %w", previewDSN(cfg.MySQLConfig.DSN), err)
+		return nil, fmt.Errorf("无法连接到数据库 (DSN: %s): %w", config.RedactDSN(cfg.MySQLConfig.DSN), err)
 	}
 
 	// 获取通用数据库对象 sql.DB 以便进行底层操作
@@ -80,50 +82,62 @@ func InitMySQL(cfg *config.UserHubConfig, logger *core.ZapLogger) (*gorm.DB, err
 	sqlDB.SetMaxOpenConns(cfg.MySQLConfig.MaxOpenConn)
 	sqlDB.SetConnMaxLifetime(time.Hour) // 建议这个值也加入配置
 
+	// 仓库操作失败时记录实际执行的 SQL (参数已隐藏凭证) 与 TraceID
+	if cfg.MySQLConfig.ErrorLog.Enabled {
+		if err := RegisterSQLErrorLogging(db, logger, cfg.MySQLConfig.ErrorLog); err != nil {
+			logger.Error("注册 SQL 错误日志回调失败", zap.Error(err))
+			return nil, fmt.Errorf("注册 SQL 错误日志回调失败: %w", err)
+		}
+	}
+
+	// 统计每个请求执行的 SQL 次数，配合 QueryCountMiddleware 发现 N+1 查询 (仅用于非生产环境)
+	if cfg.MySQLConfig.QueryCount.Enabled {
+		if err := RegisterQueryCounting(db); err != nil {
+			logger.Error("注册 SQL 计数回调失败", zap.Error(err))
+			return nil, fmt.Errorf("注册 SQL 计数回调失败: %w", err)
+		}
+	}
+
 	// 自动迁移数据库表结构
 	// 注意：确保你的 GORM 版本与 entities 定义兼容
 	err = db.AutoMigrate(
 		&entities.User{},
 		&entities.UserIdentity{},
 		&entities.UserProfile{},
+		&entities.AdminAuditLog{},
+		&entities.PasswordHistory{},
+		&entities.RefreshToken{},
+		&entities.StorageObject{},
+		&entities.SMSDeadLetter{},
+		&entities.APIKey{},
 	)
 	if err != nil {
 		logger.Error("数据库迁移失败", zap.Error(err))
 		return nil, fmt.Errorf("数据库迁移失败: %w", err)
 	}
 
-	logger.Info("成功连接到 MySQL 数据库 (使用DSN) 并完成自动迁移")
-	return db, nil
-}
-
-// previewDSN 返回一个用于日志记录的DSN预览版本，隐藏密码。
-// 这是一个简单的实现，你可能需要根据你的DSN格式进行调整。
-func previewDSN(dsn string) string {
-	// 尝试找到 "@" 符号，密码通常在它之前
-	atIndex := -1
-	for i := len(dsn) - 1; i >= 0; i-- {
-		if dsn[i] == '@' {
-			atIndex = i
-			break
+	// 标识符唯一性改为按租户限定 (idx_tenant_identifier)，AutoMigrate 不会删除旧的全局唯一索引，需要手动移除
+	if migrator := db.Migrator(); migrator.HasIndex(&entities.UserIdentity{}, "idx_type_identifier") {
+		if err := migrator.DropIndex(&entities.UserIdentity{}, "idx_type_identifier"); err != nil {
+			logger.Error("删除旧的身份标识符唯一索引失败", zap.Error(err))
+			return nil, fmt.Errorf("删除旧的身份标识符唯一索引失败: %w", err)
 		}
+		logger.Info("已删除旧的全局身份标识符唯一索引 idx_type_identifier")
 	}
 
-	if atIndex == -1 { // 没有找到 "@"，可能DSN格式不同或不含密码
-		return dsn
-	}
-
-	// 尝试找到密码开始的位置，通常在第一个 ":" 之后，但在 "@" 之前
-	passwordStartIndex := -1
-	for i := 0; i < atIndex; i++ {
-		if dsn[i] == ':' {
-			passwordStartIndex = i + 1
-			break
-		}
+	// verified 字段新增时默认为 false，此前注册的账号密码身份需要补记为已验证 (注册时即视为已验证)，
+	// 否则开启 require_verified_identity 后这些用户将无法登录且没有自助验证途径；条件更新可重复执行
+	backfill := db.Model(&entities.UserIdentity{}).
+		Where("identity_type = ? AND verified = ?", enums.AccountPassword, false).
+		Update("verified", true)
+	if backfill.Error != nil {
+		logger.Error("补记账号密码身份为已验证失败", zap.Error(backfill.Error))
+		return nil, fmt.Errorf("补记账号密码身份为已验证失败: %w", backfill.Error)
 	}
-
-	if passwordStartIndex == -1 || passwordStartIndex >= atIndex { // 没有找到密码的明确分隔符
-		return dsn
+	if backfill.RowsAffected > 0 {
+		logger.Info("已将历史账号密码身份补记为已验证", zap.Int64("rows", backfill.RowsAffected))
 	}
 
-	return dsn[:passwordStartIndex] + "****" + dsn[atIndex:]
+	logger.Info("成功连接到 MySQL 数据库 (使用DSN) 并完成自动迁移")
+	return db, nil
 }
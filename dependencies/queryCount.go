@@ -0,0 +1,52 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// queryCounterKey 请求上下文中 SQL 计数器的键
+type queryCounterKey struct{}
+
+// QueryCounter 记录单个请求执行的 SQL 次数，可被并发的查询安全地累加
+type QueryCounter struct {
+	count atomic.Int64
+}
+
+// Count 返回目前累计的 SQL 次数
+func (c *QueryCounter) Count() int64 {
+	return c.count.Load()
+}
+
+// WithQueryCounter 返回携带新计数器的上下文；仓库操作使用该上下文 (db.WithContext) 时会被计数
+func WithQueryCounter(ctx context.Context) (context.Context, *QueryCounter) {
+	counter := &QueryCounter{}
+	return context.WithValue(ctx, queryCounterKey{}, counter), counter
+}
+
+// RegisterQueryCounting 为 GORM 的各类操作注册计数回调：
+// 语句上下文中带有 QueryCounter 时每执行一条 SQL 计数加一，没有计数器的调用 (如后台任务) 不受影响。
+func RegisterQueryCounting(db *gorm.DB) error {
+	callback := func(tx *gorm.DB) {
+		if tx.Statement.Context == nil || tx.DryRun {
+			return
+		}
+		if counter, ok := tx.Statement.Context.Value(queryCounterKey{}).(*QueryCounter); ok {
+			counter.count.Add(1)
+		}
+	}
+
+	const name = "user_hub:count_query"
+	registrations := []error{
+		db.Callback().Create().Before("gorm:create").Register(name, callback),
+		db.Callback().Query().Before("gorm:query").Register(name, callback),
+		db.Callback().Update().Before("gorm:update").Register(name, callback),
+		db.Callback().Delete().Before("gorm:delete").Register(name, callback),
+		db.Callback().Row().Before("gorm:row").Register(name, callback),
+		db.Callback().Raw().Before("gorm:raw").Register(name, callback),
+	}
+	return errors.Join(registrations...)
+}
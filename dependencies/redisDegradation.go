@@ -0,0 +1,107 @@
+package dependencies
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// RedisFeature 依赖 Redis 的功能标识，用于日志与指标
+type RedisFeature string
+
+// 依赖 Redis 的功能
+const (
+	RedisFeatureCaptchaSendQuota   RedisFeature = "captcha_send_quota"   // 按手机号的验证码发送冷却与每日上限
+	RedisFeatureCaptchaGlobalQuota RedisFeature = "captcha_global_quota" // 全局验证码发送总量
+	RedisFeatureProfileCooldown    RedisFeature = "profile_cooldown"     // 资料与头像修改冷却
+	RedisFeatureTokenBlacklist     RedisFeature = "token_blacklist"      // 令牌黑名单 (安全关键)
+	RedisFeaturePasswordVerify     RedisFeature = "password_verify"      // 当前密码校验限流 (安全关键，防止暴力猜测)
+)
+
+// redisFailureMode 功能在 Redis 出错时的失败模式
+type redisFailureMode int
+
+const (
+	redisFailClosed       redisFailureMode = iota // 安全关键功能：始终拒绝，不受配置影响
+	redisFailConfigurable                         // 非安全功能：由 fail_open 配置决定，默认拒绝
+	redisFailOpen                                 // 非安全功能：始终放行 (引入降级策略前即为放行的功能，保持原有行为)
+)
+
+// redisFeatureModes 各功能声明的失败模式
+// - 新增依赖 Redis 的功能时在此登记，未登记的功能按安全关键处理。
+var redisFeatureModes = map[RedisFeature]redisFailureMode{
+	RedisFeatureCaptchaSendQuota:   redisFailConfigurable,
+	RedisFeatureCaptchaGlobalQuota: redisFailConfigurable,
+	RedisFeatureProfileCooldown:    redisFailOpen,
+	RedisFeatureTokenBlacklist:     redisFailClosed,
+	RedisFeaturePasswordVerify:     redisFailClosed,
+}
+
+// RedisDegradationStat 某个功能的 Redis 失败统计
+type RedisDegradationStat struct {
+	Feature  RedisFeature // 功能标识
+	Critical bool         // 是否为安全关键功能
+	Failures int64        // Redis 操作失败次数
+	FailOpen int64        // 其中按降级策略放行的次数
+}
+
+// redisFeatureCounters 单个功能的计数器
+type redisFeatureCounters struct {
+	failures atomic.Int64
+	failOpen atomic.Int64
+}
+
+// RedisDegradation 集中决定 Redis 操作失败时各功能是放行还是拒绝，并统计降级次数。
+// 设计目的:
+//   - 调用方只需声明功能标识，是否放行由功能的失败模式与配置共同决定，避免各处各自判断。
+//   - 计数器供 /metrics 导出，便于发现 Redis 故障期间有哪些功能处于降级状态。
+type RedisDegradation struct {
+	failOpen bool                                   // 非安全功能是否在失败时放行
+	counters map[RedisFeature]*redisFeatureCounters // 各功能的计数器，创建后只读
+	logger   *core.ZapLogger
+}
+
+// NewRedisDegradation 创建 RedisDegradation 实例。
+func NewRedisDegradation(cfg config.RedisDegradationConfig, logger *core.ZapLogger) *RedisDegradation {
+	counters := make(map[RedisFeature]*redisFeatureCounters, len(redisFeatureModes))
+	for feature := range redisFeatureModes {
+		counters[feature] = &redisFeatureCounters{}
+	}
+	return &RedisDegradation{failOpen: cfg.FailOpen, counters: counters, logger: logger}
+}
+
+// AllowOnFailure 记录一次 Redis 操作失败，并返回是否按降级策略放行本次请求。
+// - 返回 true 时调用方应跳过该功能继续处理请求；返回 false 时按原有逻辑返回错误。
+func (d *RedisDegradation) AllowOnFailure(feature RedisFeature, err error) bool {
+	mode, known := redisFeatureModes[feature]
+	allow := known && (mode == redisFailOpen || (mode == redisFailConfigurable && d.failOpen))
+	if counters := d.counters[feature]; counters != nil {
+		counters.failures.Add(1)
+		if allow {
+			counters.failOpen.Add(1)
+		}
+	}
+	if allow {
+		d.logger.Warn("Redis 操作失败，按降级策略放行", zap.String("feature", string(feature)), zap.Error(err))
+	}
+	return allow
+}
+
+// Stats 返回各功能的失败统计，按功能标识排序。
+func (d *RedisDegradation) Stats() []RedisDegradationStat {
+	stats := make([]RedisDegradationStat, 0, len(d.counters))
+	for feature, counters := range d.counters {
+		stats = append(stats, RedisDegradationStat{
+			Feature:  feature,
+			Critical: redisFeatureModes[feature] == redisFailClosed,
+			Failures: counters.failures.Load(),
+			FailOpen: counters.failOpen.Load(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Feature < stats[j].Feature })
+	return stats
+}
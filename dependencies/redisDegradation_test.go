@@ -0,0 +1,59 @@
+package dependencies
+
+import (
+	"errors"
+	"testing"
+
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+func TestRedisDegradationAllowOnFailure(t *testing.T) {
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	redisErr := errors.New("redis: connection refused")
+
+	tests := []struct {
+		name        string
+		feature     RedisFeature
+		wantDefault bool // fail_open 未配置 (false) 时是否放行
+		wantOpen    bool // fail_open 为 true 时是否放行
+	}{
+		{name: "资料修改冷却始终放行", feature: RedisFeatureProfileCooldown, wantDefault: true, wantOpen: true},
+		{name: "验证码发送限流由配置决定", feature: RedisFeatureCaptchaSendQuota, wantDefault: false, wantOpen: true},
+		{name: "全局验证码限流由配置决定", feature: RedisFeatureCaptchaGlobalQuota, wantDefault: false, wantOpen: true},
+		{name: "令牌黑名单始终拒绝", feature: RedisFeatureTokenBlacklist, wantDefault: false, wantOpen: false},
+		{name: "密码校验限流始终拒绝", feature: RedisFeaturePasswordVerify, wantDefault: false, wantOpen: false},
+		{name: "未登记的功能按安全关键处理", feature: RedisFeature("unknown"), wantDefault: false, wantOpen: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, failOpen := range []bool{false, true} {
+				d := NewRedisDegradation(config.RedisDegradationConfig{FailOpen: failOpen}, logger)
+				want := tt.wantDefault
+				if failOpen {
+					want = tt.wantOpen
+				}
+				if got := d.AllowOnFailure(tt.feature, redisErr); got != want {
+					t.Errorf("fail_open=%v: AllowOnFailure = %v，期望 %v", failOpen, got, want)
+				}
+				for _, stat := range d.Stats() {
+					if stat.Feature != tt.feature {
+						continue
+					}
+					wantFailOpen := int64(0)
+					if want {
+						wantFailOpen = 1
+					}
+					if stat.Failures != 1 || stat.FailOpen != wantFailOpen {
+						t.Errorf("fail_open=%v: 统计 = %+v，期望 Failures=1 FailOpen=%d", failOpen, stat, wantFailOpen)
+					}
+				}
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/Xushengqwer/user_hub/config"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -20,6 +22,10 @@ type SMSClient interface {
 	// - 输出: error 表示发送是否成功，成功时返回 nil
 	// - 注意: 不负责生成或存储验证码，仅处理发送逻辑
 	SendCode(ctx context.Context, phone string, code string) error
+
+	// Ping 使用配置的 AppID/Secret 换取一次平台 access_token，验证凭证有效且服务可达
+	// - 不会发送任何短信，用于启动检查
+	Ping(ctx context.Context) error
 }
 
 // smsClient 实现 SMSClient 接口的结构体
@@ -36,7 +42,6 @@ func NewSMSClient(config *config.SMSConfig) (SMSClient, error) {
 	// 1. 校验配置是否有效
 	// - 确保必要字段非空
 	if config == nil || config.AppID == "" || config.Secret == "" || config.Endpoint == "" || config.TemplateID == "" {
-		fmt.Println(config)
 		return nil, fmt.Errorf("SMS 配置无效，缺少必要字段")
 	}
 
@@ -88,7 +93,7 @@ func (s *smsClient) SendCode(ctx context.Context, phone string, code string) err
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		// - 发送失败，返回错误
-		return fmt.Errorf("发送短信验证码失败: %v", err)
+		return fmt.Errorf("发送短信验证码失败: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -107,10 +112,46 @@ func (s *smsClient) SendCode(ctx context.Context, phone string, code string) err
 	// 5. 验证发送结果
 	// - 检查 errcode 是否为 0
 	if result.ErrCode != 0 {
-		return fmt.Errorf("短信发送失败，错误码: %d, 错误信息: %s", result.ErrCode, result.ErrMsg)
+		// 短信经由微信云托管发送，业务错误码与微信 API 同构
+		return fmt.Errorf("短信发送失败: %w", &WechatAPIError{ErrCode: result.ErrCode, ErrMsg: result.ErrMsg})
 	}
 
 	// 6. 发送成功，返回 nil
 	// - 表示验证码已成功发送到用户手机号
 	return nil
 }
+
+// Ping 通过获取 access_token 验证短信服务凭证
+func (s *smsClient) Ping(ctx context.Context) error {
+	apiURL := fmt.Sprintf(
+		"https://api.weixin.qq.com/cgi-bin/token?grant_type=client_credential&appid=%s&secret=%s",
+		url.QueryEscape(s.config.AppID), url.QueryEscape(s.config.Secret),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("创建短信凭证检查请求失败: %v", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		// 请求地址中带有 secret，只保留底层错误，避免凭证随错误信息出现在日志或健康检查结果中
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("请求短信凭证检查接口失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ErrCode     int    `json:"errcode"`
+		ErrMsg      string `json:"errmsg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("解析短信凭证检查响应失败: %v", err)
+	}
+	if result.ErrCode != 0 || result.AccessToken == "" {
+		return fmt.Errorf("短信服务凭证无效，错误码: %d, 错误信息: %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
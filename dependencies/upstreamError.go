@@ -0,0 +1,91 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// 第三方服务名称，用于 UpstreamError.Service
+const (
+	UpstreamServiceCOS    = "cos"
+	UpstreamServiceSMS    = "sms"
+	UpstreamServiceWechat = "wechat"
+)
+
+// 第三方错误类别，用于 UpstreamError.Category
+const (
+	UpstreamCategoryTimeout    = "timeout"     // 请求超时
+	UpstreamCategoryCanceled   = "canceled"    // 请求被取消
+	UpstreamCategoryNetwork    = "network"     // 连接失败、DNS 解析失败等网络错误
+	UpstreamCategoryHTTPStatus = "http_status" // 上游返回非成功的 HTTP 状态码 (如 COS 403)
+	UpstreamCategoryAPIError   = "api_error"   // 上游返回业务错误码 (如微信 errcode)
+	UpstreamCategoryUnknown    = "unknown"     // 无法归类
+)
+
+// UpstreamError 描述一次第三方服务调用失败，附带可对外展示的脱敏摘要。
+// - Service/Category/StatusCode/Code/RequestID 只取自结构化字段，不含原始错误文本，可在开启时返回给管理员排障。
+// - 原始错误通过 Unwrap 保留，仅用于日志与 errors.Is/As 判断。
+type UpstreamError struct {
+	Service    string // 第三方服务名称，取值见 UpstreamService* 常量
+	Category   string // 错误类别，取值见 UpstreamCategory* 常量
+	StatusCode int    // 上游 HTTP 状态码，无法获取时为 0
+	Code       string // 上游错误码 (如 COS 的 AccessDenied、微信 errcode)，无法获取时为空
+	RequestID  string // 上游请求 ID (如 COS 的 x-cos-request-id)，便于向服务商查询
+	err        error
+}
+
+// Error 实现 error 接口。
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("%s 调用失败 (%s): %v", e.Service, e.Category, e.err)
+}
+
+// Unwrap 返回原始错误。
+func (e *UpstreamError) Unwrap() error {
+	return e.err
+}
+
+// ClassifyUpstreamError 将第三方服务调用返回的原始错误归类为 UpstreamError。
+// - err 为 nil 时返回 nil；err 链中已有 UpstreamError 时直接返回它，避免重复包装。
+func ClassifyUpstreamError(service string, err error) *UpstreamError {
+	if err == nil {
+		return nil
+	}
+	var existing *UpstreamError
+	if errors.As(err, &existing) {
+		return existing
+	}
+
+	upstreamErr := &UpstreamError{Service: service, Category: UpstreamCategoryUnknown, err: err}
+	var (
+		cosErr    *cos.ErrorResponse
+		wechatErr *WechatAPIError
+		netErr    net.Error
+	)
+	switch {
+	case errors.As(err, &cosErr):
+		upstreamErr.Category = UpstreamCategoryHTTPStatus
+		upstreamErr.Code = cosErr.Code
+		upstreamErr.RequestID = cosErr.RequestID
+		if cosErr.Response != nil {
+			upstreamErr.StatusCode = cosErr.Response.StatusCode
+		}
+	case errors.As(err, &wechatErr):
+		upstreamErr.Category = UpstreamCategoryAPIError
+		upstreamErr.Code = strconv.Itoa(wechatErr.ErrCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		upstreamErr.Category = UpstreamCategoryTimeout
+	case errors.Is(err, context.Canceled):
+		upstreamErr.Category = UpstreamCategoryCanceled
+	case errors.As(err, &netErr):
+		upstreamErr.Category = UpstreamCategoryNetwork
+		if netErr.Timeout() {
+			upstreamErr.Category = UpstreamCategoryTimeout
+		}
+	}
+	return upstreamErr
+}
@@ -3,10 +3,16 @@ package dependencies
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt" // 引入 fmt 包用于错误包装
 	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"io" // 引入 io 包读取响应体
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 )
 
@@ -17,14 +23,84 @@ type WechatClient interface {
 	// - ctx: 用于控制请求的上下文，例如超时或取消。
 	// - code: 小程序通过 wx.login() 获取的临时登录凭证。
 	// - 返回: openid (用户唯一标识), sessionKey (会话密钥), 以及可能的错误。
-	// - 如果微信 API 返回错误码，会封装成 error 返回。
+	// - 如果微信 API 返回错误码，返回包装了 *WechatAPIError 的错误，可通过 errors.As 取出 errcode。
 	GetSession(ctx context.Context, code string) (openid, sessionKey string, err error)
+
+	// GetAccessToken 获取调用微信服务端接口（如消息推送）所需的平台 access_token。
+	// - 优先读取 Redis 缓存；缓存缺失时刷新，并在官方过期时间前提前失效，避免使用临近过期的令牌。
+	// - 进程内互斥 + Redis 分布式锁保证同一时刻只有一个调用方请求微信，其余调用方等待并复用结果。
+	// - 微信返回错误码时返回包装了 *WechatAPIError 的错误。
+	GetAccessToken(ctx context.Context) (string, error)
+
+	// Ping 检查微信 API 服务是否可达。
+	// - 仅发起一次 HEAD 请求，收到任何 HTTP 响应即视为可达；不消耗 access_token 调用额度，也不校验凭证。
+	Ping(ctx context.Context) error
+}
+
+// access_token 缓存相关参数
+const (
+	wechatAccessTokenRefreshAhead = 5 * time.Minute        // 在官方过期时间前提前刷新的时长
+	wechatAccessTokenMinTTL       = time.Minute            // 缓存的最短有效期
+	wechatAccessTokenLockTTL      = 10 * time.Second       // 刷新锁的有效期，需覆盖一次 HTTP 请求
+	wechatAccessTokenWaitInterval = 100 * time.Millisecond // 未抢到锁时轮询缓存的间隔
+)
+
+// releaseLockScript 仅当锁仍由自己持有时才删除，避免误删其他实例在锁过期后重新获取的锁
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// 微信 jscode2session 接口的常见错误码
+const (
+	WechatErrCodeInvalidCode      = 40029 // code 无效
+	WechatErrCodeCodeUsed         = 40163 // code 已被使用
+	WechatErrCodeHighRiskUser     = 40226 // 高风险等级用户，登录被拦截
+	WechatErrCodeInvalidAppID     = 40013 // AppID 无效
+	WechatErrCodeInvalidAppSecret = 40125 // AppSecret 无效
+	WechatErrCodeMissingAppID     = 41002 // 缺少 AppID 参数
+	WechatErrCodeMissingAppSecret = 41004 // 缺少 AppSecret 参数
+)
+
+// WechatAPIError 表示微信 API 返回的业务错误（errcode 非 0）。
+// - 调用方据此区分"用户需要重新登录"与"服务端配置错误"。
+type WechatAPIError struct {
+	ErrCode int    // 微信返回的 errcode
+	ErrMsg  string // 微信返回的 errmsg
+}
+
+// Error 实现 error 接口。
+func (e *WechatAPIError) Error() string {
+	return fmt.Sprintf("微信 API 业务错误: code=%d, msg=%s", e.ErrCode, e.ErrMsg)
+}
+
+// IsClientError 判断是否为客户端原因导致的错误（code 无效/已使用、用户被风控），用户重新登录即可。
+func (e *WechatAPIError) IsClientError() bool {
+	switch e.ErrCode {
+	case WechatErrCodeInvalidCode, WechatErrCodeCodeUsed, WechatErrCodeHighRiskUser:
+		return true
+	}
+	return false
+}
+
+// IsConfigError 判断是否为服务端配置错误（AppID/AppSecret 无效或缺失），需要运维介入。
+func (e *WechatAPIError) IsConfigError() bool {
+	switch e.ErrCode {
+	case WechatErrCodeInvalidAppID, WechatErrCodeInvalidAppSecret, WechatErrCodeMissingAppID, WechatErrCodeMissingAppSecret:
+		return true
+	}
+	return false
 }
 
 // wechatClient 是 WechatClient 接口的实现。
 type wechatClient struct {
 	config *config.WechatConfig // config 存储微信小程序的 AppID 和 Secret
 	client *http.Client         // client 是用于发送 HTTP 请求的客户端实例
+	redis  *redis.Client        // redis 用于缓存 access_token 及多实例间的刷新锁
+
+	tokenMu sync.Mutex // tokenMu 保证同一进程内同一时刻只有一个 goroutine 刷新 access_token
 }
 
 // wechatSessionResponse 定义了微信 jscode2session API 成功响应的结构。
@@ -37,11 +113,20 @@ type wechatSessionResponse struct {
 	ErrMsg     string `json:"errmsg"`      // 错误信息 (成功时为 "ok")
 }
 
+// wechatAccessTokenResponse 定义了微信获取 access_token API 响应的结构。
+type wechatAccessTokenResponse struct {
+	AccessToken string `json:"access_token"` // 平台接口调用凭证
+	ExpiresIn   int    `json:"expires_in"`   // 凭证有效时间 (秒)
+	ErrCode     int    `json:"errcode"`      // 错误码 (成功时为 0 或不返回)
+	ErrMsg      string `json:"errmsg"`       // 错误信息
+}
+
 // NewWechatClient 创建一个新的 wechatClient 实例。
-// - 依赖注入微信配置和 HTTP 客户端。
-func NewWechatClient(config *config.WechatConfig) WechatClient {
+// - 依赖注入微信配置和 Redis 客户端（用于缓存 access_token）。
+func NewWechatClient(config *config.WechatConfig, redisClient *redis.Client) WechatClient {
 	return &wechatClient{
 		config: config,
+		redis:  redisClient,
 		client: &http.Client{
 			// 设置合理的 HTTP 请求超时时间
 			Timeout: 10 * time.Second,
@@ -49,6 +134,23 @@ func NewWechatClient(config *config.WechatConfig) WechatClient {
 	}
 }
 
+// wechatAPIBaseURL 微信服务端 API 的根地址，用于可达性检查
+const wechatAPIBaseURL = "https://api.weixin.qq.com"
+
+// Ping 实现接口方法，对微信 API 根地址发起 HEAD 请求检查可达性。
+func (w *wechatClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, wechatAPIBaseURL, nil)
+	if err != nil {
+		return fmt.Errorf("创建微信可达性检查请求失败: %w", err)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("微信 API 不可达: %w", err)
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
 // GetSession 实现接口方法，调用微信 API 获取会话信息。
 func (w *wechatClient) GetSession(ctx context.Context, code string) (string, string, error) {
 	// 1. 构造请求 URL
@@ -99,10 +201,118 @@ func (w *wechatClient) GetSession(ctx context.Context, code string) (string, str
 	// 7. 检查微信业务错误码
 	// - 如果 ErrCode 不为 0，表示微信 API 返回了业务错误。
 	if result.ErrCode != 0 {
-		// 返回包含微信错误码和错误信息的错误
-		return "", "", fmt.Errorf("wechatClient.GetSession: 微信 API 业务错误: code=%d, msg=%s", result.ErrCode, result.ErrMsg)
+		// 返回携带微信错误码的类型化错误，供服务层区分客户端错误与配置错误
+		return "", "", fmt.Errorf("wechatClient.GetSession: %w", &WechatAPIError{ErrCode: result.ErrCode, ErrMsg: result.ErrMsg})
 	}
 
 	// 8. 成功获取，返回 openid 和 sessionKey
 	return result.OpenID, result.SessionKey, nil
 }
+
+// GetAccessToken 实现接口方法，返回缓存的或新刷新的 access_token。
+func (w *wechatClient) GetAccessToken(ctx context.Context) (string, error) {
+	// 1. 快速路径：直接命中缓存
+	if token, err := w.cachedAccessToken(ctx); err != nil || token != "" {
+		return token, err
+	}
+
+	// 2. 进程内互斥，拿到锁后再次检查缓存（可能已被前一个持锁者刷新）
+	w.tokenMu.Lock()
+	defer w.tokenMu.Unlock()
+	if token, err := w.cachedAccessToken(ctx); err != nil || token != "" {
+		return token, err
+	}
+
+	// 3. 多实例间通过 Redis 锁互斥；未抢到锁时等待持锁实例写入缓存
+	lockValue := uuid.New().String()
+	for {
+		acquired, err := w.redis.SetNX(ctx, constants.WechatAccessTokenLockKey, lockValue, wechatAccessTokenLockTTL).Result()
+		if err != nil {
+			return "", fmt.Errorf("wechatClient.GetAccessToken: 获取刷新锁失败: %w", err)
+		}
+		if acquired {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("wechatClient.GetAccessToken: 等待其他实例刷新 access_token 时上下文结束: %w", ctx.Err())
+		case <-time.After(wechatAccessTokenWaitInterval):
+		}
+		if token, err := w.cachedAccessToken(ctx); err != nil || token != "" {
+			return token, err
+		}
+	}
+	defer func() {
+		// 使用独立的上下文释放锁，避免请求上下文取消后锁残留到过期
+		releaseCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = releaseLockScript.Run(releaseCtx, w.redis, []string{constants.WechatAccessTokenLockKey}, lockValue).Err()
+	}()
+
+	// 4. 持有锁后最后检查一次缓存，随后向微信请求新的 access_token
+	if token, err := w.cachedAccessToken(ctx); err != nil || token != "" {
+		return token, err
+	}
+	token, expiresIn, err := w.fetchAccessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 5. 写入缓存，提前于官方过期时间失效
+	ttl := expiresIn - wechatAccessTokenRefreshAhead
+	if ttl < wechatAccessTokenMinTTL {
+		ttl = wechatAccessTokenMinTTL
+	}
+	// 缓存写入失败不影响本次调用，下次调用会重新刷新
+	_ = w.redis.Set(ctx, constants.WechatAccessTokenKey, token, ttl).Err()
+	return token, nil
+}
+
+// cachedAccessToken 读取缓存的 access_token，缓存不存在时返回空字符串和 nil 错误。
+func (w *wechatClient) cachedAccessToken(ctx context.Context) (string, error) {
+	token, err := w.redis.Get(ctx, constants.WechatAccessTokenKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil
+		}
+		return "", fmt.Errorf("wechatClient.GetAccessToken: 读取 access_token 缓存失败: %w", err)
+	}
+	return token, nil
+}
+
+// fetchAccessToken 调用微信 API 获取新的 access_token 及其有效期。
+func (w *wechatClient) fetchAccessToken(ctx context.Context) (string, time.Duration, error) {
+	apiURL := fmt.Sprintf(
+		"https://api.weixin.qq.com/cgi-bin/token?grant_type=client_credential&appid=%s&secret=%s",
+		url.QueryEscape(w.config.AppID), url.QueryEscape(w.config.Secret),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("wechatClient.GetAccessToken: 创建微信 API 请求失败: %w", err)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("wechatClient.GetAccessToken: 请求微信 API 失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("wechatClient.GetAccessToken: 读取微信 API 响应体失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("wechatClient.GetAccessToken: 微信 API 返回非 200 状态码: %d, 响应体: %s", resp.StatusCode, string(body))
+	}
+
+	var result wechatAccessTokenResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", 0, fmt.Errorf("wechatClient.GetAccessToken: 解析微信 API 响应失败: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return "", 0, fmt.Errorf("wechatClient.GetAccessToken: %w", &WechatAPIError{ErrCode: result.ErrCode, ErrMsg: result.ErrMsg})
+	}
+	if result.AccessToken == "" {
+		return "", 0, fmt.Errorf("wechatClient.GetAccessToken: 微信 API 未返回 access_token")
+	}
+	return result.AccessToken, time.Duration(result.ExpiresIn) * time.Second, nil
+}
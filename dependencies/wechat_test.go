@@ -0,0 +1,178 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// rewriteTransport 将发往微信 API 的请求改写到本地测试服务器，保留路径与查询参数
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestWechatClient 创建请求被重定向到 handler 的微信客户端
+func newTestWechatClient(t *testing.T, handler http.HandlerFunc) *wechatClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("解析测试服务器地址失败: %v", err)
+	}
+
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	return &wechatClient{
+		config: &config.WechatConfig{AppID: "wx-app", Secret: "wx-secret"},
+		client: &http.Client{Transport: rewriteTransport{target: target}},
+		redis:  redisClient,
+	}
+}
+
+// errcodeHandler 以 200 状态码返回指定的微信业务错误码
+func errcodeHandler(code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"errcode":%d,"errmsg":"error %d"}`, code, code)
+	}
+}
+
+func TestWechatGetSessionSuccess(t *testing.T) {
+	client := newTestWechatClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/sns/jscode2session" || q.Get("appid") != "wx-app" || q.Get("secret") != "wx-secret" || q.Get("js_code") != "code-1" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"openid":"openid-1","session_key":"session-1"}`))
+	})
+
+	openid, sessionKey, err := client.GetSession(context.Background(), "code-1")
+	if err != nil {
+		t.Fatalf("GetSession 失败: %v", err)
+	}
+	if openid != "openid-1" || sessionKey != "session-1" {
+		t.Errorf("GetSession = %q, %q，期望 openid-1, session-1", openid, sessionKey)
+	}
+}
+
+func TestWechatTypedErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       int
+		wantClient bool
+		wantConfig bool
+	}{
+		{name: "code 无效", code: WechatErrCodeInvalidCode, wantClient: true},
+		{name: "code 已使用", code: WechatErrCodeCodeUsed, wantClient: true},
+		{name: "高风险用户", code: WechatErrCodeHighRiskUser, wantClient: true},
+		{name: "AppID 无效", code: WechatErrCodeInvalidAppID, wantConfig: true},
+		{name: "AppSecret 无效", code: WechatErrCodeInvalidAppSecret, wantConfig: true},
+		{name: "缺少 AppID", code: WechatErrCodeMissingAppID, wantConfig: true},
+		{name: "缺少 AppSecret", code: WechatErrCodeMissingAppSecret, wantConfig: true},
+		{name: "系统繁忙既非客户端也非配置错误", code: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestWechatClient(t, errcodeHandler(tt.code))
+
+			calls := map[string]func() error{
+				"GetSession": func() error {
+					_, _, err := client.GetSession(context.Background(), "code-1")
+					return err
+				},
+				"GetAccessToken": func() error {
+					_, err := client.GetAccessToken(context.Background())
+					return err
+				},
+			}
+			for method, call := range calls {
+				err := call()
+				var apiErr *WechatAPIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("%s: 期望返回 *WechatAPIError，实际为 %v", method, err)
+				}
+				if apiErr.ErrCode != tt.code {
+					t.Errorf("%s: ErrCode = %d，期望 %d", method, apiErr.ErrCode, tt.code)
+				}
+				if apiErr.IsClientError() != tt.wantClient || apiErr.IsConfigError() != tt.wantConfig {
+					t.Errorf("%s: IsClientError = %v, IsConfigError = %v，期望 %v, %v",
+						method, apiErr.IsClientError(), apiErr.IsConfigError(), tt.wantClient, tt.wantConfig)
+				}
+			}
+		})
+	}
+}
+
+func TestWechatTransportErrorsAreNotAPIErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{name: "非 200 状态码", handler: func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}},
+		{name: "响应不是 JSON", handler: func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("<html>"))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestWechatClient(t, tt.handler)
+			_, _, err := client.GetSession(context.Background(), "code-1")
+			var apiErr *WechatAPIError
+			if err == nil || errors.As(err, &apiErr) {
+				t.Errorf("期望返回非业务错误，实际为 %v", err)
+			}
+		})
+	}
+}
+
+func TestWechatGetAccessTokenCachesResult(t *testing.T) {
+	requests := 0
+	client := newTestWechatClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/cgi-bin/token" {
+			http.Error(w, "unexpected path", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"token-1","expires_in":7200}`))
+	})
+
+	for i := 0; i < 3; i++ {
+		token, err := client.GetAccessToken(context.Background())
+		if err != nil {
+			t.Fatalf("第 %d 次 GetAccessToken 失败: %v", i+1, err)
+		}
+		if token != "token-1" {
+			t.Errorf("access_token = %q，期望 token-1", token)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("请求微信次数 = %d，期望缓存命中后只请求 1 次", requests)
+	}
+	if ttl := client.redis.TTL(context.Background(), constants.WechatAccessTokenKey).Val(); ttl <= 0 || ttl > 7200*time.Second-wechatAccessTokenRefreshAhead {
+		t.Errorf("缓存有效期 = %v，应提前于官方过期时间", ttl)
+	}
+}
@@ -6,6 +6,7 @@ package docs
 
 import (
 	"github.com/Xushengqwer/go-common/response" // 导入您的通用响应包
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/models/vo" // 导入您的 VO 包
 	// 如果需要，导入其他包，例如 enums
 )
@@ -56,6 +57,12 @@ type SwaggerAPIIdentityListResponse struct {
 	response.APIResponse[vo.IdentityList]
 }
 
+// SwaggerAPILoginMethodListResponse 包装了 response.APIResponse[vo.LoginMethodList]
+// 用于 IdentityController.GetLoginMethodsByUserIDHandler
+type SwaggerAPILoginMethodListResponse struct {
+	response.APIResponse[vo.LoginMethodList]
+}
+
 // SwaggerAPIIdentityTypeListResponse 包装了 response.APIResponse[vo.IdentityTypeList]
 // 用于 IdentityController.GetIdentityTypesByUserIDHandler
 type SwaggerAPIIdentityTypeListResponse struct {
@@ -69,6 +76,12 @@ type SwaggerAPIProfileVOResponse struct {
 	response.APIResponse[vo.ProfileVO]
 }
 
+// SwaggerAPIAdminUserDetailResponse 包装了 response.APIResponse[vo.AdminUserDetailVO]
+// 用于 UserManageController.GetUserDetailHandler
+type SwaggerAPIAdminUserDetailResponse struct {
+	response.APIResponse[vo.AdminUserDetailVO]
+}
+
 // SwaggerAPIUserVOResponse 包装了 response.APIResponse[vo.UserVO]
 // 用于 UserController.CreateUserHandler, UserController.GetUserByIDHandler, UserController.UpdateUserHandler
 type SwaggerAPIUserVOResponse struct {
@@ -98,3 +111,147 @@ type SwaggerAPIErrorResponseAny struct {
 // type SwaggerAPISomeOtherListResponse struct {
 //     response.APIResponse[[]*vo.SomeOtherVO]
 // }
+
+// SwaggerAPIAuditLogListResponse 包装了 response.APIResponse[vo.AuditLogListResponse]
+// 用于 AuditController.ListAuditLogsHandler
+type SwaggerAPIAuditLogListResponse struct {
+	response.APIResponse[vo.AuditLogListResponse]
+}
+
+// SwaggerAPIFeatureFlagsResponse 包装了 response.APIResponse[vo.FeatureFlagsVO]
+// 用于 MetaController.GetFeaturesHandler
+type SwaggerAPIFeatureFlagsResponse struct {
+	response.APIResponse[vo.FeatureFlagsVO]
+}
+
+// SwaggerAPILocalesResponse 包装了 response.APIResponse[vo.LocalesVO]
+// 用于 MetaController.GetLocalesHandler
+type SwaggerAPILocalesResponse struct {
+	response.APIResponse[vo.LocalesVO]
+}
+
+// SwaggerAPIBlacklistStatsResponse 包装了 response.APIResponse[vo.BlacklistStatsVO]
+// 用于 AuthTokenController.GetBlacklistStatsHandler
+type SwaggerAPIBlacklistStatsResponse struct {
+	response.APIResponse[vo.BlacklistStatsVO]
+}
+
+// SwaggerAPIBlacklistEntryResponse 包装了 response.APIResponse[vo.BlacklistEntryVO]
+// 用于 AuthTokenController.InspectBlacklistedJtiHandler
+type SwaggerAPIBlacklistEntryResponse struct {
+	response.APIResponse[vo.BlacklistEntryVO]
+}
+
+// SwaggerAPIBlacklistClearResponse 包装了 response.APIResponse[vo.BlacklistClearVO]
+// 用于 AuthTokenController.ClearBlacklistedJtiHandler
+type SwaggerAPIBlacklistClearResponse struct {
+	response.APIResponse[vo.BlacklistClearVO]
+}
+
+// SwaggerAPILoginFailureResponse 包装了 response.APIResponse[vo.LoginFailureVO]
+// 用于各登录接口的失败响应，data.reason 为稳定的失败原因码
+type SwaggerAPILoginFailureResponse struct {
+	response.APIResponse[vo.LoginFailureVO]
+}
+
+// SwaggerAPIReactivationChallengeResponse 包装了 response.APIResponse[vo.ReactivationChallengeVO]
+// 用于各登录接口在账号已停用时返回的 403 响应
+type SwaggerAPIReactivationChallengeResponse struct {
+	response.APIResponse[vo.ReactivationChallengeVO]
+}
+
+// SwaggerAPIIdentityVerificationRequiredResponse 包装了 response.APIResponse[vo.IdentityVerificationRequiredVO]
+// 用于开启"登录要求已验证身份"策略后，使用未验证身份登录时返回的 403 响应
+type SwaggerAPIIdentityVerificationRequiredResponse struct {
+	response.APIResponse[vo.IdentityVerificationRequiredVO]
+}
+
+// SwaggerAPIRecoveryTokenResponse 包装了 response.APIResponse[vo.RecoveryTokenVO]
+// 用于 AccountRecoveryController.StartRecoveryHandler
+type SwaggerAPIRecoveryTokenResponse struct {
+	response.APIResponse[vo.RecoveryTokenVO]
+}
+
+// SwaggerAPIConsistencyReportResponse 包装了 response.APIResponse[vo.ConsistencyReportVO]
+// 用于 ConsistencyController.ScanHandler
+type SwaggerAPIConsistencyReportResponse struct {
+	response.APIResponse[vo.ConsistencyReportVO]
+}
+
+// SwaggerAPIPresignedURLResponse 包装了 response.APIResponse[vo.PresignedURLVO]
+// 用于 FileController.GetDownloadURLHandler
+type SwaggerAPIPresignedURLResponse struct {
+	response.APIResponse[vo.PresignedURLVO]
+}
+
+// SwaggerAPIStorageUsageResponse 包装了 response.APIResponse[vo.StorageUsageVO]
+// 用于 FileController.GetUsageHandler
+type SwaggerAPIStorageUsageResponse struct {
+	response.APIResponse[vo.StorageUsageVO]
+}
+
+// SwaggerAPITokenIntrospectionResponse 包装了 response.APIResponse[vo.TokenIntrospectionVO]
+// 用于 AuthTokenController.IntrospectHandler
+type SwaggerAPITokenIntrospectionResponse struct {
+	response.APIResponse[vo.TokenIntrospectionVO]
+}
+
+// SwaggerAPITokenIntrospectionBatchResponse 包装了 response.APIResponse[vo.TokenIntrospectionBatchVO]
+// 用于 AuthTokenController.BatchIntrospectHandler
+type SwaggerAPITokenIntrospectionBatchResponse struct {
+	response.APIResponse[vo.TokenIntrospectionBatchVO]
+}
+
+// SwaggerAPITokenClaimsResponse 包装了 response.APIResponse[vo.TokenClaimsVO]
+// 用于 AuthTokenController.WhoAmIHandler
+type SwaggerAPITokenClaimsResponse struct {
+	response.APIResponse[vo.TokenClaimsVO]
+}
+
+// SwaggerAPIBatchRoleResultResponse 包装了 response.APIResponse[vo.BatchRoleResultVO]
+// 用于 UserManageController.BatchSetRoleHandler
+type SwaggerAPIBatchRoleResultResponse struct {
+	response.APIResponse[vo.BatchRoleResultVO]
+}
+
+// SwaggerAPIUserActionResultResponse 包装了 response.APIResponse[vo.UserActionResultVO]
+// 用于 UserManageController.DeleteUserHandler 和 BlackUserHandler
+type SwaggerAPIUserActionResultResponse struct {
+	response.APIResponse[vo.UserActionResultVO]
+}
+
+// SwaggerAPISecurityOverviewResponse 包装了 response.APIResponse[vo.SecurityOverviewVO]
+// 用于 AccountSecurityController.GetSecurityOverviewHandler
+type SwaggerAPISecurityOverviewResponse struct {
+	response.APIResponse[vo.SecurityOverviewVO]
+}
+
+// SwaggerAPIActivePlatformsResponse 包装了 response.APIResponse[vo.ActivePlatformsVO]
+// 用于 AccountSecurityController.GetActivePlatformsHandler
+type SwaggerAPIActivePlatformsResponse struct {
+	response.APIResponse[vo.ActivePlatformsVO]
+}
+
+// SwaggerAPIAPIKeyCreatedResponse 包装了 response.APIResponse[vo.APIKeyCreatedVO]
+// 用于 APIKeyController.CreateAPIKeyHandler
+type SwaggerAPIAPIKeyCreatedResponse struct {
+	response.APIResponse[vo.APIKeyCreatedVO]
+}
+
+// SwaggerAPIAPIKeyListResponse 包装了 response.APIResponse[[]vo.APIKeyVO]
+// 用于 APIKeyController.ListAPIKeysHandler
+type SwaggerAPIAPIKeyListResponse struct {
+	response.APIResponse[[]vo.APIKeyVO]
+}
+
+// SwaggerAPIDeepHealthResponse 包装了 response.APIResponse[vo.DeepHealthVO]
+// 用于 HealthController.DeepHealthHandler
+type SwaggerAPIDeepHealthResponse struct {
+	response.APIResponse[vo.DeepHealthVO]
+}
+
+// SwaggerAPIConfigResponse 包装了 response.APIResponse[config.UserHubConfig]
+// 用于 ConfigController.GetEffectiveConfigHandler
+type SwaggerAPIConfigResponse struct {
+	response.APIResponse[config.UserHubConfig]
+}
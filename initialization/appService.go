@@ -1,16 +1,31 @@
 package initialization
 
 import (
-	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/service/userManage"
 
 	// 导入重构后的 service 包路径 (根据实际路径调整)
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/enums"
 	"github.com/Xushengqwer/user_hub/repository/mysql"
 	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/apikey"
+	"github.com/Xushengqwer/user_hub/service/audit"
+	"github.com/Xushengqwer/user_hub/service/consistency"
+	"github.com/Xushengqwer/user_hub/service/deactivation"
+	"github.com/Xushengqwer/user_hub/service/feature"
+	"github.com/Xushengqwer/user_hub/service/file"
+	"github.com/Xushengqwer/user_hub/service/health"
 	"github.com/Xushengqwer/user_hub/service/identity"
 	"github.com/Xushengqwer/user_hub/service/login/auth"
 	"github.com/Xushengqwer/user_hub/service/login/oAuth"
+	"github.com/Xushengqwer/user_hub/service/login/unified"
+	"github.com/Xushengqwer/user_hub/service/password"
 	"github.com/Xushengqwer/user_hub/service/profile" // 确保导入 profile 服务
+	"github.com/Xushengqwer/user_hub/service/provisioning"
+	"github.com/Xushengqwer/user_hub/service/recovery"
+	"github.com/Xushengqwer/user_hub/service/registration"
+	"github.com/Xushengqwer/user_hub/service/security"
+	"github.com/Xushengqwer/user_hub/service/sms"
 	"github.com/Xushengqwer/user_hub/service/token"
 	"github.com/Xushengqwer/user_hub/service/userList"
 )
@@ -21,13 +36,26 @@ type AppServices struct {
 	WechatMiniProgram oAuth.WechatMiniProgramService
 	Account           auth.AccountService
 	Phone             auth.PhoneAuthService
+	UnifiedLogin      unified.UnifiedLoginService
 	IdentityService   identity.UserIdentityService
 	ProfileService    profile.UserProfileService // 这个字段应该已经存在
 	TokenService      token.AuthTokenService
 	UserService       userManage.UserManageService
+	UserDetail        userManage.AdminUserDetailService
 	QueryService      userList.UserListQueryService
+	AuditService      audit.AdminAuditService
+	Consistency       consistency.DataConsistencyService
+	File              file.FileService
+	FeatureService    feature.FeatureFlagService
+	Registration      registration.RegistrationGateService
+	Deactivation      deactivation.AccountDeactivationService
+	Recovery          recovery.AccountRecoveryService
 	CodeRepo          redis.CodeRepo
-	SMS               dependencies.SMSClient
+	CaptchaSender     sms.CaptchaSender
+	TokenSweeper      token.RefreshTokenSweeper
+	Security          security.AccountSecurityService
+	APIKey            apikey.APIKeyService
+	Health            health.DependencyHealthService
 }
 
 // SetupServices 初始化所有仓库层和服务层实例。
@@ -37,13 +65,47 @@ func SetupServices(deps *AppDependencies) *AppServices {
 	userRepo := mysql.NewUserRepository(deps.DB)
 	profileRepo := mysql.NewProfileRepository(deps.DB)
 	joinQuery := mysql.NewJoinQuery(deps.DB)
+	auditRepo := mysql.NewAdminAuditRepository(deps.DB)
+	passwordHistoryRepo := mysql.NewPasswordHistoryRepository(deps.DB)
+	refreshTokenRepo := mysql.NewRefreshTokenRepository(deps.DB)
+	consistencyRepo := mysql.NewConsistencyRepository(deps.DB)
+	storageObjectRepo := mysql.NewStorageObjectRepository(deps.DB)
+	smsDeadLetterRepo := mysql.NewSMSDeadLetterRepository(deps.DB)
+	apiKeyRepo := mysql.NewAPIKeyRepository(deps.DB)
 
 	// 2. 初始化 Redis 仓库实例 (这部分保持不变)
-	codeRepo := redis.NewCodeRepo(deps.RedisClient)
+	codeRepo := redis.NewCodeRepo(deps.RedisClient, deps.Clock)
 	tokenBlackRepo := redis.NewTokenBlacklistRepo(deps.RedisClient)
+	reactivationRepo := redis.NewReactivationRepo(deps.RedisClient)
+	recoveryRepo := redis.NewRecoveryRepo(deps.RedisClient)
+	securityOverviewCache := redis.NewSecurityOverviewCache(deps.RedisClient)
+	avatarLockRepo := redis.NewAvatarLockRepo(deps.RedisClient)
+	profileCooldownRepo := redis.NewProfileCooldownRepo(deps.RedisClient)
+	passwordVerifyRepo := redis.NewPasswordVerifyRepo(deps.RedisClient)
+	recentAuthRepo := redis.NewRecentAuthRepo(deps.RedisClient)
+	wechatSessionRepo := redis.NewWechatSessionRepo(deps.RedisClient)
 
 	// 3. 初始化服务层实例
 
+	// 验证码短信发送：失败时按配置重试，重试耗尽后写入死信表
+	captchaSender := sms.NewCaptchaSender(
+		deps.SMSClient,
+		smsDeadLetterRepo,
+		deps.Config.SMSConfig,
+		deps.DB,
+		deps.Logger,
+	)
+
+	// 文件服务负责存储配额与用量统计，头像上传依赖它
+	fileService := file.NewFileService(
+		deps.COSClient,
+		storageObjectRepo,
+		deps.Config.COSConfig,
+		deps.Config.StorageConfig,
+		deps.DB,
+		deps.Logger,
+	)
+
 	// 首先初始化 UserProfileService，因为它会被其他服务依赖
 	profileService := profile.NewUserProfileService(
 		userRepo,
@@ -51,53 +113,154 @@ func SetupServices(deps *AppDependencies) *AppServices {
 		deps.DB,
 		deps.Logger,
 		deps.COSClient,
+		deps.CDNClient,
+		fileService,
+		avatarLockRepo,
+		profileCooldownRepo,
+		recentAuthRepo,
+		deps.Config.AvatarConfig,
+		deps.Config.ProfileUpdate,
+		deps.RedisDegrade,
+		deps.Config.RegionConfig,
+		deps.Regions,
+	)
+
+	// 新用户开户服务：三种登录方式的自动注册共用同一段事务逻辑
+	provisioningService := provisioning.NewUserProvisioningService(
+		userRepo,
+		identityRepo,
+		profileRepo,
+		deps.COSClient,
+		deps.Config.AvatarConfig,
+		deps.Config.NicknameConfig,
+		deps.Config.RegionConfig,
+		deps.Regions,
+		deps.DB,
+		deps.Logger,
+	)
+
+	// 令牌服务需先于账号停用服务及各登录服务初始化（停用时用于吊销当前令牌，登录时统一签发刷新令牌）
+	tokenService := token.NewAuthTokenService(
+		tokenBlackRepo,
+		userRepo,
+		refreshTokenRepo,
+		deps.JwtToken,
+		deps.Config.JWTConfig.RefreshWhitelist,
+		deps.DB,
+		deps.Clock,
+		deps.RedisDegrade,
+		auditRepo,
+		deps.Logger,
+	)
+
+	// 账号停用/重新激活服务：各登录服务在账号已停用时通过它签发重新激活凭证
+	deactivationService := deactivation.NewAccountDeactivationService(
+		userRepo,
+		reactivationRepo,
+		tokenService,
+		deps.JwtToken,
+		deps.DB,
+		deps.Logger,
+	)
+
+	// 注册开关：账号注册及手机号/微信自动注册前检查，支持热重载
+	registrationGate := registration.NewRegistrationGateService(
+		deps.Config.Registration,
+		deps.Logger,
 	)
 
-	// 初始化微信小程序认证服务，并注入 profileService
+	// 初始化微信小程序认证服务，并注入 provisioningService
 	wechatService := oAuth.NewWechatMiniProgramService(
 		identityRepo,
 		userRepo,
-		profileRepo,
+		provisioningService,
+		registrationGate,
 		tokenBlackRepo,
 		deps.JwtToken,
+		deactivationService,
+		tokenService,
 		deps.WechatClient,
+		wechatSessionRepo,
+		deps.Config.WechatConfig,
+		deps.Config.NicknameConfig,
 		deps.DB,
 		deps.Logger,
 	)
 
-	// 初始化账号密码认证服务，并注入 profileService
+	// 初始化账号密码认证服务，并注入 provisioningService
 	accountService := auth.NewAccountService(
 		identityRepo,
 		userRepo,
-		profileRepo,
+		provisioningService,
+		registrationGate,
 		tokenBlackRepo,
+		passwordVerifyRepo,
+		recentAuthRepo,
 		deps.JwtToken,
+		deactivationService,
+		tokenService,
+		deps.Config.LoginPolicyConfig,
+		deps.Config.PasswordConfig,
+		deps.Config.ProfileUpdate.ReverifyWindowOrDefault(),
+		deps.RedisDegrade,
+		auditRepo,
 		deps.DB,
 		deps.Logger,
 	)
 
-	// 初始化手机号认证服务，并注入 profileService
+	// 初始化手机号认证服务，并注入 provisioningService
 	phoneService := auth.NewPhoneAuthService(
 		identityRepo,
 		userRepo,
-		profileRepo,
+		provisioningService,
+		registrationGate,
 		codeRepo,
 		deps.JwtToken,
+		deactivationService,
+		tokenService,
+		deps.Config.LoginPolicyConfig,
 		deps.DB,
 		deps.Logger,
 	)
 
+	// 历史密码服务：修改/重置密码时禁止复用最近 N 个密码
+	passwordHistoryService := password.NewPasswordHistoryService(
+		passwordHistoryRepo,
+		deps.Config.PasswordConfig,
+		deps.Logger,
+	)
+
 	// 初始化其他服务 (保持不变)
 	identityService := identity.NewUserIdentityService(
 		identityRepo,
+		userRepo,
 		deps.DB,
 		deps.Logger,
+		passwordHistoryService,
+		codeRepo,
+		identity.DefaultCredentialStrategies(deps.Encryptor, encryptedIdentityTypes(deps.Config.CredentialEncryption)),
+		deps.Config.IdentityList,
 	)
 
-	tokenService := token.NewAuthTokenService(
-		tokenBlackRepo,
+	// 统一登录入口：按标识符类型分发到账号密码或手机号验证码登录
+	unifiedLoginService := unified.NewUnifiedLoginService(
+		accountService,
+		phoneService,
+		deps.Config.UnifiedLogin,
+		deps.Config.AccountConfig,
+		deps.Logger,
+	)
+
+	// 账号找回服务：凭已验证的次要身份签发一次性找回凭证，并记录审计日志
+	recoveryService := recovery.NewAccountRecoveryService(
+		identityRepo,
 		userRepo,
-		deps.JwtToken,
+		auditRepo,
+		codeRepo,
+		recoveryRepo,
+		deps.WechatClient,
+		passwordHistoryService,
+		deps.DB,
 		deps.Logger,
 	)
 
@@ -105,6 +268,11 @@ func SetupServices(deps *AppDependencies) *AppServices {
 		userRepo,
 		identityRepo,
 		profileRepo, // UserManageService 也可能需要 profileRepo (例如，如果它也创建用户配置文件)
+		auditRepo,
+		userManage.NewPreDeleteCheckFromConfig(deps.Config.DeleteGuard, deps.Logger),
+		deps.COSClient,
+		fileService,
+		deps.Config.UserDelete,
 		deps.DB,
 		deps.Logger,
 		// 如果 UserManageService.CreateUser 也需要创建 profile,
@@ -117,17 +285,104 @@ func SetupServices(deps *AppDependencies) *AppServices {
 		deps.Logger,
 	)
 
+	auditService := audit.NewAdminAuditService(
+		auditRepo,
+		deps.Clock,
+		deps.Logger,
+	)
+
+	consistencyService := consistency.NewDataConsistencyService(
+		consistencyRepo,
+		profileRepo,
+		auditRepo,
+		deps.DB,
+		deps.Logger,
+	)
+
+	featureService := feature.NewFeatureFlagService(
+		deps.Config.FeatureFlagConfig,
+		deps.Logger,
+	)
+
+	// 过期刷新令牌清理任务：由 main 按配置启动，并在关停时停止
+	tokenSweeper := token.NewRefreshTokenSweeper(
+		refreshTokenRepo,
+		deps.Config.SessionSweep,
+		deps.DB,
+		deps.Clock,
+		deps.Logger,
+	)
+
+	// 账号安全概览：只读聚合身份、会话与审计数据
+	securityService := security.NewAccountSecurityService(
+		userRepo,
+		identityService,
+		refreshTokenRepo,
+		auditRepo,
+		securityOverviewCache,
+		deps.Config.JWTConfig.RefreshWhitelist,
+		deps.Clock,
+		deps.Logger,
+	)
+
+	// 管理员用户详情：只读聚合核心信息、资料、登录方式与会话
+	userDetailService := userManage.NewAdminUserDetailService(
+		userRepo,
+		profileRepo,
+		identityService,
+		refreshTokenRepo,
+		deps.Config.JWTConfig.RefreshWhitelist,
+		deps.Clock,
+		deps.Logger,
+	)
+
+	// API 密钥：程序化访问凭证的管理与认证
+	apiKeyService := apikey.NewAPIKeyService(apiKeyRepo, userRepo, deps.DB, deps.Clock, deps.Logger)
+
+	// 深度健康检查：并发探测各外部依赖，供运维面板使用
+	healthService := health.NewDependencyHealthService(
+		deps.DB,
+		deps.RedisClient,
+		deps.COSClient,
+		deps.SMSClient,
+		deps.WechatClient,
+		deps.Config.HealthCheck,
+		deps.Logger,
+	)
+
 	// 4. 封装所有初始化完成的服务实例到 AppServices 结构体中
 	return &AppServices{
 		WechatMiniProgram: wechatService,
 		Account:           accountService,
 		Phone:             phoneService,
+		UnifiedLogin:      unifiedLoginService,
 		IdentityService:   identityService,
 		ProfileService:    profileService, // 确保 profileService 被正确赋值
 		TokenService:      tokenService,
 		UserService:       userService,
+		UserDetail:        userDetailService,
 		QueryService:      queryService,
+		AuditService:      auditService,
+		Consistency:       consistencyService,
+		File:              fileService,
+		FeatureService:    featureService,
+		Registration:      registrationGate,
+		Deactivation:      deactivationService,
+		Recovery:          recoveryService,
 		CodeRepo:          codeRepo,
-		SMS:               deps.SMSClient,
+		CaptchaSender:     captchaSender,
+		TokenSweeper:      tokenSweeper,
+		Security:          securityService,
+		APIKey:            apiKeyService,
+		Health:            healthService,
+	}
+}
+
+// encryptedIdentityTypes 将配置中的身份类型编号转换为身份类型枚举
+func encryptedIdentityTypes(cfg config.CredentialEncryptionConfig) []enums.IdentityType {
+	types := make([]enums.IdentityType, 0, len(cfg.EncryptedTypes))
+	for _, t := range cfg.EncryptedTypes {
+		types = append(types, enums.IdentityType(t))
 	}
+	return types
 }
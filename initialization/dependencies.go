@@ -27,6 +27,11 @@ type AppDependencies struct {
 	WechatClient dependencies.WechatClient       // WechatClient: 微信 API 客户端实例。
 	SMSClient    dependencies.SMSClient          // SMSClient: 短信服务客户端实例。
 	COSClient    dependencies.COSClientInterface // 新增 COS 客户端接口
+	CDNClient    dependencies.CDNClient          // CDNClient: CDN 缓存刷新客户端，未启用时为空操作实现。
+	Regions      utils.RegionDataset             // Regions: 省市一致性校验使用的行政区划数据集，未启用校验时为 nil。
+	Encryptor    dependencies.Encryptor          // Encryptor: 身份凭证落库加密器，未配置密钥时加解密返回错误。
+	Clock        utils.Clock                     // Clock: 时间来源，与时间相关的组件共用，测试时可替换为 utils.FakeClock。
+	RedisDegrade *dependencies.RedisDegradation  // RedisDegrade: Redis 出错时各功能放行或拒绝的集中决策与统计。
 }
 
 // SetupDependencies 初始化应用所需的所有基础依赖项。
@@ -47,15 +52,22 @@ func SetupDependencies(cfg *config.UserHubConfig, logger *core.ZapLogger) (*AppD
 	var deps AppDependencies
 	deps.Config = cfg    // 直接使用传入的配置
 	deps.Logger = logger // 直接使用传入的日志记录器
+	deps.Clock = utils.SystemClock
 
 	// 1. 注册自定义验证器
 	//    - 这是应用启动时需要完成的基础设置。
-	if err := utils.RegisterCustomValidators(); err != nil {
+	if err := utils.RegisterCustomValidators(cfg.AccountConfig.IdentifierFormat, cfg.NicknameConfig); err != nil {
 		// 如果注册失败，这是一个严重问题，应阻止应用启动。
 		// 返回错误而不是直接 Fatal，让 main 函数处理退出。
 		return nil, fmt.Errorf("注册自定义验证器失败: %w", err)
 	}
-	logger.Info("自定义验证器注册成功")
+	logger.Info("自定义验证器注册成功", zap.String("accountFormat", cfg.AccountConfig.IdentifierFormatOrDefault()))
+
+	// 1.1 设置密码哈希算法与强度 (只影响新生成的哈希，旧哈希按其自身参数校验)
+	if err := utils.ConfigurePasswordHashing(cfg.PasswordConfig); err != nil {
+		return nil, fmt.Errorf("密码哈希配置无效: %w", err)
+	}
+	logger.Info("密码哈希配置已加载", zap.String("algorithm", cfg.PasswordConfig.HashAlgorithmOrDefault()))
 
 	// 2. 初始化数据库连接 (MySQL)
 	//    - 依赖配置中的 MySQLConfig 和 logger。
@@ -76,23 +88,24 @@ func SetupDependencies(cfg *config.UserHubConfig, logger *core.ZapLogger) (*AppD
 		return nil, fmt.Errorf("初始化 Redis 失败: %w", err) // 保持错误包装
 	}
 	deps.RedisClient = redisClient
+	deps.RedisDegrade = dependencies.NewRedisDegradation(cfg.RedisDegradation, logger)
 	logger.Info("Redis 连接初始化成功")
 
 	// 4. 初始化 JWT 工具
 	//    - 依赖配置中的 JWTConfig。
-	deps.JwtToken = dependencies.NewJWTUtility(&cfg.JWTConfig) // 直接使用包名调用
+	deps.JwtToken = dependencies.NewJWTUtility(&cfg.JWTConfig, deps.Clock) // 直接使用包名调用
 	logger.Info("JWT 工具初始化成功")
 
 	// 5. 初始化微信客户端工具
-	//    - 依赖配置中的 WechatConfig。
-	deps.WechatClient = dependencies.NewWechatClient(&cfg.WechatConfig) // 直接使用包名调用
+	//    - 依赖配置中的 WechatConfig，以及 Redis（缓存平台 access_token）。
+	deps.WechatClient = dependencies.NewWechatClient(&cfg.WechatConfig, redisClient) // 直接使用包名调用
 	logger.Info("微信客户端初始化成功")
 
 	// 6. 初始化短信服务客户端 (微信云托管)
 	//    - 依赖配置中的 SMSConfig 和 logger。
 	//    - NewSMSClient 内部会进行配置校验并可能返回错误。
-	logger.Info("准备初始化短信服务客户端", zap.Any("smsConfig", cfg.SMSConfig)) // 打印配置用于调试
-	smsClient, _ := dependencies.NewSMSClient(&cfg.SMSConfig)        // 直接使用包名调用
+	logger.Info("准备初始化短信服务客户端", zap.Any("smsConfig", cfg.SMSConfig.Redacted())) // 打印配置用于调试 (已隐藏 Secret)
+	smsClient, smsInitErr := dependencies.NewSMSClient(&cfg.SMSConfig)          // 直接使用包名调用，错误留给严格启动检查处理
 	//if err != nil {
 	//   暂未接入，注释掉失败抛错是异常的代码
 	//	// 短信服务初始化失败可能是配置问题或依赖问题。
@@ -113,7 +126,71 @@ func SetupDependencies(cfg *config.UserHubConfig, logger *core.ZapLogger) (*AppD
 	deps.COSClient = cosClient
 	logger.Info("COS 客户端初始化成功")
 
+	// 7.1 初始化 CDN 缓存刷新客户端
+	cdnClient, err := dependencies.NewCDNClient(&cfg.CDNConfig, &cfg.COSConfig, logger)
+	if err != nil {
+		logger.Error("初始化 CDN 客户端失败", zap.Error(err))
+		return nil, fmt.Errorf("初始化 CDN 客户端失败: %w", err)
+	}
+	deps.CDNClient = cdnClient
+
+	// 7.2 加载省市一致性校验使用的行政区划数据集
+	if err := cfg.RegionConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("省市校验配置无效: %w", err)
+	}
+	if cfg.RegionConfig.Enabled() {
+		regions, err := utils.LoadRegionDataset(cfg.RegionConfig.DatasetFile)
+		if err != nil {
+			logger.Error("加载行政区划数据集失败", zap.Error(err))
+			return nil, fmt.Errorf("加载行政区划数据集失败: %w", err)
+		}
+		deps.Regions = regions
+		logger.Info("行政区划数据集加载成功", zap.String("mode", cfg.RegionConfig.Mode), zap.String("datasetFile", cfg.RegionConfig.DatasetFile))
+	}
+
+	// 7.3 初始化身份凭证加密器，密钥配置有误时终止启动，避免写入无法解密的数据
+	if len(cfg.CredentialEncryption.EncryptedTypes) > 0 && !cfg.CredentialEncryption.Enabled() {
+		return nil, fmt.Errorf("配置了需要加密的身份类型，但未配置凭证加密密钥")
+	}
+	encryptor, err := dependencies.NewEncryptor(&cfg.CredentialEncryption)
+	if err != nil {
+		return nil, fmt.Errorf("初始化凭证加密器失败: %w", err)
+	}
+	deps.Encryptor = encryptor
+	if cfg.CredentialEncryption.Enabled() {
+		logger.Info("凭证加密器初始化成功", zap.String("activeKeyID", cfg.CredentialEncryption.ActiveKeyID), zap.Int("keys", len(cfg.CredentialEncryption.Keys)))
+	}
+
+	// 7.4 严格启动模式下探测 COS 与短信服务的凭证和连通性，失败则终止启动
+	if err := runStartupChecks(cfg.StartupConfig, cosClient, smsClient, smsInitErr, logger); err != nil {
+		return nil, err
+	}
+
 	// 8. 所有依赖项初始化成功，返回包含它们的结构体 (序号可能需要调整)
 	logger.Info("所有基础依赖项初始化完成")
 	return &deps, nil
 }
+
+// Close 释放长生命周期的基础依赖（数据库连接池、Redis 连接池）。
+// 应在 HTTP 服务器完成排空 (srv.Shutdown 返回) 之后调用，避免在途请求使用到已关闭的连接。
+func (d *AppDependencies) Close() {
+	if d.RedisClient != nil {
+		if err := d.RedisClient.Close(); err != nil {
+			d.Logger.Error("关闭 Redis 连接失败", zap.Error(err))
+		} else {
+			d.Logger.Info("Redis 连接已关闭")
+		}
+	}
+	if d.DB != nil {
+		sqlDB, err := d.DB.DB()
+		if err != nil {
+			d.Logger.Error("获取底层数据库连接失败，无法关闭", zap.Error(err))
+			return
+		}
+		if err := sqlDB.Close(); err != nil {
+			d.Logger.Error("关闭数据库连接失败", zap.Error(err))
+		} else {
+			d.Logger.Info("数据库连接已关闭")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package initialization
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+)
+
+// runStartupChecks 在严格启动模式下实际探测外部服务的凭证与连通性。
+// 设计目的:
+//   - InitCOS / NewSMSClient 只校验配置是否填写，凭证错误要到首次调用时才暴露；
+//     严格模式下提前探测，使配置问题在启动时就以明确的错误终止进程。
+//
+// 参数:
+//   - cfg: 启动配置 (是否启用、单项超时)。
+//   - cosClient: 已初始化的 COS 客户端。
+//   - smsClient: 短信客户端，初始化失败时为 nil。
+//   - smsInitErr: 短信客户端初始化时返回的错误。
+//   - logger: 日志记录器，每项检查的结果都会记录。
+//
+// 返回:
+//   - error: 任一检查失败时返回包含检查项名称的错误；非严格模式下始终返回 nil。
+func runStartupChecks(cfg config.StartupConfig, cosClient dependencies.COSClientInterface, smsClient dependencies.SMSClient, smsInitErr error, logger *core.ZapLogger) error {
+	if !cfg.StrictStartup {
+		logger.Info("未启用严格启动模式，跳过 COS/短信服务连通性检查")
+		return nil
+	}
+
+	timeout := cfg.CheckTimeoutOrDefault()
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{name: "COS", run: cosClient.Ping},
+		{name: "SMS", run: func(ctx context.Context) error {
+			if smsClient == nil {
+				return fmt.Errorf("短信客户端初始化失败: %w", smsInitErr)
+			}
+			return smsClient.Ping(ctx)
+		}},
+	}
+
+	for _, check := range checks {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		start := time.Now()
+		err := check.run(ctx)
+		cancel()
+		if err != nil {
+			logger.Error("启动连通性检查失败", zap.String("check", check.name), zap.Duration("elapsed", time.Since(start)), zap.Error(err))
+			return fmt.Errorf("启动连通性检查 %s 未通过: %w", check.name, err)
+		}
+		logger.Info("启动连通性检查通过", zap.String("check", check.name), zap.Duration("elapsed", time.Since(start)))
+	}
+	return nil
+}
@@ -21,8 +21,10 @@ import (
 	// 导入项目包
 	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/dependencies"
 	_ "github.com/Xushengqwer/user_hub/docs"
 	"github.com/Xushengqwer/user_hub/initialization"
+	"github.com/Xushengqwer/user_hub/middleware"
 	"github.com/Xushengqwer/user_hub/router"
 )
 
@@ -48,8 +50,8 @@ func main() {
 		log.Fatalf("FATAL: 加载配置失败 (%s): %v", configFile, err)
 	}
 
-	// --- [新增] 打印最终生效的配置以供调试 ---
-	configBytes, err := json.MarshalIndent(cfg, "", "  ")
+	// --- 打印最终生效的配置以供调试 (密钥、密码等敏感字段已隐藏) ---
+	configBytes, err := json.MarshalIndent(cfg.Redacted(), "", "  ")
 	if err != nil {
 		log.Fatalf("无法序列化配置以进行打印: %v", err)
 	}
@@ -109,13 +111,37 @@ func main() {
 	appServices := initialization.SetupServices(appDeps)
 	logger.Info("服务层初始化成功")
 
+	// 5.1 监听配置文件以热重载功能开关与注册开关 (路径解析规则与 LoadConfig 一致: 环境变量优先)
+	featureConfigPath := os.Getenv("APP_CONFIG_PATH")
+	if featureConfigPath == "" {
+		featureConfigPath = configFile
+	}
+	// 两者共用同一个文件监听，避免对同一文件注册多个 fsnotify 监听
+	if err := dependencies.WatchConfigFile(featureConfigPath, logger, appServices.FeatureService, appServices.Registration); err != nil {
+		logger.Warn("功能开关与注册开关热重载未启用，将使用启动时的配置", zap.Error(err))
+	}
+
+	// 5.2 启动过期刷新令牌清理任务 (仅在启用刷新令牌白名单时有意义)
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	sweeperDone := make(chan struct{})
+	if cfg.JWTConfig.RefreshWhitelist && cfg.SessionSweep.Enabled {
+		go func() {
+			defer close(sweeperDone)
+			appServices.TokenSweeper.Run(sweeperCtx)
+		}()
+	} else {
+		close(sweeperDone)
+	}
+
 	// 6. 设置路由和中间件
+	drainState := middleware.NewDrainState()
 	setupRouter := router.SetupRouter(
 		logger,
 		&cfg,
 		appDeps.JwtToken,
 		appServices,
 		appDeps,
+		drainState,
 	)
 	logger.Info("Gin 路由器设置完成")
 
@@ -125,6 +151,11 @@ func main() {
 		Addr:    serverAddress,
 		Handler: otelhttp.NewHandler(setupRouter, "HTTPServer"),
 	}
+	// Shutdown 开始时翻转排空标记，之后的响应都会携带 "Connection: close"
+	srv.RegisterOnShutdown(func() {
+		drainState.StartDraining()
+		logger.Info("服务进入排空阶段，后续响应将通知客户端关闭连接")
+	})
 
 	// 8. 启动服务器 (使用 goroutine，以便不阻塞后续的优雅关停逻辑)
 	go func() {
@@ -141,15 +172,24 @@ func main() {
 	logger.Info("接收到关停信号", zap.String("signal", recSignal.String()))
 
 	// 10. 执行优雅关停
-	ctxShutdown, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownTimeout := cfg.ShutdownConfig.Timeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = 10 * time.Second
+	}
+	ctxShutdown, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancelShutdown()
 
-	logger.Info("开始优雅关停 HTTP 服务器...")
+	logger.Info("开始优雅关停 HTTP 服务器...", zap.Duration("timeout", shutdownTimeout))
 	if err := srv.Shutdown(ctxShutdown); err != nil {
 		logger.Error("HTTP 服务器优雅关停失败", zap.Error(err))
 	} else {
 		logger.Info("HTTP 服务器已成功关闭")
 	}
 
+	// 11. 停止后台任务，在途请求排空后再释放数据库、Redis 等长生命周期资源
+	stopSweeper()
+	<-sweeperDone
+	appDeps.Close()
+
 	logger.Info("服务已完全关闭")
 }
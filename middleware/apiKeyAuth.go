@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	commonConstants "github.com/Xushengqwer/go-common/constants"
+	"github.com/Xushengqwer/go-common/core"
+	commonEnums "github.com/Xushengqwer/go-common/models/enums"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/service/apikey"
+)
+
+// APIKeyAuthMiddleware 创建 API 密钥认证中间件。
+// 设计目的:
+//   - 请求携带 X-API-Key 时按密钥哈希查找所属用户，并写入与网关透传相同的用户上下文 (UserID / Role / Status)，
+//     下游处理函数无需区分调用方是通过 JWT 还是 API 密钥认证的。
+//   - 只读密钥只允许 GET / HEAD / OPTIONS 请求，其他方法返回 403。
+//   - 管理员的 API 密钥按普通用户角色写入上下文：管理接口只接受交互式登录的管理员，密钥泄露时不会获得管理权限。
+//
+// 必须注册在 UserContextMiddleware 之后；网关已透传用户身份 (X-User-ID) 时以网关身份为准，忽略 API 密钥。
+func APIKeyAuthMiddleware(apiKeyService apikey.APIKeyService, logger *core.ZapLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(constants.APIKeyHeader)
+		if rawKey == "" || c.GetString(string(commonConstants.UserIDKey)) != "" {
+			c.Next()
+			return
+		}
+
+		principal, err := apiKeyService.Authenticate(c.Request.Context(), rawKey)
+		if err != nil {
+			if errors.Is(err, commonerrors.ErrSystemError) {
+				response.RespondError(c, http.StatusInternalServerError, response.ErrCodeServerInternal, commonerrors.ErrSystemError.Error())
+			} else {
+				response.RespondError(c, http.StatusUnauthorized, response.ErrCodeClientUnauthorized, err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		if !isReadOnlyMethod(c.Request.Method) && !principal.HasScope(enums.APIKeyScopeWrite) {
+			logger.Warn("只读 API 密钥尝试执行写操作",
+				zap.String("userID", principal.UserID),
+				zap.String("keyPrefix", principal.KeyLabel),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+			)
+			response.RespondError(c, http.StatusForbidden, response.ErrCodeClientForbidden, "该 API 密钥为只读权限")
+			c.Abort()
+			return
+		}
+
+		c.Set(string(commonConstants.UserIDKey), principal.UserID)
+		c.Set(string(commonConstants.RoleKey), strconv.FormatUint(uint64(apiKeyRole(principal.Role)), 10))
+		c.Set(string(commonConstants.StatusKey), strconv.FormatUint(uint64(principal.Status), 10))
+		c.Set(constants.APIKeyIDContextKey, principal.KeyID)
+		c.Next()
+	}
+}
+
+// isReadOnlyMethod 判断请求方法是否不会修改资源
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// apiKeyRole 返回 API 密钥调用者在上下文中使用的角色：管理员降为普通用户，其他角色保持不变
+func apiKeyRole(role commonEnums.UserRole) commonEnums.UserRole {
+	if role == commonEnums.RoleAdmin {
+		return commonEnums.RoleUser
+	}
+	return role
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	commonConstants "github.com/Xushengqwer/go-common/constants"
+	"github.com/Xushengqwer/go-common/core"
+	commonEnums "github.com/Xushengqwer/go-common/models/enums"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/service/apikey"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// fakeAPIKeyService 内嵌接口，仅实现认证，始终返回固定的调用者
+type fakeAPIKeyService struct {
+	apikey.APIKeyService
+	principal apikey.Principal
+}
+
+func (s fakeAPIKeyService) Authenticate(_ context.Context, _ string) (*apikey.Principal, error) {
+	p := s.principal
+	return &p, nil
+}
+
+func TestAPIKeyAuthCapsAdminRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     commonEnums.UserRole
+		scopes   []enums.APIKeyScope
+		method   string
+		wantCode int
+		wantRole commonEnums.UserRole
+	}{
+		{name: "管理员密钥降为普通用户", role: commonEnums.RoleAdmin, scopes: []enums.APIKeyScope{enums.APIKeyScopeRead}, method: http.MethodGet, wantCode: http.StatusOK, wantRole: commonEnums.RoleUser},
+		{name: "管理员写权限密钥同样降级", role: commonEnums.RoleAdmin, scopes: []enums.APIKeyScope{enums.APIKeyScopeWrite}, method: http.MethodPost, wantCode: http.StatusOK, wantRole: commonEnums.RoleUser},
+		{name: "普通用户角色不变", role: commonEnums.RoleUser, scopes: []enums.APIKeyScope{enums.APIKeyScopeRead}, method: http.MethodGet, wantCode: http.StatusOK, wantRole: commonEnums.RoleUser},
+		{name: "访客角色不变", role: commonEnums.RoleGuest, scopes: []enums.APIKeyScope{enums.APIKeyScopeRead}, method: http.MethodGet, wantCode: http.StatusOK, wantRole: commonEnums.RoleGuest},
+		{name: "只读密钥不能执行写操作", role: commonEnums.RoleUser, scopes: []enums.APIKeyScope{enums.APIKeyScopeRead}, method: http.MethodPost, wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := fakeAPIKeyService{principal: apikey.Principal{KeyID: 7, UserID: "u1", Role: tt.role, Status: commonEnums.StatusActive, Scopes: tt.scopes}}
+			var gotRole string
+			var gotKeyID any
+			r := gin.New()
+			r.Use(APIKeyAuthMiddleware(service, newTestLogger(t)))
+			r.Handle(tt.method, "/resource", func(c *gin.Context) {
+				gotRole = c.GetString(string(commonConstants.RoleKey))
+				gotKeyID, _ = c.Get(constants.APIKeyIDContextKey)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/resource", nil)
+			req.Header.Set(constants.APIKeyHeader, "uhk_test")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("状态码 = %d，期望 %d，响应体: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if want := strconv.FormatUint(uint64(tt.wantRole), 10); gotRole != want {
+				t.Errorf("上下文角色 = %q，期望 %q", gotRole, want)
+			}
+			if gotKeyID != uint(7) {
+				t.Errorf("上下文密钥 ID = %v，期望 7", gotKeyID)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+const (
+	// defaultCompressionMinSize 未配置最小压缩阈值时使用的默认值 (字节)
+	defaultCompressionMinSize = 1024
+)
+
+// defaultCompressibleTypes 未配置 Content-Type 白名单时默认允许压缩的类型
+var defaultCompressibleTypes = []string{
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"text/*",
+}
+
+// CompressionMiddleware 创建 gzip 响应压缩中间件。
+// 设计目的:
+//   - 仅当客户端通过 Accept-Encoding 声明支持 gzip 时才压缩。
+//   - 响应体需达到最小阈值且 Content-Type 位于白名单内（图片等已压缩内容不在白名单中，不会被重复压缩）。
+//   - 下游已设置 Content-Encoding 的响应、以及 ExcludedPaths 中的路径（如流式导出）直接透传。
+//   - 所有经过本中间件的响应都带有 Vary: Accept-Encoding（包括未压缩的），避免共享缓存把未压缩版本返回给支持 gzip 的客户端，反之亦然。
+//   - Level 为 0 时使用默认级别，不支持配置为 gzip.NoCompression；不需要压缩时应关闭中间件。
+//
+// 是否压缩在第一次 Write 时决定，Gin 的 JSON 渲染会一次性写出完整响应体，因此阈值判断是准确的。
+func CompressionMiddleware(cfg config.CompressionConfig) gin.HandlerFunc {
+	level := cfg.Level
+	if level == 0 || level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = defaultCompressionMinSize
+	}
+	contentTypes := cfg.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = defaultCompressibleTypes
+	}
+
+	return func(c *gin.Context) {
+		addVaryAcceptEncoding(c.Writer.Header())
+		if !acceptsGzip(c.GetHeader("Accept-Encoding")) || isExcludedPath(c.Request.URL.Path, cfg.ExcludedPaths) {
+			c.Next()
+			return
+		}
+
+		gw := &gzipResponseWriter{
+			ResponseWriter: c.Writer,
+			level:          level,
+			minSize:        minSize,
+			contentTypes:   contentTypes,
+		}
+		c.Writer = gw
+		defer gw.close()
+
+		c.Next()
+	}
+}
+
+// gzipResponseWriter 包装 gin.ResponseWriter，按需将响应体写入 gzip 流
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	level        int
+	minSize      int
+	contentTypes []string
+
+	decided bool         // 是否已经完成“压缩与否”的判断
+	gz      *gzip.Writer // 非 nil 表示当前响应正在被压缩
+}
+
+// Write 在第一次写入时决定是否压缩，之后的写入沿用该决定
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		if w.shouldCompress(len(data)) {
+			h := w.Header()
+			h.Set("Content-Encoding", "gzip")
+			h.Del("Content-Length") // 压缩后长度改变，交由 chunked 传输
+			gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.level)
+			if err != nil {
+				// 级别已在创建中间件时校验，这里仅作兜底：放弃压缩，直接透传
+				h.Del("Content-Encoding")
+			} else {
+				w.gz = gz
+			}
+		}
+	}
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 保证 c.String 等通过 WriteString 输出的内容同样经过压缩判断
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 先刷新 gzip 缓冲区，再刷新底层连接，保证 SSE 等场景下数据及时送达
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// close 结束 gzip 流，写出尾部校验数据
+func (w *gzipResponseWriter) close() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
+
+// shouldCompress 根据响应状态、已有编码、Content-Type 和大小判断是否需要压缩
+func (w *gzipResponseWriter) shouldCompress(size int) bool {
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false // 下游已经编码过（例如直接转发的压缩包），不再重复压缩
+	}
+	if cl := h.Get("Content-Length"); cl != "" {
+		if n, err := strconv.Atoi(cl); err == nil && n < w.minSize {
+			return false
+		}
+	} else if size < w.minSize {
+		return false
+	}
+	return isCompressibleType(h.Get("Content-Type"), w.contentTypes)
+}
+
+// addVaryAcceptEncoding 在 Vary 头中追加 Accept-Encoding，已存在时不重复添加
+func addVaryAcceptEncoding(h http.Header) {
+	for _, value := range h.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field == "*" || strings.EqualFold(field, "Accept-Encoding") {
+				return
+			}
+		}
+	}
+	h.Add("Vary", "Accept-Encoding")
+}
+
+// acceptsGzip 解析 Accept-Encoding 头，判断客户端是否接受 gzip（q=0 视为明确拒绝）
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		rejected := false
+		for _, param := range fields[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if q, ok := strings.CutPrefix(param, "q="); ok {
+				if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+					rejected = true
+				}
+			}
+		}
+		if !rejected {
+			return true
+		}
+	}
+	return false
+}
+
+// isCompressibleType 判断 Content-Type 是否在白名单中，支持 "text/*" 形式的通配
+func isCompressibleType(contentType string, allowlist []string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	for _, allowed := range allowlist {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(mediaType, prefix) {
+				return true
+			}
+		} else if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// isExcludedPath 判断请求路径是否命中给定的路径前缀 (压缩排除列表、失败请求体日志白名单共用)
+func isExcludedPath(path string, excluded []string) bool {
+	for _, prefix := range excluded {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
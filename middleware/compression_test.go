@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "未携带请求头", header: "", want: false},
+		{name: "仅 gzip", header: "gzip", want: true},
+		{name: "大小写不敏感", header: "GZip", want: true},
+		{name: "多个编码", header: "br, gzip, deflate", want: true},
+		{name: "带权重", header: "gzip;q=0.5", want: true},
+		{name: "权重含空格", header: "gzip; q = 0.8", want: true},
+		{name: "q=0 明确拒绝", header: "gzip;q=0", want: false},
+		{name: "q=0.0 明确拒绝", header: "gzip;q=0.0", want: false},
+		{name: "通配符", header: "*", want: true},
+		{name: "通配符被拒绝", header: "*;q=0", want: false},
+		{name: "不支持 gzip", header: "br, deflate", want: false},
+		{name: "相似名称不匹配", header: "x-gzip-custom", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acceptsGzip(tt.header); got != tt.want {
+				t.Errorf("acceptsGzip(%q) = %v，期望 %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+	small := strings.Repeat("a", 100)
+	tests := []struct {
+		name           string
+		cfg            config.CompressionConfig
+		path           string
+		acceptEncoding string
+		status         int
+		contentType    string
+		body           string
+		preset         http.Header // 下游处理函数预先设置的响应头
+		upstreamVary   string      // 先于压缩中间件执行的中间件 (如 CORS) 设置的 Vary
+		wantGzip       bool
+	}{
+		{name: "达到阈值的 JSON 被压缩", acceptEncoding: "gzip", contentType: "application/json; charset=utf-8", body: large, wantGzip: true},
+		{name: "错误响应同样压缩", acceptEncoding: "gzip", status: http.StatusInternalServerError, contentType: "application/json", body: large, wantGzip: true},
+		{name: "低于默认阈值不压缩", acceptEncoding: "gzip", contentType: "application/json", body: small},
+		{name: "自定义阈值", cfg: config.CompressionConfig{MinSize: 50}, acceptEncoding: "gzip", contentType: "application/json", body: small, wantGzip: true},
+		{name: "Content-Length 低于阈值不压缩", acceptEncoding: "gzip", contentType: "application/json", body: large, preset: http.Header{"Content-Length": {"10"}}},
+		{name: "text 通配类型被压缩", acceptEncoding: "gzip", contentType: "text/csv", body: large, wantGzip: true},
+		{name: "图片不在白名单", acceptEncoding: "gzip", contentType: "image/png", body: large},
+		{name: "缺少 Content-Type 不压缩", acceptEncoding: "gzip", body: large},
+		{name: "自定义白名单替换默认列表", cfg: config.CompressionConfig{ContentTypes: []string{"application/xml"}}, acceptEncoding: "gzip", contentType: "application/json", body: large},
+		{name: "客户端不接受 gzip", acceptEncoding: "br", contentType: "application/json", body: large},
+		{name: "排除路径", cfg: config.CompressionConfig{ExcludedPaths: []string{"/export"}}, path: "/export/users", acceptEncoding: "gzip", contentType: "application/json", body: large},
+		{name: "下游已编码不重复压缩", acceptEncoding: "gzip", contentType: "application/json", body: large, preset: http.Header{"Content-Encoding": {"br"}}},
+		{name: "已有 Vary 不重复追加", acceptEncoding: "gzip", contentType: "application/json", body: large, upstreamVary: "Origin, accept-encoding", wantGzip: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = "/resource"
+			}
+			status := tt.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			r := gin.New()
+			if tt.upstreamVary != "" {
+				r.Use(func(c *gin.Context) { c.Header("Vary", tt.upstreamVary) })
+			}
+			r.Use(CompressionMiddleware(tt.cfg))
+			r.GET("/*path", func(c *gin.Context) {
+				for key, values := range tt.preset {
+					for _, v := range values {
+						c.Writer.Header().Add(key, v)
+					}
+				}
+				if tt.contentType != "" {
+					c.Header("Content-Type", tt.contentType)
+				}
+				c.Status(status)
+				_, _ = c.Writer.WriteString(tt.body)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			gotGzip := w.Header().Get("Content-Encoding") == "gzip"
+			if gotGzip != tt.wantGzip {
+				t.Fatalf("是否压缩 = %v，期望 %v (Content-Encoding: %q)", gotGzip, tt.wantGzip, w.Header().Get("Content-Encoding"))
+			}
+			// 无论是否压缩，响应都必须声明随 Accept-Encoding 变化，且只声明一次
+			if n := strings.Count(strings.ToLower(strings.Join(w.Header().Values("Vary"), ",")), "accept-encoding"); n != 1 {
+				t.Errorf("Vary 中 Accept-Encoding 出现 %d 次，期望 1 次 (Vary: %v)", n, w.Header().Values("Vary"))
+			}
+
+			body := w.Body.String()
+			if gotGzip {
+				zr, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("解析 gzip 响应失败: %v", err)
+				}
+				raw, err := io.ReadAll(zr)
+				if err != nil {
+					t.Fatalf("解压响应失败: %v", err)
+				}
+				body = string(raw)
+			}
+			if body != tt.body {
+				t.Errorf("响应体长度 = %d，期望 %d", len(body), len(tt.body))
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DrainState 记录服务是否已进入关停排空阶段。
+// 由 main 在 http.Server.RegisterOnShutdown 中翻转，中间件据此通知客户端关闭连接。
+type DrainState struct {
+	draining atomic.Bool
+}
+
+// NewDrainState 创建一个处于正常服务状态的 DrainState
+func NewDrainState() *DrainState {
+	return &DrainState{}
+}
+
+// StartDraining 标记服务进入排空阶段，可被重复调用
+func (s *DrainState) StartDraining() {
+	s.draining.Store(true)
+}
+
+// IsDraining 返回服务是否处于排空阶段
+func (s *DrainState) IsDraining() bool {
+	return s.draining.Load()
+}
+
+// DrainMiddleware 在服务排空期间为响应加上 "Connection: close"，
+// 让客户端（以及上游负载均衡）不再复用当前连接，而是重新建连到其他实例，使滚动发布更平滑。
+func DrainMiddleware(state *DrainState) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if state.IsDraining() {
+			c.Header("Connection", "close")
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/Xushengqwer/go-common/constants"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// redactedValue 脱敏字段在日志中的替代值
+const redactedValue = "[REDACTED]"
+
+// ErrorBodyLogMiddleware 创建失败请求的请求体日志中间件。
+// 设计目的:
+//   - 访问日志只有状态码，排查 400/500 时往往需要看到请求体；该中间件只在响应为非 2xx 且路径位于白名单时记录请求体。
+//   - 请求体在 handler 读取时顺带复制 (最多 MaxBodyBytes 字节)，不提前读取，也不改变 handler 看到的内容。
+//   - 只记录能解析的 JSON，并按字段名脱敏；非 JSON 或超出上限的请求体只记录长度，保证密码、凭证、令牌不会进入日志。
+func ErrorBodyLogMiddleware(cfg config.ErrorBodyLogConfig, logger *core.ZapLogger) gin.HandlerFunc {
+	maxBytes := cfg.MaxBodyBytesOrDefault()
+	redactFields := make([]string, 0, len(cfg.RedactFieldsOrDefault()))
+	for _, field := range cfg.RedactFieldsOrDefault() {
+		redactFields = append(redactFields, strings.ToLower(field))
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || !isExcludedPath(c.Request.URL.Path, cfg.Routes) {
+			c.Next()
+			return
+		}
+
+		capture := &bodyCapture{ReadCloser: c.Request.Body, limit: maxBytes}
+		c.Request.Body = capture
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= 200 && status < 300 {
+			return
+		}
+		logger.Warn("请求失败，记录请求体用于排查",
+			zap.String("trace_id", traceIDOf(c)),
+			zap.String("http.method", c.Request.Method),
+			zap.String("url.path", c.Request.URL.Path),
+			zap.Int("http.status_code", status),
+			zap.Int64("request.body_size", capture.total),
+			zap.String("request.body", capture.redactedBody(redactFields)),
+		)
+	}
+}
+
+// bodyCapture 包装请求体，在被读取时复制前 limit 字节
+type bodyCapture struct {
+	io.ReadCloser
+	limit int
+
+	buf   bytes.Buffer // 已复制的请求体
+	total int64        // handler 实际读取的总字节数
+}
+
+// Read 透传读取，并复制不超过上限的部分
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.total += int64(n)
+	if remaining := b.limit - b.buf.Len(); remaining > 0 && n > 0 {
+		b.buf.Write(p[:min(n, remaining)])
+	}
+	return n, err
+}
+
+// redactedBody 返回脱敏后的请求体；请求体为空、超过上限或不是 JSON 时只返回说明文字
+func (b *bodyCapture) redactedBody(redactFields []string) string {
+	if b.total == 0 {
+		return ""
+	}
+	if b.total > int64(b.limit) {
+		return "[omitted: body exceeds max_body_bytes]"
+	}
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(b.buf.Bytes()))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return "[omitted: body is not JSON]"
+	}
+	out, err := json.Marshal(redactJSON(value, redactFields))
+	if err != nil {
+		return "[omitted: body is not JSON]"
+	}
+	return string(out)
+}
+
+// redactJSON 递归替换字段名包含任一脱敏片段 (不区分大小写) 的值
+func redactJSON(value any, redactFields []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if shouldRedact(key, redactFields) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSON(child, redactFields)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = redactJSON(child, redactFields)
+		}
+	}
+	return value
+}
+
+// shouldRedact 判断字段名是否需要脱敏
+func shouldRedact(key string, redactFields []string) bool {
+	key = strings.ToLower(key)
+	for _, field := range redactFields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// traceIDOf 获取当前请求的 TraceID，与访问日志的取值方式一致，便于关联
+func traceIDOf(c *gin.Context) string {
+	if v, ok := c.Get(constants.TraceIDKey); ok {
+		if traceID, _ := v.(string); traceID != "" {
+			return traceID
+		}
+	}
+	if sc := trace.SpanFromContext(c.Request.Context()).SpanContext(); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return "unknown-trace-id"
+}
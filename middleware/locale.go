@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// LocaleMiddleware 创建基于 Accept-Language 的响应语言选择中间件。
+// 设计目的:
+//   - 只在配置的支持语言中选择，避免出现部分翻译的响应；无法匹配时回退到默认语言。
+//   - 所选语言写入 Gin Context (constants.LocaleContextKey) 供后续文案目录使用，并通过 Content-Language 告知客户端。
+//
+// 匹配规则按 q 值从高到低逐项尝试：先精确匹配 (忽略大小写)，再按主语言匹配 (如 "zh" 或 "zh-TW" 匹配 "zh-CN")，"*" 匹配默认语言。
+func LocaleMiddleware(cfg config.LocaleConfig) gin.HandlerFunc {
+	supported := cfg.SupportedOrDefault()
+	fallback := cfg.DefaultOrDefault()
+	return func(c *gin.Context) {
+		locale := selectLocale(c.GetHeader("Accept-Language"), supported, fallback)
+		c.Set(constants.LocaleContextKey, locale)
+		c.Header("Content-Language", locale)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// languageRange Accept-Language 中的一项
+type languageRange struct {
+	tag string
+	q   float64
+}
+
+// selectLocale 从 Accept-Language 中选出第一个可匹配支持列表的语言，均不匹配时返回 fallback
+func selectLocale(header string, supported []string, fallback string) string {
+	for _, r := range parseAcceptLanguage(header) {
+		if r.tag == "*" {
+			return fallback
+		}
+		if locale, ok := matchLocale(r.tag, supported); ok {
+			return locale
+		}
+	}
+	return fallback
+}
+
+// parseAcceptLanguage 解析 Accept-Language，按 q 值降序返回 (q 相同时保持原顺序)，忽略 q=0 的项
+func parseAcceptLanguage(header string) []languageRange {
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, languageRange{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	return ranges
+}
+
+// matchLocale 在支持列表中查找与 tag 匹配的语言：精确匹配优先，其次主语言相同
+func matchLocale(tag string, supported []string) (string, bool) {
+	for _, locale := range supported {
+		if strings.EqualFold(locale, tag) {
+			return locale, true
+		}
+	}
+	primary := primaryLanguage(tag)
+	for _, locale := range supported {
+		if strings.EqualFold(primaryLanguage(locale), primary) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// primaryLanguage 返回语言标签的主语言子标签，如 "zh-CN" -> "zh"
+func primaryLanguage(tag string) string {
+	primary, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	return primary
+}
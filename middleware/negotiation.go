@@ -0,0 +1,259 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	mimeJSON    = "application/json"
+	mimeXML     = "application/xml"
+	mimeTextXML = "text/xml"
+
+	// xmlRootElement XML 响应的根元素名，对应 JSON 的 APIResponse 信封
+	xmlRootElement = "response"
+	// xmlItemElement JSON 数组元素在 XML 中的元素名
+	xmlItemElement = "item"
+	// xmlEntryElement 键名不是合法 XML 元素名时使用的元素名，原键名放在 key 属性中
+	xmlEntryElement = "entry"
+)
+
+// ContentNegotiationMiddleware 创建基于 Accept 头的响应格式协商中间件。
+// 设计目的:
+//   - 业务代码仍统一通过 response.Respond* 输出 JSON；客户端明确偏好 XML 时，由中间件将 JSON 响应体转换为等价的 XML。
+//   - 未携带 Accept、或 JSON 优先级不低于 XML 时直接放行，JSON 路径不做任何缓冲或转换。
+//   - 只转换 Content-Type 为 application/json 的响应，图片、文件等其他类型原样透传。
+//
+// XML 结构与 JSON 一一对应：根元素为 <response>，对象字段按原顺序成为子元素，数组元素成为 <item>，
+// 不能作为 XML 元素名的键输出为 <entry key="原键名">。
+func ContentNegotiationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept")
+		xmlType, ok := preferredXMLType(c.GetHeader("Accept"))
+		if !ok {
+			c.Next()
+			return
+		}
+
+		xw := &xmlResponseWriter{ResponseWriter: c.Writer, contentType: xmlType}
+		c.Writer = xw
+		defer xw.finish()
+
+		c.Next()
+	}
+}
+
+// xmlResponseWriter 包装 gin.ResponseWriter，缓冲 JSON 响应体并在请求结束时转换为 XML 输出
+type xmlResponseWriter struct {
+	gin.ResponseWriter
+	contentType string
+
+	decided   bool         // 是否已经完成“转换与否”的判断
+	buffering bool         // 当前响应是否被缓冲等待转换
+	buf       bytes.Buffer // 缓冲的 JSON 响应体
+}
+
+// Write 在第一次写入时根据 Content-Type 决定是否转换，之后的写入沿用该决定
+func (w *xmlResponseWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.buffering = !w.ResponseWriter.Written() && isMediaType(w.Header().Get("Content-Type"), mimeJSON)
+	}
+	if w.buffering {
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 保证 c.String 等通过 WriteString 输出的内容同样经过转换判断
+func (w *xmlResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 缓冲期间不向下游刷新，避免提前写出响应头
+func (w *xmlResponseWriter) Flush() {
+	if !w.buffering {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// finish 将缓冲的 JSON 转换为 XML 写出；JSON 无法解析时按原样写出，保证响应不丢失
+func (w *xmlResponseWriter) finish() {
+	if !w.buffering {
+		return
+	}
+	w.buffering = false
+
+	h := w.Header()
+	h.Del("Content-Length")
+	var out bytes.Buffer
+	if err := jsonToXML(bytes.NewReader(w.buf.Bytes()), &out); err != nil {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		return
+	}
+	h.Set("Content-Type", w.contentType+"; charset=utf-8")
+	_, _ = w.ResponseWriter.Write(out.Bytes())
+}
+
+// preferredXMLType 解析 Accept 头，XML 的优先级严格高于 JSON 时返回客户端请求的 XML 类型
+// - 同等优先级 (包括 "*/*" 和未携带 Accept) 时以 JSON 为默认
+func preferredXMLType(header string) (string, bool) {
+	if header == "" {
+		return "", false
+	}
+	jsonQ := acceptQuality(header, mimeJSON)
+	appXMLQ := acceptQuality(header, mimeXML)
+	textXMLQ := acceptQuality(header, mimeTextXML)
+
+	xmlType, xmlQ := mimeXML, appXMLQ
+	if textXMLQ > appXMLQ {
+		xmlType, xmlQ = mimeTextXML, textXMLQ
+	}
+	if xmlQ > jsonQ {
+		return xmlType, true
+	}
+	return "", false
+}
+
+// acceptQuality 返回 Accept 头中与 mediaType 最匹配的媒体范围的 q 值，未匹配时为 0
+// - 精确匹配优先于 "type/*"，"type/*" 优先于 "*/*"
+func acceptQuality(header, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	bestSpecificity, bestQ := -1, 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		rangeType := strings.ToLower(strings.TrimSpace(fields[0]))
+
+		specificity := -1
+		switch {
+		case rangeType == mediaType:
+			specificity = 2
+		case rangeType == mainType+"/*":
+			specificity = 1
+		case rangeType == "*/*":
+			specificity = 0
+		}
+		if specificity < bestSpecificity || specificity < 0 {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		bestSpecificity, bestQ = specificity, q
+	}
+	return bestQ
+}
+
+// isMediaType 判断 Content-Type 的媒体类型部分是否为指定类型
+func isMediaType(contentType, mediaType string) bool {
+	return strings.EqualFold(strings.TrimSpace(strings.Split(contentType, ";")[0]), mediaType)
+}
+
+// jsonToXML 以流式方式将一个 JSON 值转换为 XML，保留对象字段的原始顺序
+func jsonToXML(r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	enc := xml.NewEncoder(w)
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if err := writeXMLValue(dec, enc, xml.StartElement{Name: xml.Name{Local: xmlRootElement}}); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// writeXMLValue 读取下一个 JSON 值并以 start 为外层元素写出
+func writeXMLValue(dec *json.Decoder, enc *xml.Encoder, start xml.StartElement) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return errors.New("JSON 对象的键不是字符串")
+				}
+				if err := writeXMLValue(dec, enc, elementForKey(key)); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for dec.More() {
+				if err := writeXMLValue(dec, enc, xml.StartElement{Name: xml.Name{Local: xmlItemElement}}); err != nil {
+					return err
+				}
+			}
+		}
+		// 读取对应的结束分隔符 '}' 或 ']'
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	case string:
+		err = enc.EncodeToken(xml.CharData(v))
+	case json.Number:
+		err = enc.EncodeToken(xml.CharData(v.String()))
+	case bool:
+		err = enc.EncodeToken(xml.CharData(strconv.FormatBool(v)))
+	case nil:
+		// null 输出为空元素
+	}
+	if err != nil {
+		return err
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// elementForKey 键名可作为 XML 元素名时直接使用，否则输出为带 key 属性的 <entry> 元素
+func elementForKey(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: xmlEntryElement},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+// isXMLName 判断字符串能否作为 XML 元素名 (保守规则：字母或下划线开头，其后为字母、数字、'_'、'-'、'.'，且不以 xml 开头)
+func isXMLName(s string) bool {
+	if s == "" || strings.HasPrefix(strings.ToLower(s), "xml") {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
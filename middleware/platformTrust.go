@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// platformHeader 客户端平台请求头
+const platformHeader = "X-Platform"
+
+// PlatformTrustMiddleware 创建 X-Platform 信任中间件。
+// 设计目的:
+//   - 平台决定刷新令牌通过 Cookie 还是响应体下发，恶意客户端伪造 X-Platform 可以改变令牌的处理方式。
+//   - 请求的直连对端不是可信代理时，用 User-Agent 推断出的平台 (推断不出时用兜底平台) 覆盖 X-Platform，
+//     下游处理函数仍按原方式读取请求头，无需感知信任策略。
+//
+// 只应在 trusted_proxy 模式下注册；TrustedProxies 中无法解析的条目会被忽略并记录警告。
+func PlatformTrustMiddleware(cfg config.PlatformTrustConfig, logger *core.ZapLogger) gin.HandlerFunc {
+	var prefixes []netip.Prefix
+	for _, entry := range cfg.TrustedProxies {
+		prefix, err := parseIPOrPrefix(entry)
+		if err != nil {
+			logger.Warn("忽略无法解析的可信代理地址", zap.String("entry", entry), zap.Error(err))
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	fallback := enums.Platform(cfg.DefaultPlatformOrDefault())
+
+	return func(c *gin.Context) {
+		if !isTrustedPeer(c.RemoteIP(), prefixes) {
+			platform := platformFromUserAgent(c.Request.UserAgent())
+			if platform == "" {
+				platform = fallback
+			}
+			c.Request.Header.Set(platformHeader, string(platform))
+		}
+		c.Next()
+	}
+}
+
+// parseIPOrPrefix 解析单个 IP 或 CIDR，单个 IP 视为完整长度的前缀
+func parseIPOrPrefix(entry string) (netip.Prefix, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// isTrustedPeer 判断直连对端地址是否位于可信代理列表中
+func isTrustedPeer(remoteIP string, prefixes []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(remoteIP)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// platformFromUserAgent 根据 User-Agent 粗略推断客户端平台，无法判断时返回空
+// - 微信小程序的 User-Agent 同时包含 MicroMessenger 与 miniProgram；普通的微信内置浏览器按 web 处理。
+// - 常见原生 HTTP 库 (okhttp、CFNetwork、Dart 等) 视为 app，带 Mozilla 标识的视为浏览器。
+func platformFromUserAgent(userAgent string) enums.Platform {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case ua == "":
+		return ""
+	case strings.Contains(ua, "micromessenger") && strings.Contains(ua, "miniprogram"):
+		return enums.PlatformWechat
+	case strings.Contains(ua, "okhttp"), strings.Contains(ua, "cfnetwork"), strings.Contains(ua, "dart/"), strings.Contains(ua, "alamofire"):
+		return enums.PlatformApp
+	case strings.Contains(ua, "mozilla/"):
+		return enums.PlatformWeb
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+)
+
+// QueryCountMiddleware 创建单请求 SQL 次数统计中间件。
+// 设计目的:
+//   - 为请求上下文挂载 SQL 计数器，由 GORM 计数回调 (dependencies.RegisterQueryCounting) 累加。
+//   - 请求结束时次数超过阈值则记录告警，用于在开发与测试环境中尽早发现“循环内逐条查询”之类的 N+1 问题。
+//   - 只统计经由请求上下文 (c.Request.Context()) 执行的 SQL。
+func QueryCountMiddleware(cfg config.QueryCountLogConfig, logger *core.ZapLogger) gin.HandlerFunc {
+	threshold := int64(cfg.WarnThresholdOrDefault())
+
+	return func(c *gin.Context) {
+		ctx, counter := dependencies.WithQueryCounter(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if count := counter.Count(); count > threshold {
+			logger.Warn("单个请求执行的 SQL 次数超过阈值，可能存在 N+1 查询",
+				zap.String("trace_id", traceIDOf(c)),
+				zap.String("http.method", c.Request.Method),
+				zap.String("http.route", c.FullPath()),
+				zap.Int64("db.query_count", count),
+				zap.Int64("threshold", threshold),
+			)
+		}
+	}
+}
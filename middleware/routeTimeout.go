@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/Xushengqwer/go-common/core"
+	commonMiddleware "github.com/Xushengqwer/go-common/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// skipTimeoutContextKey go-common 的 RequestTimeoutMiddleware 识别的“跳过超时”标记键，
+// 该中间件看到此标记为 true 时直接放行，不再施加全局超时。
+const skipTimeoutContextKey = "skipTimeout"
+
+// routeTimeout 一条已生效的超时覆盖及其对应的超时中间件
+type routeTimeout struct {
+	prefix  string
+	handler gin.HandlerFunc
+}
+
+// RouteTimeoutMiddleware 创建按路由组覆盖请求超时的中间件链，需按顺序注册在全局 RequestTimeoutMiddleware 之前。
+// 设计目的:
+//   - 全局超时保持较短，只为头像上传、导入导出等耗时接口放宽。
+//   - 子 Context 的截止时间不能晚于父 Context，因此不能简单地在全局超时之内再套一层更长的超时；
+//     命中覆盖的请求改为由本中间件施加对应的超时，并设置跳过标记让全局超时中间件放行。
+//   - 按路由模板 (c.FullPath) 做前缀匹配，取最长前缀；未命中的请求直接交给全局超时。
+//
+// 返回两个处理函数：第一个为命中的请求施加覆盖超时，第二个再设置跳过标记。
+// 跳过标记必须在覆盖超时生效之后设置，否则覆盖超时本身也会被跳过。
+func RouteTimeoutMiddleware(cfg config.RouteTimeoutConfig, logger *core.ZapLogger) gin.HandlersChain {
+	var overrides []routeTimeout
+	for _, override := range cfg.Overrides {
+		if override.PathPrefix == "" || override.Timeout <= 0 {
+			logger.Warn("忽略无效的路由超时覆盖配置", zap.String("pathPrefix", override.PathPrefix), zap.Duration("timeout", override.Timeout))
+			continue
+		}
+		overrides = append(overrides, routeTimeout{
+			prefix:  override.PathPrefix,
+			handler: commonMiddleware.RequestTimeoutMiddleware(logger, override.Timeout),
+		})
+	}
+
+	applyTimeout := func(c *gin.Context) {
+		if matched := matchRouteTimeout(overrides, c.FullPath()); matched != nil {
+			matched.handler(c)
+			return
+		}
+		c.Next()
+	}
+	skipGlobalTimeout := func(c *gin.Context) {
+		if matchRouteTimeout(overrides, c.FullPath()) != nil {
+			c.Set(skipTimeoutContextKey, true)
+		}
+		c.Next()
+	}
+	return gin.HandlersChain{applyTimeout, skipGlobalTimeout}
+}
+
+// matchRouteTimeout 返回与路由模板匹配的最长前缀覆盖，未匹配时返回 nil
+func matchRouteTimeout(overrides []routeTimeout, fullPath string) *routeTimeout {
+	if fullPath == "" { // 未匹配到路由 (404) 时 FullPath 为空
+		return nil
+	}
+	var matched *routeTimeout
+	for i := range overrides {
+		if strings.HasPrefix(fullPath, overrides[i].prefix) && (matched == nil || len(overrides[i].prefix) > len(matched.prefix)) {
+			matched = &overrides[i]
+		}
+	}
+	return matched
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// tenantIDPattern 租户 ID 的格式：字母、数字、'_'、'-'，长度与身份表的 tenant_id 列一致
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// TenantMiddleware 创建租户识别中间件。
+// 设计目的:
+//   - 从配置的请求头读取租户 ID 并写入请求上下文 (utils.WithTenant)，身份仓库据此限定标识符的唯一性与查找范围。
+//   - 未携带请求头时不写入，按全局租户处理；格式非法或不在允许列表中的租户 ID 直接以 400 拒绝。
+func TenantMiddleware(cfg config.TenantConfig, logger *core.ZapLogger) gin.HandlerFunc {
+	header := cfg.HeaderOrDefault()
+	return func(c *gin.Context) {
+		tenantID := c.GetHeader(header)
+		if tenantID == "" {
+			c.Next()
+			return
+		}
+		if !tenantIDPattern.MatchString(tenantID) || (len(cfg.Allowed) > 0 && !slices.Contains(cfg.Allowed, tenantID)) {
+			logger.Warn("请求携带的租户 ID 无效", zap.String("header", header), zap.String("tenantID", tenantID))
+			response.RespondError(c, http.StatusBadRequest, response.ErrCodeClientInvalidInput, "无效的租户")
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(utils.WithTenant(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// UpstreamErrorDetailMiddleware 创建第三方错误详情开关中间件。
+// 设计目的:
+//   - 将配置写入 Gin Context (constants.UpstreamErrorDetailContextKey)，由控制器在输出第三方失败响应时
+//     结合调用者角色决定是否附带脱敏的上游错误摘要，各控制器无需单独注入该配置。
+//   - 未写入该键时 (未启用) 控制器只输出通用文案。
+func UpstreamErrorDetailMiddleware(cfg config.UpstreamErrorDetailConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(constants.UpstreamErrorDetailContextKey, cfg)
+		c.Next()
+	}
+}
@@ -1,12 +1,45 @@
 package dto
 
 type AccountRegisterData struct {
-	Account         string `json:"account" binding:"required,Account"`   // 使用 "Account" 校验器
-	Password        string `json:"password" binding:"required,Password"` // 使用 "Password" 校验器
-	ConfirmPassword string `json:"confirmPassword" binding:"required"`   // 这里没有自定义格式校验器，但如果需要在服务端检查密码一致性，可以添加 `eqfield=Password`，不过这通常在前端或服务层处理。
+	Account         string `json:"account" binding:"required,Account" sanitize:"trim"` // 使用 "Account" 校验器
+	Password        string `json:"password" binding:"required,Password"`               // 使用 "Password" 校验器
+	ConfirmPassword string `json:"confirmPassword" binding:"required"`                 // 这里没有自定义格式校验器，但如果需要在服务端检查密码一致性，可以添加 `eqfield=Password`，不过这通常在前端或服务层处理。
 }
 
 type AccountLoginData struct {
-	Account  string `json:"account" binding:"required"`  // 用户账号
-	Password string `json:"password" binding:"required"` // 密码
+	Account  string `json:"account" binding:"required" sanitize:"trim"` // 用户账号
+	Password string `json:"password" binding:"required"`                // 密码
+}
+
+// ReactivateAccountDTO 定义重新激活已停用账号的请求结构体
+type ReactivateAccountDTO struct {
+	// 登录已停用账号时返回的一次性重新激活凭证
+	ReactivationTicket string `json:"reactivation_ticket" binding:"required" example:"9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"`
+}
+
+// VerifyPasswordDTO 定义校验当前密码的请求结构体
+type VerifyPasswordDTO struct {
+	// 用户的当前密码
+	Password string `json:"password" binding:"required" example:"password123"`
+}
+
+// UnifiedLoginData 定义统一登录入口的请求结构体
+// - 提供 password 时按密码登录，否则按验证码登录
+type UnifiedLoginData struct {
+	// 登录标识符：账号或手机号
+	Identifier string `json:"identifier" binding:"required" sanitize:"trim" example:"13800138000"`
+	// 密码 (密码登录时提供)
+	Password string `json:"password" example:"password123"`
+	// 短信验证码 (验证码登录时提供)
+	Code string `json:"code" example:"123456"`
+	// 身份类型 (account / phone / email)，省略时根据标识符格式自动识别
+	Type string `json:"type" binding:"omitempty,oneof=account phone email" example:"phone"`
+}
+
+// ChangeAccountDTO 定义修改当前用户账号（用户名）的请求结构体
+type ChangeAccountDTO struct {
+	// 新账号，需符合账号格式要求
+	NewAccount string `json:"new_account" binding:"required,Account" sanitize:"trim" example:"new_account01"`
+	// 用户的当前密码
+	Password string `json:"password" binding:"required" example:"password123"`
 }
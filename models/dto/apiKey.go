@@ -0,0 +1,13 @@
+package dto
+
+import "github.com/Xushengqwer/user_hub/models/enums"
+
+// CreateAPIKeyDTO 创建 API 密钥请求
+type CreateAPIKeyDTO struct {
+	// 密钥名称，便于用户辨认用途
+	Name string `json:"name" binding:"required,max=64" example:"CI 部署脚本"`
+	// 权限范围，省略时为只读
+	Scopes []enums.APIKeyScope `json:"scopes" binding:"omitempty,max=2,dive,oneof=read write" example:"read"`
+	// 有效天数，省略时不过期
+	ExpiresInDays int `json:"expires_in_days" binding:"omitempty,min=1,max=365" example:"90"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Xushengqwer/user_hub/models/enums"
+)
+
+// AuditLogQueryDTO 定义审计日志查询请求结构体
+// - 用于管理员按条件分页查询审计日志，通过 URL 查询参数传入
+type AuditLogQueryDTO struct {
+	// 操作者（管理员）用户 ID，精确匹配
+	ActorID string `form:"actor_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// 操作目标 ID，精确匹配
+	TargetID string `form:"target_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// 操作类型，精确匹配
+	Action enums.AuditAction `form:"action" example:"user.blacklist"`
+	// 起始时间（含），RFC3339 格式
+	StartTime *time.Time `form:"start_time" time_format:"2006-01-02T15:04:05Z07:00" example:"2023-01-01T00:00:00Z"`
+	// 结束时间（不含），RFC3339 格式
+	EndTime *time.Time `form:"end_time" time_format:"2006-01-02T15:04:05Z07:00" example:"2023-12-31T00:00:00Z"`
+	// 页码，默认 1；携带 cursor 时忽略
+	Page int `form:"page" binding:"omitempty,gte=1" example:"1"`
+	// 每页大小，默认 10
+	PageSize int `form:"page_size" binding:"omitempty,gte=1,lte=100" example:"10"`
+	// 键集分页游标，取自上一页响应的 next_cursor；携带时按游标翻页，不再使用 page
+	Cursor string `form:"cursor" binding:"omitempty,max=256" example:"eyJ0IjoiMjAyMy0wMS0wMVQwMDowMDowMFoiLCJpZCI6MTB9"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+// ConsistencyScanDTO 定义数据一致性巡检请求结构体
+// - 扫描按批次分页进行，每类问题最多检查 max_records 条，避免一次请求扫描全表
+type ConsistencyScanDTO struct {
+	// 是否修复发现的问题：为缺少资料的用户补建资料、删除孤儿资料与孤儿身份
+	Repair bool `json:"repair" example:"false"`
+	// 每批查询的记录数，默认 200
+	BatchSize int `json:"batch_size" binding:"omitempty,gte=1,lte=1000" example:"200"`
+	// 每类问题最多处理的记录数，默认 5000
+	MaxRecords int `json:"max_records" binding:"omitempty,gte=1,lte=100000" example:"5000"`
+}
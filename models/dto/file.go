@@ -0,0 +1,7 @@
+package dto
+
+// PresignFileURLDTO 定义获取私有文件临时下载链接的查询参数
+type PresignFileURLDTO struct {
+	// 链接有效秒数，省略时使用默认有效期，不得超过配置的最长有效期
+	TTL int `form:"ttl" binding:"omitempty,gte=1" example:"600"`
+}
@@ -10,7 +10,7 @@ type CreateIdentityDTO struct {
 	// 身份类型（0=账号密码, 1=小程序, 2=手机号）
 	IdentityType enums.IdentityType `json:"identity_type" binding:"required" example:"0"`
 	// 标识符（如账号、OpenID、手机号）
-	Identifier string `json:"identifier" binding:"required" example:"user123"`
+	Identifier string `json:"identifier" binding:"required" sanitize:"trim" example:"user123"`
 	// 凭证（如密码哈希、UnionID）
 	Credential string `json:"credential" binding:"required" example:"hashed_password"`
 }
@@ -22,9 +22,19 @@ type UpdateIdentityDTO struct {
 	Credential string `json:"credential" binding:"required" example:"new_hashed_password"`
 }
 
+// IdentityListQuery 定义非管理员查询身份列表的分页参数
+// - 省略时返回第一页；每页条数超过配置的上限时按上限返回
+type IdentityListQuery struct {
+	// 页码，从 1 开始
+	Page int `form:"page" binding:"omitempty,gte=1" example:"1"`
+	// 每页条数
+	PageSize int `form:"page_size" binding:"omitempty,gte=1" example:"20"`
+}
+
 // IdentityCredential 定义身份验证所需的最小字段集结构体
 // - 用于返回用户身份凭证的核心信息
 type IdentityCredential struct {
 	UserID     string `gorm:"column:user_id"`    // 用户 ID
 	Credential string `gorm:"column:credential"` // 身份凭证（如密码哈希）
+	Verified   bool   `gorm:"column:verified"`   // 标识符归属是否已验证
 }
@@ -22,3 +22,19 @@ type UpdateUserDTO struct {
 	// 用户状态（0=Active, 1=Blacklisted），可选
 	Status enums.UserStatus `json:"status" binding:"omitempty,oneof=0 1" example:"0"`
 }
+
+// ReactivateUserDTO 定义管理员恢复用户的请求体 (请求体可省略)
+type ReactivateUserDTO struct {
+	// 恢复原因，写入审计日志，可选
+	Reason string `json:"reason" binding:"omitempty,max=255" sanitize:"trim" example:"申诉核实后解除封禁"`
+}
+
+// BatchRoleDTO 定义批量设置用户角色的请求体
+type BatchRoleDTO struct {
+	// 目标用户 ID 列表，单次上限见 batchLimitConfig.limits.set_role (默认 500)
+	UserIDs []string `json:"user_ids" binding:"required,min=1,dive,required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// 目标角色（0=Admin, 1=User, 2=Guest），必填；使用指针以区分未传与 0
+	Role *enums.UserRole `json:"role" binding:"required,oneof=0 1 2" example:"1"`
+	// 预演模式：为 true 时只校验并报告将会发生的变更，不提交任何修改
+	DryRun bool `json:"dry_run" example:"false"`
+}
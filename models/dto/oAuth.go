@@ -5,4 +5,13 @@ type WechatMiniProgramLoginData struct {
 	// Code 微信小程序通过 wx.login() 获取的临时授权码
 	// - 必填，用于后端换取 openid 和 session_key
 	Code string `json:"code" binding:"required"`
+	// Nickname 前端通过微信获取的用户昵称 (可选)
+	// - 仅在首次登录自动注册时作为初始昵称；不符合昵称规则时忽略，不影响登录
+	Nickname string `json:"nickname,omitempty" example:"微信用户"`
+}
+
+// WechatRefreshSessionData 定义刷新微信会话的请求结构体
+type WechatRefreshSessionData struct {
+	// Code 微信小程序通过 wx.login() 重新获取的临时授权码
+	Code string `json:"code" binding:"required" example:"0a3Xxx000abcDE1Fgh2Ijk3LmnO4Xxxq"`
 }
@@ -2,13 +2,25 @@ package dto
 
 // PhoneLoginOrRegisterData 定义手机号登录或注册的数据传输对象
 type PhoneLoginOrRegisterData struct {
-	Phone string `json:"phone" binding:"required"` // 手机号，必填
-	Code  string `json:"code" binding:"required"`  // 验证码，必填
+	Phone string `json:"phone" binding:"required" sanitize:"trim"` // 手机号，必填
+	Code  string `json:"code" binding:"required"`                  // 验证码，必填
 }
 
 //	todo mobile标签还没实现呢
 
+// VerifyPhoneDTO 定义"仅验证手机号"请求的数据传输对象
+// - 用于已登录用户通过验证码验证并绑定手机号，不创建登录会话
+type VerifyPhoneDTO struct {
+	Phone string `json:"phone" binding:"required" sanitize:"trim" example:"13800138000"` // 手机号，必填
+	Code  string `json:"code" binding:"required" example:"123456"`                       // 验证码，必填
+}
+
 // SendCaptchaRequest 定义发送验证码的请求数据传输对象
 type SendCaptchaRequest struct {
-	Phone string `json:"phone" binding:"required,mobile"` // 手机号，必填且需符合格式
+	Phone string `json:"phone" binding:"required,mobile" sanitize:"trim"` // 手机号，必填且需符合格式
+}
+
+// ResendCodeRequest 定义重新发送验证码的请求数据传输对象
+type ResendCodeRequest struct {
+	Phone string `json:"phone" binding:"required,ChinesePhone" sanitize:"trim"` // 手机号，必填且需符合格式
 }
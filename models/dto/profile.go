@@ -7,8 +7,8 @@ import "github.com/Xushengqwer/user_hub/models/enums"
 type CreateProfileDTO struct {
 	// 用户 ID
 	UserID string `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
-	// 昵称 (可选)
-	Nickname string `json:"nickname" binding:"omitempty" example:"小明"`
+	// 昵称 (可选)，允许中文，长度由昵称配置决定
+	Nickname string `json:"nickname" binding:"omitempty,Nickname" sanitize:"trim" example:"小明"`
 	// 头像 URL (可选)
 	AvatarURL string `json:"avatar_url" binding:"omitempty,url" example:"https://example.com/avatar.jpg"`
 	// 性别（0=未知, 1=男, 2=女）(可选)
@@ -22,13 +22,26 @@ type CreateProfileDTO struct {
 // UpdateProfileDTO 定义更新资料请求结构体
 // - 用于用户或管理员更新资料时接收请求数据。
 // - 使用指针类型字段，只有当请求中明确提供了某个字段时，对应的值才不为 nil，服务层据此进行更新。
+// - 同时带有 form 标签，供 multipart/form-data 形式的"资料 + 头像"合并接口复用。
 type UpdateProfileDTO struct {
-	// 昵称 (可选更新)
-	Nickname *string `json:"nickname,omitempty" example:"小明"` // 改为指针 *string
+	// 昵称 (可选更新)，允许中文，长度由昵称配置决定
+	Nickname *string `json:"nickname,omitempty" form:"nickname" binding:"omitempty,Nickname" sanitize:"trim" example:"小明"` // 改为指针 *string
 	// 性别（0=未知, 1=男, 2=女）(可选更新)
-	Gender *enums.Gender `json:"gender,omitempty" example:"1"` // 改为指针 *enums.Gender, 移除了 oneof (Gin 对指针的 oneof 验证可能不直观，可以在服务层验证)
+	Gender *enums.Gender `json:"gender,omitempty" form:"gender" example:"1"` // 改为指针 *enums.Gender, 移除了 oneof (Gin 对指针的 oneof 验证可能不直观，可以在服务层验证)
 	// 省份 (可选更新)
-	Province *string `json:"province,omitempty" example:"广东"` // 改为指针 *string
+	Province *string `json:"province,omitempty" form:"province" example:"广东"` // 改为指针 *string
 	// 城市 (可选更新)
-	City *string `json:"city,omitempty" example:"深圳"` // 改为指针 *string
+	City *string `json:"city,omitempty" form:"city" example:"深圳"` // 改为指针 *string
+}
+
+// AvatarFromURLDTO 定义从远程 URL 设置头像的请求结构体
+type AvatarFromURLDTO struct {
+	// 远程图片地址，仅支持 http/https
+	URL string `json:"url" binding:"required,url" example:"https://example.com/avatar.png"`
+}
+
+// AvatarBase64DTO 定义以 base64 data URI 上传头像的请求结构体
+type AvatarBase64DTO struct {
+	// 图片的 data URI，形如 "data:image/png;base64,iVBORw0KGgo..."
+	Data string `json:"data" binding:"required" example:"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAA..."`
 }
@@ -0,0 +1,32 @@
+package dto
+
+import "github.com/Xushengqwer/user_hub/models/enums"
+
+// StartRecoveryDTO 定义发起账号找回的请求结构体
+// - 用户通过一个已验证的次要身份（手机号验证码或微信登录 code）证明账号归属
+type StartRecoveryDTO struct {
+	// 用于找回的身份类型（1=微信小程序, 2=手机号），账号密码不能作为找回凭证
+	IdentityType enums.IdentityType `json:"identity_type" binding:"oneof=1 2" example:"2"`
+	// 标识符：手机号找回时为手机号；微信找回时留空（由 code 换取 OpenID）
+	Identifier string `json:"identifier" sanitize:"trim" example:"13800138000"`
+	// 验证码：手机号找回时为短信验证码；微信找回时为 wx.login() 获取的 code
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+// RecoveryResetPasswordDTO 定义使用找回凭证重置密码的请求结构体
+type RecoveryResetPasswordDTO struct {
+	// 发起找回时获得的一次性找回凭证
+	RecoveryToken string `json:"recovery_token" binding:"required" example:"9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"`
+	// 新密码
+	NewPassword string `json:"new_password" binding:"required,Password" example:"newPassword123"`
+}
+
+// RecoveryAddIdentityDTO 定义使用找回凭证添加账号密码登录方式的请求结构体
+type RecoveryAddIdentityDTO struct {
+	// 发起找回时获得的一次性找回凭证
+	RecoveryToken string `json:"recovery_token" binding:"required" example:"9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"`
+	// 新的登录账号
+	Account string `json:"account" binding:"required,Account" sanitize:"trim" example:"newAccount"`
+	// 新账号的密码
+	Password string `json:"password" binding:"required,Password" example:"password123"`
+}
@@ -3,3 +3,23 @@ package dto
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"omitempty"`
 }
+
+// IntrospectTokenRequest 令牌检查请求
+type IntrospectTokenRequest struct {
+	// 待检查的令牌
+	Token string `json:"token" binding:"required"`
+	// 令牌类型提示: access_token (默认) 或 refresh_token
+	TokenTypeHint string `json:"token_type_hint" binding:"omitempty,oneof=access_token refresh_token" example:"access_token"`
+}
+
+// BatchIntrospectTokenRequest 批量令牌检查请求
+type BatchIntrospectTokenRequest struct {
+	// 待检查的令牌列表，结果按相同顺序返回，单次上限见 batchLimitConfig.limits.introspect_tokens (默认 100)
+	Tokens []IntrospectTokenRequest `json:"tokens" binding:"required,min=1,dive"`
+}
+
+// ClearBlacklistedJtiRequest 管理员将 JTI 移出黑名单的请求
+type ClearBlacklistedJtiRequest struct {
+	// 移出黑名单的原因，写入审计日志
+	Reason string `json:"reason" binding:"required,max=255" sanitize:"trim" example:"用户退出后无法重新登录，排查确认为误加入黑名单"`
+}
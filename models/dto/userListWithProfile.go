@@ -11,10 +11,24 @@ type UserQueryDTO struct {
 	LikeFilters map[string]string `json:"like_filters" binding:"omitempty" example:"{\"username\": \"test\"}"`
 	// 时间范围条件（如 created_at 在某个范围内）
 	TimeRangeFilters map[string][2]time.Time `json:"time_range_filters" binding:"omitempty" `
-	// 排序字段（如 "created_at DESC"）
-	OrderBy string `json:"order_by" binding:"omitempty" example:"created_at DESC"`
+	// 只返回指定字段 (可选，如 ["status", "nickname"])，user_id 总会返回；为空时返回全部字段
+	// 允许的字段：user_id、role、status、nickname、avatar_url、gender、province、city、created_at、updated_at
+	Fields []string `json:"fields" binding:"omitempty,max=10,dive,required" example:"status,nickname"`
+	// 排序（如 "created_at DESC"），多个排序键以逗号分隔（如 "status ASC, created_at DESC"）；可用字段：created_at、user_id、status
+	OrderBy string `json:"order_by" binding:"omitempty" example:"status ASC, created_at DESC"`
 	// 页码，默认 1
 	Page int `json:"page" binding:"gte=1" example:"1"`
 	// 每页大小，默认 10
 	PageSize int `json:"page_size" binding:"gte=1,lte=100" example:"10"`
 }
+
+// UserSearchDTO 定义管理员组合搜索用户的请求结构体
+// - 通过 URL 查询参数传入，关键字同时匹配用户 ID、手机号、账号/邮箱 (精确) 与昵称 (模糊)
+type UserSearchDTO struct {
+	// 搜索关键字，去除首尾空白后至少 2 个字符
+	Q string `form:"q" binding:"required" sanitize:"trim" example:"13800138000"`
+	// 页码，默认 1
+	Page int `form:"page" binding:"omitempty,gte=1" example:"1"`
+	// 每页大小，默认 10
+	PageSize int `form:"page_size" binding:"omitempty,gte=1,lte=100" example:"10"`
+}
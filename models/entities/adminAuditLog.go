@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"time"
+)
+
+// AdminAuditLog 管理员操作审计日志
+type AdminAuditLog struct {
+	// 自增主键
+	ID uint `gorm:"primary_key;auto_increment"`
+
+	// 执行操作的管理员用户ID
+	// - 与 created_at 组成联合索引，覆盖“按操作者过滤 + 按时间倒序分页”的查询 (InnoDB 二级索引隐含主键 id)
+	ActorID string `gorm:"type:varchar(64);not null;index:idx_audit_actor_created,priority:1"`
+
+	// 操作类型（如 user.create、user.blacklist）
+	Action enums.AuditAction `gorm:"type:varchar(64);not null;index:idx_audit_action_created,priority:1"`
+
+	// 操作目标（通常是被操作用户的 UserID）
+	TargetID string `gorm:"type:varchar(64);not null;index:idx_audit_target_created,priority:1"`
+
+	// 变更内容的 JSON 描述（字段级 from/to）
+	Diff string `gorm:"type:text"`
+
+	// 记录时间
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index;index:idx_audit_actor_created,priority:2;index:idx_audit_action_created,priority:2;index:idx_audit_target_created,priority:2"`
+}
@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// APIKey 用户的 API 密钥，供程序化访问使用
+// - 只保存密钥的 SHA-256 哈希用于查找，明文只在创建时返回一次；Prefix 仅用于在列表中辨认密钥。
+type APIKey struct {
+	// 自增主键
+	ID uint `gorm:"primaryKey;autoIncrement"`
+
+	// 所属用户ID
+	UserID string `gorm:"type:char(36);not null;index"`
+
+	// 用户为密钥设置的名称
+	Name string `gorm:"type:varchar(64);not null"`
+
+	// 密钥明文的前若干字符，用于展示
+	Prefix string `gorm:"type:varchar(16);not null"`
+
+	// 密钥明文的 SHA-256 哈希 (十六进制)
+	KeyHash string `gorm:"type:char(64);not null;uniqueIndex"`
+
+	// 权限范围，多个以逗号分隔
+	Scopes string `gorm:"type:varchar(64);not null"`
+
+	// 最近使用时间，从未使用为空
+	LastUsedAt *time.Time `gorm:"type:timestamp;null"`
+
+	// 过期时间，为空表示不过期
+	ExpiresAt *time.Time `gorm:"type:timestamp;null"`
+
+	// 创建时间
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
+}
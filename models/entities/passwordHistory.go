@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// PasswordHistory 用户历史密码记录，用于阻止修改/重置密码时复用最近使用过的密码
+type PasswordHistory struct {
+	// 自增主键
+	ID uint `gorm:"primary_key;auto_increment"`
+
+	// 所属用户ID
+	UserID string `gorm:"type:varchar(64);not null;index"`
+
+	// 密码的哈希值 (bcrypt 或 argon2id，见 utils.SetPassword)
+	PasswordHash string `gorm:"type:varchar(255);not null"`
+
+	// 记录时间
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index"`
+}
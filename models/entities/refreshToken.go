@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"github.com/Xushengqwer/go-common/models/enums"
+	"time"
+)
+
+// RefreshToken 已签发刷新令牌的白名单记录（启用 JWTConfig.RefreshWhitelist 时使用）
+// 刷新时要求 JTI 存在且未被使用，使令牌轮换不依赖 Redis 黑名单的可用性
+type RefreshToken struct {
+	// 刷新令牌的 JTI
+	JTI string `gorm:"primaryKey;type:varchar(64)"`
+
+	// 所属用户ID
+	UserID string `gorm:"type:varchar(64);not null;index"`
+
+	// 签发平台
+	Platform enums.Platform `gorm:"type:varchar(16);not null"`
+
+	// 令牌过期时间
+	ExpiresAt time.Time `gorm:"type:timestamp;not null;index"`
+
+	// 被使用（轮换或吊销）的时间，为空表示尚未使用
+	ConsumedAt *time.Time `gorm:"type:timestamp;null"`
+
+	// 签发时间
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// SMSDeadLetter 多次重试后仍发送失败的验证码短信记录，供运维排查与人工跟进
+// - 不保存验证码本身
+type SMSDeadLetter struct {
+	// 自增主键
+	ID uint `gorm:"primary_key;auto_increment"`
+
+	// 目标手机号
+	Phone string `gorm:"type:varchar(32);not null;index"`
+
+	// 实际尝试发送的次数 (含首次)
+	Attempts int `gorm:"not null"`
+
+	// 最后一次发送失败的错误信息
+	LastError string `gorm:"type:varchar(1024)"`
+
+	// 记录时间
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// StorageObject 用户上传到对象存储的文件记录，用于统计每个用户的存储用量
+type StorageObject struct {
+	// COS 对象键
+	ObjectKey string `gorm:"primaryKey;type:varchar(512)"`
+
+	// 上传该对象的用户ID
+	UserID string `gorm:"type:varchar(64);not null;index"`
+
+	// 对象大小 (字节)
+	SizeBytes int64 `gorm:"not null"`
+
+	// 创建时间
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
+
+	// 更新时间 (同一对象键被覆盖写入时更新)
+	UpdatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;autoUpdateTime"`
+}
@@ -13,15 +13,27 @@ type UserIdentity struct {
 	// 关联 User 表的 UserID，外键
 	UserID string `gorm:"type:char(36);not null;index;foreignKey:UserID;references:user_id;constraint:OnDelete:CASCADE"`
 
+	// 所属租户，标识符在租户内唯一；未启用租户隔离时均为全局租户 "default"
+	TenantID string `gorm:"type:varchar(64);not null;default:'default';uniqueIndex:idx_tenant_identifier,priority:1"`
+
 	// 身份类型（0=账号密码, 1=小程序, 2=手机号）
 	IdentityType enums.IdentityType `gorm:"type:int;not null"`
 
-	// 标识符，如账号、OpenID、手机号，具有唯一性索引
-	Identifier string `gorm:"type:varchar(255);not null;uniqueIndex:idx_type_identifier"`
+	// 标识符，如账号、OpenID、手机号，与租户组成唯一性索引
+	Identifier string `gorm:"type:varchar(255);not null;uniqueIndex:idx_tenant_identifier,priority:2"`
 
 	// 凭证，如密码（哈希）、UnionID
 	Credential string `gorm:"type:varchar(255)"`
 
+	// 标识符归属是否已验证（如手机号通过短信验证码、OpenID 由微信接口返回）
+	Verified bool `gorm:"not null;default:false"`
+
+	// 是否为主登录方式（注册时创建的身份）
+	IsPrimary bool `gorm:"not null;default:false"`
+
+	// 最近一次使用该身份登录的时间，从未使用过则为 NULL
+	LastUsedAt *time.Time `gorm:"type:timestamp;null"`
+
 	// 创建时间，默认当前时间戳
 	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
 
@@ -2,6 +2,7 @@ package entities
 
 import (
 	"github.com/Xushengqwer/user_hub/models/enums"
+	"gorm.io/gorm"
 	"time"
 )
 
@@ -33,4 +34,7 @@ type UserProfile struct {
 
 	// 更新时间，默认当前时间戳，自动更新
 	UpdatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;autoUpdateTime"`
+
+	// 软删除时间戳，与用户一同软删除，保留头像地址供之后硬删除时清理存储对象
+	DeletedAt gorm.DeletedAt `gorm:"type:timestamp;column:deleted_at"`
 }
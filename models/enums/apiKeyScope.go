@@ -0,0 +1,9 @@
+package enums
+
+// APIKeyScope API 密钥的权限范围
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead  APIKeyScope = "read"  // 只读：仅允许 GET / HEAD / OPTIONS 请求
+	APIKeyScopeWrite APIKeyScope = "write" // 读写：允许所有请求方法
+)
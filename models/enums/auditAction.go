@@ -0,0 +1,23 @@
+package enums
+
+// AuditAction 审计操作类型（管理员操作及账号找回等安全敏感的自助操作）
+type AuditAction string
+
+const (
+	AuditActionCreateUser    AuditAction = "user.create"     // 创建用户
+	AuditActionUpdateUser    AuditAction = "user.update"     // 更新用户角色/状态
+	AuditActionBlacklistUser AuditAction = "user.blacklist"  // 拉黑用户
+	AuditActionDeleteUser    AuditAction = "user.delete"     // 删除用户
+	AuditActionBatchRole     AuditAction = "user.batch_role" // 批量设置用户角色 (每个用户一条记录)
+	AuditActionReactivate    AuditAction = "user.reactivate" // 管理员恢复已拉黑或已停用的用户
+
+	AuditActionClearBlacklistedJti AuditAction = "token.blacklist_clear" // 管理员将 JTI 移出令牌黑名单
+
+	AuditActionConsistencyRepair AuditAction = "data.consistency_repair" // 数据一致性巡检中的修复操作
+
+	AuditActionRecoveryStart         AuditAction = "recovery.start"          // 通过已验证身份发起账号找回（含失败的尝试）
+	AuditActionRecoveryResetPassword AuditAction = "recovery.reset_password" // 使用找回凭证重置密码
+	AuditActionRecoveryAddIdentity   AuditAction = "recovery.add_identity"   // 使用找回凭证添加新的登录方式
+
+	AuditActionChangeAccount AuditAction = "account.change_account" // 用户修改自己的账号（用户名）
+)
@@ -0,0 +1,11 @@
+package enums
+
+// TokenRejectReason 令牌被拒绝的粗粒度原因，供网关/客户端决定是刷新令牌还是重新登录
+// - 只区分三类，具体原因 (签名、发行者、受众等) 仅记录在服务端日志中
+type TokenRejectReason string
+
+const (
+	TokenRejectExpired TokenRejectReason = "expired" // 令牌已过期，可尝试使用刷新令牌续期
+	TokenRejectInvalid TokenRejectReason = "invalid" // 令牌无效 (格式、签名、发行者、受众等校验失败)，需要重新登录
+	TokenRejectRevoked TokenRejectReason = "revoked" // 令牌已被吊销 (如已退出登录或已轮换)，需要重新登录
+)
@@ -0,0 +1,17 @@
+package enums
+
+import commonenums "github.com/Xushengqwer/go-common/models/enums"
+
+// 本服务在公共 UserStatus 枚举 (0=活跃, 1=拉黑) 之外扩展的用户状态
+const (
+	// StatusDeactivated 用户自行停用账号：数据完整保留，停用期间无法登录或刷新令牌，可通过登录挑战重新激活
+	StatusDeactivated commonenums.UserStatus = 2
+)
+
+// UserStatusName 返回用户状态的可读名称，覆盖本服务扩展的状态（公共枚举的 String 对其返回 "unknown"）
+func UserStatusName(s commonenums.UserStatus) string {
+	if s == StatusDeactivated {
+		return "deactivated"
+	}
+	return s.String()
+}
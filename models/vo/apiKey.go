@@ -0,0 +1,24 @@
+package vo
+
+import (
+	"time"
+
+	"github.com/Xushengqwer/user_hub/models/enums"
+)
+
+// APIKeyVO API 密钥的元数据，不含密钥明文
+type APIKeyVO struct {
+	ID         uint                `json:"id" example:"1"`
+	Name       string              `json:"name" example:"CI 部署脚本"`
+	Prefix     string              `json:"prefix" example:"uhk_Ab12Cd34"` // 密钥明文的前若干字符，用于辨认
+	Scopes     []enums.APIKeyScope `json:"scopes" example:"read"`
+	LastUsedAt *time.Time          `json:"last_used_at" swaggertype:"string"` // 最近使用时间，从未使用为 null
+	ExpiresAt  *time.Time          `json:"expires_at" swaggertype:"string"`   // 过期时间，不过期为 null
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// APIKeyCreatedVO 创建 API 密钥的结果，密钥明文只在此处返回一次
+type APIKeyCreatedVO struct {
+	APIKeyVO
+	Key string `json:"key" example:"uhk_Ab12Cd34..."` // 密钥明文，请立即妥善保存，之后无法再次查看
+}
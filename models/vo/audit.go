@@ -0,0 +1,28 @@
+package vo
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Xushengqwer/user_hub/models/enums"
+)
+
+// AuditLogVO 定义审计日志响应结构体
+type AuditLogVO struct {
+	// 日志 ID
+	ID uint `json:"id" example:"1"`
+	// 操作者（管理员）用户 ID
+	ActorID string `json:"actor_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// 操作类型
+	Action enums.AuditAction `json:"action" example:"user.update"`
+	// 操作目标 ID
+	TargetID string `json:"target_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// 字段级变更内容
+	Diff json.RawMessage `json:"diff" swaggertype:"object"`
+	// 记录时间
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// AuditLogListResponse 定义审计日志分页查询响应结构体
+// - next_cursor 作为 cursor 参数回传即可继续翻页；按页码翻页时同时返回 page / page_size
+type AuditLogListResponse = PageVO[*AuditLogVO]
@@ -1,15 +1,95 @@
 package vo
 
+import (
+	"time"
+
+	commonEnums "github.com/Xushengqwer/go-common/models/enums"
+	"github.com/Xushengqwer/user_hub/models/enums"
+)
+
 type Userinfo struct {
 	UserID string `json:"userID"`
 }
 
 type TokenPair struct {
-	AccessToken  string `json:"access_token"`  // 新认证令牌
-	RefreshToken string `json:"refresh_token"` // 新刷新令牌（可选）
+	AccessToken  string `json:"access_token"`            // 新认证令牌
+	RefreshToken string `json:"refresh_token,omitempty"` // 新刷新令牌（Web 平台通过 Cookie 下发，响应体中不出现该字段）
 }
 
 type LoginResponse struct {
 	User  Userinfo  `json:"userManage"` // 用户信息
 	Token TokenPair `json:"token"`      // Token 对
 }
+
+// 登录失败原因码：各登录接口的错误响应在 data.reason 中返回，取值稳定，客户端据此决定下一步
+// (重新输入、重新获取验证码、走重新激活流程等)，而不必解析面向用户的错误文案。
+const (
+	LoginReasonInvalidInput        = "invalid_input"         // 请求参数或平台类型无效，以及其他无法归类的业务错误
+	LoginReasonInvalidCredentials  = "invalid_credentials"   // 账号不存在或密码错误 (不作区分)
+	LoginReasonInvalidCaptcha      = "invalid_captcha"       // 短信验证码错误或已过期
+	LoginReasonInvalidWechatCode   = "invalid_wechat_code"   // 微信登录 code 无效或已过期，需重新调用 wx.login
+	LoginReasonAccountBlacklisted  = "account_blacklisted"   // 账号已被封禁
+	LoginReasonAccountUnavailable  = "account_unavailable"   // 账号处于其他不允许登录的状态
+	LoginReasonAccountDeactivated  = "account_deactivated"   // 账号已停用，data 中附带重新激活凭证
+	LoginReasonIdentityNotVerified = "identity_not_verified" // 登录策略要求身份已验证，data 中附带验证指引
+	LoginReasonServiceUnavailable  = "service_unavailable"   // 依赖的服务暂时不可用，可稍后重试
+	LoginReasonRegistrationClosed  = "registration_closed"   // 注册暂未开放，新用户无法自动注册
+	LoginReasonInternalError       = "internal_error"        // 系统内部错误
+)
+
+// LoginFailureVO 登录失败时随错误响应返回的结构化原因
+type LoginFailureVO struct {
+	Reason   string           `json:"reason"`             // 失败原因码，取值见 LoginReason* 常量
+	Upstream *UpstreamErrorVO `json:"upstream,omitempty"` // 第三方服务失败的脱敏摘要，仅在开启第三方错误详情且调用者有权查看时返回
+}
+
+// ReactivationChallengeVO 登录已停用账号时返回的重新激活指引
+type ReactivationChallengeVO struct {
+	Reason             string `json:"reason"`              // 登录失败原因码，固定为 account_deactivated
+	ReactivationTicket string `json:"reactivation_ticket"` // 一次性重新激活凭证
+	ExpiresIn          int64  `json:"expires_in"`          // 凭证有效期 (秒)
+	ReactivatePath     string `json:"reactivate_path"`     // 重新激活接口路径
+}
+
+// IdentityVerificationRequiredVO 登录策略要求身份已验证、而本次登录所用身份未验证时返回的指引
+type IdentityVerificationRequiredVO struct {
+	Reason       string             `json:"reason"`                // 登录失败原因码，固定为 identity_not_verified
+	IdentityType enums.IdentityType `json:"identity_type"`         // 本次登录使用的身份类型
+	ResendPath   string             `json:"resend_path,omitempty"` // 重新获取验证码的接口路径，无自助验证途径时为空
+}
+
+// RecoveryTokenVO 发起账号找回成功后返回的一次性找回凭证
+// - 该凭证不是访问令牌，不能用于访问任何需要登录的接口，只能用于 AllowedActions 中列出的操作
+type RecoveryTokenVO struct {
+	RecoveryToken  string   `json:"recovery_token"`  // 一次性找回凭证
+	ExpiresIn      int64    `json:"expires_in"`      // 凭证有效期 (秒)
+	AllowedActions []string `json:"allowed_actions"` // 凭证可用于的操作: reset_password / add_identity
+}
+
+// TokenIntrospectionVO 令牌检查结果
+// - 令牌不可用时只给出粗粒度原因，具体的校验失败原因不对外暴露
+type TokenIntrospectionVO struct {
+	Active    bool                    `json:"active"`                                    // 令牌当前是否可用
+	Reason    enums.TokenRejectReason `json:"reason,omitempty" example:"expired"`        // 不可用原因: expired / invalid / revoked
+	UserID    string                  `json:"user_id,omitempty"`                         // 令牌所属用户ID (仅可用时返回)
+	Platform  string                  `json:"platform,omitempty" example:"web"`          // 令牌签发平台 (仅可用时返回)
+	ExpiresAt *time.Time              `json:"expires_at,omitempty" swaggertype:"string"` // 令牌过期时间 (仅可用时返回)
+}
+
+// TokenIntrospectionBatchVO 批量令牌检查结果，Items 与请求中的令牌顺序一一对应
+type TokenIntrospectionBatchVO struct {
+	Items []*TokenIntrospectionVO `json:"items"`
+}
+
+// TokenClaimsVO 当前访问令牌中携带的声明，完全由令牌解析得到，不查询数据库
+//   - 角色与状态是签发时的快照，可能落后于数据库中的最新值
+//   - 不包含权限范围 (scopes)：访问令牌格式中没有 scope 声明，令牌持有者拥有其角色的全部权限；
+//     权限范围只存在于 API 密钥 (read / write)，不会出现在访问令牌中
+type TokenClaimsVO struct {
+	UserID    string                 `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Role      commonEnums.UserRole   `json:"role" example:"1"`
+	Status    commonEnums.UserStatus `json:"status" example:"0"`
+	Platform  string                 `json:"platform" example:"web"`
+	IssuedAt  *time.Time             `json:"issued_at,omitempty" swaggertype:"string"`
+	ExpiresAt *time.Time             `json:"expires_at,omitempty" swaggertype:"string"`
+}
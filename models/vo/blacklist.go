@@ -0,0 +1,42 @@
+package vo
+
+import "time"
+
+// BlacklistStatsVO 定义令牌黑名单统计信息响应结构体
+// - 计数为当前实例自启动以来的累计值，多实例部署时各实例独立统计
+type BlacklistStatsVO struct {
+	// 成功加入黑名单的 JTI 数量
+	Added int64 `json:"added" example:"120"`
+	// 黑名单检查命中次数
+	Hits int64 `json:"hits" example:"15"`
+	// 黑名单检查未命中次数
+	Misses int64 `json:"misses" example:"9800"`
+	// 命中率 (hits / (hits + misses))，尚无检查时为 0
+	HitRate float64 `json:"hit_rate" example:"0.0015"`
+	// 黑名单中 JTI 数量的估算值，尚未估算时为 0
+	SizeEstimate int64 `json:"size_estimate" example:"3500"`
+	// 估算值是否为精确值（扫描预算内遍历完了整个键空间）
+	SizeExact bool `json:"size_exact" example:"true"`
+	// 本次估算扫描过的键数量（近似值）
+	ScannedKeys int64 `json:"scanned_keys" example:"5000"`
+	// 估算完成时间，尚未估算时为空
+	EstimatedAt *time.Time `json:"estimated_at,omitempty" example:"2023-01-01T00:00:00Z"`
+}
+
+// BlacklistEntryVO 定义单个 JTI 黑名单状态的响应结构体
+type BlacklistEntryVO struct {
+	// 查询的 JWT ID
+	JTI string `json:"jti" example:"9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"`
+	// 是否在黑名单中
+	Blacklisted bool `json:"blacklisted" example:"true"`
+	// 在黑名单中的剩余存活秒数，不在黑名单中时为 0
+	TTLSeconds int64 `json:"ttl_seconds" example:"3540"`
+}
+
+// BlacklistClearVO 定义移除黑名单 JTI 的响应结构体
+type BlacklistClearVO struct {
+	// 被移除的 JWT ID
+	JTI string `json:"jti" example:"9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d"`
+	// 是否确实移除了记录；JTI 本就不在黑名单中时为 false
+	Removed bool `json:"removed" example:"true"`
+}
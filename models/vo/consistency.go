@@ -0,0 +1,25 @@
+package vo
+
+// ConsistencyIssueVO 描述一类数据不一致问题的扫描结果
+type ConsistencyIssueVO struct {
+	// 发现的问题记录 ID (用户 ID，或资料/身份主键)
+	IDs []string `json:"ids"`
+	// 发现的问题数量
+	Found int `json:"found" example:"3"`
+	// 已修复的数量 (未开启修复时为 0)
+	Repaired int `json:"repaired" example:"0"`
+	// 是否因达到 max_records 上限而提前结束扫描，为 true 时可再次调用继续处理
+	Truncated bool `json:"truncated" example:"false"`
+}
+
+// ConsistencyReportVO 定义数据一致性巡检报告
+type ConsistencyReportVO struct {
+	// 本次是否执行了修复
+	Repair bool `json:"repair" example:"false"`
+	// 没有资料记录的有效用户
+	UsersWithoutProfile ConsistencyIssueVO `json:"users_without_profile"`
+	// 关联用户不存在或已删除的资料
+	OrphanProfiles ConsistencyIssueVO `json:"orphan_profiles"`
+	// 关联用户不存在或已删除的身份
+	OrphanIdentities ConsistencyIssueVO `json:"orphan_identities"`
+}
@@ -0,0 +1,15 @@
+package vo
+
+// FeatureFlagsVO 定义下发给前端的功能开关响应结构体
+type FeatureFlagsVO struct {
+	// 功能开关集合，key 为功能名称，value 为是否开启（已合并调用者角色的覆盖配置）
+	Flags map[string]bool `json:"flags"`
+}
+
+// LocalesVO 定义下发给前端的语言支持信息
+type LocalesVO struct {
+	// 服务端支持的语言标签，Accept-Language 只会在其中匹配
+	Supported []string `json:"supported" example:"zh-CN"`
+	// 无法匹配时使用的默认语言
+	Default string `json:"default" example:"zh-CN"`
+}
@@ -0,0 +1,21 @@
+package vo
+
+import "time"
+
+// PresignedURLVO 定义私有文件临时下载链接的响应结构体
+type PresignedURLVO struct {
+	// 带签名的临时下载地址
+	URL string `json:"url" example:"https://bucket-1250000000.cos.ap-guangzhou.myqcloud.com/documents/uid/a.pdf?q-sign-algorithm=sha1&..."`
+	// 链接过期时间
+	ExpiresAt time.Time `json:"expires_at" example:"2024-01-01T00:15:00Z"`
+}
+
+// StorageUsageVO 定义用户存储用量的响应结构体
+type StorageUsageVO struct {
+	// 已使用的字节数
+	UsedBytes int64 `json:"used_bytes" example:"1048576"`
+	// 配额字节数
+	QuotaBytes int64 `json:"quota_bytes" example:"104857600"`
+	// 剩余可用字节数
+	RemainingBytes int64 `json:"remaining_bytes" example:"103809024"`
+}
@@ -0,0 +1,29 @@
+package vo
+
+// 依赖健康状态取值
+const (
+	DependencyStatusUp            = "up"             // 探测成功
+	DependencyStatusDown          = "down"           // 探测失败或单项超时
+	DependencyStatusTimeout       = "timeout"        // 整体时限内未完成探测
+	DependencyStatusNotConfigured = "not_configured" // 客户端未初始化 (如短信配置缺失)
+
+	HealthStatusDegraded = "degraded" // 整体状态: 至少一个已配置的依赖不可用
+)
+
+// DependencyHealthVO 描述单个外部依赖的探测结果
+type DependencyHealthVO struct {
+	// 探测状态: up / down / timeout / not_configured
+	Status string `json:"status" example:"up"`
+	// 探测耗时 (毫秒)
+	LatencyMs int64 `json:"latency_ms" example:"12"`
+	// 失败原因，探测成功时为空
+	Error string `json:"error,omitempty" example:"dial tcp: i/o timeout"`
+}
+
+// DeepHealthVO 定义深度健康检查的汇总结果
+type DeepHealthVO struct {
+	// 整体状态: 所有已配置的依赖都为 up 时为 up，否则为 degraded
+	Status string `json:"status" example:"up"`
+	// 各依赖的探测结果，键为依赖名称 (mysql / redis / cos / sms / wechat)
+	Dependencies map[string]DependencyHealthVO `json:"dependencies"`
+}
@@ -22,10 +22,32 @@ type IdentityVO struct {
 	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
 }
 
-type IdentityList struct {
-	Items []*IdentityVO `json:"items"`
-}
+// IdentityList 定义身份列表的响应结构体 (不分页，total 为身份数量)
+type IdentityList = PageVO[*IdentityVO]
 
 type IdentityTypeList struct {
 	Items []enums.IdentityType `json:"items"`
 }
+
+// LoginMethodVO 定义登录方式响应结构体
+// - 用于账号安全页展示用户已绑定的登录方式，标识符已脱敏
+type LoginMethodVO struct {
+	// 身份 ID
+	IdentityID uint `json:"identity_id" example:"1"`
+	// 身份类型（0=账号密码, 1=小程序, 2=手机号）
+	IdentityType enums.IdentityType `json:"identity_type" example:"2"`
+	// 脱敏后的标识符
+	MaskedIdentifier string `json:"masked_identifier" example:"138****0000"`
+	// 标识符归属是否已验证
+	Verified bool `json:"verified" example:"true"`
+	// 是否为主登录方式
+	Primary bool `json:"primary" example:"true"`
+	// 最近一次使用该方式登录的时间，从未使用过则为 null
+	LastUsedAt *time.Time `json:"last_used_at" example:"2023-01-01T00:00:00Z"`
+	// 绑定时间
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+type LoginMethodList struct {
+	Items []*LoginMethodVO `json:"items"`
+}
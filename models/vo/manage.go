@@ -3,6 +3,8 @@ package vo
 import (
 	"github.com/Xushengqwer/go-common/models/enums"
 	"time"
+
+	projectEnums "github.com/Xushengqwer/user_hub/models/enums"
 )
 
 // UserVO 定义用户响应结构体
@@ -18,4 +20,66 @@ type UserVO struct {
 	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
 	// 更新时间
 	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	// 软删除时间，仅在管理员以 include_deleted 查询到已删除用户时返回
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2023-06-01T00:00:00Z"`
+}
+
+// 管理操作 (批量或单个) 中单个用户的处理结果
+const (
+	BatchItemUpdated   = "updated"   // 已更新；预演 (dry_run) 时表示将被更新
+	BatchItemUnchanged = "unchanged" // 已是目标值，无需更新
+	BatchItemNotFound  = "not_found" // 用户不存在或已删除
+	BatchItemFailed    = "failed"    // 所在批次写入失败
+)
+
+// BatchItemResultVO 批量操作中单个用户的处理结果
+type BatchItemResultVO struct {
+	// 用户 ID
+	UserID string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// 处理结果: updated / unchanged / not_found / failed
+	Result string `json:"result" example:"updated"`
+}
+
+// BatchRoleResultVO 批量设置用户角色的结果报告
+type BatchRoleResultVO struct {
+	// 目标角色
+	Role enums.UserRole `json:"role" example:"1"`
+	// 各结果的数量
+	Updated   int `json:"updated" example:"10"`
+	Unchanged int `json:"unchanged" example:"2"`
+	NotFound  int `json:"not_found" example:"1"`
+	Failed    int `json:"failed" example:"0"`
+	// 每个用户的处理结果，顺序与请求一致 (重复的 ID 只保留一次)
+	Results []BatchItemResultVO `json:"results"`
+	// 是否为预演：为 true 时结果表示"将会发生"的变更，未提交任何修改
+	DryRun bool `json:"dry_run" example:"false"`
+}
+
+// UserActionResultVO 单个用户的破坏性管理操作 (删除、拉黑) 的结果报告
+type UserActionResultVO struct {
+	// 用户 ID
+	UserID string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// 处理结果: updated / unchanged / not_found
+	Result string `json:"result" example:"updated"`
+	// 是否为预演：为 true 时结果表示"将会发生"的变更，未提交任何修改
+	DryRun bool `json:"dry_run" example:"false"`
+	// 删除方式 (soft / hard)，仅删除用户时返回
+	DeleteMode string `json:"delete_mode,omitempty" example:"soft"`
+}
+
+// AdminUserDetailVO 管理员查看单个用户时的聚合视图
+// - 一次请求返回核心信息、资料、脱敏后的登录方式、有效会话数与最近登录，管理后台无需分别调用多个接口。
+type AdminUserDetailVO struct {
+	// 核心用户信息
+	User *UserVO `json:"user"`
+	// 用户资料，资料记录不存在时为 null
+	Profile *ProfileVO `json:"profile"`
+	// 已绑定的登录方式，标识符已脱敏
+	Identities []*LoginMethodVO `json:"identities"`
+	// 当前有效的登录会话数；未启用刷新令牌白名单时无法统计，为 null
+	ActiveSessions *int64 `json:"active_sessions" example:"2"`
+	// 最近一次登录的时间，从未登录过则为 null
+	LastLoginAt *time.Time `json:"last_login_at" example:"2023-01-01T00:00:00Z"`
+	// 最近一次登录使用的方式，从未登录过则为 null
+	LastLoginMethod *projectEnums.IdentityType `json:"last_login_method" example:"2"`
 }
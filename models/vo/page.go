@@ -0,0 +1,25 @@
+package vo
+
+// PageVO 列表接口统一的分页信封，客户端只需按同一结构解析各列表接口
+// - 页码分页的接口返回 page / page_size；键集 (游标) 分页的接口返回 next_cursor，为空表示没有更多数据。
+// - 不分页的列表只返回 items 与 total (total 即 items 的数量)。
+type PageVO[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total" example:"100"`
+	Page       int    `json:"page,omitempty" example:"1"`
+	PageSize   int    `json:"page_size,omitempty" example:"10"`
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMy0wMS0wMVQwMDowMDowMFoiLCJpZCI6MTB9"`
+}
+
+// NewPageVO 构造页码分页的信封；items 为 nil 时输出空数组而不是 null
+func NewPageVO[T any](items []T, total int64, page, pageSize int) PageVO[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return PageVO[T]{Items: items, Total: total, Page: page, PageSize: pageSize}
+}
+
+// NewListVO 构造不分页列表的信封，total 为列表长度
+func NewListVO[T any](items []T) PageVO[T] {
+	return NewPageVO(items, int64(len(items)), 0, 0)
+}
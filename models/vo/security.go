@@ -0,0 +1,54 @@
+package vo
+
+import (
+	"time"
+
+	commonEnums "github.com/Xushengqwer/go-common/models/enums"
+	projectEnums "github.com/Xushengqwer/user_hub/models/enums"
+)
+
+// SecurityEventVO 账号安全概览中的一条安全事件 (来自针对该用户的审计日志)
+type SecurityEventVO struct {
+	// 事件类型，与审计日志的操作类型一致
+	Action projectEnums.AuditAction `json:"action" example:"user.update"`
+	// 发生时间
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// SecurityOverviewVO 账号安全页所需信息的聚合视图
+// - 一次请求返回绑定的登录方式、有效会话数、最近登录和近期安全事件，避免安全页多次往返。
+type SecurityOverviewVO struct {
+	// 用户 ID
+	UserID string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// 账号状态
+	Status commonEnums.UserStatus `json:"status" example:"0"`
+	// 已绑定的登录方式，标识符已脱敏
+	LoginMethods []*LoginMethodVO `json:"login_methods"`
+	// 当前有效的登录会话数；未启用刷新令牌白名单时无法统计，为 null
+	ActiveSessions *int64 `json:"active_sessions" example:"2"`
+	// 最近一次登录的时间，从未登录过则为 null
+	LastLoginAt *time.Time `json:"last_login_at" example:"2023-01-01T00:00:00Z"`
+	// 最近一次登录使用的方式，从未登录过则为 null
+	LastLoginMethod *projectEnums.IdentityType `json:"last_login_method" example:"2"`
+	// 近期安全事件，按时间倒序
+	RecentEvents []*SecurityEventVO `json:"recent_events"`
+	// 概览生成时间 (命中缓存时为缓存写入的时间)
+	GeneratedAt time.Time `json:"generated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// PlatformSessionsVO 某个平台上的有效登录会话数
+type PlatformSessionsVO struct {
+	// 平台
+	Platform commonEnums.Platform `json:"platform" example:"web"`
+	// 该平台上的有效会话数
+	Sessions int64 `json:"sessions" example:"1"`
+}
+
+// ActivePlatformsVO 用户当前已登录的平台
+// - 用于“你已在网页端和 App 上登录”一类的提示，按平台固定顺序 (web、app、wechat) 返回。
+type ActivePlatformsVO struct {
+	// 至少有一个有效会话的平台；未启用刷新令牌白名单时无法统计，为 null
+	Platforms []*PlatformSessionsVO `json:"platforms"`
+	// 全部平台的有效会话总数；未启用刷新令牌白名单时为 null
+	TotalSessions *int64 `json:"total_sessions" example:"2"`
+}
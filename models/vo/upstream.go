@@ -0,0 +1,20 @@
+package vo
+
+// UpstreamErrorVO 第三方服务调用失败的脱敏摘要，仅在配置开启且调用者为管理员 (或开启调试开关) 时返回
+type UpstreamErrorVO struct {
+	// 第三方服务: cos / sms / wechat
+	Service string `json:"service" example:"cos"`
+	// 错误类别: timeout / canceled / network / http_status / api_error / unknown
+	Category string `json:"category" example:"http_status"`
+	// 上游 HTTP 状态码，无法获取时省略
+	StatusCode int `json:"status_code,omitempty" example:"403"`
+	// 上游错误码 (如 COS 的 AccessDenied、微信 errcode)，无法获取时省略
+	Code string `json:"code,omitempty" example:"AccessDenied"`
+	// 上游请求 ID，便于向服务商查询
+	RequestID string `json:"request_id,omitempty" example:"NjE0YjQ2ZjZfOTBiMjU5MDlfMjA0NV8x"`
+}
+
+// UpstreamFailureVO 第三方服务失败响应的 data 部分，message 仍为面向用户的通用文案
+type UpstreamFailureVO struct {
+	Upstream *UpstreamErrorVO `json:"upstream,omitempty"`
+}
@@ -31,7 +31,39 @@ type UserWithProfileVO struct {
 	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
 }
 
+// Project 返回只包含指定字段 (JSON 字段名) 的结果，user_id 总会包含；未知字段被忽略
+// - 用于用户列表查询的字段投影，未被选择的字段不出现在响应中，而不是以零值输出。
+func (v *UserWithProfileVO) Project(fields []string) map[string]any {
+	projected := make(map[string]any, len(fields)+1)
+	projected["user_id"] = v.UserID
+	for _, field := range fields {
+		switch field {
+		case "role":
+			projected[field] = v.Role
+		case "status":
+			projected[field] = v.Status
+		case "nickname":
+			projected[field] = v.Nickname
+		case "avatar_url":
+			projected[field] = v.AvatarURL
+		case "gender":
+			projected[field] = v.Gender
+		case "province":
+			projected[field] = v.Province
+		case "city":
+			projected[field] = v.City
+		case "created_at":
+			projected[field] = v.CreatedAt
+		case "updated_at":
+			projected[field] = v.UpdatedAt
+		}
+	}
+	return projected
+}
+
+// UserListResponse 定义用户列表 (分页查询与搜索) 的响应结构体
 type UserListResponse struct {
-	Users []*UserWithProfileVO `json:"users"`
-	Total int64                `json:"total"`
+	PageVO[*UserWithProfileVO]
+	// 已废弃：与 items 相同，仅为兼容旧客户端保留；listEnvelopeConfig.omit_legacy_fields 为 true 时省略
+	Users []*UserWithProfileVO `json:"users,omitempty"`
 }
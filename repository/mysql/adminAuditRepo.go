@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+
+	"gorm.io/gorm"
+)
+
+// AdminAuditRepository 定义了管理员操作审计日志的存储接口。
+type AdminAuditRepository interface {
+	// CreateAuditLog 写入一条审计日志。
+	// - 使用传入的 db 执行，调用方可传入事务对象，使审计记录与业务变更同时提交或回滚。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	CreateAuditLog(ctx context.Context, db *gorm.DB, log *entities.AdminAuditLog) error
+
+	// ListAuditLogs 按条件分页查询审计日志，按 (记录时间, ID) 倒序返回。
+	// - after 非空时使用键集分页：只返回排在 after 之后 (即 (created_at, id) 严格小于它) 的记录，忽略 query.Page。
+	// - after 为空时按 query.Page 偏移分页。
+	// - limit 为本次最多返回的条数，调用方可多取一条用于判断是否还有下一页。
+	// - 返回当前页的日志列表和符合过滤条件的总记录数 (与分页方式无关)。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	ListAuditLogs(ctx context.Context, query *dto.AuditLogQueryDTO, after *entities.AdminAuditLog, limit int) ([]*entities.AdminAuditLog, int64, error)
+}
+
+// adminAuditRepository 是 AdminAuditRepository 接口基于 GORM 的实现。
+type adminAuditRepository struct {
+	db *gorm.DB // db 是 GORM 数据库连接实例
+}
+
+// NewAdminAuditRepository 创建一个新的 adminAuditRepository 实例。
+// - 依赖注入 GORM 数据库连接。
+func NewAdminAuditRepository(db *gorm.DB) AdminAuditRepository {
+	return &adminAuditRepository{db: db}
+}
+
+// CreateAuditLog 实现接口方法，写入审计日志。
+func (r *adminAuditRepository) CreateAuditLog(ctx context.Context, db *gorm.DB, log *entities.AdminAuditLog) error {
+	if err := db.WithContext(ctx).Create(log).Error; err != nil {
+		return fmt.Errorf("adminAuditRepo.CreateAuditLog: 写入审计日志失败 (操作: %s, 目标: %s): %w", log.Action, log.TargetID, err)
+	}
+	return nil
+}
+
+// ListAuditLogs 实现接口方法，按条件分页查询审计日志。
+func (r *adminAuditRepository) ListAuditLogs(ctx context.Context, query *dto.AuditLogQueryDTO, after *entities.AdminAuditLog, limit int) ([]*entities.AdminAuditLog, int64, error) {
+	db := r.db.WithContext(ctx).Model(&entities.AdminAuditLog{})
+
+	// 1. 组装过滤条件
+	if query.ActorID != "" {
+		db = db.Where("actor_id = ?", query.ActorID)
+	}
+	if query.TargetID != "" {
+		db = db.Where("target_id = ?", query.TargetID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.StartTime != nil {
+		db = db.Where("created_at >= ?", *query.StartTime)
+	}
+	if query.EndTime != nil {
+		db = db.Where("created_at < ?", *query.EndTime)
+	}
+
+	// 2. 统计总数
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("adminAuditRepo.ListAuditLogs: 统计审计日志总数失败: %w", err)
+	}
+
+	// 3. 分页查询：有游标时按键集定位，否则按页码偏移
+	if limit <= 0 {
+		limit = 10
+	}
+	pageDB := db.Order("created_at DESC, id DESC").Limit(limit)
+	if after != nil {
+		pageDB = pageDB.Where("(created_at < ? OR (created_at = ? AND id < ?))", after.CreatedAt, after.CreatedAt, after.ID)
+	} else if query.Page > 1 {
+		pageSize := query.PageSize
+		if pageSize <= 0 {
+			pageSize = 10
+		}
+		pageDB = pageDB.Offset((query.Page - 1) * pageSize)
+	}
+
+	var logs []*entities.AdminAuditLog
+	if err := pageDB.Find(&logs).Error; err != nil {
+		return nil, 0, fmt.Errorf("adminAuditRepo.ListAuditLogs: 查询审计日志失败: %w", err)
+	}
+	return logs, total, nil
+}
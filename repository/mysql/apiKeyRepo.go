@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+)
+
+// APIKeyRepository 定义了用户 API 密钥的存储接口。
+type APIKeyRepository interface {
+	// CreateAPIKey 写入一条 API 密钥记录。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	CreateAPIKey(ctx context.Context, db *gorm.DB, key *entities.APIKey) error
+
+	// ListByUserID 按创建时间倒序返回指定用户的全部 API 密钥。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	ListByUserID(ctx context.Context, userID string) ([]*entities.APIKey, error)
+
+	// CountByUserID 统计指定用户的 API 密钥数量。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	CountByUserID(ctx context.Context, userID string) (int64, error)
+
+	// GetByKeyHash 根据密钥哈希查找 API 密钥。
+	// - 未找到时返回 commonerrors.ErrRepoNotFound。
+	GetByKeyHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+
+	// DeleteAPIKey 删除指定用户的指定 API 密钥。
+	// - 密钥不存在或不属于该用户时返回 commonerrors.ErrRepoNotFound。
+	DeleteAPIKey(ctx context.Context, db *gorm.DB, userID string, id uint) error
+
+	// UpdateLastUsedAt 将密钥的最近使用时间更新为 at。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	UpdateLastUsedAt(ctx context.Context, db *gorm.DB, id uint, at time.Time) error
+}
+
+// apiKeyRepository 是 APIKeyRepository 接口基于 GORM 的实现。
+type apiKeyRepository struct {
+	db *gorm.DB // db 是 GORM 数据库连接实例
+}
+
+// NewAPIKeyRepository 创建一个新的 apiKeyRepository 实例。
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// CreateAPIKey 实现接口方法。
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, db *gorm.DB, key *entities.APIKey) error {
+	if err := db.WithContext(ctx).Create(key).Error; err != nil {
+		return fmt.Errorf("apiKeyRepo.CreateAPIKey: 写入 API 密钥失败 (用户ID: %s): %w", key.UserID, err)
+	}
+	return nil
+}
+
+// ListByUserID 实现接口方法。
+func (r *apiKeyRepository) ListByUserID(ctx context.Context, userID string) ([]*entities.APIKey, error) {
+	var keys []*entities.APIKey
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, fmt.Errorf("apiKeyRepo.ListByUserID: 查询 API 密钥失败 (用户ID: %s): %w", userID, err)
+	}
+	return keys, nil
+}
+
+// CountByUserID 实现接口方法。
+func (r *apiKeyRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.APIKey{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("apiKeyRepo.CountByUserID: 统计 API 密钥失败 (用户ID: %s): %w", userID, err)
+	}
+	return count, nil
+}
+
+// GetByKeyHash 实现接口方法。
+func (r *apiKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	var key entities.APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commonerrors.ErrRepoNotFound
+		}
+		return nil, fmt.Errorf("apiKeyRepo.GetByKeyHash: 查询 API 密钥失败: %w", err)
+	}
+	return &key, nil
+}
+
+// DeleteAPIKey 实现接口方法。
+func (r *apiKeyRepository) DeleteAPIKey(ctx context.Context, db *gorm.DB, userID string, id uint) error {
+	result := db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&entities.APIKey{})
+	if result.Error != nil {
+		return fmt.Errorf("apiKeyRepo.DeleteAPIKey: 删除 API 密钥失败 (用户ID: %s, 密钥ID: %d): %w", userID, id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return commonerrors.ErrRepoNotFound
+	}
+	return nil
+}
+
+// UpdateLastUsedAt 实现接口方法。
+func (r *apiKeyRepository) UpdateLastUsedAt(ctx context.Context, db *gorm.DB, id uint, at time.Time) error {
+	err := db.WithContext(ctx).Model(&entities.APIKey{}).Where("id = ?", id).Update("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("apiKeyRepo.UpdateLastUsedAt: 更新 API 密钥使用时间失败 (密钥ID: %d): %w", id, err)
+	}
+	return nil
+}
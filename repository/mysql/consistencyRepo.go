@@ -0,0 +1,117 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+
+	"gorm.io/gorm"
+)
+
+// ConsistencyRepository 定义了跨表数据一致性巡检所需的查询与修复操作。
+// - 所有扫描均为按主键游标分页的有界查询，调用方通过上一批最后一条记录的主键继续扫描。
+// - "孤儿"指关联的用户不存在或已被软删除的资料/身份记录。
+type ConsistencyRepository interface {
+	// ListUsersWithoutProfile 查询没有资料记录的有效用户 (未软删除)。
+	// - afterUserID: 游标，仅返回 user_id 大于该值的用户；首批传空字符串。
+	// - 返回按 user_id 升序的用户 ID 列表，最多 limit 条。
+	ListUsersWithoutProfile(ctx context.Context, afterUserID string, limit int) ([]string, error)
+
+	// ListOrphanProfiles 查询关联用户不存在或已软删除的资料记录。
+	// - afterID: 游标，仅返回主键大于该值的记录；首批传 0。
+	ListOrphanProfiles(ctx context.Context, afterID uint, limit int) ([]*entities.UserProfile, error)
+
+	// ListOrphanIdentities 查询关联用户不存在或已软删除的身份记录。
+	// - afterID: 游标，仅返回主键大于该值的记录；首批传 0。
+	ListOrphanIdentities(ctx context.Context, afterID uint, limit int) ([]*entities.UserIdentity, error)
+
+	// DeleteProfilesByIDs 按主键批量删除资料记录，使用传入的 db 执行以支持事务。
+	DeleteProfilesByIDs(ctx context.Context, db *gorm.DB, ids []uint) error
+
+	// DeleteIdentitiesByIDs 按主键批量删除身份记录，使用传入的 db 执行以支持事务。
+	DeleteIdentitiesByIDs(ctx context.Context, db *gorm.DB, ids []uint) error
+}
+
+// consistencyRepository 是 ConsistencyRepository 接口基于 GORM 的实现。
+type consistencyRepository struct {
+	db *gorm.DB // db 是 GORM 数据库连接实例
+}
+
+// NewConsistencyRepository 创建一个新的 consistencyRepository 实例。
+// - 依赖注入 GORM 数据库连接。
+func NewConsistencyRepository(db *gorm.DB) ConsistencyRepository {
+	return &consistencyRepository{db: db}
+}
+
+// ListUsersWithoutProfile 实现接口方法，查询缺少资料的有效用户。
+func (r *consistencyRepository) ListUsersWithoutProfile(ctx context.Context, afterUserID string, limit int) ([]string, error) {
+	var userIDs []string
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Joins("LEFT JOIN user_profiles ON user_profiles.user_id = users.user_id AND user_profiles.deleted_at IS NULL").
+		Where("users.deleted_at IS NULL AND user_profiles.id IS NULL AND users.user_id > ?", afterUserID).
+		Order("users.user_id ASC").
+		Limit(limit).
+		Pluck("users.user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("consistencyRepo.ListUsersWithoutProfile: 查询缺少资料的用户失败 (游标: %s): %w", afterUserID, err)
+	}
+	return userIDs, nil
+}
+
+// ListOrphanProfiles 实现接口方法，查询孤儿资料记录。
+func (r *consistencyRepository) ListOrphanProfiles(ctx context.Context, afterID uint, limit int) ([]*entities.UserProfile, error) {
+	var profiles []*entities.UserProfile
+	err := r.db.WithContext(ctx).
+		Model(&entities.UserProfile{}).
+		Joins("LEFT JOIN users ON users.user_id = user_profiles.user_id AND users.deleted_at IS NULL").
+		Where("users.user_id IS NULL AND user_profiles.id > ?", afterID).
+		Order("user_profiles.id ASC").
+		Limit(limit).
+		Find(&profiles).Error
+	if err != nil {
+		return nil, fmt.Errorf("consistencyRepo.ListOrphanProfiles: 查询孤儿资料失败 (游标: %d): %w", afterID, err)
+	}
+	return profiles, nil
+}
+
+// ListOrphanIdentities 实现接口方法，查询孤儿身份记录。
+func (r *consistencyRepository) ListOrphanIdentities(ctx context.Context, afterID uint, limit int) ([]*entities.UserIdentity, error) {
+	var identities []*entities.UserIdentity
+	err := r.db.WithContext(ctx).
+		Model(&entities.UserIdentity{}).
+		Joins("LEFT JOIN users ON users.user_id = user_identities.user_id AND users.deleted_at IS NULL").
+		Where("users.user_id IS NULL AND user_identities.identity_id > ?", afterID).
+		Order("user_identities.identity_id ASC").
+		Limit(limit).
+		Find(&identities).Error
+	if err != nil {
+		return nil, fmt.Errorf("consistencyRepo.ListOrphanIdentities: 查询孤儿身份失败 (游标: %d): %w", afterID, err)
+	}
+	return identities, nil
+}
+
+// DeleteProfilesByIDs 实现接口方法，批量删除资料记录。
+// - 与删除用户时的级联处理一致，这里软删除资料 (传入 Unscoped 的 db 时物理删除)。
+func (r *consistencyRepository) DeleteProfilesByIDs(ctx context.Context, db *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := db.WithContext(ctx).Where("id IN ?", ids).Delete(&entities.UserProfile{}).Error; err != nil {
+		return fmt.Errorf("consistencyRepo.DeleteProfilesByIDs: 批量删除资料失败 (数量: %d): %w", len(ids), err)
+	}
+	return nil
+}
+
+// DeleteIdentitiesByIDs 实现接口方法，批量删除身份记录。
+// - 身份表没有软删除列，与删除用户时的级联处理一致，这里直接删除。
+func (r *consistencyRepository) DeleteIdentitiesByIDs(ctx context.Context, db *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := db.WithContext(ctx).Where("identity_id IN ?", ids).Delete(&entities.UserIdentity{}).Error; err != nil {
+		return fmt.Errorf("consistencyRepo.DeleteIdentitiesByIDs: 批量删除身份失败 (数量: %d): %w", len(ids), err)
+	}
+	return nil
+}
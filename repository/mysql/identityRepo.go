@@ -4,21 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt" // 引入 fmt 包用于错误包装
+	"time"
+
 	"github.com/Xushengqwer/go-common/commonerrors"
 
 	// 假设 IdentityCredential 移到了 dto 包
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/entities"
 	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // IdentityRepository 定义了与用户身份（UserIdentity）数据存储相关的操作接口。
 // - 它抽象了数据库交互的细节，允许服务层以统一的方式访问和管理用户身份数据。
 type IdentityRepository interface {
 	// CreateIdentity 持久化一个新的用户身份记录。
-	// - 接收应用上下文和待创建的用户身份实体。
+	// - 接收应用上下文和待创建的用户身份实体；实体未指定租户时归属 ctx 所属租户。
 	// - 如果数据库操作失败，则返回包装后的错误。
 	CreateIdentity(ctx context.Context, db *gorm.DB, identity *entities.UserIdentity) error
 
@@ -28,6 +32,7 @@ type IdentityRepository interface {
 	GetIdentityByID(ctx context.Context, identityID uint) (*entities.UserIdentity, error)
 
 	// GetIdentityByTypeAndIdentifier 根据身份类型和唯一标识符检索用户的核心凭证信息。
+	// - 只在 ctx 所属租户 (utils.TenantFromContext) 内查找；按标识符定位身份的其他方法同样如此。
 	// - 主要用于登录验证等场景，只选择必要字段以提高效率。
 	// - 如果未找到匹配的凭证，将返回 commonerrors.ErrRepoNotFound。
 	// - 其他数据库错误将被包装后返回。
@@ -35,8 +40,9 @@ type IdentityRepository interface {
 
 	// UpdateIdentity 更新一个已存在的用户身份记录。
 	// - 注意：此方法当前使用 GORM 的 Save，会更新所有字段。服务层应确保传入的实体是期望的状态。
+	// - 使用传入的 db 执行，调用方可传入事务对象（如修改密码时需与历史密码记录同时提交）。
 	// - 如果数据库操作失败，则返回包装后的错误。
-	UpdateIdentity(ctx context.Context, identity *entities.UserIdentity) error
+	UpdateIdentity(ctx context.Context, db *gorm.DB, identity *entities.UserIdentity) error
 
 	// DeleteIdentity 根据主键 ID 删除一个用户身份记录。
 	// - 如果数据库操作失败，则返回包装后的错误。
@@ -47,6 +53,12 @@ type IdentityRepository interface {
 	// - 如果数据库查询失败，则返回包装后的错误。
 	GetIdentitiesByUserID(ctx context.Context, userID string) ([]*entities.UserIdentity, error)
 
+	// LockIdentitiesByUserID 在事务中检索并锁定 (SELECT ... FOR UPDATE) 指定用户的所有身份记录。
+	// - 用于解绑等需要基于“当前身份数量”做判断的写操作，防止并发解绑把用户的身份删光。
+	// - db 必须是事务对象，锁在事务结束时释放。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	LockIdentitiesByUserID(ctx context.Context, db *gorm.DB, userID string) ([]*entities.UserIdentity, error)
+
 	// GetIdentityTypesByUserID 检索指定用户 ID 所拥有的所有身份类型。
 	// - 使用 Pluck 高效获取单列数据。
 	// - 如果用户没有任何身份记录，将返回一个空列表和 nil 错误。
@@ -64,6 +76,22 @@ type IdentityRepository interface {
 	// 返回:
 	//  - error: 如果数据库操作失败，则返回包装后的错误。如果用户没有任何身份记录，不视为错误。
 	DeleteIdentitiesByUserID(ctx context.Context, db *gorm.DB, userID string) error
+
+	// UpdateLastUsedAt 记录某个身份最近一次被用于登录的时间。
+	// - 使用 UpdateColumn，不会刷新 updated_at，避免把“登录”误记为“资料变更”。
+	// - 如果数据库操作失败，则返回包装后的错误；未匹配到记录不视为错误。
+	UpdateLastUsedAt(ctx context.Context, identityType enums.IdentityType, identifier string, usedAt time.Time) error
+
+	// ReplaceCredential 在凭证仍为 oldCredential 时将其替换为 newCredential。
+	// - 用于登录成功后透明升级密码哈希；以旧凭证作为条件，避免覆盖并发的修改密码。
+	// - 使用 UpdateColumn，不会刷新 updated_at。
+	// - 如果数据库操作失败，则返回包装后的错误；凭证已变化 (未匹配到记录) 不视为错误。
+	ReplaceCredential(ctx context.Context, identityType enums.IdentityType, identifier string, oldCredential, newCredential string) error
+
+	// MarkVerified 将指定类型与标识符的身份标记为已验证。
+	// - 用于登录过程中已证明标识符归属的场景 (如手机号验证码登录)。
+	// - 如果数据库操作失败，则返回包装后的错误；未匹配到记录不视为错误。
+	MarkVerified(ctx context.Context, identityType enums.IdentityType, identifier string) error
 }
 
 // identityRepository 是 IdentityRepository 接口基于 GORM 的实现。
@@ -79,6 +107,9 @@ func NewIdentityRepository(db *gorm.DB) IdentityRepository {
 
 // CreateIdentity 实现接口方法，持久化用户身份记录。
 func (r *identityRepository) CreateIdentity(ctx context.Context, db *gorm.DB, identity *entities.UserIdentity) error {
+	if identity.TenantID == "" {
+		identity.TenantID = utils.TenantFromContext(ctx)
+	}
 	// 执行数据库创建操作
 	if err := db.WithContext(ctx).Create(identity).Error; err != nil {
 		// 包装创建操作时发生的错误，添加中文上下文信息
@@ -114,9 +145,9 @@ func (r *identityRepository) GetIdentityByTypeAndIdentifier(ctx context.Context,
 	var cred dto.IdentityCredential // 使用 dto 包下的结构体
 	// 执行数据库查询操作，只选择需要的字段
 	err := r.db.WithContext(ctx).
-		Select("user_id, credential").
+		Select("user_id, credential, verified").
 		Table("user_identities"). // 明确指定表名，因为 DTO 通常不是 GORM 模型
-		Where("identity_type = ? AND identifier = ?", identityType, identifier).
+		Where("tenant_id = ? AND identity_type = ? AND identifier = ?", utils.TenantFromContext(ctx), identityType, identifier).
 		First(&cred).Error
 
 	if err != nil {
@@ -133,10 +164,11 @@ func (r *identityRepository) GetIdentityByTypeAndIdentifier(ctx context.Context,
 }
 
 // UpdateIdentity 实现接口方法，更新用户身份信息。
-func (r *identityRepository) UpdateIdentity(ctx context.Context, identity *entities.UserIdentity) error {
+// - 使用传入的 db 对象执行操作，使其能够参与外部事务。
+func (r *identityRepository) UpdateIdentity(ctx context.Context, db *gorm.DB, identity *entities.UserIdentity) error {
 	// 注意：Save 会更新所有字段。确保调用方传入的是完整的、期望状态的实体。
 	// 执行数据库更新操作
-	if err := r.db.WithContext(ctx).Save(identity).Error; err != nil {
+	if err := db.WithContext(ctx).Save(identity).Error; err != nil {
 		// 包装更新操作时发生的错误，添加中文上下文信息
 		return fmt.Errorf("identityRepo.UpdateIdentity: 更新身份失败 (ID: %d): %w", identity.IdentityID, err)
 	}
@@ -173,6 +205,19 @@ func (r *identityRepository) GetIdentitiesByUserID(ctx context.Context, userID s
 	return identities, nil
 }
 
+// LockIdentitiesByUserID 实现接口方法，在事务中锁定用户的所有身份。
+func (r *identityRepository) LockIdentitiesByUserID(ctx context.Context, db *gorm.DB, userID string) ([]*entities.UserIdentity, error) {
+	var identities []*entities.UserIdentity
+	err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		Find(&identities).Error
+	if err != nil {
+		return nil, fmt.Errorf("identityRepo.LockIdentitiesByUserID: 锁定用户身份列表失败 (UserID: %s): %w", userID, err)
+	}
+	return identities, nil
+}
+
 // GetIdentityTypesByUserID 实现接口方法，获取用户的所有身份类型。
 func (r *identityRepository) GetIdentityTypesByUserID(ctx context.Context, userID string) ([]enums.IdentityType, error) {
 	var identityTypes []enums.IdentityType
@@ -204,3 +249,39 @@ func (r *identityRepository) DeleteIdentitiesByUserID(ctx context.Context, db *g
 	// 例如，如果一个用户没有任何身份信息，调用此方法删除其身份是正常的，不应报错。
 	return nil
 }
+
+// UpdateLastUsedAt 实现接口方法，记录身份最近一次登录时间。
+func (r *identityRepository) UpdateLastUsedAt(ctx context.Context, identityType enums.IdentityType, identifier string, usedAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.UserIdentity{}).
+		Where("tenant_id = ? AND identity_type = ? AND identifier = ?", utils.TenantFromContext(ctx), identityType, identifier).
+		UpdateColumn("last_used_at", usedAt).Error
+	if err != nil {
+		return fmt.Errorf("identityRepo.UpdateLastUsedAt: 更新身份最近使用时间失败 (类型: %d, 标识符: %s): %w", identityType, identifier, err)
+	}
+	return nil
+}
+
+// ReplaceCredential 实现接口方法，按旧凭证条件替换身份凭证。
+func (r *identityRepository) ReplaceCredential(ctx context.Context, identityType enums.IdentityType, identifier string, oldCredential, newCredential string) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.UserIdentity{}).
+		Where("tenant_id = ? AND identity_type = ? AND identifier = ? AND credential = ?", utils.TenantFromContext(ctx), identityType, identifier, oldCredential).
+		UpdateColumn("credential", newCredential).Error
+	if err != nil {
+		return fmt.Errorf("identityRepo.ReplaceCredential: 替换身份凭证失败 (类型: %d, 标识符: %s): %w", identityType, identifier, err)
+	}
+	return nil
+}
+
+// MarkVerified 实现接口方法，将身份标记为已验证。
+func (r *identityRepository) MarkVerified(ctx context.Context, identityType enums.IdentityType, identifier string) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.UserIdentity{}).
+		Where("tenant_id = ? AND identity_type = ? AND identifier = ?", utils.TenantFromContext(ctx), identityType, identifier).
+		Update("verified", true).Error
+	if err != nil {
+		return fmt.Errorf("identityRepo.MarkVerified: 标记身份为已验证失败 (类型: %d, 标识符: %s): %w", identityType, identifier, err)
+	}
+	return nil
+}
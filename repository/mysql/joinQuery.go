@@ -3,12 +3,16 @@ package mysql
 import (
 	"context"
 	"fmt" // 引入 fmt 包用于错误包装
+	"slices"
 	"strings"
 
-	"github.com/Xushengqwer/user_hub/models/dto" // 引入 DTO 包
-	"github.com/Xushengqwer/user_hub/models/vo"  // 引入 VO 包
+	"github.com/Xushengqwer/user_hub/models/dto"      // 引入 DTO 包
+	"github.com/Xushengqwer/user_hub/models/entities" // 引入实体包
+	"github.com/Xushengqwer/user_hub/models/enums"    // 引入项目内部枚举包
+	"github.com/Xushengqwer/user_hub/models/vo"       // 引入 VO 包
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // 定义允许的过滤字段及其对应的数据库列名
@@ -24,9 +28,109 @@ var allowedFilters = map[string]string{
 var allowedOrderBy = map[string]string{
 	"created_at": "users.created_at",
 	"user_id":    "users.user_id",
+	"status":     "users.status",
 	// ... 在这里添加其他允许排序的字段
 }
 
+// userProjectionColumns 用户列表允许投影的字段 (与 vo.UserWithProfileVO 的 JSON 字段名一致) 及其 SELECT 表达式
+// - 顺序即完整投影的列顺序；user_id 必须排在第一位，投影时总会包含。
+var userProjectionColumns = []struct {
+	field  string
+	column string
+}{
+	{"user_id", "users.user_id"},
+	{"role", "users.user_role as role"},
+	{"status", "users.status"},
+	{"nickname", "user_profiles.nickname"},
+	{"avatar_url", "user_profiles.avatar_url"},
+	{"gender", "user_profiles.gender"},
+	{"province", "user_profiles.province"},
+	{"city", "user_profiles.city"},
+	{"created_at", "users.created_at"},
+	{"updated_at", "users.updated_at"},
+}
+
+// IsUserProjectionField 判断字段是否允许出现在用户列表查询的投影 (dto.UserQueryDTO.Fields) 中
+func IsUserProjectionField(field string) bool {
+	for _, col := range userProjectionColumns {
+		if col.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// userProjectionSelect 构造用户列表查询的 SELECT 列表
+// - fields 为空时使用完整投影；否则只选择允许列表中被请求的字段，并总是包含 user_id。
+func userProjectionSelect(fields []string) string {
+	columns := make([]string, 0, len(userProjectionColumns))
+	for i, col := range userProjectionColumns {
+		if len(fields) == 0 || i == 0 || slices.Contains(fields, col.field) {
+			columns = append(columns, col.column)
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// orderTiebreaker 追加在所有排序之后的次级排序。
+// - created_at 等列不唯一，值相同的行在不同页之间的先后顺序不确定，翻页时会出现重复或遗漏；以唯一的 user_id 兜底保证顺序确定。
+const orderTiebreaker = "users.user_id ASC"
+
+// defaultOrderBy 未指定或没有任何有效排序键时使用的排序
+const defaultOrderBy = "users.created_at DESC"
+
+// buildOrderByClause 将客户端传入的排序字符串转换为安全的 ORDER BY 子句。
+// - 支持多个以逗号分隔的排序键，如 "status ASC, created_at DESC"；方向省略时为 ASC，不区分大小写。
+// - 每个键只能使用 allowedOrderBy 中的字段，列名取自映射表而非客户端输入；无效的字段或方向、重复的字段被跳过。
+// - 没有任何有效排序键时使用默认排序；结果总以唯一的 user_id 兜底 (已按 user_id 排序时除外)。
+func buildOrderByClause(orderBy string) string {
+	var (
+		terms  []string
+		seen   = make(map[string]bool)
+		unique bool
+	)
+	for _, key := range strings.Split(orderBy, ",") {
+		parts := strings.Fields(key)
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) > 2 {
+			fmt.Printf("警告: 忽略了格式无效的排序键: %s\n", strings.TrimSpace(key))
+			continue
+		}
+
+		dbColumn, ok := allowedOrderBy[parts[0]]
+		if !ok {
+			fmt.Printf("警告: 忽略了不允许的排序字段: %s\n", parts[0])
+			continue
+		}
+		direction := "ASC"
+		if len(parts) == 2 {
+			direction = strings.ToUpper(parts[1])
+			if direction != "ASC" && direction != "DESC" {
+				fmt.Printf("警告: 忽略了无效的排序方向: %s\n", parts[1])
+				continue
+			}
+		}
+		if seen[dbColumn] {
+			continue
+		}
+		seen[dbColumn] = true
+		if dbColumn == allowedOrderBy["user_id"] {
+			unique = true
+		}
+		terms = append(terms, dbColumn+" "+direction)
+	}
+
+	if len(terms) == 0 {
+		return defaultOrderBy + ", " + orderTiebreaker
+	}
+	if !unique {
+		terms = append(terms, orderTiebreaker)
+	}
+	return strings.Join(terms, ", ")
+}
+
 // JoinQuery 定义了专注于多表联合查询的操作接口。
 // - 它提供了比单个实体仓库更复杂的查询能力。
 type JoinQuery interface {
@@ -35,6 +139,12 @@ type JoinQuery interface {
 	// - 直接返回用于 API 响应的 VO 列表，减少服务层的转换工作。
 	// - 如果数据库查询失败，则返回包装后的错误。
 	ListUsersWithProfile(ctx context.Context, queryDTO *dto.UserQueryDTO) ([]*vo.UserWithProfileVO, int64, error)
+
+	// SearchUsersWithProfile 按关键字组合搜索用户及其资料，用于管理后台的单一搜索框。
+	// - 精确匹配用户 ID、手机号 / 账号身份的标识符 (均有索引)，模糊匹配昵称 (LIKE，关键字中的通配符会被转义)。
+	// - 同一用户命中多个条件时只返回一次；精确命中的用户排在前面。
+	// - maxResults 限制可翻页的结果总数，返回的总数也不超过该值，用于约束 LIKE 的扫描成本。
+	SearchUsersWithProfile(ctx context.Context, keyword string, page, pageSize, maxResults int) ([]*vo.UserWithProfileVO, int64, error)
 }
 
 // joinQuery 是 JoinQuery 接口基于 GORM 的实现。
@@ -52,14 +162,11 @@ func NewJoinQuery(db *gorm.DB) JoinQuery {
 func (r *joinQuery) ListUsersWithProfile(ctx context.Context, queryDTO *dto.UserQueryDTO) ([]*vo.UserWithProfileVO, int64, error) {
 	var results []*vo.UserWithProfileVO
 
-	// 1. 构建基础查询 (与之前相同)
+	// 1. 构建基础查询：按 Fields 限定 SELECT 列表 (调用方需先用 IsUserProjectionField 校验)，为空时选择全部字段
 	db := r.db.WithContext(ctx).
 		Table("users").
-		Joins("LEFT JOIN user_profiles ON user_profiles.user_id = users.user_id").
-		Select("users.user_id, users.user_role as role, users.status, " +
-			"user_profiles.nickname, user_profiles.avatar_url, user_profiles.gender, " +
-			"user_profiles.province, user_profiles.city, " +
-			"users.created_at, users.updated_at")
+		Joins("LEFT JOIN user_profiles ON user_profiles.user_id = users.user_id AND user_profiles.deleted_at IS NULL").
+		Select(userProjectionSelect(queryDTO.Fields))
 
 	// 2. 安全地应用过滤条件
 	// - 精确匹配
@@ -111,32 +218,8 @@ func (r *joinQuery) ListUsersWithProfile(ctx context.Context, queryDTO *dto.User
 		return nil, 0, fmt.Errorf("joinQuery.ListUsersWithProfile: 查询总数失败: %w", err)
 	}
 
-	// 4. 安全地应用排序
-	orderByClause := "users.created_at DESC" // 默认排序
-	if queryDTO.OrderBy != "" {
-		parts := strings.Fields(queryDTO.OrderBy) // 按空格分割，例如 "created_at DESC"
-		field := parts[0]
-		direction := "ASC" // 默认升序
-		if len(parts) > 1 {
-			dirUpper := strings.ToUpper(parts[1])
-			if dirUpper == "DESC" {
-				direction = "DESC"
-			} else if dirUpper != "ASC" {
-				// 如果方向不是 ASC 或 DESC，则忽略或报错
-				fmt.Printf("警告: 忽略了无效的排序方向: %s\n", parts[1])
-				direction = "" // 标记为无效，使用默认排序
-			}
-		}
-
-		// 验证排序字段是否允许
-		if dbColumn, ok := allowedOrderBy[field]; ok && direction != "" {
-			orderByClause = dbColumn + " " + direction
-		} else {
-			fmt.Printf("警告: 忽略了不允许或无效的排序字段: %s\n", field)
-			// 使用默认排序
-		}
-	}
-	db = db.Order(orderByClause)
+	// 4. 安全地应用排序 (支持多个以逗号分隔的排序键，逐个校验)
+	db = db.Order(buildOrderByClause(queryDTO.OrderBy))
 
 	// 5. 应用分页 (与之前相同)
 	page := queryDTO.Page
@@ -158,3 +241,65 @@ func (r *joinQuery) ListUsersWithProfile(ctx context.Context, queryDTO *dto.User
 	// 7. 返回结果
 	return results, total, nil
 }
+
+// searchIdentityTypes 搜索时按标识符精确匹配的身份类型 (手机号、账号/邮箱)
+var searchIdentityTypes = []enums.IdentityType{enums.Phone, enums.AccountPassword}
+
+// likeEscaper 转义 LIKE 模式中的通配符，使关键字按字面匹配
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchUsersWithProfile 实现接口方法，执行用户的组合关键字搜索。
+func (r *joinQuery) SearchUsersWithProfile(ctx context.Context, keyword string, page, pageSize, maxResults int) ([]*vo.UserWithProfileVO, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	// 1. 构建匹配条件：用户 ID 精确、身份标识符精确 (子查询)、昵称模糊
+	identityMatch := r.db.Model(&entities.UserIdentity{}).
+		Select("user_id").
+		Where("identifier = ? AND identity_type IN ?", keyword, searchIdentityTypes)
+	exactMatch := r.db.Where("users.user_id = ?", keyword).Or("users.user_id IN (?)", identityMatch)
+	baseQuery := func() *gorm.DB {
+		return r.db.WithContext(ctx).
+			Table("users").
+			Joins("LEFT JOIN user_profiles ON user_profiles.user_id = users.user_id AND user_profiles.deleted_at IS NULL").
+			Where("users.deleted_at IS NULL").
+			Where(r.db.Where(exactMatch).Or("user_profiles.nickname LIKE ?", "%"+likeEscaper.Replace(keyword)+"%"))
+	}
+
+	// 2. 统计总数，最多统计到 maxResults 条
+	var total int64
+	capped := baseQuery().Select("users.user_id").Limit(maxResults)
+	if err := r.db.WithContext(ctx).Table("(?) AS matched", capped).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("joinQuery.SearchUsersWithProfile: 查询总数失败: %w", err)
+	}
+
+	// 3. 分页只在 maxResults 范围内进行
+	offset := (page - 1) * pageSize
+	if offset >= int(total) {
+		return []*vo.UserWithProfileVO{}, total, nil
+	}
+	limit := pageSize
+	if offset+limit > maxResults {
+		limit = maxResults - offset
+	}
+
+	// 4. 查询当前页：精确命中优先，其余按创建时间倒序 (user_id 兜底保证翻页稳定)
+	var results []*vo.UserWithProfileVO
+	orderBy := clause.OrderBy{Expression: clause.Expr{
+		SQL:  "CASE WHEN users.user_id = ? OR users.user_id IN (?) THEN 0 ELSE 1 END, users.created_at DESC, " + orderTiebreaker,
+		Vars: []any{keyword, identityMatch},
+	}}
+	err := baseQuery().
+		Select(userProjectionSelect(nil)).
+		Order(orderBy).
+		Offset(offset).Limit(limit).
+		Scan(&results).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("joinQuery.SearchUsersWithProfile: 搜索用户失败: %w", err)
+	}
+	return results, total, nil
+}
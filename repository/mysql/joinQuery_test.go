@@ -0,0 +1,112 @@
+package mysql
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	commonenums "github.com/Xushengqwer/go-common/models/enums"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+)
+
+func TestBuildOrderByClause(t *testing.T) {
+	const defaultClause = "users.created_at DESC, users.user_id ASC"
+
+	tests := []struct {
+		name    string
+		orderBy string
+		want    string
+	}{
+		{name: "多个有效排序键", orderBy: "status ASC, created_at DESC", want: "users.status ASC, users.created_at DESC, users.user_id ASC"},
+		{name: "注入尝试整体被丢弃", orderBy: "created_at; DROP TABLE users", want: defaultClause},
+		{name: "无效方向的键被跳过", orderBy: "created_at DESC, status SIDEWAYS", want: "users.created_at DESC, users.user_id ASC"},
+		{name: "空字符串使用默认排序", orderBy: "", want: defaultClause},
+		{name: "仅有逗号与空白使用默认排序", orderBy: " , ,", want: defaultClause},
+		{name: "重复字段只保留第一个", orderBy: "created_at DESC, created_at ASC", want: "users.created_at DESC, users.user_id ASC"},
+		{name: "重复字段不同写法只保留第一个", orderBy: "status, status desc, created_at", want: "users.status ASC, users.created_at ASC, users.user_id ASC"},
+		{name: "方向不区分大小写", orderBy: "created_at desc", want: "users.created_at DESC, users.user_id ASC"},
+		{name: "不允许的字段使用默认排序", orderBy: "password DESC", want: defaultClause},
+		{name: "已按 user_id 排序时不追加兜底", orderBy: "status DESC, user_id DESC", want: "users.status DESC, users.user_id DESC"},
+		{name: "字段名区分大小写", orderBy: "Created_At DESC", want: defaultClause},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildOrderByClause(tt.orderBy)
+			if got != tt.want {
+				t.Errorf("buildOrderByClause(%q) = %q，期望 %q", tt.orderBy, got, tt.want)
+			}
+			for _, forbidden := range []string{";", "DROP", "SIDEWAYS"} {
+				if strings.Contains(got, forbidden) {
+					t.Errorf("buildOrderByClause(%q) 结果包含客户端输入 %q: %q", tt.orderBy, forbidden, got)
+				}
+			}
+		})
+	}
+}
+
+// newSQLiteDB 创建内存 SQLite 数据库并迁移用户相关表，用于验证真实的排序与分页行为
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("打开 SQLite 失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取底层连接失败: %v", err)
+	}
+	// 内存数据库按连接隔离，限制为单连接保证所有查询看到同一份数据
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	if err := db.AutoMigrate(&entities.User{}, &entities.UserProfile{}); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+	return db
+}
+
+func TestListUsersWithProfileStablePagingOnDuplicateTimestamps(t *testing.T) {
+	db := newSQLiteDB(t)
+
+	// 所有用户的 created_at 与 status 完全相同，插入顺序故意与 user_id 顺序不一致
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	userIDs := []string{"u5", "u2", "u7", "u1", "u4", "u6", "u3"}
+	for _, id := range userIDs {
+		if err := db.Create(&entities.User{UserID: id, UserRole: commonenums.RoleUser, Status: commonenums.StatusActive, CreatedAt: createdAt, UpdatedAt: createdAt}).Error; err != nil {
+			t.Fatalf("插入用户失败: %v", err)
+		}
+	}
+	want := slices.Clone(userIDs)
+	slices.Sort(want)
+
+	for _, orderBy := range []string{"", "created_at DESC", "status ASC, created_at DESC"} {
+		t.Run("排序="+orderBy, func(t *testing.T) {
+			repo := NewJoinQuery(db)
+			const pageSize = 3
+
+			var got []string
+			for page := 1; page <= 3; page++ {
+				results, total, err := repo.ListUsersWithProfile(context.Background(), &dto.UserQueryDTO{Page: page, PageSize: pageSize, OrderBy: orderBy})
+				if err != nil {
+					t.Fatalf("第 %d 页查询失败: %v", page, err)
+				}
+				if total != int64(len(userIDs)) {
+					t.Fatalf("total = %d，期望 %d", total, len(userIDs))
+				}
+				for _, r := range results {
+					got = append(got, r.UserID)
+				}
+			}
+			// 逐页拼接的结果应恰好覆盖全部用户且无重复，并按 user_id 兜底排序
+			if !slices.Equal(got, want) {
+				t.Errorf("逐页拼接结果 = %v，期望 %v", got, want)
+			}
+		})
+	}
+}
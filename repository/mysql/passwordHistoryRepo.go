@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+
+	"gorm.io/gorm"
+)
+
+// PasswordHistoryRepository 定义了用户历史密码记录的存储接口。
+type PasswordHistoryRepository interface {
+	// ListRecentHashes 按记录时间倒序返回指定用户最近的 limit 个密码哈希。
+	// - 用户没有任何历史记录时返回空列表和 nil 错误。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	ListRecentHashes(ctx context.Context, userID string, limit int) ([]string, error)
+
+	// AddPasswordHash 为指定用户追加一条历史密码记录。
+	// - 使用传入的 db 执行，调用方可传入事务对象，使其与密码更新同时提交或回滚。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	AddPasswordHash(ctx context.Context, db *gorm.DB, userID string, passwordHash string) error
+
+	// TrimHistory 只保留指定用户最近的 keep 条历史密码记录，删除更早的记录。
+	// - 使用传入的 db 执行，调用方可传入事务对象。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	TrimHistory(ctx context.Context, db *gorm.DB, userID string, keep int) error
+}
+
+// passwordHistoryRepository 是 PasswordHistoryRepository 接口基于 GORM 的实现。
+type passwordHistoryRepository struct {
+	db *gorm.DB // db 是 GORM 数据库连接实例
+}
+
+// NewPasswordHistoryRepository 创建一个新的 passwordHistoryRepository 实例。
+// - 依赖注入 GORM 数据库连接。
+func NewPasswordHistoryRepository(db *gorm.DB) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+// ListRecentHashes 实现接口方法，查询最近的历史密码哈希。
+func (r *passwordHistoryRepository) ListRecentHashes(ctx context.Context, userID string, limit int) ([]string, error) {
+	var hashes []string
+	err := r.db.WithContext(ctx).
+		Model(&entities.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").Order("id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	if err != nil {
+		return nil, fmt.Errorf("passwordHistoryRepo.ListRecentHashes: 查询历史密码失败 (用户ID: %s): %w", userID, err)
+	}
+	return hashes, nil
+}
+
+// AddPasswordHash 实现接口方法，追加历史密码记录。
+func (r *passwordHistoryRepository) AddPasswordHash(ctx context.Context, db *gorm.DB, userID string, passwordHash string) error {
+	record := &entities.PasswordHistory{UserID: userID, PasswordHash: passwordHash}
+	if err := db.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("passwordHistoryRepo.AddPasswordHash: 写入历史密码失败 (用户ID: %s): %w", userID, err)
+	}
+	return nil
+}
+
+// TrimHistory 实现接口方法，裁剪历史密码记录。
+func (r *passwordHistoryRepository) TrimHistory(ctx context.Context, db *gorm.DB, userID string, keep int) error {
+	// 先查出需要保留的记录 ID，再删除其余记录（MySQL 不支持在 DELETE 的子查询中直接使用 LIMIT）
+	var keepIDs []uint
+	err := db.WithContext(ctx).
+		Model(&entities.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").Order("id DESC").
+		Limit(keep).
+		Pluck("id", &keepIDs).Error
+	if err != nil {
+		return fmt.Errorf("passwordHistoryRepo.TrimHistory: 查询需保留的历史密码失败 (用户ID: %s): %w", userID, err)
+	}
+
+	query := db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(keepIDs) > 0 {
+		query = query.Where("id NOT IN ?", keepIDs)
+	}
+	if err := query.Delete(&entities.PasswordHistory{}).Error; err != nil {
+		return fmt.Errorf("passwordHistoryRepo.TrimHistory: 删除过期历史密码失败 (用户ID: %s): %w", userID, err)
+	}
+	return nil
+}
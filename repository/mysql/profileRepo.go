@@ -24,12 +24,18 @@ type ProfileRepository interface {
 	// - 其他数据库错误将被包装后返回。
 	GetProfileByUserID(ctx context.Context, userID string) (*entities.UserProfile, error)
 
+	// GetProfileByUserIDIncludingDeleted 与 GetProfileByUserID 相同，但不排除已软删除的资料 (使用 Unscoped)。
+	// - 仅用于硬删除已软删除的用户时读取头像地址，业务流程应继续使用 GetProfileByUserID。
+	// - 如果未找到匹配的用户资料，将返回 commonerrors.ErrRepoNotFound。
+	GetProfileByUserIDIncludingDeleted(ctx context.Context, userID string) (*entities.UserProfile, error)
+
 	// UpdateProfile 更新一个已存在的用户资料信息。
 	// - 注意：此方法当前使用 GORM 的 Save，会更新记录的所有字段。服务层应确保传入的实体是期望的完整状态。
 	// - 如果数据库操作失败，则返回包装后的错误。
 	UpdateProfile(ctx context.Context, profile *entities.UserProfile) error
 
 	// DeleteProfile 根据用户 ID 删除一条用户资料记录。
+	// - 资料表使用软删除；传入 Unscoped 的 db (硬删除用户) 时物理删除，包括此前已被软删除的资料。
 	// - 如果数据库操作失败，则返回包装后的错误。
 	DeleteProfile(ctx context.Context, db *gorm.DB, userID string) error
 }
@@ -75,6 +81,19 @@ func (r *profileRepository) GetProfileByUserID(ctx context.Context, userID strin
 	return &profile, nil
 }
 
+// GetProfileByUserIDIncludingDeleted 实现接口方法，查询用户资料时包含已软删除的记录。
+func (r *profileRepository) GetProfileByUserIDIncludingDeleted(ctx context.Context, userID string) (*entities.UserProfile, error) {
+	var profile entities.UserProfile
+	err := r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).First(&profile).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commonerrors.ErrRepoNotFound
+		}
+		return nil, fmt.Errorf("profileRepo.GetProfileByUserIDIncludingDeleted: 查询用户资料失败 (UserID: %s): %w", userID, err)
+	}
+	return &profile, nil
+}
+
 // UpdateProfile 实现接口方法，更新用户资料信息。
 func (r *profileRepository) UpdateProfile(ctx context.Context, profile *entities.UserProfile) error {
 	// 注意：Save 会更新记录的所有字段。服务层应确保传入的 profile 实体是期望的完整状态，
@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+)
+
+func TestProfileSoftDeleteKeepsAvatarForHardDelete(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	repo := NewProfileRepository(db)
+	const avatarURL = "https://bucket.example.com/avatars/u1.png"
+	if err := repo.CreateProfile(ctx, db, &entities.UserProfile{UserID: "u1", Nickname: "用户1", AvatarURL: avatarURL}); err != nil {
+		t.Fatalf("创建资料失败: %v", err)
+	}
+
+	// 软删除后普通查询查不到，包含已删除记录的查询仍能读到头像地址
+	if err := repo.DeleteProfile(ctx, db, "u1"); err != nil {
+		t.Fatalf("软删除资料失败: %v", err)
+	}
+	if _, err := repo.GetProfileByUserID(ctx, "u1"); !errors.Is(err, commonerrors.ErrRepoNotFound) {
+		t.Errorf("软删除后 GetProfileByUserID 错误 = %v，期望 ErrRepoNotFound", err)
+	}
+	profile, err := repo.GetProfileByUserIDIncludingDeleted(ctx, "u1")
+	if err != nil {
+		t.Fatalf("软删除后 GetProfileByUserIDIncludingDeleted 失败: %v", err)
+	}
+	if profile.AvatarURL != avatarURL || !profile.DeletedAt.Valid {
+		t.Errorf("查到的资料 = %+v，期望保留头像地址并带有删除时间", profile)
+	}
+
+	// Unscoped 删除 (硬删除) 物理删除已软删除的资料
+	if err := repo.DeleteProfile(ctx, db.Unscoped(), "u1"); err != nil {
+		t.Fatalf("硬删除资料失败: %v", err)
+	}
+	if _, err := repo.GetProfileByUserIDIncludingDeleted(ctx, "u1"); !errors.Is(err, commonerrors.ErrRepoNotFound) {
+		t.Errorf("硬删除后 GetProfileByUserIDIncludingDeleted 错误 = %v，期望 ErrRepoNotFound", err)
+	}
+}
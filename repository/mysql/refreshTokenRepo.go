@@ -0,0 +1,146 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Xushengqwer/go-common/models/enums"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository 定义了刷新令牌白名单的存储接口。
+type RefreshTokenRepository interface {
+	// CreateRefreshToken 写入一条已签发刷新令牌的记录。
+	// - 使用传入的 db 执行，调用方可传入事务对象（如令牌轮换时与旧令牌的消费同时提交）。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	CreateRefreshToken(ctx context.Context, db *gorm.DB, token *entities.RefreshToken) error
+
+	// ConsumeRefreshToken 原子地将指定 JTI 标记为已使用。
+	// - 只有记录存在、尚未使用且未过期时才会更新，返回 true；否则返回 false。
+	// - 依赖单条 UPDATE 的条件判断，并发请求中只有一个能成功消费同一个 JTI。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	ConsumeRefreshToken(ctx context.Context, db *gorm.DB, jti string, now time.Time) (bool, error)
+
+	// DeleteExpiredByUserID 删除指定用户已过期的刷新令牌记录，控制白名单表的规模。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	DeleteExpiredByUserID(ctx context.Context, db *gorm.DB, userID string, now time.Time) error
+
+	// DeleteExpiredBatch 删除最多 limit 条已过期的刷新令牌记录 (不限用户)，供后台清理任务分批调用。
+	// - 返回实际删除的记录数；小于 limit 说明已无剩余的过期记录。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	DeleteExpiredBatch(ctx context.Context, db *gorm.DB, now time.Time, limit int) (int64, error)
+
+	// CountActiveByUserID 统计指定用户尚未使用且未过期的刷新令牌数量，即当前有效的登录会话数。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	CountActiveByUserID(ctx context.Context, userID string, now time.Time) (int64, error)
+
+	// CountActiveByPlatform 按签发平台统计指定用户尚未使用且未过期的刷新令牌数量，只返回数量大于 0 的平台。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	CountActiveByPlatform(ctx context.Context, userID string, now time.Time) (map[enums.Platform]int64, error)
+}
+
+// refreshTokenRepository 是 RefreshTokenRepository 接口基于 GORM 的实现。
+type refreshTokenRepository struct {
+	db *gorm.DB // db 是 GORM 数据库连接实例
+}
+
+// NewRefreshTokenRepository 创建一个新的 refreshTokenRepository 实例。
+// - 依赖注入 GORM 数据库连接。
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+// CreateRefreshToken 实现接口方法，写入刷新令牌记录。
+func (r *refreshTokenRepository) CreateRefreshToken(ctx context.Context, db *gorm.DB, token *entities.RefreshToken) error {
+	if err := db.WithContext(ctx).Create(token).Error; err != nil {
+		return fmt.Errorf("refreshTokenRepo.CreateRefreshToken: 写入刷新令牌记录失败 (用户ID: %s): %w", token.UserID, err)
+	}
+	return nil
+}
+
+// ConsumeRefreshToken 实现接口方法，原子地消费刷新令牌。
+func (r *refreshTokenRepository) ConsumeRefreshToken(ctx context.Context, db *gorm.DB, jti string, now time.Time) (bool, error) {
+	result := db.WithContext(ctx).
+		Model(&entities.RefreshToken{}).
+		Where("jti = ? AND consumed_at IS NULL AND expires_at > ?", jti, now).
+		Update("consumed_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("refreshTokenRepo.ConsumeRefreshToken: 消费刷新令牌失败 (JTI: %s): %w", jti, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteExpiredByUserID 实现接口方法，清理用户已过期的刷新令牌记录。
+func (r *refreshTokenRepository) DeleteExpiredByUserID(ctx context.Context, db *gorm.DB, userID string, now time.Time) error {
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND expires_at <= ?", userID, now).
+		Delete(&entities.RefreshToken{}).Error
+	if err != nil {
+		return fmt.Errorf("refreshTokenRepo.DeleteExpiredByUserID: 清理过期刷新令牌失败 (用户ID: %s): %w", userID, err)
+	}
+	return nil
+}
+
+// DeleteExpiredBatch 实现接口方法，分批清理已过期的刷新令牌记录。
+// 先按过期时间索引取出一批主键再按主键删除，避免单条 DELETE 长时间锁表。
+func (r *refreshTokenRepository) DeleteExpiredBatch(ctx context.Context, db *gorm.DB, now time.Time, limit int) (int64, error) {
+	var jtis []string
+	err := db.WithContext(ctx).
+		Model(&entities.RefreshToken{}).
+		Where("expires_at <= ?", now).
+		Limit(limit).
+		Pluck("jti", &jtis).Error
+	if err != nil {
+		return 0, fmt.Errorf("refreshTokenRepo.DeleteExpiredBatch: 查询过期刷新令牌失败: %w", err)
+	}
+	if len(jtis) == 0 {
+		return 0, nil
+	}
+
+	result := db.WithContext(ctx).
+		Where("jti IN ? AND expires_at <= ?", jtis, now).
+		Delete(&entities.RefreshToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("refreshTokenRepo.DeleteExpiredBatch: 删除过期刷新令牌失败 (数量: %d): %w", len(jtis), result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// CountActiveByUserID 实现接口方法，统计用户当前有效的刷新令牌数量。
+func (r *refreshTokenRepository) CountActiveByUserID(ctx context.Context, userID string, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entities.RefreshToken{}).
+		Where("user_id = ? AND consumed_at IS NULL AND expires_at > ?", userID, now).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("refreshTokenRepo.CountActiveByUserID: 统计有效刷新令牌失败 (用户ID: %s): %w", userID, err)
+	}
+	return count, nil
+}
+
+// CountActiveByPlatform 实现接口方法，按平台分组统计用户当前有效的刷新令牌数量。
+func (r *refreshTokenRepository) CountActiveByPlatform(ctx context.Context, userID string, now time.Time) (map[enums.Platform]int64, error) {
+	var rows []struct {
+		Platform enums.Platform
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&entities.RefreshToken{}).
+		Select("platform, COUNT(*) AS count").
+		Where("user_id = ? AND consumed_at IS NULL AND expires_at > ?", userID, now).
+		Group("platform").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("refreshTokenRepo.CountActiveByPlatform: 按平台统计有效刷新令牌失败 (用户ID: %s): %w", userID, err)
+	}
+	counts := make(map[enums.Platform]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Platform] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+
+	"gorm.io/gorm"
+)
+
+// SMSDeadLetterRepository 定义了发送失败短信 (死信) 记录的存储接口。
+type SMSDeadLetterRepository interface {
+	// Create 写入一条死信记录。
+	// - 使用传入的 db 执行，调用方可传入事务对象。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	Create(ctx context.Context, db *gorm.DB, record *entities.SMSDeadLetter) error
+}
+
+// smsDeadLetterRepository 是 SMSDeadLetterRepository 接口基于 GORM 的实现。
+type smsDeadLetterRepository struct {
+	db *gorm.DB // db 是 GORM 数据库连接实例
+}
+
+// NewSMSDeadLetterRepository 创建一个新的 smsDeadLetterRepository 实例。
+// - 依赖注入 GORM 数据库连接。
+func NewSMSDeadLetterRepository(db *gorm.DB) SMSDeadLetterRepository {
+	return &smsDeadLetterRepository{db: db}
+}
+
+// Create 实现接口方法，写入死信记录。
+func (r *smsDeadLetterRepository) Create(ctx context.Context, db *gorm.DB, record *entities.SMSDeadLetter) error {
+	if err := db.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("smsDeadLetterRepo.Create: 写入短信死信记录失败 (手机号: %s): %w", record.Phone, err)
+	}
+	return nil
+}
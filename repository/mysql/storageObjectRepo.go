@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StorageObjectRepository 定义了用户上传对象记录的存储接口，用于统计存储用量。
+type StorageObjectRepository interface {
+	// SumUsageByUserID 返回指定用户所有对象的总字节数，没有记录时返回 0。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	SumUsageByUserID(ctx context.Context, userID string) (int64, error)
+
+	// GetObject 按对象键查询对象记录。
+	// - 如果记录不存在，返回 commonerrors.ErrRepoNotFound。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	GetObject(ctx context.Context, objectKey string) (*entities.StorageObject, error)
+
+	// UpsertObject 记录一个对象；对象键已存在 (覆盖写入) 时更新其大小与归属。
+	// - 使用传入的 db 执行，调用方可传入事务对象。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	UpsertObject(ctx context.Context, db *gorm.DB, object *entities.StorageObject) error
+
+	// DeleteObject 删除对象键对应的记录，记录不存在时视为成功。
+	// - 使用传入的 db 执行，调用方可传入事务对象。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	DeleteObject(ctx context.Context, db *gorm.DB, objectKey string) error
+
+	// DeleteObjectsByUserID 删除指定用户的全部对象记录，没有记录时视为成功。
+	// - 使用传入的 db 执行，调用方可传入事务对象。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	DeleteObjectsByUserID(ctx context.Context, db *gorm.DB, userID string) error
+}
+
+// storageObjectRepository 是 StorageObjectRepository 接口基于 GORM 的实现。
+type storageObjectRepository struct {
+	db *gorm.DB // db 是 GORM 数据库连接实例
+}
+
+// NewStorageObjectRepository 创建一个新的 storageObjectRepository 实例。
+// - 依赖注入 GORM 数据库连接。
+func NewStorageObjectRepository(db *gorm.DB) StorageObjectRepository {
+	return &storageObjectRepository{db: db}
+}
+
+// SumUsageByUserID 实现接口方法，汇总用户的存储用量。
+func (r *storageObjectRepository) SumUsageByUserID(ctx context.Context, userID string) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).
+		Model(&entities.StorageObject{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(size_bytes), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("storageObjectRepo.SumUsageByUserID: 汇总存储用量失败 (用户ID: %s): %w", userID, err)
+	}
+	return total, nil
+}
+
+// GetObject 实现接口方法，按对象键查询对象记录。
+func (r *storageObjectRepository) GetObject(ctx context.Context, objectKey string) (*entities.StorageObject, error) {
+	var object entities.StorageObject
+	if err := r.db.WithContext(ctx).Where("object_key = ?", objectKey).First(&object).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commonerrors.ErrRepoNotFound
+		}
+		return nil, fmt.Errorf("storageObjectRepo.GetObject: 查询上传对象记录失败 (对象键: %s): %w", objectKey, err)
+	}
+	return &object, nil
+}
+
+// UpsertObject 实现接口方法，写入或更新对象记录。
+func (r *storageObjectRepository) UpsertObject(ctx context.Context, db *gorm.DB, object *entities.StorageObject) error {
+	err := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "object_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "size_bytes", "updated_at"}),
+		}).
+		Create(object).Error
+	if err != nil {
+		return fmt.Errorf("storageObjectRepo.UpsertObject: 记录上传对象失败 (对象键: %s): %w", object.ObjectKey, err)
+	}
+	return nil
+}
+
+// DeleteObject 实现接口方法，删除对象记录。
+func (r *storageObjectRepository) DeleteObject(ctx context.Context, db *gorm.DB, objectKey string) error {
+	if err := db.WithContext(ctx).Where("object_key = ?", objectKey).Delete(&entities.StorageObject{}).Error; err != nil {
+		return fmt.Errorf("storageObjectRepo.DeleteObject: 删除上传对象记录失败 (对象键: %s): %w", objectKey, err)
+	}
+	return nil
+}
+
+// DeleteObjectsByUserID 实现接口方法，删除用户的全部对象记录。
+func (r *storageObjectRepository) DeleteObjectsByUserID(ctx context.Context, db *gorm.DB, userID string) error {
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.StorageObject{}).Error; err != nil {
+		return fmt.Errorf("storageObjectRepo.DeleteObjectsByUserID: 删除用户的上传对象记录失败 (用户ID: %s): %w", userID, err)
+	}
+	return nil
+}
@@ -26,10 +26,16 @@ type UserRepository interface {
 	// - 其他数据库错误将被包装后返回。
 	GetUserByID(ctx context.Context, userID string) (*entities.User, error)
 
+	// GetUserByIDIncludingDeleted 与 GetUserByID 相同，但不排除已软删除的用户 (使用 Unscoped)。
+	// - 仅用于管理端查看已删除账号，业务流程应继续使用 GetUserByID。
+	// - 如果未找到匹配的用户，将返回 commonerrors.ErrRepoNotFound。
+	GetUserByIDIncludingDeleted(ctx context.Context, userID string) (*entities.User, error)
+
 	// UpdateUser 更新一个已存在的核心用户信息。
 	// - 注意：此方法当前使用 GORM 的 Updates，通常只更新非零值字段。服务层应确保传入的实体是期望的状态，或考虑使用 Select 指定更新字段。
+	// - 使用传入的 db 执行，使其能够参与外部事务。
 	// - 如果数据库操作失败，则返回包装后的错误。
-	UpdateUser(ctx context.Context, user *entities.User) error
+	UpdateUser(ctx context.Context, db *gorm.DB, user *entities.User) error
 
 	// DeleteUser 根据用户 ID（软）删除一个核心用户记录。
 	// - GORM 的 Delete 默认执行软删除（如果模型包含 gorm.DeletedAt）。
@@ -38,8 +44,23 @@ type UserRepository interface {
 
 	// BlackUser 将指定用户 ID 的状态更新为“拉黑”。
 	// - 直接更新 status 字段。
+	// - 使用传入的 db 执行，使其能够参与外部事务。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	BlackUser(ctx context.Context, db *gorm.DB, userID string) error
+
+	// UpdateUserStatus 将指定用户 ID 的状态更新为给定值（如用户自行停用/重新激活账号）。
+	// - 使用传入的 db 执行，使其能够参与外部事务。
 	// - 如果数据库操作失败，则返回包装后的错误。
-	BlackUser(ctx context.Context, userID string) error
+	UpdateUserStatus(ctx context.Context, db *gorm.DB, userID string, status enums.UserStatus) error
+
+	// ListUsersByIDs 批量查询指定 ID 的用户 (不含已软删除的用户)，不存在的 ID 不会出现在结果中。
+	// - 如果数据库查询失败，则返回包装后的错误。
+	ListUsersByIDs(ctx context.Context, userIDs []string) ([]entities.User, error)
+
+	// UpdateUserRoles 将指定 ID 的用户角色批量更新为给定值。
+	// - 使用传入的 db 执行，使其能够参与外部事务。
+	// - 如果数据库操作失败，则返回包装后的错误。
+	UpdateUserRoles(ctx context.Context, db *gorm.DB, userIDs []string, role enums.UserRole) error
 }
 
 // userRepository 是 UserRepository 接口基于 GORM 的实现。
@@ -83,12 +104,25 @@ func (r *userRepository) GetUserByID(ctx context.Context, userID string) (*entit
 	return &user, nil
 }
 
+// GetUserByIDIncludingDeleted 实现接口方法，查询用户时包含已软删除的记录。
+func (r *userRepository) GetUserByIDIncludingDeleted(ctx context.Context, userID string) (*entities.User, error) {
+	var user entities.User
+	err := r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commonerrors.ErrRepoNotFound
+		}
+		return nil, fmt.Errorf("userRepo.GetUserByIDIncludingDeleted: 查询用户失败 (UserID: %s): %w", userID, err)
+	}
+	return &user, nil
+}
+
 // UpdateUser 实现接口方法，更新用户信息。
-func (r *userRepository) UpdateUser(ctx context.Context, user *entities.User) error {
+func (r *userRepository) UpdateUser(ctx context.Context, db *gorm.DB, user *entities.User) error {
 	// 使用 GORM 的 Updates 方法更新用户记录，通常只更新非零值字段。
 	// Model(&entities.User{UserID: userManage.UserID}) 指定了更新条件基于主键。
 	// Updates(userManage) 传入包含待更新字段的实体。
-	result := r.db.WithContext(ctx).Model(&entities.User{UserID: user.UserID}).Updates(user)
+	result := db.WithContext(ctx).Model(&entities.User{UserID: user.UserID}).Updates(user)
 	if result.Error != nil {
 		// 包装更新操作时发生的错误，添加中文上下文信息
 		return fmt.Errorf("userRepo.UpdateUser: 更新用户信息失败 (UserID: %s): %w", user.UserID, result.Error)
@@ -123,9 +157,9 @@ func (r *userRepository) DeleteUser(ctx context.Context, db *gorm.DB, userID str
 }
 
 // BlackUser 实现接口方法，设置用户为黑名单状态。
-func (r *userRepository) BlackUser(ctx context.Context, userID string) error {
+func (r *userRepository) BlackUser(ctx context.Context, db *gorm.DB, userID string) error {
 	// 使用 GORM 的 Update 方法更新单个字段 'status'
-	result := r.db.WithContext(ctx).Model(&entities.User{}).Where("user_id = ?", userID).Update("status", enums.StatusBlacklisted)
+	result := db.WithContext(ctx).Model(&entities.User{}).Where("user_id = ?", userID).Update("status", enums.StatusBlacklisted)
 	if result.Error != nil {
 		// 包装更新状态操作时发生的错误，添加中文上下文信息
 		return fmt.Errorf("userRepo.BlackUser: 拉黑用户失败 (UserID: %s): %w", userID, result.Error)
@@ -137,3 +171,36 @@ func (r *userRepository) BlackUser(ctx context.Context, userID string) error {
 	// 操作成功，返回 nil
 	return nil
 }
+
+// UpdateUserStatus 实现接口方法，更新用户状态字段。
+func (r *userRepository) UpdateUserStatus(ctx context.Context, db *gorm.DB, userID string, status enums.UserStatus) error {
+	result := db.WithContext(ctx).Model(&entities.User{}).Where("user_id = ?", userID).Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("userRepo.UpdateUserStatus: 更新用户状态失败 (UserID: %s, Status: %d): %w", userID, status, result.Error)
+	}
+	return nil
+}
+
+// ListUsersByIDs 实现接口方法，批量查询用户。
+func (r *userRepository) ListUsersByIDs(ctx context.Context, userIDs []string) ([]entities.User, error) {
+	var users []entities.User
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+	if err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("userRepo.ListUsersByIDs: 批量查询用户失败 (数量: %d): %w", len(userIDs), err)
+	}
+	return users, nil
+}
+
+// UpdateUserRoles 实现接口方法，批量更新用户角色。
+func (r *userRepository) UpdateUserRoles(ctx context.Context, db *gorm.DB, userIDs []string, role enums.UserRole) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	result := db.WithContext(ctx).Model(&entities.User{}).Where("user_id IN ?", userIDs).Update("user_role", role)
+	if result.Error != nil {
+		return fmt.Errorf("userRepo.UpdateUserRoles: 批量更新用户角色失败 (数量: %d, Role: %d): %w", len(userIDs), role, result.Error)
+	}
+	return nil
+}
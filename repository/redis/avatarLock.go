@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// releaseOwnedLockScript 仅当锁仍由自己持有时才删除，避免误删锁过期后被其他请求重新获取的锁
+var releaseOwnedLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AvatarLockRepo 定义了按用户互斥头像上传的 Redis 锁接口。
+// - 同一用户的并发上传会在资料 Save 上竞争，后写入者覆盖先写入者的 URL，导致其中一个对象成为孤立文件；按用户加锁使上传串行化。
+type AvatarLockRepo interface {
+	// Acquire 尝试获取指定用户的头像上传锁，ttl 为锁的最长持有时间 (防止进程异常退出后锁残留)。
+	// - 获取成功返回 (持有者令牌, true, nil)，令牌用于释放锁；锁已被占用返回 ("", false, nil)。
+	// - 其他 Redis 错误将被包装后返回。
+	Acquire(ctx context.Context, userID string, ttl time.Duration) (string, bool, error)
+
+	// Release 释放由 token 持有的头像上传锁；锁已过期或被他人持有时不做任何操作。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	Release(ctx context.Context, userID string, token string) error
+}
+
+// avatarLockRepo 是 AvatarLockRepo 接口基于 go-redis/v9 的实现。
+type avatarLockRepo struct {
+	client *redis.Client // client 是 Redis v9 客户端实例
+}
+
+// NewAvatarLockRepo 创建一个新的 avatarLockRepo 实例。
+func NewAvatarLockRepo(client *redis.Client) AvatarLockRepo {
+	return &avatarLockRepo{client: client}
+}
+
+// buildKey 示例键: "avatar_upload:lock:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+func (r *avatarLockRepo) buildKey(userID string) string {
+	return constants.AvatarUploadLockKeyPrefix + ":" + userID
+}
+
+// Acquire 实现接口方法。
+func (r *avatarLockRepo) Acquire(ctx context.Context, userID string, ttl time.Duration) (string, bool, error) {
+	token := uuid.New().String()
+	acquired, err := r.client.SetNX(ctx, r.buildKey(userID), token, ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("avatarLockRepo.Acquire: 获取头像上传锁失败 (UserID: %s): %w", userID, err)
+	}
+	if !acquired {
+		return "", false, nil
+	}
+	return token, true, nil
+}
+
+// Release 实现接口方法。
+func (r *avatarLockRepo) Release(ctx context.Context, userID string, token string) error {
+	if err := releaseOwnedLockScript.Run(ctx, r.client, []string{r.buildKey(userID)}, token).Err(); err != nil {
+		return fmt.Errorf("avatarLockRepo.Release: 释放头像上传锁失败 (UserID: %s): %w", userID, err)
+	}
+	return nil
+}
@@ -10,8 +10,72 @@ import (
 	"github.com/redis/go-redis/v9"
 	// 引入你的公共错误包
 	"github.com/Xushengqwer/go-common/commonerrors"
+
+	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// 验证码发送额度检查的失败原因
+var (
+	ErrCaptchaCooldown    = errors.New("验证码发送过于频繁")     // 仍处于冷却期内
+	ErrCaptchaDailyLimit  = errors.New("今日验证码发送次数已达上限") // 已达每日发送上限
+	ErrCaptchaGlobalLimit = errors.New("验证码发送总量已达上限")   // 所有手机号合计的发送量已达全局上限
 )
 
+// reserveSendScript 原子地检查冷却期与每日计数，通过时写入冷却键并递增计数。
+// KEYS[1]: 冷却键, KEYS[2]: 每日计数键
+// ARGV[1]: 冷却毫秒数, ARGV[2]: 每日上限, ARGV[3]: 计数键过期秒数
+// 返回 {状态, 需等待毫秒数, 当日已用次数, 计数键剩余毫秒数}，状态 0=通过, 1=冷却中, 2=已达每日上限。
+var reserveSendScript = redis.NewScript(`
+local count = tonumber(redis.call('GET', KEYS[2]) or '0')
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	return {1, ttl, count, redis.call('PTTL', KEYS[2])}
+end
+if count >= tonumber(ARGV[2]) then
+	local dailyTTL = redis.call('PTTL', KEYS[2])
+	return {2, dailyTTL, count, dailyTTL}
+end
+count = redis.call('INCR', KEYS[2])
+if count == 1 then
+	redis.call('EXPIRE', KEYS[2], ARGV[3])
+end
+redis.call('SET', KEYS[1], '1', 'PX', ARGV[1])
+return {0, 0, count, redis.call('PTTL', KEYS[2])}
+`)
+
+// reserveGlobalSendScript 原子地检查并递增全局 (所有手机号合计) 的每分钟、每小时发送计数。
+// KEYS[1]: 当前分钟计数键, KEYS[2]: 当前小时计数键
+// ARGV[1]: 每分钟上限, ARGV[2]: 每小时上限 (<=0 表示该窗口不限制)
+// 返回 {状态, 需等待毫秒数}，状态 0=通过, 1=已达全局上限 (本次不计数)。
+var reserveGlobalSendScript = redis.NewScript(`
+local wait = 0
+for i = 1, 2 do
+	local limit = tonumber(ARGV[i])
+	if limit > 0 and tonumber(redis.call('GET', KEYS[i]) or '0') >= limit then
+		wait = math.max(wait, redis.call('PTTL', KEYS[i]))
+	end
+end
+if wait > 0 then
+	return {1, wait}
+end
+local ttls = {60, 3600}
+for i = 1, 2 do
+	if tonumber(ARGV[i]) > 0 and redis.call('INCR', KEYS[i]) == 1 then
+		redis.call('EXPIRE', KEYS[i], ttls[i])
+	end
+end
+return {0, 0}
+`)
+
+// SendQuota 描述一次验证码发送额度检查后的配额状态，用于向客户端输出限流响应头。
+type SendQuota struct {
+	Limit     int           // 每日发送上限
+	Remaining int           // 当日剩余可发送次数
+	Reset     time.Duration // 距每日计数重置的时长，当日尚未发送时为 0
+	Wait      time.Duration // 被拒绝时需要等待的时长，通过时为 0
+}
+
 // CodeRepo 定义了与 Redis 中存储验证码相关的操作接口。
 // - 它封装了 Redis 的具体命令，提供标准化的验证码管理方法。
 type CodeRepo interface {
@@ -29,18 +93,32 @@ type CodeRepo interface {
 	// - 通常在验证码成功使用后调用，防止重复使用。
 	// - 如果 Redis 操作失败，则返回包装后的错误。
 	DeleteCaptcha(ctx context.Context, phone string) error
+
+	// ReserveSend 为一次验证码发送预占额度：检查同一手机号的冷却期与当日发送次数。
+	// - 无论是否通过都返回当前的配额状态 (上限、剩余次数、重置时间)。
+	// - 通过时开始新的冷却期并计入当日次数，返回的 Wait 为 0。
+	// - 冷却中返回 ErrCaptchaCooldown，已达上限返回 ErrCaptchaDailyLimit，Wait 为需要等待的时长。
+	// - 其他 Redis 错误将被包装后返回。
+	ReserveSend(ctx context.Context, phone string, cooldown time.Duration, dailyLimit int) (SendQuota, error)
+
+	// ReserveGlobalSend 为一次验证码发送预占全局额度：所有手机号合计的每分钟、每小时发送次数。
+	// - 上限 <=0 表示对应窗口不限制。
+	// - 已达任一上限时返回 ErrCaptchaGlobalLimit 及需要等待的时长，本次不计数。
+	// - 其他 Redis 错误将被包装后返回。
+	ReserveGlobalSend(ctx context.Context, perMinute int, perHour int) (time.Duration, error)
 }
 
 // codeRepo 是 CodeRepo 接口基于 go-redis/v9 的实现。
 type codeRepo struct {
 	// 注意：字段类型改为 *redis.Client (v9 版本)
 	client *redis.Client // client 是 Redis v9 客户端实例
+	clock  utils.Clock   // clock 决定每日、每分钟、每小时计数键所属的自然时间窗口
 }
 
 // NewCodeRepo 创建一个新的 codeRepo 实例。
-// - 依赖注入 Redis v9 客户端。
-func NewCodeRepo(client *redis.Client) CodeRepo {
-	return &codeRepo{client: client}
+// - 依赖注入 Redis v9 客户端与时间来源。
+func NewCodeRepo(client *redis.Client, clock utils.Clock) CodeRepo {
+	return &codeRepo{client: client, clock: clock}
 }
 
 // buildKey 根据手机号生成用于 Redis 操作的键名。
@@ -99,3 +177,50 @@ func (r *codeRepo) DeleteCaptcha(ctx context.Context, phone string) error {
 	// 操作成功（或 key 本就不存在），返回 nil
 	return nil
 }
+
+// ReserveSend 实现接口方法，原子地检查并预占验证码发送额度。
+// - 每日计数键按自然日区分，例如 "captcha_send:daily:13800138000:20240101"。
+func (r *codeRepo) ReserveSend(ctx context.Context, phone string, cooldown time.Duration, dailyLimit int) (SendQuota, error) {
+	cooldownKey := constants.CaptchaSendKeyPrefix + ":cooldown:" + phone
+	dailyKey := constants.CaptchaSendKeyPrefix + ":daily:" + phone + ":" + r.clock.Now().Format("20060102")
+
+	res, err := reserveSendScript.Run(ctx, r.client,
+		[]string{cooldownKey, dailyKey},
+		cooldown.Milliseconds(), dailyLimit, int64((24 * time.Hour).Seconds()),
+	).Int64Slice()
+	if err != nil {
+		return SendQuota{}, fmt.Errorf("codeRepo.ReserveSend: 检查验证码发送额度失败 (手机号: %s): %w", phone, err)
+	}
+
+	quota := SendQuota{
+		Limit:     dailyLimit,
+		Remaining: max(dailyLimit-int(res[2]), 0),
+		Reset:     time.Duration(max(res[3], 0)) * time.Millisecond, // 计数键不存在时 PTTL 为负数
+		Wait:      time.Duration(res[1]) * time.Millisecond,
+	}
+	switch res[0] {
+	case 1:
+		return quota, ErrCaptchaCooldown
+	case 2:
+		return quota, ErrCaptchaDailyLimit
+	default:
+		return quota, nil
+	}
+}
+
+// ReserveGlobalSend 实现接口方法，原子地检查并预占全局验证码发送额度。
+// - 计数键按自然分钟、自然小时区分，例如 "captcha_send:global:minute:202401011230"。
+func (r *codeRepo) ReserveGlobalSend(ctx context.Context, perMinute int, perHour int) (time.Duration, error) {
+	now := r.clock.Now()
+	minuteKey := constants.CaptchaSendKeyPrefix + ":global:minute:" + now.Format("200601021504")
+	hourKey := constants.CaptchaSendKeyPrefix + ":global:hour:" + now.Format("2006010215")
+
+	res, err := reserveGlobalSendScript.Run(ctx, r.client, []string{minuteKey, hourKey}, perMinute, perHour).Int64Slice()
+	if err != nil {
+		return 0, fmt.Errorf("codeRepo.ReserveGlobalSend: 检查全局验证码发送额度失败: %w", err)
+	}
+	if res[0] == 1 {
+		return time.Duration(res[1]) * time.Millisecond, ErrCaptchaGlobalLimit
+	}
+	return 0, nil
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// ErrPasswordVerifyLimit 校验当前密码的次数已达当前窗口上限
+var ErrPasswordVerifyLimit = errors.New("密码校验过于频繁")
+
+// reserveAttemptScript 原子地检查并递增固定窗口内的尝试次数，窗口从第一次尝试开始计时。
+// KEYS[1]: 计数键
+// ARGV[1]: 窗口内上限, ARGV[2]: 窗口毫秒数
+// 返回 {状态, 窗口内已用次数, 计数键剩余毫秒数}，状态 0=通过, 1=已达上限 (本次不计数)。
+var reserveAttemptScript = redis.NewScript(`
+local count = tonumber(redis.call('GET', KEYS[1]) or '0')
+if count >= tonumber(ARGV[1]) then
+	return {1, count, redis.call('PTTL', KEYS[1])}
+end
+count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return {0, count, redis.call('PTTL', KEYS[1])}
+`)
+
+// AttemptQuota 描述一次尝试额度检查后的配额状态，用于向客户端输出限流响应头。
+type AttemptQuota struct {
+	Limit     int           // 窗口内允许的尝试次数
+	Remaining int           // 窗口内剩余的尝试次数
+	Reset     time.Duration // 距窗口重置的时长；被拒绝时即需要等待的时长
+}
+
+// PasswordVerifyRepo 定义了“校验当前密码”接口按用户限流的存储接口。
+// - 每次尝试 (无论密码是否正确) 都计入次数，避免该接口被用作猜测密码的工具。
+type PasswordVerifyRepo interface {
+	// ReserveAttempt 为指定用户预占一次校验额度。
+	// - 无论是否通过都返回当前的配额状态。
+	// - 已达上限时返回 ErrPasswordVerifyLimit，本次不计入次数。
+	// - 其他 Redis 错误将被包装后返回。
+	ReserveAttempt(ctx context.Context, userID string, limit int, window time.Duration) (AttemptQuota, error)
+}
+
+// passwordVerifyRepo 是 PasswordVerifyRepo 接口基于 go-redis/v9 的实现。
+type passwordVerifyRepo struct {
+	client *redis.Client // client 是 Redis v9 客户端实例
+}
+
+// NewPasswordVerifyRepo 创建一个新的 passwordVerifyRepo 实例。
+func NewPasswordVerifyRepo(client *redis.Client) PasswordVerifyRepo {
+	return &passwordVerifyRepo{client: client}
+}
+
+// buildKey 示例键: "password_verify:attempts:<userID>"
+func (r *passwordVerifyRepo) buildKey(userID string) string {
+	return constants.PasswordVerifyKeyPrefix + ":attempts:" + userID
+}
+
+// ReserveAttempt 实现接口方法。
+func (r *passwordVerifyRepo) ReserveAttempt(ctx context.Context, userID string, limit int, window time.Duration) (AttemptQuota, error) {
+	res, err := reserveAttemptScript.Run(ctx, r.client, []string{r.buildKey(userID)}, limit, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return AttemptQuota{}, fmt.Errorf("passwordVerifyRepo.ReserveAttempt: 检查密码校验额度失败 (UserID: %s): %w", userID, err)
+	}
+
+	quota := AttemptQuota{
+		Limit:     limit,
+		Remaining: max(limit-int(res[1]), 0),
+		Reset:     time.Duration(max(res[2], 0)) * time.Millisecond,
+	}
+	if res[0] == 1 {
+		return quota, ErrPasswordVerifyLimit
+	}
+	return quota, nil
+}
@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// 资料修改冷却的类别：资料字段与头像分别计时
+const (
+	ProfileCooldownFields = "fields" // 昵称、性别、地区等资料字段
+	ProfileCooldownAvatar = "avatar" // 头像
+)
+
+// ProfileCooldownRepo 定义了用户资料修改冷却时间的存储接口。
+// - 每次修改成功后写入一个带过期时间的键，键存在期间视为处于冷却中。
+type ProfileCooldownRepo interface {
+	// Remaining 返回指定用户某类修改的剩余冷却时间，不在冷却中时返回 0。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	Remaining(ctx context.Context, userID string, kind string) (time.Duration, error)
+
+	// Start 为指定用户某类修改开始计时，cooldown 后自动过期。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	Start(ctx context.Context, userID string, kind string, cooldown time.Duration) error
+}
+
+// profileCooldownRepo 是 ProfileCooldownRepo 接口基于 go-redis/v9 的实现。
+type profileCooldownRepo struct {
+	client *redis.Client // client 是 Redis v9 客户端实例
+}
+
+// NewProfileCooldownRepo 创建一个新的 profileCooldownRepo 实例。
+func NewProfileCooldownRepo(client *redis.Client) ProfileCooldownRepo {
+	return &profileCooldownRepo{client: client}
+}
+
+// buildKey 示例键: "profile_update:cooldown:avatar:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+func (r *profileCooldownRepo) buildKey(userID string, kind string) string {
+	return constants.ProfileCooldownKeyPrefix + ":" + kind + ":" + userID
+}
+
+// Remaining 实现接口方法。
+func (r *profileCooldownRepo) Remaining(ctx context.Context, userID string, kind string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, r.buildKey(userID, kind)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("profileCooldownRepo.Remaining: 查询资料修改冷却时间失败 (UserID: %s, Kind: %s): %w", userID, kind, err)
+	}
+	// 键不存在 (-2) 或没有过期时间 (-1) 均视为不在冷却中
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Start 实现接口方法。
+func (r *profileCooldownRepo) Start(ctx context.Context, userID string, kind string, cooldown time.Duration) error {
+	if err := r.client.Set(ctx, r.buildKey(userID, kind), time.Now().Unix(), cooldown).Err(); err != nil {
+		return fmt.Errorf("profileCooldownRepo.Start: 写入资料修改冷却时间失败 (UserID: %s, Kind: %s): %w", userID, kind, err)
+	}
+	return nil
+}
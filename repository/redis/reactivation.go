@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// ReactivationRepo 定义了账号重新激活凭证的存储接口。
+// - 已停用的用户通过登录校验（证明身份）后获得一次性凭证，凭此调用重新激活接口。
+type ReactivationRepo interface {
+	// SaveTicket 保存凭证与用户 ID 的对应关系，并设置有效期。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	SaveTicket(ctx context.Context, ticket string, userID string, ttl time.Duration) error
+
+	// ConsumeTicket 读取并删除凭证（GETDEL，保证只能使用一次），返回对应的用户 ID。
+	// - 凭证不存在或已过期时返回 commonerrors.ErrRepoNotFound。
+	// - 其他 Redis 错误将被包装后返回。
+	ConsumeTicket(ctx context.Context, ticket string) (string, error)
+}
+
+// reactivationRepo 是 ReactivationRepo 接口基于 go-redis/v9 的实现。
+type reactivationRepo struct {
+	client *redis.Client // client 是 Redis v9 客户端实例
+}
+
+// NewReactivationRepo 创建一个新的 reactivationRepo 实例。
+func NewReactivationRepo(client *redis.Client) ReactivationRepo {
+	return &reactivationRepo{client: client}
+}
+
+// buildKey 示例键: "reactivation:ticket:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+func (r *reactivationRepo) buildKey(ticket string) string {
+	return constants.ReactivationKeyPrefix + ":ticket:" + ticket
+}
+
+// SaveTicket 实现接口方法。
+func (r *reactivationRepo) SaveTicket(ctx context.Context, ticket string, userID string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, r.buildKey(ticket), userID, ttl).Err(); err != nil {
+		return fmt.Errorf("reactivationRepo.SaveTicket: 保存重新激活凭证失败 (UserID: %s): %w", userID, err)
+	}
+	return nil
+}
+
+// ConsumeTicket 实现接口方法。
+func (r *reactivationRepo) ConsumeTicket(ctx context.Context, ticket string) (string, error) {
+	userID, err := r.client.GetDel(ctx, r.buildKey(ticket)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", commonerrors.ErrRepoNotFound
+		}
+		return "", fmt.Errorf("reactivationRepo.ConsumeTicket: 读取重新激活凭证失败: %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// RecentAuthRepo 定义了“近期已重新验证身份”标记的存储接口。
+// - 用户完成二次验证 (如校验当前密码) 后写入一个带过期时间的键，键存在期间视为近期已验证。
+// - 修改敏感资料等操作据此判断是否需要用户先重新验证。
+type RecentAuthRepo interface {
+	// Mark 记录指定用户刚完成重新验证，window 后自动过期。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	Mark(ctx context.Context, userID string, window time.Duration) error
+
+	// IsRecent 返回指定用户是否处于重新验证后的有效期内。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	IsRecent(ctx context.Context, userID string) (bool, error)
+}
+
+// recentAuthRepo 是 RecentAuthRepo 接口基于 go-redis/v9 的实现。
+type recentAuthRepo struct {
+	client *redis.Client // client 是 Redis v9 客户端实例
+}
+
+// NewRecentAuthRepo 创建一个新的 recentAuthRepo 实例。
+func NewRecentAuthRepo(client *redis.Client) RecentAuthRepo {
+	return &recentAuthRepo{client: client}
+}
+
+// buildKey 示例键: "recent_auth:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+func (r *recentAuthRepo) buildKey(userID string) string {
+	return constants.RecentAuthKeyPrefix + ":" + userID
+}
+
+// Mark 实现接口方法。
+func (r *recentAuthRepo) Mark(ctx context.Context, userID string, window time.Duration) error {
+	if err := r.client.Set(ctx, r.buildKey(userID), time.Now().Unix(), window).Err(); err != nil {
+		return fmt.Errorf("recentAuthRepo.Mark: 写入重新验证标记失败 (UserID: %s): %w", userID, err)
+	}
+	return nil
+}
+
+// IsRecent 实现接口方法。
+func (r *recentAuthRepo) IsRecent(ctx context.Context, userID string) (bool, error) {
+	n, err := r.client.Exists(ctx, r.buildKey(userID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("recentAuthRepo.IsRecent: 查询重新验证标记失败 (UserID: %s): %w", userID, err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// RecoveryRepo 定义了账号找回凭证的存储接口。
+// - 用户通过另一个已验证身份证明归属后获得短期凭证，只能用于重置密码或添加新的登录方式。
+type RecoveryRepo interface {
+	// SaveToken 保存凭证与用户 ID 的对应关系，并设置有效期。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	SaveToken(ctx context.Context, token string, userID string, ttl time.Duration) error
+
+	// PeekToken 读取凭证对应的用户 ID 但不删除，用于在执行操作前校验凭证。
+	// - 凭证不存在或已过期时返回 commonerrors.ErrRepoNotFound。
+	PeekToken(ctx context.Context, token string) (string, error)
+
+	// ConsumeToken 读取并删除凭证（GETDEL，保证只能使用一次），返回对应的用户 ID。
+	// - 凭证不存在或已过期时返回 commonerrors.ErrRepoNotFound。
+	// - 其他 Redis 错误将被包装后返回。
+	ConsumeToken(ctx context.Context, token string) (string, error)
+}
+
+// recoveryRepo 是 RecoveryRepo 接口基于 go-redis/v9 的实现。
+type recoveryRepo struct {
+	client *redis.Client // client 是 Redis v9 客户端实例
+}
+
+// NewRecoveryRepo 创建一个新的 recoveryRepo 实例。
+func NewRecoveryRepo(client *redis.Client) RecoveryRepo {
+	return &recoveryRepo{client: client}
+}
+
+// buildKey 示例键: "recovery:token:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+func (r *recoveryRepo) buildKey(token string) string {
+	return constants.RecoveryKeyPrefix + ":token:" + token
+}
+
+// SaveToken 实现接口方法。
+func (r *recoveryRepo) SaveToken(ctx context.Context, token string, userID string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, r.buildKey(token), userID, ttl).Err(); err != nil {
+		return fmt.Errorf("recoveryRepo.SaveToken: 保存账号找回凭证失败 (UserID: %s): %w", userID, err)
+	}
+	return nil
+}
+
+// PeekToken 实现接口方法。
+func (r *recoveryRepo) PeekToken(ctx context.Context, token string) (string, error) {
+	userID, err := r.client.Get(ctx, r.buildKey(token)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", commonerrors.ErrRepoNotFound
+		}
+		return "", fmt.Errorf("recoveryRepo.PeekToken: 读取账号找回凭证失败: %w", err)
+	}
+	return userID, nil
+}
+
+// ConsumeToken 实现接口方法。
+func (r *recoveryRepo) ConsumeToken(ctx context.Context, token string) (string, error) {
+	userID, err := r.client.GetDel(ctx, r.buildKey(token)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", commonerrors.ErrRepoNotFound
+		}
+		return "", fmt.Errorf("recoveryRepo.ConsumeToken: 读取账号找回凭证失败: %w", err)
+	}
+	return userID, nil
+}
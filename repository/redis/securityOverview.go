@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// SecurityOverviewCache 定义了账号安全概览的短期缓存接口。
+// - 概览需要聚合多张表的数据，安全页短时间内的重复访问直接命中缓存；值为序列化后的 JSON，由调用方负责编解码。
+type SecurityOverviewCache interface {
+	// Get 读取指定用户的缓存概览。
+	// - 缓存不存在或已过期时返回 commonerrors.ErrRepoNotFound。
+	// - 其他 Redis 错误将被包装后返回。
+	Get(ctx context.Context, userID string) ([]byte, error)
+
+	// Set 写入指定用户的概览并设置有效期。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	Set(ctx context.Context, userID string, data []byte, ttl time.Duration) error
+}
+
+// securityOverviewCache 是 SecurityOverviewCache 接口基于 go-redis/v9 的实现。
+type securityOverviewCache struct {
+	client *redis.Client // client 是 Redis v9 客户端实例
+}
+
+// NewSecurityOverviewCache 创建一个新的 securityOverviewCache 实例。
+func NewSecurityOverviewCache(client *redis.Client) SecurityOverviewCache {
+	return &securityOverviewCache{client: client}
+}
+
+// buildKey 示例键: "security_overview:user:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+func (r *securityOverviewCache) buildKey(userID string) string {
+	return constants.SecurityOverviewKeyPrefix + ":user:" + userID
+}
+
+// Get 实现接口方法。
+func (r *securityOverviewCache) Get(ctx context.Context, userID string) ([]byte, error) {
+	data, err := r.client.Get(ctx, r.buildKey(userID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, commonerrors.ErrRepoNotFound
+		}
+		return nil, fmt.Errorf("securityOverviewCache.Get: 读取安全概览缓存失败 (UserID: %s): %w", userID, err)
+	}
+	return data, nil
+}
+
+// Set 实现接口方法。
+func (r *securityOverviewCache) Set(ctx context.Context, userID string, data []byte, ttl time.Duration) error {
+	if err := r.client.Set(ctx, r.buildKey(userID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("securityOverviewCache.Set: 写入安全概览缓存失败 (UserID: %s): %w", userID, err)
+	}
+	return nil
+}
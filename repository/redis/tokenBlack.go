@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"fmt" // 引入 fmt 包用于错误包装
+	"sync/atomic"
 	"time"
 
 	// 使用 go-redis/v9
@@ -30,11 +31,56 @@ type TokenBlackRepo interface {
 	// - 返回: bool 值表示是否存在于黑名单，以及可能的查询错误。
 	// - 注意：此方法不返回 commonerrors.ErrRepoNotFound，因为 JTI 不存在于黑名单是预期情况，返回 false, nil。
 	IsJtiBlacklisted(ctx context.Context, jti string) (bool, error)
+
+	// AreJtisBlacklisted 通过一次 MGET 批量检查多个 JTI 是否存在于黑名单中，只产生一次网络往返。
+	// - 返回以 JTI 为键的结果，jtis 中的每个 JTI 都有对应的键；重复的 JTI 只查询一次。
+	// - jtis 为空时直接返回空 map，不访问 Redis。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	AreJtisBlacklisted(ctx context.Context, jtis []string) (map[string]bool, error)
+
+	// GetBlacklistTTL 查询指定 JTI 是否在黑名单中及其剩余存活时间，供运维排查使用，不计入命中/未命中计数。
+	// - 返回: 剩余存活时间、是否存在于黑名单；不存在时返回 0, false, nil。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	GetBlacklistTTL(ctx context.Context, jti string) (time.Duration, bool, error)
+
+	// RemoveJti 将指定 JTI 从黑名单中移除，使对应令牌 (若未过期) 重新可用。
+	// - 返回: 是否确实删除了记录；JTI 本就不在黑名单中时返回 false, nil。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	RemoveJti(ctx context.Context, jti string) (bool, error)
+
+	// Counters 返回本进程内的黑名单操作计数（JTI 写入、命中、未命中）。
+	// - 计数仅统计当前实例自启动以来的调用，多实例部署时需在监控系统中汇总。
+	Counters() BlacklistCounters
+
+	// EstimateSize 使用有界的 SCAN 估算黑名单中的 JTI 数量，避免 KEYS 等命令阻塞 Redis。
+	// - maxIterations: 最多执行的 SCAN 次数；batchSize: 每次 SCAN 的 COUNT 提示值。
+	// - 在预算内遍历完整个键空间时返回精确值；否则按已扫描部分的命中比例与 DBSIZE 外推。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	EstimateSize(ctx context.Context, maxIterations int, batchSize int64) (BlacklistSizeEstimate, error)
+}
+
+// BlacklistCounters 黑名单操作计数的快照
+type BlacklistCounters struct {
+	Added  int64 // Added: 成功加入黑名单的 JTI 数量
+	Hits   int64 // Hits: 检查时命中黑名单的次数
+	Misses int64 // Misses: 检查时未命中黑名单的次数
+}
+
+// BlacklistSizeEstimate 黑名单大小估算结果
+type BlacklistSizeEstimate struct {
+	Count       int64     // Count: 估算（或精确）的 JTI 数量
+	Exact       bool      // Exact: 是否在扫描预算内遍历完整个键空间
+	ScannedKeys int64     // ScannedKeys: 本次扫描检查过的键数量（近似值，基于 COUNT 提示）
+	EstimatedAt time.Time // EstimatedAt: 估算完成的时间
 }
 
 // tokenBlackRepo 是 TokenBlackRepo 接口基于 go-redis/v9 的实现。
 type tokenBlackRepo struct {
 	client *redis.Client // client 是 Redis v9 客户端实例
+
+	added  atomic.Int64 // added: 成功加入黑名单的 JTI 计数
+	hits   atomic.Int64 // hits: 黑名单命中计数
+	misses atomic.Int64 // misses: 黑名单未命中计数
 }
 
 // NewTokenBlacklistRepo 创建一个新的 tokenBlackRepo 实例。
@@ -67,10 +113,33 @@ func (r *tokenBlackRepo) AddJtiToBlacklist(ctx context.Context, jti string, ttl
 		// 包装 Redis SET 操作错误，添加中文上下文
 		return fmt.Errorf("tokenBlackRepo.AddJtiToBlacklist: 将 JTI 加入黑名单失败 (JTI: %s): %w", jti, err)
 	}
+	r.added.Add(1)
 	// 操作成功，返回 nil
 	return nil
 }
 
+// GetBlacklistTTL 实现接口方法，查询 JTI 在黑名单中的剩余存活时间。
+func (r *tokenBlackRepo) GetBlacklistTTL(ctx context.Context, jti string) (time.Duration, bool, error) {
+	ttl, err := r.client.PTTL(ctx, r.buildBlacklistKey(jti)).Result()
+	if err != nil {
+		return 0, false, fmt.Errorf("tokenBlackRepo.GetBlacklistTTL: 查询 JTI 黑名单存活时间失败 (JTI: %s): %w", jti, err)
+	}
+	// -2 表示键不存在；-1 表示键没有过期时间 (正常写入不会出现)，仍视为在黑名单中
+	if ttl == -2 {
+		return 0, false, nil
+	}
+	return max(ttl, 0), true, nil
+}
+
+// RemoveJti 实现接口方法，将 JTI 从黑名单中移除。
+func (r *tokenBlackRepo) RemoveJti(ctx context.Context, jti string) (bool, error) {
+	deleted, err := r.client.Del(ctx, r.buildBlacklistKey(jti)).Result()
+	if err != nil {
+		return false, fmt.Errorf("tokenBlackRepo.RemoveJti: 将 JTI 移出黑名单失败 (JTI: %s): %w", jti, err)
+	}
+	return deleted > 0, nil
+}
+
 // IsJtiBlacklisted 实现接口方法，检查 JTI 是否在黑名单中。
 func (r *tokenBlackRepo) IsJtiBlacklisted(ctx context.Context, jti string) (bool, error) {
 	key := r.buildBlacklistKey(jti)
@@ -81,7 +150,12 @@ func (r *tokenBlackRepo) IsJtiBlacklisted(ctx context.Context, jti string) (bool
 		return false, fmt.Errorf("tokenBlackRepo.IsJtiBlacklisted: 检查 JTI 黑名单失败 (JTI: %s): %w", jti, err)
 	}
 	// Exists 返回 1 表示存在（在黑名单中），返回 0 表示不存在
-	return exists == 1, nil
+	if exists == 1 {
+		r.hits.Add(1)
+		return true, nil
+	}
+	r.misses.Add(1)
+	return false, nil
 
 	/*
 	   // 或者使用 GET 的方式（如果需要获取值，但这里不需要）:
@@ -98,3 +172,79 @@ func (r *tokenBlackRepo) IsJtiBlacklisted(ctx context.Context, jti string) (bool
 	   return true, nil
 	*/
 }
+
+// AreJtisBlacklisted 实现接口方法，批量检查 JTI 是否在黑名单中。
+func (r *tokenBlackRepo) AreJtisBlacklisted(ctx context.Context, jtis []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(jtis))
+	unique := make([]string, 0, len(jtis))
+	for _, jti := range jtis {
+		if _, seen := result[jti]; !seen {
+			result[jti] = false
+			unique = append(unique, jti)
+		}
+	}
+	if len(unique) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, len(unique))
+	for i, jti := range unique {
+		keys[i] = r.buildBlacklistKey(jti)
+	}
+	// 黑名单值固定为非空字符串，MGET 中值为 nil 即表示不在黑名单
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("tokenBlackRepo.AreJtisBlacklisted: 批量检查 JTI 黑名单失败 (数量: %d): %w", len(unique), err)
+	}
+
+	for i, v := range values {
+		if v != nil {
+			result[unique[i]] = true
+			r.hits.Add(1)
+		} else {
+			r.misses.Add(1)
+		}
+	}
+	return result, nil
+}
+
+// Counters 实现接口方法，返回计数快照。
+func (r *tokenBlackRepo) Counters() BlacklistCounters {
+	return BlacklistCounters{
+		Added:  r.added.Load(),
+		Hits:   r.hits.Load(),
+		Misses: r.misses.Load(),
+	}
+}
+
+// EstimateSize 实现接口方法，有界扫描并估算黑名单大小。
+func (r *tokenBlackRepo) EstimateSize(ctx context.Context, maxIterations int, batchSize int64) (BlacklistSizeEstimate, error) {
+	pattern := r.buildBlacklistKey("*")
+
+	var cursor uint64
+	var matched, scanned int64
+	for i := 0; i < maxIterations; i++ {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, batchSize).Result()
+		if err != nil {
+			return BlacklistSizeEstimate{}, fmt.Errorf("tokenBlackRepo.EstimateSize: 扫描黑名单键失败 (游标: %d): %w", cursor, err)
+		}
+		matched += int64(len(keys))
+		scanned += batchSize
+		cursor = next
+		if cursor == 0 {
+			// 键空间已遍历完毕，结果为精确值
+			return BlacklistSizeEstimate{Count: matched, Exact: true, ScannedKeys: scanned, EstimatedAt: time.Now()}, nil
+		}
+	}
+
+	// 预算耗尽，按命中比例外推：估算值 = 已匹配数 / 已扫描数 * 键总数
+	total, err := r.client.DBSize(ctx).Result()
+	if err != nil {
+		return BlacklistSizeEstimate{}, fmt.Errorf("tokenBlackRepo.EstimateSize: 获取键总数失败: %w", err)
+	}
+	estimate := matched
+	if scanned > 0 && total > scanned {
+		estimate = int64(float64(matched) / float64(scanned) * float64(total))
+	}
+	return BlacklistSizeEstimate{Count: estimate, Exact: false, ScannedKeys: scanned, EstimatedAt: time.Now()}, nil
+}
@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestTokenBlackRepo 基于 miniredis 创建黑名单仓库，返回的 miniredis 实例可用于断言命令数
+func newTestTokenBlackRepo(t *testing.T) (*tokenBlackRepo, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewTokenBlacklistRepo(client).(*tokenBlackRepo), mr
+}
+
+func TestAreJtisBlacklisted(t *testing.T) {
+	tests := []struct {
+		name        string
+		blacklisted []string
+		jtis        []string
+		want        map[string]bool
+		wantHits    int64
+		wantMisses  int64
+	}{
+		{
+			name:        "命中与未命中混合",
+			blacklisted: []string{"a", "c"},
+			jtis:        []string{"a", "b", "c", "d"},
+			want:        map[string]bool{"a": true, "b": false, "c": true, "d": false},
+			wantHits:    2,
+			wantMisses:  2,
+		},
+		{
+			name:        "重复 JTI 只查询并计数一次",
+			blacklisted: []string{"a"},
+			jtis:        []string{"a", "b", "a", "b", "a"},
+			want:        map[string]bool{"a": true, "b": false},
+			wantHits:    1,
+			wantMisses:  1,
+		},
+		{
+			name:        "全部未命中",
+			blacklisted: []string{"x"},
+			jtis:        []string{"a", "b"},
+			want:        map[string]bool{"a": false, "b": false},
+			wantMisses:  2,
+		},
+		{
+			name: "空列表",
+			jtis: nil,
+			want: map[string]bool{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mr := newTestTokenBlackRepo(t)
+			ctx := context.Background()
+			for _, jti := range tt.blacklisted {
+				if err := repo.AddJtiToBlacklist(ctx, jti, time.Minute); err != nil {
+					t.Fatalf("加入黑名单失败: %v", err)
+				}
+			}
+			before := mr.CommandCount()
+
+			got, err := repo.AreJtisBlacklisted(ctx, tt.jtis)
+			if err != nil {
+				t.Fatalf("批量检查失败: %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("AreJtisBlacklisted(%v) = %v，期望 %v", tt.jtis, got, tt.want)
+			}
+
+			// 非空时只发送一条 MGET；空列表不访问 Redis
+			wantCommands := 1
+			if len(tt.jtis) == 0 {
+				wantCommands = 0
+			}
+			if n := mr.CommandCount() - before; n != wantCommands {
+				t.Errorf("Redis 命令数 = %d，期望 %d", n, wantCommands)
+			}
+
+			counters := repo.Counters()
+			if counters.Hits != tt.wantHits || counters.Misses != tt.wantMisses {
+				t.Errorf("计数 = %+v，期望 Hits=%d Misses=%d", counters, tt.wantHits, tt.wantMisses)
+			}
+		})
+	}
+}
+
+func TestAreJtisBlacklistedExpiredAndRedisError(t *testing.T) {
+	repo, mr := newTestTokenBlackRepo(t)
+	ctx := context.Background()
+
+	if err := repo.AddJtiToBlacklist(ctx, "short", time.Second); err != nil {
+		t.Fatalf("加入黑名单失败: %v", err)
+	}
+	if err := repo.AddJtiToBlacklist(ctx, "long", time.Hour); err != nil {
+		t.Fatalf("加入黑名单失败: %v", err)
+	}
+	mr.FastForward(2 * time.Second)
+
+	got, err := repo.AreJtisBlacklisted(ctx, []string{"short", "long"})
+	if err != nil {
+		t.Fatalf("批量检查失败: %v", err)
+	}
+	if want := map[string]bool{"short": false, "long": true}; !maps.Equal(got, want) {
+		t.Errorf("过期后结果 = %v，期望 %v", got, want)
+	}
+
+	// Redis 不可用时返回错误而不是部分结果
+	mr.Close()
+	if got, err := repo.AreJtisBlacklisted(ctx, []string{"long"}); err == nil {
+		t.Errorf("Redis 不可用时期望返回错误，实际结果 %v", got)
+	}
+}
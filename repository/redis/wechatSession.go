@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// WechatSession 缓存的微信会话信息
+type WechatSession struct {
+	OpenID     string // 会话对应的 OpenID
+	SessionKey string // 微信会话密钥，用于解密 wx.getUserInfo 等接口返回的加密数据
+}
+
+// WechatSessionRepo 定义了按用户缓存微信 session_key 的存储接口。
+// - session_key 属于敏感信息，只保存在 Redis 中并设置有效期，不落库。
+type WechatSessionRepo interface {
+	// SaveSession 保存 (覆盖) 用户的微信会话信息，并设置有效期。
+	// - 如果 Redis 操作失败，则返回包装后的错误。
+	SaveSession(ctx context.Context, userID string, session WechatSession, ttl time.Duration) error
+
+	// GetSession 读取用户的微信会话信息。
+	// - 不存在或已过期时返回 commonerrors.ErrRepoNotFound。
+	// - 其他 Redis 错误将被包装后返回。
+	GetSession(ctx context.Context, userID string) (WechatSession, error)
+}
+
+// wechatSessionRepo 是 WechatSessionRepo 接口基于 go-redis/v9 的实现。
+type wechatSessionRepo struct {
+	client *redis.Client // client 是 Redis v9 客户端实例
+}
+
+// NewWechatSessionRepo 创建一个新的 wechatSessionRepo 实例。
+func NewWechatSessionRepo(client *redis.Client) WechatSessionRepo {
+	return &wechatSessionRepo{client: client}
+}
+
+// buildKey 示例键: "wechat:session:<userID>"
+func (r *wechatSessionRepo) buildKey(userID string) string {
+	return constants.WechatSessionKeyPrefix + ":" + userID
+}
+
+// SaveSession 实现接口方法。
+func (r *wechatSessionRepo) SaveSession(ctx context.Context, userID string, session WechatSession, ttl time.Duration) error {
+	key := r.buildKey(userID)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "openid", session.OpenID, "session_key", session.SessionKey)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("wechatSessionRepo.SaveSession: 保存微信会话失败 (UserID: %s): %w", userID, err)
+	}
+	return nil
+}
+
+// GetSession 实现接口方法。
+func (r *wechatSessionRepo) GetSession(ctx context.Context, userID string) (WechatSession, error) {
+	values, err := r.client.HGetAll(ctx, r.buildKey(userID)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return WechatSession{}, fmt.Errorf("wechatSessionRepo.GetSession: 读取微信会话失败 (UserID: %s): %w", userID, err)
+	}
+	if values["session_key"] == "" {
+		return WechatSession{}, commonerrors.ErrRepoNotFound
+	}
+	return WechatSession{OpenID: values["openid"], SessionKey: values["session_key"]}, nil
+}
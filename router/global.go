@@ -7,7 +7,7 @@ import (
 	"github.com/Xushengqwer/user_hub/constants"
 	swaggerFiles "github.com/swaggo/files"     // swagger-files 包
 	ginSwagger "github.com/swaggo/gin-swagger" // gin-swagger 包
-	"time"
+	"strconv"
 
 	commonMiddleware "github.com/Xushengqwer/go-common/middleware"
 
@@ -19,6 +19,7 @@ import (
 	"github.com/Xushengqwer/user_hub/dependencies"
 	_ "github.com/Xushengqwer/user_hub/docs" // 引入 docs 包以注册 Swagger 信息
 	"github.com/Xushengqwer/user_hub/initialization"
+	"github.com/Xushengqwer/user_hub/middleware"
 )
 
 // SetupRouter 初始化并配置 Gin 引擎，注册所有中间件和路由。
@@ -34,6 +35,7 @@ import (
 //   - cfg: 应用的全局配置 (UserHubConfig)，用于获取 RateLimitConfig 等。
 //   - jwtUtil: JWT 工具实例，传递给需要它的控制器。
 //   - appServices: 包含所有已初始化服务实例的结构体。
+//   - drainState: 关停排空状态，排空期间的响应会带上 "Connection: close"。
 //
 // 返回:
 //   - *gin.Engine: 配置完成的 Gin 引擎实例，可以直接运行。
@@ -43,12 +45,15 @@ func SetupRouter(
 	jwtUtil dependencies.JWTTokenInterface,
 	appServices *initialization.AppServices,
 	appDeps *initialization.AppDependencies, // <-- 传入 AppDependencies 包含了 DB
+	drainState *middleware.DrainState,
 ) *gin.Engine {
 	logger.Info("开始设置 Gin 路由...")
 
 	// 1. 创建 Gin 引擎实例
 	//    使用 gin.Default() 包含 Logger 和 Recovery 中间件。Recovery 是有用的。
 	router := gin.Default()
+	// 使用原始路径匹配路由，使 %2F 编码的对象键 (如 /files/{key}/url) 作为单个路径参数，参数值仍会被解码
+	router.UseRawPath = true
 
 	// 1. OTel Middleware (最先，处理追踪上下文和 Span)
 	router.Use(otelgin.Middleware(constants.ServiceName))
@@ -66,39 +71,134 @@ func SetupRouter(
 		logger.Warn("无法获取底层的 *zap.Logger，跳过 RequestLoggerMiddleware 注册")
 	}
 
+	// 3.0.1 Error Body Log (可选，白名单路由失败时记录脱敏后的请求体，紧随访问日志以便按 TraceID 关联)
+	if cfg.ErrorBodyLog.Enabled {
+		router.Use(middleware.ErrorBodyLogMiddleware(cfg.ErrorBodyLog, logger))
+		logger.Info("已启用失败请求体日志中间件")
+	}
+
+	// 3.0.2 Query Count (可选，仅用于非生产环境：单个请求的 SQL 次数超过阈值时告警，用于发现 N+1 查询)
+	if cfg.MySQLConfig.QueryCount.Enabled {
+		router.Use(middleware.QueryCountMiddleware(cfg.MySQLConfig.QueryCount, logger))
+		logger.Info("已启用单请求 SQL 次数统计中间件")
+	}
+
+	// 3.0.3 Platform Trust (可选，仅信任来自网关的 X-Platform，其余请求按 User-Agent 推断平台)
+	// 需在所有读取 X-Platform 的处理函数之前执行
+	if cfg.PlatformTrust.TrustsOnlyProxies() {
+		router.Use(middleware.PlatformTrustMiddleware(cfg.PlatformTrust, logger))
+		logger.Info("已启用 X-Platform 可信代理校验")
+	}
+
+	// 3.0.4 Tenant (可选，按请求头限定账号标识符的唯一性与登录查找范围)
+	if cfg.Tenant.Enabled {
+		router.Use(middleware.TenantMiddleware(cfg.Tenant, logger))
+		logger.Info("已启用租户隔离 (请求头: " + cfg.Tenant.HeaderOrDefault() + ")")
+	}
+
+	// 3.0.5 In-Flight Limit (可选，在途请求数达到上限时返回 503，为数据库/Redis 连接池提供背压)
+	var inFlightLimiter *middleware.InFlightLimiter
+	if cfg.ConcurrencyLimit.Active() {
+		inFlightLimiter = middleware.NewInFlightLimiter(cfg.ConcurrencyLimit.MaxInFlight)
+		router.Use(middleware.InFlightLimitMiddleware(inFlightLimiter, cfg.ConcurrencyLimit))
+		logger.Info("已启用在途请求数上限 (max_in_flight: " + strconv.Itoa(cfg.ConcurrencyLimit.MaxInFlight) + ")")
+	}
+
+	// 3.1 Drain (关停排空期间通知客户端关闭连接)
+	router.Use(middleware.DrainMiddleware(drainState))
+
+	// 3.2 Response Compression (可选，按配置启用 gzip 压缩)
+	// 放在超时中间件之前，确保压缩流在整个请求处理结束后才被关闭
+	if cfg.CompressionConfig.Enabled {
+		router.Use(middleware.CompressionMiddleware(cfg.CompressionConfig))
+		logger.Info("已启用响应压缩中间件")
+	}
+
+	// 3.3 Content Negotiation (可选，按 Accept 头将 JSON 响应转换为 XML)
+	// 放在压缩之后，使转换后的 XML 仍会被压缩
+	if cfg.Negotiation.Enabled {
+		router.Use(middleware.ContentNegotiationMiddleware())
+		logger.Info("已启用响应格式协商中间件")
+	}
+
+	// 3.4 Locale (按 Accept-Language 在支持列表中选择响应语言，无法匹配时回退到默认语言)
+	router.Use(middleware.LocaleMiddleware(cfg.LocaleConfig))
+
 	// 4. Request Timeout (超时控制)
-	// 假设配置中的 RequestTimeout 是秒数
-	requestTimeout := time.Duration(cfg.ServerConfig.RequestTimeout) * time.Second
-	router.Use(commonMiddleware.RequestTimeoutMiddleware(logger, requestTimeout))
+	// 4.0 Route Timeout (按路由组放宽超时，如头像上传)，命中的请求由其施加超时并让全局超时放行，需在全局超时之前注册
+	if len(cfg.RouteTimeout.Overrides) > 0 {
+		router.Use(middleware.RouteTimeoutMiddleware(cfg.RouteTimeout, logger)...)
+		logger.Info("已启用按路由组覆盖请求超时 (" + strconv.Itoa(len(cfg.RouteTimeout.Overrides)) + " 条)")
+	}
+	// 配置中的 RequestTimeout 已是 time.Duration (如 "15s")，直接使用
+	router.Use(commonMiddleware.RequestTimeoutMiddleware(logger, cfg.ServerConfig.RequestTimeout))
 
 	// 5. User Context (提取用户信息)
 	router.Use(commonMiddleware.UserContextMiddleware())
+
+	// 5.1 API Key Auth (请求携带 X-API-Key 且网关未透传用户身份时，按 API 密钥填充用户上下文)
+	router.Use(middleware.APIKeyAuthMiddleware(appServices.APIKey, logger))
+
+	// 5.2 Upstream Error Detail (可选，第三方服务失败时向管理员附带脱敏的上游错误摘要)
+	if cfg.UpstreamErrorDetail.Enabled {
+		router.Use(middleware.UpstreamErrorDetailMiddleware(cfg.UpstreamErrorDetail))
+		if cfg.UpstreamErrorDetail.AllCallers {
+			logger.Warn("第三方错误详情对所有调用者生效 (调试开关)，切勿在生产环境使用")
+		} else {
+			logger.Info("已启用第三方错误详情 (仅管理员)")
+		}
+	}
 	// 3. 创建 API 版本分组 /api/v1
 	v1 := router.Group("api/v1/user-hub")
 	logger.Info("API 路由将注册到 api/v1/user-hub 分组下")
 
 	// 4. 初始化所有控制器 (使用更新后的名称和依赖)
-	accountCtrl := controller.NewAccountController(appServices.Account, logger, cfg.CookieConfig)
-	authCtrl := controller.NewAuthController(appServices.SMS, appServices.CodeRepo, logger) // AuthController 依赖 SMS, CodeRepo, Logger
+	auditCtrl := controller.NewAuditController(appServices.AuditService, logger)
+	configCtrl := controller.NewConfigController(cfg, logger)
+	consistencyCtrl := controller.NewConsistencyController(appServices.Consistency, logger)
+	apiKeyCtrl := controller.NewAPIKeyController(appServices.APIKey, logger)
+	accountCtrl := controller.NewAccountController(appServices.Account, jwtUtil, logger, cfg.CookieConfig)
+	deactivationCtrl := controller.NewAccountDeactivationController(appServices.Deactivation, jwtUtil, logger, cfg.CookieConfig)
+	authCtrl := controller.NewAuthController(appServices.CaptchaSender, appServices.CodeRepo, cfg.SMSConfig, appDeps.RedisDegrade, cfg.CaptchaDebug, logger) // AuthController 依赖短信发送器, CodeRepo, Logger
+	fileCtrl := controller.NewFileController(appServices.File, logger)
+	healthCtrl := controller.NewHealthController(appServices.Health, logger)
+	metaCtrl := controller.NewMetaController(appServices.FeatureService, cfg.LocaleConfig, logger)
 	identityCtrl := controller.NewIdentityController(appServices.IdentityService, jwtUtil, logger)
-	phoneCtrl := controller.NewPhoneAuthController(appServices.Phone, logger, cfg.CookieConfig) // 使用更新后的名称和依赖
+	phoneCtrl := controller.NewPhoneAuthController(appServices.Phone, jwtUtil, logger, cfg.CookieConfig) // 使用更新后的名称和依赖
+	unifiedLoginCtrl := controller.NewUnifiedLoginController(appServices.UnifiedLogin, jwtUtil, logger, cfg.CookieConfig, cfg.UnifiedLogin)
+	recoveryCtrl := controller.NewAccountRecoveryController(appServices.Recovery, logger)
+	securityCtrl := controller.NewAccountSecurityController(appServices.Security, logger)
 	profileCtrl := controller.NewUserProfileController(appServices.ProfileService, jwtUtil, logger, appDeps.DB)
-	tokenCtrl := controller.NewAuthTokenController(appServices.TokenService, jwtUtil, logger, cfg.CookieConfig)
-	userCtrl := controller.NewUserController(appServices.UserService, jwtUtil, logger)
-	userListQueryCtrl := controller.NewUserListQueryController(appServices.QueryService, jwtUtil, logger)
+	tokenCtrl := controller.NewAuthTokenController(appServices.TokenService, jwtUtil, logger, cfg.CookieConfig, appDeps.RedisDegrade, inFlightLimiter, cfg.BatchLimit)
+	userCtrl := controller.NewUserController(appServices.UserService, appServices.UserDetail, jwtUtil, logger, cfg.BatchLimit)
+	userListQueryCtrl := controller.NewUserListQueryController(appServices.QueryService, jwtUtil, logger, cfg.ListEnvelope)
 	wechatCtrl := controller.NewWechatAuthController(appServices.WechatMiniProgram, logger) // 使用更新后的名称和依赖
 
 	// 5. 注册每个控制器的路由到 /api/v1 分组
 	accountCtrl.RegisterRoutes(v1)
+	apiKeyCtrl.RegisterRoutes(v1)
+	auditCtrl.RegisterRoutes(v1)
 	authCtrl.RegisterRoutes(v1)
+	configCtrl.RegisterRoutes(v1)
+	consistencyCtrl.RegisterRoutes(v1)
+	deactivationCtrl.RegisterRoutes(v1)
+	fileCtrl.RegisterRoutes(v1)
+	healthCtrl.RegisterRoutes(v1)
 	identityCtrl.RegisterRoutes(v1)
+	metaCtrl.RegisterRoutes(v1)
 	phoneCtrl.RegisterRoutes(v1)
 	profileCtrl.RegisterRoutes(v1)
+	recoveryCtrl.RegisterRoutes(v1)
+	securityCtrl.RegisterRoutes(v1)
 	tokenCtrl.RegisterRoutes(v1)
+	unifiedLoginCtrl.RegisterRoutes(v1)
 	userCtrl.RegisterRoutes(v1)
 	userListQueryCtrl.RegisterRoutes(v1)
 	wechatCtrl.RegisterRoutes(v1)
 
+	// Prometheus 指标挂在根路径下，供集群内部抓取，不应经由网关对外暴露
+	router.GET("/metrics", tokenCtrl.BlacklistMetricsHandler)
+
 	logger.Info("所有业务路由已成功注册")
 
 	// 6. 配置 Swagger UI 路由
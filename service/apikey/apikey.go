@@ -0,0 +1,284 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	commonEnums "github.com/Xushengqwer/go-common/models/enums"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/constants"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+const (
+	// maxKeysPerUser 每个用户最多持有的 API 密钥数量
+	maxKeysPerUser = 10
+	// keyRandomBytes 密钥明文中随机部分的字节数
+	keyRandomBytes = 32
+	// displayPrefixLen 保存用于展示的密钥前缀长度 (含固定前缀 uhk_)
+	displayPrefixLen = 12
+	// lastUsedUpdateInterval 最近使用时间的最小更新间隔，避免每个请求都写库
+	lastUsedUpdateInterval = time.Minute
+)
+
+// API 密钥服务返回的业务错误
+var (
+	ErrTooManyKeys    = errors.New("API 密钥数量已达上限，请先删除不再使用的密钥")
+	ErrKeyNotFound    = errors.New("API 密钥不存在")
+	ErrInvalidAPIKey  = errors.New("API 密钥无效或已过期")
+	ErrAccountBlocked = errors.New("账号状态异常，API 密钥不可用")
+)
+
+// Principal 通过 API 密钥认证得到的调用者信息，字段与网关透传的用户上下文一致
+type Principal struct {
+	KeyID    uint
+	UserID   string
+	Role     commonEnums.UserRole
+	Status   commonEnums.UserStatus
+	Scopes   []enums.APIKeyScope
+	KeyLabel string // KeyLabel: 密钥前缀，用于日志
+}
+
+// HasScope 判断调用者是否拥有指定权限范围；write 隐含 read
+func (p *Principal) HasScope(scope enums.APIKeyScope) bool {
+	return slices.Contains(p.Scopes, scope) || (scope == enums.APIKeyScopeRead && slices.Contains(p.Scopes, enums.APIKeyScopeWrite))
+}
+
+// APIKeyService 定义了用户 API 密钥的管理与认证服务接口。
+// 设计目的:
+// - 为脚本、CI 等程序化访问提供不依赖 JWT 登录流程的长期凭证。
+// - 只保存密钥哈希，明文仅在创建时返回一次；按权限范围限制可用的请求方法。
+type APIKeyService interface {
+	// CreateAPIKey 为用户创建一个新的 API 密钥。
+	// 返回:
+	//  - *vo.APIKeyCreatedVO: 含密钥明文的创建结果。
+	//  - error: 数量达到上限返回 ErrTooManyKeys，其他失败返回系统错误。
+	CreateAPIKey(ctx context.Context, userID string, req *dto.CreateAPIKeyDTO) (*vo.APIKeyCreatedVO, error)
+
+	// ListAPIKeys 列出用户的全部 API 密钥元数据，不含明文。
+	ListAPIKeys(ctx context.Context, userID string) ([]*vo.APIKeyVO, error)
+
+	// DeleteAPIKey 删除用户的指定 API 密钥，删除后立即失效。
+	// - 密钥不存在或不属于该用户时返回 ErrKeyNotFound。
+	DeleteAPIKey(ctx context.Context, userID string, keyID uint) error
+
+	// Authenticate 校验 API 密钥明文并返回调用者信息，同时按间隔更新最近使用时间。
+	// - 密钥不存在、已过期或所属用户不存在时返回 ErrInvalidAPIKey；用户被拉黑或已停用时返回 ErrAccountBlocked。
+	Authenticate(ctx context.Context, rawKey string) (*Principal, error)
+}
+
+// apiKeyService 是 APIKeyService 接口的实现。
+type apiKeyService struct {
+	apiKeyRepo mysql.APIKeyRepository // apiKeyRepo: API 密钥仓库。
+	userRepo   mysql.UserRepository   // userRepo: 用户仓库，认证时读取角色与状态。
+	db         *gorm.DB               // db: 数据库连接。
+	clock      utils.Clock            // clock: 时间来源，计算与校验密钥过期时间。
+	logger     *core.ZapLogger        // logger: 日志记录器。
+}
+
+// NewAPIKeyService 创建一个新的 apiKeyService 实例。
+func NewAPIKeyService(
+	apiKeyRepo mysql.APIKeyRepository,
+	userRepo mysql.UserRepository,
+	db *gorm.DB,
+	clock utils.Clock,
+	logger *core.ZapLogger,
+) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		db:         db,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+// CreateAPIKey 实现接口方法。
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, userID string, req *dto.CreateAPIKeyDTO) (*vo.APIKeyCreatedVO, error) {
+	const operation = "APIKeyService.CreateAPIKey"
+
+	count, err := s.apiKeyRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("统计用户 API 密钥数量失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if count >= maxKeysPerUser {
+		return nil, ErrTooManyKeys
+	}
+
+	rawKey, err := generateKey()
+	if err != nil {
+		s.logger.Error("生成 API 密钥失败", zap.String("operation", operation), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = []enums.APIKeyScope{enums.APIKeyScopeRead}
+	}
+	key := &entities.APIKey{
+		UserID:  userID,
+		Name:    req.Name,
+		Prefix:  rawKey[:displayPrefixLen],
+		KeyHash: hashKey(rawKey),
+		Scopes:  joinScopes(scopes),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := s.clock.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := s.apiKeyRepo.CreateAPIKey(ctx, s.db, key); err != nil {
+		s.logger.Error("保存 API 密钥失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("已创建 API 密钥", zap.String("operation", operation), zap.String("userID", userID), zap.Uint("keyID", key.ID), zap.String("prefix", key.Prefix))
+	return &vo.APIKeyCreatedVO{APIKeyVO: *entityToVO(key), Key: rawKey}, nil
+}
+
+// ListAPIKeys 实现接口方法。
+func (s *apiKeyService) ListAPIKeys(ctx context.Context, userID string) ([]*vo.APIKeyVO, error) {
+	const operation = "APIKeyService.ListAPIKeys"
+
+	keys, err := s.apiKeyRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("查询用户 API 密钥失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	result := make([]*vo.APIKeyVO, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, entityToVO(key))
+	}
+	return result, nil
+}
+
+// DeleteAPIKey 实现接口方法。
+func (s *apiKeyService) DeleteAPIKey(ctx context.Context, userID string, keyID uint) error {
+	const operation = "APIKeyService.DeleteAPIKey"
+
+	if err := s.apiKeyRepo.DeleteAPIKey(ctx, s.db, userID, keyID); err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			return ErrKeyNotFound
+		}
+		s.logger.Error("删除 API 密钥失败", zap.String("operation", operation), zap.String("userID", userID), zap.Uint("keyID", keyID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	s.logger.Info("已删除 API 密钥", zap.String("operation", operation), zap.String("userID", userID), zap.Uint("keyID", keyID))
+	return nil
+}
+
+// Authenticate 实现接口方法。
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string) (*Principal, error) {
+	const operation = "APIKeyService.Authenticate"
+
+	if !strings.HasPrefix(rawKey, constants.APIKeyTokenPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.apiKeyRepo.GetByKeyHash(ctx, hashKey(rawKey))
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		s.logger.Error("查询 API 密钥失败", zap.String("operation", operation), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	now := s.clock.Now()
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		s.logger.Error("API 密钥认证时查询用户失败", zap.String("operation", operation), zap.String("userID", key.UserID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if user.Status != commonEnums.StatusActive {
+		return nil, ErrAccountBlocked
+	}
+
+	// 最近使用时间按间隔更新，写入失败不影响本次认证
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedUpdateInterval {
+		if err := s.apiKeyRepo.UpdateLastUsedAt(context.WithoutCancel(ctx), s.db, key.ID, now); err != nil {
+			s.logger.Warn("更新 API 密钥最近使用时间失败", zap.String("operation", operation), zap.Uint("keyID", key.ID), zap.Error(err))
+		}
+	}
+
+	return &Principal{
+		KeyID:    key.ID,
+		UserID:   user.UserID,
+		Role:     user.UserRole,
+		Status:   user.Status,
+		Scopes:   splitScopes(key.Scopes),
+		KeyLabel: key.Prefix,
+	}, nil
+}
+
+// generateKey 生成 "uhk_" + Base64URL(32 字节随机数) 形式的密钥明文
+func generateKey() (string, error) {
+	buf := make([]byte, keyRandomBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return constants.APIKeyTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashKey 计算密钥明文的 SHA-256 哈希
+// - 密钥本身为高熵随机数，无需 bcrypt 等慢哈希，且需要按哈希直接查找。
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// joinScopes 将权限范围去重后以逗号拼接
+func joinScopes(scopes []enums.APIKeyScope) string {
+	names := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !slices.Contains(names, string(scope)) {
+			names = append(names, string(scope))
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// splitScopes 解析逗号分隔的权限范围
+func splitScopes(s string) []enums.APIKeyScope {
+	var scopes []enums.APIKeyScope
+	for _, name := range strings.Split(s, ",") {
+		if name != "" {
+			scopes = append(scopes, enums.APIKeyScope(name))
+		}
+	}
+	return scopes
+}
+
+// entityToVO 将 API 密钥实体转换为视图对象
+func entityToVO(key *entities.APIKey) *vo.APIKeyVO {
+	return &vo.APIKeyVO{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     splitScopes(key.Scopes),
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// MaxAuditQueryRange 单次查询允许的最大时间跨度，防止大范围扫描审计表
+// - 未指定起始时间时，默认从结束时间 (未指定则为当前时间) 往前回溯该跨度。
+const MaxAuditQueryRange = 90 * 24 * time.Hour
+
+// ErrAuditRangeTooLarge 查询的时间跨度超过 MaxAuditQueryRange 时返回
+var ErrAuditRangeTooLarge = errors.New("查询时间跨度不能超过 90 天")
+
+// AdminAuditService 定义了管理员审计日志查询相关的服务接口。
+// 设计目的:
+// - 为管理后台提供按操作者、目标、操作类型和时间范围检索审计日志的能力。
+// - 审计日志的写入由各业务服务在自身事务中完成，本服务只负责查询。
+type AdminAuditService interface {
+	// ListAuditLogs 分页查询审计日志。
+	// - 支持页码分页和键集 (游标) 分页，响应中的 next_cursor 可用于继续翻页。
+	// - 时间范围被限制在 MaxAuditQueryRange 之内，未指定起始时间时自动补齐。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - query: 包含过滤和分页参数的查询 DTO。
+	// 返回:
+	//  - *vo.AuditLogListResponse: 当前页的审计日志、总记录数及下一页游标。
+	//  - error: 时间跨度过大返回 ErrAuditRangeTooLarge，游标无效返回 utils.ErrInvalidCursor，其他为系统错误。
+	ListAuditLogs(ctx context.Context, query *dto.AuditLogQueryDTO) (*vo.AuditLogListResponse, error)
+}
+
+// adminAuditService 是 AdminAuditService 接口的实现。
+type adminAuditService struct {
+	repo   mysql.AdminAuditRepository // repo: 审计日志仓库。
+	clock  utils.Clock                // clock: 时间来源，未指定结束时间时作为查询范围的终点。
+	logger *core.ZapLogger            // logger: 日志记录器。
+}
+
+// NewAdminAuditService 创建一个新的 adminAuditService 实例。
+func NewAdminAuditService(repo mysql.AdminAuditRepository, clock utils.Clock, logger *core.ZapLogger) AdminAuditService {
+	return &adminAuditService{
+		repo:   repo,
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+// ListAuditLogs 实现接口方法，分页查询审计日志。
+func (s *adminAuditService) ListAuditLogs(ctx context.Context, query *dto.AuditLogQueryDTO) (*vo.AuditLogListResponse, error) {
+	const operation = "AdminAuditService.ListAuditLogs"
+
+	// 1. 限制时间跨度：缺省的起始时间按最大跨度补齐，显式跨度过大直接拒绝
+	end := s.clock.Now()
+	if query.EndTime != nil {
+		end = *query.EndTime
+	}
+	if query.StartTime == nil {
+		start := end.Add(-MaxAuditQueryRange)
+		query.StartTime = &start
+	} else if end.Sub(*query.StartTime) > MaxAuditQueryRange {
+		return nil, ErrAuditRangeTooLarge
+	}
+
+	// 2. 解析游标
+	var after *entities.AdminAuditLog
+	if query.Cursor != "" {
+		cursor, err := utils.DecodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = &entities.AdminAuditLog{ID: cursor.ID, CreatedAt: cursor.CreatedAt}
+	}
+
+	// 3. 多取一条用于判断是否还有下一页
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	logs, total, err := s.repo.ListAuditLogs(ctx, query, after, pageSize+1)
+	if err != nil {
+		s.logger.Error("调用仓库查询审计日志失败",
+			zap.String("operation", operation),
+			zap.Any("query", query),
+			zap.Error(err),
+		)
+		return nil, commonerrors.ErrSystemError
+	}
+
+	result := &vo.AuditLogListResponse{Total: total, PageSize: pageSize}
+	if query.Cursor == "" {
+		result.Page = max(query.Page, 1)
+	}
+	if len(logs) > pageSize {
+		logs = logs[:pageSize]
+		last := logs[len(logs)-1]
+		result.NextCursor = utils.EncodeCursor(utils.KeysetCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	result.Items = make([]*vo.AuditLogVO, 0, len(logs))
+	for _, log := range logs {
+		result.Items = append(result.Items, auditLogEntityToVO(log))
+	}
+	return result, nil
+}
+
+// auditLogEntityToVO 将审计日志实体转换为视图对象，Diff 以原始 JSON 输出
+func auditLogEntityToVO(log *entities.AdminAuditLog) *vo.AuditLogVO {
+	diff := json.RawMessage(log.Diff)
+	if !json.Valid(diff) {
+		diff = json.RawMessage("{}")
+	}
+	return &vo.AuditLogVO{
+		ID:        log.ID,
+		ActorID:   log.ActorID,
+		Action:    log.Action,
+		TargetID:  log.TargetID,
+		Diff:      diff,
+		CreatedAt: log.CreatedAt,
+	}
+}
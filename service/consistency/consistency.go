@@ -0,0 +1,234 @@
+package consistency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+)
+
+const (
+	defaultBatchSize  = 200  // 默认每批查询的记录数
+	defaultMaxRecords = 5000 // 默认每类问题最多处理的记录数
+)
+
+// 审计日志中各类修复操作的目标标识
+const (
+	auditTargetMissingProfile = "consistency:users_without_profile"
+	auditTargetOrphanProfile  = "consistency:orphan_profiles"
+	auditTargetOrphanIdentity = "consistency:orphan_identities"
+)
+
+// DataConsistencyService 定义了用户、资料、身份三张表之间的数据一致性巡检服务。
+// 设计目的:
+// - 各业务流程中创建与级联删除并不完全对称，可能遗留孤儿资料/身份或缺少资料的用户。
+// - 为运维提供按批次扫描、可选修复的工具，修复操作会写入审计日志。
+type DataConsistencyService interface {
+	// Scan 扫描三类不一致问题并返回报告；data.Repair 为 true 时同时修复。
+	// - actorID: 发起巡检的管理员 ID，用于记录修复操作的审计日志。
+	// - 修复按批次在独立事务中执行，单批失败时返回系统错误，已提交的批次不会回滚。
+	Scan(ctx context.Context, actorID string, data dto.ConsistencyScanDTO) (*vo.ConsistencyReportVO, error)
+}
+
+// dataConsistencyService 是 DataConsistencyService 接口的实现。
+type dataConsistencyService struct {
+	repo        mysql.ConsistencyRepository // 一致性巡检仓库
+	profileRepo mysql.ProfileRepository     // 资料仓库，用于补建缺失的资料
+	auditRepo   mysql.AdminAuditRepository  // 审计日志仓库
+	db          *gorm.DB                    // 数据库连接，用于开启修复事务
+	logger      *core.ZapLogger             // 日志记录器
+}
+
+// NewDataConsistencyService 创建一个新的 DataConsistencyService 实例。
+func NewDataConsistencyService(
+	repo mysql.ConsistencyRepository,
+	profileRepo mysql.ProfileRepository,
+	auditRepo mysql.AdminAuditRepository,
+	db *gorm.DB,
+	logger *core.ZapLogger,
+) DataConsistencyService {
+	return &dataConsistencyService{
+		repo:        repo,
+		profileRepo: profileRepo,
+		auditRepo:   auditRepo,
+		db:          db,
+		logger:      logger,
+	}
+}
+
+// Scan 实现接口方法，依次扫描缺少资料的用户、孤儿资料与孤儿身份。
+func (s *dataConsistencyService) Scan(ctx context.Context, actorID string, data dto.ConsistencyScanDTO) (*vo.ConsistencyReportVO, error) {
+	const operation = "DataConsistencyService.Scan"
+
+	batchSize := data.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	maxRecords := data.MaxRecords
+	if maxRecords <= 0 {
+		maxRecords = defaultMaxRecords
+	}
+
+	report := &vo.ConsistencyReportVO{Repair: data.Repair}
+	var err error
+	if report.UsersWithoutProfile, err = s.scanUsersWithoutProfile(ctx, actorID, data.Repair, batchSize, maxRecords); err != nil {
+		s.logger.Error("扫描缺少资料的用户失败", zap.String("operation", operation), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if report.OrphanProfiles, err = s.scanOrphanProfiles(ctx, actorID, data.Repair, batchSize, maxRecords); err != nil {
+		s.logger.Error("扫描孤儿资料失败", zap.String("operation", operation), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if report.OrphanIdentities, err = s.scanOrphanIdentities(ctx, actorID, data.Repair, batchSize, maxRecords); err != nil {
+		s.logger.Error("扫描孤儿身份失败", zap.String("operation", operation), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("数据一致性巡检完成",
+		zap.String("operation", operation),
+		zap.String("actorID", actorID),
+		zap.Bool("repair", data.Repair),
+		zap.Int("usersWithoutProfile", report.UsersWithoutProfile.Found),
+		zap.Int("orphanProfiles", report.OrphanProfiles.Found),
+		zap.Int("orphanIdentities", report.OrphanIdentities.Found),
+	)
+	return report, nil
+}
+
+// scanUsersWithoutProfile 分批扫描缺少资料的用户，开启修复时为其补建空资料。
+func (s *dataConsistencyService) scanUsersWithoutProfile(ctx context.Context, actorID string, repair bool, batchSize, maxRecords int) (vo.ConsistencyIssueVO, error) {
+	issue := vo.ConsistencyIssueVO{IDs: []string{}}
+	cursor := ""
+	for issue.Found < maxRecords {
+		userIDs, err := s.repo.ListUsersWithoutProfile(ctx, cursor, min(batchSize, maxRecords-issue.Found))
+		if err != nil {
+			return issue, err
+		}
+		if len(userIDs) == 0 {
+			return issue, nil
+		}
+		cursor = userIDs[len(userIDs)-1]
+		issue.IDs = append(issue.IDs, userIDs...)
+		issue.Found += len(userIDs)
+
+		if repair {
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				for _, userID := range userIDs {
+					if err := s.profileRepo.CreateProfile(ctx, tx, &entities.UserProfile{UserID: userID}); err != nil {
+						return err
+					}
+				}
+				return s.recordRepair(ctx, tx, actorID, auditTargetMissingProfile, userIDs)
+			})
+			if err != nil {
+				return issue, fmt.Errorf("补建缺失资料失败: %w", err)
+			}
+			issue.Repaired += len(userIDs)
+		}
+	}
+	issue.Truncated = true
+	return issue, nil
+}
+
+// scanOrphanProfiles 分批扫描孤儿资料，开启修复时删除。
+func (s *dataConsistencyService) scanOrphanProfiles(ctx context.Context, actorID string, repair bool, batchSize, maxRecords int) (vo.ConsistencyIssueVO, error) {
+	issue := vo.ConsistencyIssueVO{IDs: []string{}}
+	var cursor uint
+	for issue.Found < maxRecords {
+		profiles, err := s.repo.ListOrphanProfiles(ctx, cursor, min(batchSize, maxRecords-issue.Found))
+		if err != nil {
+			return issue, err
+		}
+		if len(profiles) == 0 {
+			return issue, nil
+		}
+		cursor = profiles[len(profiles)-1].ID
+		ids := make([]uint, 0, len(profiles))
+		idStrs := make([]string, 0, len(profiles))
+		for _, p := range profiles {
+			ids = append(ids, p.ID)
+			idStrs = append(idStrs, strconv.FormatUint(uint64(p.ID), 10))
+		}
+		issue.IDs = append(issue.IDs, idStrs...)
+		issue.Found += len(profiles)
+
+		if repair {
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				if err := s.repo.DeleteProfilesByIDs(ctx, tx, ids); err != nil {
+					return err
+				}
+				return s.recordRepair(ctx, tx, actorID, auditTargetOrphanProfile, idStrs)
+			})
+			if err != nil {
+				return issue, fmt.Errorf("删除孤儿资料失败: %w", err)
+			}
+			issue.Repaired += len(profiles)
+		}
+	}
+	issue.Truncated = true
+	return issue, nil
+}
+
+// scanOrphanIdentities 分批扫描孤儿身份，开启修复时删除。
+func (s *dataConsistencyService) scanOrphanIdentities(ctx context.Context, actorID string, repair bool, batchSize, maxRecords int) (vo.ConsistencyIssueVO, error) {
+	issue := vo.ConsistencyIssueVO{IDs: []string{}}
+	var cursor uint
+	for issue.Found < maxRecords {
+		identities, err := s.repo.ListOrphanIdentities(ctx, cursor, min(batchSize, maxRecords-issue.Found))
+		if err != nil {
+			return issue, err
+		}
+		if len(identities) == 0 {
+			return issue, nil
+		}
+		cursor = identities[len(identities)-1].IdentityID
+		ids := make([]uint, 0, len(identities))
+		idStrs := make([]string, 0, len(identities))
+		for _, identity := range identities {
+			ids = append(ids, identity.IdentityID)
+			idStrs = append(idStrs, strconv.FormatUint(uint64(identity.IdentityID), 10))
+		}
+		issue.IDs = append(issue.IDs, idStrs...)
+		issue.Found += len(identities)
+
+		if repair {
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				if err := s.repo.DeleteIdentitiesByIDs(ctx, tx, ids); err != nil {
+					return err
+				}
+				return s.recordRepair(ctx, tx, actorID, auditTargetOrphanIdentity, idStrs)
+			})
+			if err != nil {
+				return issue, fmt.Errorf("删除孤儿身份失败: %w", err)
+			}
+			issue.Repaired += len(identities)
+		}
+	}
+	issue.Truncated = true
+	return issue, nil
+}
+
+// recordRepair 在修复事务中写入一条审计日志，记录本批次修复涉及的记录 ID。
+func (s *dataConsistencyService) recordRepair(ctx context.Context, tx *gorm.DB, actorID, target string, ids []string) error {
+	diffJSON, err := json.Marshal(map[string][]string{"ids": ids})
+	if err != nil {
+		return fmt.Errorf("序列化审计变更内容失败: %w", err)
+	}
+	return s.auditRepo.CreateAuditLog(ctx, tx, &entities.AdminAuditLog{
+		ActorID:  actorID,
+		Action:   enums.AuditActionConsistencyRepair,
+		TargetID: target,
+		Diff:     string(diffJSON),
+	})
+}
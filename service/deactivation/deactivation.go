@@ -0,0 +1,207 @@
+package deactivation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/dependencies"
+	myenums "github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/token"
+)
+
+// ReactivationTicketTTL 重新激活凭证的有效期
+const ReactivationTicketTTL = 10 * time.Minute
+
+// ReactivatePath 重新激活接口的路径，随停用提示一并返回给客户端
+const ReactivatePath = "/api/v1/user-hub/account/reactivate"
+
+// AccountDeactivatedError 登录时账号处于停用状态返回的错误。
+// 用户已通过本次登录的身份校验，因此附带一次性的重新激活凭证，客户端可凭此调用重新激活接口。
+type AccountDeactivatedError struct {
+	ReactivationTicket string        // ReactivationTicket: 一次性重新激活凭证
+	ExpiresIn          time.Duration // ExpiresIn: 凭证有效期
+}
+
+// Error 实现 error 接口
+func (e *AccountDeactivatedError) Error() string {
+	return "账号已停用"
+}
+
+// AccountDeactivationService 定义了用户自助停用/重新激活账号的服务接口。
+// 设计目的:
+// - 与删除不同，停用是可逆的：只修改用户状态，资料、身份等数据全部保留。
+// - 停用后所有登录方式都会被拒绝，已签发的刷新令牌因用户状态非活跃而无法续期。
+type AccountDeactivationService interface {
+	// Deactivate 停用当前用户的账号，并吊销本次请求携带的令牌。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - userID: 当前认证用户的ID。
+	//  - tokensToRevoke: 需要立即加入黑名单的令牌（如当前的 Access Token / Refresh Token），可为空。
+	// 返回:
+	//  - error: 用户不存在或状态不允许停用时返回业务错误，数据库操作失败时返回系统错误。
+	Deactivate(ctx context.Context, userID string, tokensToRevoke []string) error
+
+	// ChallengeForReactivation 供各登录服务在发现账号已停用时调用，签发重新激活凭证。
+	// 返回:
+	//  - error: 成功时为 *AccountDeactivatedError（携带凭证），Redis 操作失败时为系统错误。
+	ChallengeForReactivation(ctx context.Context, userID string) error
+
+	// Reactivate 使用重新激活凭证恢复账号，并签发新的令牌对（等同于一次成功登录）。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - ticket: 登录时获得的一次性重新激活凭证。
+	//  - platform: 发起请求的客户端平台类型。
+	// 返回:
+	//  - vo.Userinfo / vo.TokenPair: 与登录接口一致的用户信息与令牌对。
+	//  - error: 凭证无效、账号不处于停用状态时返回业务错误，其他失败返回系统错误。
+	Reactivate(ctx context.Context, ticket string, platform enums.Platform) (vo.Userinfo, vo.TokenPair, error)
+}
+
+// accountDeactivationService 是 AccountDeactivationService 接口的实现。
+type accountDeactivationService struct {
+	userRepo         mysql.UserRepository           // userRepo: 用户仓库。
+	reactivationRepo redis.ReactivationRepo         // reactivationRepo: 重新激活凭证仓库。
+	tokenService     token.AuthTokenService         // tokenService: 用于吊销停用请求携带的令牌，以及重新激活后签发刷新令牌。
+	jwtUtil          dependencies.JWTTokenInterface // jwtUtil: 重新激活成功后签发令牌。
+	db               *gorm.DB                       // db: 数据库连接。
+	logger           *core.ZapLogger                // logger: 日志记录器。
+}
+
+// NewAccountDeactivationService 创建一个新的 accountDeactivationService 实例。
+func NewAccountDeactivationService(
+	userRepo mysql.UserRepository,
+	reactivationRepo redis.ReactivationRepo,
+	tokenService token.AuthTokenService,
+	jwtUtil dependencies.JWTTokenInterface,
+	db *gorm.DB,
+	logger *core.ZapLogger,
+) AccountDeactivationService {
+	return &accountDeactivationService{
+		userRepo:         userRepo,
+		reactivationRepo: reactivationRepo,
+		tokenService:     tokenService,
+		jwtUtil:          jwtUtil,
+		db:               db,
+		logger:           logger,
+	}
+}
+
+// Deactivate 实现接口方法。
+func (s *accountDeactivationService) Deactivate(ctx context.Context, userID string, tokensToRevoke []string) error {
+	const operation = "AccountDeactivationService.Deactivate"
+
+	// 1. 只有活跃用户可以自行停用；被拉黑的用户不能借停用/激活绕过封禁
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("尝试停用不存在的用户", zap.String("operation", operation), zap.String("userID", userID))
+			return errors.New("用户不存在")
+		}
+		s.logger.Error("停用账号前获取用户信息失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	switch user.Status {
+	case enums.StatusActive:
+	case myenums.StatusDeactivated:
+		return errors.New("账号已处于停用状态")
+	default:
+		s.logger.Warn("非活跃状态的用户尝试停用账号", zap.String("operation", operation), zap.String("userID", userID), zap.String("status", myenums.UserStatusName(user.Status)))
+		return errors.New("当前账号状态不允许停用")
+	}
+
+	// 2. 更新状态
+	if err := s.userRepo.UpdateUserStatus(ctx, s.db, userID, myenums.StatusDeactivated); err != nil {
+		s.logger.Error("更新用户状态为停用失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+
+	// 3. 吊销本次请求携带的令牌；其他设备上的刷新令牌会因用户状态非活跃而无法续期
+	for _, t := range tokensToRevoke {
+		if t == "" {
+			continue
+		}
+		if err := s.tokenService.Logout(ctx, t); err != nil {
+			s.logger.Warn("停用账号时吊销令牌失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("用户已停用账号", zap.String("operation", operation), zap.String("userID", userID))
+	return nil
+}
+
+// ChallengeForReactivation 实现接口方法。
+func (s *accountDeactivationService) ChallengeForReactivation(ctx context.Context, userID string) error {
+	const operation = "AccountDeactivationService.ChallengeForReactivation"
+
+	ticket := uuid.New().String()
+	if err := s.reactivationRepo.SaveTicket(ctx, ticket, userID, ReactivationTicketTTL); err != nil {
+		s.logger.Error("保存重新激活凭证失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	s.logger.Info("已停用的账号登录校验通过，签发重新激活凭证", zap.String("operation", operation), zap.String("userID", userID))
+	return &AccountDeactivatedError{ReactivationTicket: ticket, ExpiresIn: ReactivationTicketTTL}
+}
+
+// Reactivate 实现接口方法。
+func (s *accountDeactivationService) Reactivate(ctx context.Context, ticket string, platform enums.Platform) (vo.Userinfo, vo.TokenPair, error) {
+	const operation = "AccountDeactivationService.Reactivate"
+	emptyUserInfo := vo.Userinfo{}
+	emptyTokenPair := vo.TokenPair{}
+
+	// 1. 消费凭证（一次性）
+	userID, err := s.reactivationRepo.ConsumeTicket(ctx, ticket)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("使用了无效或已过期的重新激活凭证", zap.String("operation", operation))
+			return emptyUserInfo, emptyTokenPair, errors.New("重新激活凭证无效或已过期，请重新登录")
+		}
+		s.logger.Error("读取重新激活凭证失败", zap.String("operation", operation), zap.Error(err))
+		return emptyUserInfo, emptyTokenPair, commonerrors.ErrSystemError
+	}
+
+	// 2. 校验用户当前确实处于停用状态
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.Error("重新激活时获取用户信息失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			return emptyUserInfo, emptyTokenPair, errors.New("用户不存在")
+		}
+		return emptyUserInfo, emptyTokenPair, commonerrors.ErrSystemError
+	}
+	if user.Status != myenums.StatusDeactivated {
+		s.logger.Warn("尝试重新激活未停用的账号", zap.String("operation", operation), zap.String("userID", userID), zap.String("status", myenums.UserStatusName(user.Status)))
+		return emptyUserInfo, emptyTokenPair, errors.New("账号未处于停用状态，无需重新激活")
+	}
+
+	// 3. 恢复为活跃状态
+	if err := s.userRepo.UpdateUserStatus(ctx, s.db, userID, enums.StatusActive); err != nil {
+		s.logger.Error("恢复用户状态为活跃失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return emptyUserInfo, emptyTokenPair, commonerrors.ErrSystemError
+	}
+
+	// 4. 签发新的令牌对
+	accessToken, err := s.jwtUtil.GenerateAccessToken(user.UserID, user.UserRole, enums.StatusActive, platform)
+	if err != nil {
+		s.logger.Error("重新激活后生成访问令牌失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return emptyUserInfo, emptyTokenPair, commonerrors.ErrSystemError
+	}
+	refreshToken, err := s.tokenService.IssueRefreshToken(ctx, user.UserID, platform)
+	if err != nil {
+		s.logger.Error("重新激活后生成刷新令牌失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return emptyUserInfo, emptyTokenPair, commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("用户已重新激活账号", zap.String("operation", operation), zap.String("userID", userID), zap.Any("platform", platform))
+	return vo.Userinfo{UserID: user.UserID}, vo.TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
@@ -0,0 +1,99 @@
+package feature
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/vo"
+)
+
+// featureFlagConfigKey 功能开关在配置文件中的顶层键名，与 UserHubConfig 的 mapstructure 标签保持一致
+const featureFlagConfigKey = "featureFlagConfig"
+
+// FeatureFlagService 定义了功能开关相关的服务接口。
+// 设计目的:
+// - 为前端提供由服务端控制的功能开关，开关内容来自配置文件，可按调用者角色覆盖。
+// - 配置文件变更后自动热重载，读取路径无锁，切换以整体快照替换的方式完成。
+type FeatureFlagService interface {
+	// GetFeatures 返回对指定调用者生效的功能开关及其 ETag。
+	// 参数:
+	//  - role: 调用者角色；为 nil 时（匿名或角色未知）仅返回全局默认开关。
+	// 返回:
+	//  - *vo.FeatureFlagsVO: 合并角色覆盖后的开关集合。
+	//  - string: 基于开关内容计算的强 ETag（已带双引号），内容不变时保持稳定。
+	GetFeatures(role *enums.UserRole) (*vo.FeatureFlagsVO, string)
+
+	// ReloadConfig 配置文件变化后重新加载功能开关，由 dependencies.WatchConfigFile 统一调用。
+	// 解析失败时保留旧快照。
+	dependencies.ConfigSubscriber
+}
+
+// featureFlagService 是 FeatureFlagService 接口的实现。
+type featureFlagService struct {
+	current atomic.Pointer[config.FeatureFlagConfig] // current: 当前生效的开关快照，热重载时整体替换。
+	logger  *core.ZapLogger                          // logger: 日志记录器。
+}
+
+// NewFeatureFlagService 创建一个新的 featureFlagService 实例。
+// 参数:
+//   - initial: 启动时加载的功能开关配置。
+//   - logger: 日志记录器实例。
+func NewFeatureFlagService(initial config.FeatureFlagConfig, logger *core.ZapLogger) FeatureFlagService {
+	s := &featureFlagService{logger: logger}
+	s.current.Store(&initial)
+	return s
+}
+
+// GetFeatures 实现接口方法，合并全局开关与角色覆盖并计算 ETag。
+func (s *featureFlagService) GetFeatures(role *enums.UserRole) (*vo.FeatureFlagsVO, string) {
+	cfg := s.current.Load()
+
+	flags := make(map[string]bool, len(cfg.Flags))
+	for name, enabled := range cfg.Flags {
+		flags[name] = enabled
+	}
+	if role != nil {
+		for name, enabled := range cfg.RoleOverrides[role.String()] {
+			flags[name] = enabled
+		}
+	}
+
+	// encoding/json 对 map 的 key 排序输出，相同内容的序列化结果稳定，可直接用于计算 ETag
+	payload, _ := json.Marshal(flags)
+	sum := sha256.Sum256(payload)
+	etag := fmt.Sprintf("%q", hex.EncodeToString(sum[:8]))
+
+	return &vo.FeatureFlagsVO{Flags: flags}, etag
+}
+
+// ReloadConfig 实现接口方法，仅重新解析功能开关部分并整体替换快照。
+func (s *featureFlagService) ReloadConfig(v *viper.Viper, file string) {
+	const operation = "FeatureFlagService.ReloadConfig"
+
+	var next config.FeatureFlagConfig
+	if err := v.UnmarshalKey(featureFlagConfigKey, &next); err != nil {
+		// 解析失败时保留旧快照，避免错误配置导致开关全部失效
+		s.logger.Error("热重载功能开关失败，继续使用旧配置",
+			zap.String("operation", operation),
+			zap.String("file", file),
+			zap.Error(err),
+		)
+		return
+	}
+	s.current.Store(&next)
+	s.logger.Info("功能开关已热重载",
+		zap.String("operation", operation),
+		zap.String("file", file),
+		zap.Int("flagCount", len(next.Flags)),
+	)
+}
@@ -0,0 +1,232 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+)
+
+var (
+	// ErrFileAccessDenied 请求的文件不属于当前用户
+	ErrFileAccessDenied = errors.New("无权访问该文件")
+	// ErrStorageQuotaExceeded 本次上传会超出用户的存储配额
+	ErrStorageQuotaExceeded = errors.New("存储空间不足，请删除部分文件后重试")
+)
+
+// FileService 定义了用户私有文件相关的服务接口。
+type FileService interface {
+	// PresignDownloadURL 为当前用户拥有的私有对象生成临时下载链接。
+	// - 对象键中 (文件名之前) 必须包含用户 ID 这一层目录，例如 "documents/{userID}/a.pdf"，否则返回 ErrFileAccessDenied。
+	// - ttlSeconds 为 0 时使用默认有效期；超过配置的最长有效期时返回业务错误。
+	PresignDownloadURL(ctx context.Context, userID string, objectKey string, ttlSeconds int) (*vo.PresignedURLVO, error)
+
+	// CheckQuota 检查用户再上传 size 字节后是否超出存储配额，超出时返回 ErrStorageQuotaExceeded。
+	CheckQuota(ctx context.Context, userID string, size int64) error
+
+	// RecordUpload 在上传成功后记录对象大小，计入用户存储用量；同一对象键覆盖写入时以新大小为准。
+	RecordUpload(ctx context.Context, userID string, objectKey string, size int64) error
+
+	// ReleaseObject 在对象被删除后移除其用量记录。
+	ReleaseObject(ctx context.Context, objectKey string) error
+
+	// OwnsObject 判断对象键是否记录为该用户上传的对象；没有记录 (如外部链接或他人的对象) 时返回 false。
+	OwnsObject(ctx context.Context, userID string, objectKey string) (bool, error)
+
+	// ReleaseUserObjects 移除用户的全部用量记录，用于软删除用户后不再计入其存储用量 (对象本身保留)。
+	ReleaseUserObjects(ctx context.Context, userID string) error
+
+	// GetUsage 返回用户当前的存储用量与配额。
+	GetUsage(ctx context.Context, userID string) (*vo.StorageUsageVO, error)
+}
+
+// fileService 是 FileService 接口的实现。
+type fileService struct {
+	cosClient  dependencies.COSClientInterface // COS 客户端
+	objectRepo mysql.StorageObjectRepository   // 上传对象记录仓库，用于统计存储用量
+	cosConfig  config.COSConfig                // COS 配置 (预签名有效期)
+	storageCfg config.StorageQuotaConfig       // 存储配额配置
+	db         *gorm.DB                        // 数据库连接
+	logger     *core.ZapLogger                 // 日志记录器
+}
+
+// NewFileService 创建一个新的 FileService 实例。
+func NewFileService(
+	cosClient dependencies.COSClientInterface,
+	objectRepo mysql.StorageObjectRepository,
+	cosConfig config.COSConfig,
+	storageCfg config.StorageQuotaConfig,
+	db *gorm.DB,
+	logger *core.ZapLogger,
+) FileService {
+	return &fileService{
+		cosClient:  cosClient,
+		objectRepo: objectRepo,
+		cosConfig:  cosConfig,
+		storageCfg: storageCfg,
+		db:         db,
+		logger:     logger,
+	}
+}
+
+// PresignDownloadURL 实现接口方法。
+func (s *fileService) PresignDownloadURL(ctx context.Context, userID string, objectKey string, ttlSeconds int) (*vo.PresignedURLVO, error) {
+	const operation = "FileService.PresignDownloadURL"
+
+	// 1. 校验有效期
+	maxTTL := s.cosConfig.PresignMaxExpireOrDefault()
+	if ttlSeconds > maxTTL {
+		return nil, fmt.Errorf("链接有效期不能超过 %d 秒", maxTTL)
+	}
+	if ttlSeconds <= 0 {
+		ttlSeconds = s.cosConfig.PresignExpireOrDefault()
+	}
+
+	// 2. 校验对象归属
+	if !objectOwnedBy(objectKey, userID) {
+		s.logger.Warn("用户尝试获取不属于自己的文件链接",
+			zap.String("operation", operation),
+			zap.String("userID", userID),
+			zap.String("objectKey", objectKey),
+		)
+		return nil, ErrFileAccessDenied
+	}
+
+	// 3. 生成预签名链接
+	ttl := time.Duration(ttlSeconds) * time.Second
+	url, err := s.cosClient.PresignGetURL(ctx, objectKey, ttl)
+	if err != nil {
+		s.logger.Error("生成预签名下载链接失败",
+			zap.String("operation", operation),
+			zap.String("userID", userID),
+			zap.String("objectKey", objectKey),
+			zap.Error(err),
+		)
+		return nil, commonerrors.ErrSystemError
+	}
+
+	return &vo.PresignedURLVO{URL: url, ExpiresAt: time.Now().Add(ttl)}, nil
+}
+
+// CheckQuota 实现接口方法。
+// - 按已用量 + 本次大小判断；覆盖写入同一对象键时旧大小也会计入，极端情况下会略微偏严。
+func (s *fileService) CheckQuota(ctx context.Context, userID string, size int64) error {
+	const operation = "FileService.CheckQuota"
+
+	used, err := s.objectRepo.SumUsageByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("查询用户存储用量失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	quota := s.storageCfg.QuotaBytes()
+	if used+size > quota {
+		s.logger.Warn("上传将超出用户存储配额",
+			zap.String("operation", operation),
+			zap.String("userID", userID),
+			zap.Int64("usedBytes", used),
+			zap.Int64("uploadBytes", size),
+			zap.Int64("quotaBytes", quota),
+		)
+		return ErrStorageQuotaExceeded
+	}
+	return nil
+}
+
+// RecordUpload 实现接口方法。
+func (s *fileService) RecordUpload(ctx context.Context, userID string, objectKey string, size int64) error {
+	const operation = "FileService.RecordUpload"
+
+	object := &entities.StorageObject{ObjectKey: objectKey, UserID: userID, SizeBytes: size}
+	if err := s.objectRepo.UpsertObject(ctx, s.db, object); err != nil {
+		s.logger.Error("记录上传对象失败", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	return nil
+}
+
+// ReleaseObject 实现接口方法。
+func (s *fileService) ReleaseObject(ctx context.Context, objectKey string) error {
+	const operation = "FileService.ReleaseObject"
+
+	if err := s.objectRepo.DeleteObject(ctx, s.db, objectKey); err != nil {
+		s.logger.Error("删除上传对象记录失败", zap.String("operation", operation), zap.String("objectKey", objectKey), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	return nil
+}
+
+// OwnsObject 实现接口方法。
+func (s *fileService) OwnsObject(ctx context.Context, userID string, objectKey string) (bool, error) {
+	const operation = "FileService.OwnsObject"
+
+	object, err := s.objectRepo.GetObject(ctx, objectKey)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			return false, nil
+		}
+		s.logger.Error("查询上传对象记录失败", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey), zap.Error(err))
+		return false, commonerrors.ErrSystemError
+	}
+	return object.UserID == userID, nil
+}
+
+// ReleaseUserObjects 实现接口方法。
+func (s *fileService) ReleaseUserObjects(ctx context.Context, userID string) error {
+	const operation = "FileService.ReleaseUserObjects"
+
+	if err := s.objectRepo.DeleteObjectsByUserID(ctx, s.db, userID); err != nil {
+		s.logger.Error("删除用户的上传对象记录失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	return nil
+}
+
+// GetUsage 实现接口方法。
+func (s *fileService) GetUsage(ctx context.Context, userID string) (*vo.StorageUsageVO, error) {
+	const operation = "FileService.GetUsage"
+
+	used, err := s.objectRepo.SumUsageByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("查询用户存储用量失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	quota := s.storageCfg.QuotaBytes()
+	return &vo.StorageUsageVO{
+		UsedBytes:      used,
+		QuotaBytes:     quota,
+		RemainingBytes: max(quota-used, 0),
+	}, nil
+}
+
+// objectOwnedBy 判断对象键的目录部分是否包含该用户 ID 这一层，并拒绝包含 "." / ".." 的路径。
+func objectOwnedBy(objectKey string, userID string) bool {
+	if userID == "" {
+		return false
+	}
+	segments := strings.Split(strings.TrimPrefix(objectKey, "/"), "/")
+	if len(segments) < 2 || segments[len(segments)-1] == "" {
+		return false
+	}
+	owned := false
+	for _, seg := range segments[:len(segments)-1] {
+		if seg == "." || seg == ".." {
+			return false
+		}
+		if seg == userID {
+			owned = true
+		}
+	}
+	return owned
+}
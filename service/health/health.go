@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/vo"
+)
+
+// errNotConfigured 依赖客户端未初始化，探测结果记为 not_configured
+var errNotConfigured = errors.New("客户端未初始化")
+
+// DependencyHealthService 定义外部依赖的深度健康检查服务。
+// 设计目的:
+// - 为运维面板与排障提供比存活检查更详细的视图：逐项探测 MySQL、Redis、COS、短信与微信服务。
+// - 各依赖并发探测且各自有超时，整体另有时限，单个慢依赖不会拖住整个检查。
+type DependencyHealthService interface {
+	// Check 并发探测所有外部依赖，返回每项的状态、耗时与失败原因。
+	// - 整体时限到达时立即返回，尚未完成的探测标记为 timeout。
+	// - 探测失败不作为错误返回，而是体现在结果的状态中。
+	Check(ctx context.Context) *vo.DeepHealthVO
+}
+
+// probe 描述一项依赖探测
+type probe struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// probeResult 是单项探测的结果
+type probeResult struct {
+	name   string
+	health vo.DependencyHealthVO
+}
+
+// dependencyHealthService 是 DependencyHealthService 接口的实现。
+type dependencyHealthService struct {
+	probes []probe                  // 待探测的依赖列表
+	cfg    config.HealthCheckConfig // 单项与整体超时配置
+	logger *core.ZapLogger          // 日志记录器
+}
+
+// NewDependencyHealthService 创建一个新的 DependencyHealthService 实例。
+// - smsClient 在短信配置缺失时为 nil，对应依赖的探测结果为 not_configured。
+func NewDependencyHealthService(
+	db *gorm.DB,
+	redisClient *redis.Client,
+	cosClient dependencies.COSClientInterface,
+	smsClient dependencies.SMSClient,
+	wechatClient dependencies.WechatClient,
+	cfg config.HealthCheckConfig,
+	logger *core.ZapLogger,
+) DependencyHealthService {
+	probes := []probe{
+		{name: "mysql", run: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}},
+		{name: "redis", run: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}},
+		{name: "cos", run: func(ctx context.Context) error {
+			if cosClient == nil {
+				return errNotConfigured
+			}
+			return cosClient.Ping(ctx) // HEAD 存储桶
+		}},
+		{name: "sms", run: func(ctx context.Context) error {
+			if smsClient == nil {
+				return errNotConfigured
+			}
+			return smsClient.Ping(ctx) // 换取一次 access_token，不发送短信
+		}},
+		{name: "wechat", run: func(ctx context.Context) error {
+			if wechatClient == nil {
+				return errNotConfigured
+			}
+			return wechatClient.Ping(ctx) // 仅检查可达性
+		}},
+	}
+	return &dependencyHealthService{
+		probes: probes,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Check 实现接口方法。
+func (s *dependencyHealthService) Check(ctx context.Context) *vo.DeepHealthVO {
+	const operation = "DependencyHealthService.Check"
+
+	overallCtx, cancel := context.WithTimeout(ctx, s.cfg.OverallTimeoutOrDefault())
+	defer cancel()
+
+	// 1. 并发执行各项探测；通道带缓冲，整体超时返回后迟到的探测也不会阻塞
+	results := make(chan probeResult, len(s.probes))
+	var wg sync.WaitGroup
+	for _, p := range s.probes {
+		wg.Add(1)
+		go func(p probe) {
+			defer wg.Done()
+			results <- probeResult{name: p.name, health: s.runProbe(overallCtx, p)}
+		}(p)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// 2. 收集结果，直到全部完成或整体时限到达
+	report := &vo.DeepHealthVO{
+		Status:       vo.DependencyStatusUp,
+		Dependencies: make(map[string]vo.DependencyHealthVO, len(s.probes)),
+	}
+	select {
+	case <-done:
+	case <-overallCtx.Done():
+		s.logger.Warn("深度健康检查达到整体时限，未完成的探测标记为超时", zap.String("operation", operation))
+	}
+	for len(results) > 0 {
+		r := <-results
+		report.Dependencies[r.name] = r.health
+	}
+	for _, p := range s.probes {
+		if _, ok := report.Dependencies[p.name]; !ok {
+			report.Dependencies[p.name] = vo.DependencyHealthVO{Status: vo.DependencyStatusTimeout, Error: "整体时限内未完成探测"}
+		}
+	}
+
+	// 3. 汇总整体状态：未配置的依赖不计入
+	for name, h := range report.Dependencies {
+		if h.Status == vo.DependencyStatusUp || h.Status == vo.DependencyStatusNotConfigured {
+			continue
+		}
+		report.Status = vo.HealthStatusDegraded
+		s.logger.Warn("依赖健康检查未通过",
+			zap.String("operation", operation),
+			zap.String("dependency", name),
+			zap.String("status", h.Status),
+			zap.String("error", h.Error),
+		)
+	}
+	return report
+}
+
+// runProbe 在单项超时内执行一次探测并记录耗时。
+func (s *dependencyHealthService) runProbe(ctx context.Context, p probe) vo.DependencyHealthVO {
+	probeCtx, cancel := context.WithTimeout(ctx, s.cfg.ProbeTimeoutOrDefault())
+	defer cancel()
+
+	start := time.Now()
+	err := p.run(probeCtx)
+	health := vo.DependencyHealthVO{Status: vo.DependencyStatusUp, LatencyMs: time.Since(start).Milliseconds()}
+	switch {
+	case errors.Is(err, errNotConfigured):
+		health.Status = vo.DependencyStatusNotConfigured
+	case err != nil:
+		health.Status = vo.DependencyStatusDown
+		health.Error = err.Error()
+	}
+	return health
+}
@@ -0,0 +1,141 @@
+package identity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// 凭证策略返回的业务错误
+var (
+	ErrCredentialNotAllowed     = errors.New("该身份类型不支持设置凭证")
+	ErrCredentialNotRetrievable = errors.New("该身份类型的凭证不可取回")
+)
+
+// CredentialStrategy 定义某一身份类型的凭证在落库前后的处理方式。
+// 设计目的:
+//   - 凭证的处理方式 (哈希、加密、原样保存、拒绝) 由身份类型决定，集中在一处配置，
+//     CreateIdentity / UpdateIdentity 不再按类型分别写特殊分支。
+type CredentialStrategy interface {
+	// Prepare 将客户端提交的凭证转换为落库的形式。
+	// - 凭证不被接受时返回业务错误 (如 ErrCredentialNotAllowed)；哈希、加密等内部失败返回其他错误，调用方应视为系统错误。
+	Prepare(credential string) (string, error)
+
+	// Reveal 将落库的凭证还原为原文，供需要使用凭证原文的内部流程 (如刷新第三方令牌)。
+	// - 单向哈希等无法还原的凭证返回 ErrCredentialNotRetrievable。
+	Reveal(stored string) (string, error)
+}
+
+// hashCredential 按密码哈希配置 (bcrypt / argon2id) 单向哈希凭证
+type hashCredential struct{}
+
+// HashCredential 返回哈希策略，适用于密码等只需校验、无需取回的凭证。
+func HashCredential() CredentialStrategy { return hashCredential{} }
+
+// Prepare 实现 CredentialStrategy 接口。
+func (hashCredential) Prepare(credential string) (string, error) {
+	hashed, err := utils.SetPassword(credential)
+	if err != nil {
+		return "", fmt.Errorf("哈希凭证失败: %w", err)
+	}
+	return hashed, nil
+}
+
+// Reveal 实现 CredentialStrategy 接口，哈希不可还原。
+func (hashCredential) Reveal(string) (string, error) { return "", ErrCredentialNotRetrievable }
+
+// encryptCredential 使用 Encryptor 可逆加密凭证
+type encryptCredential struct {
+	encryptor dependencies.Encryptor
+}
+
+// EncryptCredential 返回加密策略，适用于需要取回原文的密钥类凭证 (如第三方刷新令牌)。
+// - 启用加密前写入的明文凭证在 Reveal 时原样返回，下次更新时会被加密保存。
+func EncryptCredential(encryptor dependencies.Encryptor) CredentialStrategy {
+	return encryptCredential{encryptor: encryptor}
+}
+
+// Prepare 实现 CredentialStrategy 接口。
+func (s encryptCredential) Prepare(credential string) (string, error) {
+	if credential == "" {
+		return "", nil
+	}
+	encrypted, err := s.encryptor.Encrypt(credential)
+	if err != nil {
+		return "", fmt.Errorf("加密凭证失败: %w", err)
+	}
+	return encrypted, nil
+}
+
+// Reveal 实现 CredentialStrategy 接口。
+func (s encryptCredential) Reveal(stored string) (string, error) {
+	if !s.encryptor.IsEncrypted(stored) {
+		return stored, nil
+	}
+	plaintext, err := s.encryptor.Decrypt(stored)
+	if err != nil {
+		return "", fmt.Errorf("解密凭证失败: %w", err)
+	}
+	return plaintext, nil
+}
+
+// plainCredential 原样保存凭证
+type plainCredential struct{}
+
+// StorePlainCredential 返回原样保存策略，只应用于本身不敏感的值。
+func StorePlainCredential() CredentialStrategy { return plainCredential{} }
+
+// Prepare 实现 CredentialStrategy 接口。
+func (plainCredential) Prepare(credential string) (string, error) { return credential, nil }
+
+// Reveal 实现 CredentialStrategy 接口。
+func (plainCredential) Reveal(stored string) (string, error) { return stored, nil }
+
+// rejectCredential 拒绝任何非空凭证
+type rejectCredential struct{}
+
+// RejectCredential 返回拒绝策略，适用于不以凭证登录的身份类型；空凭证按空值保存。
+func RejectCredential() CredentialStrategy { return rejectCredential{} }
+
+// Prepare 实现 CredentialStrategy 接口。
+func (rejectCredential) Prepare(credential string) (string, error) {
+	if credential != "" {
+		return "", ErrCredentialNotAllowed
+	}
+	return "", nil
+}
+
+// Reveal 实现 CredentialStrategy 接口。
+func (rejectCredential) Reveal(string) (string, error) { return "", ErrCredentialNotRetrievable }
+
+// CredentialStrategies 按身份类型索引的凭证处理策略；未登记的身份类型一律拒绝凭证。
+type CredentialStrategies map[enums.IdentityType]CredentialStrategy
+
+// DefaultCredentialStrategies 返回默认的凭证处理策略
+// - 账号密码：始终哈希保存，即使出现在 encryptedTypes 中也不会改为加密。
+// - encryptedTypes 中的其他类型：使用 encryptor 加密保存。
+// - 微信与手机号 (未配置加密时)：登录不依赖凭证，为兼容已有调用方仍原样保存提交的值。
+func DefaultCredentialStrategies(encryptor dependencies.Encryptor, encryptedTypes []enums.IdentityType) CredentialStrategies {
+	strategies := CredentialStrategies{
+		enums.AccountPassword:   HashCredential(),
+		enums.WechatMiniProgram: StorePlainCredential(),
+		enums.Phone:             StorePlainCredential(),
+	}
+	for _, identityType := range encryptedTypes {
+		if identityType != enums.AccountPassword {
+			strategies[identityType] = EncryptCredential(encryptor)
+		}
+	}
+	return strategies
+}
+
+// For 返回指定身份类型的策略，未登记时返回 RejectCredential。
+func (cs CredentialStrategies) For(identityType enums.IdentityType) CredentialStrategy {
+	if strategy, ok := cs[identityType]; ok {
+		return strategy
+	}
+	return RejectCredential()
+}
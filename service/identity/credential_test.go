@@ -0,0 +1,149 @@
+package identity
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// newTestEncryptor 创建使用固定 32 字节密钥的加密器
+func newTestEncryptor(t *testing.T) dependencies.Encryptor {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	encryptor, err := dependencies.NewEncryptor(&config.CredentialEncryptionConfig{
+		ActiveKeyID: "k1",
+		Keys:        []config.CredentialEncryptionKey{{ID: "k1", Key: key}},
+	})
+	if err != nil {
+		t.Fatalf("创建加密器失败: %v", err)
+	}
+	return encryptor
+}
+
+func TestHashCredential(t *testing.T) {
+	strategy := HashCredential()
+
+	stored, err := strategy.Prepare("secret-password")
+	if err != nil {
+		t.Fatalf("Prepare 失败: %v", err)
+	}
+	if stored == "secret-password" {
+		t.Fatal("哈希策略不应原样保存凭证")
+	}
+	if err := utils.CheckPassword(stored, "secret-password"); err != nil {
+		t.Errorf("哈希结果应能通过密码校验: %v", err)
+	}
+	if _, err := strategy.Reveal(stored); !errors.Is(err, ErrCredentialNotRetrievable) {
+		t.Errorf("Reveal 期望 ErrCredentialNotRetrievable，实际为 %v", err)
+	}
+}
+
+func TestEncryptCredential(t *testing.T) {
+	encryptor := newTestEncryptor(t)
+	strategy := EncryptCredential(encryptor)
+
+	stored, err := strategy.Prepare("refresh-token")
+	if err != nil {
+		t.Fatalf("Prepare 失败: %v", err)
+	}
+	if !encryptor.IsEncrypted(stored) || strings.Contains(stored, "refresh-token") {
+		t.Fatalf("加密策略应保存密文，实际为 %q", stored)
+	}
+	if revealed, err := strategy.Reveal(stored); err != nil || revealed != "refresh-token" {
+		t.Errorf("Reveal = %q, %v，期望还原为 refresh-token", revealed, err)
+	}
+
+	// 空凭证不加密，按空值保存
+	if stored, err := strategy.Prepare(""); err != nil || stored != "" {
+		t.Errorf("Prepare(\"\") = %q, %v，期望空值", stored, err)
+	}
+
+	// 启用加密前写入的明文原样返回
+	if revealed, err := strategy.Reveal("legacy-plaintext"); err != nil || revealed != "legacy-plaintext" {
+		t.Errorf("明文凭证 Reveal = %q, %v，期望原样返回", revealed, err)
+	}
+
+	// 被篡改的密文返回系统错误而非业务错误
+	tampered := stored[:len(stored)-2] + "xx"
+	if _, err := strategy.Reveal(tampered); err == nil || errors.Is(err, ErrCredentialNotRetrievable) {
+		t.Errorf("篡改的密文期望返回解密错误，实际为 %v", err)
+	}
+}
+
+func TestEncryptCredentialWithoutKeys(t *testing.T) {
+	encryptor, err := dependencies.NewEncryptor(&config.CredentialEncryptionConfig{})
+	if err != nil {
+		t.Fatalf("创建加密器失败: %v", err)
+	}
+	if _, err := EncryptCredential(encryptor).Prepare("refresh-token"); !errors.Is(err, dependencies.ErrEncryptionNotConfigured) {
+		t.Errorf("未配置密钥时期望 ErrEncryptionNotConfigured，实际为 %v", err)
+	}
+}
+
+func TestPlainCredential(t *testing.T) {
+	strategy := StorePlainCredential()
+	for _, credential := range []string{"", "session-key"} {
+		stored, err := strategy.Prepare(credential)
+		if err != nil || stored != credential {
+			t.Errorf("Prepare(%q) = %q, %v，期望原样保存", credential, stored, err)
+		}
+		if revealed, err := strategy.Reveal(stored); err != nil || revealed != credential {
+			t.Errorf("Reveal(%q) = %q, %v，期望原样返回", stored, revealed, err)
+		}
+	}
+}
+
+func TestRejectCredential(t *testing.T) {
+	strategy := RejectCredential()
+	if _, err := strategy.Prepare("anything"); !errors.Is(err, ErrCredentialNotAllowed) {
+		t.Errorf("非空凭证期望 ErrCredentialNotAllowed，实际为 %v", err)
+	}
+	if stored, err := strategy.Prepare(""); err != nil || stored != "" {
+		t.Errorf("Prepare(\"\") = %q, %v，期望空值", stored, err)
+	}
+	if _, err := strategy.Reveal(""); !errors.Is(err, ErrCredentialNotRetrievable) {
+		t.Errorf("Reveal 期望 ErrCredentialNotRetrievable，实际为 %v", err)
+	}
+}
+
+func TestDefaultCredentialStrategies(t *testing.T) {
+	encryptor := newTestEncryptor(t)
+
+	tests := []struct {
+		name           string
+		encryptedTypes []enums.IdentityType
+		identityType   enums.IdentityType
+		want           CredentialStrategy
+	}{
+		{name: "账号密码默认哈希", identityType: enums.AccountPassword, want: HashCredential()},
+		{name: "账号密码在加密列表中仍哈希", encryptedTypes: []enums.IdentityType{enums.AccountPassword}, identityType: enums.AccountPassword, want: HashCredential()},
+		{name: "微信默认原样保存", identityType: enums.WechatMiniProgram, want: StorePlainCredential()},
+		{name: "微信在加密列表中改为加密", encryptedTypes: []enums.IdentityType{enums.WechatMiniProgram}, identityType: enums.WechatMiniProgram, want: EncryptCredential(encryptor)},
+		{name: "手机号默认原样保存", identityType: enums.Phone, want: StorePlainCredential()},
+		{name: "未登记类型拒绝凭证", identityType: enums.IdentityType(99), want: RejectCredential()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultCredentialStrategies(encryptor, tt.encryptedTypes).For(tt.identityType)
+			if got != tt.want {
+				t.Errorf("策略 = %T，期望 %T", got, tt.want)
+			}
+		})
+	}
+
+	// 账号密码即使出现在加密列表中，保存的也是可校验的哈希而非密文
+	stored, err := DefaultCredentialStrategies(encryptor, []enums.IdentityType{enums.AccountPassword}).
+		For(enums.AccountPassword).Prepare("secret-password")
+	if err != nil {
+		t.Fatalf("Prepare 失败: %v", err)
+	}
+	if encryptor.IsEncrypted(stored) || utils.CheckPassword(stored, "secret-password") != nil {
+		t.Errorf("账号密码应哈希保存，实际为 %q", stored)
+	}
+}
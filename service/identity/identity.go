@@ -8,16 +8,49 @@ import (
 	"github.com/Xushengqwer/go-common/core" // 引入日志包
 	"go.uber.org/zap"                       // 引入 zap 用于日志字段
 
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/entities"
 	"github.com/Xushengqwer/user_hub/models/enums"
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/password"
 	"github.com/Xushengqwer/user_hub/utils" // 引入密码工具
 
 	"gorm.io/gorm"
 )
 
+// 身份管理的业务错误
+var (
+	ErrIdentityNotFound  = errors.New("身份记录不存在")                // 身份不存在，或不属于当前用户
+	ErrLastIdentity      = errors.New("不能解绑唯一的登录方式，请先绑定其他登录方式") // 解绑后用户将无法登录
+	ErrIdentityForbidden = errors.New("无权操作该身份记录")              // 非管理员操作不属于自己的身份
+	ErrUserNotFound      = errors.New("用户不存在")                  // 查询的用户不存在 (区别于用户存在但没有任何身份)
+)
+
+// IdentityListOptions 描述查询身份列表时的调用者权限与分页参数。
+// - Privileged 为 true (管理员) 时返回完整、不分页的列表，忽略 Page/PageSize；RevealFull 仅在此时生效。
+// - 非特权调用始终脱敏标识符，并按页返回：Page 默认为 1，PageSize 默认且最大为 identityListConfig.page_size。
+type IdentityListOptions struct {
+	Privileged bool // Privileged: 调用者是否为管理员
+	RevealFull bool // RevealFull: 是否返回完整标识符，仅特权调用可用
+	Page       int  // Page: 页码，从 1 开始
+	PageSize   int  // PageSize: 每页条数
+}
+
+// Caller 描述发起身份修改操作的调用者，用于服务层的归属校验。
+// - 不完全依赖网关的权限判断：即使网关配置失误放行了请求，非管理员也只能操作自己的身份。
+type Caller struct {
+	UserID  string // 调用者用户 ID (来自网关透传的认证信息)
+	IsAdmin bool   // 调用者是否为管理员；管理员可操作任意用户的身份
+}
+
+// canOperate 判断调用者能否操作属于 ownerID 的身份
+func (c Caller) canOperate(ownerID string) bool {
+	return c.IsAdmin || (c.UserID != "" && c.UserID == ownerID)
+}
+
 // UserIdentityService 定义了管理用户多种身份标识（如账号密码、微信、手机号等）的服务接口。
 // 设计目的:
 // - 将用户身份凭证的管理与核心用户属性、具体的认证流程（如登录、注册）解耦。
@@ -40,36 +73,53 @@ type UserIdentityService interface {
 
 	// UpdateIdentity 更新指定身份ID的凭证信息。
 	// 使用场景:
-	//  - 用户修改其账号密码登录方式的密码。
+	//  - 用户修改其账号密码登录方式的密码（新密码不能与当前密码及最近 N 个历史密码相同）。
 	//  - 系统更新了某个OAuth身份的访问令牌（虽然此场景下通常是更新凭证，但具体取决于OAuth流程）。
 	// 参数:
+	//  - caller: 调用者；非管理员只能更新属于自己的身份。
 	//  - identityID: 要更新的身份记录的数据库主键ID。
 	//  - dto: 包含新凭证信息的数据传输对象。
 	// 返回:
 	//  - *vo.IdentityVO: 更新后的身份信息的视图对象。
-	//  - error: 操作过程中发生的任何错误。
-	UpdateIdentity(ctx context.Context, identityID uint, dto *dto.UpdateIdentityDTO) (*vo.IdentityVO, error)
+	//  - error: 操作过程中发生的任何错误；身份不属于非管理员调用者时返回 ErrIdentityForbidden。
+	UpdateIdentity(ctx context.Context, caller Caller, identityID uint, dto *dto.UpdateIdentityDTO) (*vo.IdentityVO, error)
 
 	// DeleteIdentity 删除指定ID的用户身份记录。
 	// 使用场景:
 	//  - 用户解绑某个登录方式（例如，不再使用微信登录）。
 	//  - 管理员移除某个用户的特定登录凭证。
 	// 参数:
+	//  - caller: 调用者；非管理员只能删除属于自己的身份。
 	//  - identityID: 要删除的身份记录的数据库主键ID。
 	// 返回:
-	//  - error: 操作过程中发生的任何错误。
-	DeleteIdentity(ctx context.Context, identityID uint) error
+	//  - error: 操作过程中发生的任何错误；身份不属于非管理员调用者时返回 ErrIdentityForbidden，身份不存在视为成功 (幂等)；
+	//    非管理员删除自己唯一的登录方式时返回 ErrLastIdentity。
+	DeleteIdentity(ctx context.Context, caller Caller, identityID uint) error
 
-	// GetIdentitiesByUserID 检索指定用户ID关联的所有身份记录。
+	// UnbindMyIdentity 用户自助解绑自己的某个登录方式。
 	// 使用场景:
-	//  - 用户在个人资料页面查看自己已绑定的所有登录方式。
-	//  - 管理员后台查看某个用户的全部身份凭证信息（不含敏感凭证内容）。
+	//  - 用户在账号安全页移除不再使用的登录方式。
+	// 规则:
+	//  - 身份必须属于 userID，否则按不存在处理 (不泄露他人身份是否存在)。
+	//  - 不能解绑唯一的登录方式；在事务中锁定该用户的身份后再判断，避免并发解绑把身份删光。
+	// 参数:
+	//  - userID: 当前认证用户的ID。
+	//  - identityID: 要解绑的身份记录ID。
+	// 返回:
+	//  - error: 身份不存在或不属于该用户返回 ErrIdentityNotFound，唯一登录方式返回 ErrLastIdentity，其他失败返回系统错误。
+	UnbindMyIdentity(ctx context.Context, userID string, identityID uint) error
+
+	// GetIdentitiesByUserID 检索指定用户ID关联的身份记录。
+	// 使用场景:
+	//  - 用户在个人资料页面查看自己已绑定的登录方式 (按页返回，标识符脱敏)。
+	//  - 管理员后台查看某个用户的全部身份凭证信息（不含敏感凭证内容，不分页）。
 	// 参数:
 	//  - userID: 要查询的用户ID。
+	//  - opts: 调用者权限与分页参数，见 IdentityListOptions。
 	// 返回:
-	//  - []*vo.IdentityVO: 用户身份信息视图对象的列表。如果用户没有任何身份记录，返回空列表。
-	//  - error: 操作过程中发生的任何错误。
-	GetIdentitiesByUserID(ctx context.Context, userID string) ([]*vo.IdentityVO, error)
+	//  - vo.IdentityList: 身份列表信封。如果用户存在但没有任何身份记录，返回空列表。
+	//  - error: 用户不存在返回 ErrUserNotFound，其他失败返回系统错误。
+	GetIdentitiesByUserID(ctx context.Context, userID string, opts IdentityListOptions) (vo.IdentityList, error)
 
 	// GetIdentityTypesByUserID 检索指定用户ID所拥有的所有身份类型。
 	// 使用场景:
@@ -78,21 +128,59 @@ type UserIdentityService interface {
 	// 参数:
 	//  - userID: 要查询的用户ID。
 	// 返回:
-	//  - []enums.IdentityType: 用户身份类型的枚举列表。如果用户没有任何身份记录，返回空列表。
-	//  - error: 操作过程中发生的任何错误。
+	//  - []enums.IdentityType: 用户身份类型的枚举列表。如果用户存在但没有任何身份记录，返回空列表。
+	//  - error: 用户不存在返回 ErrUserNotFound，其他失败返回系统错误。
 	GetIdentityTypesByUserID(ctx context.Context, userID string) ([]enums.IdentityType, error)
+
+	// GetLoginMethodsByUserID 检索指定用户已绑定的登录方式及其元数据。
+	// 使用场景:
+	//  - 账号安全页一次性展示所有登录方式：类型、脱敏标识符、是否已验证、是否主登录方式、最近使用时间。
+	// 参数:
+	//  - userID: 要查询的用户ID。
+	// 返回:
+	//  - []*vo.LoginMethodVO: 登录方式列表。如果用户没有任何身份记录，返回空列表。
+	//  - error: 操作过程中发生的任何错误。
+	GetLoginMethodsByUserID(ctx context.Context, userID string) ([]*vo.LoginMethodVO, error)
+
+	// VerifyAndBindPhone 校验短信验证码，并为指定用户绑定一个已验证的手机号身份，不创建登录会话。
+	// 使用场景:
+	//  - 使用账号密码或微信注册的用户补充绑定手机号。
+	//  - 用户此前已绑定但未验证的同一手机号，验证后标记为已验证。
+	// 参数:
+	//  - userID: 当前认证用户的ID。
+	//  - phone: 要绑定的手机号。
+	//  - code: 用户收到的短信验证码。
+	// 返回:
+	//  - *vo.IdentityVO: 绑定（或标记为已验证）后的手机号身份。
+	//  - error: 验证码错误、手机号已被其他账号绑定、当前账号已绑定其他手机号等业务错误，或系统错误。
+	VerifyAndBindPhone(ctx context.Context, userID string, phone string, code string) (*vo.IdentityVO, error)
+
+	// RevealCredential 读取并还原指定身份的凭证原文，仅供内部流程使用，不对外暴露为 HTTP 接口。
+	// 使用场景:
+	//  - 使用加密保存的第三方刷新令牌换取新的访问令牌。
+	// 参数:
+	//  - identityID: 身份记录的数据库主键ID。
+	// 返回:
+	//  - string: 凭证原文。
+	//  - error: 记录不存在、凭证不可取回 (ErrCredentialNotRetrievable，如密码哈希) 等业务错误，或解密失败等系统错误。
+	RevealCredential(ctx context.Context, identityID uint) (string, error)
 }
 
 // userIdentityService 是 UserIdentityService 接口的实现。
 // 它封装了与用户身份相关的业务逻辑和数据持久化操作。
 type userIdentityService struct {
-	repo mysql.IdentityRepository // repo: 身份数据仓库，负责与数据库直接交互。
-	db   *gorm.DB                 // db: GORM数据库连接实例。主要用于将原始连接传递给仓库层方法，
+	repo     mysql.IdentityRepository // repo: 身份数据仓库，负责与数据库直接交互。
+	userRepo mysql.UserRepository     // userRepo: 用户仓库，查询身份列表时区分“用户不存在”与“没有身份”。
+	db       *gorm.DB                 // db: GORM数据库连接实例。主要用于将原始连接传递给仓库层方法，
 	// 因为此服务中的每个方法通常代表一个独立的、原子性的操作单元。
 	// 如果这些方法需要被编排进一个更大的、跨多个服务方法或仓库方法的事务，
 	// 那么事务的开启和管理应在更高层（如应用服务编排层或特定的业务流程服务）进行，
 	// 并将事务性 `*gorm.DB` (即 `tx`) 传递给底层的仓库方法。
-	logger *core.ZapLogger // logger: 日志记录器，用于记录操作信息和错误。
+	logger          *core.ZapLogger                 // logger: 日志记录器，用于记录操作信息和错误。
+	passwordHistory password.PasswordHistoryService // passwordHistory: 历史密码校验与记录服务，修改账号密码时使用。
+	codeRepo        redis.CodeRepo                  // codeRepo: 短信验证码仓库，验证并绑定手机号时使用。
+	credentials     CredentialStrategies            // credentials: 各身份类型凭证落库前的处理策略。
+	listConfig      config.IdentityListConfig       // listConfig: 非管理员查询身份列表时的分页上限。
 }
 
 // NewUserIdentityService 创建一个新的 userIdentityService 实例。
@@ -101,13 +189,23 @@ type userIdentityService struct {
 // - 这种设计提高了代码的可测试性（可以mock依赖）和灵活性（方便替换实现）。
 func NewUserIdentityService(
 	repo mysql.IdentityRepository,
+	userRepo mysql.UserRepository,
 	db *gorm.DB,
 	logger *core.ZapLogger,
+	passwordHistory password.PasswordHistoryService,
+	codeRepo redis.CodeRepo,
+	credentials CredentialStrategies,
+	listConfig config.IdentityListConfig,
 ) UserIdentityService {
 	return &userIdentityService{
-		repo:   repo,
-		db:     db,
-		logger: logger,
+		repo:            repo,
+		userRepo:        userRepo,
+		db:              db,
+		logger:          logger,
+		passwordHistory: passwordHistory,
+		codeRepo:        codeRepo,
+		credentials:     credentials,
+		listConfig:      listConfig,
 	}
 }
 
@@ -130,27 +228,42 @@ func entityToVO(identity *entities.UserIdentity) *vo.IdentityVO {
 	}
 }
 
+// entityToLoginMethodVO 将身份实体转换为登录方式视图对象，标识符按类型脱敏。
+func entityToLoginMethodVO(identity *entities.UserIdentity) *vo.LoginMethodVO {
+	if identity == nil {
+		return nil
+	}
+	return &vo.LoginMethodVO{
+		IdentityID:       identity.IdentityID,
+		IdentityType:     identity.IdentityType,
+		MaskedIdentifier: utils.MaskIdentifier(identity.IdentityType, identity.Identifier),
+		Verified:         identity.Verified,
+		Primary:          identity.IsPrimary,
+		LastUsedAt:       identity.LastUsedAt,
+		CreatedAt:        identity.CreatedAt,
+	}
+}
+
 // CreateIdentity 实现接口方法，为用户创建新的身份标识。
 func (s *userIdentityService) CreateIdentity(ctx context.Context, dto *dto.CreateIdentityDTO) (*vo.IdentityVO, error) {
 	const operation = "UserIdentityService.CreateIdentity" // 用于日志和错误追踪的操作标识
 
 	// 1. 准备身份实体 (Data Preparation and Validation)
-	//    - 对于账号密码类型的身份，凭证（密码）在存储前必须进行哈希处理。
-	//    - 其他类型的身份凭证可能不需要特殊处理，或有其自身的验证逻辑（例如OAuth token）。
-	credential := dto.Credential
-	if dto.IdentityType == enums.AccountPassword { // 假设 enums.AccountPassword 已在公共模块定义
-		hashedPassword, err := utils.SetPassword(dto.Credential) // 使用密码工具进行哈希
-		if err != nil {
-			s.logger.Error("创建身份时密码加密失败",
-				zap.String("operation", operation),
-				zap.String("userID", dto.UserID),
-				zap.Any("identityType", dto.IdentityType),
-				zap.Error(err), // 记录原始加密错误
-			)
-			// 密码加密失败是系统内部问题，向上层返回通用系统错误。
-			return nil, commonerrors.ErrSystemError
-		}
-		credential = hashedPassword
+	//    - 凭证按身份类型的策略处理：账号密码哈希保存，其他类型按各自策略加密、原样保存或拒绝。
+	credential, err := s.prepareCredential(operation, dto.IdentityType, dto.Credential)
+	if err != nil {
+		return nil, err
+	}
+
+	// 用户尚无任何身份时，新建的身份即为主登录方式
+	existingTypes, err := s.repo.GetIdentityTypesByUserID(ctx, dto.UserID)
+	if err != nil {
+		s.logger.Error("创建身份前查询用户已有身份失败",
+			zap.String("operation", operation),
+			zap.String("userID", dto.UserID),
+			zap.Error(err),
+		)
+		return nil, commonerrors.ErrSystemError
 	}
 
 	identityEntity := &entities.UserIdentity{
@@ -158,6 +271,9 @@ func (s *userIdentityService) CreateIdentity(ctx context.Context, dto *dto.Creat
 		IdentityType: dto.IdentityType,
 		Identifier:   dto.Identifier,
 		Credential:   credential, // 使用处理后（可能已加密）的凭证
+		// 账号由用户自行设定无需验证；手机号、OpenID 等直接绑定时未经过验证流程
+		Verified:  dto.IdentityType == enums.AccountPassword,
+		IsPrimary: len(existingTypes) == 0,
 	}
 
 	// 2. 调用仓库层创建身份记录
@@ -191,7 +307,7 @@ func (s *userIdentityService) CreateIdentity(ctx context.Context, dto *dto.Creat
 }
 
 // UpdateIdentity 实现接口方法，更新指定身份的凭证。
-func (s *userIdentityService) UpdateIdentity(ctx context.Context, identityID uint, dto *dto.UpdateIdentityDTO) (*vo.IdentityVO, error) {
+func (s *userIdentityService) UpdateIdentity(ctx context.Context, caller Caller, identityID uint, dto *dto.UpdateIdentityDTO) (*vo.IdentityVO, error) {
 	const operation = "UserIdentityService.UpdateIdentity"
 
 	// 1. 查询目标身份记录是否存在
@@ -216,30 +332,40 @@ func (s *userIdentityService) UpdateIdentity(ctx context.Context, identityID uin
 		)
 		return nil, commonerrors.ErrSystemError
 	}
+	if !caller.canOperate(identityEntity.UserID) {
+		s.logger.Warn("非管理员尝试更新不属于自己的身份", zap.String("operation", operation), zap.String("callerID", caller.UserID), zap.Uint("identityID", identityID))
+		return nil, ErrIdentityForbidden
+	}
 
 	// 2. 准备新的凭证
-	//    - 同样，如果身份类型是账号密码，新凭证需要加密。
-	newCredential := dto.Credential
-	if identityEntity.IdentityType == enums.AccountPassword {
-		hashedPassword, err := utils.SetPassword(dto.Credential)
-		if err != nil {
-			s.logger.Error("更新身份时密码加密失败",
-				zap.String("operation", operation),
-				zap.Uint("identityID", identityID),
-				zap.Error(err),
-			)
-			return nil, commonerrors.ErrSystemError
+	//    - 账号密码类型的新密码不能与当前密码及最近 N 个历史密码相同；凭证本身按身份类型的策略处理。
+	isPassword := identityEntity.IdentityType == enums.AccountPassword
+	if isPassword {
+		if err := s.passwordHistory.EnsureNotReused(ctx, identityEntity.UserID, identityEntity.Credential, dto.Credential); err != nil {
+			return nil, err
 		}
-		newCredential = hashedPassword
+	}
+	newCredential, err := s.prepareCredential(operation, identityEntity.IdentityType, dto.Credential)
+	if err != nil {
+		return nil, err
 	}
 	identityEntity.Credential = newCredential // 更新实体中的凭证
 
-	// 3. 调用仓库层更新身份记录
-	if err := s.repo.UpdateIdentity(ctx, identityEntity); err != nil {
+	// 3. 在事务中更新身份记录；账号密码类型同时写入历史密码并裁剪到最近 N 条
+	txErr := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.UpdateIdentity(ctx, tx, identityEntity); err != nil {
+			return err
+		}
+		if isPassword {
+			return s.passwordHistory.Record(ctx, tx, identityEntity.UserID, newCredential)
+		}
+		return nil
+	})
+	if txErr != nil {
 		s.logger.Error("调用仓库更新身份失败",
 			zap.String("operation", operation),
 			zap.Uint("identityID", identityID),
-			zap.Error(err),
+			zap.Error(txErr),
 		)
 		return nil, commonerrors.ErrSystemError
 	}
@@ -253,11 +379,90 @@ func (s *userIdentityService) UpdateIdentity(ctx context.Context, identityID uin
 	return entityToVO(identityEntity), nil
 }
 
+// prepareCredential 按身份类型的策略处理凭证；策略拒绝的凭证原样返回业务错误，处理失败映射为系统错误。
+func (s *userIdentityService) prepareCredential(operation string, identityType enums.IdentityType, credential string) (string, error) {
+	prepared, err := s.credentials.For(identityType).Prepare(credential)
+	if err == nil {
+		return prepared, nil
+	}
+	if errors.Is(err, ErrCredentialNotAllowed) {
+		return "", err
+	}
+	s.logger.Error("处理身份凭证失败",
+		zap.String("operation", operation),
+		zap.Any("identityType", identityType),
+		zap.Error(err),
+	)
+	return "", commonerrors.ErrSystemError
+}
+
+// UnbindMyIdentity 实现接口方法，用户自助解绑登录方式。
+func (s *userIdentityService) UnbindMyIdentity(ctx context.Context, userID string, identityID uint) error {
+	const operation = "UserIdentityService.UnbindMyIdentity"
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		identities, err := s.repo.LockIdentitiesByUserID(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		owned := false
+		for _, identity := range identities {
+			if identity.IdentityID == identityID {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			return ErrIdentityNotFound
+		}
+		if len(identities) <= 1 {
+			return ErrLastIdentity
+		}
+		return s.repo.DeleteIdentity(ctx, tx, identityID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrIdentityNotFound) || errors.Is(err, ErrLastIdentity) {
+			s.logger.Warn("用户解绑登录方式被拒绝", zap.String("operation", operation), zap.String("userID", userID), zap.Uint("identityID", identityID), zap.Error(err))
+			return err
+		}
+		s.logger.Error("用户解绑登录方式失败", zap.String("operation", operation), zap.String("userID", userID), zap.Uint("identityID", identityID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("用户已解绑登录方式", zap.String("operation", operation), zap.String("userID", userID), zap.Uint("identityID", identityID))
+	return nil
+}
+
 // DeleteIdentity 实现接口方法，删除指定的用户身份。
-func (s *userIdentityService) DeleteIdentity(ctx context.Context, identityID uint) error {
+func (s *userIdentityService) DeleteIdentity(ctx context.Context, caller Caller, identityID uint) error {
 	const operation = "UserIdentityService.DeleteIdentity"
 
-	// 1. 调用仓库层删除身份记录
+	// 1. 校验归属；身份不存在时删除是幂等的，直接视为成功
+	identityEntity, err := s.repo.GetIdentityByID(ctx, identityID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("尝试删除不存在的身份记录，操作视为成功（幂等）", zap.String("operation", operation), zap.Uint("identityID", identityID))
+			return nil
+		}
+		s.logger.Error("删除身份前查询记录失败", zap.String("operation", operation), zap.Uint("identityID", identityID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	if !caller.canOperate(identityEntity.UserID) {
+		s.logger.Warn("非管理员尝试删除不属于自己的身份", zap.String("operation", operation), zap.String("callerID", caller.UserID), zap.Uint("identityID", identityID))
+		return ErrIdentityForbidden
+	}
+
+	// 1.1 非管理员删除自己的身份与自助解绑走同一流程，在锁内确认不是唯一的登录方式
+	if !caller.IsAdmin {
+		err := s.UnbindMyIdentity(ctx, caller.UserID, identityID)
+		if errors.Is(err, ErrIdentityNotFound) {
+			// 校验归属后身份已被并发删除，与上面的幂等处理保持一致
+			return nil
+		}
+		return err
+	}
+
+	// 2. 调用仓库层删除身份记录
 	if err := s.repo.DeleteIdentity(ctx, s.db, identityID); err != nil {
 		// 对于删除操作，如果记录本身未找到 (ErrRepoNotFound)，通常不视为一个需要向上层报错的“失败”。
 		// 操作是幂等的：删除一个不存在的东西和成功删除它，最终状态是一样的（它不存在）。
@@ -284,13 +489,16 @@ func (s *userIdentityService) DeleteIdentity(ctx context.Context, identityID uin
 	return nil
 }
 
-// GetIdentitiesByUserID 实现接口方法，获取用户的所有身份信息。
-func (s *userIdentityService) GetIdentitiesByUserID(ctx context.Context, userID string) ([]*vo.IdentityVO, error) {
+// GetIdentitiesByUserID 实现接口方法，获取用户的身份信息。
+func (s *userIdentityService) GetIdentitiesByUserID(ctx context.Context, userID string, opts IdentityListOptions) (vo.IdentityList, error) {
 	const operation = "UserIdentityService.GetIdentitiesByUserID"
 
-	// 1. 调用仓库层获取身份实体列表
-	//    - 这是一个只读操作，通常可以直接使用仓库内部的 s.db，除非有特殊需要在事务中读取。
-	//    - 假设 s.repo.GetIdentitiesByUserID 签名未变。
+	// 0. 先确认用户存在，使“用户不存在”与“用户没有任何身份”可以区分
+	if err := s.ensureUserExists(ctx, operation, userID); err != nil {
+		return vo.IdentityList{}, err
+	}
+
+	// 1. 调用仓库层获取身份实体列表 (单个用户的身份数量有限，分页在内存中完成)
 	identityEntities, err := s.repo.GetIdentitiesByUserID(ctx, userID)
 	if err != nil {
 		s.logger.Error("调用仓库获取用户身份列表失败",
@@ -298,28 +506,55 @@ func (s *userIdentityService) GetIdentitiesByUserID(ctx context.Context, userID
 			zap.String("userID", userID),
 			zap.Error(err),
 		)
-		return nil, commonerrors.ErrSystemError
+		return vo.IdentityList{}, commonerrors.ErrSystemError
 	}
 
-	// 2. 将实体列表转换为视图对象列表
+	// 2. 非特权调用按页截取
+	total := int64(len(identityEntities))
+	page, pageSize := 0, 0
+	if !opts.Privileged {
+		page, pageSize = max(opts.Page, 1), opts.PageSize
+		if maxPageSize := s.listConfig.PageSizeOrDefault(); pageSize <= 0 || pageSize > maxPageSize {
+			pageSize = maxPageSize
+		}
+		start := min((page-1)*pageSize, len(identityEntities))
+		end := min(start+pageSize, len(identityEntities))
+		identityEntities = identityEntities[start:end]
+	}
+
+	// 3. 将实体列表转换为视图对象列表
 	//    - 如果没有记录，会返回一个空的 vo.IdentityVO 切片，这是期望的行为。
+	//    - 仅特权调用显式要求时返回完整标识符，其余情况脱敏，避免完整手机号、邮箱外泄。
+	revealFull := opts.Privileged && opts.RevealFull
 	identityVOs := make([]*vo.IdentityVO, 0, len(identityEntities))
 	for _, entity := range identityEntities {
-		identityVOs = append(identityVOs, entityToVO(entity))
+		identityVO := entityToVO(entity)
+		if !revealFull {
+			identityVO.Identifier = utils.MaskIdentifier(entity.IdentityType, entity.Identifier)
+		}
+		identityVOs = append(identityVOs, identityVO)
 	}
 
 	s.logger.Info("成功获取用户身份列表",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
 		zap.Int("count", len(identityVOs)), // 记录获取到的数量
+		zap.Int64("total", total),
+		zap.Bool("privileged", opts.Privileged),
+		zap.Bool("revealFull", revealFull),
 	)
-	return identityVOs, nil
+	return vo.NewPageVO(identityVOs, total, page, pageSize), nil
 }
 
 // GetIdentityTypesByUserID 实现接口方法，获取用户的所有身份类型。
 func (s *userIdentityService) GetIdentityTypesByUserID(ctx context.Context, userID string) ([]enums.IdentityType, error) {
 	const operation = "UserIdentityService.GetIdentityTypesByUserID"
 
+	// 0. 先确认用户存在，使“用户不存在”与“用户没有任何身份”可以区分
+	if err := s.ensureUserExists(ctx, operation, userID); err != nil {
+		return nil, err
+	}
+
 	// 1. 调用仓库层获取身份类型列表
 	//    - 只读操作。
 	//    - 假设 s.repo.GetIdentityTypesByUserID 签名未变。
@@ -340,3 +575,142 @@ func (s *userIdentityService) GetIdentityTypesByUserID(ctx context.Context, user
 	)
 	return identityTypes, nil
 }
+
+// ensureUserExists 确认指定用户存在：不存在时返回 ErrUserNotFound，查询失败时返回系统错误。
+func (s *userIdentityService) ensureUserExists(ctx context.Context, operation string, userID string) error {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("查询身份时用户不存在", zap.String("operation", operation), zap.String("userID", userID))
+			return ErrUserNotFound
+		}
+		s.logger.Error("查询身份前检查用户是否存在失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	return nil
+}
+
+// GetLoginMethodsByUserID 实现接口方法，获取用户已绑定的登录方式及其元数据。
+func (s *userIdentityService) GetLoginMethodsByUserID(ctx context.Context, userID string) ([]*vo.LoginMethodVO, error) {
+	const operation = "UserIdentityService.GetLoginMethodsByUserID"
+
+	identityEntities, err := s.repo.GetIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("调用仓库获取用户登录方式失败",
+			zap.String("operation", operation),
+			zap.String("userID", userID),
+			zap.Error(err),
+		)
+		return nil, commonerrors.ErrSystemError
+	}
+
+	loginMethods := make([]*vo.LoginMethodVO, 0, len(identityEntities))
+	for _, entity := range identityEntities {
+		loginMethods = append(loginMethods, entityToLoginMethodVO(entity))
+	}
+
+	s.logger.Info("成功获取用户登录方式列表",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.Int("count", len(loginMethods)),
+	)
+	return loginMethods, nil
+}
+
+// VerifyAndBindPhone 实现接口方法，验证验证码并绑定已验证的手机号身份。
+func (s *userIdentityService) VerifyAndBindPhone(ctx context.Context, userID string, phone string, code string) (*vo.IdentityVO, error) {
+	const operation = "UserIdentityService.VerifyAndBindPhone"
+
+	// 1. 校验验证码（复用登录时的短信验证码）
+	storedCode, err := s.codeRepo.GetCaptcha(ctx, phone)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("验证手机号时验证码不存在或已过期", zap.String("operation", operation), zap.String("userID", userID))
+			return nil, errors.New("验证码错误或已过期")
+		}
+		s.logger.Error("验证手机号时获取验证码失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if storedCode != code {
+		s.logger.Warn("验证手机号时验证码不匹配", zap.String("operation", operation), zap.String("userID", userID))
+		return nil, errors.New("验证码错误或已过期")
+	}
+	if err := s.codeRepo.DeleteCaptcha(ctx, phone); err != nil {
+		s.logger.Error("删除已使用的验证码失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+	}
+
+	// 2. 该手机号不能已被其他账号绑定
+	owner, err := s.repo.GetIdentityByTypeAndIdentifier(ctx, enums.Phone, phone)
+	if err != nil && !errors.Is(err, commonerrors.ErrRepoNotFound) {
+		s.logger.Error("查询手机号身份归属失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if err == nil && owner.UserID != userID {
+		s.logger.Warn("手机号已被其他账号绑定", zap.String("operation", operation), zap.String("userID", userID))
+		return nil, errors.New("该手机号已被其他账号绑定")
+	}
+
+	// 3. 查找当前用户已有的身份：同号则标记为已验证，异号则拒绝
+	identities, err := s.repo.GetIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("查询用户已有身份失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	for _, identity := range identities {
+		if identity.IdentityType != enums.Phone {
+			continue
+		}
+		if identity.Identifier != phone {
+			return nil, errors.New("当前账号已绑定其他手机号，请先解绑")
+		}
+		if !identity.Verified {
+			identity.Verified = true
+			if err := s.repo.UpdateIdentity(ctx, s.db, identity); err != nil {
+				s.logger.Error("标记手机号身份为已验证失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+				return nil, commonerrors.ErrSystemError
+			}
+		}
+		s.logger.Info("手机号身份已验证", zap.String("operation", operation), zap.String("userID", userID), zap.Uint("identityID", identity.IdentityID))
+		return entityToVO(identity), nil
+	}
+
+	// 4. 创建已验证的手机号身份
+	identityEntity := &entities.UserIdentity{
+		UserID:       userID,
+		IdentityType: enums.Phone,
+		Identifier:   phone,
+		Verified:     true, // 已通过短信验证码证明手机号归属
+		IsPrimary:    len(identities) == 0,
+	}
+	if err := s.repo.CreateIdentity(ctx, s.db, identityEntity); err != nil {
+		s.logger.Error("创建手机号身份失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("成功验证并绑定手机号", zap.String("operation", operation), zap.String("userID", userID), zap.Uint("identityID", identityEntity.IdentityID))
+	return entityToVO(identityEntity), nil
+}
+
+// RevealCredential 实现接口方法，按身份类型的策略还原凭证原文。
+func (s *userIdentityService) RevealCredential(ctx context.Context, identityID uint) (string, error) {
+	const operation = "UserIdentityService.RevealCredential"
+
+	identityEntity, err := s.repo.GetIdentityByID(ctx, identityID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			return "", ErrIdentityNotFound
+		}
+		s.logger.Error("读取凭证前查询身份记录失败", zap.String("operation", operation), zap.Uint("identityID", identityID), zap.Error(err))
+		return "", commonerrors.ErrSystemError
+	}
+
+	credential, err := s.credentials.For(identityEntity.IdentityType).Reveal(identityEntity.Credential)
+	if err != nil {
+		if errors.Is(err, ErrCredentialNotRetrievable) {
+			return "", err
+		}
+		// 不记录凭证内容，只记录身份与错误
+		s.logger.Error("还原身份凭证失败", zap.String("operation", operation), zap.Uint("identityID", identityID), zap.Any("identityType", identityEntity.IdentityType), zap.Error(err))
+		return "", commonerrors.ErrSystemError
+	}
+	return credential, nil
+}
@@ -0,0 +1,321 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Xushengqwer/go-common/commonerrors"
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	mysqldriver "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+)
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// newMockGormDB 基于 sqlmock 创建 GORM 连接，仅用于驱动事务的开启/提交/回滚；
+// 数据读写由内存假仓库完成。
+func newMockGormDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建 sqlmock 失败: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := gorm.Open(mysqldriver.New(mysqldriver.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建 GORM 连接失败: %v", err)
+	}
+	return db, mock
+}
+
+// fakeUserRepo 内嵌接口，仅实现按 ID 查询用户；不在 users 中的用户返回 ErrRepoNotFound
+type fakeUserRepo struct {
+	mysql.UserRepository
+	users map[string]bool
+	err   error // err: 不为 nil 时查询直接返回该错误
+}
+
+func (r *fakeUserRepo) GetUserByID(_ context.Context, userID string) (*entities.User, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if !r.users[userID] {
+		return nil, commonerrors.ErrRepoNotFound
+	}
+	return &entities.User{UserID: userID}, nil
+}
+
+// fakeIdentityRepo 内嵌接口，以内存列表实现身份的查询、加锁查询、更新与删除
+type fakeIdentityRepo struct {
+	mysql.IdentityRepository
+	identities []*entities.UserIdentity
+	updated    []uint // updated: 被更新的身份 ID
+	deleted    []uint // deleted: 被删除的身份 ID
+}
+
+func (r *fakeIdentityRepo) GetIdentityByID(_ context.Context, identityID uint) (*entities.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.IdentityID == identityID {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, commonerrors.ErrRepoNotFound
+}
+
+func (r *fakeIdentityRepo) LockIdentitiesByUserID(ctx context.Context, _ *gorm.DB, userID string) ([]*entities.UserIdentity, error) {
+	return r.GetIdentitiesByUserID(ctx, userID)
+}
+
+func (r *fakeIdentityRepo) UpdateIdentity(_ context.Context, _ *gorm.DB, identity *entities.UserIdentity) error {
+	r.updated = append(r.updated, identity.IdentityID)
+	return nil
+}
+
+func (r *fakeIdentityRepo) DeleteIdentity(_ context.Context, _ *gorm.DB, identityID uint) error {
+	r.deleted = append(r.deleted, identityID)
+	r.identities = slices.DeleteFunc(r.identities, func(identity *entities.UserIdentity) bool {
+		return identity.IdentityID == identityID
+	})
+	return nil
+}
+
+func (r *fakeIdentityRepo) GetIdentitiesByUserID(_ context.Context, userID string) ([]*entities.UserIdentity, error) {
+	var result []*entities.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			result = append(result, identity)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeIdentityRepo) GetIdentityTypesByUserID(_ context.Context, userID string) ([]enums.IdentityType, error) {
+	var result []enums.IdentityType
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			result = append(result, identity.IdentityType)
+		}
+	}
+	return result, nil
+}
+
+// newTestIdentityService 组装只依赖内存假实现的身份服务
+func newTestIdentityService(t *testing.T, userRepo *fakeUserRepo, identities []*entities.UserIdentity, listConfig config.IdentityListConfig) UserIdentityService {
+	t.Helper()
+	return NewUserIdentityService(&fakeIdentityRepo{identities: identities}, userRepo, nil, newTestLogger(t), nil, nil, CredentialStrategies{}, listConfig)
+}
+
+// newTestOwnershipService 组装用于归属校验测试的身份服务：owner 有两个手机号身份，sole 只有一个
+func newTestOwnershipService(t *testing.T) (UserIdentityService, *fakeIdentityRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock := newMockGormDB(t)
+	identityRepo := &fakeIdentityRepo{identities: []*entities.UserIdentity{
+		{IdentityID: 1, UserID: "owner", IdentityType: enums.Phone, Identifier: "13800000001"},
+		{IdentityID: 2, UserID: "owner", IdentityType: enums.Phone, Identifier: "13800000002"},
+		{IdentityID: 3, UserID: "sole", IdentityType: enums.Phone, Identifier: "13800000003"},
+	}}
+	svc := NewUserIdentityService(identityRepo, &fakeUserRepo{}, db, newTestLogger(t), nil, nil,
+		DefaultCredentialStrategies(nil, nil), config.IdentityListConfig{})
+	return svc, identityRepo, mock
+}
+
+func TestIdentityQueriesDistinguishMissingUser(t *testing.T) {
+	identities := []*entities.UserIdentity{{IdentityID: 1, UserID: "with-identity", IdentityType: enums.Phone, Identifier: "13800001234"}}
+
+	tests := []struct {
+		name      string
+		userID    string
+		repoErr   error
+		wantErr   error
+		wantCount int
+	}{
+		{name: "用户存在且有身份", userID: "with-identity", wantCount: 1},
+		{name: "用户存在但没有身份返回空列表", userID: "no-identity", wantCount: 0},
+		{name: "用户不存在", userID: "missing", wantErr: ErrUserNotFound},
+		{name: "查询用户失败返回系统错误", userID: "with-identity", repoErr: errors.New("db down"), wantErr: commonerrors.ErrSystemError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &fakeUserRepo{users: map[string]bool{"with-identity": true, "no-identity": true}, err: tt.repoErr}
+			svc := newTestIdentityService(t, userRepo, identities, config.IdentityListConfig{})
+
+			list, err := svc.GetIdentitiesByUserID(context.Background(), tt.userID, IdentityListOptions{Privileged: true})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetIdentitiesByUserID 错误 = %v，期望 %v", err, tt.wantErr)
+			}
+			types, typesErr := svc.GetIdentityTypesByUserID(context.Background(), tt.userID)
+			if !errors.Is(typesErr, tt.wantErr) {
+				t.Fatalf("GetIdentityTypesByUserID 错误 = %v，期望 %v", typesErr, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if list.Items == nil || len(list.Items) != tt.wantCount || list.Total != int64(tt.wantCount) {
+				t.Errorf("身份列表 = %+v，期望 %d 条且不为 nil", list, tt.wantCount)
+			}
+			if len(types) != tt.wantCount {
+				t.Errorf("身份类型 = %v，期望 %d 个", types, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestGetIdentitiesByUserIDMaskedVsFull(t *testing.T) {
+	var identities []*entities.UserIdentity
+	for i := 1; i <= 5; i++ {
+		identities = append(identities, &entities.UserIdentity{
+			IdentityID:   uint(i),
+			UserID:       "u1",
+			IdentityType: enums.Phone,
+			Identifier:   "1380000123" + string(rune('0'+i)),
+		})
+	}
+	const full, masked = "13800001231", "138****1231"
+
+	tests := []struct {
+		name         string
+		opts         IdentityListOptions
+		wantIDs      []uint
+		wantFirst    string
+		wantPage     int
+		wantPageSize int
+	}{
+		{name: "非管理员按配置分页并脱敏", opts: IdentityListOptions{}, wantIDs: []uint{1, 2}, wantFirst: masked, wantPage: 1, wantPageSize: 2},
+		{name: "非管理员请求第二页", opts: IdentityListOptions{Page: 2}, wantIDs: []uint{3, 4}, wantPage: 2, wantPageSize: 2},
+		{name: "非管理员每页条数不超过上限", opts: IdentityListOptions{PageSize: 100}, wantIDs: []uint{1, 2}, wantFirst: masked, wantPage: 1, wantPageSize: 2},
+		{name: "非管理员要求完整标识符无效", opts: IdentityListOptions{RevealFull: true}, wantIDs: []uint{1, 2}, wantFirst: masked, wantPage: 1, wantPageSize: 2},
+		{name: "非管理员页码超出范围返回空页", opts: IdentityListOptions{Page: 9}, wantIDs: []uint{}, wantPage: 9, wantPageSize: 2},
+		{name: "管理员默认不分页但脱敏", opts: IdentityListOptions{Privileged: true, Page: 2, PageSize: 1}, wantIDs: []uint{1, 2, 3, 4, 5}, wantFirst: masked},
+		{name: "管理员显式要求完整标识符", opts: IdentityListOptions{Privileged: true, RevealFull: true}, wantIDs: []uint{1, 2, 3, 4, 5}, wantFirst: full},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &fakeUserRepo{users: map[string]bool{"u1": true}}
+			svc := newTestIdentityService(t, userRepo, identities, config.IdentityListConfig{PageSize: 2})
+
+			list, err := svc.GetIdentitiesByUserID(context.Background(), "u1", tt.opts)
+			if err != nil {
+				t.Fatalf("GetIdentitiesByUserID 失败: %v", err)
+			}
+			gotIDs := make([]uint, 0, len(list.Items))
+			for _, item := range list.Items {
+				gotIDs = append(gotIDs, item.IdentityID)
+			}
+			if !slices.Equal(gotIDs, tt.wantIDs) {
+				t.Errorf("身份 ID = %v，期望 %v", gotIDs, tt.wantIDs)
+			}
+			if list.Total != 5 || list.Page != tt.wantPage || list.PageSize != tt.wantPageSize {
+				t.Errorf("total/page/page_size = %d/%d/%d，期望 5/%d/%d", list.Total, list.Page, list.PageSize, tt.wantPage, tt.wantPageSize)
+			}
+			if tt.wantFirst != "" && list.Items[0].Identifier != tt.wantFirst {
+				t.Errorf("标识符 = %q，期望 %q", list.Items[0].Identifier, tt.wantFirst)
+			}
+		})
+	}
+
+	// 脱敏只作用于返回的视图对象，不修改仓库中的实体
+	if identities[0].Identifier != full {
+		t.Errorf("实体标识符被修改为 %q", identities[0].Identifier)
+	}
+}
+
+func TestUpdateIdentityOwnership(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  Caller
+		wantErr error
+	}{
+		{name: "本人更新自己的身份", caller: Caller{UserID: "owner"}},
+		{name: "非本人更新他人身份", caller: Caller{UserID: "other"}, wantErr: ErrIdentityForbidden},
+		{name: "未认证调用者", caller: Caller{}, wantErr: ErrIdentityForbidden},
+		{name: "管理员更新他人身份", caller: Caller{UserID: "admin", IsAdmin: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, identityRepo, mock := newTestOwnershipService(t)
+			if tt.wantErr == nil {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			}
+
+			identityVO, err := svc.UpdateIdentity(context.Background(), tt.caller, 1, &dto.UpdateIdentityDTO{Credential: "new-value"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateIdentity 错误 = %v，期望 %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("事务调用不符合预期: %v", err)
+			}
+			if tt.wantErr != nil {
+				if len(identityRepo.updated) != 0 {
+					t.Errorf("无权操作时不应写库，实际更新了 %v", identityRepo.updated)
+				}
+				return
+			}
+			if identityVO == nil || identityVO.IdentityID != 1 || !slices.Equal(identityRepo.updated, []uint{1}) {
+				t.Errorf("更新结果 = %+v，已更新 %v，期望更新身份 1", identityVO, identityRepo.updated)
+			}
+		})
+	}
+}
+
+func TestDeleteIdentityOwnership(t *testing.T) {
+	tests := []struct {
+		name        string
+		caller      Caller
+		identityID  uint
+		wantErr     error
+		wantTx      bool
+		wantDeleted []uint
+	}{
+		{name: "本人删除自己的身份", caller: Caller{UserID: "owner"}, identityID: 1, wantTx: true, wantDeleted: []uint{1}},
+		{name: "本人不能删除唯一的登录方式", caller: Caller{UserID: "sole"}, identityID: 3, wantErr: ErrLastIdentity, wantTx: true},
+		{name: "非本人删除他人身份", caller: Caller{UserID: "other"}, identityID: 1, wantErr: ErrIdentityForbidden},
+		{name: "管理员删除他人身份", caller: Caller{UserID: "admin", IsAdmin: true}, identityID: 1, wantDeleted: []uint{1}},
+		{name: "管理员可删除唯一的登录方式", caller: Caller{UserID: "admin", IsAdmin: true}, identityID: 3, wantDeleted: []uint{3}},
+		{name: "身份不存在视为成功", caller: Caller{UserID: "other"}, identityID: 99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, identityRepo, mock := newTestOwnershipService(t)
+			if tt.wantTx {
+				mock.ExpectBegin()
+				if tt.wantErr == nil {
+					mock.ExpectCommit()
+				} else {
+					mock.ExpectRollback()
+				}
+			}
+
+			err := svc.DeleteIdentity(context.Background(), tt.caller, tt.identityID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteIdentity 错误 = %v，期望 %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("事务调用不符合预期: %v", err)
+			}
+			if !slices.Equal(identityRepo.deleted, tt.wantDeleted) {
+				t.Errorf("已删除 %v，期望 %v", identityRepo.deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt" // 引入 fmt 包用于错误包装
+	"time"
 
 	// 引入公共模块
 	"github.com/Xushengqwer/go-common/commonerrors"
@@ -12,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
 
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/entities"
@@ -19,6 +22,11 @@ import (
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/repository/mysql"
 	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/deactivation"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
+	"github.com/Xushengqwer/user_hub/service/provisioning"
+	"github.com/Xushengqwer/user_hub/service/registration"
+	"github.com/Xushengqwer/user_hub/service/token"
 	"github.com/Xushengqwer/user_hub/utils" // 引入密码工具
 
 	"gorm.io/gorm"
@@ -39,34 +47,92 @@ type AccountService interface {
 	// - platform: 发起请求的客户端平台类型。
 	// - 返回: 包含用户 ID 的 Userinfo、包含访问和刷新令牌的 TokenPair，以及可能发生的业务错误或系统错误。
 	Login(ctx context.Context, data dto.AccountLoginData, platform enums.Platform) (vo.Userinfo, vo.TokenPair, error)
+
+	// VerifyPassword 校验已登录用户提交的当前密码 (用于进入敏感设置前的二次确认)，通过后记录近期重新验证标记。
+	// - ctx: 请求上下文。
+	// - userID: 当前登录用户的 ID。
+	// - password: 用户提交的当前密码。
+	// - 返回: 本次校验后的限流配额；密码错误或用户没有账号密码身份时返回 ErrPasswordVerifyFailed，
+	//   超出频率限制时返回 redis.ErrPasswordVerifyLimit，其他失败返回 ErrSystemError。
+	VerifyPassword(ctx context.Context, userID string, password string) (redis.AttemptQuota, error)
+
+	// ChangeAccount 修改已登录用户自己的账号（账号密码身份的 Identifier），用户 ID 保持不变。
+	// - ctx: 请求上下文。
+	// - userID: 当前登录用户的 ID。
+	// - data: 包含新账号和当前密码的 DTO。
+	// - 返回: 本次密码校验后的限流配额（未进行密码校验时为零值）；用户没有账号密码身份时返回 ErrNoAccountIdentity，新账号与当前账号相同时返回 ErrAccountUnchanged，
+	//   新账号已被占用时返回 ErrAccountTaken；当前密码的校验错误与 VerifyPassword 相同，其他失败返回 ErrSystemError。
+	ChangeAccount(ctx context.Context, userID string, data dto.ChangeAccountDTO) (redis.AttemptQuota, error)
 }
 
+// ErrPasswordVerifyFailed 校验当前密码未通过；不区分密码错误与未设置账号密码，避免泄露账号信息
+var ErrPasswordVerifyFailed = errors.New("密码校验未通过")
+
+var (
+	// ErrNoAccountIdentity 用户没有账号密码身份，无法修改账号
+	ErrNoAccountIdentity = errors.New("当前用户未设置账号密码，无法修改账号")
+	// ErrAccountUnchanged 新账号与当前账号相同
+	ErrAccountUnchanged = errors.New("新账号与当前账号相同")
+	// ErrAccountTaken 新账号已被其他用户占用
+	ErrAccountTaken = errors.New("该账号已被占用")
+)
+
 // accountService 是 AccountService 接口的实现。
 type accountService struct {
-	identityRepo   mysql.IdentityRepository // 身份仓库
-	userRepo       mysql.UserRepository     // 用户仓库
-	tokenBlackRepo redis.TokenBlackRepo     // 令牌黑名单仓库 (Login 中未使用，但保持注入)
-	profileRepo    mysql.ProfileRepository
-	jwtUtil        dependencies.JWTTokenInterface // JWT 工具
-	db             *gorm.DB                       // 数据库连接
-	logger         *core.ZapLogger                // 日志记录器
+	identityRepo   mysql.IdentityRepository                // 身份仓库
+	userRepo       mysql.UserRepository                    // 用户仓库
+	tokenBlackRepo redis.TokenBlackRepo                    // 令牌黑名单仓库 (Login 中未使用，但保持注入)
+	verifyRepo     redis.PasswordVerifyRepo                // 校验当前密码的限流仓库
+	recentAuth     redis.RecentAuthRepo                    // 校验当前密码通过后写入近期重新验证标记
+	provisioning   provisioning.UserProvisioningService    // 新用户开户服务
+	registration   registration.RegistrationGateService    // 注册开关 (关闭时拒绝新用户注册)
+	jwtUtil        dependencies.JWTTokenInterface          // JWT 工具
+	deactivation   deactivation.AccountDeactivationService // 账号停用/重新激活服务
+	tokenService   token.AuthTokenService                  // 令牌服务，用于签发刷新令牌
+	loginPolicy    config.LoginPolicyConfig                // 登录策略 (是否要求身份已验证)
+	passwordPolicy config.PasswordPolicyConfig             // 密码策略 (校验当前密码的限流参数)
+	reverifyWindow time.Duration                           // 近期重新验证标记的有效期
+	degrade        *dependencies.RedisDegradation          // Redis 降级决策与统计 (密码校验限流为安全关键功能)
+	auditRepo      mysql.AdminAuditRepository              // 审计日志仓库 (记录账号修改)
+	db             *gorm.DB                                // 数据库连接
+	logger         *core.ZapLogger                         // 日志记录器
 }
 
 func NewAccountService(
 	identityRepo mysql.IdentityRepository,
 	userRepo mysql.UserRepository,
-	profileRepo mysql.ProfileRepository,
+	provisioningService provisioning.UserProvisioningService,
+	registrationGate registration.RegistrationGateService,
 	tokenBlackRepo redis.TokenBlackRepo,
+	verifyRepo redis.PasswordVerifyRepo,
+	recentAuth redis.RecentAuthRepo,
 	jwtUtil dependencies.JWTTokenInterface,
+	deactivationService deactivation.AccountDeactivationService,
+	tokenService token.AuthTokenService,
+	loginPolicy config.LoginPolicyConfig,
+	passwordPolicy config.PasswordPolicyConfig,
+	reverifyWindow time.Duration,
+	degrade *dependencies.RedisDegradation,
+	auditRepo mysql.AdminAuditRepository,
 	db *gorm.DB,
 	logger *core.ZapLogger, // 注入 logger
 ) AccountService { // 返回接口类型
 	return &accountService{ // 返回结构体指针
 		identityRepo:   identityRepo,
 		userRepo:       userRepo,
-		profileRepo:    profileRepo,
+		provisioning:   provisioningService,
+		registration:   registrationGate,
 		tokenBlackRepo: tokenBlackRepo,
+		verifyRepo:     verifyRepo,
+		recentAuth:     recentAuth,
 		jwtUtil:        jwtUtil,
+		deactivation:   deactivationService,
+		tokenService:   tokenService,
+		loginPolicy:    loginPolicy,
+		passwordPolicy: passwordPolicy,
+		reverifyWindow: reverifyWindow,
+		degrade:        degrade,
+		auditRepo:      auditRepo,
 		db:             db,
 		logger:         logger, // 存储 logger
 	}
@@ -77,6 +143,11 @@ func (s *accountService) Register(ctx context.Context, data dto.AccountRegisterD
 	const operation = "AccountService.Register" // 修改操作名称以反映服务层
 	emptyUserInfo := vo.Userinfo{}
 
+	// 0. 注册关闭时直接拒绝，不再进行后续校验
+	if err := s.registration.CheckRegistration(registration.ChannelAccount); err != nil {
+		return emptyUserInfo, err
+	}
+
 	// 1. 基本校验：密码与确认密码是否一致
 	if data.Password != data.ConfirmPassword {
 		s.logger.Warn("注册时密码与确认密码不一致", zap.String("operation", operation), zap.String("account", data.Account))
@@ -118,37 +189,24 @@ func (s *accountService) Register(ctx context.Context, data dto.AccountRegisterD
 		return emptyUserInfo, commonerrors.ErrSystemError
 	}
 
-	newUser := &entities.User{
-		UserID:   userID,
-		UserRole: enums.RoleUser,     // 默认为普通用户
-		Status:   enums.StatusActive, // 默认状态为活跃
-	}
 	newIdentity := &entities.UserIdentity{
 		UserID:       userID,
 		IdentityType: myenums.AccountPassword,
 		Identifier:   data.Account,
 		Credential:   hashedPassword,
+		Verified:     true, // 账号由用户自行设定，不存在需要额外验证的归属关系
+		IsPrimary:    true, // 注册时创建的身份即为主登录方式
 	}
-	// 准备初始用户资料实体，只包含 UserID
+	// 准备初始用户资料实体，只包含 UserID (昵称由开户服务按默认昵称策略生成)
 	initialProfile := &entities.UserProfile{
-		UserID:   userID,
-		Nickname: data.Account,
+		UserID: userID,
 		// 其他字段（如 AvatarURL, Gender, Province, City）将使用数据库默认值或保持为空
 	}
 
-	// 4. 使用事务创建用户、身份和初始资料
+	// 4. 使用事务创建用户、身份和初始资料（委托给开户服务，默认普通用户、活跃状态）
 	txErr := s.db.Transaction(func(tx *gorm.DB) error {
-		if err := s.userRepo.CreateUser(ctx, tx, newUser); err != nil {
-			return fmt.Errorf("事务中创建用户失败: %w", err)
-		}
-		if err := s.identityRepo.CreateIdentity(ctx, tx, newIdentity); err != nil {
-			return fmt.Errorf("事务中创建身份失败: %w", err)
-		}
-		// 在事务中创建初始用户资料
-		if err := s.profileRepo.CreateProfile(ctx, tx, initialProfile); err != nil {
-			return fmt.Errorf("事务中创建初始用户资料失败: %w", err)
-		}
-		return nil // 事务成功
+		_, err := s.provisioning.CreateUserWithIdentity(ctx, tx, enums.RoleUser, enums.StatusActive, newIdentity, initialProfile)
+		return err
 	})
 
 	if txErr != nil {
@@ -184,7 +242,7 @@ func (s *accountService) Login(ctx context.Context, data dto.AccountLoginData, p
 				zap.String("operation", operation),
 				zap.String("account", data.Account),
 			)
-			return emptyUserInfo, emptyTokenPair, errors.New("账号不存在或密码错误")
+			return emptyUserInfo, emptyTokenPair, loginerr.ErrInvalidCredentials
 		}
 		s.logger.Error("登录时查找账号身份失败",
 			zap.String("operation", operation),
@@ -202,7 +260,16 @@ func (s *accountService) Login(ctx context.Context, data dto.AccountLoginData, p
 			zap.String("userID", identityCredential.UserID),
 			zap.String("account", data.Account),
 		)
-		return emptyUserInfo, emptyTokenPair, errors.New("账号不存在或密码错误")
+		return emptyUserInfo, emptyTokenPair, loginerr.ErrInvalidCredentials
+	}
+
+	// 按登录策略检查身份是否已验证 (在密码校验之后，避免泄露验证状态)
+	if err := checkIdentityVerified(s.loginPolicy, myenums.AccountPassword, identityCredential); err != nil {
+		s.logger.Warn("登录策略要求身份已验证，拒绝未验证的账号登录",
+			zap.String("operation", operation),
+			zap.String("userID", identityCredential.UserID),
+		)
+		return emptyUserInfo, emptyTokenPair, err
 	}
 
 	// 3. 获取用户信息
@@ -221,15 +288,34 @@ func (s *accountService) Login(ctx context.Context, data dto.AccountLoginData, p
 	}
 
 	// 4. 检查用户状态
+	// 已停用的账号：本次登录已证明身份，签发重新激活凭证供客户端走重新激活流程
+	if user.Status == myenums.StatusDeactivated {
+		return emptyUserInfo, emptyTokenPair, s.deactivation.ChallengeForReactivation(ctx, user.UserID)
+	}
 	if user.Status != enums.StatusActive {
 		s.logger.Warn("尝试登录但用户状态异常",
 			zap.String("operation", operation),
 			zap.String("userID", user.UserID),
 			zap.Any("status", user.Status),
 		)
-		return emptyUserInfo, emptyTokenPair, fmt.Errorf("用户状态异常，无法登录")
+		if user.Status == enums.StatusBlacklisted {
+			return emptyUserInfo, emptyTokenPair, loginerr.ErrAccountBlacklisted
+		}
+		return emptyUserInfo, emptyTokenPair, loginerr.ErrAccountUnavailable
 	}
 
+	// 记录该登录方式的最近使用时间（尽力而为，失败不影响登录）
+	if err := s.identityRepo.UpdateLastUsedAt(ctx, myenums.AccountPassword, data.Account, time.Now()); err != nil {
+		s.logger.Warn("更新账号身份最近使用时间失败",
+			zap.String("operation", operation),
+			zap.String("userID", user.UserID),
+			zap.Error(err),
+		)
+	}
+
+	// 存储的密码哈希弱于当前哈希配置时透明升级（尽力而为，失败不影响登录）
+	s.rehashPasswordIfNeeded(ctx, user.UserID, data.Account, identityCredential.Credential, data.Password)
+
 	// 5. 生成令牌
 	accessToken, err := s.jwtUtil.GenerateAccessToken(user.UserID, user.UserRole, user.Status, platform)
 	if err != nil {
@@ -241,7 +327,7 @@ func (s *accountService) Login(ctx context.Context, data dto.AccountLoginData, p
 		// 生成令牌失败返回系统错误
 		return emptyUserInfo, emptyTokenPair, commonerrors.ErrSystemError
 	}
-	refreshToken, err := s.jwtUtil.GenerateRefreshToken(user.UserID, platform)
+	refreshToken, err := s.tokenService.IssueRefreshToken(ctx, user.UserID, platform)
 	if err != nil {
 		s.logger.Error("生成刷新令牌失败",
 			zap.String("operation", operation),
@@ -265,3 +351,159 @@ func (s *accountService) Login(ctx context.Context, data dto.AccountLoginData, p
 	}
 	return userInfo, tokenPair, nil
 }
+
+// VerifyPassword 实现接口方法，校验已登录用户的当前密码。
+func (s *accountService) VerifyPassword(ctx context.Context, userID string, password string) (redis.AttemptQuota, error) {
+	const operation = "AccountService.VerifyPassword"
+
+	// 1. 先按用户预占校验额度：每次尝试都计数，防止接口被用来逐个猜测密码
+	quota, err := s.verifyRepo.ReserveAttempt(ctx, userID, s.passwordPolicy.VerifyMaxAttemptsOrDefault(), s.passwordPolicy.VerifyWindowOrDefault())
+	if err != nil {
+		if errors.Is(err, redis.ErrPasswordVerifyLimit) {
+			s.logger.Warn("校验当前密码过于频繁", zap.String("operation", operation), zap.String("userID", userID), zap.Duration("reset", quota.Reset))
+			return quota, err
+		}
+		s.degrade.AllowOnFailure(dependencies.RedisFeaturePasswordVerify, err) // 安全关键功能，只记录失败，不放行
+		s.logger.Error("检查密码校验额度失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return quota, commonerrors.ErrSystemError
+	}
+
+	// 2. 查找用户的账号密码身份
+	identities, err := s.identityRepo.GetIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("校验当前密码时查询用户身份失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return quota, commonerrors.ErrSystemError
+	}
+	var passwordHash string
+	for _, identity := range identities {
+		if identity.IdentityType == myenums.AccountPassword {
+			passwordHash = identity.Credential
+			break
+		}
+	}
+	if passwordHash == "" {
+		s.logger.Warn("用户没有账号密码身份，无法校验当前密码", zap.String("operation", operation), zap.String("userID", userID))
+		return quota, ErrPasswordVerifyFailed
+	}
+
+	// 3. 校验密码
+	if err := utils.CheckPassword(passwordHash, password); err != nil {
+		s.logger.Warn("校验当前密码未通过", zap.String("operation", operation), zap.String("userID", userID))
+		return quota, ErrPasswordVerifyFailed
+	}
+
+	// 4. 记录近期重新验证标记，供修改敏感资料等操作检查 (尽力而为，失败只影响后续敏感操作)
+	if err := s.recentAuth.Mark(ctx, userID, s.reverifyWindow); err != nil {
+		s.logger.Warn("写入重新验证标记失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+	}
+	return quota, nil
+}
+
+// ChangeAccount 实现接口方法，修改当前用户的账号。
+func (s *accountService) ChangeAccount(ctx context.Context, userID string, data dto.ChangeAccountDTO) (redis.AttemptQuota, error) {
+	const operation = "AccountService.ChangeAccount"
+	var quota redis.AttemptQuota
+
+	// 1. 没有账号密码身份的用户（如仅手机号登录）不能修改账号，明确拒绝而不是按密码错误处理
+	identities, err := s.identityRepo.GetIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("修改账号时查询用户身份失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return quota, commonerrors.ErrSystemError
+	}
+	current := findIdentityByType(identities, myenums.AccountPassword)
+	if current == nil {
+		s.logger.Warn("用户没有账号密码身份，拒绝修改账号", zap.String("operation", operation), zap.String("userID", userID))
+		return quota, ErrNoAccountIdentity
+	}
+	if current.Identifier == data.NewAccount {
+		return quota, ErrAccountUnchanged
+	}
+
+	// 2. 校验当前密码（与 VerifyPassword 共用限流额度）
+	quota, err = s.VerifyPassword(ctx, userID, data.Password)
+	if err != nil {
+		return quota, err
+	}
+
+	// 3. 检查新账号是否已被占用
+	if _, err := s.identityRepo.GetIdentityByTypeAndIdentifier(ctx, myenums.AccountPassword, data.NewAccount); err == nil {
+		s.logger.Warn("修改账号时新账号已被占用", zap.String("operation", operation), zap.String("userID", userID), zap.String("newAccount", data.NewAccount))
+		return quota, ErrAccountTaken
+	} else if !errors.Is(err, commonerrors.ErrRepoNotFound) {
+		s.logger.Error("检查新账号是否存在时查询失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return quota, commonerrors.ErrSystemError
+	}
+
+	// 4. 在事务中锁定用户身份后更新账号并写入审计日志
+	var oldAccount string
+	txErr := s.db.Transaction(func(tx *gorm.DB) error {
+		locked, err := s.identityRepo.LockIdentitiesByUserID(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		identity := findIdentityByType(locked, myenums.AccountPassword)
+		if identity == nil {
+			return ErrNoAccountIdentity
+		}
+		oldAccount = identity.Identifier
+		identity.Identifier = data.NewAccount
+		if err := s.identityRepo.UpdateIdentity(ctx, tx, identity); err != nil {
+			return err
+		}
+		diff, err := json.Marshal(map[string]string{"old_account": oldAccount, "new_account": data.NewAccount})
+		if err != nil {
+			return fmt.Errorf("序列化审计变更内容失败: %w", err)
+		}
+		return s.auditRepo.CreateAuditLog(ctx, tx, &entities.AdminAuditLog{
+			ActorID:  userID,
+			Action:   myenums.AuditActionChangeAccount,
+			TargetID: userID,
+			Diff:     string(diff),
+		})
+	})
+	if txErr != nil {
+		if errors.Is(txErr, ErrNoAccountIdentity) {
+			return quota, ErrNoAccountIdentity
+		}
+		s.logger.Error("修改账号事务失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(txErr))
+		return quota, commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("用户已修改账号",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.String("oldAccount", oldAccount),
+		zap.String("newAccount", data.NewAccount),
+	)
+	return quota, nil
+}
+
+// findIdentityByType 返回身份列表中第一个指定类型的身份，不存在时返回 nil。
+func findIdentityByType(identities []*entities.UserIdentity, identityType myenums.IdentityType) *entities.UserIdentity {
+	for _, identity := range identities {
+		if identity.IdentityType == identityType {
+			return identity
+		}
+	}
+	return nil
+}
+
+// rehashPasswordIfNeeded 在密码校验通过后，若存储的哈希算法或强度弱于当前配置，则用本次提交的明文重新哈希并更新凭证。
+// - 只记录日志不返回错误：升级失败时旧哈希仍然有效，下次登录会再次尝试。
+func (s *accountService) rehashPasswordIfNeeded(ctx context.Context, userID, account, storedHash, password string) {
+	const operation = "AccountLogin.rehashPassword"
+
+	if !utils.PasswordNeedsRehash(storedHash) {
+		return
+	}
+	newHash, err := utils.SetPassword(password)
+	if err != nil {
+		s.logger.Warn("登录时重新哈希密码失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return
+	}
+	if err := s.identityRepo.ReplaceCredential(ctx, myenums.AccountPassword, account, storedHash, newHash); err != nil {
+		s.logger.Warn("登录时升级密码哈希失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return
+	}
+	s.logger.Info("已按当前哈希配置升级密码哈希", zap.String("operation", operation), zap.String("userID", userID))
+}
@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	myenums "github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/service/token"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// fakeIdentityRepo 内嵌接口，仅实现账号登录用到的方法；调用未实现的方法会 panic
+type fakeIdentityRepo struct {
+	mysql.IdentityRepository
+	credential        *dto.IdentityCredential
+	replacedWith      string
+	replaceCalls      int
+	replaceErr        error // replaceErr: 不为 nil 时 ReplaceCredential 直接返回该错误
+	lastUsedAtUpdates int
+}
+
+func (r *fakeIdentityRepo) GetIdentityByTypeAndIdentifier(_ context.Context, _ myenums.IdentityType, _ string) (*dto.IdentityCredential, error) {
+	if r.credential == nil {
+		return nil, errors.New("not found")
+	}
+	c := *r.credential
+	return &c, nil
+}
+
+func (r *fakeIdentityRepo) UpdateLastUsedAt(_ context.Context, _ myenums.IdentityType, _ string, _ time.Time) error {
+	r.lastUsedAtUpdates++
+	return nil
+}
+
+func (r *fakeIdentityRepo) ReplaceCredential(_ context.Context, _ myenums.IdentityType, _ string, oldCredential, newCredential string) error {
+	r.replaceCalls++
+	if r.replaceErr != nil {
+		return r.replaceErr
+	}
+	if r.credential == nil || r.credential.Credential != oldCredential {
+		return errors.New("credential changed")
+	}
+	r.credential.Credential = newCredential
+	r.replacedWith = newCredential
+	return nil
+}
+
+// fakeUserRepo 内嵌接口，仅实现按 ID 查询用户
+type fakeUserRepo struct {
+	mysql.UserRepository
+	user *entities.User
+}
+
+func (r *fakeUserRepo) GetUserByID(_ context.Context, _ string) (*entities.User, error) {
+	return r.user, nil
+}
+
+// fakeJWT 内嵌接口，仅实现签发访问令牌
+type fakeJWT struct {
+	dependencies.JWTTokenInterface
+}
+
+func (fakeJWT) GenerateAccessToken(userID string, _ enums.UserRole, _ enums.UserStatus, _ enums.Platform) (string, error) {
+	return "access-" + userID, nil
+}
+
+// fakeTokenService 内嵌接口，仅实现签发刷新令牌
+type fakeTokenService struct {
+	token.AuthTokenService
+}
+
+func (fakeTokenService) IssueRefreshToken(_ context.Context, userID string, _ enums.Platform) (string, error) {
+	return "refresh-" + userID, nil
+}
+
+// newTestAccountService 组装只依赖内存假实现的账号服务，storedHash 为账号当前存储的密码哈希
+func newTestAccountService(t *testing.T, storedHash string, verified bool, policy config.LoginPolicyConfig) (*accountService, *fakeIdentityRepo) {
+	t.Helper()
+	identityRepo := &fakeIdentityRepo{credential: &dto.IdentityCredential{UserID: "u1", Credential: storedHash, Verified: verified}}
+	return &accountService{
+		identityRepo: identityRepo,
+		userRepo:     &fakeUserRepo{user: &entities.User{UserID: "u1", UserRole: enums.RoleUser, Status: enums.StatusActive}},
+		jwtUtil:      fakeJWT{},
+		tokenService: fakeTokenService{},
+		loginPolicy:  policy,
+		logger:       newTestLogger(t),
+	}, identityRepo
+}
+
+func TestAccountLoginRequireVerifiedIdentity(t *testing.T) {
+	hash, err := utils.SetPassword("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		verified      bool
+		requireVerify bool
+		wantRejected  bool
+	}{
+		{name: "策略关闭_已验证", verified: true, requireVerify: false, wantRejected: false},
+		{name: "策略关闭_未验证", verified: false, requireVerify: false, wantRejected: false},
+		{name: "策略开启_已验证", verified: true, requireVerify: true, wantRejected: false},
+		{name: "策略开启_未验证", verified: false, requireVerify: true, wantRejected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestAccountService(t, hash, tt.verified, config.LoginPolicyConfig{RequireVerifiedIdentity: tt.requireVerify})
+
+			userInfo, tokens, err := svc.Login(context.Background(), dto.AccountLoginData{Account: "alice", Password: "secret-password"}, enums.PlatformApp)
+
+			var notVerified *IdentityNotVerifiedError
+			if tt.wantRejected {
+				if !errors.As(err, &notVerified) {
+					t.Fatalf("期望返回 IdentityNotVerifiedError，实际为 %v", err)
+				}
+				if notVerified.IdentityType != myenums.AccountPassword {
+					t.Errorf("IdentityType = %v，期望 AccountPassword", notVerified.IdentityType)
+				}
+				if tokens.AccessToken != "" || tokens.RefreshToken != "" {
+					t.Errorf("被拒绝的登录不应签发令牌: %+v", tokens)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("期望登录成功，实际错误: %v", err)
+			}
+			if userInfo.UserID != "u1" || tokens.AccessToken == "" || tokens.RefreshToken == "" {
+				t.Errorf("登录结果不完整: userInfo=%+v tokens=%+v", userInfo, tokens)
+			}
+		})
+	}
+}
+
+func TestAccountLoginWrongPasswordHidesVerificationState(t *testing.T) {
+	hash, err := utils.SetPassword("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	svc, _ := newTestAccountService(t, hash, false, config.LoginPolicyConfig{RequireVerifiedIdentity: true})
+
+	_, _, err = svc.Login(context.Background(), dto.AccountLoginData{Account: "alice", Password: "wrong"}, enums.PlatformApp)
+	var notVerified *IdentityNotVerifiedError
+	if errors.As(err, &notVerified) {
+		t.Fatal("密码错误时不应泄露身份的验证状态")
+	}
+}
+
+func TestAccountLoginRehashesWeakPassword(t *testing.T) {
+	const password = "secret-password"
+	lowCost, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("生成低强度哈希失败: %v", err)
+	}
+	defaultCost, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("生成默认强度哈希失败: %v", err)
+	}
+	argon2Policy := config.PasswordPolicyConfig{HashAlgorithm: config.PasswordHashArgon2id, Argon2MemoryKiB: 64, Argon2Iterations: 1, Argon2Parallelism: 1}
+
+	tests := []struct {
+		name        string
+		hashing     config.PasswordPolicyConfig
+		storedHash  string
+		password    string
+		wantRehash  bool
+		wantPrefix  string // 升级后哈希的前缀
+		wantLoginOK bool
+	}{
+		{name: "低强度 bcrypt 升级到配置强度", storedHash: string(lowCost), password: password, wantRehash: true, wantPrefix: "$2a$10$", wantLoginOK: true},
+		{name: "强度已满足时不重写", storedHash: string(defaultCost), password: password, wantLoginOK: true},
+		{name: "切换到 argon2id 后升级算法", hashing: argon2Policy, storedHash: string(defaultCost), password: password, wantRehash: true, wantPrefix: "$argon2id$", wantLoginOK: true},
+		{name: "密码错误时不升级", storedHash: string(lowCost), password: "wrong-password"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := utils.ConfigurePasswordHashing(tt.hashing); err != nil {
+				t.Fatalf("配置密码哈希失败: %v", err)
+			}
+			t.Cleanup(func() { _ = utils.ConfigurePasswordHashing(config.PasswordPolicyConfig{}) })
+			svc, identityRepo := newTestAccountService(t, tt.storedHash, true, config.LoginPolicyConfig{})
+
+			_, _, err := svc.Login(context.Background(), dto.AccountLoginData{Account: "alice", Password: tt.password}, enums.PlatformApp)
+			if tt.wantLoginOK != (err == nil) {
+				t.Fatalf("登录结果错误: %v，期望成功 = %v", err, tt.wantLoginOK)
+			}
+
+			if !tt.wantRehash {
+				if identityRepo.replaceCalls != 0 || identityRepo.credential.Credential != tt.storedHash {
+					t.Errorf("不应重写凭证，ReplaceCredential 调用 %d 次", identityRepo.replaceCalls)
+				}
+				return
+			}
+			if identityRepo.replaceCalls != 1 {
+				t.Fatalf("ReplaceCredential 调用 %d 次，期望 1 次", identityRepo.replaceCalls)
+			}
+			if !strings.HasPrefix(identityRepo.replacedWith, tt.wantPrefix) {
+				t.Errorf("升级后的哈希 = %q，期望以 %q 开头", identityRepo.replacedWith, tt.wantPrefix)
+			}
+			if err := utils.CheckPassword(identityRepo.replacedWith, password); err != nil {
+				t.Errorf("升级后的哈希无法校验原密码: %v", err)
+			}
+			if utils.PasswordNeedsRehash(identityRepo.replacedWith) {
+				t.Error("升级后的哈希仍被判定为需要升级")
+			}
+		})
+	}
+}
+
+func TestAccountLoginRehashFailureDoesNotBlockLogin(t *testing.T) {
+	lowCost, err := bcrypt.GenerateFromPassword([]byte("secret-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("生成低强度哈希失败: %v", err)
+	}
+	svc, identityRepo := newTestAccountService(t, string(lowCost), true, config.LoginPolicyConfig{})
+	// 模拟并发修改密码：登录读取到的旧哈希与库中当前凭证不一致，条件更新失败
+	identityRepo.replaceErr = errors.New("credential changed")
+
+	_, tokens, err := svc.Login(context.Background(), dto.AccountLoginData{Account: "alice", Password: "secret-password"}, enums.PlatformApp)
+	if err != nil || tokens.AccessToken == "" {
+		t.Fatalf("升级哈希失败不应影响登录: err=%v tokens=%+v", err, tokens)
+	}
+	if identityRepo.replaceCalls != 1 || identityRepo.credential.Credential != string(lowCost) {
+		t.Errorf("升级失败时应保留旧哈希，ReplaceCredential 调用 %d 次", identityRepo.replaceCalls)
+	}
+}
@@ -3,7 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
-	"fmt" // 引入 fmt 包用于错误包装
+	"time"
 
 	// 引入公共模块
 	"github.com/Xushengqwer/go-common/commonerrors"
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
 
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/entities"
@@ -19,6 +20,11 @@ import (
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/repository/mysql"
 	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/deactivation"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
+	"github.com/Xushengqwer/user_hub/service/provisioning"
+	"github.com/Xushengqwer/user_hub/service/registration"
+	"github.com/Xushengqwer/user_hub/service/token"
 	// "github.com/Xushengqwer/user_hub/service/profile" // 不再需要 profileService
 
 	"gorm.io/gorm"
@@ -36,30 +42,42 @@ type PhoneAuthService interface {
 
 // phoneAuthService 是 PhoneAuthService 接口的实现。
 type phoneAuthService struct {
-	identityRepo mysql.IdentityRepository       // 身份仓库
-	userRepo     mysql.UserRepository           // 用户仓库
-	profileRepo  mysql.ProfileRepository        // 用户资料仓库
-	codeRepo     redis.CodeRepo                 // 验证码仓库
-	jwtUtil      dependencies.JWTTokenInterface // JWT 工具
-	db           *gorm.DB                       // 数据库连接
-	logger       *core.ZapLogger                // 日志记录器
+	identityRepo mysql.IdentityRepository                // 身份仓库
+	userRepo     mysql.UserRepository                    // 用户仓库
+	provisioning provisioning.UserProvisioningService    // 新用户开户服务
+	registration registration.RegistrationGateService    // 注册开关 (关闭时拒绝自动注册)
+	codeRepo     redis.CodeRepo                          // 验证码仓库
+	jwtUtil      dependencies.JWTTokenInterface          // JWT 工具
+	deactivation deactivation.AccountDeactivationService // 账号停用/重新激活服务
+	tokenService token.AuthTokenService                  // 令牌服务，用于签发刷新令牌
+	loginPolicy  config.LoginPolicyConfig                // 登录策略 (是否要求身份已验证)
+	db           *gorm.DB                                // 数据库连接
+	logger       *core.ZapLogger                         // 日志记录器
 }
 
 func NewPhoneAuthService(
 	identityRepo mysql.IdentityRepository,
 	userRepo mysql.UserRepository,
-	profileRepo mysql.ProfileRepository,
+	provisioningService provisioning.UserProvisioningService,
+	registrationGate registration.RegistrationGateService,
 	codeRepo redis.CodeRepo,
 	jwtUtil dependencies.JWTTokenInterface,
+	deactivationService deactivation.AccountDeactivationService,
+	tokenService token.AuthTokenService,
+	loginPolicy config.LoginPolicyConfig,
 	db *gorm.DB,
 	logger *core.ZapLogger,
 ) PhoneAuthService {
 	return &phoneAuthService{
 		identityRepo: identityRepo,
 		userRepo:     userRepo,
-		profileRepo:  profileRepo,
+		provisioning: provisioningService,
+		registration: registrationGate,
 		codeRepo:     codeRepo,
 		jwtUtil:      jwtUtil,
+		deactivation: deactivationService,
+		tokenService: tokenService,
+		loginPolicy:  loginPolicy,
 		db:           db,
 		logger:       logger,
 	}
@@ -71,6 +89,11 @@ func (s *phoneAuthService) LoginOrRegister(ctx context.Context, data dto.PhoneLo
 	emptyUserInfo := vo.Userinfo{}
 	emptyTokenPair := vo.TokenPair{}
 
+	// 0. 注册关闭且配置为整体拒绝时，不校验验证码直接返回
+	if err := s.registration.CheckLoginOrRegisterEntry(registration.ChannelPhone); err != nil {
+		return emptyUserInfo, emptyTokenPair, err
+	}
+
 	// 1. 验证验证码
 	storedCode, err := s.codeRepo.GetCaptcha(ctx, data.Phone)
 	if err != nil {
@@ -79,7 +102,7 @@ func (s *phoneAuthService) LoginOrRegister(ctx context.Context, data dto.PhoneLo
 				zap.String("operation", operation),
 				zap.String("phone", data.Phone),
 			)
-			return emptyUserInfo, emptyTokenPair, errors.New("验证码错误或已过期")
+			return emptyUserInfo, emptyTokenPair, loginerr.ErrInvalidCaptcha
 		}
 		s.logger.Error("获取验证码失败",
 			zap.String("operation", operation),
@@ -94,7 +117,7 @@ func (s *phoneAuthService) LoginOrRegister(ctx context.Context, data dto.PhoneLo
 			zap.String("operation", operation),
 			zap.String("phone", data.Phone),
 		)
-		return emptyUserInfo, emptyTokenPair, errors.New("验证码错误或已过期")
+		return emptyUserInfo, emptyTokenPair, loginerr.ErrInvalidCaptcha
 	}
 
 	if err := s.codeRepo.DeleteCaptcha(ctx, data.Phone); err != nil {
@@ -112,7 +135,10 @@ func (s *phoneAuthService) LoginOrRegister(ctx context.Context, data dto.PhoneLo
 
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrRepoNotFound) {
-			// 3. 用户身份不存在，执行自动注册流程
+			// 3. 用户身份不存在，执行自动注册流程 (注册关闭时拒绝)
+			if err := s.registration.CheckRegistration(registration.ChannelPhone); err != nil {
+				return emptyUserInfo, emptyTokenPair, err
+			}
 			newUserID := uuid.New().String()
 			s.logger.Info("手机号用户首次登录，开始自动注册",
 				zap.String("operation", operation),
@@ -120,35 +146,22 @@ func (s *phoneAuthService) LoginOrRegister(ctx context.Context, data dto.PhoneLo
 				zap.String("newUserID", newUserID),
 			)
 
-			newUser := &entities.User{
-				UserID:   newUserID,
-				UserRole: enums.RoleUser,
-				Status:   enums.StatusActive,
-			}
 			newIdentity := &entities.UserIdentity{
 				UserID:       newUserID,
 				IdentityType: myenums.Phone,
 				Identifier:   data.Phone,
-				Credential:   "", // 手机号登录通常无密码
+				Credential:   "",   // 手机号登录通常无密码
+				Verified:     true, // 已通过短信验证码证明手机号归属
+				IsPrimary:    true, // 注册时创建的身份即为主登录方式
 			}
-			// 准备初始用户资料实体
+			// 准备初始用户资料实体 (昵称由开户服务按默认昵称策略生成，默认不使用手机号)
 			initialProfile := &entities.UserProfile{
-				UserID:   newUserID,
-				Nickname: data.Phone,
+				UserID: newUserID,
 			}
 
 			txErr := s.db.Transaction(func(tx *gorm.DB) error {
-				if err := s.userRepo.CreateUser(ctx, tx, newUser); err != nil {
-					return fmt.Errorf("事务中创建用户失败: %w", err)
-				}
-				if err := s.identityRepo.CreateIdentity(ctx, tx, newIdentity); err != nil {
-					return fmt.Errorf("事务中创建身份失败: %w", err)
-				}
-				// 在事务中创建初始用户资料
-				if err := s.profileRepo.CreateProfile(ctx, tx, initialProfile); err != nil {
-					return fmt.Errorf("事务中创建初始用户资料失败: %w", err)
-				}
-				return nil // 事务成功
+				_, err := s.provisioning.CreateUserWithIdentity(ctx, tx, enums.RoleUser, enums.StatusActive, newIdentity, initialProfile)
+				return err
 			})
 
 			if txErr != nil {
@@ -175,6 +188,18 @@ func (s *phoneAuthService) LoginOrRegister(ctx context.Context, data dto.PhoneLo
 		}
 	} else {
 		userID = identityCredential.UserID
+		// 登录策略要求身份已验证时：本次验证码校验已证明手机号归属，直接将未验证的手机号身份标记为已验证
+		if s.loginPolicy.RequireVerifiedIdentity && !identityCredential.Verified {
+			if err := s.identityRepo.MarkVerified(ctx, myenums.Phone, data.Phone); err != nil {
+				s.logger.Error("验证码登录时标记手机号身份为已验证失败",
+					zap.String("operation", operation),
+					zap.String("userID", userID),
+					zap.Error(err),
+				)
+				return emptyUserInfo, emptyTokenPair, commonerrors.ErrSystemError
+			}
+			s.logger.Info("验证码登录已验证手机号身份", zap.String("operation", operation), zap.String("userID", userID))
+		}
 		s.logger.Info("手机号用户已存在，直接登录",
 			zap.String("operation", operation),
 			zap.String("userID", userID),
@@ -201,13 +226,29 @@ func (s *phoneAuthService) LoginOrRegister(ctx context.Context, data dto.PhoneLo
 	}
 
 	// 5. 检查用户状态
+	// 已停用的账号：本次登录已证明身份，签发重新激活凭证供客户端走重新激活流程
+	if user.Status == myenums.StatusDeactivated {
+		return emptyUserInfo, emptyTokenPair, s.deactivation.ChallengeForReactivation(ctx, user.UserID)
+	}
 	if user.Status != enums.StatusActive {
 		s.logger.Warn("尝试登录但用户状态异常",
 			zap.String("operation", operation),
 			zap.String("userID", user.UserID),
 			zap.Any("status", user.Status),
 		)
-		return emptyUserInfo, emptyTokenPair, fmt.Errorf("用户状态异常，无法登录")
+		if user.Status == enums.StatusBlacklisted {
+			return emptyUserInfo, emptyTokenPair, loginerr.ErrAccountBlacklisted
+		}
+		return emptyUserInfo, emptyTokenPair, loginerr.ErrAccountUnavailable
+	}
+
+	// 记录该登录方式的最近使用时间（尽力而为，失败不影响登录）
+	if err := s.identityRepo.UpdateLastUsedAt(ctx, myenums.Phone, data.Phone, time.Now()); err != nil {
+		s.logger.Warn("更新手机号身份最近使用时间失败",
+			zap.String("operation", operation),
+			zap.String("userID", user.UserID),
+			zap.Error(err),
+		)
 	}
 
 	// 6. 生成令牌
@@ -220,7 +261,7 @@ func (s *phoneAuthService) LoginOrRegister(ctx context.Context, data dto.PhoneLo
 		)
 		return emptyUserInfo, emptyTokenPair, commonerrors.ErrSystemError
 	}
-	refreshToken, err := s.jwtUtil.GenerateRefreshToken(user.UserID, platform)
+	refreshToken, err := s.tokenService.IssueRefreshToken(ctx, user.UserID, platform)
 	if err != nil {
 		s.logger.Error("生成刷新令牌失败",
 			zap.String("operation", operation),
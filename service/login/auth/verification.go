@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	myenums "github.com/Xushengqwer/user_hub/models/enums"
+)
+
+// SendCaptchaPath 发送短信验证码接口的路径；手机号身份可通过验证码登录完成验证
+const SendCaptchaPath = "/api/v1/user-hub/auth/send-captcha"
+
+// IdentityNotVerifiedError 开启"登录要求已验证身份"策略后，使用未验证的身份登录时返回的错误。
+// 只在凭证校验通过后返回，不会向未提供正确凭证的请求泄露身份的验证状态。
+type IdentityNotVerifiedError struct {
+	IdentityType myenums.IdentityType // IdentityType: 本次登录使用的身份类型
+	ResendPath   string               // ResendPath: 重新获取验证码的接口路径，该身份类型没有自助验证途径时为空
+}
+
+// Error 实现 error 接口
+func (e *IdentityNotVerifiedError) Error() string {
+	return "请先验证您的账号"
+}
+
+// checkIdentityVerified 按登录策略检查本次登录所用身份是否已验证，策略关闭时始终通过。
+func checkIdentityVerified(policy config.LoginPolicyConfig, identityType myenums.IdentityType, credential *dto.IdentityCredential) error {
+	if !policy.RequireVerifiedIdentity || credential.Verified {
+		return nil
+	}
+	resendPath := ""
+	if identityType == myenums.Phone {
+		resendPath = SendCaptchaPath
+	}
+	return &IdentityNotVerifiedError{IdentityType: identityType, ResendPath: resendPath}
+}
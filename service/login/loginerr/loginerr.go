@@ -0,0 +1,28 @@
+// Package loginerr 定义各登录流程 (账号、手机号、微信、统一登录) 共用的业务错误。
+// 控制器据此为登录失败响应附加稳定的原因码 (vo.LoginReason*)，错误文案本身保持通用，避免泄露账号是否存在等信息。
+package loginerr
+
+import "errors"
+
+var (
+	// ErrInvalidCredentials 账号不存在或密码错误；两种情况不作区分，防止账号枚举
+	ErrInvalidCredentials = errors.New("账号不存在或密码错误")
+
+	// ErrInvalidCaptcha 短信验证码错误或已过期；不区分验证码不存在与不匹配
+	ErrInvalidCaptcha = errors.New("验证码错误或已过期")
+
+	// ErrInvalidWechatCode 微信登录 code 无效或已过期，客户端应重新调用 wx.login 获取
+	ErrInvalidWechatCode = errors.New("微信登录凭证无效或已过期，请重新登录")
+
+	// ErrAccountBlacklisted 账号已被封禁 (只在证明身份之后返回)
+	ErrAccountBlacklisted = errors.New("账号已被封禁，无法登录")
+
+	// ErrAccountUnavailable 账号处于其他不允许登录的状态
+	ErrAccountUnavailable = errors.New("用户状态异常，无法登录")
+
+	// ErrWechatUnavailable 微信接口暂时不可用，客户端可稍后重试
+	ErrWechatUnavailable = errors.New("微信登录凭证校验失败，请稍后重试")
+
+	// ErrRegistrationClosed 注册开关已关闭，不再创建新用户 (已有用户的登录不受影响)
+	ErrRegistrationClosed = errors.New("注册暂未开放")
+)
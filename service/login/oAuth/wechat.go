@@ -3,7 +3,8 @@ package oAuth
 import (
 	"context"
 	"errors"
-	"fmt" // 引入 fmt 包用于错误包装
+	"fmt"
+	"time"
 
 	// 引入公共模块
 	"github.com/Xushengqwer/go-common/commonerrors"
@@ -12,13 +13,20 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap" // 引入 zap 用于日志字段
 
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/entities"
 	myenums "github.com/Xushengqwer/user_hub/models/enums" // 确保 myenums 别名被正确使用
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/repository/mysql"
-	"github.com/Xushengqwer/user_hub/repository/redis" // 虽然此服务目前未使用，但保持依赖注入的完整性
+	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/deactivation"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
+	"github.com/Xushengqwer/user_hub/service/provisioning"
+	"github.com/Xushengqwer/user_hub/service/registration"
+	"github.com/Xushengqwer/user_hub/service/token"
+	"github.com/Xushengqwer/user_hub/utils"
 
 	"gorm.io/gorm"
 )
@@ -31,37 +39,66 @@ type WechatMiniProgramService interface {
 	// - platform: 发起请求的客户端平台类型。
 	// - 返回: 包含用户 ID 的 Userinfo、包含访问和刷新令牌的 TokenPair，以及可能发生的错误 (对上层友好)。
 	LoginOrRegister(ctx context.Context, data dto.WechatMiniProgramLoginData, platform enums.Platform) (vo.Userinfo, vo.TokenPair, error)
+
+	// RefreshSession 为已登录用户用新的 code 刷新缓存的 session_key，不会创建账号或身份。
+	// - ctx: 请求上下文。
+	// - userID: 当前登录用户的 ID。
+	// - code: 小程序通过 wx.login() 重新获取的临时登录凭证。
+	// - 返回: code 对应的微信账号未绑定到当前用户时返回 ErrWechatSessionMismatch；
+	//   code 无效返回 loginerr.ErrInvalidWechatCode，微信暂不可用返回 loginerr.ErrWechatUnavailable，其他失败返回 ErrSystemError。
+	RefreshSession(ctx context.Context, userID string, code string) error
 }
 
+// ErrWechatSessionMismatch code 对应的微信账号没有绑定到当前用户，拒绝刷新会话
+var ErrWechatSessionMismatch = errors.New("该微信账号未绑定到当前用户")
+
 // wechatMiniProgramService 是 WechatMiniProgramService 接口的实现。
 type wechatMiniProgramService struct {
-	identityRepo   mysql.IdentityRepository       // 身份仓库
-	userRepo       mysql.UserRepository           // 用户仓库
-	profileRepo    mysql.ProfileRepository        // 用户资料仓库
-	tokenBlackRepo redis.TokenBlackRepo           // 令牌黑名单仓库
-	jwtUtil        dependencies.JWTTokenInterface // JWT 工具
-	wechatClient   dependencies.WechatClient      // 微信 API 客户端
-	db             *gorm.DB                       // 数据库连接 (用于启动事务和非事务操作)
-	logger         *core.ZapLogger                // 日志记录器
+	identityRepo   mysql.IdentityRepository                // 身份仓库
+	userRepo       mysql.UserRepository                    // 用户仓库
+	provisioning   provisioning.UserProvisioningService    // 新用户开户服务
+	registration   registration.RegistrationGateService    // 注册开关 (关闭时拒绝自动注册)
+	tokenBlackRepo redis.TokenBlackRepo                    // 令牌黑名单仓库
+	jwtUtil        dependencies.JWTTokenInterface          // JWT 工具
+	deactivation   deactivation.AccountDeactivationService // 账号停用/重新激活服务
+	tokenService   token.AuthTokenService                  // 令牌服务，用于签发刷新令牌
+	wechatClient   dependencies.WechatClient               // 微信 API 客户端
+	sessionRepo    redis.WechatSessionRepo                 // 微信 session_key 缓存仓库
+	wechatCfg      config.WechatConfig                     // 微信配置 (session_key 缓存时长)
+	nicknameCfg    config.NicknameConfig                   // 昵称规则，用于校验微信昵称
+	db             *gorm.DB                                // 数据库连接 (用于启动事务和非事务操作)
+	logger         *core.ZapLogger                         // 日志记录器
 }
 
 func NewWechatMiniProgramService(
 	identityRepo mysql.IdentityRepository,
 	userRepo mysql.UserRepository,
-	profileRepo mysql.ProfileRepository,
+	provisioningService provisioning.UserProvisioningService,
+	registrationGate registration.RegistrationGateService,
 	tokenBlackRepo redis.TokenBlackRepo,
 	jwtUtil dependencies.JWTTokenInterface,
+	deactivationService deactivation.AccountDeactivationService,
+	tokenService token.AuthTokenService,
 	wechatClient dependencies.WechatClient,
+	sessionRepo redis.WechatSessionRepo,
+	wechatCfg config.WechatConfig,
+	nicknameCfg config.NicknameConfig,
 	db *gorm.DB,
 	logger *core.ZapLogger, // 添加 logger 参数
 ) WechatMiniProgramService {
 	return &wechatMiniProgramService{
 		identityRepo:   identityRepo,
 		userRepo:       userRepo,
-		profileRepo:    profileRepo,
+		provisioning:   provisioningService,
+		registration:   registrationGate,
 		tokenBlackRepo: tokenBlackRepo,
 		jwtUtil:        jwtUtil,
+		deactivation:   deactivationService,
+		tokenService:   tokenService,
 		wechatClient:   wechatClient,
+		sessionRepo:    sessionRepo,
+		wechatCfg:      wechatCfg,
+		nicknameCfg:    nicknameCfg,
 		db:             db,
 		logger:         logger,
 	}
@@ -73,15 +110,15 @@ func (s *wechatMiniProgramService) LoginOrRegister(ctx context.Context, data dto
 	emptyUserInfo := vo.Userinfo{}
 	emptyTokenPair := vo.TokenPair{}
 
+	// 0. 注册关闭且配置为整体拒绝时，不调用微信接口直接返回
+	if err := s.registration.CheckLoginOrRegisterEntry(registration.ChannelWechat); err != nil {
+		return emptyUserInfo, emptyTokenPair, err
+	}
+
 	// 1. 调用微信 API 获取 OpenID 和 SessionKey
-	openid, _, err := s.wechatClient.GetSession(ctx, data.Code)
+	openid, sessionKey, err := s.exchangeCode(ctx, operation, data.Code)
 	if err != nil {
-		s.logger.Error("调用微信 GetSession 失败",
-			zap.String("operation", operation),
-			zap.String("code", data.Code), // 注意：code 是一次性的，记录它可能对调试有帮助，但要注意敏感性
-			zap.Error(err),
-		)
-		return emptyUserInfo, emptyTokenPair, fmt.Errorf("微信登录凭证校验失败，请稍后重试")
+		return emptyUserInfo, emptyTokenPair, err
 	}
 
 	// 2. 尝试根据 OpenID 查找用户身份凭证
@@ -90,7 +127,10 @@ func (s *wechatMiniProgramService) LoginOrRegister(ctx context.Context, data dto
 
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrRepoNotFound) {
-			// 3. 用户身份不存在，执行自动注册流程
+			// 3. 用户身份不存在，执行自动注册流程 (注册关闭时拒绝)
+			if err := s.registration.CheckRegistration(registration.ChannelWechat); err != nil {
+				return emptyUserInfo, emptyTokenPair, err
+			}
 			newUserID := uuid.New().String()
 			s.logger.Info("微信用户首次登录，开始自动注册",
 				zap.String("operation", operation),
@@ -98,35 +138,23 @@ func (s *wechatMiniProgramService) LoginOrRegister(ctx context.Context, data dto
 				zap.String("newUserID", newUserID),
 			)
 
-			newUser := &entities.User{
-				UserID:   newUserID,
-				UserRole: enums.RoleUser,
-				Status:   enums.StatusActive,
-			}
 			newIdentity := &entities.UserIdentity{
 				UserID:       newUserID,
 				IdentityType: myenums.WechatMiniProgram,
 				Identifier:   openid,
-				Credential:   "", // 微信登录通常无密码凭证，或存储 session_key (需谨慎，当前为空)
+				Credential:   "",   // 微信登录通常无密码凭证，或存储 session_key (需谨慎，当前为空)
+				Verified:     true, // OpenID 由微信接口返回，归属已由微信保证
+				IsPrimary:    true, // 注册时创建的身份即为主登录方式
 			}
-			// 准备初始用户资料实体
+			// 准备初始用户资料实体 (微信昵称缺失或无效时，由开户服务按默认昵称策略生成)
 			initialProfile := &entities.UserProfile{
-				UserID: newUserID,
-				// todo : Nickname 后续可以直接采取微信用户的昵称
+				UserID:   newUserID,
+				Nickname: s.wechatNickname(data.Nickname),
 			}
 
 			txErr := s.db.Transaction(func(tx *gorm.DB) error {
-				if err := s.userRepo.CreateUser(ctx, tx, newUser); err != nil {
-					return fmt.Errorf("事务中创建用户失败: %w", err)
-				}
-				if err := s.identityRepo.CreateIdentity(ctx, tx, newIdentity); err != nil {
-					return fmt.Errorf("事务中创建身份失败: %w", err)
-				}
-				// 在事务中创建初始用户资料
-				if err := s.profileRepo.CreateProfile(ctx, tx, initialProfile); err != nil {
-					return fmt.Errorf("事务中创建初始用户资料失败: %w", err)
-				}
-				return nil // 事务成功
+				_, err := s.provisioning.CreateUserWithIdentity(ctx, tx, enums.RoleUser, enums.StatusActive, newIdentity, initialProfile)
+				return err
 			})
 
 			if txErr != nil {
@@ -179,15 +207,34 @@ func (s *wechatMiniProgramService) LoginOrRegister(ctx context.Context, data dto
 	}
 
 	// 5. 检查用户状态
+	// 已停用的账号：本次登录已证明身份，签发重新激活凭证供客户端走重新激活流程
+	if user.Status == myenums.StatusDeactivated {
+		return emptyUserInfo, emptyTokenPair, s.deactivation.ChallengeForReactivation(ctx, user.UserID)
+	}
 	if user.Status != enums.StatusActive {
 		s.logger.Warn("用户尝试登录但状态异常",
 			zap.String("operation", operation),
 			zap.String("userID", userID),
 			zap.Any("status", user.Status),
 		)
-		return emptyUserInfo, emptyTokenPair, fmt.Errorf("用户状态异常，无法登录")
+		if user.Status == enums.StatusBlacklisted {
+			return emptyUserInfo, emptyTokenPair, loginerr.ErrAccountBlacklisted
+		}
+		return emptyUserInfo, emptyTokenPair, loginerr.ErrAccountUnavailable
+	}
+
+	// 记录该登录方式的最近使用时间（尽力而为，失败不影响登录）
+	if err := s.identityRepo.UpdateLastUsedAt(ctx, myenums.WechatMiniProgram, openid, time.Now()); err != nil {
+		s.logger.Warn("更新微信身份最近使用时间失败",
+			zap.String("operation", operation),
+			zap.String("userID", user.UserID),
+			zap.Error(err),
+		)
 	}
 
+	// 缓存本次的 session_key，供后续解密微信加密数据（尽力而为，失败不影响登录）
+	s.saveSession(ctx, operation, user.UserID, openid, sessionKey)
+
 	// 6. 生成令牌
 	accessToken, err := s.jwtUtil.GenerateAccessToken(user.UserID, user.UserRole, user.Status, platform)
 	if err != nil {
@@ -198,7 +245,7 @@ func (s *wechatMiniProgramService) LoginOrRegister(ctx context.Context, data dto
 		)
 		return emptyUserInfo, emptyTokenPair, commonerrors.ErrServiceBusy
 	}
-	refreshToken, err := s.jwtUtil.GenerateRefreshToken(user.UserID, platform)
+	refreshToken, err := s.tokenService.IssueRefreshToken(ctx, user.UserID, platform)
 	if err != nil {
 		s.logger.Error("生成刷新令牌失败",
 			zap.String("operation", operation),
@@ -221,3 +268,96 @@ func (s *wechatMiniProgramService) LoginOrRegister(ctx context.Context, data dto
 	}
 	return userInfo, tokenPair, nil
 }
+
+// RefreshSession 实现接口方法，刷新已登录用户的微信会话。
+func (s *wechatMiniProgramService) RefreshSession(ctx context.Context, userID string, code string) error {
+	const operation = "WechatMiniProgramService.RefreshSession"
+
+	// 1. 用新的 code 换取 OpenID 和 SessionKey
+	openid, sessionKey, err := s.exchangeCode(ctx, operation, code)
+	if err != nil {
+		return err
+	}
+
+	// 2. 只刷新已有身份：OpenID 必须已绑定到当前用户，绝不在此创建账号或身份
+	identityCredential, err := s.identityRepo.GetIdentityByTypeAndIdentifier(ctx, myenums.WechatMiniProgram, openid)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("刷新微信会话时 OpenID 未绑定任何用户", zap.String("operation", operation), zap.String("userID", userID))
+			return ErrWechatSessionMismatch
+		}
+		s.logger.Error("刷新微信会话时查找微信身份失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	if identityCredential.UserID != userID {
+		s.logger.Warn("刷新微信会话时 OpenID 属于其他用户", zap.String("operation", operation), zap.String("userID", userID))
+		return ErrWechatSessionMismatch
+	}
+
+	// 3. 覆盖缓存的 session_key，并更新该身份的最近使用时间
+	if err := s.sessionRepo.SaveSession(ctx, userID, redis.WechatSession{OpenID: openid, SessionKey: sessionKey}, s.wechatCfg.SessionTTLOrDefault()); err != nil {
+		s.logger.Error("保存刷新后的微信会话失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	if err := s.identityRepo.UpdateLastUsedAt(ctx, myenums.WechatMiniProgram, openid, time.Now()); err != nil {
+		s.logger.Warn("更新微信身份最近使用时间失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+	}
+
+	s.logger.Info("微信会话已刷新", zap.String("operation", operation), zap.String("userID", userID))
+	return nil
+}
+
+// exchangeCode 调用微信 API 用 code 换取 OpenID 和 SessionKey，并将失败映射为对上层友好的错误。
+// - 客户端原因 (code 无效/过期) 返回 loginerr.ErrInvalidWechatCode，配置错误返回 ErrSystemError，其他失败返回 loginerr.ErrWechatUnavailable。
+func (s *wechatMiniProgramService) exchangeCode(ctx context.Context, operation string, code string) (string, string, error) {
+	openid, sessionKey, err := s.wechatClient.GetSession(ctx, code)
+	if err == nil {
+		return openid, sessionKey, nil
+	}
+
+	// 区分微信业务错误：客户端原因（code 无效/过期）提示重新登录，配置错误视为系统错误
+	var apiErr *dependencies.WechatAPIError
+	if errors.As(err, &apiErr) && apiErr.IsClientError() {
+		s.logger.Warn("微信登录凭证无效",
+			zap.String("operation", operation),
+			zap.Int("errcode", apiErr.ErrCode),
+			zap.String("errmsg", apiErr.ErrMsg),
+		)
+		return "", "", loginerr.ErrInvalidWechatCode
+	}
+	s.logger.Error("调用微信 GetSession 失败",
+		zap.String("operation", operation),
+		zap.String("code", code), // 注意：code 是一次性的，记录它可能对调试有帮助，但要注意敏感性
+		zap.Error(err),
+	)
+	if apiErr != nil && apiErr.IsConfigError() {
+		return "", "", commonerrors.ErrSystemError
+	}
+	return "", "", fmt.Errorf("%w: %w", loginerr.ErrWechatUnavailable, dependencies.ClassifyUpstreamError(dependencies.UpstreamServiceWechat, err))
+}
+
+// saveSession 缓存用户的微信会话；失败只记录日志，由客户端在需要时调用刷新接口补齐。
+func (s *wechatMiniProgramService) saveSession(ctx context.Context, operation string, userID string, openid string, sessionKey string) {
+	if sessionKey == "" {
+		return
+	}
+	if err := s.sessionRepo.SaveSession(ctx, userID, redis.WechatSession{OpenID: openid, SessionKey: sessionKey}, s.wechatCfg.SessionTTLOrDefault()); err != nil {
+		s.logger.Warn("缓存微信会话失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+	}
+}
+
+// wechatNickname 规范化并校验前端传入的微信昵称，不符合昵称规则时返回空字符串 (不阻断注册)。
+func (s *wechatMiniProgramService) wechatNickname(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	nickname, err := utils.NormalizeText(raw)
+	if err != nil || !utils.IsValidNickname(nickname, s.nicknameCfg) {
+		s.logger.Warn("微信昵称不符合昵称规则，已忽略",
+			zap.String("operation", "WechatMiniProgramService.wechatNickname"),
+			zap.Int("length", len([]rune(raw))),
+		)
+		return ""
+	}
+	return nickname
+}
@@ -0,0 +1,85 @@
+package oAuth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
+	"github.com/Xushengqwer/user_hub/service/registration"
+)
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// stubWechatClient 内嵌接口，GetSession 固定返回预设的错误；调用未实现的方法会 panic
+type stubWechatClient struct {
+	dependencies.WechatClient
+	err   error
+	codes []string
+}
+
+func (c *stubWechatClient) GetSession(_ context.Context, code string) (string, string, error) {
+	c.codes = append(c.codes, code)
+	return "", "", c.err
+}
+
+func TestLoginOrRegisterMapsWechatErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    error
+		notWant error
+	}{
+		{name: "code 无效", err: &dependencies.WechatAPIError{ErrCode: dependencies.WechatErrCodeInvalidCode, ErrMsg: "invalid code"}, want: loginerr.ErrInvalidWechatCode},
+		{name: "code 已被使用", err: &dependencies.WechatAPIError{ErrCode: dependencies.WechatErrCodeCodeUsed, ErrMsg: "code been used"}, want: loginerr.ErrInvalidWechatCode},
+		{name: "高风险用户", err: &dependencies.WechatAPIError{ErrCode: dependencies.WechatErrCodeHighRiskUser, ErrMsg: "high risk"}, want: loginerr.ErrInvalidWechatCode},
+		{name: "被包装的客户端错误", err: fmt.Errorf("wechatClient.GetSession: %w", &dependencies.WechatAPIError{ErrCode: dependencies.WechatErrCodeInvalidCode}), want: loginerr.ErrInvalidWechatCode},
+		{name: "AppID 无效", err: &dependencies.WechatAPIError{ErrCode: dependencies.WechatErrCodeInvalidAppID}, want: commonerrors.ErrSystemError, notWant: loginerr.ErrInvalidWechatCode},
+		{name: "AppSecret 无效", err: &dependencies.WechatAPIError{ErrCode: dependencies.WechatErrCodeInvalidAppSecret}, want: commonerrors.ErrSystemError, notWant: loginerr.ErrInvalidWechatCode},
+		{name: "缺少 AppID", err: &dependencies.WechatAPIError{ErrCode: dependencies.WechatErrCodeMissingAppID}, want: commonerrors.ErrSystemError, notWant: loginerr.ErrInvalidWechatCode},
+		{name: "缺少 AppSecret", err: fmt.Errorf("wechatClient.GetSession: %w", &dependencies.WechatAPIError{ErrCode: dependencies.WechatErrCodeMissingAppSecret}), want: commonerrors.ErrSystemError, notWant: loginerr.ErrInvalidWechatCode},
+		{name: "其他微信业务错误", err: &dependencies.WechatAPIError{ErrCode: -1, ErrMsg: "system busy"}, want: loginerr.ErrWechatUnavailable, notWant: commonerrors.ErrSystemError},
+		{name: "网络错误", err: errors.New("connection refused"), want: loginerr.ErrWechatUnavailable, notWant: commonerrors.ErrSystemError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := newTestLogger(t)
+			client := &stubWechatClient{err: tt.err}
+			// 仓库与事务依赖保持为 nil：换取 session 失败后服务不得继续查找或创建用户
+			service := NewWechatMiniProgramService(nil, nil, nil,
+				registration.NewRegistrationGateService(config.RegistrationConfig{}, logger),
+				nil, nil, nil, nil, client, nil, config.WechatConfig{}, config.NicknameConfig{}, nil, logger)
+
+			userInfo, tokens, err := service.LoginOrRegister(context.Background(), dto.WechatMiniProgramLoginData{Code: "wx-code"}, enums.PlatformWechat)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("错误 = %v，期望 %v", err, tt.want)
+			}
+			if tt.notWant != nil && errors.Is(err, tt.notWant) {
+				t.Errorf("错误 = %v，不应同时匹配 %v", err, tt.notWant)
+			}
+			if userInfo.UserID != "" || tokens.AccessToken != "" || tokens.RefreshToken != "" {
+				t.Errorf("失败时不应返回用户或令牌: %+v %+v", userInfo, tokens)
+			}
+			if len(client.codes) != 1 || client.codes[0] != "wx-code" {
+				t.Errorf("GetSession 调用参数 = %v，期望只用 wx-code 调用一次", client.codes)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package unified
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/service/login/auth"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// UnifiedLoginService 定义了"任意标识符登录"的统一入口服务接口。
+// - 根据标识符类型与凭证类型分发到对应的专用登录服务，自身不包含认证逻辑。
+type UnifiedLoginService interface {
+	// Login 识别标识符类型并分发到账号密码登录或手机号验证码登录。
+	// - data.Type 为空时按格式自动识别：邮箱 -> email，手机号 -> phone，其余 -> account。
+	// - 提供 password 时视为密码登录，否则视为验证码登录。
+	// - 返回: 用户信息、令牌对，以及分发后服务返回的业务错误或系统错误。
+	Login(ctx context.Context, data dto.UnifiedLoginData, platform enums.Platform) (vo.Userinfo, vo.TokenPair, error)
+}
+
+// unifiedLoginService 是 UnifiedLoginService 接口的实现。
+type unifiedLoginService struct {
+	accountService auth.AccountService       // 账号密码登录服务
+	phoneService   auth.PhoneAuthService     // 手机号验证码登录服务
+	cfg            config.UnifiedLoginConfig // 统一登录配置 (允许的身份类型)
+	accountCfg     config.AccountConfig      // 账号格式策略，决定邮箱是否可作为账号登录
+	logger         *core.ZapLogger           // 日志记录器
+}
+
+// NewUnifiedLoginService 创建一个新的 UnifiedLoginService 实例。
+func NewUnifiedLoginService(
+	accountService auth.AccountService,
+	phoneService auth.PhoneAuthService,
+	cfg config.UnifiedLoginConfig,
+	accountCfg config.AccountConfig,
+	logger *core.ZapLogger,
+) UnifiedLoginService {
+	return &unifiedLoginService{
+		accountService: accountService,
+		phoneService:   phoneService,
+		cfg:            cfg,
+		accountCfg:     accountCfg,
+		logger:         logger,
+	}
+}
+
+// DetectIdentifierType 根据标识符格式推断身份类型：邮箱 -> email，手机号 -> phone，其余 -> account。
+func DetectIdentifierType(identifier string) string {
+	switch {
+	case utils.IsEmail(identifier):
+		return config.UnifiedLoginTypeEmail
+	case utils.IsChinesePhone(identifier):
+		return config.UnifiedLoginTypePhone
+	default:
+		return config.UnifiedLoginTypeAccount
+	}
+}
+
+// Login 实现了 UnifiedLoginService 接口的 Login 方法。
+func (s *unifiedLoginService) Login(ctx context.Context, data dto.UnifiedLoginData, platform enums.Platform) (vo.Userinfo, vo.TokenPair, error) {
+	const operation = "UnifiedLoginService.Login"
+
+	// 1. 确定身份类型：显式指定优先，否则按格式识别
+	identityType := strings.ToLower(strings.TrimSpace(data.Type))
+	if identityType == "" {
+		identityType = DetectIdentifierType(data.Identifier)
+	}
+	// 账号格式策略允许邮箱时，邮箱即账号
+	if identityType == config.UnifiedLoginTypeEmail && s.accountCfg.AllowsEmail() {
+		identityType = config.UnifiedLoginTypeAccount
+	}
+	if !s.cfg.TypeAllowed(identityType) {
+		s.logger.Warn("统一登录入口不允许该身份类型",
+			zap.String("operation", operation),
+			zap.String("identityType", identityType),
+		)
+		return vo.Userinfo{}, vo.TokenPair{}, errors.New("该登录方式未在统一登录入口开放，请使用对应的专用登录接口")
+	}
+
+	// 2. 按凭证类型分发：有密码走密码登录，否则走验证码登录
+	usePassword := data.Password != ""
+	if !usePassword && data.Code == "" {
+		return vo.Userinfo{}, vo.TokenPair{}, errors.New("请提供密码或验证码")
+	}
+
+	switch identityType {
+	case config.UnifiedLoginTypeAccount:
+		if !usePassword {
+			return vo.Userinfo{}, vo.TokenPair{}, errors.New("账号登录需要提供密码")
+		}
+		return s.accountService.Login(ctx, dto.AccountLoginData{
+			Account:  data.Identifier,
+			Password: data.Password,
+		}, platform)
+	case config.UnifiedLoginTypePhone:
+		if usePassword {
+			return vo.Userinfo{}, vo.TokenPair{}, errors.New("手机号登录暂不支持密码，请使用验证码登录")
+		}
+		return s.phoneService.LoginOrRegister(ctx, dto.PhoneLoginOrRegisterData{
+			Phone: data.Identifier,
+			Code:  data.Code,
+		}, platform)
+	case config.UnifiedLoginTypeEmail:
+		return vo.Userinfo{}, vo.TokenPair{}, errors.New("暂不支持邮箱登录")
+	default:
+		return vo.Userinfo{}, vo.TokenPair{}, errors.New("不支持的登录类型")
+	}
+}
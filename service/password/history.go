@@ -0,0 +1,89 @@
+package password
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// ErrPasswordReused 表示新密码与当前密码或最近使用过的密码相同。
+var ErrPasswordReused = errors.New("新密码不能与最近使用过的密码相同")
+
+// PasswordHistoryService 定义了历史密码校验与记录的服务接口。
+// 设计目的:
+//   - 防止用户在修改/重置密码时轮换回旧密码，所有设置新密码的流程共用同一套规则。
+//   - 历史记录只保留最近 N 条 (PasswordPolicyConfig.HistorySize)。
+type PasswordHistoryService interface {
+	// EnsureNotReused 校验新密码未与当前密码及最近 N 个历史密码重复。
+	// 参数:
+	//  - userID: 用户ID。
+	//  - currentHash: 当前生效的密码哈希；历史表上线前注册的用户没有历史记录，需要单独比对当前密码。为空时跳过。
+	//  - newPassword: 用户提交的新密码明文。
+	// 返回:
+	//  - error: 重复时返回 ErrPasswordReused；查询失败返回 commonerrors.ErrSystemError。
+	EnsureNotReused(ctx context.Context, userID string, currentHash string, newPassword string) error
+
+	// Record 将新密码哈希写入历史并裁剪到最近 N 条。
+	// - tx 应为更新密码所在的事务，保证历史记录与密码更新同时提交或回滚。
+	// - 失败时返回包装后的错误，调用方负责回滚并映射为对外错误。
+	Record(ctx context.Context, tx *gorm.DB, userID string, newHash string) error
+}
+
+// passwordHistoryService 是 PasswordHistoryService 接口的实现。
+type passwordHistoryService struct {
+	repo   mysql.PasswordHistoryRepository // repo: 历史密码数据仓库。
+	cfg    config.PasswordPolicyConfig     // cfg: 密码策略配置。
+	logger *core.ZapLogger                 // logger: 日志记录器。
+}
+
+// NewPasswordHistoryService 创建一个新的 passwordHistoryService 实例。
+func NewPasswordHistoryService(
+	repo mysql.PasswordHistoryRepository,
+	cfg config.PasswordPolicyConfig,
+	logger *core.ZapLogger,
+) PasswordHistoryService {
+	return &passwordHistoryService{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// EnsureNotReused 实现接口方法。
+func (s *passwordHistoryService) EnsureNotReused(ctx context.Context, userID string, currentHash string, newPassword string) error {
+	const operation = "PasswordHistoryService.EnsureNotReused"
+
+	if currentHash != "" && utils.CheckPassword(currentHash, newPassword) == nil {
+		s.logger.Warn("新密码与当前密码相同", zap.String("operation", operation), zap.String("userID", userID))
+		return ErrPasswordReused
+	}
+
+	hashes, err := s.repo.ListRecentHashes(ctx, userID, s.cfg.HistorySizeOrDefault())
+	if err != nil {
+		s.logger.Error("查询历史密码失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	for _, hash := range hashes {
+		if utils.CheckPassword(hash, newPassword) == nil {
+			s.logger.Warn("新密码与最近使用过的密码相同", zap.String("operation", operation), zap.String("userID", userID))
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// Record 实现接口方法。
+func (s *passwordHistoryService) Record(ctx context.Context, tx *gorm.DB, userID string, newHash string) error {
+	if err := s.repo.AddPasswordHash(ctx, tx, userID, newHash); err != nil {
+		return err
+	}
+	return s.repo.TrimHistory(ctx, tx, userID, s.cfg.HistorySizeOrDefault())
+}
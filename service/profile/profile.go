@@ -1,12 +1,20 @@
 package profile
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"github.com/Xushengqwer/user_hub/config"
 	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/enums"
+	"image"
 	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
 
 	// 引入公共模块
 	"github.com/Xushengqwer/go-common/commonerrors"
@@ -17,10 +25,31 @@ import (
 	"github.com/Xushengqwer/user_hub/models/entities"
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/file"
+	"github.com/Xushengqwer/user_hub/utils"
 
 	"gorm.io/gorm"
 )
 
+// ErrAvatarUploadInProgress 同一用户已有头像上传正在处理
+var ErrAvatarUploadInProgress = errors.New("上传处理中，请稍后重试")
+
+// ProfileCooldownError 资料或头像在冷却时间内再次修改
+type ProfileCooldownError struct {
+	RetryAfter time.Duration // 距离冷却结束的时间
+}
+
+func (e *ProfileCooldownError) Error() string {
+	return "操作过于频繁，请稍后再试"
+}
+
+// ErrReverificationRequired 修改的资料字段属于敏感字段，而用户近期未重新验证身份
+var ErrReverificationRequired = errors.New("修改该资料前需要重新验证身份，请先校验当前密码")
+
+// avatarLockReleaseTimeout 释放头像上传锁的超时时间
+const avatarLockReleaseTimeout = time.Second
+
 // UserProfileService 定义了管理用户详细资料（如昵称、头像、性别、地区等）的服务接口。
 // 设计目的:
 // - 将用户的基础资料信息与核心用户账户（User）和身份凭证（UserIdentity）分离管理。
@@ -38,8 +67,9 @@ type UserProfileService interface {
 	//  - dto: 包含待更新资料字段的 DTO。DTO中的字段为可选更新，服务会根据DTO中提供的非空/非零值进行更新。
 	// 返回:
 	//  - *vo.ProfileVO: 更新后的用户资料的视图对象。
+	//  - bool: 是否有字段发生了实际变化；为 false 时未写库，返回的是现有资料。
 	//  - error: 操作过程中发生的任何错误。
-	UpdateProfile(ctx context.Context, userID string, dto *dto.UpdateProfileDTO) (*vo.ProfileVO, error)
+	UpdateProfile(ctx context.Context, userID string, dto *dto.UpdateProfileDTO) (*vo.ProfileVO, bool, error)
 
 	// UploadAndSetAvatar 上传用户头像到COS，并更新用户资料中的头像URL。
 	// 参数:
@@ -52,6 +82,46 @@ type UserProfileService interface {
 	//  - error: 操作过程中发生的任何错误。
 	UploadAndSetAvatar(ctx context.Context, userID string, fileName string, fileReader io.Reader, fileSize int64) (string, error)
 
+	// UpdateProfileWithAvatar 在一次调用中更新资料字段并（可选）更换头像，供 multipart 表单接口使用。
+	// 资料字段与头像作为整体生效：字段校验失败时不会上传头像；数据库更新失败时会删除本次新上传的头像对象。
+	// 参数:
+	//  - userID: 要更新资料的用户ID。
+	//  - dto: 待更新的资料字段，规则与 UpdateProfile 一致。
+	//  - fileName: 头像文件的原始名称，用于提取扩展名。
+	//  - fileReader: 头像文件内容；为 nil 时只更新资料字段。
+	//  - fileSize: 头像文件大小（字节）。
+	// 返回:
+	//  - *vo.ProfileVO: 更新后的用户资料的视图对象。
+	//  - error: 操作过程中发生的任何错误。
+	UpdateProfileWithAvatar(ctx context.Context, userID string, dto *dto.UpdateProfileDTO, fileName string, fileReader io.Reader, fileSize int64) (*vo.ProfileVO, error)
+
+	// UploadAvatarFromURL 从远程 URL 拉取图片作为用户头像，校验规则与文件上传一致。
+	// 拉取过程带有超时、大小与类型限制，并拒绝解析到内网/保留地址的主机 (SSRF 防护)。
+	// 参数:
+	//  - userID: 要更新头像的用户ID。
+	//  - imageURL: 远程图片地址，仅支持 http/https。
+	// 返回:
+	//  - string: 成功上传后头像的公开访问URL。
+	//  - error: 操作过程中发生的任何错误；链接无效、地址被拦截、内容不是图片等为业务错误。
+	UploadAvatarFromURL(ctx context.Context, userID string, imageURL string) (string, error)
+
+	// UploadAvatarFromBase64 使用 base64 编码的图片（如前端 canvas 导出的 data URI）作为用户头像，校验规则与文件上传一致。
+	// 参数:
+	//  - userID: 要更新头像的用户ID。
+	//  - dataURI: 形如 "data:image/png;base64,..." 的字符串。
+	// 返回:
+	//  - string: 成功上传后头像的公开访问URL。
+	//  - error: 操作过程中发生的任何错误；格式错误、MIME 类型不支持、文件过大等为业务错误。
+	UploadAvatarFromBase64(ctx context.Context, userID string, dataURI string) (string, error)
+
+	// DeleteProfile 删除指定用户的资料记录（管理员操作），核心用户与身份记录保持不变。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - userID: 要删除资料的用户ID。
+	// 返回:
+	//  - error: 资料不存在时返回业务错误，数据库操作失败时返回系统错误。
+	DeleteProfile(ctx context.Context, userID string) error
+
 	// GetMyAccountDetail 获取当前认证用户的聚合账户详情（核心信息 + 资料）。
 	// 参数:
 	//  - ctx: 请求上下文。
@@ -64,11 +134,22 @@ type UserProfileService interface {
 
 // userProfileService 是 UserProfileService 接口的实现。
 type userProfileService struct {
-	userRepo  mysql.UserRepository            // 用户核心信息仓库
-	repo      mysql.ProfileRepository         // repo: 用户资料数据仓库。
-	db        *gorm.DB                        // db: GORM数据库连接实例，用于传递给仓库层的写操作方法。
-	logger    *core.ZapLogger                 // logger: 日志记录器。
-	cosClient dependencies.COSClientInterface // <--- 新增此字段
+	userRepo    mysql.UserRepository            // 用户核心信息仓库
+	repo        mysql.ProfileRepository         // repo: 用户资料数据仓库。
+	db          *gorm.DB                        // db: GORM数据库连接实例，用于传递给仓库层的写操作方法。
+	logger      *core.ZapLogger                 // logger: 日志记录器。
+	cosClient   dependencies.COSClientInterface // <--- 新增此字段
+	cdnClient   dependencies.CDNClient          // cdnClient: 覆盖写入头像后用于刷新 CDN 缓存。
+	fileService file.FileService                // fileService: 存储配额检查与用量记录。
+	avatarLock  redis.AvatarLockRepo            // avatarLock: 按用户串行化头像上传的锁。
+	cooldown    redis.ProfileCooldownRepo       // cooldown: 资料与头像修改的冷却计时。
+	recentAuth  redis.RecentAuthRepo            // recentAuth: 修改敏感字段前检查的近期重新验证标记。
+	avatarCfg   config.AvatarConfig             // avatarCfg: 头像上传处理配置。
+	updateCfg   config.ProfileUpdateConfig      // updateCfg: 资料修改频率限制配置。
+	degrade     *dependencies.RedisDegradation  // degrade: 冷却时间查询失败时是否放行。
+	regionCfg   config.RegionConfig             // regionCfg: 省市一致性校验配置。
+	regions     utils.RegionDataset             // regions: 省市一致性校验使用的行政区划数据集。
+	fetchClient *http.Client                    // fetchClient: 拉取远程头像使用的 HTTP 客户端，带 SSRF 防护。
 }
 
 func NewUserProfileService(
@@ -77,13 +158,34 @@ func NewUserProfileService(
 	db *gorm.DB,
 	logger *core.ZapLogger,
 	cosClient dependencies.COSClientInterface, // <--- 新增此参数
+	cdnClient dependencies.CDNClient,
+	fileService file.FileService,
+	avatarLock redis.AvatarLockRepo,
+	cooldown redis.ProfileCooldownRepo,
+	recentAuth redis.RecentAuthRepo,
+	avatarCfg config.AvatarConfig,
+	updateCfg config.ProfileUpdateConfig,
+	degrade *dependencies.RedisDegradation,
+	regionCfg config.RegionConfig,
+	regions utils.RegionDataset,
 ) UserProfileService {
 	return &userProfileService{
-		userRepo:  userRepo,
-		repo:      repo,
-		db:        db,
-		logger:    logger,
-		cosClient: cosClient,
+		userRepo:    userRepo,
+		repo:        repo,
+		db:          db,
+		logger:      logger,
+		cosClient:   cosClient,
+		cdnClient:   cdnClient,
+		fileService: fileService,
+		avatarLock:  avatarLock,
+		cooldown:    cooldown,
+		recentAuth:  recentAuth,
+		avatarCfg:   avatarCfg,
+		updateCfg:   updateCfg,
+		degrade:     degrade,
+		regionCfg:   regionCfg,
+		regions:     regions,
+		fetchClient: utils.NewSafeHTTPClient(avatarCfg.FetchTimeoutOrDefault()),
 	}
 }
 
@@ -108,7 +210,7 @@ func profileEntityToVO(profile *entities.UserProfile) *vo.ProfileVO {
 }
 
 // UpdateProfile 实现接口方法
-func (s *userProfileService) UpdateProfile(ctx context.Context, userID string, dto *dto.UpdateProfileDTO) (*vo.ProfileVO, error) {
+func (s *userProfileService) UpdateProfile(ctx context.Context, userID string, dto *dto.UpdateProfileDTO) (*vo.ProfileVO, bool, error) {
 	const operation = "UserProfileService.UpdateProfile"
 
 	// 1. 查询目标用户资料是否存在
@@ -119,56 +221,39 @@ func (s *userProfileService) UpdateProfile(ctx context.Context, userID string, d
 				zap.String("operation", operation),
 				zap.String("userID", userID),
 			)
-			return nil, errors.New("要更新的用户资料不存在")
+			return nil, false, errors.New("要更新的用户资料不存在")
 		}
 		s.logger.Error("更新用户资料前查询失败",
 			zap.String("operation", operation),
 			zap.String("userID", userID),
 			zap.Error(err),
 		)
-		return nil, commonerrors.ErrSystemError
+		return nil, false, commonerrors.ErrSystemError
 	}
 
 	// 2. 根据 DTO 中非 nil 的字段更新实体 (Patch Update Logic)
-	updated := false // 标记是否有字段被实际更新
-
-	if dto.Nickname != nil && profileEntity.Nickname != *dto.Nickname {
-		// 检查 Nickname 指针是否非 nil，并且值与当前实体中的值不同
-		profileEntity.Nickname = *dto.Nickname // 解引用指针获取值并更新
-		updated = true
-	}
-	if dto.Gender != nil {
-		// 检查 Gender 指针是否非 nil
-		// 可选：在此处验证解引用后的值是否有效 (0, 1, 2)
-		genderValue := *dto.Gender
-		if genderValue != enums.Unknown && genderValue != enums.Male && genderValue != enums.Female {
-			s.logger.Warn("无效的性别值", zap.Any("gender", genderValue), zap.String("userID", userID))
-			return nil, errors.New("无效的性别值") // 或者忽略无效值？取决于业务需求
-		}
-		if profileEntity.Gender != genderValue {
-			profileEntity.Gender = genderValue // 解引用指针获取值并更新
-			updated = true
-		}
-	}
-	if dto.Province != nil && profileEntity.Province != *dto.Province {
-		// 检查 Province 指针是否非 nil，并且值与当前实体中的值不同
-		profileEntity.Province = *dto.Province
-		updated = true
-	}
-	if dto.City != nil && profileEntity.City != *dto.City {
-		// 检查 City 指针是否非 nil，并且值与当前实体中的值不同
-		profileEntity.City = *dto.City
-		updated = true
+	previousAvatarURL := profileEntity.AvatarURL
+	changedFields, err := s.applyProfileUpdates(userID, profileEntity, dto)
+	if err != nil {
+		return nil, false, err
 	}
 
 	// 如果没有任何字段需要更新，可以直接返回当前实体对应的 VO
-	if !updated {
+	if len(changedFields) == 0 {
 		s.logger.Info("用户资料无需更新，未提供有效修改或值与现有数据相同",
 			zap.String("operation", operation),
 			zap.String("userID", userID),
 		)
 		// 注意：即使没有更新，返回的 VO 中的 UpdatedAt 也是从数据库读出来的旧时间
-		return profileEntityToVO(profileEntity), nil
+		return profileEntityToVO(profileEntity), false, nil
+	}
+
+	// 修改敏感字段需要近期重新验证过身份；冷却时间内不允许再次修改 (没有实际变化的请求不受限制)
+	if err := s.checkReverification(ctx, userID, changedFields); err != nil {
+		return nil, false, err
+	}
+	if err := s.checkCooldown(ctx, userID, redis.ProfileCooldownFields, s.updateCfg.CooldownOrDisabled()); err != nil {
+		return nil, false, err
 	}
 
 	// 3. 调用仓库层更新资料
@@ -182,7 +267,10 @@ func (s *userProfileService) UpdateProfile(ctx context.Context, userID string, d
 			zap.String("userID", userID),
 			zap.Error(err),
 		)
-		return nil, commonerrors.ErrSystemError
+		return nil, false, commonerrors.ErrSystemError
+	}
+	if profileEntity.AvatarURL != previousAvatarURL {
+		s.releaseReplacedAvatar(ctx, userID, previousAvatarURL)
 	}
 
 	// 4. 重新从数据库获取更新后的记录 (可选但推荐，确保返回最新数据，特别是 UpdatedAt)
@@ -196,32 +284,355 @@ func (s *userProfileService) UpdateProfile(ctx context.Context, userID string, d
 			zap.Error(err),
 		)
 		// 即使更新成功了，但无法返回最新数据，也应该报告错误
-		return nil, commonerrors.ErrSystemError
+		return nil, false, commonerrors.ErrSystemError
 	}
 
+	s.startCooldown(ctx, userID, redis.ProfileCooldownFields, s.updateCfg.CooldownOrDisabled())
+
 	s.logger.Info("成功更新用户资料",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
 	)
 
 	// 5. 转换并返回更新后的 VO
-	return profileEntityToVO(updatedProfileEntity), nil
+	return profileEntityToVO(updatedProfileEntity), true, nil
+}
+
+// applyProfileUpdates 将 DTO 中非 nil 的字段应用到资料实体上（只修改内存中的实体，不写库）。
+// 返回发生了实际变化的字段 (JSON 字段名)；字段值无效时返回业务错误。
+func (s *userProfileService) applyProfileUpdates(userID string, profileEntity *entities.UserProfile, dto *dto.UpdateProfileDTO) ([]string, error) {
+	var changed []string // 记录被实际更新的字段
+
+	if dto.Nickname != nil && profileEntity.Nickname != *dto.Nickname {
+		// 检查 Nickname 指针是否非 nil，并且值与当前实体中的值不同
+		profileEntity.Nickname = *dto.Nickname // 解引用指针获取值并更新
+		changed = append(changed, "nickname")
+	}
+	if dto.Gender != nil {
+		// 检查 Gender 指针是否非 nil
+		// 可选：在此处验证解引用后的值是否有效 (0, 1, 2)
+		genderValue := *dto.Gender
+		if genderValue != enums.Unknown && genderValue != enums.Male && genderValue != enums.Female {
+			s.logger.Warn("无效的性别值", zap.Any("gender", genderValue), zap.String("userID", userID))
+			return nil, errors.New("无效的性别值") // 或者忽略无效值？取决于业务需求
+		}
+		if profileEntity.Gender != genderValue {
+			profileEntity.Gender = genderValue // 解引用指针获取值并更新
+			changed = append(changed, "gender")
+		}
+	}
+	if dto.Province != nil && profileEntity.Province != *dto.Province {
+		// 检查 Province 指针是否非 nil，并且值与当前实体中的值不同
+		profileEntity.Province = *dto.Province
+		changed = append(changed, "province")
+	}
+	if dto.City != nil && profileEntity.City != *dto.City {
+		// 检查 City 指针是否非 nil，并且值与当前实体中的值不同
+		profileEntity.City = *dto.City
+		changed = append(changed, "city")
+	}
+	// 省份或城市发生变化时，校验两者组合后的结果（只改其中一项时与现有值组合校验）
+	if (dto.Province != nil || dto.City != nil) && len(changed) > 0 {
+		if err := s.checkRegion(userID, profileEntity.Province, profileEntity.City); err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
 }
 
-// UploadAndSetAvatar 方法修改：直接更新实体并保存
+// checkRegion 按配置的模式校验省市是否匹配：warn 模式只记录日志，reject 模式返回业务错误。
+func (s *userProfileService) checkRegion(userID string, province string, city string) error {
+	const operation = "UserProfileService.checkRegion"
+	if !s.regionCfg.Enabled() {
+		return nil
+	}
+	err := utils.ValidateRegionPair(s.regions, province, city)
+	if err == nil {
+		return nil
+	}
+	if s.regionCfg.Mode != config.RegionCheckReject {
+		s.logger.Warn("用户资料省市不匹配，按 warn 模式放行", zap.String("operation", operation), zap.String("userID", userID),
+			zap.String("province", province), zap.String("city", city), zap.Error(err))
+		return nil
+	}
+	s.logger.Warn("用户资料省市不匹配，拒绝更新", zap.String("operation", operation), zap.String("userID", userID),
+		zap.String("province", province), zap.String("city", city), zap.Error(err))
+	if errors.Is(err, utils.ErrRegionUnknownProvince) {
+		return fmt.Errorf("无法识别的省份: %s", province)
+	}
+	return fmt.Errorf("城市 %s 不属于 %s", city, province)
+}
+
+// UploadAndSetAvatar 实现接口方法：读取上传文件后交由统一的头像处理流程
 func (s *userProfileService) UploadAndSetAvatar(ctx context.Context, userID string, fileName string, fileReader io.Reader, fileSize int64) (string, error) {
 	const operation = "UserProfileService.UploadAndSetAvatar"
 	s.logger.Info("开始上传并设置用户头像", zap.String("operation", operation), zap.String("userID", userID), zap.String("fileName", fileName), zap.Int64("fileSize", fileSize))
 
-	// 1. 上传头像到 COS
-	avatarURL, err := s.cosClient.UploadUserAvatar(ctx, userID, fileName, fileReader, fileSize)
+	data, err := s.readAvatarFile(userID, fileReader)
 	if err != nil {
-		s.logger.Error("上传头像到腾讯云 COS 失败", zap.String("operation", operation), zap.String("userID", userID), zap.String("fileName", fileName), zap.Error(err))
-		return "", fmt.Errorf("上传头像到腾讯云 COS 服务失败: %w", commonerrors.ErrThirdPartyServiceError)
+		return "", err
 	}
-	s.logger.Info("头像成功上传到 COS", zap.String("operation", operation), zap.String("userID", userID), zap.String("avatarURL", avatarURL))
+	return s.processAndSetAvatar(ctx, userID, fileName, data)
+}
 
-	// 2. 获取当前用户资料实体
+// readAvatarFile 读取文件内容到内存，后续的尺寸校验与元数据处理都基于完整数据；多读 1 字节用于判断是否超限
+func (s *userProfileService) readAvatarFile(userID string, fileReader io.Reader) ([]byte, error) {
+	const operation = "UserProfileService.readAvatarFile"
+	maxSize := s.avatarCfg.MaxFileSizeBytes()
+	data, err := io.ReadAll(io.LimitReader(fileReader, maxSize+1))
+	if err != nil {
+		s.logger.Error("读取头像文件内容失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("文件大小不能超过 %dMB", maxSize/1024/1024)
+	}
+	return data, nil
+}
+
+// UpdateProfileWithAvatar 实现接口方法：先在内存中应用资料字段，再上传头像，最后一次性写库
+func (s *userProfileService) UpdateProfileWithAvatar(ctx context.Context, userID string, dto *dto.UpdateProfileDTO, fileName string, fileReader io.Reader, fileSize int64) (*vo.ProfileVO, error) {
+	const operation = "UserProfileService.UpdateProfileWithAvatar"
+	s.logger.Info("开始更新用户资料及头像", zap.String("operation", operation), zap.String("userID", userID), zap.Bool("hasAvatar", fileReader != nil), zap.Int64("fileSize", fileSize))
+
+	// 更换头像时与其他头像上传互斥，锁覆盖“读取资料 -> 上传 -> 写库”的全过程
+	if fileReader != nil {
+		release, err := s.lockAvatarUpload(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	// 1. 查询资料并应用字段修改；字段校验失败时直接返回，避免产生无用的 COS 对象
+	profileEntity, err := s.repo.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("尝试更新不存在的用户资料", zap.String("operation", operation), zap.String("userID", userID))
+			return nil, errors.New("要更新的用户资料不存在")
+		}
+		s.logger.Error("更新用户资料前查询失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	previousAvatarURL := profileEntity.AvatarURL
+	changedFields, err := s.applyProfileUpdates(userID, profileEntity, dto)
+	if err != nil {
+		return nil, err
+	}
+	fieldsUpdated := len(changedFields) > 0
+	updated := fieldsUpdated
+
+	// 1.1 敏感字段检查近期重新验证；资料字段与头像分别检查冷却时间，均在上传前拒绝以免产生无用的 COS 对象
+	if fieldsUpdated {
+		if err := s.checkReverification(ctx, userID, changedFields); err != nil {
+			return nil, err
+		}
+		if err := s.checkCooldown(ctx, userID, redis.ProfileCooldownFields, s.updateCfg.CooldownOrDisabled()); err != nil {
+			return nil, err
+		}
+	}
+	if fileReader != nil {
+		if err := s.checkCooldown(ctx, userID, redis.ProfileCooldownAvatar, s.updateCfg.AvatarCooldownOrDisabled()); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. 如提供了头像文件，读取、校验并上传
+	var avatarURL string
+	if fileReader != nil {
+		data, err := s.readAvatarFile(userID, fileReader)
+		if err != nil {
+			return nil, err
+		}
+		avatarURL, err = s.uploadAvatarData(ctx, userID, fileName, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// URL 未变说明使用了固定对象键 (latest 命名模式)，旧对象已被覆盖，只需刷新 CDN，失败时也不能删除该对象
+	avatarOverwritten := avatarURL != "" && avatarURL == profileEntity.AvatarURL
+	if avatarURL != "" && !avatarOverwritten {
+		profileEntity.AvatarURL = avatarURL
+		updated = true
+	}
+
+	// 3. 写库；失败时清理本次新上传的对象，避免遗留孤立文件
+	if updated {
+		if err := s.repo.UpdateProfile(ctx, profileEntity); err != nil {
+			s.logger.Error("更新用户资料及头像失败（仓库层）", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			if avatarURL != "" && !avatarOverwritten {
+				s.discardUploadedAvatar(ctx, userID, avatarURL)
+			}
+			return nil, commonerrors.ErrSystemError
+		}
+	}
+	if profileEntity.AvatarURL != previousAvatarURL {
+		s.releaseReplacedAvatar(ctx, userID, previousAvatarURL)
+	}
+	if avatarOverwritten {
+		s.purgeAvatarCache(ctx, userID, avatarURL)
+	}
+	if fieldsUpdated {
+		s.startCooldown(ctx, userID, redis.ProfileCooldownFields, s.updateCfg.CooldownOrDisabled())
+	}
+	if avatarURL != "" {
+		s.startCooldown(ctx, userID, redis.ProfileCooldownAvatar, s.updateCfg.AvatarCooldownOrDisabled())
+	}
+
+	// 4. 重新读取以返回数据库中的最新数据
+	updatedProfileEntity, err := s.repo.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("更新用户资料后重新获取记录失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("成功更新用户资料及头像", zap.String("operation", operation), zap.String("userID", userID), zap.String("avatarURL", avatarURL))
+	return profileEntityToVO(updatedProfileEntity), nil
+}
+
+// discardUploadedAvatar 尽力删除已上传但未能写入资料的头像对象，失败只记录日志。
+func (s *userProfileService) discardUploadedAvatar(ctx context.Context, userID string, avatarURL string) {
+	const operation = "UserProfileService.discardUploadedAvatar"
+	objectKey, ok := s.cosClient.ObjectKeyFromURL(avatarURL)
+	if !ok {
+		s.logger.Warn("无法从头像URL解析对象键，跳过清理", zap.String("operation", operation), zap.String("userID", userID), zap.String("avatarURL", avatarURL))
+		return
+	}
+	if err := s.cosClient.DeleteObject(ctx, objectKey); err != nil {
+		s.logger.Error("清理未使用的头像对象失败，需人工处理", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey), zap.Error(err))
+		return
+	}
+	// 对象已删除，同步移除其用量记录 (失败已在文件服务中记录日志)
+	_ = s.fileService.ReleaseObject(ctx, objectKey)
+	s.logger.Info("已清理未使用的头像对象", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey))
+}
+
+// releaseReplacedAvatar 资料已改用新头像后，尽力删除旧头像对象并移除其用量记录，失败只记录日志。
+// - 旧头像不属于当前存储桶 (如微信头像链接)，或对象未记录为该用户上传 (如手动填写的他人头像地址) 时跳过。
+// - 调用方需保证旧地址与新地址不同；latest 命名模式下对象键不变，旧对象已被覆盖，不能删除。
+func (s *userProfileService) releaseReplacedAvatar(ctx context.Context, userID string, previousURL string) {
+	const operation = "UserProfileService.releaseReplacedAvatar"
+	if previousURL == "" {
+		return
+	}
+	objectKey, ok := s.cosClient.ObjectKeyFromURL(previousURL)
+	if !ok {
+		return
+	}
+	// 资料已写库，清理不应随请求取消而中断
+	ctx = context.WithoutCancel(ctx)
+	owned, err := s.fileService.OwnsObject(ctx, userID, objectKey)
+	if err != nil {
+		return
+	}
+	if !owned {
+		s.logger.Info("旧头像对象未记录为该用户上传，跳过清理", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey))
+		return
+	}
+	if err := s.cosClient.DeleteObject(ctx, objectKey); err != nil {
+		s.logger.Error("删除旧头像对象失败，需人工处理", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey), zap.Error(err))
+		return
+	}
+	// 对象已删除，同步移除其用量记录 (失败已在文件服务中记录日志)
+	_ = s.fileService.ReleaseObject(ctx, objectKey)
+	s.logger.Info("已删除被替换的旧头像对象", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey))
+}
+
+// UploadAvatarFromURL 实现接口方法：拉取远程图片后交由统一的头像处理流程
+func (s *userProfileService) UploadAvatarFromURL(ctx context.Context, userID string, imageURL string) (string, error) {
+	const operation = "UserProfileService.UploadAvatarFromURL"
+	s.logger.Info("开始从远程地址拉取头像", zap.String("operation", operation), zap.String("userID", userID), zap.String("imageURL", imageURL))
+
+	data, err := utils.FetchRemoteImage(ctx, s.fetchClient, imageURL, s.avatarCfg.MaxFileSizeBytes())
+	if err != nil {
+		s.logger.Warn("拉取远程头像失败", zap.String("operation", operation), zap.String("userID", userID), zap.String("imageURL", imageURL), zap.Error(err))
+		switch {
+		case errors.Is(err, utils.ErrRemoteURLInvalid):
+			return "", errors.New("头像链接无效，仅支持 http/https 地址")
+		case errors.Is(err, utils.ErrRemoteAddrBlocked):
+			return "", errors.New("不允许从内网或保留地址拉取头像")
+		case errors.Is(err, utils.ErrRemoteNotImage):
+			return "", errors.New("头像链接指向的内容不是图片")
+		case errors.Is(err, utils.ErrRemoteTooLarge):
+			return "", fmt.Errorf("文件大小不能超过 %dMB", s.avatarCfg.MaxFileSizeBytes()/1024/1024)
+		default:
+			return "", errors.New("无法下载头像链接指向的图片，请检查链接是否可访问")
+		}
+	}
+	return s.processAndSetAvatar(ctx, userID, "", data)
+}
+
+// avatarDataURIFormats data URI 中允许的 MIME 类型及其对应的图片格式名 (与 image.DecodeConfig 返回值一致)
+var avatarDataURIFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// UploadAvatarFromBase64 实现接口方法：解码 data URI 后交由统一的头像处理流程
+func (s *userProfileService) UploadAvatarFromBase64(ctx context.Context, userID string, dataURI string) (string, error) {
+	const operation = "UserProfileService.UploadAvatarFromBase64"
+	s.logger.Info("开始处理 base64 头像", zap.String("operation", operation), zap.String("userID", userID), zap.Int("encodedLength", len(dataURI)))
+
+	// 1. 解析 "data:<mime>;base64,<payload>"
+	header, payload, found := strings.Cut(strings.TrimSpace(dataURI), ",")
+	mimeType, isBase64 := strings.CutSuffix(strings.TrimPrefix(header, "data:"), ";base64")
+	if !found || !strings.HasPrefix(header, "data:") || !isBase64 {
+		return "", errors.New("头像数据格式无效，应为 data:image/<类型>;base64,<数据>")
+	}
+	mimeType = strings.ToLower(mimeType)
+	expectedFormat, ok := avatarDataURIFormats[mimeType]
+	if !ok {
+		return "", errors.New("不支持的头像图片类型，仅支持 image/jpeg、image/png、image/gif")
+	}
+
+	// 2. 解码前先按编码长度估算大小，避免为超大数据分配内存
+	maxSize := s.avatarCfg.MaxFileSizeBytes()
+	if int64(base64.StdEncoding.DecodedLen(len(payload))) > maxSize+2 { // DecodedLen 按无填充估算，最多多出 2 字节
+		return "", fmt.Errorf("文件大小不能超过 %dMB", maxSize/1024/1024)
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		s.logger.Warn("base64 头像解码失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return "", errors.New("头像数据不是有效的 base64 编码")
+	}
+	if int64(len(data)) > maxSize {
+		return "", fmt.Errorf("文件大小不能超过 %dMB", maxSize/1024/1024)
+	}
+
+	// 3. 声明的 MIME 类型必须与实际内容一致，防止伪装
+	if _, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil && format != expectedFormat {
+		return "", fmt.Errorf("头像数据声明的类型 (%s) 与实际内容 (%s) 不符", mimeType, format)
+	}
+
+	return s.processAndSetAvatar(ctx, userID, "", data)
+}
+
+// processAndSetAvatar 头像处理的统一流程：格式/尺寸校验 -> 去除元数据 -> 上传 COS -> 更新资料。
+// 各上传入口（文件、远程 URL 等）在读取数据后都应调用此方法，保证校验规则一致。
+// fileName 仅用于确定扩展名，为空或无扩展名时按识别出的图片格式补全。
+func (s *userProfileService) processAndSetAvatar(ctx context.Context, userID string, fileName string, data []byte) (string, error) {
+	const operation = "UserProfileService.processAndSetAvatar"
+
+	// 0. 同一用户的头像上传串行执行，避免并发上传在资料写库时互相覆盖并遗留孤立对象
+	release, err := s.lockAvatarUpload(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	// 0.1 冷却时间内不允许再次更换头像
+	if err := s.checkCooldown(ctx, userID, redis.ProfileCooldownAvatar, s.updateCfg.AvatarCooldownOrDisabled()); err != nil {
+		return "", err
+	}
+
+	// 1-3. 校验、去除元数据并上传
+	avatarURL, err := s.uploadAvatarData(ctx, userID, fileName, data)
+	if err != nil {
+		return "", err
+	}
+
+	// 4. 获取当前用户资料实体
 	profileEntity, err := s.repo.GetProfileByUserID(ctx, userID)
 	if err != nil {
 		// 如果用户资料不存在，这可能是一个错误，因为理论上用户注册时应已创建。
@@ -235,32 +646,229 @@ func (s *userProfileService) UploadAndSetAvatar(ctx context.Context, userID stri
 		return "", commonerrors.ErrSystemError
 	}
 
-	// 3.直接修改实体中的 AvatarURL
+	// 5. 直接修改实体中的 AvatarURL
+	//    URL 未变说明使用了固定对象键 (latest 命名模式)，新图已覆盖旧对象，需要刷新 CDN 缓存
 	if profileEntity.AvatarURL == avatarURL {
 		s.logger.Info("新的头像URL与现有URL相同，无需更新数据库", zap.String("operation", operation), zap.String("userID", userID), zap.String("avatarURL", avatarURL))
+		s.purgeAvatarCache(ctx, userID, avatarURL)
+		s.startCooldown(ctx, userID, redis.ProfileCooldownAvatar, s.updateCfg.AvatarCooldownOrDisabled())
 		return avatarURL, nil // 如果URL未变，则无需更新数据库
 	}
+	previousAvatarURL := profileEntity.AvatarURL
 	profileEntity.AvatarURL = avatarURL
 
-	// 4. 调用仓库层更新（保存）整个实体
+	// 6. 调用仓库层更新（保存）整个实体
 	// 注意：s.repo.UpdateProfile 接收的是整个实体，它的内部实现是 GORM 的 Save，它会更新所有字段。
 	// 如果是 Updates，它会更新有变化的字段。
 	// 通常，对于部分更新，先获取实体，修改字段，然后 Save 是常见做法。
 	if err := s.repo.UpdateProfile(ctx, profileEntity); err != nil {
 		s.logger.Error("更新用户资料中的头像URL失败（仓库层）", zap.String("operation", operation), zap.String("userID", userID), zap.String("newAvatarURL", avatarURL), zap.Error(err))
-		// 错误处理策略：
-		// 此时图片已上传到 COS，但数据库更新失败。
-		// - 选项1: 尝试删除已上传的 COS 对象（会增加代码复杂性，需要 s.cosClient.DeleteObject）。
-		// - 选项2: 返回错误，让用户重试。下次上传可能会覆盖或创建新对象，取决于 UploadUserAvatar 的对象键生成逻辑。
-		// - 选项3: 记录严重错误，可能需要人工介入。
-		// 当前选择选项2，简单返回错误。
+		// 此时图片已上传到 COS，但数据库更新失败：尽力删除新对象，避免遗留孤立文件，然后让用户重试。
+		s.discardUploadedAvatar(ctx, userID, avatarURL)
 		return "", commonerrors.ErrSystemError
 	}
+	s.releaseReplacedAvatar(ctx, userID, previousAvatarURL)
 
 	s.logger.Info("成功更新用户资料中的头像URL", zap.String("operation", operation), zap.String("userID", userID), zap.String("newAvatarURL", avatarURL))
 	return avatarURL, nil
 }
 
+// lockAvatarUpload 获取指定用户的头像上传锁，返回用于释放锁的函数。
+// - 锁已被占用时返回 ErrAvatarUploadInProgress。
+// - 配置关闭或 Redis 不可用时不加锁直接放行 (只记录日志)，避免锁服务故障导致头像功能整体不可用。
+func (s *userProfileService) lockAvatarUpload(ctx context.Context, userID string) (func(), error) {
+	const operation = "UserProfileService.lockAvatarUpload"
+	if !s.avatarCfg.UploadLockEnabled() {
+		return func() {}, nil
+	}
+
+	token, acquired, err := s.avatarLock.Acquire(ctx, userID, s.avatarCfg.UploadLockTTLOrDefault())
+	if err != nil {
+		s.logger.Warn("获取头像上传锁失败，本次上传不加锁", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return func() {}, nil
+	}
+	if !acquired {
+		s.logger.Warn("同一用户已有头像上传正在处理", zap.String("operation", operation), zap.String("userID", userID))
+		return nil, ErrAvatarUploadInProgress
+	}
+
+	return func() {
+		// 使用独立的上下文释放锁，避免请求上下文取消后锁残留到过期
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), avatarLockReleaseTimeout)
+		defer cancel()
+		if err := s.avatarLock.Release(releaseCtx, userID, token); err != nil {
+			s.logger.Warn("释放头像上传锁失败，锁将在过期后自动释放", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		}
+	}, nil
+}
+
+// checkReverification 变更的字段中包含敏感字段时，要求用户近期重新验证过身份，否则返回 ErrReverificationRequired。
+// - 未配置敏感字段时直接放行；Redis 不可用时无法确认验证状态，按安全优先返回系统错误。
+func (s *userProfileService) checkReverification(ctx context.Context, userID string, changedFields []string) error {
+	const operation = "UserProfileService.checkReverification"
+	var sensitive []string
+	for _, field := range changedFields {
+		if s.updateCfg.IsSensitiveField(field) {
+			sensitive = append(sensitive, field)
+		}
+	}
+	if len(sensitive) == 0 {
+		return nil
+	}
+	recent, err := s.recentAuth.IsRecent(ctx, userID)
+	if err != nil {
+		s.logger.Error("查询重新验证标记失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	if !recent {
+		s.logger.Warn("修改敏感资料字段前未重新验证身份", zap.String("operation", operation), zap.String("userID", userID), zap.Strings("fields", sensitive))
+		return ErrReverificationRequired
+	}
+	return nil
+}
+
+// checkCooldown 检查指定类别的修改是否处于冷却中，处于冷却中时返回 *ProfileCooldownError。
+// - cooldown 为 0 (未启用) 时直接放行；Redis 不可用时记录日志并放行 (RedisFeatureProfileCooldown 始终放行)，避免限流故障导致资料无法修改。
+func (s *userProfileService) checkCooldown(ctx context.Context, userID string, kind string, cooldown time.Duration) error {
+	const operation = "UserProfileService.checkCooldown"
+	if cooldown <= 0 {
+		return nil
+	}
+	remaining, err := s.cooldown.Remaining(ctx, userID, kind)
+	if err != nil {
+		if s.degrade.AllowOnFailure(dependencies.RedisFeatureProfileCooldown, err) {
+			return nil
+		}
+		s.logger.Error("查询资料修改冷却时间失败", zap.String("operation", operation), zap.String("userID", userID), zap.String("kind", kind), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	if remaining > 0 {
+		s.logger.Info("资料修改过于频繁", zap.String("operation", operation), zap.String("userID", userID), zap.String("kind", kind), zap.Duration("retryAfter", remaining))
+		return &ProfileCooldownError{RetryAfter: remaining}
+	}
+	return nil
+}
+
+// startCooldown 在修改成功后开始冷却计时，失败只记录日志。
+func (s *userProfileService) startCooldown(ctx context.Context, userID string, kind string, cooldown time.Duration) {
+	if cooldown <= 0 {
+		return
+	}
+	if err := s.cooldown.Start(context.WithoutCancel(ctx), userID, kind, cooldown); err != nil {
+		s.logger.Warn("写入资料修改冷却时间失败", zap.String("operation", "UserProfileService.startCooldown"), zap.String("userID", userID), zap.String("kind", kind), zap.Error(err))
+	}
+}
+
+// uploadAvatarData 头像上传前的统一处理：格式/尺寸校验 -> 去除元数据 -> 配额检查 -> 上传 COS 并计入用量，返回头像公开 URL。
+// fileName 仅用于确定扩展名，为空或无扩展名时按识别出的图片格式补全。
+func (s *userProfileService) uploadAvatarData(ctx context.Context, userID string, fileName string, data []byte) (string, error) {
+	const operation = "UserProfileService.uploadAvatarData"
+
+	// 1. 校验图片格式与尺寸
+	format, err := s.validateAvatarImage(data)
+	if err != nil {
+		s.logger.Warn("头像图片校验未通过", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return "", err
+	}
+	if filepath.Ext(fileName) == "" {
+		fileName = "avatar." + format
+	}
+
+	// 2. 按配置去除图片元数据 (EXIF/GPS)
+	if s.avatarCfg.StripMetadata {
+		stripped, _, processed, err := utils.StripImageMetadata(data, s.avatarCfg.JPEGQuality)
+		if err != nil {
+			s.logger.Warn("头像图片解码失败，拒绝上传", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			return "", errors.New("头像图片已损坏或格式无效")
+		}
+		if processed {
+			s.logger.Info("已去除头像图片元数据", zap.String("operation", operation), zap.String("userID", userID),
+				zap.String("format", format), zap.Int("originalSize", len(data)), zap.Int("strippedSize", len(stripped)))
+			data = stripped
+		} else {
+			s.logger.Info("头像图片格式不支持元数据去除，按原样上传", zap.String("operation", operation), zap.String("userID", userID), zap.String("format", format))
+		}
+	}
+
+	// 3. 检查存储配额 (按处理后的实际大小)
+	if err := s.fileService.CheckQuota(ctx, userID, int64(len(data))); err != nil {
+		return "", err
+	}
+
+	// 4. 上传头像到 COS
+	avatarURL, err := s.cosClient.UploadUserAvatar(ctx, userID, fileName, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		s.logger.Error("上传头像到腾讯云 COS 失败", zap.String("operation", operation), zap.String("userID", userID), zap.String("fileName", fileName), zap.Error(err))
+		return "", fmt.Errorf("上传头像到腾讯云 COS 服务失败: %w: %w", commonerrors.ErrThirdPartyServiceError, dependencies.ClassifyUpstreamError(dependencies.UpstreamServiceCOS, err))
+	}
+
+	// 5. 计入存储用量；记录失败不影响本次上传，只会使用量暂时偏低
+	if objectKey, ok := s.cosClient.ObjectKeyFromURL(avatarURL); ok {
+		_ = s.fileService.RecordUpload(ctx, userID, objectKey, int64(len(data)))
+	} else {
+		s.logger.Warn("无法从头像URL解析对象键，未计入存储用量", zap.String("operation", operation), zap.String("userID", userID), zap.String("avatarURL", avatarURL))
+	}
+	s.logger.Info("头像成功上传到 COS", zap.String("operation", operation), zap.String("userID", userID), zap.String("avatarURL", avatarURL))
+	return avatarURL, nil
+}
+
+// validateAvatarImage 仅解析图片头部获取格式与尺寸 (image.DecodeConfig)，不做完整解码，
+// 拒绝无法识别的格式以及超出配置范围的尺寸，成功时返回识别出的格式名 (jpeg/png/gif)。
+// 返回的错误均为面向用户的业务错误。
+func (s *userProfileService) validateAvatarImage(data []byte) (string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", errors.New("无法识别的图片格式，仅支持 JPEG、PNG、GIF")
+	}
+	minW, minH, maxW, maxH := s.avatarCfg.DimensionBounds()
+	if cfg.Width < minW || cfg.Height < minH {
+		return "", fmt.Errorf("头像尺寸过小 (%dx%d)，最小为 %dx%d", cfg.Width, cfg.Height, minW, minH)
+	}
+	if cfg.Width > maxW || cfg.Height > maxH {
+		return "", fmt.Errorf("头像尺寸过大 (%dx%d)，最大为 %dx%d", cfg.Width, cfg.Height, maxW, maxH)
+	}
+	return format, nil
+}
+
+// purgeAvatarCache 尽力刷新头像 URL 的 CDN 缓存，失败只记录日志，不影响头像更新结果。
+func (s *userProfileService) purgeAvatarCache(ctx context.Context, userID string, avatarURL string) {
+	const operation = "UserProfileService.purgeAvatarCache"
+	if err := s.cdnClient.PurgeURLs(ctx, []string{avatarURL}); err != nil {
+		s.logger.Warn("刷新头像 CDN 缓存失败，客户端可能短时间内仍看到旧头像",
+			zap.String("operation", operation),
+			zap.String("userID", userID),
+			zap.String("avatarURL", avatarURL),
+			zap.Error(err),
+		)
+		return
+	}
+	s.logger.Info("已提交头像 CDN 缓存刷新", zap.String("operation", operation), zap.String("userID", userID), zap.String("avatarURL", avatarURL))
+}
+
+// DeleteProfile 实现接口方法，删除指定用户的资料。
+func (s *userProfileService) DeleteProfile(ctx context.Context, userID string) error {
+	const operation = "UserProfileService.DeleteProfile"
+
+	// 1. 先确认资料存在，仓库层的删除对不存在的记录是幂等的，无法区分 404
+	if _, err := s.repo.GetProfileByUserID(ctx, userID); err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("尝试删除不存在的用户资料", zap.String("operation", operation), zap.String("userID", userID))
+			return errors.New("要删除的用户资料不存在")
+		}
+		s.logger.Error("删除前查询用户资料失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+
+	// 2. 执行删除
+	if err := s.repo.DeleteProfile(ctx, s.db, userID); err != nil {
+		s.logger.Error("删除用户资料失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("用户资料删除成功", zap.String("operation", operation), zap.String("userID", userID))
+	return nil
+}
+
 // GetMyAccountDetail 实现接口方法，获取当前用户的聚合账户详情。
 func (s *userProfileService) GetMyAccountDetail(ctx context.Context, userID string) (*vo.MyAccountDetailVO, error) {
 	const operation = "UserProfileService.GetMyAccountDetail"
@@ -0,0 +1,125 @@
+package profile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/repository/redis"
+)
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// fakeProfileRepo 内嵌接口，以单条内存记录实现资料的查询与更新
+type fakeProfileRepo struct {
+	mysql.ProfileRepository
+	profile *entities.UserProfile
+	updates int
+}
+
+func (r *fakeProfileRepo) GetProfileByUserID(_ context.Context, _ string) (*entities.UserProfile, error) {
+	p := *r.profile
+	return &p, nil
+}
+
+func (r *fakeProfileRepo) UpdateProfile(_ context.Context, profile *entities.UserProfile) error {
+	r.updates++
+	p := *profile
+	r.profile = &p
+	return nil
+}
+
+// fakeCooldown 内嵌接口，记录冷却时间的查询与写入次数；始终不在冷却中
+type fakeCooldown struct {
+	redis.ProfileCooldownRepo
+	checks int
+	starts int
+}
+
+func (c *fakeCooldown) Remaining(_ context.Context, _ string, _ string) (time.Duration, error) {
+	c.checks++
+	return 0, nil
+}
+
+func (c *fakeCooldown) Start(_ context.Context, _ string, _ string, _ time.Duration) error {
+	c.starts++
+	return nil
+}
+
+// fakeRecentAuth 内嵌接口，记录重新验证标记的查询次数；始终视为近期已验证
+type fakeRecentAuth struct {
+	redis.RecentAuthRepo
+	checks int
+}
+
+func (a *fakeRecentAuth) IsRecent(_ context.Context, _ string) (bool, error) {
+	a.checks++
+	return true, nil
+}
+
+func TestUpdateProfileNoOp(t *testing.T) {
+	nickname := func(s string) *string { return &s }
+	gender := func(g enums.Gender) *enums.Gender { return &g }
+
+	tests := []struct {
+		name        string
+		dto         dto.UpdateProfileDTO
+		wantChanged bool
+		wantNick    string
+	}{
+		{name: "未提供任何字段", dto: dto.UpdateProfileDTO{}, wantNick: "Alice"},
+		{name: "字段值与现有数据相同", dto: dto.UpdateProfileDTO{Nickname: nickname("Alice"), Gender: gender(enums.Female), City: nickname("深圳")}, wantNick: "Alice"},
+		{name: "昵称发生变化", dto: dto.UpdateProfileDTO{Nickname: nickname("Bob")}, wantChanged: true, wantNick: "Bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeProfileRepo{profile: &entities.UserProfile{UserID: "u1", Nickname: "Alice", Gender: enums.Female, City: "深圳"}}
+			cooldown := &fakeCooldown{}
+			recentAuth := &fakeRecentAuth{}
+			svc := &userProfileService{
+				repo:       repo,
+				cooldown:   cooldown,
+				recentAuth: recentAuth,
+				updateCfg:  config.ProfileUpdateConfig{Cooldown: time.Minute, SensitiveFields: []string{"nickname"}},
+				logger:     newTestLogger(t),
+			}
+
+			profileVO, changed, err := svc.UpdateProfile(context.Background(), "u1", &tt.dto)
+			if err != nil {
+				t.Fatalf("UpdateProfile 失败: %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v，期望 %v", changed, tt.wantChanged)
+			}
+			if profileVO == nil || profileVO.Nickname != tt.wantNick {
+				t.Errorf("返回的资料 = %+v，期望昵称 %q", profileVO, tt.wantNick)
+			}
+
+			// 无变更时不写库，也不触发冷却、重新验证等副作用
+			wantCalls := 0
+			if tt.wantChanged {
+				wantCalls = 1
+			}
+			if repo.updates != wantCalls || cooldown.checks != wantCalls || cooldown.starts != wantCalls || recentAuth.checks != wantCalls {
+				t.Errorf("写库/冷却查询/冷却写入/重新验证次数 = %d/%d/%d/%d，期望均为 %d",
+					repo.updates, cooldown.checks, cooldown.starts, recentAuth.checks, wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// defaultAvatarUploadTimeout 上传默认头像的超时时间；上传发生在注册事务内，需要尽快结束以免长时间占用事务
+const defaultAvatarUploadTimeout = 5 * time.Second
+
+// generateDefaultAvatar 按配置生成默认头像并上传到 COS，返回头像 URL。
+// 这是注册流程中的尽力而为步骤：任何失败都只记录日志并返回空字符串，不影响开户。
+// - 生成的图片体积很小，不计入用户的存储配额。
+// - 若上传成功后注册事务回滚，该对象会遗留在 COS 中 (对象键包含用户 ID，可按前缀清理)。
+func (s *userProvisioningService) generateDefaultAvatar(ctx context.Context, userID string, nickname string) string {
+	const operation = "UserProvisioningService.generateDefaultAvatar"
+
+	if !s.avatarCfg.DefaultAvatarEnabled() || s.cosClient == nil {
+		return ""
+	}
+
+	size := s.avatarCfg.DefaultSizeOrDefault()
+	var (
+		data []byte
+		err  error
+	)
+	initials := utils.AvatarInitials(nickname)
+	if s.avatarCfg.DefaultStyle == config.DefaultAvatarInitials && initials != "" {
+		data, err = utils.GenerateInitialsAvatar(initials, userID, size)
+	} else {
+		data, err = utils.GenerateIdenticonAvatar(userID, size)
+	}
+	if err != nil {
+		s.logger.Warn("生成默认头像失败，跳过", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return ""
+	}
+
+	uploadCtx, cancel := context.WithTimeout(ctx, defaultAvatarUploadTimeout)
+	defer cancel()
+	avatarURL, err := s.cosClient.UploadUserAvatar(uploadCtx, userID, "avatar.png", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		s.logger.Warn("上传默认头像失败，新用户头像保持为空", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return ""
+	}
+	return avatarURL
+}
@@ -0,0 +1,42 @@
+package provisioning
+
+import (
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	myenums "github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// defaultNickname 按身份类型对应的默认昵称策略生成新用户的初始昵称。
+// - identifier 策略下登录标识符超出长度时截断，截断后仍不满足昵称规则 (如含不可见字符) 时退化为随机昵称。
+// - 微信的标识符是 OpenID，不适合展示，identifier 策略按 empty 处理。
+// - 未知的策略值按 empty 处理，避免配置错误时意外暴露标识符。
+func (s *userProvisioningService) defaultNickname(identity *entities.UserIdentity) string {
+	var strategy string
+	switch identity.IdentityType {
+	case myenums.AccountPassword:
+		strategy = s.nicknameCfg.AccountDefaultOrDefault()
+	case myenums.Phone:
+		strategy = s.nicknameCfg.PhoneDefaultOrDefault()
+	case myenums.WechatMiniProgram:
+		strategy = s.nicknameCfg.WechatDefaultOrDefault()
+		if strategy == config.NicknameDefaultIdentifier {
+			strategy = config.NicknameDefaultEmpty
+		}
+	default:
+		strategy = config.NicknameDefaultEmpty
+	}
+
+	switch strategy {
+	case config.NicknameDefaultIdentifier:
+		nickname := utils.TruncateNickname(identity.Identifier, s.nicknameCfg)
+		if utils.IsValidNickname(nickname, s.nicknameCfg) {
+			return nickname
+		}
+		return utils.RandomNickname(s.nicknameCfg)
+	case config.NicknameDefaultRandom:
+		return utils.RandomNickname(s.nicknameCfg)
+	default:
+		return ""
+	}
+}
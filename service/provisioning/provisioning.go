@@ -0,0 +1,160 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// UserProvisioningService 定义了新用户开户（User + UserIdentity + UserProfile）的服务接口。
+// 设计目的:
+//   - 账号注册、手机号自动注册、微信自动注册都需要在同一事务中创建这三条记录，
+//     将这部分核心逻辑收敛到一处，后续的事件发送、审计等改动只需修改这里。
+//   - 各登录流程保留自己的前置校验与后续处理（令牌签发等），只委托事务内的创建步骤。
+type UserProvisioningService interface {
+	// CreateUserWithIdentity 在事务中创建核心用户、身份凭证和初始资料。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - tx: 调用方开启的事务；为 nil 时由本方法自行开启事务。
+	//  - role/status: 新用户的角色与初始状态。
+	//  - identity: 身份凭证，必须提供 IdentityType 和 Identifier；UserID 为空时自动生成。
+	//  - profile: 初始资料，可为 nil（仅创建包含 UserID 的空资料）；其 UserID 会被覆盖为新用户的 ID。
+	//    未提供昵称时按 NicknameConfig 中该身份类型的默认昵称策略生成 (登录标识符 / 随机友好昵称 / 留空)。
+	//    带有省市时按 RegionConfig 校验一致性，reject 模式下不匹配会返回错误（可用 errors.Is 判断 utils.ErrRegion*）。
+	//    未提供头像且 AvatarConfig 启用了默认头像时，会尽力生成并上传一张默认头像，失败不影响开户。
+	// 返回:
+	//  - *entities.User: 创建成功的核心用户实体。
+	//  - error: 参数无效或任一步骤失败时返回包装后的错误，调用方负责映射为对外错误。
+	CreateUserWithIdentity(ctx context.Context, tx *gorm.DB, role enums.UserRole, status enums.UserStatus, identity *entities.UserIdentity, profile *entities.UserProfile) (*entities.User, error)
+}
+
+// userProvisioningService 是 UserProvisioningService 接口的实现。
+type userProvisioningService struct {
+	userRepo     mysql.UserRepository            // userRepo: 用户数据仓库。
+	identityRepo mysql.IdentityRepository        // identityRepo: 用户身份数据仓库。
+	profileRepo  mysql.ProfileRepository         // profileRepo: 用户资料数据仓库。
+	cosClient    dependencies.COSClientInterface // cosClient: 上传生成的默认头像，可为 nil (不生成)。
+	avatarCfg    config.AvatarConfig             // avatarCfg: 默认头像的生成样式与尺寸。
+	nicknameCfg  config.NicknameConfig           // nicknameCfg: 未提供昵称时的默认昵称策略与长度限制。
+	regionCfg    config.RegionConfig             // regionCfg: 省市一致性校验配置，初始资料带有省市时生效。
+	regions      utils.RegionDataset             // regions: 省市一致性校验使用的行政区划数据集。
+	db           *gorm.DB                        // db: 调用方未传入事务时用于开启事务。
+	logger       *core.ZapLogger                 // logger: 日志记录器。
+}
+
+// NewUserProvisioningService 创建一个新的 userProvisioningService 实例。
+func NewUserProvisioningService(
+	userRepo mysql.UserRepository,
+	identityRepo mysql.IdentityRepository,
+	profileRepo mysql.ProfileRepository,
+	cosClient dependencies.COSClientInterface,
+	avatarCfg config.AvatarConfig,
+	nicknameCfg config.NicknameConfig,
+	regionCfg config.RegionConfig,
+	regions utils.RegionDataset,
+	db *gorm.DB,
+	logger *core.ZapLogger,
+) UserProvisioningService {
+	return &userProvisioningService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		profileRepo:  profileRepo,
+		cosClient:    cosClient,
+		avatarCfg:    avatarCfg,
+		nicknameCfg:  nicknameCfg,
+		regionCfg:    regionCfg,
+		regions:      regions,
+		db:           db,
+		logger:       logger,
+	}
+}
+
+// CreateUserWithIdentity 实现接口方法。
+func (s *userProvisioningService) CreateUserWithIdentity(ctx context.Context, tx *gorm.DB, role enums.UserRole, status enums.UserStatus, identity *entities.UserIdentity, profile *entities.UserProfile) (*entities.User, error) {
+	const operation = "UserProvisioningService.CreateUserWithIdentity"
+
+	if identity == nil || identity.Identifier == "" {
+		return nil, errors.New("provisioning.CreateUserWithIdentity: 身份凭证不能为空")
+	}
+	if tx == nil {
+		var user *entities.User
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			user, err = s.CreateUserWithIdentity(ctx, tx, role, status, identity, profile)
+			return err
+		})
+		return user, err
+	}
+
+	// 1. 统一三条记录的 UserID
+	if identity.UserID == "" {
+		identity.UserID = uuid.New().String()
+	}
+	userID := identity.UserID
+	if profile == nil {
+		profile = &entities.UserProfile{}
+	}
+	profile.UserID = userID
+
+	// 初始资料带有省市时，按配置校验其一致性（与资料更新的规则相同）
+	if s.regionCfg.Enabled() {
+		if err := utils.ValidateRegionPair(s.regions, profile.Province, profile.City); err != nil {
+			if s.regionCfg.Mode == config.RegionCheckReject {
+				return nil, fmt.Errorf("provisioning.CreateUserWithIdentity: 初始资料省市不匹配 (省份: %s, 城市: %s): %w", profile.Province, profile.City, err)
+			}
+			s.logger.Warn("初始资料省市不匹配，按 warn 模式放行",
+				zap.String("operation", operation),
+				zap.String("userID", userID),
+				zap.String("province", profile.Province),
+				zap.String("city", profile.City),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// 未提供昵称时按该注册方式的策略生成默认昵称 (需先于默认头像，首字母头像依赖昵称)
+	if profile.Nickname == "" {
+		profile.Nickname = s.defaultNickname(identity)
+	}
+
+	// 未提供头像时按配置生成默认头像 (尽力而为)
+	if profile.AvatarURL == "" {
+		profile.AvatarURL = s.generateDefaultAvatar(ctx, userID, profile.Nickname)
+	}
+
+	user := &entities.User{
+		UserID:   userID,
+		UserRole: role,
+		Status:   status,
+	}
+
+	// 2. 依次创建用户、身份与初始资料，任一失败都会使调用方的事务回滚
+	if err := s.userRepo.CreateUser(ctx, tx, user); err != nil {
+		return nil, fmt.Errorf("事务中创建用户失败: %w", err)
+	}
+	if err := s.identityRepo.CreateIdentity(ctx, tx, identity); err != nil {
+		return nil, fmt.Errorf("事务中创建身份失败: %w", err)
+	}
+	if err := s.profileRepo.CreateProfile(ctx, tx, profile); err != nil {
+		return nil, fmt.Errorf("事务中创建初始用户资料失败: %w", err)
+	}
+
+	s.logger.Debug("事务中已创建用户、身份与初始资料",
+		zap.String("operation", operation),
+		zap.String("userID", userID),
+		zap.Uint("identityType", uint(identity.IdentityType)),
+	)
+	return user, nil
+}
@@ -0,0 +1,350 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	mysqldriver "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	myenums "github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// newMockGormDB 基于 sqlmock 创建 GORM 连接，仅用于驱动事务的开启/提交/回滚；
+// 数据写入由内存假仓库完成。
+func newMockGormDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建 sqlmock 失败: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := gorm.Open(mysqldriver.New(mysqldriver.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建 GORM 连接失败: %v", err)
+	}
+	return db, mock
+}
+
+// recordingRepos 记录开户三个步骤写入的实体与所用的事务；failAt 指定在哪一步返回错误
+type recordingRepos struct {
+	user     *entities.User
+	identity *entities.UserIdentity
+	profile  *entities.UserProfile
+	txs      []*gorm.DB
+	failAt   string // failAt: "user" / "identity" / "profile"，为空时全部成功
+}
+
+var errRepoFailed = errors.New("repo failed")
+
+func (r *recordingRepos) record(step string, tx *gorm.DB) error {
+	r.txs = append(r.txs, tx)
+	if r.failAt == step {
+		return errRepoFailed
+	}
+	return nil
+}
+
+// fakeUserRepo 内嵌接口，仅实现创建用户
+type fakeUserRepo struct {
+	mysql.UserRepository
+	*recordingRepos
+}
+
+func (r fakeUserRepo) CreateUser(_ context.Context, tx *gorm.DB, user *entities.User) error {
+	r.user = user
+	return r.record("user", tx)
+}
+
+// fakeIdentityRepo 内嵌接口，仅实现创建身份
+type fakeIdentityRepo struct {
+	mysql.IdentityRepository
+	*recordingRepos
+}
+
+func (r fakeIdentityRepo) CreateIdentity(_ context.Context, tx *gorm.DB, identity *entities.UserIdentity) error {
+	r.identity = identity
+	return r.record("identity", tx)
+}
+
+// fakeProfileRepo 内嵌接口，仅实现创建资料
+type fakeProfileRepo struct {
+	mysql.ProfileRepository
+	*recordingRepos
+}
+
+func (r fakeProfileRepo) CreateProfile(_ context.Context, tx *gorm.DB, profile *entities.UserProfile) error {
+	r.profile = profile
+	return r.record("profile", tx)
+}
+
+// fakeCOSClient 内嵌接口，仅实现头像上传；err 不为 nil 时上传失败
+type fakeCOSClient struct {
+	dependencies.COSClientInterface
+	err      error
+	uploaded int
+}
+
+func (c *fakeCOSClient) UploadUserAvatar(_ context.Context, userID string, _ string, reader io.Reader, _ int64) (string, error) {
+	c.uploaded++
+	if _, err := io.ReadAll(reader); err != nil {
+		return "", err
+	}
+	if c.err != nil {
+		return "", c.err
+	}
+	return "https://cdn.example.com/avatars/" + userID + ".png", nil
+}
+
+// testProvisioningOptions 测试中可调整的开户服务配置
+type testProvisioningOptions struct {
+	cos         dependencies.COSClientInterface
+	avatarCfg   config.AvatarConfig
+	nicknameCfg config.NicknameConfig
+	regionCfg   config.RegionConfig
+}
+
+func newTestProvisioningService(t *testing.T, db *gorm.DB, opts testProvisioningOptions) (UserProvisioningService, *recordingRepos) {
+	t.Helper()
+	repos := &recordingRepos{}
+	regions := utils.NewStaticRegionDataset(map[string][]string{"广东省": {"深圳市", "广州市"}})
+	return NewUserProvisioningService(
+		fakeUserRepo{recordingRepos: repos},
+		fakeIdentityRepo{recordingRepos: repos},
+		fakeProfileRepo{recordingRepos: repos},
+		opts.cos, opts.avatarCfg, opts.nicknameCfg, opts.regionCfg, regions, db, newTestLogger(t),
+	), repos
+}
+
+func accountIdentity(identifier string) *entities.UserIdentity {
+	return &entities.UserIdentity{IdentityType: myenums.AccountPassword, Identifier: identifier, Credential: "hashed"}
+}
+
+func TestCreateUserWithIdentityRejectsEmptyIdentity(t *testing.T) {
+	db, mock := newMockGormDB(t)
+	svc, repos := newTestProvisioningService(t, db, testProvisioningOptions{})
+
+	for name, identity := range map[string]*entities.UserIdentity{
+		"身份为 nil": nil,
+		"标识符为空":   {IdentityType: myenums.AccountPassword},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := svc.CreateUserWithIdentity(context.Background(), nil, enums.RoleUser, enums.StatusActive, identity, nil); err == nil {
+				t.Error("期望返回错误")
+			}
+		})
+	}
+	if len(repos.txs) != 0 {
+		t.Errorf("参数无效时不应写入任何记录，实际写入 %d 次", len(repos.txs))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("参数无效时不应开启事务: %v", err)
+	}
+}
+
+func TestCreateUserWithIdentityOpensTransaction(t *testing.T) {
+	db, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	svc, repos := newTestProvisioningService(t, db, testProvisioningOptions{})
+
+	identity := accountIdentity("alice")
+	user, err := svc.CreateUserWithIdentity(context.Background(), nil, enums.RoleUser, enums.StatusActive, identity, nil)
+	if err != nil {
+		t.Fatalf("开户失败: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("未传入事务时应自行开启并提交事务: %v", err)
+	}
+
+	// 三条记录共享新生成的 UserID
+	if user.UserID == "" || identity.UserID != user.UserID || repos.profile.UserID != user.UserID || repos.user != user {
+		t.Errorf("UserID 不一致: user=%q identity=%q profile=%q", user.UserID, identity.UserID, repos.profile.UserID)
+	}
+	if user.UserRole != enums.RoleUser || user.Status != enums.StatusActive {
+		t.Errorf("角色/状态 = %v/%v，期望 RoleUser/StatusActive", user.UserRole, user.Status)
+	}
+	if len(repos.txs) != 3 || repos.txs[0] == nil || repos.txs[0] != repos.txs[1] || repos.txs[1] != repos.txs[2] {
+		t.Errorf("三个步骤应在同一事务中执行: %v", repos.txs)
+	}
+}
+
+func TestCreateUserWithIdentityUsesCallerTransaction(t *testing.T) {
+	db, mock := newMockGormDB(t)
+	svc, repos := newTestProvisioningService(t, db, testProvisioningOptions{})
+
+	identity := accountIdentity("alice")
+	identity.UserID = "existing-id"
+	profile := &entities.UserProfile{UserID: "ignored", Nickname: "Alice"}
+	user, err := svc.CreateUserWithIdentity(context.Background(), db, enums.RoleUser, enums.StatusActive, identity, profile)
+	if err != nil {
+		t.Fatalf("开户失败: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("传入事务时不应再开启事务: %v", err)
+	}
+	for i, tx := range repos.txs {
+		if tx != db {
+			t.Errorf("第 %d 步未使用调用方的事务", i+1)
+		}
+	}
+	if user.UserID != "existing-id" || repos.profile != profile || profile.UserID != "existing-id" {
+		t.Errorf("应沿用身份上的 UserID 并覆盖资料的 UserID: user=%q profile=%q", user.UserID, profile.UserID)
+	}
+	if profile.Nickname != "Alice" {
+		t.Errorf("已提供的昵称不应被替换，实际为 %q", profile.Nickname)
+	}
+}
+
+func TestCreateUserWithIdentityRollsBackOnFailure(t *testing.T) {
+	for _, step := range []string{"user", "identity", "profile"} {
+		t.Run(step, func(t *testing.T) {
+			db, mock := newMockGormDB(t)
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+			svc, repos := newTestProvisioningService(t, db, testProvisioningOptions{})
+			repos.failAt = step
+
+			user, err := svc.CreateUserWithIdentity(context.Background(), nil, enums.RoleUser, enums.StatusActive, accountIdentity("alice"), nil)
+			if !errors.Is(err, errRepoFailed) || user != nil {
+				t.Fatalf("期望返回包装后的仓库错误，实际为 %v, %v", user, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("任一步骤失败应回滚事务: %v", err)
+			}
+			if want := map[string]int{"user": 1, "identity": 2, "profile": 3}[step]; len(repos.txs) != want {
+				t.Errorf("失败后不应继续执行后续步骤，实际执行 %d 步，期望 %d 步", len(repos.txs), want)
+			}
+		})
+	}
+}
+
+func TestCreateUserWithIdentityRegionCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		city    string
+		wantErr error
+	}{
+		{name: "reject 模式拒绝不匹配的省市", mode: config.RegionCheckReject, city: "杭州市", wantErr: utils.ErrRegionCityMismatch},
+		{name: "reject 模式放行匹配的省市", mode: config.RegionCheckReject, city: "深圳市"},
+		{name: "warn 模式放行不匹配的省市", mode: config.RegionCheckWarn, city: "杭州市"},
+		{name: "off 模式不校验", mode: config.RegionCheckOff, city: "杭州市"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newMockGormDB(t)
+			svc, repos := newTestProvisioningService(t, db, testProvisioningOptions{regionCfg: config.RegionConfig{Mode: tt.mode}})
+
+			profile := &entities.UserProfile{Province: "广东省", City: tt.city}
+			_, err := svc.CreateUserWithIdentity(context.Background(), db, enums.RoleUser, enums.StatusActive, accountIdentity("alice"), profile)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("期望错误 %v，实际为 %v", tt.wantErr, err)
+				}
+				if len(repos.txs) != 0 {
+					t.Error("省市校验失败时不应写入任何记录")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("开户失败: %v", err)
+			}
+			if repos.profile.City != tt.city {
+				t.Errorf("City = %q，期望原样保存 %q", repos.profile.City, tt.city)
+			}
+		})
+	}
+}
+
+func TestCreateUserWithIdentityDefaultNickname(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *entities.UserIdentity
+		cfg      config.NicknameConfig
+		want     string // want: 为 "*" 时只要求非空 (随机昵称)
+	}{
+		{name: "账号默认使用标识符", identity: accountIdentity("alice"), want: "alice"},
+		{name: "手机号默认随机昵称", identity: &entities.UserIdentity{IdentityType: myenums.Phone, Identifier: "13800000000"}, want: "*"},
+		{name: "微信默认留空", identity: &entities.UserIdentity{IdentityType: myenums.WechatMiniProgram, Identifier: "openid"}, want: ""},
+		{name: "微信配置 identifier 仍留空", identity: &entities.UserIdentity{IdentityType: myenums.WechatMiniProgram, Identifier: "openid"}, cfg: config.NicknameConfig{WechatDefault: config.NicknameDefaultIdentifier}, want: ""},
+		{name: "账号配置 empty", identity: accountIdentity("alice"), cfg: config.NicknameConfig{AccountDefault: config.NicknameDefaultEmpty}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newMockGormDB(t)
+			svc, repos := newTestProvisioningService(t, db, testProvisioningOptions{nicknameCfg: tt.cfg})
+
+			if _, err := svc.CreateUserWithIdentity(context.Background(), db, enums.RoleUser, enums.StatusActive, tt.identity, nil); err != nil {
+				t.Fatalf("开户失败: %v", err)
+			}
+			got := repos.profile.Nickname
+			if tt.want == "*" {
+				if got == "" || got == tt.identity.Identifier {
+					t.Errorf("昵称 = %q，期望随机生成且不暴露标识符", got)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("昵称 = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateUserWithIdentityDefaultAvatar(t *testing.T) {
+	tests := []struct {
+		name       string
+		cos        *fakeCOSClient
+		style      string
+		avatarURL  string
+		wantUpload int
+		wantAvatar bool
+	}{
+		{name: "未启用默认头像", cos: &fakeCOSClient{}, wantUpload: 0},
+		{name: "生成并上传默认头像", cos: &fakeCOSClient{}, style: config.DefaultAvatarIdenticon, wantUpload: 1, wantAvatar: true},
+		{name: "上传失败不影响开户", cos: &fakeCOSClient{err: errors.New("cos down")}, style: config.DefaultAvatarInitials, wantUpload: 1},
+		{name: "已提供头像时不生成", cos: &fakeCOSClient{}, style: config.DefaultAvatarIdenticon, avatarURL: "https://example.com/a.png", wantUpload: 0, wantAvatar: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newMockGormDB(t)
+			svc, repos := newTestProvisioningService(t, db, testProvisioningOptions{
+				cos:       tt.cos,
+				avatarCfg: config.AvatarConfig{DefaultStyle: tt.style, DefaultSize: 32},
+			})
+
+			profile := &entities.UserProfile{AvatarURL: tt.avatarURL}
+			if _, err := svc.CreateUserWithIdentity(context.Background(), db, enums.RoleUser, enums.StatusActive, accountIdentity("alice"), profile); err != nil {
+				t.Fatalf("开户失败: %v", err)
+			}
+			if tt.cos.uploaded != tt.wantUpload {
+				t.Errorf("上传次数 = %d，期望 %d", tt.cos.uploaded, tt.wantUpload)
+			}
+			if (repos.profile.AvatarURL != "") != tt.wantAvatar {
+				t.Errorf("AvatarURL = %q，期望非空: %v", repos.profile.AvatarURL, tt.wantAvatar)
+			}
+		})
+	}
+}
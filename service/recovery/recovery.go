@@ -0,0 +1,425 @@
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	commonenums "github.com/Xushengqwer/go-common/models/enums"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/password"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+const (
+	// RecoveryTokenTTL 找回凭证的有效期
+	RecoveryTokenTTL = 10 * time.Minute
+
+	// ActionResetPassword 找回凭证可用于重置账号密码
+	ActionResetPassword = "reset_password"
+	// ActionAddIdentity 找回凭证可用于添加新的账号密码登录方式
+	ActionAddIdentity = "add_identity"
+
+	// anonymousActor 找回失败且无法确定用户时审计日志中的操作者
+	anonymousActor = "anonymous"
+)
+
+// errInvalidRecoveryToken 找回凭证不存在、已过期或已被使用
+var errInvalidRecoveryToken = errors.New("找回凭证无效或已过期，请重新发起找回")
+
+// AccountRecoveryService 定义了通过次要已验证身份找回账号的服务接口。
+// 设计目的:
+//   - 用户无法使用主登录方式时，凭另一个已验证的身份（手机号验证码、微信登录 code）证明账号归属。
+//   - 找回凭证是存放在 Redis 中的一次性随机串而不是 JWT，无法通过网关认证，因此不能作为普通会话使用；
+//     它只能用于重置密码或添加新的账号密码登录方式，使用一次即失效。
+//   - 每一次找回尝试（成功或失败）都会写入审计日志。
+type AccountRecoveryService interface {
+	// StartRecovery 校验次要身份及其验证码，成功后签发短期找回凭证。
+	// - 只接受已验证（Verified）的手机号或微信身份；账号密码不能作为找回凭证。
+	// - 返回的错误统一为模糊的业务错误，避免泄露某个手机号是否已注册。
+	StartRecovery(ctx context.Context, data dto.StartRecoveryDTO) (*vo.RecoveryTokenVO, error)
+
+	// ResetPassword 使用找回凭证重置用户账号密码登录方式的密码。
+	// - 新密码同样受历史密码规则约束；凭证在成功后失效。
+	ResetPassword(ctx context.Context, data dto.RecoveryResetPasswordDTO) error
+
+	// AddPasswordIdentity 使用找回凭证为用户添加一个新的账号密码登录方式。
+	// - 仅适用于尚未设置账号密码登录方式的用户（已有的应使用 ResetPassword）；凭证在成功后失效。
+	AddPasswordIdentity(ctx context.Context, data dto.RecoveryAddIdentityDTO) error
+}
+
+// accountRecoveryService 是 AccountRecoveryService 接口的实现。
+type accountRecoveryService struct {
+	identityRepo    mysql.IdentityRepository        // identityRepo: 身份仓库。
+	userRepo        mysql.UserRepository            // userRepo: 用户仓库，用于检查用户状态。
+	auditRepo       mysql.AdminAuditRepository      // auditRepo: 审计日志仓库，记录每一次找回尝试。
+	codeRepo        redis.CodeRepo                  // codeRepo: 短信验证码仓库，手机号找回时使用。
+	recoveryRepo    redis.RecoveryRepo              // recoveryRepo: 找回凭证仓库。
+	wechatClient    dependencies.WechatClient       // wechatClient: 微信客户端，微信找回时用 code 换取 OpenID。
+	passwordHistory password.PasswordHistoryService // passwordHistory: 历史密码校验与记录服务。
+	db              *gorm.DB                        // db: 数据库连接。
+	logger          *core.ZapLogger                 // logger: 日志记录器。
+}
+
+// NewAccountRecoveryService 创建一个新的 accountRecoveryService 实例。
+func NewAccountRecoveryService(
+	identityRepo mysql.IdentityRepository,
+	userRepo mysql.UserRepository,
+	auditRepo mysql.AdminAuditRepository,
+	codeRepo redis.CodeRepo,
+	recoveryRepo redis.RecoveryRepo,
+	wechatClient dependencies.WechatClient,
+	passwordHistory password.PasswordHistoryService,
+	db *gorm.DB,
+	logger *core.ZapLogger,
+) AccountRecoveryService {
+	return &accountRecoveryService{
+		identityRepo:    identityRepo,
+		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		codeRepo:        codeRepo,
+		recoveryRepo:    recoveryRepo,
+		wechatClient:    wechatClient,
+		passwordHistory: passwordHistory,
+		db:              db,
+		logger:          logger,
+	}
+}
+
+// StartRecovery 实现接口方法。
+func (s *accountRecoveryService) StartRecovery(ctx context.Context, data dto.StartRecoveryDTO) (*vo.RecoveryTokenVO, error) {
+	const operation = "AccountRecoveryService.StartRecovery"
+	failedErr := errors.New("身份验证失败，无法找回账号")
+
+	// 1. 校验次要身份的验证码，得到其标识符
+	identifier, err := s.verifyIdentity(ctx, data)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrSystemError) {
+			return nil, err
+		}
+		s.auditAttempt(ctx, "", data.IdentityType, data.Identifier, false, err.Error())
+		return nil, err
+	}
+	masked := utils.MaskIdentifier(data.IdentityType, identifier)
+
+	// 2. 查找身份记录并确认已验证
+	credential, err := s.identityRepo.GetIdentityByTypeAndIdentifier(ctx, data.IdentityType, identifier)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("找回账号时身份不存在", zap.String("operation", operation), zap.String("identifier", masked))
+			s.auditAttempt(ctx, "", data.IdentityType, identifier, false, "身份不存在")
+			return nil, failedErr
+		}
+		s.logger.Error("找回账号时查询身份失败", zap.String("operation", operation), zap.String("identifier", masked), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	userID := credential.UserID
+
+	identities, err := s.identityRepo.GetIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("找回账号时查询用户身份列表失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	verified := false
+	for _, identity := range identities {
+		if identity.IdentityType == data.IdentityType && identity.Identifier == identifier {
+			verified = identity.Verified
+			break
+		}
+	}
+	if !verified {
+		s.logger.Warn("找回账号使用了未验证的身份", zap.String("operation", operation), zap.String("userID", userID))
+		s.auditAttempt(ctx, userID, data.IdentityType, identifier, false, "身份未验证")
+		return nil, failedErr
+	}
+
+	// 3. 被拉黑的用户不允许通过找回绕过封禁
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.Error("找回账号时获取用户信息失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.auditAttempt(ctx, userID, data.IdentityType, identifier, false, "用户不存在")
+			return nil, failedErr
+		}
+		return nil, commonerrors.ErrSystemError
+	}
+	if user.Status == commonenums.StatusBlacklisted {
+		s.logger.Warn("被拉黑的用户尝试找回账号", zap.String("operation", operation), zap.String("userID", userID))
+		s.auditAttempt(ctx, userID, data.IdentityType, identifier, false, "用户已被拉黑")
+		return nil, errors.New("账号状态异常，无法找回")
+	}
+
+	// 4. 签发一次性找回凭证
+	token := uuid.New().String()
+	if err := s.recoveryRepo.SaveToken(ctx, token, userID, RecoveryTokenTTL); err != nil {
+		s.logger.Error("保存找回凭证失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	s.auditAttempt(ctx, userID, data.IdentityType, identifier, true, "")
+	s.logger.Info("已签发账号找回凭证", zap.String("operation", operation), zap.String("userID", userID), zap.Any("identityType", data.IdentityType))
+	return &vo.RecoveryTokenVO{
+		RecoveryToken:  token,
+		ExpiresIn:      int64(RecoveryTokenTTL.Seconds()),
+		AllowedActions: []string{ActionResetPassword, ActionAddIdentity},
+	}, nil
+}
+
+// ResetPassword 实现接口方法。
+func (s *accountRecoveryService) ResetPassword(ctx context.Context, data dto.RecoveryResetPasswordDTO) error {
+	const operation = "AccountRecoveryService.ResetPassword"
+
+	// 1. 校验凭证（暂不消费，业务校验失败时允许用户修改输入后重试）
+	userID, err := s.peekToken(ctx, operation, data.RecoveryToken)
+	if err != nil {
+		return err
+	}
+
+	// 2. 找到账号密码身份并校验新密码
+	identity, err := s.findPasswordIdentity(ctx, userID)
+	if err != nil {
+		s.logger.Error("重置密码时查询身份失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	if identity == nil {
+		s.recordRecoveryAudit(ctx, s.db, userID, enums.AuditActionRecoveryResetPassword, map[string]any{"success": false, "reason": "未设置账号密码登录方式"})
+		return errors.New("该账号未设置账号密码登录方式，请改为添加新的登录方式")
+	}
+	if err := s.passwordHistory.EnsureNotReused(ctx, userID, identity.Credential, data.NewPassword); err != nil {
+		if !errors.Is(err, commonerrors.ErrSystemError) {
+			s.recordRecoveryAudit(ctx, s.db, userID, enums.AuditActionRecoveryResetPassword, map[string]any{"success": false, "reason": err.Error()})
+		}
+		return err
+	}
+	hashedPassword, err := utils.SetPassword(data.NewPassword)
+	if err != nil {
+		s.logger.Error("重置密码时密码加密失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+
+	// 3. 消费凭证：并发请求中只有一个能继续执行
+	if err := s.consumeToken(ctx, operation, data.RecoveryToken); err != nil {
+		return err
+	}
+
+	// 4. 在事务中更新密码、写入历史密码和审计日志
+	identity.Credential = hashedPassword
+	txErr := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.identityRepo.UpdateIdentity(ctx, tx, identity); err != nil {
+			return err
+		}
+		if err := s.passwordHistory.Record(ctx, tx, userID, hashedPassword); err != nil {
+			return err
+		}
+		return s.createRecoveryAudit(ctx, tx, userID, enums.AuditActionRecoveryResetPassword, map[string]any{"success": true, "identity_id": identity.IdentityID})
+	})
+	if txErr != nil {
+		s.logger.Error("找回流程重置密码事务失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(txErr))
+		return commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("已通过找回流程重置密码", zap.String("operation", operation), zap.String("userID", userID))
+	return nil
+}
+
+// AddPasswordIdentity 实现接口方法。
+func (s *accountRecoveryService) AddPasswordIdentity(ctx context.Context, data dto.RecoveryAddIdentityDTO) error {
+	const operation = "AccountRecoveryService.AddPasswordIdentity"
+
+	// 1. 校验凭证（暂不消费）
+	userID, err := s.peekToken(ctx, operation, data.RecoveryToken)
+	if err != nil {
+		return err
+	}
+
+	// 2. 已有账号密码登录方式的用户应改为重置密码；新账号不能已被占用
+	existing, err := s.findPasswordIdentity(ctx, userID)
+	if err != nil {
+		s.logger.Error("添加登录方式时查询身份失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	if existing != nil {
+		s.recordRecoveryAudit(ctx, s.db, userID, enums.AuditActionRecoveryAddIdentity, map[string]any{"success": false, "reason": "已存在账号密码登录方式"})
+		return errors.New("该账号已设置账号密码登录方式，请改为重置密码")
+	}
+	_, err = s.identityRepo.GetIdentityByTypeAndIdentifier(ctx, enums.AccountPassword, data.Account)
+	if err == nil {
+		return errors.New("账号已存在，请更换其他账号")
+	} else if !errors.Is(err, commonerrors.ErrRepoNotFound) {
+		s.logger.Error("添加登录方式时检查账号是否存在失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	hashedPassword, err := utils.SetPassword(data.Password)
+	if err != nil {
+		s.logger.Error("添加登录方式时密码加密失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+
+	// 3. 消费凭证
+	if err := s.consumeToken(ctx, operation, data.RecoveryToken); err != nil {
+		return err
+	}
+
+	// 4. 在事务中创建身份、写入历史密码和审计日志
+	identity := &entities.UserIdentity{
+		UserID:       userID,
+		IdentityType: enums.AccountPassword,
+		Identifier:   data.Account,
+		Credential:   hashedPassword,
+		Verified:     true, // 账号由用户自行设定，不存在需要额外验证的归属关系
+	}
+	txErr := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.identityRepo.CreateIdentity(ctx, tx, identity); err != nil {
+			return err
+		}
+		if err := s.passwordHistory.Record(ctx, tx, userID, hashedPassword); err != nil {
+			return err
+		}
+		return s.createRecoveryAudit(ctx, tx, userID, enums.AuditActionRecoveryAddIdentity, map[string]any{"success": true, "identity_id": identity.IdentityID, "account": utils.MaskIdentifier(enums.AccountPassword, data.Account)})
+	})
+	if txErr != nil {
+		s.logger.Error("找回流程添加登录方式事务失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(txErr))
+		return commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("已通过找回流程添加账号密码登录方式", zap.String("operation", operation), zap.String("userID", userID))
+	return nil
+}
+
+// verifyIdentity 校验次要身份的验证码，返回该身份的标识符（手机号或 OpenID）。
+func (s *accountRecoveryService) verifyIdentity(ctx context.Context, data dto.StartRecoveryDTO) (string, error) {
+	const operation = "AccountRecoveryService.verifyIdentity"
+
+	switch data.IdentityType {
+	case enums.Phone:
+		if data.Identifier == "" {
+			return "", errors.New("请提供手机号")
+		}
+		storedCode, err := s.codeRepo.GetCaptcha(ctx, data.Identifier)
+		if err != nil {
+			if errors.Is(err, commonerrors.ErrRepoNotFound) {
+				return "", errors.New("验证码错误或已过期")
+			}
+			s.logger.Error("找回账号时获取验证码失败", zap.String("operation", operation), zap.Error(err))
+			return "", commonerrors.ErrSystemError
+		}
+		if storedCode != data.Code {
+			return "", errors.New("验证码错误或已过期")
+		}
+		if err := s.codeRepo.DeleteCaptcha(ctx, data.Identifier); err != nil {
+			s.logger.Error("删除已使用的验证码失败", zap.String("operation", operation), zap.Error(err))
+		}
+		return data.Identifier, nil
+	case enums.WechatMiniProgram:
+		openid, _, err := s.wechatClient.GetSession(ctx, data.Code)
+		if err != nil {
+			var apiErr *dependencies.WechatAPIError
+			if errors.As(err, &apiErr) && apiErr.IsClientError() {
+				return "", errors.New("微信登录凭证无效或已过期，请重新登录")
+			}
+			s.logger.Error("找回账号时调用微信 GetSession 失败", zap.String("operation", operation), zap.Error(err))
+			return "", commonerrors.ErrSystemError
+		}
+		return openid, nil
+	default:
+		return "", errors.New("不支持使用该身份类型找回账号")
+	}
+}
+
+// findPasswordIdentity 返回用户的账号密码身份，不存在时返回 nil。
+func (s *accountRecoveryService) findPasswordIdentity(ctx context.Context, userID string) (*entities.UserIdentity, error) {
+	identities, err := s.identityRepo.GetIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, identity := range identities {
+		if identity.IdentityType == enums.AccountPassword {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+// peekToken 校验找回凭证并返回对应的用户 ID。
+func (s *accountRecoveryService) peekToken(ctx context.Context, operation string, token string) (string, error) {
+	userID, err := s.recoveryRepo.PeekToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("使用了无效的找回凭证", zap.String("operation", operation))
+			return "", errInvalidRecoveryToken
+		}
+		s.logger.Error("读取找回凭证失败", zap.String("operation", operation), zap.Error(err))
+		return "", commonerrors.ErrSystemError
+	}
+	return userID, nil
+}
+
+// consumeToken 原子地消费找回凭证，凭证已被并发请求使用时返回无效凭证错误。
+func (s *accountRecoveryService) consumeToken(ctx context.Context, operation string, token string) error {
+	if _, err := s.recoveryRepo.ConsumeToken(ctx, token); err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("找回凭证已被使用", zap.String("operation", operation))
+			return errInvalidRecoveryToken
+		}
+		s.logger.Error("消费找回凭证失败", zap.String("operation", operation), zap.Error(err))
+		return commonerrors.ErrSystemError
+	}
+	return nil
+}
+
+// auditAttempt 记录一次发起找回的尝试，标识符脱敏后写入；无法确定用户时以 anonymous 记录。
+func (s *accountRecoveryService) auditAttempt(ctx context.Context, userID string, identityType enums.IdentityType, identifier string, success bool, reason string) {
+	detail := map[string]any{
+		"identity_type": identityType,
+		"success":       success,
+	}
+	if identifier != "" {
+		detail["identifier"] = utils.MaskIdentifier(identityType, identifier)
+	}
+	if reason != "" {
+		detail["reason"] = reason
+	}
+	if userID == "" {
+		userID = anonymousActor
+	}
+	s.recordRecoveryAudit(ctx, s.db, userID, enums.AuditActionRecoveryStart, detail)
+}
+
+// recordRecoveryAudit 写入审计日志，失败只记录日志，不影响找回流程的结果。
+func (s *accountRecoveryService) recordRecoveryAudit(ctx context.Context, db *gorm.DB, userID string, action enums.AuditAction, detail map[string]any) {
+	if err := s.createRecoveryAudit(ctx, db, userID, action, detail); err != nil {
+		s.logger.Error("写入账号找回审计日志失败",
+			zap.String("operation", "AccountRecoveryService.recordRecoveryAudit"),
+			zap.String("userID", userID),
+			zap.String("action", string(action)),
+			zap.Error(err),
+		)
+	}
+}
+
+// createRecoveryAudit 构造并写入一条账号找回审计日志，操作者与目标均为被找回的用户。
+func (s *accountRecoveryService) createRecoveryAudit(ctx context.Context, db *gorm.DB, userID string, action enums.AuditAction, detail map[string]any) error {
+	detailJSON, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.CreateAuditLog(ctx, db, &entities.AdminAuditLog{
+		ActorID:  userID,
+		Action:   action,
+		TargetID: userID,
+		Diff:     string(detailJSON),
+	})
+}
@@ -0,0 +1,112 @@
+package registration
+
+import (
+	"sync/atomic"
+
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
+)
+
+// registrationConfigKey 注册开关在配置文件中的顶层键名，与 UserHubConfig 的 mapstructure 标签保持一致
+const registrationConfigKey = "registrationConfig"
+
+// 注册渠道，仅用于日志记录
+const (
+	ChannelAccount = "account" // 账号密码注册
+	ChannelPhone   = "phone"   // 手机号验证码登录时自动注册
+	ChannelWechat  = "wechat"  // 微信小程序登录时自动注册
+)
+
+// RegistrationGateService 定义了注册开关相关的服务接口。
+// 设计目的:
+// - 在所有创建新用户的入口 (账号注册、手机号/微信自动注册) 统一检查注册是否开放。
+// - 配置文件变更后自动热重载，读取路径无锁，切换以整体快照替换的方式完成。
+type RegistrationGateService interface {
+	// CheckRegistration 在创建新用户之前调用。
+	// 参数:
+	//  - channel: 注册渠道 (Channel* 常量)，仅用于日志。
+	// 返回:
+	//  - error: 注册关闭时返回 loginerr.ErrRegistrationClosed，否则为 nil。
+	CheckRegistration(channel string) error
+
+	// CheckLoginOrRegisterEntry 在手机号/微信"登录或注册"入口的开始处调用。
+	// 仅当注册关闭且 closed_mode 为 reject 时拒绝整个入口；login_only 模式下放行，由自动注册分支再调用 CheckRegistration。
+	// 参数:
+	//  - channel: 登录渠道 (ChannelPhone / ChannelWechat)，仅用于日志。
+	// 返回:
+	//  - error: 入口被拒绝时返回 loginerr.ErrRegistrationClosed，否则为 nil。
+	CheckLoginOrRegisterEntry(channel string) error
+
+	// ReloadConfig 配置文件变化后重新加载注册开关，由 dependencies.WatchConfigFile 统一调用。
+	// 解析失败时保留旧快照。
+	dependencies.ConfigSubscriber
+}
+
+// registrationGateService 是 RegistrationGateService 接口的实现。
+type registrationGateService struct {
+	current atomic.Pointer[config.RegistrationConfig] // current: 当前生效的注册开关快照，热重载时整体替换。
+	logger  *core.ZapLogger                           // logger: 日志记录器。
+}
+
+// NewRegistrationGateService 创建一个新的 registrationGateService 实例。
+// 参数:
+//   - initial: 启动时加载的注册开关配置。
+//   - logger: 日志记录器实例。
+func NewRegistrationGateService(initial config.RegistrationConfig, logger *core.ZapLogger) RegistrationGateService {
+	s := &registrationGateService{logger: logger}
+	s.current.Store(&initial)
+	return s
+}
+
+// CheckRegistration 实现接口方法，注册关闭时拒绝创建新用户。
+func (s *registrationGateService) CheckRegistration(channel string) error {
+	if s.current.Load().Open() {
+		return nil
+	}
+	s.logger.Info("注册暂未开放，拒绝创建新用户",
+		zap.String("operation", "RegistrationGateService.CheckRegistration"),
+		zap.String("channel", channel),
+	)
+	return loginerr.ErrRegistrationClosed
+}
+
+// CheckLoginOrRegisterEntry 实现接口方法，reject 模式下注册关闭时拒绝"登录或注册"入口。
+func (s *registrationGateService) CheckLoginOrRegisterEntry(channel string) error {
+	cfg := s.current.Load()
+	if cfg.Open() || cfg.ClosedModeOrDefault() != config.RegistrationClosedModeReject {
+		return nil
+	}
+	s.logger.Info("注册暂未开放，拒绝登录或注册入口",
+		zap.String("operation", "RegistrationGateService.CheckLoginOrRegisterEntry"),
+		zap.String("channel", channel),
+	)
+	return loginerr.ErrRegistrationClosed
+}
+
+// ReloadConfig 实现接口方法，仅重新解析注册开关部分并整体替换快照。
+func (s *registrationGateService) ReloadConfig(v *viper.Viper, file string) {
+	const operation = "RegistrationGateService.ReloadConfig"
+
+	var next config.RegistrationConfig
+	if err := v.UnmarshalKey(registrationConfigKey, &next); err != nil {
+		// 解析失败时保留旧快照，避免错误配置意外开放或关闭注册
+		s.logger.Error("热重载注册开关失败，继续使用旧配置",
+			zap.String("operation", operation),
+			zap.String("file", file),
+			zap.Error(err),
+		)
+		return
+	}
+	s.current.Store(&next)
+	s.logger.Info("注册开关已热重载",
+		zap.String("operation", operation),
+		zap.String("file", file),
+		zap.Bool("open", next.Open()),
+		zap.String("closedMode", next.ClosedModeOrDefault()),
+	)
+}
@@ -0,0 +1,103 @@
+package registration
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/spf13/viper"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/service/login/loginerr"
+)
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// newYAMLViper 返回已读取给定 YAML 内容的 viper 实例，模拟配置文件变化后的状态
+func newYAMLViper(t *testing.T, content string) *viper.Viper {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewBufferString(content)); err != nil {
+		t.Fatalf("读取 YAML 失败: %v", err)
+	}
+	return v
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestRegistrationGateOpenAndClosedStates(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             config.RegistrationConfig
+		wantRegister    bool // CheckRegistration 是否放行
+		wantEntryAccess bool // CheckLoginOrRegisterEntry 是否放行
+	}{
+		{name: "未配置视为开放", cfg: config.RegistrationConfig{}, wantRegister: true, wantEntryAccess: true},
+		{name: "显式开放", cfg: config.RegistrationConfig{Enabled: boolPtr(true), ClosedMode: config.RegistrationClosedModeReject}, wantRegister: true, wantEntryAccess: true},
+		{name: "关闭_默认仅允许登录", cfg: config.RegistrationConfig{Enabled: boolPtr(false)}, wantRegister: false, wantEntryAccess: true},
+		{name: "关闭_login_only", cfg: config.RegistrationConfig{Enabled: boolPtr(false), ClosedMode: config.RegistrationClosedModeLoginOnly}, wantRegister: false, wantEntryAccess: true},
+		{name: "关闭_reject", cfg: config.RegistrationConfig{Enabled: boolPtr(false), ClosedMode: config.RegistrationClosedModeReject}, wantRegister: false, wantEntryAccess: false},
+		{name: "关闭_无效模式按 login_only 处理", cfg: config.RegistrationConfig{Enabled: boolPtr(false), ClosedMode: "unknown"}, wantRegister: false, wantEntryAccess: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := NewRegistrationGateService(tt.cfg, newTestLogger(t))
+			assertGate(t, gate, tt.wantRegister, tt.wantEntryAccess)
+		})
+	}
+}
+
+// assertGate 断言两个检查方法的放行结果；拒绝时必须返回 loginerr.ErrRegistrationClosed
+func assertGate(t *testing.T, gate RegistrationGateService, wantRegister, wantEntryAccess bool) {
+	t.Helper()
+	for _, channel := range []string{ChannelAccount, ChannelPhone, ChannelWechat} {
+		err := gate.CheckRegistration(channel)
+		if wantRegister && err != nil {
+			t.Errorf("CheckRegistration(%s) 期望放行，实际为 %v", channel, err)
+		}
+		if !wantRegister && !errors.Is(err, loginerr.ErrRegistrationClosed) {
+			t.Errorf("CheckRegistration(%s) 期望 ErrRegistrationClosed，实际为 %v", channel, err)
+		}
+	}
+	for _, channel := range []string{ChannelPhone, ChannelWechat} {
+		err := gate.CheckLoginOrRegisterEntry(channel)
+		if wantEntryAccess && err != nil {
+			t.Errorf("CheckLoginOrRegisterEntry(%s) 期望放行，实际为 %v", channel, err)
+		}
+		if !wantEntryAccess && !errors.Is(err, loginerr.ErrRegistrationClosed) {
+			t.Errorf("CheckLoginOrRegisterEntry(%s) 期望 ErrRegistrationClosed，实际为 %v", channel, err)
+		}
+	}
+}
+
+func TestRegistrationGateReloadConfig(t *testing.T) {
+	gate := NewRegistrationGateService(config.RegistrationConfig{}, newTestLogger(t))
+
+	// 开放 -> 关闭 (reject)
+	gate.ReloadConfig(newYAMLViper(t, "registrationConfig:\n  enabled: false\n  closed_mode: reject\n"), "config.yaml")
+	assertGate(t, gate, false, false)
+
+	// 解析失败时保留旧快照
+	gate.ReloadConfig(newYAMLViper(t, "registrationConfig:\n  enabled: [1, 2]\n"), "config.yaml")
+	assertGate(t, gate, false, false)
+
+	// 关闭 -> 重新开放
+	gate.ReloadConfig(newYAMLViper(t, "registrationConfig:\n  enabled: true\n"), "config.yaml")
+	assertGate(t, gate, true, true)
+
+	// 删除整个配置段视为开放
+	gate.ReloadConfig(newYAMLViper(t, "registrationConfig:\n  enabled: false\n"), "config.yaml")
+	gate.ReloadConfig(newYAMLViper(t, "otherConfig:\n  key: value\n"), "config.yaml")
+	assertGate(t, gate, true, true)
+}
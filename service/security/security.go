@@ -0,0 +1,196 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/service/identity"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+const (
+	// overviewCacheTTL 安全概览的缓存时间，足够覆盖安全页的连续刷新，又不会让绑定/解绑后的变化长时间不可见
+	overviewCacheTTL = 30 * time.Second
+	// recentEventsLimit 概览中返回的近期安全事件条数
+	recentEventsLimit = 5
+	// recentEventsWindow 近期安全事件的回溯时间
+	recentEventsWindow = 90 * 24 * time.Hour
+)
+
+// platformOrder 已登录平台的展示顺序
+var platformOrder = []enums.Platform{enums.PlatformWeb, enums.PlatformApp, enums.PlatformWechat}
+
+// ErrUserNotFound 查询概览的用户不存在 (或已删除) 时返回
+var ErrUserNotFound = errors.New("用户不存在")
+
+// AccountSecurityService 定义了账号安全概览的只读聚合服务接口。
+// 设计目的:
+// - 账号安全页需要同时展示登录方式、会话、最近登录和安全事件，由服务端一次聚合返回，减少前端往返。
+// - 只读取数据，不做任何修改；结果短期缓存，缓存读写失败时直接回源，不影响接口可用性。
+type AccountSecurityService interface {
+	// GetSecurityOverview 获取指定用户的账号安全概览。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - userID: 当前认证用户的 ID。
+	// 返回:
+	//  - *vo.SecurityOverviewVO: 聚合后的安全概览。
+	//  - error: 用户不存在返回 ErrUserNotFound，其他失败返回 commonerrors.ErrSystemError。
+	GetSecurityOverview(ctx context.Context, userID string) (*vo.SecurityOverviewVO, error)
+
+	// GetActivePlatforms 获取指定用户当前至少有一个有效会话的平台及各平台会话数。
+	// - 直接读取刷新令牌白名单 (每个用户的记录很少)，不经过缓存，登录/登出后立即可见。
+	// - 未启用刷新令牌白名单时返回的 Platforms 与 TotalSessions 为 nil。
+	// 返回:
+	//  - error: 查询失败返回 commonerrors.ErrSystemError。
+	GetActivePlatforms(ctx context.Context, userID string) (*vo.ActivePlatformsVO, error)
+}
+
+// accountSecurityService 是 AccountSecurityService 接口的实现。
+type accountSecurityService struct {
+	userRepo         mysql.UserRepository         // userRepo: 用户仓库，读取账号状态。
+	identityService  identity.UserIdentityService // identityService: 身份服务，读取脱敏后的登录方式。
+	refreshTokenRepo mysql.RefreshTokenRepository // refreshTokenRepo: 刷新令牌白名单仓库，统计有效会话。
+	auditRepo        mysql.AdminAuditRepository   // auditRepo: 审计日志仓库，读取近期安全事件。
+	cache            redis.SecurityOverviewCache  // cache: 概览短期缓存。
+	refreshWhitelist bool                         // refreshWhitelist: 是否启用刷新令牌白名单，未启用时无法统计会话数。
+	clock            utils.Clock                  // clock: 时间来源，判断会话是否有效并确定近期事件的时间窗口。
+	logger           *core.ZapLogger              // logger: 日志记录器。
+}
+
+// NewAccountSecurityService 创建一个新的 accountSecurityService 实例。
+// 参数:
+//   - refreshWhitelist: 对应 JWTConfig.RefreshWhitelist；为 false 时概览中的有效会话数返回 null。
+func NewAccountSecurityService(
+	userRepo mysql.UserRepository,
+	identityService identity.UserIdentityService,
+	refreshTokenRepo mysql.RefreshTokenRepository,
+	auditRepo mysql.AdminAuditRepository,
+	cache redis.SecurityOverviewCache,
+	refreshWhitelist bool,
+	clock utils.Clock,
+	logger *core.ZapLogger,
+) AccountSecurityService {
+	return &accountSecurityService{
+		userRepo:         userRepo,
+		identityService:  identityService,
+		refreshTokenRepo: refreshTokenRepo,
+		auditRepo:        auditRepo,
+		cache:            cache,
+		refreshWhitelist: refreshWhitelist,
+		clock:            clock,
+		logger:           logger,
+	}
+}
+
+// GetSecurityOverview 实现接口方法。
+func (s *accountSecurityService) GetSecurityOverview(ctx context.Context, userID string) (*vo.SecurityOverviewVO, error) {
+	const operation = "AccountSecurityService.GetSecurityOverview"
+
+	// 1. 优先读取缓存；缓存异常只记录日志并回源
+	if cached, err := s.cache.Get(ctx, userID); err == nil {
+		var overview vo.SecurityOverviewVO
+		if json.Unmarshal(cached, &overview) == nil {
+			return &overview, nil
+		}
+	} else if !errors.Is(err, commonerrors.ErrRepoNotFound) {
+		s.logger.Warn("读取安全概览缓存失败，回源查询", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+	}
+
+	// 2. 账号状态
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.Error("查询用户失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	// 3. 登录方式 (已脱敏)，并据此推算最近一次登录
+	loginMethods, err := s.identityService.GetLoginMethodsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	overview := &vo.SecurityOverviewVO{
+		UserID:       userID,
+		Status:       user.Status,
+		LoginMethods: loginMethods,
+		GeneratedAt:  now,
+	}
+	for _, method := range loginMethods {
+		if method.LastUsedAt != nil && (overview.LastLoginAt == nil || method.LastUsedAt.After(*overview.LastLoginAt)) {
+			identityType := method.IdentityType
+			overview.LastLoginAt = method.LastUsedAt
+			overview.LastLoginMethod = &identityType
+		}
+	}
+
+	// 4. 有效会话数 (仅在启用刷新令牌白名单时可统计)
+	if s.refreshWhitelist {
+		count, err := s.refreshTokenRepo.CountActiveByUserID(ctx, userID, now)
+		if err != nil {
+			s.logger.Error("统计有效会话失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			return nil, commonerrors.ErrSystemError
+		}
+		overview.ActiveSessions = &count
+	}
+
+	// 5. 近期安全事件：针对该用户的审计日志
+	since := now.Add(-recentEventsWindow)
+	logs, _, err := s.auditRepo.ListAuditLogs(ctx, &dto.AuditLogQueryDTO{TargetID: userID, StartTime: &since}, nil, recentEventsLimit)
+	if err != nil {
+		s.logger.Error("查询近期安全事件失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	overview.RecentEvents = make([]*vo.SecurityEventVO, 0, len(logs))
+	for _, log := range logs {
+		overview.RecentEvents = append(overview.RecentEvents, &vo.SecurityEventVO{Action: log.Action, CreatedAt: log.CreatedAt})
+	}
+
+	// 6. 写入缓存，失败不影响本次结果
+	if data, err := json.Marshal(overview); err == nil {
+		if err := s.cache.Set(ctx, userID, data, overviewCacheTTL); err != nil {
+			s.logger.Warn("写入安全概览缓存失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		}
+	}
+	return overview, nil
+}
+
+// GetActivePlatforms 实现接口方法。
+func (s *accountSecurityService) GetActivePlatforms(ctx context.Context, userID string) (*vo.ActivePlatformsVO, error) {
+	const operation = "AccountSecurityService.GetActivePlatforms"
+
+	result := &vo.ActivePlatformsVO{}
+	if !s.refreshWhitelist {
+		return result, nil
+	}
+
+	counts, err := s.refreshTokenRepo.CountActiveByPlatform(ctx, userID, s.clock.Now())
+	if err != nil {
+		s.logger.Error("按平台统计有效会话失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	var total int64
+	result.Platforms = make([]*vo.PlatformSessionsVO, 0, len(counts))
+	for _, platform := range platformOrder {
+		if count := counts[platform]; count > 0 {
+			result.Platforms = append(result.Platforms, &vo.PlatformSessionsVO{Platform: platform, Sessions: count})
+			total += count
+		}
+	}
+	result.TotalSessions = &total
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+)
+
+// asyncSendTimeout 异步发送时后台任务 (含全部重试) 的总超时时间
+const asyncSendTimeout = 30 * time.Second
+
+// errSMSClientUnavailable 短信客户端未初始化 (如配置缺失) 时的发送错误
+var errSMSClientUnavailable = errors.New("短信客户端未初始化")
+
+// CaptchaSender 定义了带重试的验证码短信发送接口。
+// 设计目的:
+// - 短信服务商偶发失败时按指数退避重试，避免用户因一次失败而必须重新发起流程。
+// - 重试耗尽后写入死信表，便于运维发现与跟进。
+type CaptchaSender interface {
+	// Send 发送验证码到指定手机号。
+	// - 同步模式下阻塞直到发送成功或重试耗尽，重试耗尽时返回包装了 ErrThirdPartyServiceError 的错误。
+	// - 异步模式 (配置 async_send) 下立即返回 nil，发送与重试在后台完成，调用方需在调用前保存好验证码。
+	Send(ctx context.Context, phone string, code string) error
+}
+
+// captchaSender 是 CaptchaSender 接口的实现。
+type captchaSender struct {
+	client         dependencies.SMSClient        // client: 底层短信客户端。
+	deadLetterRepo mysql.SMSDeadLetterRepository // deadLetterRepo: 死信记录仓库。
+	smsConfig      config.SMSConfig              // smsConfig: 重试次数、退避时间与异步开关。
+	db             *gorm.DB                      // db: 数据库连接。
+	logger         *core.ZapLogger               // logger: 日志记录器。
+}
+
+// NewCaptchaSender 创建一个新的 captchaSender 实例。
+// 参数:
+//   - client: 底层短信客户端，可以为 nil (未配置短信服务时每次发送都会失败并记录死信)。
+//   - deadLetterRepo: 死信记录仓库。
+//   - smsCfg: 短信配置。
+//   - db: 数据库连接。
+//   - logger: 日志记录器实例。
+func NewCaptchaSender(
+	client dependencies.SMSClient,
+	deadLetterRepo mysql.SMSDeadLetterRepository,
+	smsCfg config.SMSConfig,
+	db *gorm.DB,
+	logger *core.ZapLogger,
+) CaptchaSender {
+	return &captchaSender{
+		client:         client,
+		deadLetterRepo: deadLetterRepo,
+		smsConfig:      smsCfg,
+		db:             db,
+		logger:         logger,
+	}
+}
+
+// Send 实现接口方法。
+func (s *captchaSender) Send(ctx context.Context, phone string, code string) error {
+	if !s.smsConfig.AsyncSend {
+		return s.sendWithRetry(ctx, phone, code)
+	}
+
+	// 后台任务不随请求结束而取消，但有独立的总超时
+	bgCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), asyncSendTimeout)
+	go func() {
+		defer cancel()
+		_ = s.sendWithRetry(bgCtx, phone, code)
+	}()
+	return nil
+}
+
+// sendWithRetry 按配置的次数与指数退避发送，全部失败后写入死信记录。
+func (s *captchaSender) sendWithRetry(ctx context.Context, phone string, code string) error {
+	const operation = "CaptchaSender.sendWithRetry"
+
+	maxAttempts := s.smsConfig.SendAttemptsOrDefault()
+	backoff := s.smsConfig.RetryBackoffOrDefault()
+
+	var lastErr error
+	attempts := 0
+	for attempts < maxAttempts {
+		if attempts > 0 {
+			if err := waitBackoff(ctx, backoff); err != nil {
+				lastErr = fmt.Errorf("等待重试时上下文结束: %w (上次错误: %v)", err, lastErr)
+				break
+			}
+			backoff *= 2
+		}
+
+		attempts++
+		lastErr = s.sendOnce(ctx, phone, code)
+		if lastErr == nil {
+			if attempts > 1 {
+				s.logger.Info("短信验证码重试后发送成功", zap.String("operation", operation), zap.String("phone", phone), zap.Int("attempts", attempts))
+			}
+			return nil
+		}
+		s.logger.Warn("短信验证码发送失败", zap.String("operation", operation), zap.String("phone", phone), zap.Int("attempt", attempts), zap.Int("maxAttempts", maxAttempts), zap.Error(lastErr))
+	}
+
+	s.recordDeadLetter(ctx, phone, attempts, lastErr)
+	return fmt.Errorf("短信验证码发送失败 (已尝试 %d 次): %w: %w", attempts, commonerrors.ErrThirdPartyServiceError, dependencies.ClassifyUpstreamError(dependencies.UpstreamServiceSMS, lastErr))
+}
+
+// waitBackoff 等待 d 后返回 nil；期间上下文结束则返回上下文错误。
+func waitBackoff(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sendOnce 调用底层客户端发送一次。
+func (s *captchaSender) sendOnce(ctx context.Context, phone string, code string) error {
+	if s.client == nil {
+		return errSMSClientUnavailable
+	}
+	return s.client.SendCode(ctx, phone, code)
+}
+
+// recordDeadLetter 写入死信记录；写入失败只记录日志。
+func (s *captchaSender) recordDeadLetter(ctx context.Context, phone string, attempts int, lastErr error) {
+	const operation = "CaptchaSender.recordDeadLetter"
+
+	errMsg := ""
+	if lastErr != nil {
+		// 按字符截断到列宽，避免截断出非法的 UTF-8 序列
+		if runes := []rune(lastErr.Error()); len(runes) > 1024 {
+			errMsg = string(runes[:1024])
+		} else {
+			errMsg = string(runes)
+		}
+	}
+	record := &entities.SMSDeadLetter{Phone: phone, Attempts: attempts, LastError: errMsg}
+
+	// 请求可能已超时或取消，死信写入使用不可取消的上下文
+	if err := s.deadLetterRepo.Create(context.WithoutCancel(ctx), s.db, record); err != nil {
+		s.logger.Error("写入短信死信记录失败", zap.String("operation", operation), zap.String("phone", phone), zap.Error(err))
+		return
+	}
+	s.logger.Error("短信验证码重试耗尽，已写入死信记录", zap.String("operation", operation), zap.String("phone", phone), zap.Int("attempts", attempts), zap.String("lastError", errMsg))
+}
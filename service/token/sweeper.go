@@ -0,0 +1,107 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// RefreshTokenSweeper 定义了后台清理过期刷新令牌记录的任务接口。
+// 设计目的:
+// - 刷新令牌自然过期且用户未显式登出、也不再登录时，白名单记录不会被顺带清理，需要周期任务兜底。
+// - 每轮按批次删除并限制批次数，避免积压较多时单轮占用数据库过久。
+type RefreshTokenSweeper interface {
+	// Run 按配置的间隔循环清理，阻塞直到 ctx 结束；调用方通过取消 ctx 停止任务。
+	Run(ctx context.Context)
+
+	// SweepOnce 执行一轮清理，返回本轮删除的记录数。
+	SweepOnce(ctx context.Context) (int64, error)
+}
+
+// refreshTokenSweeper 是 RefreshTokenSweeper 接口的实现。
+type refreshTokenSweeper struct {
+	refreshTokenRepo mysql.RefreshTokenRepository // refreshTokenRepo: 刷新令牌白名单仓库。
+	cfg              config.SessionSweepConfig    // cfg: 清理间隔与批次限制。
+	db               *gorm.DB                     // db: 数据库连接。
+	clock            utils.Clock                  // clock: 时间来源，判断白名单记录是否过期。
+	logger           *core.ZapLogger              // logger: 日志记录器。
+}
+
+// NewRefreshTokenSweeper 创建一个新的 refreshTokenSweeper 实例。
+func NewRefreshTokenSweeper(
+	refreshTokenRepo mysql.RefreshTokenRepository,
+	cfg config.SessionSweepConfig,
+	db *gorm.DB,
+	clock utils.Clock,
+	logger *core.ZapLogger,
+) RefreshTokenSweeper {
+	return &refreshTokenSweeper{
+		refreshTokenRepo: refreshTokenRepo,
+		cfg:              cfg,
+		db:               db,
+		clock:            clock,
+		logger:           logger,
+	}
+}
+
+// Run 实现接口方法。
+func (s *refreshTokenSweeper) Run(ctx context.Context) {
+	const operation = "RefreshTokenSweeper.Run"
+
+	interval := s.cfg.IntervalOrDefault()
+	s.logger.Info("过期刷新令牌清理任务已启动", zap.String("operation", operation), zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("过期刷新令牌清理任务已停止", zap.String("operation", operation))
+			return
+		case <-ticker.C:
+			// 失败只记录日志，下一轮重试
+			_, _ = s.SweepOnce(ctx)
+		}
+	}
+}
+
+// SweepOnce 实现接口方法。
+func (s *refreshTokenSweeper) SweepOnce(ctx context.Context) (int64, error) {
+	const operation = "RefreshTokenSweeper.SweepOnce"
+
+	batchSize := s.cfg.BatchSizeOrDefault()
+	maxBatches := s.cfg.MaxBatchesOrDefault()
+	now := s.clock.Now()
+
+	var total int64
+	for batch := 0; batch < maxBatches; batch++ {
+		if ctx.Err() != nil {
+			break
+		}
+		deleted, err := s.refreshTokenRepo.DeleteExpiredBatch(ctx, s.db, now, batchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				// 关停时取消的清理不视为失败
+				return total, ctx.Err()
+			}
+			s.logger.Error("清理过期刷新令牌记录失败", zap.String("operation", operation), zap.Int64("deletedSoFar", total), zap.Error(err))
+			return total, err
+		}
+		total += deleted
+		if deleted < int64(batchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.logger.Info("已清理过期刷新令牌记录", zap.String("operation", operation), zap.Int64("deleted", total))
+	}
+	return total, nil
+}
@@ -2,8 +2,12 @@ package token
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt" // 引入 fmt 包用于错误包装
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	// 引入公共模块
@@ -13,9 +17,15 @@ import (
 	"go.uber.org/zap" // 引入 zap 用于日志字段
 
 	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	projectEnums "github.com/Xushengqwer/user_hub/models/enums" // 项目内部枚举
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/repository/mysql"
 	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/utils"
+
+	"gorm.io/gorm"
 )
 
 // AuthTokenService 定义了管理认证令牌（Access Token 和 Refresh Token）的服务接口。
@@ -46,14 +56,148 @@ type AuthTokenService interface {
 	//  - vo.TokenPair: 包含新的 Access Token 和 Refresh Token 的结构体。
 	//  - error: 操作过程中发生的任何错误，可能是业务错误（如令牌无效、用户状态异常）或系统错误。
 	RefreshToken(ctx context.Context, refreshToken string) (vo.TokenPair, error)
+
+	// IssueRefreshToken 为用户签发新的 Refresh Token。
+	// 主要逻辑: 生成令牌；启用刷新令牌白名单时，同时将其 JTI 与过期时间落库，后续刷新时才会被接受。
+	// 所有登录流程都应通过此方法签发 Refresh Token，而不是直接调用 JWT 工具。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - userID: 用户ID。
+	//  - platform: 客户端平台类型，决定令牌有效期。
+	// 返回:
+	//  - string: 签发的 Refresh Token。
+	//  - error: 生成或落库失败时返回包装后的错误，调用方负责映射为系统错误。
+	IssueRefreshToken(ctx context.Context, userID string, platform enums.Platform) (string, error)
+
+	// GetBlacklistStats 返回令牌黑名单的计数与大小估算，用于容量规划。
+	// 主要逻辑: 读取进程内计数，并通过有界 SCAN 重新估算黑名单大小（结果会被缓存供指标导出使用）。
+	// 参数:
+	//  - ctx: 请求上下文。
+	// 返回:
+	//  - *vo.BlacklistStatsVO: 统计信息。
+	//  - error: 估算过程中 Redis 操作失败时返回系统错误。
+	GetBlacklistStats(ctx context.Context) (*vo.BlacklistStatsVO, error)
+
+	// GetBlacklistMetrics 返回令牌黑名单的计数与最近一次的大小估算，不访问 Redis。
+	// 用于 Prometheus 抓取等高频调用场景。
+	GetBlacklistMetrics() *vo.BlacklistStatsVO
+
+	// InspectBlacklistedJti 查询单个 JTI 是否在黑名单中及其剩余存活时间，用于排查“退出后无法重新登录”等问题。
+	// 返回:
+	//  - *vo.BlacklistEntryVO: JTI 的黑名单状态。
+	//  - error: Redis 查询失败时返回系统错误。
+	InspectBlacklistedJti(ctx context.Context, jti string) (*vo.BlacklistEntryVO, error)
+
+	// ClearBlacklistedJti 将单个 JTI 移出黑名单，并写入审计日志。
+	// 注意: 移出后对应令牌在过期前会重新可用，调用方必须确保只有管理员能够触发。
+	// 参数:
+	//  - actorID: 执行操作的管理员 ID。
+	//  - jti: 要移出黑名单的 JWT ID。
+	//  - reason: 操作原因，写入审计日志。
+	// 返回:
+	//  - *vo.BlacklistClearVO: 是否确实移除了记录。
+	//  - error: Redis 或审计日志写入失败时返回系统错误。
+	ClearBlacklistedJti(ctx context.Context, actorID string, jti string, reason string) (*vo.BlacklistClearVO, error)
+
+	// IntrospectToken 检查令牌当前是否可用，供网关等内部调用方判断应刷新令牌还是要求重新登录。
+	// 主要逻辑: 按令牌类型解析并校验签名、有效期、发行者与受众，再检查 JTI 黑名单。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - tokenString: 待检查的令牌。
+	//  - refresh: true 表示按 Refresh Token 解析，否则按 Access Token 解析。
+	// 返回:
+	//  - *vo.TokenIntrospectionVO: 令牌可用时 Active 为 true 并带有声明信息；不可用时带有粗粒度原因。
+	//  - error: 仅在检查黑名单等系统操作失败时返回系统错误，令牌本身无效不视为错误。
+	IntrospectToken(ctx context.Context, tokenString string, refresh bool) (*vo.TokenIntrospectionVO, error)
+
+	// IntrospectTokens 批量检查令牌，供网关在一次请求中校验多个令牌。
+	// 主要逻辑: 使用有界的工作协程并行解析令牌，再对解析成功的 JTI 做一次批量黑名单查询。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - requests: 待检查的令牌及类型提示，数量由调用方限制。
+	// 返回:
+	//  - []*vo.TokenIntrospectionVO: 与 requests 顺序一一对应的检查结果。
+	//  - error: 仅在批量查询黑名单失败时返回系统错误。
+	IntrospectTokens(ctx context.Context, requests []dto.IntrospectTokenRequest) ([]*vo.TokenIntrospectionVO, error)
+
+	// DescribeAccessToken 解析调用方自己的 Access Token 并返回其声明，不查询数据库。
+	// 主要逻辑: 解析并校验令牌，检查 JTI 黑名单。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - accessToken: 调用方的 Access Token。
+	// 返回:
+	//  - *vo.TokenClaimsVO: 令牌中的声明。
+	//  - error: 令牌无效或已吊销时返回 *TokenRejectedError；检查黑名单失败时返回系统错误。
+	DescribeAccessToken(ctx context.Context, accessToken string) (*vo.TokenClaimsVO, error)
+}
+
+// ErrTokenRevoked 令牌的 JTI 已在黑名单中或刷新令牌已被使用
+var ErrTokenRevoked = errors.New("令牌已被吊销")
+
+// TokenRejectedError 令牌被拒绝时返回的业务错误。
+// - Error() 只返回面向终端用户的提示，不包含具体的校验失败原因。
+// - 具体原因保留在 Cause 中，可通过 errors.Is 判断 (如 dependencies.ErrTokenExpired、ErrTokenRevoked)。
+type TokenRejectedError struct {
+	Reason  projectEnums.TokenRejectReason // 粗粒度原因: expired / invalid / revoked
+	Message string                         // 面向终端用户的提示
+	Cause   error                          // 具体原因
 }
 
+func (e *TokenRejectedError) Error() string { return e.Message }
+
+func (e *TokenRejectedError) Unwrap() error { return e.Cause }
+
+// RejectionReasonOf 将令牌解析或校验错误归类为粗粒度原因。
+func RejectionReasonOf(err error) projectEnums.TokenRejectReason {
+	var rejected *TokenRejectedError
+	switch {
+	case errors.As(err, &rejected):
+		return rejected.Reason
+	case errors.Is(err, ErrTokenRevoked):
+		return projectEnums.TokenRejectRevoked
+	case errors.Is(err, dependencies.ErrTokenExpired):
+		return projectEnums.TokenRejectExpired
+	default:
+		return projectEnums.TokenRejectInvalid
+	}
+}
+
+// newRefreshTokenRejected 根据解析错误构造刷新令牌被拒绝的业务错误。
+func newRefreshTokenRejected(cause error) *TokenRejectedError {
+	reason := RejectionReasonOf(cause)
+	message := "无效的刷新令牌"
+	switch reason {
+	case projectEnums.TokenRejectExpired:
+		message = "刷新令牌已过期，请重新登录"
+	case projectEnums.TokenRejectRevoked:
+		message = "刷新令牌已失效"
+	}
+	return &TokenRejectedError{Reason: reason, Message: message, Cause: cause}
+}
+
+const (
+	// blacklistScanMaxIterations 估算黑名单大小时最多执行的 SCAN 次数
+	blacklistScanMaxIterations = 100
+	// blacklistScanBatchSize 每次 SCAN 的 COUNT 提示值，与最大次数共同限制单次估算的扫描量
+	blacklistScanBatchSize = 1000
+	// introspectWorkers 批量检查令牌时并行解析的最大协程数
+	introspectWorkers = 8
+)
+
 // authTokenService 是 AuthTokenService 接口的实现。
 type authTokenService struct {
-	tokenBlackRepo redis.TokenBlackRepo           // tokenBlackRepo: JTI 黑名单仓库。
-	userRepo       mysql.UserRepository           // userRepo: 用户仓库，用于获取用户信息。
-	jwtUtil        dependencies.JWTTokenInterface // jwtUtil: JWT 工具，用于解析和生成令牌。
-	logger         *core.ZapLogger                // logger: 日志记录器。
+	tokenBlackRepo   redis.TokenBlackRepo           // tokenBlackRepo: JTI 黑名单仓库。
+	userRepo         mysql.UserRepository           // userRepo: 用户仓库，用于获取用户信息。
+	refreshTokenRepo mysql.RefreshTokenRepository   // refreshTokenRepo: 刷新令牌白名单仓库，仅在启用白名单时使用。
+	jwtUtil          dependencies.JWTTokenInterface // jwtUtil: JWT 工具，用于解析和生成令牌。
+	refreshWhitelist bool                           // refreshWhitelist: 是否启用数据库刷新令牌白名单。
+	db               *gorm.DB                       // db: 数据库连接，用于令牌轮换事务。
+	clock            utils.Clock                    // clock: 时间来源，判断刷新令牌白名单记录是否过期。
+	degrade          *dependencies.RedisDegradation // degrade: 记录黑名单查询失败 (安全关键功能，始终拒绝)。
+	auditRepo        mysql.AdminAuditRepository     // auditRepo: 审计日志仓库，记录管理员移出黑名单的操作。
+	logger           *core.ZapLogger                // logger: 日志记录器。
+
+	lastEstimate atomic.Pointer[redis.BlacklistSizeEstimate] // lastEstimate: 最近一次黑名单大小估算结果。
 }
 
 // NewAuthTokenService 创建一个新的 authTokenService 实例。
@@ -62,14 +206,26 @@ type authTokenService struct {
 func NewAuthTokenService(
 	tokenBlackRepo redis.TokenBlackRepo,
 	userRepo mysql.UserRepository,
+	refreshTokenRepo mysql.RefreshTokenRepository,
 	jwtUtil dependencies.JWTTokenInterface,
+	refreshWhitelist bool,
+	db *gorm.DB,
+	clock utils.Clock,
+	degrade *dependencies.RedisDegradation,
+	auditRepo mysql.AdminAuditRepository,
 	logger *core.ZapLogger, // 注入 logger
 ) AuthTokenService { // 返回接口类型
 	return &authTokenService{ // 返回结构体指针
-		tokenBlackRepo: tokenBlackRepo,
-		userRepo:       userRepo,
-		jwtUtil:        jwtUtil,
-		logger:         logger, // 存储 logger
+		tokenBlackRepo:   tokenBlackRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		jwtUtil:          jwtUtil,
+		refreshWhitelist: refreshWhitelist,
+		db:               db,
+		clock:            clock,
+		degrade:          degrade,
+		auditRepo:        auditRepo,
+		logger:           logger, // 存储 logger
 	}
 }
 
@@ -97,11 +253,24 @@ func (s *authTokenService) Logout(ctx context.Context, tokenToRevoke string) err
 		// }
 	}
 
+	// 启用白名单时，同时将该 JTI 标记为已使用，即使 Redis 黑名单写入失败也无法再用于刷新
+	if s.refreshWhitelist {
+		if _, err := s.refreshTokenRepo.ConsumeRefreshToken(ctx, s.db, claims.ID, s.clock.Now()); err != nil {
+			s.logger.Error("退出登录时标记刷新令牌白名单记录失败",
+				zap.String("operation", operation),
+				zap.String("jti", claims.ID),
+				zap.String("userID", claims.UserID),
+				zap.Error(err),
+			)
+		}
+	}
+
 	// 2. 计算令牌剩余的有效时间 (TTL)
 	//    将 JTI 加入黑名单时，设置的过期时间应等于令牌本身的剩余有效时间。
 	var ttl time.Duration
 	if claims.ExpiresAt != nil {
-		ttl = time.Until(claims.ExpiresAt.Time) // time.Until 自动处理已过期的情况 (返回负数或零)
+		// 过期后的时钟偏差容忍期内令牌仍可通过校验，黑名单需覆盖到容忍期结束
+		ttl = claims.ExpiresAt.Time.Sub(s.clock.Now()) + s.jwtUtil.Leeway() // 已完全失效时为负数或零
 	} else {
 		// 如果令牌没有过期时间（不符合规范，但做防御性处理），可以设置一个默认的较短过期时间
 		// 或者直接报错。这里我们选择记录警告并跳过黑名单（因为它没有明确的失效时间点）。
@@ -160,24 +329,29 @@ func (s *authTokenService) RefreshToken(ctx context.Context, refreshToken string
 			zap.String("operation", operation),
 			zap.Error(err),
 		)
-		// 返回明确的业务错误
-		return emptyTokenPair, errors.New("无效的刷新令牌")
+		// 返回带分类原因的业务错误 (提示文案不包含具体原因)
+		return emptyTokenPair, newRefreshTokenRejected(err)
 	}
 	// 从 Claims 中获取 JTI 和 UserID
 	jti := claims.ID
 	userID := claims.UserID
 
 	// 2. 检查 Refresh Token 的 JTI 是否在黑名单中
+	//    启用白名单时以数据库记录为准，Redis 不可用不阻断刷新（后续的原子消费会拒绝已使用的令牌）
 	isBlacklisted, err := s.tokenBlackRepo.IsJtiBlacklisted(ctx, jti)
 	if err != nil {
+		s.degrade.AllowOnFailure(dependencies.RedisFeatureTokenBlacklist, err)
 		// 检查黑名单时发生错误
 		s.logger.Error("检查 JTI 黑名单失败",
 			zap.String("operation", operation),
 			zap.String("jti", jti),
 			zap.String("userID", userID),
+			zap.Bool("refreshWhitelist", s.refreshWhitelist),
 			zap.Error(err),
 		)
-		return emptyTokenPair, commonerrors.ErrSystemError
+		if !s.refreshWhitelist {
+			return emptyTokenPair, commonerrors.ErrSystemError
+		}
 	}
 	if isBlacklisted {
 		// JTI 在黑名单中，表示此 Refresh Token 已被吊销（例如，用户已退出登录）
@@ -186,7 +360,7 @@ func (s *authTokenService) RefreshToken(ctx context.Context, refreshToken string
 			zap.String("jti", jti),
 			zap.String("userID", userID),
 		)
-		return emptyTokenPair, errors.New("刷新令牌已失效") // 返回业务错误
+		return emptyTokenPair, newRefreshTokenRejected(ErrTokenRevoked) // 返回业务错误
 	}
 
 	// 3. 获取最新的用户信息
@@ -239,11 +413,44 @@ func (s *authTokenService) RefreshToken(ctx context.Context, refreshToken string
 		return emptyTokenPair, commonerrors.ErrSystemError
 	}
 
+	// 5.1 启用白名单时，在同一事务中原子地消费旧 JTI 并登记新 JTI
+	//     并发使用同一个 Refresh Token 时只有一个请求能消费成功，其余请求被拒绝。
+	if s.refreshWhitelist {
+		errRefreshTokenConsumed := newRefreshTokenRejected(ErrTokenRevoked)
+		txErr := s.db.Transaction(func(tx *gorm.DB) error {
+			consumed, err := s.refreshTokenRepo.ConsumeRefreshToken(ctx, tx, jti, s.clock.Now())
+			if err != nil {
+				return err
+			}
+			if !consumed {
+				return errRefreshTokenConsumed
+			}
+			return s.saveRefreshToken(ctx, tx, newRefreshToken)
+		})
+		if txErr != nil {
+			if errors.Is(txErr, errRefreshTokenConsumed) {
+				s.logger.Warn("刷新令牌不在白名单中或已被使用",
+					zap.String("operation", operation),
+					zap.String("jti", jti),
+					zap.String("userID", userID),
+				)
+				return emptyTokenPair, txErr
+			}
+			s.logger.Error("刷新令牌白名单轮换事务失败",
+				zap.String("operation", operation),
+				zap.String("jti", jti),
+				zap.String("userID", userID),
+				zap.Error(txErr),
+			)
+			return emptyTokenPair, commonerrors.ErrSystemError
+		}
+	}
+
 	// 6. 将旧的 Refresh Token 加入黑名单
 	//    计算旧 Refresh Token 的剩余 TTL
 	var oldTokenTTL time.Duration
 	if claims.ExpiresAt != nil {
-		oldTokenTTL = time.Until(claims.ExpiresAt.Time)
+		oldTokenTTL = claims.ExpiresAt.Time.Sub(s.clock.Now()) + s.jwtUtil.Leeway() // 覆盖时钟偏差容忍期
 	}
 	// 只有当旧 Token 还有剩余时间时才加入黑名单
 	if oldTokenTTL > 0 {
@@ -281,3 +488,291 @@ func (s *authTokenService) RefreshToken(ctx context.Context, refreshToken string
 	}
 	return newTokenPair, nil
 }
+
+// IssueRefreshToken 实现接口方法，签发并（按需）登记 Refresh Token。
+func (s *authTokenService) IssueRefreshToken(ctx context.Context, userID string, platform enums.Platform) (string, error) {
+	refreshToken, err := s.jwtUtil.GenerateRefreshToken(userID, platform)
+	if err != nil {
+		return "", fmt.Errorf("AuthTokenService.IssueRefreshToken: 生成刷新令牌失败: %w", err)
+	}
+	if !s.refreshWhitelist {
+		return refreshToken, nil
+	}
+	if err := s.saveRefreshToken(ctx, s.db, refreshToken); err != nil {
+		return "", err
+	}
+	// 顺带清理该用户已过期的记录，失败不影响签发
+	if err := s.refreshTokenRepo.DeleteExpiredByUserID(ctx, s.db, userID, s.clock.Now()); err != nil {
+		s.logger.Warn("清理过期刷新令牌记录失败",
+			zap.String("operation", "AuthTokenService.IssueRefreshToken"),
+			zap.String("userID", userID),
+			zap.Error(err),
+		)
+	}
+	return refreshToken, nil
+}
+
+// saveRefreshToken 解析刚签发的 Refresh Token，将其 JTI 与过期时间写入白名单。
+func (s *authTokenService) saveRefreshToken(ctx context.Context, db *gorm.DB, refreshToken string) error {
+	claims, err := s.jwtUtil.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return fmt.Errorf("AuthTokenService.saveRefreshToken: 解析新签发的刷新令牌失败: %w", err)
+	}
+	if claims.ExpiresAt == nil {
+		return fmt.Errorf("AuthTokenService.saveRefreshToken: 刷新令牌缺少过期时间声明 (JTI: %s)", claims.ID)
+	}
+	return s.refreshTokenRepo.CreateRefreshToken(ctx, db, &entities.RefreshToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		Platform:  claims.Platform,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+}
+
+// IntrospectToken 实现接口方法，检查令牌当前是否可用。
+func (s *authTokenService) IntrospectToken(ctx context.Context, tokenString string, refresh bool) (*vo.TokenIntrospectionVO, error) {
+	const operation = "AuthTokenService.IntrospectToken"
+
+	claims, rejected := s.parseForIntrospection(tokenString, refresh)
+	if rejected != nil {
+		return rejected, nil
+	}
+
+	isBlacklisted, err := s.tokenBlackRepo.IsJtiBlacklisted(ctx, claims.ID)
+	if err != nil {
+		s.degrade.AllowOnFailure(dependencies.RedisFeatureTokenBlacklist, err)
+		s.logger.Error("令牌检查时查询 JTI 黑名单失败", zap.String("operation", operation), zap.String("jti", claims.ID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if isBlacklisted {
+		return &vo.TokenIntrospectionVO{Active: false, Reason: projectEnums.TokenRejectRevoked}, nil
+	}
+	return activeIntrospection(claims), nil
+}
+
+// IntrospectTokens 实现接口方法，批量检查令牌。
+func (s *authTokenService) IntrospectTokens(ctx context.Context, requests []dto.IntrospectTokenRequest) ([]*vo.TokenIntrospectionVO, error) {
+	const operation = "AuthTokenService.IntrospectTokens"
+
+	// 1. 并行解析：解析只做签名与声明校验，是纯 CPU 操作，用固定数量的协程处理即可
+	results := make([]*vo.TokenIntrospectionVO, len(requests))
+	claimsList := make([]*dependencies.CustomClaims, len(requests))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(introspectWorkers, len(requests)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				req := requests[i]
+				claimsList[i], results[i] = s.parseForIntrospection(req.Token, req.TokenTypeHint == "refresh_token")
+			}
+		}()
+	}
+	for i := range requests {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// 2. 对解析成功的令牌做一次批量黑名单查询
+	var pending []int
+	var jtis []string
+	for i, claims := range claimsList {
+		if claims != nil {
+			pending = append(pending, i)
+			jtis = append(jtis, claims.ID)
+		}
+	}
+	blacklisted, err := s.tokenBlackRepo.AreJtisBlacklisted(ctx, jtis)
+	if err != nil {
+		s.logger.Error("批量令牌检查时查询 JTI 黑名单失败", zap.String("operation", operation), zap.Int("count", len(jtis)), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	for _, i := range pending {
+		if blacklisted[claimsList[i].ID] {
+			results[i] = &vo.TokenIntrospectionVO{Active: false, Reason: projectEnums.TokenRejectRevoked}
+		} else {
+			results[i] = activeIntrospection(claimsList[i])
+		}
+	}
+	return results, nil
+}
+
+// parseForIntrospection 按令牌类型解析令牌；解析失败时返回带粗粒度原因的检查结果，成功时返回声明。
+func (s *authTokenService) parseForIntrospection(tokenString string, refresh bool) (*dependencies.CustomClaims, *vo.TokenIntrospectionVO) {
+	const operation = "AuthTokenService.parseForIntrospection"
+
+	parse := s.jwtUtil.ParseAccessToken
+	if refresh {
+		parse = s.jwtUtil.ParseRefreshToken
+	}
+	claims, err := parse(tokenString)
+	if err != nil {
+		reason := RejectionReasonOf(err)
+		s.logger.Info("令牌检查未通过", zap.String("operation", operation), zap.Bool("refresh", refresh), zap.String("reason", string(reason)), zap.Error(err))
+		return nil, &vo.TokenIntrospectionVO{Active: false, Reason: reason}
+	}
+	return claims, nil
+}
+
+// activeIntrospection 由已通过全部检查的令牌声明构造“可用”的检查结果。
+func activeIntrospection(claims *dependencies.CustomClaims) *vo.TokenIntrospectionVO {
+	result := &vo.TokenIntrospectionVO{
+		Active:   true,
+		UserID:   claims.UserID,
+		Platform: string(claims.Platform),
+	}
+	if claims.ExpiresAt != nil {
+		expiresAt := claims.ExpiresAt.Time
+		result.ExpiresAt = &expiresAt
+	}
+	return result
+}
+
+// DescribeAccessToken 实现接口方法，返回 Access Token 中的声明。
+func (s *authTokenService) DescribeAccessToken(ctx context.Context, accessToken string) (*vo.TokenClaimsVO, error) {
+	const operation = "AuthTokenService.DescribeAccessToken"
+
+	claims, err := s.jwtUtil.ParseAccessToken(accessToken)
+	if err != nil {
+		s.logger.Info("解析 Access Token 失败", zap.String("operation", operation), zap.Error(err))
+		reason := RejectionReasonOf(err)
+		message := "无效的访问令牌"
+		if reason == projectEnums.TokenRejectExpired {
+			message = "访问令牌已过期"
+		}
+		return nil, &TokenRejectedError{Reason: reason, Message: message, Cause: err}
+	}
+
+	isBlacklisted, err := s.tokenBlackRepo.IsJtiBlacklisted(ctx, claims.ID)
+	if err != nil {
+		s.degrade.AllowOnFailure(dependencies.RedisFeatureTokenBlacklist, err)
+		s.logger.Error("查询 Access Token JTI 黑名单失败", zap.String("operation", operation), zap.String("jti", claims.ID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if isBlacklisted {
+		s.logger.Warn("使用已吊销的 Access Token 查询令牌声明", zap.String("operation", operation), zap.String("jti", claims.ID), zap.String("userID", claims.UserID))
+		return nil, &TokenRejectedError{Reason: projectEnums.TokenRejectRevoked, Message: "访问令牌已失效", Cause: ErrTokenRevoked}
+	}
+
+	result := &vo.TokenClaimsVO{
+		UserID:   claims.UserID,
+		Role:     claims.Role,
+		Status:   claims.Status,
+		Platform: string(claims.Platform),
+	}
+	if claims.IssuedAt != nil {
+		issuedAt := claims.IssuedAt.Time
+		result.IssuedAt = &issuedAt
+	}
+	if claims.ExpiresAt != nil {
+		expiresAt := claims.ExpiresAt.Time
+		result.ExpiresAt = &expiresAt
+	}
+	return result, nil
+}
+
+// GetBlacklistStats 实现接口方法，估算黑名单大小并返回统计信息。
+func (s *authTokenService) GetBlacklistStats(ctx context.Context) (*vo.BlacklistStatsVO, error) {
+	const operation = "AuthTokenService.GetBlacklistStats"
+
+	estimate, err := s.tokenBlackRepo.EstimateSize(ctx, blacklistScanMaxIterations, blacklistScanBatchSize)
+	if err != nil {
+		s.logger.Error("估算令牌黑名单大小失败", zap.String("operation", operation), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	s.lastEstimate.Store(&estimate)
+
+	s.logger.Info("完成令牌黑名单大小估算",
+		zap.String("operation", operation),
+		zap.Int64("sizeEstimate", estimate.Count),
+		zap.Bool("exact", estimate.Exact),
+		zap.Int64("scannedKeys", estimate.ScannedKeys),
+	)
+	return s.GetBlacklistMetrics(), nil
+}
+
+// InspectBlacklistedJti 实现接口方法，查询单个 JTI 的黑名单状态。
+func (s *authTokenService) InspectBlacklistedJti(ctx context.Context, jti string) (*vo.BlacklistEntryVO, error) {
+	const operation = "AuthTokenService.InspectBlacklistedJti"
+
+	ttl, present, err := s.tokenBlackRepo.GetBlacklistTTL(ctx, jti)
+	if err != nil {
+		s.logger.Error("查询 JTI 黑名单状态失败", zap.String("operation", operation), zap.String("jti", jti), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	return &vo.BlacklistEntryVO{
+		JTI:         jti,
+		Blacklisted: present,
+		TTLSeconds:  int64(math.Ceil(ttl.Seconds())),
+	}, nil
+}
+
+// ClearBlacklistedJti 实现接口方法，将 JTI 移出黑名单。
+// 先写审计日志再删除，保证每一次移出操作都有记录；审计写入失败时不执行删除。
+func (s *authTokenService) ClearBlacklistedJti(ctx context.Context, actorID string, jti string, reason string) (*vo.BlacklistClearVO, error) {
+	const operation = "AuthTokenService.ClearBlacklistedJti"
+
+	ttl, present, err := s.tokenBlackRepo.GetBlacklistTTL(ctx, jti)
+	if err != nil {
+		s.logger.Error("移出黑名单前查询 JTI 状态失败", zap.String("operation", operation), zap.String("jti", jti), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if !present {
+		return &vo.BlacklistClearVO{JTI: jti, Removed: false}, nil
+	}
+
+	diff, err := json.Marshal(map[string]any{
+		"reason":      reason,
+		"ttl_seconds": int64(math.Ceil(ttl.Seconds())),
+	})
+	if err != nil {
+		s.logger.Error("序列化审计变更内容失败", zap.String("operation", operation), zap.String("jti", jti), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	if err := s.auditRepo.CreateAuditLog(ctx, s.db, &entities.AdminAuditLog{
+		ActorID:  actorID,
+		Action:   projectEnums.AuditActionClearBlacklistedJti,
+		TargetID: jti,
+		Diff:     string(diff),
+	}); err != nil {
+		s.logger.Error("写入移出黑名单审计日志失败，放弃移除", zap.String("operation", operation), zap.String("jti", jti), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	removed, err := s.tokenBlackRepo.RemoveJti(ctx, jti)
+	if err != nil {
+		s.logger.Error("将 JTI 移出黑名单失败", zap.String("operation", operation), zap.String("jti", jti), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+	s.logger.Warn("管理员已将 JTI 移出令牌黑名单",
+		zap.String("operation", operation),
+		zap.String("actorID", actorID),
+		zap.String("jti", jti),
+		zap.Bool("removed", removed),
+		zap.String("reason", reason),
+	)
+	return &vo.BlacklistClearVO{JTI: jti, Removed: removed}, nil
+}
+
+// GetBlacklistMetrics 实现接口方法，组合进程内计数与缓存的大小估算。
+func (s *authTokenService) GetBlacklistMetrics() *vo.BlacklistStatsVO {
+	counters := s.tokenBlackRepo.Counters()
+	stats := &vo.BlacklistStatsVO{
+		Added:  counters.Added,
+		Hits:   counters.Hits,
+		Misses: counters.Misses,
+	}
+	if checks := counters.Hits + counters.Misses; checks > 0 {
+		stats.HitRate = float64(counters.Hits) / float64(checks)
+	}
+	if estimate := s.lastEstimate.Load(); estimate != nil {
+		estimatedAt := estimate.EstimatedAt
+		stats.SizeEstimate = estimate.Count
+		stats.SizeExact = estimate.Exact
+		stats.ScannedKeys = estimate.ScannedKeys
+		stats.EstimatedAt = &estimatedAt
+	}
+	return stats
+}
@@ -0,0 +1,118 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	"github.com/Xushengqwer/go-common/models/enums"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/repository/redis"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// fakeTokenBlackRepo 内嵌接口，记录加入黑名单的 JTI 及其 TTL
+type fakeTokenBlackRepo struct {
+	redis.TokenBlackRepo
+	ttls map[string]time.Duration
+}
+
+func (r *fakeTokenBlackRepo) AddJtiToBlacklist(_ context.Context, jti string, ttl time.Duration) error {
+	r.ttls[jti] = ttl
+	return nil
+}
+
+// fakeRefreshTokenRepo 内嵌接口，记录清理过期记录时使用的当前时间
+type fakeRefreshTokenRepo struct {
+	mysql.RefreshTokenRepository
+	sweptAt []time.Time
+}
+
+func (r *fakeRefreshTokenRepo) DeleteExpiredBatch(_ context.Context, _ *gorm.DB, now time.Time, _ int) (int64, error) {
+	r.sweptAt = append(r.sweptAt, now)
+	return 0, nil
+}
+
+func TestLogoutBlacklistTTLFollowsClock(t *testing.T) {
+	const (
+		ttl    = 10 * time.Minute
+		leeway = 30 * time.Second
+	)
+	tests := []struct {
+		name    string
+		elapsed time.Duration // 签发后经过的时间
+		wantTTL time.Duration // 为 0 表示不应加入黑名单
+	}{
+		{name: "有效期内按剩余时间加容忍期拉黑", elapsed: 4 * time.Minute, wantTTL: 6*time.Minute + leeway},
+		{name: "过期但仍在容忍期内只拉黑剩余容忍期", elapsed: ttl + 10*time.Second, wantTTL: 20 * time.Second},
+		{name: "超过容忍期后无需拉黑", elapsed: ttl + leeway + time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := utils.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+			jwtUtil := dependencies.NewJWTUtility(&config.JWTConfig{
+				SecretKey:     "access-secret",
+				RefreshSecret: "refresh-secret",
+				Issuer:        "user_hub_test",
+				Leeway:        leeway,
+				DefaultTTL:    config.TokenTTLConfig{AccessTokenTTL: ttl, RefreshTokenTTL: ttl},
+			}, clock)
+			blackRepo := &fakeTokenBlackRepo{ttls: map[string]time.Duration{}}
+			svc := NewAuthTokenService(blackRepo, nil, nil, jwtUtil, false, nil, clock, nil, nil, newTestLogger(t))
+
+			refreshToken, err := jwtUtil.GenerateRefreshToken("u1", enums.PlatformApp)
+			if err != nil {
+				t.Fatalf("签发刷新令牌失败: %v", err)
+			}
+			clock.Advance(tt.elapsed)
+
+			if err := svc.Logout(context.Background(), refreshToken); err != nil {
+				t.Fatalf("Logout 失败: %v", err)
+			}
+			if tt.wantTTL == 0 {
+				if len(blackRepo.ttls) != 0 {
+					t.Errorf("已失效的令牌不应加入黑名单: %v", blackRepo.ttls)
+				}
+				return
+			}
+			if len(blackRepo.ttls) != 1 {
+				t.Fatalf("黑名单记录 = %v，期望 1 条", blackRepo.ttls)
+			}
+			for jti, got := range blackRepo.ttls {
+				if got != tt.wantTTL {
+					t.Errorf("JTI %s 的黑名单 TTL = %v，期望 %v", jti, got, tt.wantTTL)
+				}
+			}
+		})
+	}
+}
+
+func TestSweepOnceUsesClock(t *testing.T) {
+	clock := utils.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	repo := &fakeRefreshTokenRepo{}
+	sweeper := NewRefreshTokenSweeper(repo, config.SessionSweepConfig{}, nil, clock, newTestLogger(t))
+
+	clock.Advance(time.Hour)
+	if _, err := sweeper.SweepOnce(context.Background()); err != nil {
+		t.Fatalf("SweepOnce 失败: %v", err)
+	}
+	if want := clock.Now(); len(repo.sweptAt) != 1 || !repo.sweptAt[0].Equal(want) {
+		t.Errorf("清理使用的时间 = %v，期望 %v", repo.sweptAt, want)
+	}
+}
@@ -2,6 +2,10 @@ package userList
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
 	// 引入公共模块
 	"github.com/Xushengqwer/go-common/commonerrors"
 	"github.com/Xushengqwer/go-common/core" // 引入日志包
@@ -26,10 +30,33 @@ type UserListQueryService interface {
 	// 返回:
 	//  - []*vo.UserWithProfileVO: 用户及其Profile信息的视图对象列表。
 	//  - int64: 符合查询条件的总记录数。
-	//  - error: 操作过程中发生的任何错误，通常是系统错误。
+	//  - error: Fields 含有不允许投影的字段时返回包装了 ErrInvalidProjectionField 的错误，数据库失败时返回系统错误。
 	ListUsersWithProfile(ctx context.Context, dto *dto.UserQueryDTO) ([]*vo.UserWithProfileVO, int64, error)
+
+	// SearchUsers 按单一关键字组合搜索用户 (用户 ID、手机号、账号/邮箱精确匹配，昵称模糊匹配)。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - dto: 包含关键字与分页参数的搜索 DTO。
+	// 返回:
+	//  - []*vo.UserWithProfileVO: 去重后的用户及其Profile信息列表，精确命中的用户排在前面。
+	//  - int64: 命中总数，不超过 MaxSearchResults。
+	//  - error: 关键字过短时返回 ErrSearchQueryTooShort，数据库失败时返回系统错误。
+	SearchUsers(ctx context.Context, dto *dto.UserSearchDTO) ([]*vo.UserWithProfileVO, int64, error)
 }
 
+const (
+	// MinSearchQueryLength 搜索关键字的最小字符数，避免过短的关键字触发大范围的昵称模糊扫描
+	MinSearchQueryLength = 2
+	// MaxSearchResults 单次搜索可翻页的结果上限
+	MaxSearchResults = 200
+)
+
+// ErrSearchQueryTooShort 搜索关键字过短
+var ErrSearchQueryTooShort = errors.New("搜索关键字至少需要 2 个字符")
+
+// ErrInvalidProjectionField 查询请求的 fields 中包含不允许投影的字段
+var ErrInvalidProjectionField = errors.New("不支持的查询字段")
+
 // userListQueryService 是 UserListQueryService 接口的实现。
 type userListQueryService struct {
 	repo   mysql.JoinQuery // repo: 联合查询仓库，负责执行实际的数据库查询。
@@ -60,6 +87,14 @@ func (s *userListQueryService) ListUsersWithProfile(ctx context.Context, dto *dt
 		zap.Any("queryDTO", dto), // 记录查询参数，注意敏感信息处理（如果DTO中包含）
 	)
 
+	// 0. 校验字段投影：只允许仓库层白名单中的字段，避免客户端拼接任意列
+	for _, field := range dto.Fields {
+		if !mysql.IsUserProjectionField(field) {
+			s.logger.Warn("用户列表查询包含不支持的投影字段", zap.String("operation", operation), zap.String("field", field))
+			return nil, 0, fmt.Errorf("%w: %s", ErrInvalidProjectionField, field)
+		}
+	}
+
 	// 1. 直接调用仓库层的 ListUsersWithProfile 方法。
 	//    - 我们之前已重构仓库层，使其直接接收 dto.UserQueryDTO 并返回 []*vo.UserWithProfileVO。
 	//    - 因此，服务层不再需要进行 DTO 到仓库查询结构体的转换，也不再需要手动将仓库结果映射到 VO。
@@ -88,3 +123,29 @@ func (s *userListQueryService) ListUsersWithProfile(ctx context.Context, dto *dt
 	//      因此，这里无需手动处理这些字段的映射。
 	return results, total, nil
 }
+
+// SearchUsers 实现接口方法，执行用户的组合关键字搜索。
+func (s *userListQueryService) SearchUsers(ctx context.Context, dto *dto.UserSearchDTO) ([]*vo.UserWithProfileVO, int64, error) {
+	const operation = "UserListQueryService.SearchUsers"
+
+	if utf8.RuneCountInString(dto.Q) < MinSearchQueryLength {
+		return nil, 0, ErrSearchQueryTooShort
+	}
+
+	results, total, err := s.repo.SearchUsersWithProfile(ctx, dto.Q, dto.Page, dto.PageSize, MaxSearchResults)
+	if err != nil {
+		s.logger.Error("调用仓库搜索用户失败",
+			zap.String("operation", operation),
+			zap.Int("queryLength", utf8.RuneCountInString(dto.Q)), // 关键字可能是手机号等敏感信息，只记录长度
+			zap.Error(err),
+		)
+		return nil, 0, commonerrors.ErrSystemError
+	}
+
+	s.logger.Info("成功搜索用户",
+		zap.String("operation", operation),
+		zap.Int64("totalRecords", total),
+		zap.Int("returnedRecords", len(results)),
+	)
+	return results, total, nil
+}
@@ -0,0 +1,34 @@
+package userManage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
+
+	"gorm.io/gorm"
+)
+
+// fieldChange 描述审计日志中单个字段的变更，From/To 为 nil 表示字段不存在（创建或删除）
+type fieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// recordAudit 写入一条管理员审计日志。
+// - db 通常传入业务变更所在的事务对象，保证审计记录与变更同时提交或回滚。
+// - diff 通常为 map[string]fieldChange；需要附带原因等说明时可传入包含 fieldChange 的任意可序列化结构。
+func (s *userService) recordAudit(ctx context.Context, db *gorm.DB, actorID string, action enums.AuditAction, targetID string, diff any) error {
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("序列化审计变更内容失败: %w", err)
+	}
+	return s.auditRepo.CreateAuditLog(ctx, db, &entities.AdminAuditLog{
+		ActorID:  actorID,
+		Action:   action,
+		TargetID: targetID,
+		Diff:     string(diffJSON),
+	})
+}
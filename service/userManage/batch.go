@@ -0,0 +1,120 @@
+package userManage
+
+import (
+	"context"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/enums"
+	"github.com/Xushengqwer/user_hub/models/vo"
+)
+
+// batchRoleChunkSize 批量设置角色时每个事务处理的用户数
+const batchRoleChunkSize = 100
+
+// BatchSetRole 实现接口方法，批量设置用户角色。
+func (s *userService) BatchSetRole(ctx context.Context, actorID string, dto *dto.BatchRoleDTO) (*vo.BatchRoleResultVO, error) {
+	const operation = "UserManageService.BatchSetRole"
+	role := *dto.Role
+
+	// 1. 去重并保持请求顺序
+	seen := make(map[string]struct{}, len(dto.UserIDs))
+	userIDs := make([]string, 0, len(dto.UserIDs))
+	for _, id := range dto.UserIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		userIDs = append(userIDs, id)
+	}
+
+	report := &vo.BatchRoleResultVO{Role: role, Results: make([]vo.BatchItemResultVO, 0, len(userIDs)), DryRun: dto.DryRun}
+	for start := 0; start < len(userIDs); start += batchRoleChunkSize {
+		chunk := userIDs[start:min(start+batchRoleChunkSize, len(userIDs))]
+
+		// 2. 查询本批用户，区分不存在、无需变更与需要更新
+		users, err := s.userRepo.ListUsersByIDs(ctx, chunk)
+		if err != nil {
+			s.logger.Error("批量设置角色时查询用户失败", zap.String("operation", operation), zap.Int("chunkStart", start), zap.Error(err))
+			return nil, commonerrors.ErrSystemError
+		}
+		currentRoles := make(map[string]string, len(users))
+		for _, u := range users {
+			currentRoles[u.UserID] = u.UserRole.String()
+		}
+		var toUpdate []string
+		outcome := make(map[string]string, len(chunk))
+		for _, id := range chunk {
+			from, ok := currentRoles[id]
+			switch {
+			case !ok:
+				outcome[id] = vo.BatchItemNotFound
+			case from == role.String():
+				outcome[id] = vo.BatchItemUnchanged
+			default:
+				toUpdate = append(toUpdate, id)
+			}
+		}
+
+		// 3. 本批变更与审计日志在同一事务中提交；预演模式在写入前短路，只报告将被更新的用户
+		if len(toUpdate) > 0 && dto.DryRun {
+			for _, id := range toUpdate {
+				outcome[id] = vo.BatchItemUpdated
+			}
+		} else if len(toUpdate) > 0 {
+			err = s.db.Transaction(func(tx *gorm.DB) error {
+				if err := s.userRepo.UpdateUserRoles(ctx, tx, toUpdate, role); err != nil {
+					return err
+				}
+				for _, id := range toUpdate {
+					diff := map[string]fieldChange{"user_role": {From: currentRoles[id], To: role.String()}}
+					if err := s.recordAudit(ctx, tx, actorID, enums.AuditActionBatchRole, id, diff); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			result := vo.BatchItemUpdated
+			if err != nil {
+				s.logger.Error("批量设置角色事务失败", zap.String("operation", operation), zap.Int("chunkStart", start), zap.Int("count", len(toUpdate)), zap.Error(err))
+				result = vo.BatchItemFailed
+			} else {
+				// 角色变更事件：审计日志是持久记录，这里额外输出结构化日志便于日志管道订阅
+				s.logger.Info("用户角色已批量变更", zap.String("operation", operation), zap.String("actorID", actorID), zap.String("role", role.String()), zap.Strings("userIDs", toUpdate))
+			}
+			for _, id := range toUpdate {
+				outcome[id] = result
+			}
+		}
+
+		// 4. 按请求顺序汇总本批结果
+		for _, id := range chunk {
+			switch outcome[id] {
+			case vo.BatchItemUpdated:
+				report.Updated++
+			case vo.BatchItemUnchanged:
+				report.Unchanged++
+			case vo.BatchItemNotFound:
+				report.NotFound++
+			case vo.BatchItemFailed:
+				report.Failed++
+			}
+			report.Results = append(report.Results, vo.BatchItemResultVO{UserID: id, Result: outcome[id]})
+		}
+	}
+
+	s.logger.Info("批量设置用户角色完成",
+		zap.String("operation", operation),
+		zap.String("actorID", actorID),
+		zap.String("role", role.String()),
+		zap.Int("updated", report.Updated),
+		zap.Int("unchanged", report.Unchanged),
+		zap.Int("notFound", report.NotFound),
+		zap.Int("failed", report.Failed),
+		zap.Bool("dryRun", report.DryRun),
+	)
+	return report, nil
+}
@@ -0,0 +1,143 @@
+package userManage
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/service/identity"
+	"github.com/Xushengqwer/user_hub/utils"
+)
+
+// ErrDetailUserNotFound 查询详情的用户不存在 (或已删除) 时返回
+var ErrDetailUserNotFound = errors.New("指定的用户不存在")
+
+// AdminUserDetailService 定义了管理员查看单个用户聚合详情的只读服务接口。
+// 设计目的:
+// - 管理后台查看用户时原本需要分别调用用户、资料、身份等多个接口，由服务端一次聚合返回。
+// - 确认用户存在后，资料、登录方式与会话数三组查询并发执行，减少接口耗时。
+type AdminUserDetailService interface {
+	// GetUserDetail 获取指定用户的聚合详情 (权限由调用方校验)。
+	// 参数:
+	//  - ctx: 请求上下文。
+	//  - userID: 要查询的用户 ID。
+	// 返回:
+	//  - *vo.AdminUserDetailVO: 聚合后的用户详情；资料不存在时 Profile 为 null。
+	//  - error: 用户不存在返回 ErrDetailUserNotFound，其他失败返回 commonerrors.ErrSystemError。
+	GetUserDetail(ctx context.Context, userID string) (*vo.AdminUserDetailVO, error)
+}
+
+// adminUserDetailService 是 AdminUserDetailService 接口的实现。
+type adminUserDetailService struct {
+	userRepo         mysql.UserRepository         // userRepo: 用户仓库，读取核心信息。
+	profileRepo      mysql.ProfileRepository      // profileRepo: 资料仓库。
+	identityService  identity.UserIdentityService // identityService: 身份服务，读取脱敏后的登录方式。
+	refreshTokenRepo mysql.RefreshTokenRepository // refreshTokenRepo: 刷新令牌白名单仓库，统计有效会话。
+	refreshWhitelist bool                         // refreshWhitelist: 是否启用刷新令牌白名单，未启用时无法统计会话数。
+	clock            utils.Clock                  // clock: 时间来源，判断会话是否有效。
+	logger           *core.ZapLogger              // logger: 日志记录器。
+}
+
+// NewAdminUserDetailService 创建一个新的 adminUserDetailService 实例。
+// 参数:
+//   - refreshWhitelist: 对应 JWTConfig.RefreshWhitelist；为 false 时详情中的有效会话数返回 null。
+func NewAdminUserDetailService(
+	userRepo mysql.UserRepository,
+	profileRepo mysql.ProfileRepository,
+	identityService identity.UserIdentityService,
+	refreshTokenRepo mysql.RefreshTokenRepository,
+	refreshWhitelist bool,
+	clock utils.Clock,
+	logger *core.ZapLogger,
+) AdminUserDetailService {
+	return &adminUserDetailService{
+		userRepo:         userRepo,
+		profileRepo:      profileRepo,
+		identityService:  identityService,
+		refreshTokenRepo: refreshTokenRepo,
+		refreshWhitelist: refreshWhitelist,
+		clock:            clock,
+		logger:           logger,
+	}
+}
+
+// GetUserDetail 实现接口方法。
+func (s *adminUserDetailService) GetUserDetail(ctx context.Context, userID string) (*vo.AdminUserDetailVO, error) {
+	const operation = "AdminUserDetailService.GetUserDetail"
+
+	// 1. 核心信息；用户不存在时无需继续查询
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			return nil, ErrDetailUserNotFound
+		}
+		s.logger.Error("查询用户失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	// 2. 资料、登录方式、会话数相互独立，并发查询
+	var (
+		wg           sync.WaitGroup
+		profile      *entities.UserProfile
+		profileErr   error
+		loginMethods []*vo.LoginMethodVO
+		methodsErr   error
+		sessions     *int64
+		sessionsErr  error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		profile, profileErr = s.profileRepo.GetProfileByUserID(ctx, userID)
+	}()
+	go func() {
+		defer wg.Done()
+		loginMethods, methodsErr = s.identityService.GetLoginMethodsByUserID(ctx, userID)
+	}()
+	if s.refreshWhitelist {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count, err := s.refreshTokenRepo.CountActiveByUserID(ctx, userID, s.clock.Now())
+			sessions, sessionsErr = &count, err
+		}()
+	}
+	wg.Wait()
+
+	if errors.Is(profileErr, commonerrors.ErrRepoNotFound) {
+		profile = nil
+	} else if profileErr != nil {
+		s.logger.Error("查询用户资料失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(profileErr))
+		return nil, commonerrors.ErrSystemError
+	}
+	if methodsErr != nil {
+		return nil, methodsErr
+	}
+	if sessionsErr != nil {
+		s.logger.Error("统计有效会话失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(sessionsErr))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	// 3. 组装详情，最近登录由各登录方式的最近使用时间推算
+	detail := &vo.AdminUserDetailVO{
+		User:           userEntityToVO(user),
+		Profile:        userProfileEntityToVO(profile),
+		Identities:     loginMethods,
+		ActiveSessions: sessions,
+	}
+	for _, method := range loginMethods {
+		if method.LastUsedAt != nil && (detail.LastLoginAt == nil || method.LastUsedAt.After(*detail.LastLoginAt)) {
+			identityType := method.IdentityType
+			detail.LastLoginAt = method.LastUsedAt
+			detail.LastLoginMethod = &identityType
+		}
+	}
+	return detail, nil
+}
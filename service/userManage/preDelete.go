@@ -0,0 +1,57 @@
+package userManage
+
+import (
+	"context"
+	"fmt"
+)
+
+// PreDeleteCheck 定义了删除用户前的前置检查钩子。
+// 设计目的:
+//   - 在更大的系统中，其他服务可能禁止删除仍拥有内容 (帖子、订单等) 的用户；
+//     通过该接口接入检查逻辑，user_hub 无需直接依赖这些服务。
+//   - DeleteUser 在开启事务前调用；返回 *DeleteVetoError 表示否决删除，返回其他错误表示检查本身失败。
+type PreDeleteCheck interface {
+	// CheckBeforeDelete 检查是否允许删除指定用户。
+	// 返回:
+	//  - nil: 允许删除。
+	//  - *DeleteVetoError: 否决删除，Reason 会返回给调用方。
+	//  - 其他 error: 检查失败 (如依赖服务不可用)，删除同样不会执行。
+	CheckBeforeDelete(ctx context.Context, userID string) error
+}
+
+// PreDeleteCheckFunc 允许将普通函数作为 PreDeleteCheck 使用。
+type PreDeleteCheckFunc func(ctx context.Context, userID string) error
+
+// CheckBeforeDelete 实现 PreDeleteCheck 接口。
+func (f PreDeleteCheckFunc) CheckBeforeDelete(ctx context.Context, userID string) error {
+	return f(ctx, userID)
+}
+
+// NoopPreDeleteCheck 默认的前置检查，总是允许删除。
+var NoopPreDeleteCheck PreDeleteCheck = PreDeleteCheckFunc(func(context.Context, string) error { return nil })
+
+// ChainPreDeleteChecks 将多个前置检查按顺序组合，遇到第一个否决或错误即返回。
+func ChainPreDeleteChecks(checks ...PreDeleteCheck) PreDeleteCheck {
+	return PreDeleteCheckFunc(func(ctx context.Context, userID string) error {
+		for _, check := range checks {
+			if check == nil {
+				continue
+			}
+			if err := check.CheckBeforeDelete(ctx, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteVetoError 表示删除被前置检查否决，属于业务错误。
+type DeleteVetoError struct {
+	Source string // 否决来源 (如检查服务名)，用于日志
+	Reason string // 否决原因，返回给调用方
+}
+
+// Error 实现 error 接口。
+func (e *DeleteVetoError) Error() string {
+	return fmt.Sprintf("用户当前不允许删除: %s", e.Reason)
+}
@@ -0,0 +1,101 @@
+package userManage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Xushengqwer/go-common/commonerrors"
+	"github.com/Xushengqwer/go-common/core"
+	"go.uber.org/zap"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// maxPreDeleteResponseSize 前置检查响应体的读取上限
+const maxPreDeleteResponseSize = 64 * 1024
+
+// httpPreDeleteCheck 通过 HTTP 调用外部服务完成删除前置检查。
+// 请求: POST {check_url}，JSON 请求体 {"user_id": "..."}。
+// 响应: HTTP 200，JSON 响应体 {"allowed": bool, "reason": "..."}；allowed 为 false 时否决删除。
+type httpPreDeleteCheck struct {
+	cfg    config.DeleteGuardConfig // cfg: 检查地址、超时与失败策略。
+	client *http.Client             // client: 带超时的 HTTP 客户端。
+	logger *core.ZapLogger          // logger: 日志记录器。
+}
+
+// preDeleteCheckRequest 前置检查请求体
+type preDeleteCheckRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// preDeleteCheckResponse 前置检查响应体
+type preDeleteCheckResponse struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+}
+
+// NewPreDeleteCheckFromConfig 按配置创建删除前置检查；未配置 CheckURL 时返回 NoopPreDeleteCheck。
+func NewPreDeleteCheckFromConfig(cfg config.DeleteGuardConfig, logger *core.ZapLogger) PreDeleteCheck {
+	if cfg.CheckURL == "" {
+		return NoopPreDeleteCheck
+	}
+	return &httpPreDeleteCheck{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.TimeoutOrDefault()},
+		logger: logger,
+	}
+}
+
+// CheckBeforeDelete 实现 PreDeleteCheck 接口。
+func (h *httpPreDeleteCheck) CheckBeforeDelete(ctx context.Context, userID string) error {
+	const operation = "httpPreDeleteCheck.CheckBeforeDelete"
+
+	result, err := h.call(ctx, userID)
+	if err != nil {
+		if h.cfg.FailOpen {
+			h.logger.Warn("删除前置检查失败，按 fail_open 配置放行", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			return nil
+		}
+		return fmt.Errorf("删除前置检查失败: %v: %w", err, commonerrors.ErrThirdPartyServiceError)
+	}
+	if !result.Allowed {
+		reason := result.Reason
+		if reason == "" {
+			reason = "用户仍有关联内容"
+		}
+		return &DeleteVetoError{Source: h.cfg.CheckURL, Reason: reason}
+	}
+	return nil
+}
+
+// call 发起一次检查请求并解析响应
+func (h *httpPreDeleteCheck) call(ctx context.Context, userID string) (*preDeleteCheckResponse, error) {
+	body, err := json.Marshal(preDeleteCheckRequest{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.cfg.CheckURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("构造请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求检查服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("检查服务返回非预期状态码: %d", resp.StatusCode)
+	}
+
+	var result preDeleteCheckResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPreDeleteResponseSize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析检查服务响应失败: %w", err)
+	}
+	return &result, nil
+}
@@ -8,13 +8,18 @@ import (
 	// 引入公共模块
 	"github.com/Xushengqwer/go-common/commonerrors"
 	"github.com/Xushengqwer/go-common/core"
+	commonenums "github.com/Xushengqwer/go-common/models/enums"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
 	"github.com/Xushengqwer/user_hub/models/dto"
 	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/enums"
 	"github.com/Xushengqwer/user_hub/models/vo"
 	"github.com/Xushengqwer/user_hub/repository/mysql"
+	"github.com/Xushengqwer/user_hub/service/file"
 
 	"gorm.io/gorm"
 )
@@ -27,19 +32,21 @@ type UserManageService interface {
 	// CreateUser 创建一个新的核心用户记录。通常由其他服务（如注册服务）调用。
 	// 参数:
 	//  - ctx: 请求上下文。
+	//  - actorID: 执行操作的管理员用户 ID，用于写入审计日志。
 	//  - dto: 包含新用户角色和状态的 DTO。用户 ID 由服务内部生成。
 	// 返回:
 	//  - *vo.UserVO: 成功创建的用户信息的视图对象。
 	//  - error: 操作过程中发生的任何错误。
-	CreateUser(ctx context.Context, dto *dto.CreateUserDTO) (*vo.UserVO, error)
+	CreateUser(ctx context.Context, actorID string, dto *dto.CreateUserDTO) (*vo.UserVO, error)
 
 	// GetUserByID 根据用户 ID 检索核心用户信息。
 	// 参数:
 	//  - userID: 要查询的用户 ID。
+	//  - includeDeleted: 为 true 时包含已软删除的用户，并在结果中返回删除时间 (仅供管理员使用，权限由调用方校验)。
 	// 返回:
 	//  - *vo.UserVO: 用户信息的视图对象。如果用户不存在，返回业务错误。
 	//  - error: 操作过程中发生的任何错误。
-	GetUserByID(ctx context.Context, userID string) (*vo.UserVO, error)
+	GetUserByID(ctx context.Context, userID string, includeDeleted bool) (*vo.UserVO, error)
 
 	// GetUserProfileByAdmin (管理员权限) 根据用户 ID 检索指定用户的详细资料信息。
 	// 参数:
@@ -52,52 +59,119 @@ type UserManageService interface {
 
 	// UpdateUser 更新指定用户的核心信息（目前主要是角色和状态）。
 	// 参数:
+	//  - actorID: 执行操作的管理员用户 ID，用于写入审计日志。
 	//  - userID: 要更新的用户 ID。
 	//  - dto: 包含待更新字段的 DTO。服务会根据 DTO 中提供的非零值进行更新。
 	// 返回:
 	//  - *vo.UserVO: 更新后的用户信息的视图对象。
+	//  - bool: 是否有字段发生了实际变化；为 false 时未写库也未写审计日志，返回的是现有信息。
 	//  - error: 操作过程中发生的任何错误。
-	UpdateUser(ctx context.Context, userID string, dto *dto.UpdateUserDTO) (*vo.UserVO, error)
+	UpdateUser(ctx context.Context, actorID string, userID string, dto *dto.UpdateUserDTO) (*vo.UserVO, bool, error)
 
-	// DeleteUser （软）删除指定用户及其所有关联的身份和资料信息。
-	// 此操作将在一个数据库事务中执行，以确保原子性。
+	// DeleteUser 删除指定用户及其所有关联的身份和资料信息。
+	// 此操作将在一个数据库事务中执行，以确保原子性。软删除与硬删除的级联范围相同，硬删除在事务提交后还会删除存储中的头像
+	// (包括此前已被软删除的用户，头像地址从已软删除的资料中读取)。
+	// 刷新令牌 (refresh_tokens)、API 密钥 (api_keys) 与密码历史 (password_history) 不在级联范围内，两种方式都会保留：
+	// 已删除用户无法通过令牌刷新或密钥认证 (均会校验用户是否存在)；刷新令牌由过期清理任务删除，API 密钥与密码历史需人工清理。
 	// 参数:
+	//  - actorID: 执行操作的管理员用户 ID，用于写入审计日志。
 	//  - userID: 要删除的用户 ID。
+	//  - dryRun: 为 true 时只校验并报告将会发生的变更，不开启事务、不写审计日志。
+	//  - mode: 本次请求指定的删除方式 (config.UserDeleteModeSoft / UserDeleteModeHard)，为空时使用配置的默认方式。
 	// 返回:
-	//  - error: 操作过程中发生的任何错误。
-	DeleteUser(ctx context.Context, userID string) error
+	//  - *vo.UserActionResultVO: 处理结果 (updated 表示用户存在并被删除，not_found 表示用户本就不存在，删除是幂等的)。
+	//  - error: mode 无效或配置不允许按请求指定时返回 ErrDeleteModeInvalid / ErrDeleteModeOverrideNotAllowed；
+	//    前置检查否决时返回 *DeleteVetoError；检查服务不可用时返回包装了 ErrThirdPartyServiceError 的错误；其他失败返回系统错误。
+	DeleteUser(ctx context.Context, actorID string, userID string, dryRun bool, mode string) (*vo.UserActionResultVO, error)
 
 	// BlackUser 将指定用户标记为“拉黑”状态。
 	// 参数:
+	//  - actorID: 执行操作的管理员用户 ID，用于写入审计日志。
 	//  - userID: 要拉黑的用户 ID。
+	//  - dryRun: 为 true 时只校验并报告将会发生的变更，不开启事务、不写审计日志。
 	// 返回:
-	//  - error: 操作过程中发生的任何错误。
-	BlackUser(ctx context.Context, userID string) error
+	//  - *vo.UserActionResultVO: 处理结果 (updated 表示状态被改为拉黑，unchanged 表示用户已处于拉黑状态)。
+	//  - error: 用户不存在时返回业务错误，其他失败返回系统错误。
+	BlackUser(ctx context.Context, actorID string, userID string, dryRun bool) (*vo.UserActionResultVO, error)
+
+	// ReactivateUser 将已拉黑或已停用 (StatusDeactivated) 的用户恢复为活跃状态。
+	// 只修改状态并写入审计日志，不会吊销用户现有的令牌或会话。
+	// 参数:
+	//  - actorID: 执行操作的管理员用户 ID，用于写入审计日志。
+	//  - userID: 要恢复的用户 ID。
+	//  - reason: 恢复原因，写入审计日志，可以为空。
+	// 返回:
+	//  - *vo.UserVO: 恢复后的用户信息。
+	//  - bool: 是否有实际变化；用户本就处于活跃状态时为 false，且不写审计日志。
+	//  - error: 用户不存在时返回 ErrReactivateUserNotFound，其他失败返回系统错误。
+	ReactivateUser(ctx context.Context, actorID string, userID string, reason string) (*vo.UserVO, bool, error)
+
+	// BatchSetRole 将一批用户的角色设置为同一个值。
+	// 分批在独立事务中更新并为每个实际变更的用户写入审计日志；某一批失败不影响其他批次。
+	// 参数:
+	//  - actorID: 执行操作的管理员用户 ID，用于写入审计日志。
+	//  - dto: 目标用户 ID 列表与角色。
+	// 返回:
+	//  - *vo.BatchRoleResultVO: 每个用户的处理结果与汇总数量。
+	//  - error: 查询目标用户失败等无法继续处理时返回系统错误；单个批次写入失败只体现在结果中。
+	BatchSetRole(ctx context.Context, actorID string, dto *dto.BatchRoleDTO) (*vo.BatchRoleResultVO, error)
 }
 
+// ErrReactivateUserNotFound 要恢复的用户不存在
+var ErrReactivateUserNotFound = errors.New("要恢复的用户不存在")
+
+var (
+	// ErrDeleteModeInvalid 删除方式不是 soft 或 hard
+	ErrDeleteModeInvalid = errors.New("删除方式无效，仅支持 soft 或 hard")
+	// ErrDeleteModeOverrideNotAllowed 配置不允许按请求指定删除方式
+	ErrDeleteModeOverrideNotAllowed = errors.New("当前配置不允许按请求指定删除方式")
+)
+
 // userService 是 UserManageService 接口的实现。
 type userService struct {
-	userRepo     mysql.UserRepository     // userRepo: 用户数据仓库。
-	identityRepo mysql.IdentityRepository // identityRepo: 用户身份数据仓库。
-	profileRepo  mysql.ProfileRepository  // profileRepo: 用户资料数据仓库。
-	db           *gorm.DB                 // db: GORM数据库连接实例，用于启动事务和传递给仓库方法。
-	logger       *core.ZapLogger          // logger: 日志记录器。
+	userRepo     mysql.UserRepository            // userRepo: 用户数据仓库。
+	identityRepo mysql.IdentityRepository        // identityRepo: 用户身份数据仓库。
+	profileRepo  mysql.ProfileRepository         // profileRepo: 用户资料数据仓库。
+	auditRepo    mysql.AdminAuditRepository      // auditRepo: 管理员操作审计日志仓库。
+	preDelete    PreDeleteCheck                  // preDelete: 删除用户前的前置检查钩子，可否决删除。
+	cosClient    dependencies.COSClientInterface // cosClient: 对象存储客户端，硬删除时删除用户头像，可以为 nil。
+	fileService  file.FileService                // fileService: 文件服务，删除头像后移除其用量记录。
+	deleteCfg    config.UserDeleteConfig         // deleteCfg: 删除用户的策略 (软删除/硬删除)。
+	db           *gorm.DB                        // db: GORM数据库连接实例，用于启动事务和传递给仓库方法。
+	logger       *core.ZapLogger                 // logger: 日志记录器。
 }
 
 // NewUserService 创建一个新的 userService 实例。
 // 设计原因:
 // - 依赖注入确保了服务的可测试性和灵活性。
+// 参数:
+//   - preDelete: 删除用户前的前置检查，为 nil 时使用 NoopPreDeleteCheck (总是允许)。
+//   - cosClient: 对象存储客户端，为 nil 时硬删除跳过头像清理。
+//   - deleteCfg: 删除用户的策略。
 func NewUserService(
 	userRepo mysql.UserRepository,
 	identityRepo mysql.IdentityRepository, // 注入 identityRepo
 	profileRepo mysql.ProfileRepository, // 注入 profileRepo
+	auditRepo mysql.AdminAuditRepository,
+	preDelete PreDeleteCheck,
+	cosClient dependencies.COSClientInterface,
+	fileService file.FileService,
+	deleteCfg config.UserDeleteConfig,
 	db *gorm.DB,
 	logger *core.ZapLogger,
 ) UserManageService {
+	if preDelete == nil {
+		preDelete = NoopPreDeleteCheck
+	}
 	return &userService{
 		userRepo:     userRepo,
 		identityRepo: identityRepo, // 存储 identityRepo
 		profileRepo:  profileRepo,  // 存储 profileRepo
+		auditRepo:    auditRepo,
+		preDelete:    preDelete,
+		cosClient:    cosClient,
+		fileService:  fileService,
+		deleteCfg:    deleteCfg,
 		db:           db,
 		logger:       logger,
 	}
@@ -108,17 +182,22 @@ func userEntityToVO(user *entities.User) *vo.UserVO {
 	if user == nil {
 		return nil
 	}
-	return &vo.UserVO{
+	userVO := &vo.UserVO{
 		UserID:    user.UserID,
 		UserRole:  user.UserRole,
 		Status:    user.Status,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}
+	if user.DeletedAt.Valid {
+		deletedAt := user.DeletedAt.Time
+		userVO.DeletedAt = &deletedAt
+	}
+	return userVO
 }
 
 // CreateUser 实现接口方法，创建新用户。
-func (s *userService) CreateUser(ctx context.Context, dto *dto.CreateUserDTO) (*vo.UserVO, error) {
+func (s *userService) CreateUser(ctx context.Context, actorID string, dto *dto.CreateUserDTO) (*vo.UserVO, error) {
 	const operation = "UserManageService.CreateUser"
 	userID := uuid.New().String()
 	s.logger.Info("开始创建新用户",
@@ -134,7 +213,17 @@ func (s *userService) CreateUser(ctx context.Context, dto *dto.CreateUserDTO) (*
 		Status:   dto.Status,
 	}
 
-	if err := s.userRepo.CreateUser(ctx, s.db, userEntity); err != nil {
+	// 在同一事务中创建用户并写入审计日志
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.CreateUser(ctx, tx, userEntity); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, tx, actorID, enums.AuditActionCreateUser, userID, map[string]fieldChange{
+			"user_role": {From: nil, To: dto.UserRole.String()},
+			"status":    {From: nil, To: dto.Status.String()},
+		})
+	})
+	if err != nil {
 		s.logger.Error("调用仓库创建用户失败",
 			zap.String("operation", operation),
 			zap.String("userID", userID),
@@ -165,9 +254,13 @@ func (s *userService) CreateUser(ctx context.Context, dto *dto.CreateUserDTO) (*
 }
 
 // GetUserByID 实现接口方法，获取用户信息。
-func (s *userService) GetUserByID(ctx context.Context, userID string) (*vo.UserVO, error) {
+func (s *userService) GetUserByID(ctx context.Context, userID string, includeDeleted bool) (*vo.UserVO, error) {
 	const operation = "UserManageService.GetUserByID"
-	userEntity, err := s.userRepo.GetUserByID(ctx, userID) // 假设 GetUserByID 不需要 db 参数
+	getUser := s.userRepo.GetUserByID
+	if includeDeleted {
+		getUser = s.userRepo.GetUserByIDIncludingDeleted
+	}
+	userEntity, err := getUser(ctx, userID)
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrRepoNotFound) {
 			s.logger.Info("尝试获取不存在的用户", zap.String("operation", operation), zap.String("userID", userID))
@@ -216,60 +309,134 @@ func (s *userService) GetUserProfileByAdmin(ctx context.Context, userID string)
 }
 
 // UpdateUser 实现接口方法，更新用户信息。
-func (s *userService) UpdateUser(ctx context.Context, userID string, dto *dto.UpdateUserDTO) (*vo.UserVO, error) {
+func (s *userService) UpdateUser(ctx context.Context, actorID string, userID string, dto *dto.UpdateUserDTO) (*vo.UserVO, bool, error) {
 	const operation = "UserManageService.UpdateUser"
 	userEntity, err := s.userRepo.GetUserByID(ctx, userID) // 先获取
 	if err != nil {
 		if errors.Is(err, commonerrors.ErrRepoNotFound) {
 			s.logger.Warn("尝试更新不存在的用户", zap.String("operation", operation), zap.String("userID", userID))
-			return nil, errors.New("要更新的用户不存在")
+			return nil, false, errors.New("要更新的用户不存在")
 		}
 		s.logger.Error("更新用户前查询失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
-		return nil, commonerrors.ErrSystemError
+		return nil, false, commonerrors.ErrSystemError
 	}
 
-	updated := false
+	changes := make(map[string]fieldChange)
 	if dto.UserRole != 0 && userEntity.UserRole != dto.UserRole {
+		changes["user_role"] = fieldChange{From: userEntity.UserRole.String(), To: dto.UserRole.String()}
 		userEntity.UserRole = dto.UserRole
-		updated = true
 	}
 	if dto.Status != 0 && userEntity.Status != dto.Status {
+		changes["status"] = fieldChange{From: userEntity.Status.String(), To: dto.Status.String()}
 		userEntity.Status = dto.Status
-		updated = true
 	}
 
-	if !updated {
+	if len(changes) == 0 {
 		s.logger.Info("用户信息无需更新", zap.String("operation", operation), zap.String("userID", userID))
-		return userEntityToVO(userEntity), nil
+		return userEntityToVO(userEntity), false, nil
 	}
 
-	if err := s.userRepo.UpdateUser(ctx, userEntity); err != nil {
+	// 在同一事务中更新用户并写入审计日志
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.UpdateUser(ctx, tx, userEntity); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, tx, actorID, enums.AuditActionUpdateUser, userID, changes)
+	})
+	if err != nil {
 		s.logger.Error("调用仓库更新用户失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
-		return nil, commonerrors.ErrSystemError
+		return nil, false, commonerrors.ErrSystemError
 	}
 
 	// *** 新增：更新成功后，重新从数据库获取最新记录 ***
 	updatedUserEntity, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		s.logger.Error("更新用户后重新获取记录失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
-		return nil, commonerrors.ErrSystemError // 报告错误
+		return nil, false, commonerrors.ErrSystemError // 报告错误
 	}
 
 	s.logger.Info("成功更新用户信息", zap.String("operation", operation), zap.String("userID", userID))
-	return userEntityToVO(updatedUserEntity), nil
+	return userEntityToVO(updatedUserEntity), true, nil
 }
 
-// DeleteUser 实现接口方法，事务性地软删除用户及其关联的身份和资料。
-func (s *userService) DeleteUser(ctx context.Context, userID string) error {
+// DeleteUser 实现接口方法，事务性地软删除或硬删除用户及其关联的身份和资料。
+func (s *userService) DeleteUser(ctx context.Context, actorID string, userID string, dryRun bool, mode string) (*vo.UserActionResultVO, error) {
 	const operation = "UserManageService.DeleteUserCascade" // 操作名可以更具体
+
+	// 确定删除方式：默认使用配置，按请求指定需配置允许
+	switch {
+	case mode == "":
+		mode = s.deleteCfg.ModeOrDefault()
+	case mode != config.UserDeleteModeSoft && mode != config.UserDeleteModeHard:
+		return nil, ErrDeleteModeInvalid
+	case mode != s.deleteCfg.ModeOrDefault() && !s.deleteCfg.AllowOverride:
+		return nil, ErrDeleteModeOverrideNotAllowed
+	}
+	hard := mode == config.UserDeleteModeHard
 	s.logger.Info("开始删除用户及其所有关联数据（事务性）",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
+		zap.String("mode", mode),
 	)
 
+	// 记录删除前的用户状态，用于审计日志；用户不存在时依旧执行（幂等），审计中 from 为空
+	// 硬删除同样处理已被软删除的用户，使其可以被彻底清除
+	auditDiff := map[string]fieldChange{"deleted": {From: false, To: true}, "delete_mode": {From: nil, To: mode}}
+	result := &vo.UserActionResultVO{UserID: userID, Result: vo.BatchItemNotFound, DryRun: dryRun, DeleteMode: mode}
+	getUser := s.userRepo.GetUserByID
+	if hard {
+		getUser = s.userRepo.GetUserByIDIncludingDeleted
+	}
+	if existing, getErr := getUser(ctx, userID); getErr == nil {
+		auditDiff["user_role"] = fieldChange{From: existing.UserRole.String(), To: nil}
+		auditDiff["status"] = fieldChange{From: existing.Status.String(), To: nil}
+		result.Result = vo.BatchItemUpdated
+	} else if !errors.Is(getErr, commonerrors.ErrRepoNotFound) {
+		s.logger.Error("删除用户前查询用户失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(getErr))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	// 用户存在时执行前置检查，外部服务可否决删除 (预演模式同样检查，以报告真实结果)
+	if result.Result == vo.BatchItemUpdated {
+		if err := s.preDelete.CheckBeforeDelete(ctx, userID); err != nil {
+			var veto *DeleteVetoError
+			if errors.As(err, &veto) {
+				s.logger.Warn("删除用户被前置检查否决", zap.String("operation", operation), zap.String("userID", userID), zap.String("actorID", actorID), zap.String("source", veto.Source), zap.String("reason", veto.Reason))
+				return nil, veto
+			}
+			s.logger.Error("删除用户前置检查失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+			if errors.Is(err, commonerrors.ErrThirdPartyServiceError) {
+				return nil, err
+			}
+			return nil, commonerrors.ErrSystemError
+		}
+	}
+
+	// 预演模式：校验完成后在写入前短路
+	if dryRun {
+		s.logger.Info("预演删除用户，未提交任何修改", zap.String("operation", operation), zap.String("userID", userID), zap.String("result", result.Result))
+		return result, nil
+	}
+
+	// 硬删除需要在删除资料前记下头像地址，提交后再清理存储中的对象；已软删除的用户其资料也已被软删除，需包含已删除记录查询
+	avatarURL := ""
+	if hard {
+		if profile, getErr := s.profileRepo.GetProfileByUserIDIncludingDeleted(ctx, userID); getErr == nil {
+			avatarURL = profile.AvatarURL
+		} else if !errors.Is(getErr, commonerrors.ErrRepoNotFound) {
+			s.logger.Error("硬删除用户前查询资料失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(getErr))
+			return nil, commonerrors.ErrSystemError
+		}
+	}
+
 	// 开启数据库事务
 	err := s.db.Transaction(func(tx *gorm.DB) error {
-		// 1. 软删除核心用户记录
+		// 硬删除时以 Unscoped 的事务对象调用同一组仓库方法，级联范围与软删除完全一致
+		if hard {
+			tx = tx.Unscoped()
+		}
+
+		// 1. 删除核心用户记录
 		//    仓库方法 DeleteUser 接收事务对象 tx
 		if repoErr := s.userRepo.DeleteUser(ctx, tx, userID); repoErr != nil {
 			// 如果用户本就不存在，对于删除操作通常视为成功（幂等）
@@ -295,7 +462,7 @@ func (s *userService) DeleteUser(ctx context.Context, userID string) error {
 			}
 		}
 
-		// 2. 软删除该用户的所有身份信息
+		// 2. 删除该用户的所有身份信息
 		//    调用 identityRepo 的 DeleteIdentitiesByUserID 方法，传入事务对象 tx
 		//    假设该方法内部处理了记录不存在的情况（通常是 RowsAffected=0，error=nil）
 		if repoErr := s.identityRepo.DeleteIdentitiesByUserID(ctx, tx, userID); repoErr != nil {
@@ -309,7 +476,7 @@ func (s *userService) DeleteUser(ctx context.Context, userID string) error {
 		}
 		s.logger.Info("事务中：已尝试删除用户身份信息", zap.String("operation", operation), zap.String("userID", userID))
 
-		// 3. 软删除该用户的资料信息
+		// 3. 删除该用户的资料信息
 		//    调用 profileRepo 的 DeleteProfile 方法，传入事务对象 tx
 		//    该方法按 UserID 删除，如果 Profile 不存在，仓库层应处理 NotFound (可能返回 nil 或 ErrRepoNotFound)
 		if repoErr := s.profileRepo.DeleteProfile(ctx, tx, userID); repoErr != nil {
@@ -330,6 +497,16 @@ func (s *userService) DeleteUser(ctx context.Context, userID string) error {
 		}
 		s.logger.Info("事务中：已尝试删除用户资料信息", zap.String("operation", operation), zap.String("userID", userID))
 
+		// 4. 写入审计日志，与删除操作同事务提交
+		if auditErr := s.recordAudit(ctx, tx, actorID, enums.AuditActionDeleteUser, userID, auditDiff); auditErr != nil {
+			s.logger.Error("事务中写入删除用户审计日志失败",
+				zap.String("operation", operation),
+				zap.String("userID", userID),
+				zap.Error(auditErr),
+			)
+			return fmt.Errorf("写入审计日志失败: %w", auditErr) // 导致事务回滚
+		}
+
 		// 所有操作成功，事务将自动提交
 		return nil
 	})
@@ -342,30 +519,146 @@ func (s *userService) DeleteUser(ctx context.Context, userID string) error {
 			zap.String("userID", userID),
 			zap.Error(err), // 记录事务返回的顶层错误
 		)
-		return commonerrors.ErrSystemError // 向上层返回通用系统错误
+		return nil, commonerrors.ErrSystemError // 向上层返回通用系统错误
+	}
+
+	if avatarURL != "" {
+		s.deleteAvatarObject(ctx, userID, avatarURL)
+	}
+	if !hard && result.Result == vo.BatchItemUpdated && s.fileService != nil {
+		// 软删除保留存储中的对象，但已删除的用户不再计入存储用量 (失败已在文件服务中记录日志)
+		_ = s.fileService.ReleaseUserObjects(context.WithoutCancel(ctx), userID)
 	}
 
 	s.logger.Info("成功删除用户及其所有关联数据（事务性）",
 		zap.String("operation", operation),
 		zap.String("userID", userID),
+		zap.String("mode", mode),
 	)
-	return nil
+	return result, nil
+}
+
+// deleteAvatarObject 硬删除用户后尽力删除其存储中的头像对象，失败只记录日志 (数据库记录已删除，不回滚)。
+// - 头像不属于当前存储桶 (如微信头像链接) 时跳过。
+func (s *userService) deleteAvatarObject(ctx context.Context, userID string, avatarURL string) {
+	const operation = "UserManageService.deleteAvatarObject"
+	if s.cosClient == nil {
+		return
+	}
+	objectKey, ok := s.cosClient.ObjectKeyFromURL(avatarURL)
+	if !ok {
+		s.logger.Info("头像不属于当前存储桶，跳过清理", zap.String("operation", operation), zap.String("userID", userID))
+		return
+	}
+	// 请求可能已超时或取消，数据库记录已删除，清理使用不可取消的上下文
+	ctx = context.WithoutCancel(ctx)
+	if err := s.cosClient.DeleteObject(ctx, objectKey); err != nil {
+		s.logger.Error("硬删除用户后删除头像对象失败，需人工处理", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey), zap.Error(err))
+		return
+	}
+	if s.fileService != nil {
+		// 对象已删除，同步移除其用量记录 (失败已在文件服务中记录日志)
+		_ = s.fileService.ReleaseObject(ctx, objectKey)
+	}
+	s.logger.Info("已删除被硬删除用户的头像对象", zap.String("operation", operation), zap.String("userID", userID), zap.String("objectKey", objectKey))
 }
 
 // BlackUser 实现接口方法，拉黑用户。
-func (s *userService) BlackUser(ctx context.Context, userID string) error {
+func (s *userService) BlackUser(ctx context.Context, actorID string, userID string, dryRun bool) (*vo.UserActionResultVO, error) {
 	const operation = "UserManageService.BlackUser"
-	// 假设 s.repo.BlackUser 签名已更新为 BlackUser(ctx, db, userID)
-	if err := s.userRepo.BlackUser(ctx, userID); err != nil {
+
+	// 1. 先查询用户，确认存在并记录拉黑前的状态
+	userEntity, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
 		if errors.Is(err, commonerrors.ErrRepoNotFound) {
 			s.logger.Warn("尝试拉黑不存在的用户", zap.String("operation", operation), zap.String("userID", userID))
-			return errors.New("要拉黑的用户不存在")
+			return nil, errors.New("要拉黑的用户不存在")
+		}
+		s.logger.Error("拉黑用户前查询失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, commonerrors.ErrSystemError
+	}
+
+	result := &vo.UserActionResultVO{UserID: userID, Result: vo.BatchItemUpdated, DryRun: dryRun}
+	if userEntity.Status == commonenums.StatusBlacklisted {
+		result.Result = vo.BatchItemUnchanged
+	}
+
+	// 预演模式：校验完成后在写入前短路
+	if dryRun {
+		s.logger.Info("预演拉黑用户，未提交任何修改", zap.String("operation", operation), zap.String("userID", userID), zap.String("result", result.Result))
+		return result, nil
+	}
+
+	// 2. 在同一事务中更新状态并写入审计日志
+	txErr := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.BlackUser(ctx, tx, userID); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, tx, actorID, enums.AuditActionBlacklistUser, userID, map[string]fieldChange{
+			"status": {From: userEntity.Status.String(), To: commonenums.StatusBlacklisted.String()},
+		})
+	})
+	if txErr != nil {
+		s.logger.Error("调用仓库拉黑用户失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(txErr))
+		return nil, commonerrors.ErrSystemError
+	}
+	s.logger.Info("成功拉黑用户", zap.String("operation", operation), zap.String("userID", userID), zap.String("actorID", actorID))
+	return result, nil
+}
+
+// ReactivateUser 实现接口方法，恢复已拉黑或已停用的用户。
+func (s *userService) ReactivateUser(ctx context.Context, actorID string, userID string, reason string) (*vo.UserVO, bool, error) {
+	const operation = "UserManageService.ReactivateUser"
+
+	userEntity, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, commonerrors.ErrRepoNotFound) {
+			s.logger.Warn("尝试恢复不存在的用户", zap.String("operation", operation), zap.String("userID", userID))
+			return nil, false, ErrReactivateUserNotFound
+		}
+		s.logger.Error("恢复用户前查询失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, false, commonerrors.ErrSystemError
+	}
+
+	// 已是活跃状态时幂等返回，不写审计日志
+	if userEntity.Status == commonenums.StatusActive {
+		s.logger.Info("用户已处于活跃状态，无需恢复", zap.String("operation", operation), zap.String("userID", userID))
+		return userEntityToVO(userEntity), false, nil
+	}
+	previous := userEntity.Status
+
+	// 只更新状态字段：不触碰令牌版本、刷新令牌白名单等，恢复后用户已有的会话保持不变
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.UpdateUserStatus(ctx, tx, userID, commonenums.StatusActive); err != nil {
+			return err
 		}
-		s.logger.Error("调用仓库拉黑用户失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
-		return commonerrors.ErrSystemError
+		return s.recordAudit(ctx, tx, actorID, enums.AuditActionReactivate, userID, map[string]any{
+			"status": fieldChange{From: enums.UserStatusName(previous), To: commonenums.StatusActive.String()},
+			"reason": reason,
+		})
+	})
+	if err != nil {
+		s.logger.Error("调用仓库恢复用户失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, false, commonerrors.ErrSystemError
 	}
-	s.logger.Info("成功拉黑用户", zap.String("operation", operation), zap.String("userID", userID))
-	return nil
+
+	// 重新获取记录，返回最新的状态与更新时间
+	updatedUserEntity, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.Error("恢复用户后重新获取记录失败", zap.String("operation", operation), zap.String("userID", userID), zap.Error(err))
+		return nil, false, commonerrors.ErrSystemError
+	}
+
+	// 结构化的用户恢复事件，供日志采集侧订阅 (如通知下游解除限制)
+	s.logger.Info("用户已被管理员恢复",
+		zap.String("operation", operation),
+		zap.String("event", string(enums.AuditActionReactivate)),
+		zap.String("userID", userID),
+		zap.String("actorID", actorID),
+		zap.String("fromStatus", enums.UserStatusName(previous)),
+		zap.String("reason", reason),
+	)
+	return userEntityToVO(updatedUserEntity), true, nil
 }
 
 // userProfileEntityToVO 是一个内部辅助函数，用于将数据库实体 `entities.UserProfile` 转换为对外暴露的视图对象 `vo.ProfileVO`。
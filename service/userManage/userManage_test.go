@@ -0,0 +1,227 @@
+package userManage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Xushengqwer/go-common/commonerrors"
+	commonconfig "github.com/Xushengqwer/go-common/config"
+	"github.com/Xushengqwer/go-common/core"
+	commonenums "github.com/Xushengqwer/go-common/models/enums"
+	mysqldriver "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/Xushengqwer/user_hub/dependencies"
+	"github.com/Xushengqwer/user_hub/models/dto"
+	"github.com/Xushengqwer/user_hub/models/entities"
+	"github.com/Xushengqwer/user_hub/models/vo"
+	"github.com/Xushengqwer/user_hub/repository/mysql"
+)
+
+// newTestLogger 创建只输出致命错误的日志记录器，避免测试输出被业务日志淹没
+func newTestLogger(t *testing.T) *core.ZapLogger {
+	t.Helper()
+	logger, err := core.NewZapLogger(commonconfig.ZapConfig{Level: "fatal", Encoding: "console"})
+	if err != nil {
+		t.Fatalf("创建日志记录器失败: %v", err)
+	}
+	return logger
+}
+
+// newMockGormDB 基于 sqlmock 创建 GORM 连接，仅用于驱动事务的开启/提交/回滚；
+// 数据读写由内存假仓库完成。
+func newMockGormDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("创建 sqlmock 失败: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := gorm.Open(mysqldriver.New(mysqldriver.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建 GORM 连接失败: %v", err)
+	}
+	return db, mock
+}
+
+// fakeUserRepo 内嵌接口，以单条内存记录实现用户的查询与更新
+type fakeUserRepo struct {
+	mysql.UserRepository
+	user    *entities.User
+	updates int
+	deletes int
+}
+
+func (r *fakeUserRepo) GetUserByID(_ context.Context, _ string) (*entities.User, error) {
+	u := *r.user
+	return &u, nil
+}
+
+// GetUserByIDIncludingDeleted 与 GetUserByID 相同，内存记录本身可以带有软删除时间
+func (r *fakeUserRepo) GetUserByIDIncludingDeleted(ctx context.Context, userID string) (*entities.User, error) {
+	return r.GetUserByID(ctx, userID)
+}
+
+func (r *fakeUserRepo) DeleteUser(_ context.Context, _ *gorm.DB, _ string) error {
+	r.deletes++
+	return nil
+}
+
+func (r *fakeUserRepo) UpdateUser(_ context.Context, _ *gorm.DB, user *entities.User) error {
+	r.updates++
+	u := *user
+	r.user = &u
+	return nil
+}
+
+// fakeAuditRepo 内嵌接口，记录写入的审计日志
+type fakeAuditRepo struct {
+	mysql.AdminAuditRepository
+	logs []*entities.AdminAuditLog
+}
+
+func (r *fakeAuditRepo) CreateAuditLog(_ context.Context, _ *gorm.DB, log *entities.AdminAuditLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+// fakeIdentityRepo 内嵌接口，仅实现删除用户时的级联删除
+type fakeIdentityRepo struct {
+	mysql.IdentityRepository
+}
+
+func (r *fakeIdentityRepo) DeleteIdentitiesByUserID(_ context.Context, _ *gorm.DB, _ string) error {
+	return nil
+}
+
+// fakeProfileRepo 内嵌接口，模拟资料已随用户被软删除：普通查询查不到，包含已删除记录的查询可以查到
+type fakeProfileRepo struct {
+	mysql.ProfileRepository
+	deletedProfile *entities.UserProfile
+	deletes        int
+}
+
+func (r *fakeProfileRepo) GetProfileByUserID(_ context.Context, _ string) (*entities.UserProfile, error) {
+	return nil, commonerrors.ErrRepoNotFound
+}
+
+func (r *fakeProfileRepo) GetProfileByUserIDIncludingDeleted(_ context.Context, _ string) (*entities.UserProfile, error) {
+	if r.deletedProfile == nil {
+		return nil, commonerrors.ErrRepoNotFound
+	}
+	p := *r.deletedProfile
+	return &p, nil
+}
+
+func (r *fakeProfileRepo) DeleteProfile(_ context.Context, _ *gorm.DB, _ string) error {
+	r.deletes++
+	return nil
+}
+
+// fakeCOSClient 内嵌接口，以固定前缀解析对象键并记录被删除的对象
+type fakeCOSClient struct {
+	dependencies.COSClientInterface
+	deletedKeys []string
+}
+
+func (c *fakeCOSClient) ObjectKeyFromURL(publicURL string) (string, bool) {
+	return strings.CutPrefix(publicURL, "https://bucket.example.com/")
+}
+
+func (c *fakeCOSClient) DeleteObject(_ context.Context, objectKey string) error {
+	c.deletedKeys = append(c.deletedKeys, objectKey)
+	return nil
+}
+
+func TestHardDeleteSoftDeletedUserCleansAvatar(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  *entities.UserProfile
+		wantKeys []string
+	}{
+		{name: "已软删除资料中的头像被清理", profile: &entities.UserProfile{UserID: "u1", AvatarURL: "https://bucket.example.com/avatars/u1.png", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, wantKeys: []string{"avatars/u1.png"}},
+		{name: "外部头像链接跳过清理", profile: &entities.UserProfile{UserID: "u1", AvatarURL: "https://wx.qlogo.cn/u1", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}},
+		{name: "资料不存在时照常删除", profile: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockGormDB(t)
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+			userRepo := &fakeUserRepo{user: &entities.User{UserID: "u1", UserRole: commonenums.RoleUser, Status: commonenums.StatusActive, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}}
+			profileRepo := &fakeProfileRepo{deletedProfile: tt.profile}
+			cos := &fakeCOSClient{}
+			svc := NewUserService(userRepo, &fakeIdentityRepo{}, profileRepo, &fakeAuditRepo{}, nil, cos, nil, config.UserDeleteConfig{Mode: config.UserDeleteModeHard}, db, newTestLogger(t))
+
+			result, err := svc.DeleteUser(context.Background(), "admin", "u1", false, "")
+			if err != nil {
+				t.Fatalf("DeleteUser 失败: %v", err)
+			}
+			if result.Result != vo.BatchItemUpdated || result.DeleteMode != config.UserDeleteModeHard {
+				t.Errorf("结果 = %+v，期望硬删除已存在的用户", result)
+			}
+			if userRepo.deletes != 1 || profileRepo.deletes != 1 {
+				t.Errorf("删除用户/资料次数 = %d/%d，期望均为 1", userRepo.deletes, profileRepo.deletes)
+			}
+			if !slices.Equal(cos.deletedKeys, tt.wantKeys) {
+				t.Errorf("删除的头像对象 = %v，期望 %v", cos.deletedKeys, tt.wantKeys)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("事务调用不符合预期: %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateUserNoOp(t *testing.T) {
+	tests := []struct {
+		name        string
+		dto         dto.UpdateUserDTO
+		wantChanged bool
+		wantStatus  commonenums.UserStatus
+	}{
+		{name: "未提供任何字段", dto: dto.UpdateUserDTO{}, wantStatus: commonenums.StatusActive},
+		{name: "角色与现有数据相同", dto: dto.UpdateUserDTO{UserRole: commonenums.RoleUser}, wantStatus: commonenums.StatusActive},
+		{name: "状态发生变化", dto: dto.UpdateUserDTO{Status: commonenums.StatusBlacklisted}, wantChanged: true, wantStatus: commonenums.StatusBlacklisted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockGormDB(t)
+			if tt.wantChanged {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			}
+			userRepo := &fakeUserRepo{user: &entities.User{UserID: "u1", UserRole: commonenums.RoleUser, Status: commonenums.StatusActive}}
+			auditRepo := &fakeAuditRepo{}
+			svc := NewUserService(userRepo, nil, nil, auditRepo, nil, nil, nil, config.UserDeleteConfig{}, db, newTestLogger(t))
+
+			userVO, changed, err := svc.UpdateUser(context.Background(), "admin", "u1", &tt.dto)
+			if err != nil {
+				t.Fatalf("UpdateUser 失败: %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v，期望 %v", changed, tt.wantChanged)
+			}
+			if userVO == nil || userVO.Status != tt.wantStatus {
+				t.Errorf("返回的用户 = %+v，期望状态 %v", userVO, tt.wantStatus)
+			}
+
+			// 无变更时不开启事务、不写库，也不写审计日志
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("事务调用不符合预期: %v", err)
+			}
+			wantCalls := 0
+			if tt.wantChanged {
+				wantCalls = 1
+			}
+			if userRepo.updates != wantCalls || len(auditRepo.logs) != wantCalls {
+				t.Errorf("写库/审计日志次数 = %d/%d，期望均为 %d", userRepo.updates, len(auditRepo.logs), wantCalls)
+			}
+		})
+	}
+}
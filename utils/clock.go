@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 时间来源接口。
+// 设计目的:
+//   - 令牌有效期、验证码发送额度、API 密钥过期等与时间相关的逻辑通过注入的 Clock 取当前时间，
+//     测试时可替换为 FakeClock 并手动推进时间，避免依赖真实时间的等待与不确定性。
+type Clock interface {
+	// Now 返回当前时间。
+	Now() time.Time
+}
+
+// systemClock 基于系统时间的 Clock 实现
+type systemClock struct{}
+
+// Now 实现接口方法。
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock 默认使用的真实时钟
+var SystemClock Clock = systemClock{}
+
+// FakeClock 可手动设置与推进的 Clock 实现，供测试使用，可并发访问。
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock 创建一个停在 now 的 FakeClock。
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now 实现接口方法，返回当前设置的时间。
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance 将时间向前推进 d。
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set 将时间设置为 now。
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor 游标无法解析 (被篡改、截断或来自其他版本) 时返回
+var ErrInvalidCursor = errors.New("分页游标无效")
+
+// KeysetCursor 按 (created_at DESC, id DESC) 排序的列表的键集分页位置
+// - 记录上一页最后一条记录的排序键，下一页从严格小于该键的位置开始，避免深分页的 OFFSET 扫描。
+type KeysetCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"id"`
+}
+
+// EncodeCursor 将游标编码为不透明字符串 (URL 安全的 base64)，客户端只需原样回传
+func EncodeCursor(c KeysetCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor 解析 EncodeCursor 生成的游标字符串，格式不正确时返回 ErrInvalidCursor
+func DecodeCursor(s string) (KeysetCursor, error) {
+	var c KeysetCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(raw, &c) != nil || c.ID == 0 || c.CreatedAt.IsZero() {
+		return KeysetCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
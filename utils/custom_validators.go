@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"github.com/Xushengqwer/go-common/models/enums"        // 导入公共模块的 enums 包
+	"github.com/Xushengqwer/user_hub/config"               // 导入配置包，使用账号格式策略常量
 	myenums "github.com/Xushengqwer/user_hub/models/enums" // 导入项目内部的 enums 包，并使用别名 myenums 避免命名冲突
 	"github.com/gin-gonic/gin/binding"                     // Gin 框架的数据绑定包
 	"github.com/go-playground/validator/v10"               // 强大的数据校验库
@@ -16,9 +17,12 @@ var (
 	// 规则：以1开头，第二位是3到9之间的数字，后面跟9个数字。
 	phoneNumberRegex = regexp.MustCompile(`^1[3-9]\d{9}$`)
 
-	// usernameRegex 预编译的用户名（昵称）正则表达式，用于提升校验性能。
+	// usernameRegex 预编译的用户名 (登录账号) 正则表达式，用于提升校验性能。
 	// 规则：只包含大小写字母、数字和下划线，长度在1到20个字符之间。
 	usernameRegex = regexp.MustCompile(`^[A-Za-z0-9_]{1,20}$`)
+
+	// emailRegex 预编译的邮箱正则表达式，只做格式上的粗略识别。
+	emailRegex = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
 )
 
 // ValidateChinesePhone 校验是否为中国大陆手机号。
@@ -28,12 +32,50 @@ func ValidateChinesePhone(fl validator.FieldLevel) bool {
 	return phoneNumberRegex.MatchString(phoneNumber) // 使用预编译的正则进行匹配
 }
 
-// ValidateNickname 校验昵称/用户账号格式。
-// 要求：只包含字母、数字和下划线，且长度在1到20之间。
-func ValidateNickname(fl validator.FieldLevel) bool {
+// IsChinesePhone 判断字符串是否为中国大陆手机号。
+func IsChinesePhone(s string) bool {
+	return phoneNumberRegex.MatchString(s)
+}
+
+// IsEmail 粗略判断字符串是否为邮箱格式 (local@domain.tld)，用于登录入口识别身份类型。
+func IsEmail(s string) bool {
+	return emailRegex.MatchString(s)
+}
+
+// ValidateUsername 校验用户名格式的账号。
+// 要求：只包含字母、数字和下划线，且长度在1到20之间。昵称 (展示名) 使用独立的 "Nickname" 校验，见 IsValidNickname。
+func ValidateUsername(fl validator.FieldLevel) bool {
 	return usernameRegex.MatchString(fl.Field().String()) // 使用预编译的正则进行匹配
 }
 
+// maxEmailAccountLength 邮箱账号的最大长度 (RFC 5321 规定的地址上限)。
+const maxEmailAccountLength = 254
+
+// ValidateEmailAccount 校验邮箱格式的账号。
+func ValidateEmailAccount(fl validator.FieldLevel) bool {
+	account := fl.Field().String()
+	return len(account) <= maxEmailAccountLength && emailRegex.MatchString(account)
+}
+
+// ValidateUsernameOrEmailAccount 校验账号为用户名或邮箱格式之一。
+func ValidateUsernameOrEmailAccount(fl validator.FieldLevel) bool {
+	return ValidateUsername(fl) || ValidateEmailAccount(fl)
+}
+
+// accountValidatorFor 根据配置的账号格式策略选择 "Account" 标签使用的校验函数。
+func accountValidatorFor(format string) (validator.Func, error) {
+	switch format {
+	case "", config.AccountFormatUsername:
+		return ValidateUsername, nil
+	case config.AccountFormatEmail:
+		return ValidateEmailAccount, nil
+	case config.AccountFormatEither:
+		return ValidateUsernameOrEmailAccount, nil
+	default:
+		return nil, fmt.Errorf("未知的账号格式策略 '%s' (可选 username / email / either)", format)
+	}
+}
+
 // ValidatePassword 校验密码格式。
 // 要求：长度在6到30位之间，并且必须同时包含至少一个字母和一个数字。
 func ValidatePassword(fl validator.FieldLevel) bool {
@@ -107,17 +149,30 @@ func ValidRole(fl validator.FieldLevel) bool {
 // RegisterCustomValidators 将所有自定义的校验函数注册到 Gin 的 validator 引擎中。
 // 这样就可以在 DTO 的 struct tag 中使用这些自定义的校验标签了。
 // 例如: `binding:"Account"` 或 `binding:"Password"`
-func RegisterCustomValidators() error {
+//
+// 同时安装输入规范化：带 `sanitize:"trim"` 标签的字段会在校验前去除首尾空白并拒绝控制字符。
+//
+// accountFormat 为配置中的账号格式策略，决定 "Account" 标签的校验规则；未知取值会返回错误阻止启动。
+// nicknameCfg 为昵称长度配置，决定 "Nickname" 标签的校验规则。
+func RegisterCustomValidators(accountFormat string, nicknameCfg config.NicknameConfig) error {
+	accountValidator, err := accountValidatorFor(accountFormat)
+	if err != nil {
+		return err
+	}
+
+	installSanitizingValidator()
+
 	// 获取 Gin 使用的 validator 实例
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		// 定义校验标签名和对应的校验函数
 		validations := map[string]validator.Func{
-			"ChinesePhone": ValidateChinesePhone, // 手机号校验
-			"Account":      ValidateNickname,     // 账户名/昵称校验 (之前讨论中建议的标签名是 "Username"，这里是 "Account")
-			"Password":     ValidatePassword,     // 密码格式校验
-			"Status":       ValidStatus,          // 用户状态枚举校验
-			"Role":         ValidRole,            // 用户角色枚举校验
-			"Gender":       ValidGender,          // 性别枚举校验
+			"ChinesePhone": ValidateChinesePhone,              // 手机号校验
+			"Account":      accountValidator,                  // 账号格式校验，规则由账号格式策略决定
+			"Nickname":     nicknameValidatorFor(nicknameCfg), // 昵称校验，允许 Unicode 字符，长度由昵称配置决定
+			"Password":     ValidatePassword,                  // 密码格式校验
+			"Status":       ValidStatus,                       // 用户状态枚举校验
+			"Role":         ValidRole,                         // 用户角色枚举校验
+			"Gender":       ValidGender,                       // 性别枚举校验
 		}
 
 		// 遍历并注册所有自定义校验器
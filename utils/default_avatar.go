@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// 默认头像的网格与字形规格
+const (
+	identiconGrid = 5 // identicon 为 5x5 网格，左右对称
+	glyphWidth    = 5 // 内置点阵字形宽度
+	glyphHeight   = 7 // 内置点阵字形高度
+	maxInitials   = 2 // 首字母头像最多绘制的字符数
+)
+
+// glyphs 内置 5x7 点阵字形 (A-Z、0-9)，每行低 5 位从左到右表示像素
+// - 标准库没有字体渲染能力，首字母头像只需要少量 ASCII 字符，内置点阵即可避免引入字体依赖。
+var glyphs = map[rune][glyphHeight]uint8{
+	'A': {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B': {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C': {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D': {0b11110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b11110},
+	'E': {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F': {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G': {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H': {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I': {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J': {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K': {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L': {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M': {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N': {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O': {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P': {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q': {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R': {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S': {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T': {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U': {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V': {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W': {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X': {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y': {0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100, 0b00100},
+	'Z': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	'0': {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1': {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3': {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4': {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5': {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6': {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8': {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9': {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+}
+
+// AvatarInitials 从昵称中提取可绘制的首字母 (大写)，最多两个
+// - 依次取各单词中第一个可绘制的字符，最多两个；不含可绘制字符的单词被跳过。
+// - 没有可绘制的字母或数字 (如纯中文昵称) 时返回空字符串，调用方应退化为 identicon。
+func AvatarInitials(name string) string {
+	var initials strings.Builder
+	for _, word := range strings.Fields(name) {
+		for _, r := range word {
+			r = unicode.ToUpper(r)
+			if _, ok := glyphs[r]; ok {
+				initials.WriteRune(r)
+				break
+			}
+		}
+		if initials.Len() == maxInitials {
+			break
+		}
+	}
+	return initials.String()
+}
+
+// GenerateIdenticonAvatar 由 seed (通常为用户 ID) 生成确定性的 identicon PNG
+// - 同一 seed 总是得到相同的图案与颜色，图案为左右对称的 5x5 网格。
+func GenerateIdenticonAvatar(seed string, size int) ([]byte, error) {
+	sum := sha256.Sum256([]byte(seed))
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.NRGBA{R: 0xF0, G: 0xF0, B: 0xF0, A: 0xFF}}, image.Point{}, draw.Src)
+
+	fg := &image.Uniform{C: seedColor(sum, 0.5)}
+	padding := size / 10
+	cell := (size - 2*padding) / identiconGrid
+	offset := (size - cell*identiconGrid) / 2
+	for row := 0; row < identiconGrid; row++ {
+		for col := 0; col <= identiconGrid/2; col++ {
+			if sum[3+row*3+col]&1 == 0 {
+				continue
+			}
+			for _, c := range []int{col, identiconGrid - 1 - col} {
+				rect := image.Rect(offset+c*cell, offset+row*cell, offset+(c+1)*cell, offset+(row+1)*cell)
+				draw.Draw(img, rect, fg, image.Point{}, draw.Src)
+			}
+		}
+	}
+	return encodePNG(img)
+}
+
+// GenerateInitialsAvatar 生成“彩色背景 + 白色首字母”的 PNG，背景色由 colorSeed (通常为用户 ID) 决定
+// - initials 应来自 AvatarInitials；其中没有内置字形的字符会被跳过。
+func GenerateInitialsAvatar(initials string, colorSeed string, size int) ([]byte, error) {
+	sum := sha256.Sum256([]byte(colorSeed))
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: seedColor(sum, 0.45)}, image.Point{}, draw.Src)
+
+	var runes []rune
+	for _, r := range initials {
+		if _, ok := glyphs[r]; ok && len(runes) < maxInitials {
+			runes = append(runes, r)
+		}
+	}
+	if len(runes) == 0 {
+		return encodePNG(img)
+	}
+
+	// 文字区域：字符之间留 1 个点的间距，整体约占头像高度的一半，居中绘制
+	textWidth := len(runes)*glyphWidth + len(runes) - 1
+	scale := max(1, min(size/2/glyphHeight, size*3/5/textWidth))
+	originX := (size - textWidth*scale) / 2
+	originY := (size - glyphHeight*scale) / 2
+	white := &image.Uniform{C: color.White}
+	for i, r := range runes {
+		glyph := glyphs[r]
+		glyphX := originX + i*(glyphWidth+1)*scale
+		for y := 0; y < glyphHeight; y++ {
+			for x := 0; x < glyphWidth; x++ {
+				if glyph[y]&(1<<(glyphWidth-1-x)) == 0 {
+					continue
+				}
+				rect := image.Rect(glyphX+x*scale, originY+y*scale, glyphX+(x+1)*scale, originY+(y+1)*scale)
+				draw.Draw(img, rect, white, image.Point{}, draw.Src)
+			}
+		}
+	}
+	return encodePNG(img)
+}
+
+// seedColor 由哈希值确定色相，以固定饱和度和给定亮度生成颜色，保证不同用户颜色各异且都足够醒目
+func seedColor(sum [sha256.Size]byte, lightness float64) color.NRGBA {
+	hue := float64(int(sum[0])<<8|int(sum[1])) / 65536 * 360
+	r, g, b := hslToRGB(hue, 0.55, lightness)
+	return color.NRGBA{R: r, G: g, B: b, A: 0xFF}
+}
+
+// hslToRGB 将 HSL 颜色 (h: 0-360, s/l: 0-1) 转换为 RGB
+func hslToRGB(h, s, l float64) (uint8, uint8, uint8) {
+	c := (1 - math.Abs(2*l-1)) * s
+	hp := h / 60
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g = c, x
+	case hp < 2:
+		r, g = x, c
+	case hp < 3:
+		g, b = c, x
+	case hp < 4:
+		g, b = x, c
+	case hp < 5:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := l - c/2
+	return uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255)
+}
+
+// encodePNG 将图片编码为 PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // 注册 GIF 解码器，供 image.DecodeConfig 识别 GIF 头像的尺寸
+	"image/jpeg"
+	"image/png"
+)
+
+// 支持重新编码以剥离元数据的图片格式 (image.DecodeConfig 返回的格式名)
+const (
+	ImageFormatJPEG = "jpeg"
+	ImageFormatPNG  = "png"
+)
+
+// StripImageMetadata 通过解码再编码的方式去除图片中的 EXIF/GPS 等元数据
+// - JPEG: 先按 EXIF Orientation 旋转/翻转像素，再以 jpegQuality 重新编码，保证去掉元数据后显示方向不变
+// - PNG: 重新编码会丢弃 tEXt/eXIf 等辅助数据块
+// - 其他格式不处理，返回 processed=false 和原始数据，由调用方决定是否记录日志
+//
+// 返回:
+//   - out: 处理后的图片数据（未处理时为原始数据）
+//   - format: 识别出的图片格式，无法识别时为空
+//   - processed: 是否进行了重新编码
+//   - err: 图片数据损坏等导致解码或编码失败时返回错误
+func StripImageMetadata(data []byte, jpegQuality int) (out []byte, format string, processed bool, err error) {
+	_, format, err = image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		// 未注册解码器的格式 (如 webp) 也会走到这里，视为不处理而不是错误
+		return data, "", false, nil
+	}
+	if format != ImageFormatJPEG && format != ImageFormatPNG {
+		return data, format, false, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, format, false, fmt.Errorf("解码 %s 图片失败: %w", format, err)
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case ImageFormatJPEG:
+		img = applyEXIFOrientation(img, readJPEGOrientation(data))
+		if jpegQuality <= 0 || jpegQuality > 100 {
+			jpegQuality = jpeg.DefaultQuality
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case ImageFormatPNG:
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, format, false, fmt.Errorf("重新编码 %s 图片失败: %w", format, err)
+	}
+	return buf.Bytes(), format, true, nil
+}
+
+// readJPEGOrientation 从 JPEG 的 APP1 (EXIF) 段中读取 Orientation 标签 (0x0112)
+// 未找到或数据不合法时返回 1 (正常方向)
+func readJPEGOrientation(data []byte) int {
+	const orientationTag = 0x0112
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // 图像数据开始 (SOS) 或结束，EXIF 只会出现在它们之前
+			return 1
+		}
+		segLen := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		segStart, segEnd := pos+4, pos+2+segLen
+		if segLen < 2 || segEnd > len(data) {
+			return 1
+		}
+		if marker == 0xE1 && segEnd-segStart > 6 && string(data[segStart:segStart+6]) == "Exif\x00\x00" {
+			tiff := data[segStart+6 : segEnd]
+			if len(tiff) < 8 {
+				return 1
+			}
+			var order binary.ByteOrder
+			switch string(tiff[:2]) {
+			case "II":
+				order = binary.LittleEndian
+			case "MM":
+				order = binary.BigEndian
+			default:
+				return 1
+			}
+			ifd := int(order.Uint32(tiff[4:8]))
+			if ifd+2 > len(tiff) {
+				return 1
+			}
+			count := int(order.Uint16(tiff[ifd : ifd+2]))
+			for i := 0; i < count; i++ {
+				entry := ifd + 2 + i*12
+				if entry+12 > len(tiff) {
+					return 1
+				}
+				if order.Uint16(tiff[entry:entry+2]) == orientationTag {
+					if v := int(order.Uint16(tiff[entry+8 : entry+10])); v >= 1 && v <= 8 {
+						return v
+					}
+					return 1
+				}
+			}
+			return 1
+		}
+		pos = segEnd
+	}
+	return 1
+}
+
+// applyEXIFOrientation 按 EXIF Orientation 取值 (1-8) 对图像做旋转/翻转，返回正常方向的图像
+func applyEXIFOrientation(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// 统一转换为 NRGBA，便于直接按下标读写像素
+	in := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(in, in.Bounds(), src, b.Min, draw.Src)
+
+	dw, dh := w, h
+	if orientation >= 5 { // 5-8 涉及 90 度旋转，宽高互换
+		dw, dh = h, w
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 水平翻转
+				dx, dy = w-1-x, y
+			case 3: // 旋转 180 度
+				dx, dy = w-1-x, h-1-y
+			case 4: // 垂直翻转
+				dx, dy = x, h-1-y
+			case 5: // 沿主对角线翻转
+				dx, dy = y, x
+			case 6: // 顺时针旋转 90 度
+				dx, dy = h-1-y, x
+			case 7: // 沿副对角线翻转
+				dx, dy = h-1-y, w-1-x
+			case 8: // 逆时针旋转 90 度
+				dx, dy = y, w-1-x
+			}
+			si := in.PixOffset(x, y)
+			di := out.PixOffset(dx, dy)
+			copy(out.Pix[di:di+4], in.Pix[si:si+4])
+		}
+	}
+	return out
+}
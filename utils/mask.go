@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/Xushengqwer/user_hub/models/enums"
+)
+
+// MaskIdentifier 按身份类型对标识符进行脱敏，用于对外展示
+// - 手机号: 138****0000
+// - 邮箱 (形如 x@y 的标识符): a***@example.com
+// - 微信 OpenID: 仅保留首尾各 4 位
+// - 账号: 保留首尾各 1 位，中间以 * 填充
+func MaskIdentifier(identityType enums.IdentityType, identifier string) string {
+	switch identityType {
+	case enums.Phone:
+		return MaskPhone(identifier)
+	case enums.WechatMiniProgram:
+		return maskMiddle(identifier, 4, 4)
+	default:
+		if strings.Contains(identifier, "@") {
+			return MaskEmail(identifier)
+		}
+		return MaskAccount(identifier)
+	}
+}
+
+// MaskPhone 脱敏手机号，保留前 3 位和后 4 位
+func MaskPhone(phone string) string {
+	return maskMiddle(phone, 3, 4)
+}
+
+// MaskEmail 脱敏邮箱，仅保留本地部分首字符与完整域名
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return MaskAccount(email)
+	}
+	local, domain := []rune(email[:at]), email[at:]
+	return string(local[0]) + "***" + domain
+}
+
+// MaskAccount 脱敏普通账号，保留首尾各 1 位
+func MaskAccount(account string) string {
+	return maskMiddle(account, 1, 1)
+}
+
+// maskMiddle 保留前 keepHead 位、后 keepTail 位，中间替换为 *；过短的字符串整体替换，避免泄露原值
+func maskMiddle(s string, keepHead, keepTail int) string {
+	runes := []rune(s)
+	n := len(runes)
+	if n == 0 {
+		return ""
+	}
+	if n <= keepHead+keepTail {
+		return strings.Repeat("*", n)
+	}
+	maskLen := n - keepHead - keepTail
+	if maskLen > 4 {
+		maskLen = 4 // 固定最多 4 个 *，不暴露原始长度
+	}
+	return string(runes[:keepHead]) + strings.Repeat("*", maskLen) + string(runes[n-keepTail:])
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Xushengqwer/user_hub/config"
+	"github.com/go-playground/validator/v10"
+)
+
+// IsValidNickname 判断昵称是否满足昵称规则。
+// - 长度按字符 (rune) 计算，须在配置的 [min, max] 范围内。
+// - 允许中文等 Unicode 字母、数字、标点、符号与空格；拒绝控制字符和零宽、双向覆盖等格式字符。
+func IsValidNickname(nickname string, cfg config.NicknameConfig) bool {
+	if !utf8.ValidString(nickname) {
+		return false
+	}
+	length := utf8.RuneCountInString(nickname)
+	if length < cfg.MinLengthOrDefault() || length > cfg.MaxLengthOrDefault() {
+		return false
+	}
+	for _, r := range nickname {
+		// IsGraphic 覆盖 L/M/N/P/S/Zs 类别，天然排除控制字符 (Cc) 与格式字符 (Cf)
+		if !unicode.IsGraphic(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// 随机友好昵称的词表，组合为 "形容词 + 的 + 名词 + 4 位数字"
+var (
+	nicknameAdjectives = []string{"快乐", "勇敢", "安静", "聪明", "温柔", "热情", "认真", "自由", "闪亮", "悠闲", "机智", "可爱"}
+	nicknameNouns      = []string{"海豚", "熊猫", "小鹿", "松鼠", "企鹅", "狐狸", "鲸鱼", "白鸽", "考拉", "猫咪", "向日葵", "星星"}
+)
+
+// RandomNickname 生成随机友好昵称，如 "快乐的海豚3821"；超过配置的最大长度时按字符截断。
+func RandomNickname(cfg config.NicknameConfig) string {
+	nickname := fmt.Sprintf("%s的%s%04d",
+		nicknameAdjectives[rand.IntN(len(nicknameAdjectives))],
+		nicknameNouns[rand.IntN(len(nicknameNouns))],
+		rand.IntN(10000),
+	)
+	return TruncateNickname(nickname, cfg)
+}
+
+// TruncateNickname 将昵称按字符截断到配置的最大长度。
+func TruncateNickname(nickname string, cfg config.NicknameConfig) string {
+	if runes := []rune(nickname); len(runes) > cfg.MaxLengthOrDefault() {
+		return string(runes[:cfg.MaxLengthOrDefault()])
+	}
+	return nickname
+}
+
+// nicknameValidatorFor 根据昵称配置生成 "Nickname" 标签使用的校验函数。
+// - 昵称为可选字段，空字符串表示未设置/清空昵称，不受长度下限约束。
+func nicknameValidatorFor(cfg config.NicknameConfig) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		nickname := fl.Field().String()
+		return nickname == "" || IsValidNickname(nickname, cfg)
+	}
+}
@@ -1,10 +1,74 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 
-// SetPassword 生成哈希密码（使用 bcrypt 哈希）
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// argon2id 哈希的盐与输出长度 (字节)
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// ErrPasswordMismatch 密码与哈希不匹配
+// - 与 bcrypt.ErrMismatchedHashAndPassword 相同，调用方无需区分哈希算法。
+var ErrPasswordMismatch = bcrypt.ErrMismatchedHashAndPassword
+
+// errUnknownPasswordHash 存储的哈希无法识别算法或格式损坏
+var errUnknownPasswordHash = errors.New("无法识别的密码哈希格式")
+
+// passwordHashParams 新密码使用的哈希算法与参数
+type passwordHashParams struct {
+	algorithm         string
+	bcryptCost        int
+	argon2Memory      uint32
+	argon2Iterations  uint32
+	argon2Parallelism uint8
+}
+
+// passwordHashing 当前生效的哈希参数，启动时由 ConfigurePasswordHashing 设置，未设置时为 bcrypt 默认强度
+var passwordHashing = passwordHashParams{algorithm: config.PasswordHashBcrypt, bcryptCost: bcrypt.DefaultCost}
+
+// ConfigurePasswordHashing 根据密码策略配置设置新密码使用的哈希算法与强度，应在应用启动时调用一次
+// - 只影响之后生成的哈希，已存储的哈希仍按其自身的算法与参数校验。
+func ConfigurePasswordHashing(cfg config.PasswordPolicyConfig) error {
+	params := passwordHashParams{
+		algorithm:         cfg.HashAlgorithmOrDefault(),
+		bcryptCost:        cfg.BcryptCostOrDefault(),
+		argon2Memory:      cfg.Argon2MemoryKiBOrDefault(),
+		argon2Iterations:  cfg.Argon2IterationsOrDefault(),
+		argon2Parallelism: cfg.Argon2ParallelismOrDefault(),
+	}
+	switch params.algorithm {
+	case config.PasswordHashBcrypt:
+		if params.bcryptCost < bcrypt.MinCost || params.bcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("bcrypt 强度 %d 超出范围 [%d, %d]", params.bcryptCost, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case config.PasswordHashArgon2id:
+	default:
+		return fmt.Errorf("不支持的密码哈希算法: %q", params.algorithm)
+	}
+	passwordHashing = params
+	return nil
+}
+
+// SetPassword 按当前配置的算法与强度生成哈希密码
 func SetPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	p := passwordHashing
+	if p.algorithm == config.PasswordHashArgon2id {
+		return hashArgon2id(password, p)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), p.bcryptCost)
 	if err != nil {
 		return "", err
 	}
@@ -12,7 +76,88 @@ func SetPassword(password string) (string, error) {
 }
 
 // CheckPassword 校验用户输入的密码是否与存储的哈希密码匹配
+// - 算法与参数从哈希本身识别，bcrypt 与 argon2id 哈希均可校验；不匹配时返回 ErrPasswordMismatch。
 func CheckPassword(hashedPassword, password string) error {
+	if strings.HasPrefix(hashedPassword, "$argon2id$") {
+		return checkArgon2id(hashedPassword, password)
+	}
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err
 }
+
+// PasswordNeedsRehash 判断存储的哈希是否弱于当前配置 (算法不同或强度更低)，用于登录成功后透明升级
+// - 无法识别的哈希返回 false，避免对损坏数据做写入。
+func PasswordNeedsRehash(hashedPassword string) bool {
+	p := passwordHashing
+	if strings.HasPrefix(hashedPassword, "$argon2id$") {
+		stored, _, _, err := decodeArgon2id(hashedPassword)
+		if err != nil {
+			return false
+		}
+		if p.algorithm != config.PasswordHashArgon2id {
+			return true
+		}
+		return stored.argon2Memory < p.argon2Memory ||
+			stored.argon2Iterations < p.argon2Iterations ||
+			stored.argon2Parallelism < p.argon2Parallelism
+	}
+
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return false
+	}
+	return p.algorithm != config.PasswordHashBcrypt || cost < p.bcryptCost
+}
+
+// hashArgon2id 生成 PHC 格式的 argon2id 哈希: $argon2id$v=19$m=<KiB>,t=<迭代>,p=<并行度>$<盐>$<哈希>
+func hashArgon2id(password string, p passwordHashParams) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.argon2Iterations, p.argon2Memory, p.argon2Parallelism, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.argon2Memory, p.argon2Iterations, p.argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// checkArgon2id 使用哈希中记录的参数重新计算并以常量时间比较
+func checkArgon2id(hashedPassword, password string) error {
+	p, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+	computed := argon2.IDKey([]byte(password), salt, p.argon2Iterations, p.argon2Memory, p.argon2Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(computed, key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// decodeArgon2id 解析 PHC 格式的 argon2id 哈希，返回参数、盐与哈希值
+func decodeArgon2id(hashedPassword string) (passwordHashParams, []byte, []byte, error) {
+	var p passwordHashParams
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, errUnknownPasswordHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errUnknownPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.argon2Memory, &p.argon2Iterations, &p.argon2Parallelism); err != nil ||
+		p.argon2Iterations == 0 || p.argon2Parallelism == 0 {
+		return p, nil, nil, errUnknownPasswordHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, errUnknownPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errUnknownPasswordHash
+	}
+	p.algorithm = config.PasswordHashArgon2id
+	return p, salt, key, nil
+}
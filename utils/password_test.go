@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Xushengqwer/user_hub/config"
+)
+
+// testArgon2Config 使用较低参数的 argon2id 配置，缩短测试耗时
+var testArgon2Config = config.PasswordPolicyConfig{
+	HashAlgorithm:     config.PasswordHashArgon2id,
+	Argon2MemoryKiB:   64,
+	Argon2Iterations:  1,
+	Argon2Parallelism: 1,
+}
+
+// usePasswordHashing 在测试期间切换全局哈希配置，测试结束后恢复
+func usePasswordHashing(t *testing.T, cfg config.PasswordPolicyConfig) {
+	t.Helper()
+	previous := passwordHashing
+	t.Cleanup(func() { passwordHashing = previous })
+	if err := ConfigurePasswordHashing(cfg); err != nil {
+		t.Fatalf("配置密码哈希失败: %v", err)
+	}
+}
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	usePasswordHashing(t, testArgon2Config)
+
+	hash, err := SetPassword("secret-password")
+	if err != nil {
+		t.Fatalf("生成哈希失败: %v", err)
+	}
+	if want := "$argon2id$v=19$m=64,t=1,p=1$"; !strings.HasPrefix(hash, want) {
+		t.Fatalf("哈希 = %q，期望以 %q 开头", hash, want)
+	}
+	if err := CheckPassword(hash, "secret-password"); err != nil {
+		t.Errorf("正确密码校验失败: %v", err)
+	}
+	if err := CheckPassword(hash, "wrong-password"); !errors.Is(err, ErrPasswordMismatch) {
+		t.Errorf("错误密码期望 ErrPasswordMismatch，实际为 %v", err)
+	}
+
+	// 相同密码每次使用不同的盐
+	again, err := SetPassword("secret-password")
+	if err != nil {
+		t.Fatalf("生成哈希失败: %v", err)
+	}
+	if again == hash {
+		t.Error("相同密码两次哈希结果不应相同")
+	}
+
+	// 切换回 bcrypt 后，已存储的 argon2id 哈希仍可校验
+	usePasswordHashing(t, config.PasswordPolicyConfig{})
+	if err := CheckPassword(hash, "secret-password"); err != nil {
+		t.Errorf("切换算法后校验旧 argon2id 哈希失败: %v", err)
+	}
+}
+
+func TestDecodeArgon2idMalformed(t *testing.T) {
+	const (
+		salt = "c2FsdHNhbHRzYWx0c2FsdA" // 16 字节盐
+		key  = "a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+	)
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{name: "空字符串", hash: ""},
+		{name: "段数不足", hash: "$argon2id$v=19$m=64,t=1,p=1$" + salt},
+		{name: "段数过多", hash: "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$" + key + "$extra"},
+		{name: "算法不是 argon2id", hash: "$argon2i$v=19$m=64,t=1,p=1$" + salt + "$" + key},
+		{name: "版本不支持", hash: "$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key},
+		{name: "版本格式错误", hash: "$argon2id$version=19$m=64,t=1,p=1$" + salt + "$" + key},
+		{name: "参数缺失", hash: "$argon2id$v=19$m=64,t=1$" + salt + "$" + key},
+		{name: "参数非数字", hash: "$argon2id$v=19$m=abc,t=1,p=1$" + salt + "$" + key},
+		{name: "迭代次数为 0", hash: "$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key},
+		{name: "并行度为 0", hash: "$argon2id$v=19$m=64,t=1,p=0$" + salt + "$" + key},
+		{name: "并行度溢出", hash: "$argon2id$v=19$m=64,t=1,p=256$" + salt + "$" + key},
+		{name: "盐不是 Base64", hash: "$argon2id$v=19$m=64,t=1,p=1$!!!$" + key},
+		{name: "哈希不是 Base64", hash: "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$!!!"},
+		{name: "哈希为空", hash: "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := decodeArgon2id(tt.hash); !errors.Is(err, errUnknownPasswordHash) {
+				t.Errorf("decodeArgon2id(%q) 期望 errUnknownPasswordHash，实际为 %v", tt.hash, err)
+			}
+			// 损坏的哈希既不能通过校验，也不应被当作需要升级而写入
+			if strings.HasPrefix(tt.hash, "$argon2id$") {
+				if err := CheckPassword(tt.hash, "secret-password"); err == nil {
+					t.Errorf("损坏的哈希不应通过校验: %q", tt.hash)
+				}
+			}
+			if PasswordNeedsRehash(tt.hash) {
+				t.Errorf("损坏的哈希不应判定为需要升级: %q", tt.hash)
+			}
+		})
+	}
+
+	p, gotSalt, gotKey, err := decodeArgon2id("$argon2id$v=19$m=64,t=2,p=3$" + salt + "$" + key)
+	if err != nil {
+		t.Fatalf("解析有效哈希失败: %v", err)
+	}
+	if p.argon2Memory != 64 || p.argon2Iterations != 2 || p.argon2Parallelism != 3 || len(gotSalt) != 16 || len(gotKey) == 0 {
+		t.Errorf("解析结果不正确: params=%+v salt=%d key=%d", p, len(gotSalt), len(gotKey))
+	}
+}
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	lowBcrypt, err := bcrypt.GenerateFromPassword([]byte("secret-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("生成 bcrypt 哈希失败: %v", err)
+	}
+	defaultBcrypt, err := bcrypt.GenerateFromPassword([]byte("secret-password"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("生成 bcrypt 哈希失败: %v", err)
+	}
+	weakArgon2, err := hashArgon2id("secret-password", passwordHashParams{argon2Memory: 32, argon2Iterations: 1, argon2Parallelism: 1})
+	if err != nil {
+		t.Fatalf("生成 argon2id 哈希失败: %v", err)
+	}
+	currentArgon2, err := hashArgon2id("secret-password", passwordHashParams{argon2Memory: 64, argon2Iterations: 1, argon2Parallelism: 1})
+	if err != nil {
+		t.Fatalf("生成 argon2id 哈希失败: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config config.PasswordPolicyConfig
+		hash   string
+		want   bool
+	}{
+		{name: "bcrypt_强度低于配置", config: config.PasswordPolicyConfig{}, hash: string(lowBcrypt), want: true},
+		{name: "bcrypt_强度等于配置", config: config.PasswordPolicyConfig{}, hash: string(defaultBcrypt), want: false},
+		{name: "bcrypt_强度高于配置", config: config.PasswordPolicyConfig{BcryptCost: bcrypt.MinCost}, hash: string(defaultBcrypt), want: false},
+		{name: "bcrypt_配置为 argon2id", config: testArgon2Config, hash: string(defaultBcrypt), want: true},
+		{name: "argon2id_参数低于配置", config: testArgon2Config, hash: weakArgon2, want: true},
+		{name: "argon2id_参数等于配置", config: testArgon2Config, hash: currentArgon2, want: false},
+		{name: "argon2id_配置为 bcrypt", config: config.PasswordPolicyConfig{}, hash: currentArgon2, want: true},
+		{name: "无法识别的哈希", config: config.PasswordPolicyConfig{}, hash: "plain-text", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usePasswordHashing(t, tt.config)
+			if got := PasswordNeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("PasswordNeedsRehash = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigurePasswordHashingRejectsInvalidConfig(t *testing.T) {
+	usePasswordHashing(t, config.PasswordPolicyConfig{})
+	before := passwordHashing
+
+	for _, cfg := range []config.PasswordPolicyConfig{
+		{HashAlgorithm: "md5"},
+		{BcryptCost: bcrypt.MaxCost + 1},
+		{BcryptCost: bcrypt.MinCost - 1},
+	} {
+		if err := ConfigurePasswordHashing(cfg); err == nil {
+			t.Errorf("配置 %+v 期望返回错误", cfg)
+		}
+		if passwordHashing != before {
+			t.Errorf("无效配置 %+v 不应修改当前哈希参数", cfg)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 省市一致性校验可能返回的错误，调用方可通过 errors.Is 区分并转换为面向用户的提示
+var (
+	ErrRegionUnknownProvince = errors.New("省份不在行政区划数据集中")
+	ErrRegionCityMismatch    = errors.New("城市不属于所填省份")
+)
+
+// RegionDataset 行政区划数据集的扩展点。
+// 内置实现基于 "省份 -> 城市列表" 的静态映射 (见 NewStaticRegionDataset)，
+// 需要接入其他国家/地区或外部行政区划服务时，实现此接口并在初始化时注入即可。
+type RegionDataset interface {
+	// CityInProvince 判断 city 是否属于 province。
+	// provinceKnown 为 false 表示数据集中没有该省份，此时 match 无意义。
+	CityInProvince(province, city string) (provinceKnown bool, match bool)
+}
+
+// ValidateRegionPair 使用数据集校验省份与城市是否匹配。
+// 省份或城市任一为空时视为未填写完整，不做校验。
+func ValidateRegionPair(dataset RegionDataset, province, city string) error {
+	if dataset == nil || strings.TrimSpace(province) == "" || strings.TrimSpace(city) == "" {
+		return nil
+	}
+	provinceKnown, match := dataset.CityInProvince(province, city)
+	if !provinceKnown {
+		return ErrRegionUnknownProvince
+	}
+	if !match {
+		return ErrRegionCityMismatch
+	}
+	return nil
+}
+
+// regionNameSuffixes 比较前从名称末尾去除的行政区划后缀，较长的后缀在前，
+// 使 "广东省"/"广东"、"深圳市"/"深圳"、"海淀区"/"海淀" 等写法视为同一地区
+var regionNameSuffixes = []string{
+	"特别行政区", "维吾尔自治区", "壮族自治区", "回族自治区", "自治区", "自治州", "地区", "林区", "省", "市", "盟", "区",
+}
+
+// normalizeRegionName 去除首尾空白与常见的行政区划后缀
+func normalizeRegionName(name string) string {
+	name = strings.TrimSpace(name)
+	for _, suffix := range regionNameSuffixes {
+		if trimmed, ok := strings.CutSuffix(name, suffix); ok && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
+
+// regionNameMatches 判断两个已规范化的名称是否指代同一地区。
+// 除完全相同外，还允许以至少两个字的简称匹配全称 (如 "延边" 匹配 "延边朝鲜族自治州")。
+func regionNameMatches(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if utf8.RuneCountInString(a) > utf8.RuneCountInString(b) {
+		a, b = b, a
+	}
+	return utf8.RuneCountInString(a) >= 2 && strings.HasPrefix(b, a)
+}
+
+// staticRegionDataset 基于内存映射的 RegionDataset 实现，键为规范化后的省份名
+type staticRegionDataset struct {
+	provinces map[string][]string
+}
+
+// NewStaticRegionDataset 根据 "省份 -> 城市列表" 映射创建数据集，省份与城市名称可带或不带行政区划后缀
+func NewStaticRegionDataset(data map[string][]string) RegionDataset {
+	provinces := make(map[string][]string, len(data))
+	for province, cities := range data {
+		normalized := make([]string, 0, len(cities))
+		for _, city := range cities {
+			normalized = append(normalized, normalizeRegionName(city))
+		}
+		key := normalizeRegionName(province)
+		provinces[key] = append(provinces[key], normalized...)
+	}
+	return &staticRegionDataset{provinces: provinces}
+}
+
+// CityInProvince 实现 RegionDataset 接口
+func (d *staticRegionDataset) CityInProvince(province, city string) (bool, bool) {
+	cities, ok := d.provinces[normalizeRegionName(province)]
+	if !ok {
+		return false, false
+	}
+	city = normalizeRegionName(city)
+	for _, candidate := range cities {
+		if regionNameMatches(city, candidate) {
+			return true, true
+		}
+	}
+	return true, false
+}
+
+// chinaRegionJSON 内置的中国省级行政区 -> 地级行政区 (直辖市为市辖区，港澳为分区/堂区) 数据
+//
+//go:embed regiondata/china.json
+var chinaRegionJSON []byte
+
+var (
+	chinaRegionOnce    sync.Once
+	chinaRegionDataset RegionDataset
+)
+
+// ChinaRegionDataset 返回内置的中国行政区划数据集 (只解析一次，并发安全)
+func ChinaRegionDataset() RegionDataset {
+	chinaRegionOnce.Do(func() {
+		var data map[string][]string
+		if err := json.Unmarshal(chinaRegionJSON, &data); err != nil {
+			panic(fmt.Sprintf("内置行政区划数据解析失败: %v", err))
+		}
+		chinaRegionDataset = NewStaticRegionDataset(data)
+	})
+	return chinaRegionDataset
+}
+
+// LoadRegionDataset 加载行政区划数据集：path 为空时使用内置的中国数据集，
+// 否则从 JSON 文件读取，文件格式与内置数据相同，即 {"省份": ["城市", ...]}。
+func LoadRegionDataset(path string) (RegionDataset, error) {
+	if path == "" {
+		return ChinaRegionDataset(), nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取行政区划数据文件 '%s' 失败: %w", path, err)
+	}
+	var data map[string][]string
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("解析行政区划数据文件 '%s' 失败: %w", path, err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("行政区划数据文件 '%s' 为空", path)
+	}
+	return NewStaticRegionDataset(data), nil
+}
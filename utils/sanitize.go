@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// sanitizeTag 标记需要规范化的字符串字段，例如 `sanitize:"trim"`
+// - 适用于账号、手机号、昵称等会参与查找或唯一性判断的字段；密码等凭证字段不应添加此标签。
+const sanitizeTag = "sanitize"
+
+// ErrControlCharacter 表示输入中包含控制字符
+var ErrControlCharacter = errors.New("输入中包含不允许的控制字符")
+
+// NormalizeText 去除首尾空白，并拒绝包含控制字符（换行、制表符、NUL 等）的输入
+// - 保证 " user " 与 "user" 解析为同一个标识符
+func NormalizeText(s string) (string, error) {
+	trimmed := strings.TrimSpace(s)
+	for _, r := range trimmed {
+		if unicode.IsControl(r) {
+			return "", ErrControlCharacter
+		}
+	}
+	return trimmed, nil
+}
+
+// SanitizeStruct 按 `sanitize:"trim"` 标签规范化结构体中的 string / *string 字段（含嵌入结构体）
+// - obj 必须是结构体指针，否则不做任何处理
+// - 任一字段包含控制字符时返回带字段名的错误
+func SanitizeStruct(obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return sanitizeValue(v.Elem())
+}
+
+func sanitizeValue(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		// 嵌入结构体即使未导出，其导出字段仍可设置，先于导出检查递归处理
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			if err := sanitizeValue(fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if field.Tag.Get(sanitizeTag) != "trim" {
+			continue
+		}
+
+		target := fv
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			target = fv.Elem()
+		}
+		if target.Kind() != reflect.String {
+			continue
+		}
+		normalized, err := NormalizeText(target.String())
+		if err != nil {
+			return fmt.Errorf("字段 %s: %w", field.Name, err)
+		}
+		target.SetString(normalized)
+	}
+	return nil
+}
+
+// sanitizingValidator 包装 Gin 默认的校验器：先按标签规范化字段，再执行 binding 标签校验
+// - 所有通过 ShouldBind* 绑定的请求（注册、登录、身份创建、资料更新等）都应用同一套规范化规则。
+// - `Account`、`ChinesePhone` 等格式校验看到的是规范化后的值。
+type sanitizingValidator struct {
+	binding.StructValidator
+}
+
+// ValidateStruct 实现 binding.StructValidator 接口。
+func (v *sanitizingValidator) ValidateStruct(obj any) error {
+	if err := SanitizeStruct(obj); err != nil {
+		return err
+	}
+	return v.StructValidator.ValidateStruct(obj)
+}
+
+// installSanitizingValidator 将 Gin 的全局校验器替换为带规范化的包装（重复调用不会重复包装）。
+func installSanitizingValidator() {
+	if _, ok := binding.Validator.(*sanitizingValidator); ok {
+		return
+	}
+	binding.Validator = &sanitizingValidator{StructValidator: binding.Validator}
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "首尾空格", input: " User ", want: "User"},
+		{name: "首尾制表符与换行", input: "\tUser\n", want: "User"},
+		{name: "全角空格", input: "　User　", want: "User"},
+		{name: "无需处理", input: "User", want: "User"},
+		{name: "中间空格保留", input: " 小 明 ", want: "小 明"},
+		{name: "仅空白", input: "   ", want: ""},
+		{name: "中间包含 NUL", input: "Us\x00er", wantErr: true},
+		{name: "中间包含换行", input: "Us\ner", wantErr: true},
+		{name: "中间包含制表符", input: "Us\ter", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeText(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrControlCharacter) {
+					t.Errorf("NormalizeText(%q) 期望 ErrControlCharacter，实际为 %q, %v", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeText(%q) = %q, %v，期望 %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+type sanitizeEmbedded struct {
+	Phone string `sanitize:"trim"`
+}
+
+type sanitizeTarget struct {
+	sanitizeEmbedded
+	Account  string  `sanitize:"trim"`
+	Nickname *string `sanitize:"trim"`
+	Empty    *string `sanitize:"trim"`
+	Password string  // 凭证字段不带标签，必须原样保留
+	Count    int     `sanitize:"trim"`
+	internal string  `sanitize:"trim"` //nolint:unused // 未导出字段应被跳过
+}
+
+func TestSanitizeStruct(t *testing.T) {
+	nickname := "  小明 "
+	target := &sanitizeTarget{
+		sanitizeEmbedded: sanitizeEmbedded{Phone: " 13800138000 "},
+		Account:          " User ",
+		Nickname:         &nickname,
+		Password:         " secret ",
+		Count:            1,
+		internal:         " x ",
+	}
+	if err := SanitizeStruct(target); err != nil {
+		t.Fatalf("SanitizeStruct 失败: %v", err)
+	}
+	if target.Account != "User" || target.Phone != "13800138000" || *target.Nickname != "小明" {
+		t.Errorf("带标签的字段未规范化: %+v nickname=%q", target, *target.Nickname)
+	}
+	if target.Password != " secret " || target.internal != " x " || target.Empty != nil {
+		t.Errorf("未带标签或未导出的字段不应修改: %+v", target)
+	}
+
+	if err := SanitizeStruct(&sanitizeTarget{Account: "Us\ner"}); !errors.Is(err, ErrControlCharacter) {
+		t.Errorf("包含控制字符时期望 ErrControlCharacter，实际为 %v", err)
+	}
+
+	// 非结构体指针不做处理
+	value := sanitizeTarget{Account: " User "}
+	if err := SanitizeStruct(value); err != nil || value.Account != " User " {
+		t.Errorf("非指针参数不应被处理: %+v, %v", value, err)
+	}
+	if err := SanitizeStruct((*sanitizeTarget)(nil)); err != nil {
+		t.Errorf("nil 指针不应返回错误: %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/Xushengqwer/user_hub/constants"
+)
+
+// tenantKey 请求上下文中租户 ID 的键
+type tenantKey struct{}
+
+// WithTenant 返回携带租户 ID 的上下文；身份仓库据此限定标识符的唯一性范围与查询范围
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext 返回上下文中的租户 ID，未设置时返回全局租户 constants.DefaultTenantID
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(tenantKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return constants.DefaultTenantID
+}